@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -11,10 +12,19 @@ import (
 	"time"
 
 	"github.com/aaronbengochea/periscope/backend-go/config"
+	"github.com/aaronbengochea/periscope/backend-go/internal/alerts"
 	"github.com/aaronbengochea/periscope/backend-go/internal/api"
+	"github.com/aaronbengochea/periscope/backend-go/internal/auth"
+	"github.com/aaronbengochea/periscope/backend-go/internal/jobs"
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/notify"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/aaronbengochea/periscope/backend-go/internal/rpc"
+	"github.com/aaronbengochea/periscope/backend-go/internal/services"
 	"github.com/aaronbengochea/periscope/backend-go/pkg/database"
 	"github.com/aaronbengochea/periscope/backend-go/pkg/massive"
 	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
 )
 
 func main() {
@@ -24,30 +34,198 @@ func main() {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
+	// The migrate subcommand manages the schema and exits
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(cfg, os.Args[2:]))
+	}
+	// The secrets subcommand re-encrypts stored secrets after a key rotation and exits
+	if len(os.Args) > 1 && os.Args[1] == "secrets" {
+		os.Exit(runSecrets(cfg, os.Args[2:]))
+	}
+
+	// The backup subcommand dumps Periscope's tables to object storage and exits
+	if len(os.Args) > 1 && os.Args[1] == "backup" {
+		os.Exit(runBackup(cfg, os.Args[2:]))
+	}
+
+	// The seed subcommand loads sample data for local development and demos and exits
+	if len(os.Args) > 1 && os.Args[1] == "seed" {
+		os.Exit(runSeed(cfg, os.Args[2:]))
+	}
+
 	// Set Gin mode
 	gin.SetMode(cfg.GinMode)
 
 	// Initialize Massive API client
 	massiveClient := massive.NewClient(cfg.MassiveBaseURL, cfg.MassiveAPIKey)
-	log.Println("✓ Initialized Massive API client")
+	if cfg.MassiveAPIKey == "" {
+		log.Println("Warning: MASSIVE_API_KEY is not set; market data requests without a user's own key will fail")
+	} else {
+		log.Println("✓ Initialized Massive API client")
+	}
 
-	// TODO: Initialize database connection
-	// For now, we'll skip database connection since we need the actual connection string
-	// This will be implemented in the next step
+	// Connect to the database, retrying with backoff. When it stays unreachable the server
+	// starts without it (database routes return 503) and keeps reconnecting in the background.
 	var db *database.DB
 	if cfg.DatabaseURL != "" {
-		db, err = database.NewSupabaseDB(cfg.DatabaseURL)
+		tracer := database.NewQueryTracer(time.Duration(cfg.DBSlowQueryMs) * time.Millisecond)
+		db, err = database.OpenSupabaseDB(cfg.DatabaseURL, tracer)
 		if err != nil {
 			log.Printf("Warning: Failed to connect to database: %v", err)
 			log.Println("Continuing without database connection...")
+			db = nil
 		} else {
 			defer db.Close()
-			log.Println("✓ Connected to Supabase database")
+			if cfg.DBReplicaURL != "" {
+				if err := db.AttachReplica(context.Background(), cfg.DBReplicaURL); err != nil {
+					log.Printf("Warning: %v", err)
+					log.Println("Continuing with all reads on the primary...")
+				} else {
+					log.Println("✓ Connected to read replica for snapshot, history and analytics reads")
+				}
+			}
+			if err := db.Connect(context.Background(), cfg.DBConnectAttempts, time.Second); err != nil {
+				log.Printf("Warning: Failed to connect to database: %v", err)
+				log.Println("Continuing in degraded mode, reconnecting in the background...")
+				db.Reconnect(context.Background(), time.Second, time.Duration(cfg.DBReconnectMaxSeconds)*time.Second,
+					func(ctx context.Context) { prepareDatabase(ctx, cfg, db) })
+			} else {
+				log.Println("✓ Connected to Supabase database")
+				prepareDatabase(context.Background(), cfg, db)
+			}
+		}
+	} else {
+		log.Println("No database configured (set DATABASE_URL or SUPABASE_DB_PASSWORD), continuing without one...")
+		if cfg.RESTFallback {
+			log.Println("✓ Persisting portfolios and watchlists through the Supabase REST API")
 		}
 	}
 
+	// Demo mode serves a few tickers to anonymous visitors from delayed or sample snapshots
+	var demo *services.DemoService
+	if cfg.DemoModeEnabled {
+		demo = services.NewDemoService(services.NewChainService(massiveClient), cfg.DemoTickers, cfg.RiskFreeRate)
+		log.Printf("✓ Demo mode enabled for %v", demo.Tickers())
+	}
+
+	// Users may store their own Massive API keys when there is a key to encrypt them with;
+	// webhook signing secrets are encrypted with it too
+	secretBox, err := newSecretBox(cfg)
+	if err != nil {
+		log.Fatalf("Invalid secrets encryption key: %v", err)
+	}
+	if secretBox != nil {
+		log.Println("✓ Initialized secrets encryption")
+	}
+
 	// Setup router
-	router := api.NewRouter(cfg, db, massiveClient)
+	router := api.NewRouter(cfg, db, massiveClient, demo, secretBox)
+
+	// Start background jobs (stopped on shutdown)
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	// The transaction pooler hands each statement to any server session, so LISTEN would
+	// never see a notification; cached entries then only expire
+	if db != nil && !(cfg.DBPoolerHost != "" && cfg.DBPoolMode == "transaction") {
+		go db.ListenForInvalidations(jobsCtx)
+	} else if db != nil {
+		log.Println("⚠ Cache invalidations from other instances are not received through the transaction pooler")
+	}
+	if demo != nil && cfg.DemoRefreshMinutes > 0 {
+		demoJob := jobs.NewDemoJob(demo, time.Duration(cfg.DemoRefreshMinutes)*time.Minute)
+		go demoJob.Start(jobsCtx)
+		log.Println("✓ Started demo refresh job")
+	}
+	if db != nil && cfg.ExpirationJobEnabled {
+		expirationJob := jobs.NewExpirationJob(repository.NewPositionRepository(db), repository.NewTransactionRepository(db), massiveClient)
+		go expirationJob.Start(jobsCtx)
+		log.Println("✓ Started option expiration job")
+	}
+	if db != nil && cfg.DividendJobEnabled {
+		dividendService := services.NewDividendService(massiveClient, repository.NewPositionRepository(db),
+			repository.NewTransactionRepository(db), repository.NewDividendRepository(db))
+		dividendJob := jobs.NewDividendJob(dividendService)
+		go dividendJob.Start(jobsCtx)
+		log.Println("✓ Started daily dividend job")
+	}
+	if db != nil && cfg.SnapshotJobEnabled {
+		valuationService := services.NewValuationService(massiveClient, repository.NewPositionRepository(db),
+			services.NewBetaService(massiveClient), cfg.RiskFreeRate)
+		snapshotJob := jobs.NewSnapshotJob(repository.NewPortfolioRepository(db), repository.NewSnapshotRepository(db), valuationService)
+		go snapshotJob.Start(jobsCtx)
+		log.Println("✓ Started daily portfolio snapshot job")
+	}
+	if db != nil && cfg.AlertJobEnabled {
+		valuationService := services.NewValuationService(massiveClient, repository.NewPositionRepository(db),
+			services.NewBetaService(massiveClient), cfg.RiskFreeRate)
+		positionAlerts := alerts.NewPositionEvaluator(repository.NewPositionAlertRepository(db), repository.NewPositionRepository(db), valuationService)
+		engine := alerts.NewEngine(repository.NewAlertRepository(db), positionAlerts,
+			alerts.NewStockSource(massiveClient),
+			alerts.NewChainSource(services.NewChainService(massiveClient), repository.NewIVHistoryRepository(db)),
+			alerts.NewEarningsSource(massiveClient))
+		alertJob := jobs.NewAlertJob(engine)
+		go alertJob.Start(jobsCtx)
+		log.Println("✓ Started alert job")
+
+		earningsSync := alerts.NewEarningsSync(repository.NewAlertRepository(db), repository.NewPortfolioRepository(db),
+			repository.NewPositionRepository(db), repository.NewWatchlistRepository(db))
+		earningsAlertJob := jobs.NewEarningsAlertJob(earningsSync)
+		go earningsAlertJob.Start(jobsCtx)
+		log.Println("✓ Started daily earnings alert sync job")
+	}
+	if db != nil && cfg.ChainJobEnabled && len(cfg.ChainJobTickers) > 0 {
+		optionsSnapshots := services.NewOptionsSnapshotService(services.NewChainService(massiveClient),
+			repository.NewOptionsSnapshotRepository(db))
+		optionsSnapshotJob := jobs.NewOptionsSnapshotJob(optionsSnapshots, cfg.ChainJobTickers,
+			time.Duration(cfg.ChainJobMinutes)*time.Minute)
+		go optionsSnapshotJob.Start(jobsCtx)
+		log.Printf("✓ Started options snapshot job for %v", cfg.ChainJobTickers)
+	}
+	if db != nil && cfg.RetentionJobEnabled {
+		retentionJob := jobs.NewRetentionJob(repository.NewOptionsSnapshotRepository(db),
+			cfg.RetentionIntradayDays, cfg.RetentionDailyDays)
+		go retentionJob.Start(jobsCtx)
+		log.Println("✓ Started snapshot retention job")
+	}
+	if db != nil && cfg.BackupJobEnabled {
+		backups := services.NewBackupService(repository.NewBackupRepository(db), newBackupStore(cfg, ""), cfg.BackupPrefix)
+		backupJob := jobs.NewBackupJob(backups)
+		go backupJob.Start(jobsCtx)
+		log.Printf("✓ Started backup job to bucket %s", cfg.BackupBucket)
+	}
+	if db != nil && cfg.WebhookJobEnabled {
+		webhookJob := jobs.NewWebhookJob(services.NewWebhookService(repository.NewWebhookRepository(db, secretBox)))
+		go webhookJob.Start(jobsCtx)
+		log.Println("✓ Started webhook delivery job")
+	}
+	if db != nil && cfg.DeletionJobEnabled {
+		deletions := services.NewAccountDeletionService(repository.NewUserRepository(db), repository.NewAccountRepository(db),
+			auth.NewAdminClient(cfg.SupabaseURL, cfg.SupabaseServiceKey))
+		deletionJob := jobs.NewAccountDeletionJob(deletions)
+		go deletionJob.Start(jobsCtx)
+		log.Println("✓ Started account deletion job")
+	}
+	if db != nil && cfg.AlertDeliveryJobEnabled {
+		notifiers := map[string]notify.Notifier{
+			models.ChannelWebhook: notify.NewWebhookNotifier(),
+			models.ChannelSlack:   notify.NewSlackNotifier(),
+			models.ChannelDiscord: notify.NewDiscordNotifier(),
+		}
+		if cfg.SMTPHost != "" {
+			notifiers[models.ChannelEmail] = notify.NewEmailNotifier(notify.SMTPConfig{
+				Host:     cfg.SMTPHost,
+				Port:     cfg.SMTPPort,
+				Username: cfg.SMTPUsername,
+				Password: cfg.SMTPPassword,
+				From:     cfg.SMTPFrom,
+				PerHour:  cfg.AlertEmailsPerHour,
+			})
+		}
+		alertDeliveries := services.NewAlertDeliveryService(repository.NewAlertChannelRepository(db, secretBox), notifiers)
+		alertDeliveryJob := jobs.NewAlertDeliveryJob(alertDeliveries)
+		go alertDeliveryJob.Start(jobsCtx)
+		log.Println("✓ Started alert delivery job")
+	}
 
 	// Create HTTP server
 	addr := fmt.Sprintf(":%s", cfg.Port)
@@ -69,12 +247,29 @@ func main() {
 		}
 	}()
 
+	// Internal services reach market data over gRPC on a port of its own
+	var grpcServer *grpc.Server
+	if cfg.GRPCPort != "" {
+		listener, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+		if err != nil {
+			log.Fatalf("Failed to listen for gRPC: %v", err)
+		}
+		grpcServer = rpc.NewServer(massiveClient, cfg.GRPCAuthToken)
+		go func() {
+			log.Printf("🚀 gRPC server starting on localhost:%s", cfg.GRPCPort)
+			if err := grpcServer.Serve(listener); err != nil {
+				log.Fatalf("Failed to start gRPC server: %v", err)
+			}
+		}()
+	}
+
 	// Wait for interrupt signal to gracefully shutdown the server
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
 	log.Println("Shutting down server...")
+	stopJobs()
 
 	// Graceful shutdown with 5 second timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -83,6 +278,36 @@ func main() {
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Printf("Server forced to shutdown: %v", err)
 	}
+	if grpcServer != nil {
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-ctx.Done():
+			grpcServer.Stop()
+		}
+	}
 
 	log.Println("Server stopped")
 }
+
+// prepareDatabase applies pending migrations and reports TimescaleDB once the database is
+// connected. A failed migration stops the server.
+func prepareDatabase(ctx context.Context, cfg *config.Config, db *database.DB) {
+	if cfg.MigrateOnStartup {
+		if err := migrateOnStartup(ctx, db); err != nil {
+			log.Fatalf("Failed to migrate database: %v", err)
+		}
+		log.Println("✓ Database schema is up to date")
+	}
+	if version, err := db.TimescaleVersion(ctx); err != nil {
+		log.Printf("Warning: %v", err)
+	} else if version != "" {
+		log.Printf("✓ TimescaleDB %s enabled for snapshot and quote history", version)
+	} else {
+		log.Println("TimescaleDB not enabled, snapshot and quote history use plain tables")
+	}
+}
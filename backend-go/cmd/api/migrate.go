@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"github.com/aaronbengochea/periscope/backend-go/config"
+	"github.com/aaronbengochea/periscope/backend-go/migrations"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/database"
+)
+
+const migrateUsage = `usage: api migrate [up | status | baseline VERSION]
+
+  up                apply pending migrations (the default)
+  status            list migrations and when each was applied
+  baseline VERSION  record migrations up to VERSION as applied without running them,
+                    for databases set up by hand before migrations were tracked`
+
+// runMigrate runs the migrate subcommand and returns the process exit code
+func runMigrate(cfg *config.Config, args []string) int {
+	command := "up"
+	if len(args) > 0 {
+		command = args[0]
+	}
+	if command == "help" || command == "-h" || command == "--help" {
+		fmt.Println(migrateUsage)
+		return 0
+	}
+
+	if cfg.DatabaseURL == "" {
+		log.Println("✗ No database is configured")
+		return 1
+	}
+	db, err := database.NewSupabaseDB(cfg.DatabaseURL)
+	if err != nil {
+		log.Printf("✗ Failed to connect to database: %v", err)
+		return 1
+	}
+	defer db.Close()
+
+	all, err := loadMigrations()
+	if err != nil {
+		log.Printf("✗ %v", err)
+		return 1
+	}
+
+	ctx := context.Background()
+	switch {
+	case command == "up" && len(args) <= 1:
+		applied, err := db.Migrate(ctx, all)
+		for _, m := range applied {
+			log.Printf("✓ Applied %s", m.Name)
+		}
+		if err != nil {
+			log.Printf("✗ %v", err)
+			return 1
+		}
+		log.Printf("✓ Database is up to date (%d applied)", len(applied))
+
+	case command == "status" && len(args) == 1:
+		statuses, err := db.MigrationStatus(ctx, all)
+		if err != nil {
+			log.Printf("✗ %v", err)
+			return 1
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED")
+		for _, s := range statuses {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = s.AppliedAt.Local().Format("2006-01-02 15:04:05")
+			}
+			if s.Modified {
+				applied += " (file modified since)"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", s.Version, s.Name, applied)
+		}
+		w.Flush()
+
+	case command == "baseline" && len(args) == 2:
+		recorded, err := db.BaselineMigrations(ctx, all, args[1])
+		if err != nil {
+			log.Printf("✗ %v", err)
+			return 1
+		}
+		log.Printf("✓ Recorded %d migrations up to %s as applied", len(recorded), args[1])
+
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+	return 0
+}
+
+// migrateOnStartup applies pending migrations once the server has connected to the database
+func migrateOnStartup(ctx context.Context, db *database.DB) error {
+	all, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+	applied, err := db.Migrate(ctx, all)
+	for _, m := range applied {
+		log.Printf("✓ Applied migration %s", m.Name)
+	}
+	return err
+}
+
+// loadMigrations reads the embedded migrations
+func loadMigrations() ([]database.Migration, error) {
+	return database.LoadMigrations(migrations.FS)
+}
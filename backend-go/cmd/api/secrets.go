@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/aaronbengochea/periscope/backend-go/config"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/aaronbengochea/periscope/backend-go/internal/secrets"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/database"
+)
+
+const secretsUsage = `usage: api secrets rotate
+
+  rotate  re-encrypt every stored secret not sealed with SECRETS_ENCRYPTION_KEY: users'
+          Massive API keys, and webhook and alert channel signing secrets, including
+          those stored in the clear before an encryption key was configured`
+
+// newSecretBox creates the box sealing with SECRETS_ENCRYPTION_KEY and opening with it and
+// SECRETS_PREVIOUS_KEYS, or returns nil when no key is configured
+func newSecretBox(cfg *config.Config) (*secrets.Box, error) {
+	if cfg.SecretsEncryptionKey == "" {
+		return nil, nil
+	}
+	primary, err := secrets.NewLocalKey(cfg.SecretsKeyID, cfg.SecretsEncryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	previous, err := secrets.ParseKeys(cfg.SecretsPreviousKeys)
+	if err != nil {
+		return nil, err
+	}
+	return secrets.NewBox(primary, previous...)
+}
+
+// runSecrets runs the secrets subcommand and returns the process exit code
+func runSecrets(cfg *config.Config, args []string) int {
+	if len(args) != 1 || args[0] != "rotate" {
+		if len(args) == 1 && (args[0] == "help" || args[0] == "-h" || args[0] == "--help") {
+			fmt.Println(secretsUsage)
+			return 0
+		}
+		fmt.Fprintln(os.Stderr, secretsUsage)
+		return 2
+	}
+
+	box, err := newSecretBox(cfg)
+	if err != nil {
+		log.Printf("✗ Invalid secrets encryption key: %v", err)
+		return 1
+	}
+	if box == nil {
+		log.Println("✗ SECRETS_ENCRYPTION_KEY is not set")
+		return 1
+	}
+	if cfg.DatabaseURL == "" {
+		log.Println("✗ No database is configured")
+		return 1
+	}
+	db, err := database.NewSupabaseDB(cfg.DatabaseURL)
+	if err != nil {
+		log.Printf("✗ Failed to connect to database: %v", err)
+		return 1
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	rotations := []struct {
+		name   string
+		rotate func(ctx context.Context) (int, error)
+	}{
+		{"Massive API keys", func(ctx context.Context) (int, error) {
+			return repository.NewUserRepository(db).RotateMassiveKeys(ctx, box)
+		}},
+		{"webhook secrets", repository.NewWebhookRepository(db, box).RotateSecrets},
+		{"alert channel secrets", repository.NewAlertChannelRepository(db, box).RotateSecrets},
+	}
+	code := 0
+	for _, r := range rotations {
+		rotated, err := r.rotate(ctx)
+		if err != nil {
+			log.Printf("✗ %v", err)
+			code = 1
+			continue
+		}
+		log.Printf("✓ Re-encrypted %d %s", rotated, r.name)
+	}
+	return code
+}
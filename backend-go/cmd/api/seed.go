@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/config"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/aaronbengochea/periscope/backend-go/internal/services"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/database"
+)
+
+const seedUsage = `usage: api seed
+
+  Load sample users, portfolios with their trades, watchlists and a sample SPY chain
+  capture into the database, for local development and demos. Safe to run again: what
+  was already seeded is left as it is.`
+
+// runSeed runs the seed subcommand and returns the process exit code
+func runSeed(cfg *config.Config, args []string) int {
+	if len(args) > 0 {
+		if len(args) == 1 && (args[0] == "help" || args[0] == "-h" || args[0] == "--help") {
+			fmt.Println(seedUsage)
+			return 0
+		}
+		fmt.Fprintln(os.Stderr, seedUsage)
+		return 2
+	}
+
+	if cfg.DatabaseURL == "" {
+		log.Println("✗ No database is configured")
+		return 1
+	}
+	db, err := database.NewSupabaseDB(cfg.DatabaseURL)
+	if err != nil {
+		log.Printf("✗ Failed to connect to database: %v", err)
+		return 1
+	}
+	defer db.Close()
+
+	seed := services.NewSeedService(repository.NewUserRepository(db), repository.NewPortfolioRepository(db),
+		repository.NewTransactionRepository(db), repository.NewWatchlistRepository(db),
+		repository.NewOptionsSnapshotRepository(db), cfg.RiskFreeRate)
+	if err := seed.Run(context.Background(), time.Now()); err != nil {
+		log.Printf("✗ Seed failed: %v", err)
+		return 1
+	}
+	log.Println("✓ Seeded sample data")
+	return 0
+}
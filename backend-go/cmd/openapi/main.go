@@ -0,0 +1,463 @@
+// Command openapi generates the OpenAPI 3 document served at /api/v1/openapi.json from the
+// handlers' doc comments. Each handler documented as "Name handles METHOD /path?query=" is an
+// operation; the rest of its comment describes it, and a request struct it binds with
+// ShouldBindJSON becomes the request body schema, with the constraints of its binding tags.
+//
+// Run it from backend-go with `make openapi` after adding or changing a handler, and commit
+// the generated internal/api/openapi/openapi.json. With -check it only reports whether the
+// committed document is current.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	handlersDir = "internal/api/handlers"
+	modelsDir   = "internal/models"
+	outputPath  = "internal/api/openapi/openapi.json"
+)
+
+// handlesPattern matches the first line of a handler's doc comment
+var handlesPattern = regexp.MustCompile(`^(\w+) handles (GET|POST|PUT|PATCH|DELETE) (/\S+?)([,.:]?)(?:\s+(.*))?$`)
+
+func main() {
+	check := flag.Bool("check", false, "fail if "+outputPath+" is out of date instead of writing it")
+	flag.Parse()
+
+	g := &generator{types: map[string]*ast.StructType{}, schemas: map[string]any{}}
+	if err := g.load(); err != nil {
+		fmt.Fprintf(os.Stderr, "openapi: %v\n", err)
+		os.Exit(1)
+	}
+	doc, err := json.MarshalIndent(g.document(), "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "openapi: %v\n", err)
+		os.Exit(1)
+	}
+	doc = append(doc, '\n')
+
+	if *check {
+		current, err := os.ReadFile(outputPath)
+		if err != nil || !bytes.Equal(current, doc) {
+			fmt.Fprintf(os.Stderr, "openapi: %s is out of date; run make openapi\n", outputPath)
+			os.Exit(1)
+		}
+		return
+	}
+	if err := os.WriteFile(outputPath, doc, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "openapi: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("openapi: wrote %d operations to %s\n", g.operations, outputPath)
+}
+
+// generator collects operations from the handlers and schemas from the types they bind
+type generator struct {
+	funcs      []*ast.FuncDecl
+	types      map[string]*ast.StructType // struct types of the handlers and models packages
+	schemas    map[string]any             // components/schemas, by type name
+	paths      map[string]map[string]any
+	operations int
+}
+
+// load parses the handlers and models packages
+func (g *generator) load() error {
+	for _, dir := range []string{handlersDir, modelsDir} {
+		fset := token.NewFileSet()
+		pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+			return !strings.HasSuffix(fi.Name(), "_test.go")
+		}, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+		for _, pkg := range pkgs {
+			for _, file := range pkg.Files {
+				for _, decl := range file.Decls {
+					switch d := decl.(type) {
+					case *ast.FuncDecl:
+						if dir == handlersDir && d.Recv != nil && d.Doc != nil {
+							g.funcs = append(g.funcs, d)
+						}
+					case *ast.GenDecl:
+						for _, spec := range d.Specs {
+							if ts, ok := spec.(*ast.TypeSpec); ok {
+								if st, ok := ts.Type.(*ast.StructType); ok {
+									g.types[ts.Name.Name] = st
+								}
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// document builds the OpenAPI document
+func (g *generator) document() map[string]any {
+	g.paths = map[string]map[string]any{}
+	for _, fn := range g.funcs {
+		g.operation(fn)
+	}
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":       "Periscope API",
+			"version":     "1",
+			"description": "Options chains, analytics, portfolios, watchlists and alerts. Generated from the handlers by cmd/openapi.",
+		},
+		"servers":  []any{map[string]any{"url": "/"}},
+		"security": []any{map[string]any{"bearerAuth": []any{}}, map[string]any{}},
+		"paths":    g.paths,
+		"components": map[string]any{
+			"securitySchemes": map[string]any{
+				"bearerAuth": map[string]any{
+					"type":        "http",
+					"scheme":      "bearer",
+					"description": "A Supabase access token or a Periscope API key",
+				},
+			},
+			"schemas": g.schemas,
+		},
+	}
+}
+
+// operation adds the operation a handler's doc comment describes, if it describes one
+func (g *generator) operation(fn *ast.FuncDecl) {
+	lines := strings.Split(strings.TrimSpace(fn.Doc.Text()), "\n")
+	m := handlesPattern.FindStringSubmatch(lines[0])
+	if m == nil || m[1] != fn.Name.Name {
+		return
+	}
+	method, rawPath := strings.ToLower(m[2]), m[3]
+	description := strings.TrimSpace(strings.Join(append([]string{m[5]}, lines[1:]...), " "))
+
+	path, rawQuery, _ := strings.Cut(rawPath, "?")
+	var params []any
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if name, ok := strings.CutPrefix(segment, ":"); ok {
+			segments[i] = "{" + name + "}"
+			params = append(params, map[string]any{
+				"name": name, "in": "path", "required": true, "schema": map[string]any{"type": "string"},
+			})
+		}
+	}
+	path = strings.Join(segments, "/")
+	for _, pair := range strings.Split(rawQuery, "&") {
+		name, example, _ := strings.Cut(pair, "=")
+		if name == "" {
+			continue
+		}
+		schema := map[string]any{"type": "string"}
+		if values := strings.Split(example, "|"); len(values) > 1 {
+			schema["enum"] = values
+		} else if example != "" {
+			schema["example"] = example
+		}
+		params = append(params, map[string]any{"name": name, "in": "query", "schema": schema})
+	}
+
+	op := map[string]any{
+		"operationId": fn.Name.Name,
+		"summary":     summary(fn.Name.Name),
+		"tags":        []string{tag(path)},
+		"responses":   g.responses(fn),
+	}
+	if description != "" {
+		op["description"] = capitalize(description)
+	}
+	if len(params) > 0 {
+		op["parameters"] = params
+	}
+	if body := g.requestBody(fn); body != nil {
+		op["requestBody"] = body
+	}
+
+	if g.paths[path] == nil {
+		g.paths[path] = map[string]any{}
+	}
+	g.paths[path][method] = op
+	g.operations++
+}
+
+// responses lists the success status the handler answers with, and the error statuses
+func (g *generator) responses(fn *ast.FuncDecl) map[string]any {
+	status := "200"
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			switch sel.Sel.Name {
+			case "StatusCreated":
+				status = "201"
+			case "StatusNoContent":
+				status = "204"
+			}
+		}
+		return true
+	})
+	errorBody := map[string]any{"$ref": "#/components/schemas/Error"}
+	g.schemas["Error"] = map[string]any{
+		"type": "object",
+		"properties": map[string]any{"error": map[string]any{
+			"type":     "object",
+			"required": []string{"code", "message"},
+			"properties": map[string]any{
+				"code":       map[string]any{"type": "string"},
+				"message":    map[string]any{"type": "string"},
+				"request_id": map[string]any{"type": "string"},
+				"details":    map[string]any{},
+			},
+		}},
+	}
+	responses := map[string]any{
+		"default": map[string]any{
+			"description": "An error",
+			"content":     map[string]any{"application/json": map[string]any{"schema": errorBody}},
+		},
+	}
+	if status == "204" {
+		responses[status] = map[string]any{"description": "No content"}
+	} else {
+		responses[status] = map[string]any{
+			"description": "Success",
+			"content":     map[string]any{"application/json": map[string]any{"schema": map[string]any{"type": "object"}}},
+		}
+	}
+	return responses
+}
+
+// requestBody returns the body of a handler that binds a declared request struct
+func (g *generator) requestBody(fn *ast.FuncDecl) map[string]any {
+	declared := map[string]string{}
+	var bound string
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.ValueSpec:
+			if ident, ok := n.Type.(*ast.Ident); ok {
+				for _, name := range n.Names {
+					declared[name.Name] = ident.Name
+				}
+			}
+		case *ast.CallExpr:
+			sel, ok := n.Fun.(*ast.SelectorExpr)
+			if !ok || sel.Sel.Name != "ShouldBindJSON" || len(n.Args) != 1 {
+				return true
+			}
+			if unary, ok := n.Args[0].(*ast.UnaryExpr); ok {
+				if ident, ok := unary.X.(*ast.Ident); ok {
+					bound = declared[ident.Name]
+				}
+			}
+		}
+		return true
+	})
+	if bound == "" || g.types[bound] == nil {
+		return nil
+	}
+	return map[string]any{
+		"required": true,
+		"content":  map[string]any{"application/json": map[string]any{"schema": g.ref(bound)}},
+	}
+}
+
+// ref returns a reference to a struct type's schema, adding the schema on first use
+func (g *generator) ref(name string) map[string]any {
+	ref := map[string]any{"$ref": "#/components/schemas/" + name}
+	if _, ok := g.schemas[name]; ok {
+		return ref
+	}
+	g.schemas[name] = nil // placeholder, so recursive types terminate
+
+	properties := map[string]any{}
+	var required []string
+	for _, field := range g.types[name].Fields.List {
+		jsonName, omitted := jsonFieldName(field)
+		if omitted {
+			continue
+		}
+		if len(field.Names) == 0 {
+			// An embedded struct contributes its fields
+			if ident, ok := field.Type.(*ast.Ident); ok && g.types[ident.Name] != nil {
+				g.ref(ident.Name)
+				if embedded, ok := g.schemas[ident.Name].(map[string]any); ok {
+					for k, v := range embedded["properties"].(map[string]any) {
+						properties[k] = v
+					}
+				}
+			}
+			continue
+		}
+		if jsonName == "" {
+			jsonName = field.Names[0].Name
+		}
+		schema := g.schema(field.Type)
+		binding := tagValue(field, "binding")
+		for _, rule := range strings.Split(binding, ",") {
+			if rule == "dive" {
+				break // rules after dive apply to the elements
+			}
+			applyRule(schema, rule)
+			if rule == "required" {
+				required = append(required, jsonName)
+			}
+		}
+		if field.Doc != nil || field.Comment != nil {
+			text := field.Comment.Text()
+			if field.Doc != nil {
+				text = field.Doc.Text()
+			}
+			if text = strings.TrimSpace(strings.ReplaceAll(text, "\n", " ")); text != "" && schema["$ref"] == nil {
+				schema["description"] = capitalize(text)
+			}
+		}
+		properties[jsonName] = schema
+	}
+
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		sort.Strings(required)
+		schema["required"] = required
+	}
+	g.schemas[name] = schema
+	return ref
+}
+
+// schema maps a Go type expression to a schema
+func (g *generator) schema(expr ast.Expr) map[string]any {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return g.schema(t.X)
+	case *ast.ArrayType:
+		return map[string]any{"type": "array", "items": g.schema(t.Elt)}
+	case *ast.MapType:
+		return map[string]any{"type": "object", "additionalProperties": g.schema(t.Value)}
+	case *ast.SelectorExpr:
+		if pkg, ok := t.X.(*ast.Ident); ok && pkg.Name == "time" && t.Sel.Name == "Time" {
+			return map[string]any{"type": "string", "format": "date-time"}
+		}
+		if g.types[t.Sel.Name] != nil {
+			return g.ref(t.Sel.Name)
+		}
+	case *ast.Ident:
+		switch t.Name {
+		case "string":
+			return map[string]any{"type": "string"}
+		case "bool":
+			return map[string]any{"type": "boolean"}
+		case "int", "int32", "int64", "uint", "uint32", "uint64":
+			return map[string]any{"type": "integer"}
+		case "float32", "float64":
+			return map[string]any{"type": "number"}
+		}
+		if g.types[t.Name] != nil {
+			return g.ref(t.Name)
+		}
+	}
+	return map[string]any{}
+}
+
+// applyRule carries a validator binding rule over to a schema where OpenAPI can express it
+func applyRule(schema map[string]any, rule string) {
+	name, arg, _ := strings.Cut(rule, "=")
+	value, err := strconv.ParseFloat(arg, 64)
+	bound := "imum"
+	lengthKey := map[string]string{"string": "Length", "array": "Items"}[fmt.Sprint(schema["type"])]
+	switch name {
+	case "oneof":
+		schema["enum"] = strings.Fields(arg)
+	case "email":
+		schema["format"] = "email"
+	case "http_url", "url":
+		schema["format"] = "uri"
+	case "min", "max", "gte", "lte", "gt", "lt":
+		if err != nil {
+			return
+		}
+		prefix := map[string]string{"min": "min", "gte": "min", "gt": "min", "max": "max", "lte": "max", "lt": "max"}[name]
+		if lengthKey != "" && (name == "min" || name == "max") {
+			schema[prefix+lengthKey] = int(value)
+			return
+		}
+		schema[prefix+bound] = value
+		if name == "gt" || name == "lt" {
+			schema["exclusive"+capitalize(prefix+bound)] = true
+		}
+	}
+}
+
+// jsonFieldName reads a field's JSON name from its tag; omitted reports a field tagged "-"
+func jsonFieldName(field *ast.Field) (name string, omitted bool) {
+	name, _, _ = strings.Cut(tagValue(field, "json"), ",")
+	return name, name == "-"
+}
+
+// tagValue returns the value of one key of a field's struct tag
+func tagValue(field *ast.Field, key string) string {
+	if field.Tag == nil {
+		return ""
+	}
+	raw, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return ""
+	}
+	for raw != "" {
+		raw = strings.TrimLeft(raw, " ")
+		k, rest, ok := strings.Cut(raw, ":")
+		if !ok || len(rest) == 0 || rest[0] != '"' {
+			return ""
+		}
+		end := strings.IndexByte(rest[1:], '"')
+		if end < 0 {
+			return ""
+		}
+		if k == key {
+			return rest[1 : end+1]
+		}
+		raw = rest[end+2:]
+	}
+	return ""
+}
+
+// tag groups an operation by the first segment of its path after the API version
+func tag(path string) string {
+	segments := strings.Split(strings.TrimPrefix(path, "/api/v1/"), "/")
+	return segments[0]
+}
+
+// summary spells out a handler name: ListDeletedPortfolios is "List deleted portfolios"
+func summary(name string) string {
+	var words []string
+	start := 0
+	for i := 1; i <= len(name); i++ {
+		if i == len(name) || (name[i] >= 'A' && name[i] <= 'Z' && !(name[i-1] >= 'A' && name[i-1] <= 'Z')) {
+			words = append(words, name[start:i])
+			start = i
+		}
+	}
+	for i := 1; i < len(words); i++ {
+		if strings.ToUpper(words[i]) != words[i] {
+			words[i] = strings.ToLower(words[i])
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,137 @@
+// Package alerts evaluates user alert rules against live market data in the background:
+// sources fetch the metrics the armed rules need in batched upstream requests, and the
+// engine fires the rules whose condition holds and records their triggers.
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+)
+
+// Values are the current metric values of one ticker, keyed by metric
+type Values map[string]float64
+
+// Source fetches alert metrics from market data
+type Source interface {
+	// Metrics lists the metrics the source provides
+	Metrics() []string
+	// Fetch returns the current values of the metrics for each ticker, batching upstream
+	// requests. Tickers or metrics without data are omitted.
+	Fetch(ctx context.Context, tickers []string, metrics []string) (map[string]Values, error)
+}
+
+// metricAssetTypes maps each market alert metric to the kind of ticker it applies to
+var metricAssetTypes = map[string]string{
+	models.MarketMetricPrice:         models.AssetTypeStock,
+	models.MarketMetricChangePercent: models.AssetTypeStock,
+	models.MarketMetricATMIV:         models.AssetTypeStock,
+	models.MarketMetricIVRank:        models.AssetTypeStock,
+	models.MarketMetricIVPercentile:  models.AssetTypeStock,
+	models.MarketMetricIV:            models.AssetTypeOption,
+	models.MarketMetricDelta:         models.AssetTypeOption,
+	models.MarketMetricAbsDelta:      models.AssetTypeOption,
+	models.MarketMetricGamma:         models.AssetTypeOption,
+	models.MarketMetricTheta:         models.AssetTypeOption,
+	models.MarketMetricVega:          models.AssetTypeOption,
+	models.MarketMetricSpreadPercent: models.AssetTypeOption,
+	models.MarketMetricQuoteSize:     models.AssetTypeOption,
+	models.MarketMetricLiquidity:     models.AssetTypeOption,
+	models.MarketMetricEarningsDays:  models.AssetTypeStock,
+
+	models.MarketMetricStrikeDistance:   models.AssetTypeOption,
+	models.MarketMetricDaysToExpiration: models.AssetTypeOption,
+}
+
+// Metrics lists the supported market alert metrics of an asset type in name order
+func Metrics(assetType string) []string {
+	var metrics []string
+	for metric, t := range metricAssetTypes {
+		if t == assetType {
+			metrics = append(metrics, metric)
+		}
+	}
+	sort.Strings(metrics)
+	return metrics
+}
+
+// ValidateMetric checks that the metric exists and applies to the ticker's asset type
+func ValidateMetric(metric, ticker string) error {
+	assetType, ok := metricAssetTypes[metric]
+	if !ok {
+		return fmt.Errorf("unknown metric %q", metric)
+	}
+	if want := models.TickerAssetType(ticker); assetType != want {
+		return fmt.Errorf("metric %q does not apply to %s tickers; use one of %v", metric, want, Metrics(want))
+	}
+	return nil
+}
+
+// ValidateCondition checks a composite rule on the ticker: every group holds at least one
+// condition and either all or any, not both; every comparison uses a known metric, a
+// threshold and a plain comparison operator, since crossings are tracked on single metrics
+// only; and the rule stays within the nesting and size limits. The comparisons of a rule on
+// an option contract may also use the metrics of its underlying.
+func ValidateCondition(c *models.AlertCondition, ticker string) error {
+	if n := len(c.Comparisons()); n > models.MaxConditionComparisons {
+		return fmt.Errorf("condition has %d comparisons; the limit is %d", n, models.MaxConditionComparisons)
+	}
+	if c.IsComparison() {
+		return fmt.Errorf("condition must group comparisons with all or any; use metric, operator and threshold for one comparison")
+	}
+	return validateCondition(c, ticker, "condition", 1)
+}
+
+func validateCondition(c *models.AlertCondition, ticker, path string, depth int) error {
+	if c.IsComparison() {
+		switch c.Operator {
+		case ">", ">=", "<", "<=":
+		default:
+			return fmt.Errorf("%s: operator must be >, >=, < or <=", path)
+		}
+		if c.Threshold == nil {
+			return fmt.Errorf("%s: threshold is required", path)
+		}
+		if err := ValidateMetric(c.Metric, metricTicker(ticker, c.Metric)); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		return nil
+	}
+
+	if c.Metric != "" || c.Operator != "" || c.Threshold != nil {
+		return fmt.Errorf("%s: a group cannot also be a comparison", path)
+	}
+	if c.All != nil && c.Any != nil {
+		return fmt.Errorf("%s: a group has either all or any, not both", path)
+	}
+	if depth > models.MaxConditionDepth {
+		return fmt.Errorf("%s: groups nest at most %d deep", path, models.MaxConditionDepth)
+	}
+	parts, key := c.All, "all"
+	if c.All == nil {
+		parts, key = c.Any, "any"
+	}
+	if len(parts) == 0 {
+		return fmt.Errorf("%s.%s: a group needs at least one condition", path, key)
+	}
+	for i := range parts {
+		if err := validateCondition(&parts[i], ticker, fmt.Sprintf("%s.%s[%d]", path, key, i), depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// metricTicker returns the ticker a metric of a rule on ticker is read from: the underlying
+// for a stock metric in a rule on an option contract, otherwise the ticker itself
+func metricTicker(ticker, metric string) string {
+	if metricAssetTypes[metric] != models.AssetTypeStock || models.TickerAssetType(ticker) != models.AssetTypeOption {
+		return ticker
+	}
+	if symbol, err := models.ParseOptionTicker(ticker); err == nil {
+		return symbol.Underlying
+	}
+	return ticker
+}
@@ -0,0 +1,231 @@
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/analytics"
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/aaronbengochea/periscope/backend-go/internal/services"
+)
+
+// Chain source settings
+const (
+	chainIVMaturityDays = 30 // constant maturity of the ATM IV metric, as for IV rank
+	chainWorkers        = 4  // concurrent chain fetches; the client's rate limiter still applies
+)
+
+// ChainSource provides metrics from option chains: the 30-day ATM IV of a stock with its
+// 52-week IV rank and percentile, and the IV, greeks, liquidity, moneyness and time to
+// expiration of single contracts. Each
+// underlying's chain is fetched once per run, however many of its contracts are watched.
+type ChainSource struct {
+	chainService *services.ChainService
+	ivHistory    *repository.IVHistoryRepository
+}
+
+// NewChainSource creates a new option chain source
+func NewChainSource(chainService *services.ChainService, ivHistory *repository.IVHistoryRepository) *ChainSource {
+	return &ChainSource{
+		chainService: chainService,
+		ivHistory:    ivHistory,
+	}
+}
+
+// Metrics lists the chain metrics
+func (s *ChainSource) Metrics() []string {
+	return []string{
+		models.MarketMetricATMIV,
+		models.MarketMetricIVRank,
+		models.MarketMetricIVPercentile,
+		models.MarketMetricIV,
+		models.MarketMetricDelta,
+		models.MarketMetricAbsDelta,
+		models.MarketMetricGamma,
+		models.MarketMetricTheta,
+		models.MarketMetricVega,
+		models.MarketMetricSpreadPercent,
+		models.MarketMetricQuoteSize,
+		models.MarketMetricLiquidity,
+		models.MarketMetricStrikeDistance,
+		models.MarketMetricDaysToExpiration,
+	}
+}
+
+// Fetch fetches the chain of every underlying among the tickers and computes the metrics.
+// Underlyings whose chain cannot be fetched are left out; it fails only when none can.
+func (s *ChainSource) Fetch(ctx context.Context, tickers []string, metrics []string) (map[string]Values, error) {
+	ranked := false
+	for _, metric := range metrics {
+		if metric == models.MarketMetricIVRank || metric == models.MarketMetricIVPercentile {
+			ranked = true
+		}
+	}
+
+	// Group the tickers by the underlying whose chain has their data
+	groups := make(map[string][]string)
+	for _, ticker := range tickers {
+		underlying := ticker
+		if models.TickerAssetType(ticker) == models.AssetTypeOption {
+			symbol, err := models.ParseOptionTicker(ticker)
+			if err != nil {
+				continue
+			}
+			underlying = symbol.Underlying
+		}
+		groups[underlying] = append(groups[underlying], ticker)
+	}
+
+	now := time.Now()
+	values := make(map[string]Values, len(tickers))
+	var mu sync.Mutex
+	failures := 0
+
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	for range chainWorkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for underlying := range jobs {
+				fetched, err := s.fetchUnderlying(ctx, underlying, groups[underlying], ranked, now)
+				mu.Lock()
+				if err != nil {
+					log.Printf("[AlertEngine] ⚠ No chain data for %s: %v", underlying, err)
+					failures++
+				}
+				for ticker, v := range fetched {
+					values[ticker] = v
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for underlying := range groups {
+		select {
+		case jobs <- underlying:
+		case <-ctx.Done():
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if len(groups) > 0 && failures == len(groups) {
+		return nil, fmt.Errorf("failed to fetch all %d option chains", len(groups))
+	}
+	return values, nil
+}
+
+// fetchUnderlying computes the metrics of an underlying and its watched contracts from one
+// chain snapshot
+func (s *ChainSource) fetchUnderlying(ctx context.Context, underlying string, tickers []string, ranked bool, now time.Time) (map[string]Values, error) {
+	snapshot, err := s.chainService.GetSnapshot(ctx, underlying, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	contracts := make(map[string]*models.OptionContract, len(snapshot.Contracts))
+	for i := range snapshot.Contracts {
+		c := &snapshot.Contracts[i]
+		if c.Details != nil && c.Details.Ticker != nil {
+			contracts[*c.Details.Ticker] = c
+		}
+	}
+
+	values := make(map[string]Values, len(tickers))
+	for _, ticker := range tickers {
+		v := Values{}
+		if ticker == underlying {
+			if atm := analytics.ConstantMaturityIV(snapshot.Contracts, snapshot.Spot, chainIVMaturityDays, now); atm != nil {
+				v[models.MarketMetricATMIV] = *atm
+				if ranked {
+					s.rankIV(ctx, underlying, *atm, snapshot.Spot, now, v)
+				}
+			}
+		} else if c, ok := contracts[ticker]; ok {
+			contractValues(c, snapshot.Spot, now, v)
+		}
+		values[ticker] = v
+	}
+	return values, nil
+}
+
+// contractValues sets the metrics of a single contract that the chain has data for
+func contractValues(c *models.OptionContract, spot float64, now time.Time, v Values) {
+	if c.ImpliedVol != nil {
+		v[models.MarketMetricIV] = *c.ImpliedVol
+	}
+	if g := c.Greeks; g != nil {
+		if g.Delta != nil {
+			v[models.MarketMetricDelta] = *g.Delta
+			v[models.MarketMetricAbsDelta] = math.Abs(*g.Delta)
+		}
+		if g.Gamma != nil {
+			v[models.MarketMetricGamma] = *g.Gamma
+		}
+		if g.Theta != nil {
+			v[models.MarketMetricTheta] = *g.Theta
+		}
+		if g.Vega != nil {
+			v[models.MarketMetricVega] = *g.Vega
+		}
+	}
+	if q := c.LastQuote; q != nil && q.Bid != nil && q.Ask != nil && *q.Ask >= *q.Bid {
+		if mid := *q.MidPrice(); mid > 0 {
+			v[models.MarketMetricSpreadPercent] = *q.Spread() / mid * 100
+		}
+		if q.BidSize != nil && q.AskSize != nil {
+			v[models.MarketMetricQuoteSize] = math.Min(float64(*q.BidSize), float64(*q.AskSize))
+		}
+	}
+	if score := analytics.LiquidityScore(c); score != nil {
+		v[models.MarketMetricLiquidity] = *score
+	}
+	if d := c.Details; d != nil {
+		if d.StrikePrice != nil && *d.StrikePrice > 0 && spot > 0 {
+			v[models.MarketMetricStrikeDistance] = math.Abs(spot-*d.StrikePrice) / *d.StrikePrice * 100
+		}
+		if d.ExpirationDate != nil {
+			if days, err := analytics.DaysToExpiration(*d.ExpirationDate, now); err == nil {
+				v[models.MarketMetricDaysToExpiration] = float64(days)
+			}
+		}
+	}
+}
+
+// rankIV records today's ATM IV reading and sets its IV rank and percentile against the
+// last 52 weeks of readings
+func (s *ChainSource) rankIV(ctx context.Context, ticker string, atm, spot float64, now time.Time, v Values) {
+	today := analytics.MarketDate(now)
+	if err := s.ivHistory.Upsert(ctx, &models.IVObservation{
+		Ticker:          ticker,
+		ObservedOn:      today,
+		ATMIV:           atm,
+		UnderlyingPrice: &spot,
+	}); err != nil {
+		log.Printf("[AlertEngine] ⚠ Failed to record IV observation for %s: %v", ticker, err)
+	}
+
+	history, err := s.ivHistory.ListSince(ctx, ticker, today.AddDate(0, 0, -52*7))
+	if err != nil {
+		log.Printf("[AlertEngine] ⚠ Failed to load IV history for %s: %v", ticker, err)
+		return
+	}
+	readings := make([]float64, len(history))
+	for i, obs := range history {
+		readings[i] = obs.ATMIV
+	}
+
+	window := analytics.IVRank("52w", readings, atm)
+	if window.IVRank != nil {
+		v[models.MarketMetricIVRank] = *window.IVRank
+	}
+	if window.IVPercentile != nil {
+		v[models.MarketMetricIVPercentile] = *window.IVPercentile
+	}
+}
@@ -0,0 +1,236 @@
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/analytics"
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/massive"
+)
+
+// earningsWorkers is the number of concurrent earnings calendar requests
+const earningsWorkers = 4
+
+// EarningsSource provides the days until each stock's next earnings release from the
+// earnings calendar. The calendar has no batch endpoint, so each ticker's next release is
+// fetched once per market day and reused by every run that day.
+type EarningsSource struct {
+	massiveClient *massive.Client
+
+	mu    sync.Mutex
+	day   time.Time
+	dates map[string]*time.Time // next release by ticker, nil when none is scheduled
+}
+
+// NewEarningsSource creates a new earnings calendar source
+func NewEarningsSource(massiveClient *massive.Client) *EarningsSource {
+	return &EarningsSource{
+		massiveClient: massiveClient,
+		dates:         make(map[string]*time.Time),
+	}
+}
+
+// Metrics lists the earnings metrics
+func (s *EarningsSource) Metrics() []string {
+	return []string{models.MarketMetricEarningsDays}
+}
+
+// Fetch returns the calendar days until each ticker's next earnings release, leaving out
+// tickers with none scheduled. It fails only when no ticker's calendar can be fetched.
+func (s *EarningsSource) Fetch(ctx context.Context, tickers []string, _ []string) (map[string]Values, error) {
+	today := analytics.MarketDate(time.Now())
+
+	s.mu.Lock()
+	if !s.day.Equal(today) {
+		s.day = today
+		s.dates = make(map[string]*time.Time)
+	}
+	var missing []string
+	for _, ticker := range tickers {
+		if _, ok := s.dates[ticker]; !ok {
+			missing = append(missing, ticker)
+		}
+	}
+	s.mu.Unlock()
+
+	failures := 0
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	for range earningsWorkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ticker := range jobs {
+				next, err := s.nextRelease(ctx, ticker, today)
+				s.mu.Lock()
+				if err != nil {
+					log.Printf("[AlertEngine] ⚠ No earnings calendar for %s: %v", ticker, err)
+					failures++
+				} else {
+					s.dates[ticker] = next
+				}
+				s.mu.Unlock()
+			}
+		}()
+	}
+	for _, ticker := range missing {
+		select {
+		case jobs <- ticker:
+		case <-ctx.Done():
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if failures > 0 && failures == len(tickers) {
+		return nil, fmt.Errorf("failed to fetch the earnings calendar of all %d tickers", len(tickers))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	values := make(map[string]Values, len(tickers))
+	for _, ticker := range tickers {
+		if next := s.dates[ticker]; next != nil {
+			values[ticker] = Values{models.MarketMetricEarningsDays: next.Sub(today).Hours() / 24}
+		}
+	}
+	return values, nil
+}
+
+// nextRelease returns the date of the ticker's first earnings release on or after today
+func (s *EarningsSource) nextRelease(ctx context.Context, ticker string, today time.Time) (*time.Time, error) {
+	events, err := s.massiveClient.GetUpcomingEarnings(ctx, ticker, today)
+	if err != nil {
+		return nil, err
+	}
+	for _, event := range events {
+		date, err := analytics.ParseDate(event.Date)
+		if err != nil || date.Before(today) {
+			continue
+		}
+		return &date, nil
+	}
+	return nil, nil
+}
+
+// EarningsSync keeps each user's managed earnings alerts in line with their settings and
+// with the tickers they currently hold and watch
+type EarningsSync struct {
+	alerts     *repository.AlertRepository
+	portfolios repository.PortfolioRepo
+	positions  *repository.PositionRepository
+	watchlists repository.WatchlistRepo
+}
+
+// NewEarningsSync creates a new earnings alert sync
+func NewEarningsSync(alerts *repository.AlertRepository, portfolios repository.PortfolioRepo, positions *repository.PositionRepository, watchlists repository.WatchlistRepo) *EarningsSync {
+	return &EarningsSync{
+		alerts:     alerts,
+		portfolios: portfolios,
+		positions:  positions,
+		watchlists: watchlists,
+	}
+}
+
+// SyncAll syncs the earnings alerts of every user with enabled settings
+func (e *EarningsSync) SyncAll(ctx context.Context) error {
+	settings, err := e.alerts.ListEarningsSettings(ctx)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for i := range settings {
+		if err := e.Sync(ctx, &settings[i]); err != nil {
+			log.Printf("[AlertEngine] ⚠ Failed to sync earnings alerts: %v", err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to sync earnings alerts of %d of %d users", failed, len(settings))
+	}
+	log.Printf("[AlertEngine] ✓ Synced earnings alerts of %d users", len(settings))
+	return nil
+}
+
+// Sync creates a managed alert firing DaysBefore days ahead of earnings on every ticker the
+// settings cover and removes the others; disabled settings remove them all. The alerts are
+// recurring, with a cooldown that lasts past the release so each release fires once.
+func (e *EarningsSync) Sync(ctx context.Context, s *models.EarningsAlertSettings) error {
+	tickers := []string{}
+	if s.Enabled {
+		var err error
+		if tickers, err = e.coveredTickers(ctx, s); err != nil {
+			return err
+		}
+	}
+
+	cooldown := (s.DaysBefore + 2) * 24 * 60 * 60
+	now := time.Now()
+	if err := e.alerts.SyncEarningsAlerts(ctx, s.UserID, tickers, float64(s.DaysBefore), cooldown, now); err != nil {
+		return err
+	}
+	s.Tickers = tickers
+	s.SyncedAt = &now
+	return nil
+}
+
+// coveredTickers collects the stock tickers held in the user's portfolios and watched in
+// their watchlists, as the settings choose, using the underlying of option contracts
+func (e *EarningsSync) coveredTickers(ctx context.Context, s *models.EarningsAlertSettings) ([]string, error) {
+	seen := make(map[string]bool)
+	add := func(ticker string) {
+		if models.IsStockTicker(ticker) {
+			seen[ticker] = true
+		}
+	}
+
+	if s.Portfolios {
+		portfolios, err := e.portfolios.List(ctx, s.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if len(portfolios) > 0 {
+			ids := make([]int64, len(portfolios))
+			for i, p := range portfolios {
+				ids[i] = p.ID
+			}
+			open, err := e.positions.ListByPortfolios(ctx, ids, models.PositionOpen)
+			if err != nil {
+				return nil, err
+			}
+			for _, p := range open {
+				add(p.UnderlyingTicker)
+			}
+		}
+	}
+
+	if s.Watchlists {
+		watchlists, err := e.watchlists.List(ctx, s.UserID)
+		if err != nil {
+			return nil, err
+		}
+		for _, w := range watchlists {
+			for _, item := range w.Items {
+				if symbol, err := models.ParseOptionTicker(item.Ticker); err == nil && item.AssetType == models.AssetTypeOption {
+					add(symbol.Underlying)
+				} else {
+					add(item.Ticker)
+				}
+			}
+		}
+	}
+
+	tickers := make([]string, 0, len(seen))
+	for ticker := range seen {
+		tickers = append(tickers, ticker)
+	}
+	sort.Strings(tickers)
+	return tickers, nil
+}
@@ -0,0 +1,193 @@
+package alerts
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+)
+
+// Engine evaluates armed market alerts and position alerts against live market data
+type Engine struct {
+	rules     *repository.AlertRepository
+	positions *PositionEvaluator
+	sources   map[string]Source // by metric
+}
+
+// NewEngine creates an alert engine fetching market alert metrics from the sources.
+// positions may be nil to leave position alerts unevaluated.
+func NewEngine(rules *repository.AlertRepository, positions *PositionEvaluator, sources ...Source) *Engine {
+	e := &Engine{
+		rules:     rules,
+		positions: positions,
+		sources:   make(map[string]Source),
+	}
+	for _, src := range sources {
+		for _, metric := range src.Metrics() {
+			e.sources[metric] = src
+		}
+	}
+	return e
+}
+
+// Run evaluates every armed market alert and position alert once
+func (e *Engine) Run(ctx context.Context) error {
+	now := time.Now()
+	if err := e.evaluateRules(ctx, now); err != nil {
+		return err
+	}
+	if e.positions != nil {
+		return e.positions.Evaluate(ctx, now)
+	}
+	return nil
+}
+
+// fetchRequest collects the distinct tickers and metrics one source is asked for
+type fetchRequest struct {
+	tickers []string
+	metrics []string
+	seen    map[string]bool
+}
+
+func (r *fetchRequest) add(ticker, metric string) {
+	if !r.seen["t:"+ticker] {
+		r.seen["t:"+ticker] = true
+		r.tickers = append(r.tickers, ticker)
+	}
+	if !r.seen["m:"+metric] {
+		r.seen["m:"+metric] = true
+		r.metrics = append(r.metrics, metric)
+	}
+}
+
+// evaluateRules fetches the metrics of every armed market alert with one batched request
+// per source, then fires the alerts whose condition holds. Alerts whose source failed are
+// left untouched until the next run.
+func (e *Engine) evaluateRules(ctx context.Context, now time.Time) error {
+	rules, err := e.rules.ListDue(ctx, now)
+	if err != nil {
+		return err
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+
+	requests := make(map[Source]*fetchRequest)
+	for i := range rules {
+		for _, c := range comparisons(&rules[i]) {
+			src, ok := e.sources[c.Metric]
+			if !ok {
+				continue
+			}
+			req, ok := requests[src]
+			if !ok {
+				req = &fetchRequest{seen: make(map[string]bool)}
+				requests[src] = req
+			}
+			req.add(metricTicker(rules[i].Ticker, c.Metric), c.Metric)
+		}
+	}
+
+	values := make(map[string]Values)
+	failed := make(map[Source]bool)
+	for src, req := range requests {
+		fetched, err := src.Fetch(ctx, req.tickers, req.metrics)
+		if err != nil {
+			log.Printf("[AlertEngine] ⚠ Failed to fetch %v for %d tickers: %v", req.metrics, len(req.tickers), err)
+			failed[src] = true
+			continue
+		}
+		for ticker, v := range fetched {
+			if values[ticker] == nil {
+				values[ticker] = Values{}
+			}
+			for metric, value := range v {
+				values[ticker][metric] = value
+			}
+		}
+	}
+
+	evaluated, triggered := 0, 0
+rules:
+	for i := range rules {
+		a := &rules[i]
+		for _, c := range comparisons(a) {
+			src, ok := e.sources[c.Metric]
+			if !ok {
+				log.Printf("[AlertEngine] ⚠ Alert %d uses unsupported metric %q", a.ID, c.Metric)
+				continue rules
+			}
+			if failed[src] {
+				continue rules
+			}
+		}
+		evaluated++
+
+		lookup := func(metric string) (float64, bool) {
+			v, ok := values[metricTicker(a.Ticker, metric)][metric]
+			return v, ok
+		}
+		var value *float64
+		if v, ok := lookup(a.Metric); ok {
+			value = &v
+		}
+		var matched bool
+		if a.Condition != nil {
+			matched = value != nil && a.Condition.Holds(lookup)
+		} else {
+			matched = value != nil && a.Matches(*value)
+		}
+		if !matched {
+			if err := e.rules.RecordEvaluation(ctx, a.ID, value, now); err != nil {
+				log.Printf("[AlertEngine] ⚠ Failed to record alert %d: %v", a.ID, err)
+			}
+			continue
+		}
+
+		trigger, err := e.rules.Trigger(ctx, a, *value, snapshot(a, values), now)
+		if err != nil {
+			log.Printf("[AlertEngine] ⚠ Failed to trigger alert %d: %v", a.ID, err)
+			continue
+		}
+		if trigger != nil {
+			triggered++
+			if a.Condition != nil {
+				log.Printf("[AlertEngine] ✓ Alert %d triggered: %s %s", a.ID, a.Ticker, a.Condition)
+			} else {
+				log.Printf("[AlertEngine] ✓ Alert %d triggered: %s %s %s %g (value %g)",
+					a.ID, a.Ticker, a.Metric, a.Operator, a.Threshold, *value)
+			}
+		}
+	}
+
+	log.Printf("[AlertEngine] ✓ Evaluated %d of %d alerts, %d triggered", evaluated, len(rules), triggered)
+	return nil
+}
+
+// comparisons returns the comparisons a rule is evaluated with: those of its composite
+// condition, or its single metric
+func comparisons(a *models.Alert) []models.AlertCondition {
+	if a.Condition != nil {
+		return a.Condition.Comparisons()
+	}
+	return []models.AlertCondition{{Metric: a.Metric}}
+}
+
+// snapshot collects the market data a rule was evaluated against: every metric fetched for
+// its ticker, with those of the underlying when a rule on a contract compares them
+func snapshot(a *models.Alert, values map[string]Values) map[string]float64 {
+	snap := make(map[string]float64)
+	for _, c := range comparisons(a) {
+		if ticker := metricTicker(a.Ticker, c.Metric); ticker != a.Ticker {
+			for metric, v := range values[ticker] {
+				snap[metric] = v
+			}
+		}
+	}
+	for metric, v := range values[a.Ticker] {
+		snap[metric] = v
+	}
+	return snap
+}
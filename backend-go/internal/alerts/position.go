@@ -0,0 +1,89 @@
+package alerts
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/aaronbengochea/periscope/backend-go/internal/services"
+)
+
+// PositionEvaluator evaluates armed position alerts against live contract snapshots,
+// marking the ones whose condition holds as triggered
+type PositionEvaluator struct {
+	alerts    *repository.PositionAlertRepository
+	positions *repository.PositionRepository
+	valuation *services.ValuationService
+}
+
+// NewPositionEvaluator creates a new position alert evaluator
+func NewPositionEvaluator(alerts *repository.PositionAlertRepository, positions *repository.PositionRepository, valuation *services.ValuationService) *PositionEvaluator {
+	return &PositionEvaluator{
+		alerts:    alerts,
+		positions: positions,
+		valuation: valuation,
+	}
+}
+
+// Evaluate evaluates every armed alert on an open position once
+func (e *PositionEvaluator) Evaluate(ctx context.Context, now time.Time) error {
+	alerts, err := e.alerts.ListArmed(ctx)
+	if err != nil {
+		return err
+	}
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	watched := make(map[int64]bool)
+	var portfolioIDs []int64
+	seen := make(map[int64]bool)
+	for _, a := range alerts {
+		watched[a.PositionID] = true
+		if !seen[a.PortfolioID] {
+			seen[a.PortfolioID] = true
+			portfolioIDs = append(portfolioIDs, a.PortfolioID)
+		}
+	}
+
+	open, err := e.positions.ListByPortfolios(ctx, portfolioIDs, models.PositionOpen)
+	if err != nil {
+		return err
+	}
+	var positions []models.Position
+	for _, p := range open {
+		if watched[p.ID] {
+			positions = append(positions, p)
+		}
+	}
+
+	metrics, err := e.valuation.PositionAlertMetrics(ctx, positions)
+	if err != nil {
+		return err
+	}
+
+	triggered := 0
+	for i := range alerts {
+		a := &alerts[i]
+		var value *float64
+		if v, ok := metrics[a.PositionID][a.Metric]; ok {
+			value = &v
+		}
+		fired := value != nil && a.Matches(*value)
+
+		if err := e.alerts.RecordEvaluation(ctx, a.ID, value, fired, now); err != nil {
+			log.Printf("[AlertEngine] ⚠ Failed to record position alert %d: %v", a.ID, err)
+			continue
+		}
+		if fired {
+			triggered++
+			log.Printf("[AlertEngine] ✓ Position alert %d triggered on position %d: %s %s %g (value %g)",
+				a.ID, a.PositionID, a.Metric, a.Operator, a.Threshold, *value)
+		}
+	}
+
+	log.Printf("[AlertEngine] ✓ Evaluated %d position alerts on %d positions, %d triggered", len(alerts), len(positions), triggered)
+	return nil
+}
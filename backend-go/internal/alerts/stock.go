@@ -0,0 +1,44 @@
+package alerts
+
+import (
+	"context"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/massive"
+)
+
+// StockSource provides stock metrics from the unified snapshot, up to 250 tickers per request
+type StockSource struct {
+	massiveClient *massive.Client
+}
+
+// NewStockSource creates a new stock snapshot source
+func NewStockSource(massiveClient *massive.Client) *StockSource {
+	return &StockSource{massiveClient: massiveClient}
+}
+
+// Metrics lists the stock metrics
+func (s *StockSource) Metrics() []string {
+	return []string{models.MarketMetricPrice, models.MarketMetricChangePercent}
+}
+
+// Fetch returns the latest price and session change of each ticker
+func (s *StockSource) Fetch(ctx context.Context, tickers []string, _ []string) (map[string]Values, error) {
+	snapshots, err := s.massiveClient.GetStockSnapshots(ctx, tickers)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]Values, len(snapshots))
+	for ticker, stock := range snapshots {
+		v := Values{}
+		if price := stock.Price(); price != nil {
+			v[models.MarketMetricPrice] = *price
+		}
+		if stock.Session != nil && stock.Session.ChangePercent != nil {
+			v[models.MarketMetricChangePercent] = *stock.Session.ChangePercent
+		}
+		values[ticker] = v
+	}
+	return values, nil
+}
@@ -0,0 +1,117 @@
+package alerts
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+)
+
+// Trigger stream settings
+const (
+	streamBuffer      = 32              // notifications a subscriber may fall behind by
+	streamRetryPeriod = 5 * time.Second // wait before listening again after a failure
+)
+
+// TriggerStream fans alert triggers out to the clients streaming them. It listens for the
+// triggers every instance records while at least one client is subscribed.
+type TriggerStream struct {
+	alerts *repository.AlertRepository
+
+	mu          sync.Mutex
+	subscribers map[*subscriber]struct{}
+	stop        context.CancelFunc // stops the listener, nil when it is not running
+}
+
+type subscriber struct {
+	userID *string
+	events chan models.AlertNotification
+}
+
+// NewTriggerStream creates a new alert trigger stream
+func NewTriggerStream(alerts *repository.AlertRepository) *TriggerStream {
+	return &TriggerStream{
+		alerts:      alerts,
+		subscribers: make(map[*subscriber]struct{}),
+	}
+}
+
+// Subscribe returns a channel receiving the user's alert triggers and a function that ends
+// the subscription. The channel is closed when the subscriber falls too far behind, so the
+// client can reconnect and catch up from the last trigger it received.
+func (s *TriggerStream) Subscribe(userID *string) (<-chan models.AlertNotification, func()) {
+	sub := &subscriber{userID: userID, events: make(chan models.AlertNotification, streamBuffer)}
+
+	s.mu.Lock()
+	s.subscribers[sub] = struct{}{}
+	if s.stop == nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		s.stop = cancel
+		go s.listen(ctx)
+	}
+	s.mu.Unlock()
+
+	var once sync.Once
+	return sub.events, func() {
+		once.Do(func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if _, ok := s.subscribers[sub]; ok {
+				delete(s.subscribers, sub)
+				close(sub.events)
+			}
+			if len(s.subscribers) == 0 && s.stop != nil {
+				s.stop()
+				s.stop = nil
+			}
+		})
+	}
+}
+
+// listen relays triggers to the subscribers until stopped, listening again after failures
+func (s *TriggerStream) listen(ctx context.Context) {
+	log.Println("[AlertEngine] ✓ Listening for alert triggers to stream")
+	for {
+		err := s.alerts.ListenTriggers(ctx, s.publish)
+		if ctx.Err() != nil {
+			log.Println("[AlertEngine] ✓ Stopped listening for alert triggers")
+			return
+		}
+		log.Printf("[AlertEngine] ⚠ Alert trigger listener failed, retrying in %s: %v", streamRetryPeriod, err)
+		select {
+		case <-time.After(streamRetryPeriod):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// publish hands a trigger to each subscriber of its alert's owner, dropping subscribers
+// whose buffer is full
+func (s *TriggerStream) publish(n models.AlertNotification) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for sub := range s.subscribers {
+		if !sameUser(sub.userID, n.Alert.UserID) {
+			continue
+		}
+		select {
+		case sub.events <- n:
+		default:
+			log.Printf("[AlertEngine] ⚠ Dropping alert stream subscriber %d triggers behind", streamBuffer)
+			delete(s.subscribers, sub)
+			close(sub.events)
+		}
+	}
+}
+
+// sameUser reports whether two optional user IDs name the same user, or both none
+func sameUser(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -0,0 +1,48 @@
+package analytics
+
+import "math"
+
+// minBetaReturns is the fewest overlapping daily returns a beta estimate is trusted with
+const minBetaReturns = 20
+
+// Beta returns the regression beta of an asset's daily log returns against a benchmark's.
+// Both close series must be aligned by date, oldest first. Returns nil when there are too
+// few overlapping returns or the benchmark did not move.
+func Beta(asset, benchmark []float64) *float64 {
+	n := len(asset)
+	if len(benchmark) < n {
+		n = len(benchmark)
+	}
+
+	var ra, rb []float64
+	for i := 1; i < n; i++ {
+		if asset[i-1] <= 0 || asset[i] <= 0 || benchmark[i-1] <= 0 || benchmark[i] <= 0 {
+			continue
+		}
+		ra = append(ra, math.Log(asset[i]/asset[i-1]))
+		rb = append(rb, math.Log(benchmark[i]/benchmark[i-1]))
+	}
+	if len(ra) < minBetaReturns {
+		return nil
+	}
+
+	var meanA, meanB float64
+	for i := range ra {
+		meanA += ra[i]
+		meanB += rb[i]
+	}
+	meanA /= float64(len(ra))
+	meanB /= float64(len(rb))
+
+	var cov, variance float64
+	for i := range ra {
+		cov += (ra[i] - meanA) * (rb[i] - meanB)
+		variance += (rb[i] - meanB) * (rb[i] - meanB)
+	}
+	if variance == 0 {
+		return nil
+	}
+
+	beta := cov / variance
+	return &beta
+}
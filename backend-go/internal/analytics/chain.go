@@ -0,0 +1,178 @@
+package analytics
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+)
+
+// marketLocation is the exchange timezone used for expiration math.
+// Falls back to UTC when tzdata is unavailable (e.g. minimal containers).
+var marketLocation = loadMarketLocation()
+
+func loadMarketLocation() *time.Location {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// MarketTime converts t to exchange time
+func MarketTime(t time.Time) time.Time {
+	return t.In(marketLocation)
+}
+
+// MarketDate truncates t to midnight of the current trading date in exchange time
+func MarketDate(t time.Time) time.Time {
+	y, m, d := t.In(marketLocation).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// MarketDayEnd returns the moment a trading date (midnight UTC, as from ParseDate) ends in
+// exchange time
+func MarketDayEnd(date time.Time) time.Time {
+	y, m, d := date.Date()
+	return time.Date(y, m, d+1, 0, 0, 0, 0, marketLocation)
+}
+
+// ParseDate parses a YYYY-MM-DD date string
+func ParseDate(date string) (time.Time, error) {
+	return time.Parse("2006-01-02", date)
+}
+
+// DaysToExpiration returns calendar days between now (exchange time) and the expiration date
+func DaysToExpiration(expiration string, now time.Time) (int, error) {
+	exp, err := ParseDate(expiration)
+	if err != nil {
+		return 0, err
+	}
+	return int(math.Round(exp.Sub(MarketDate(now)).Hours() / 24)), nil
+}
+
+// YearFraction converts calendar days to a year fraction for pricing models
+func YearFraction(days int) float64 {
+	return float64(days) / 365.0
+}
+
+// Expirations returns the distinct expiration dates in the chain, sorted ascending
+func Expirations(contracts []models.OptionContract) []string {
+	seen := make(map[string]bool)
+	var expirations []string
+	for i := range contracts {
+		d := contracts[i].Details
+		if d == nil || d.ExpirationDate == nil || seen[*d.ExpirationDate] {
+			continue
+		}
+		seen[*d.ExpirationDate] = true
+		expirations = append(expirations, *d.ExpirationDate)
+	}
+	sort.Strings(expirations)
+	return expirations
+}
+
+// ForExpiration returns the contracts expiring on the given date
+func ForExpiration(contracts []models.OptionContract, expiration string) []models.OptionContract {
+	var out []models.OptionContract
+	for i := range contracts {
+		d := contracts[i].Details
+		if d != nil && d.ExpirationDate != nil && *d.ExpirationDate == expiration {
+			out = append(out, contracts[i])
+		}
+	}
+	return out
+}
+
+// ContractPrice returns the best available price for a contract:
+// the bid/ask mid when quoted, otherwise the last trade, otherwise the day close
+func ContractPrice(c *models.OptionContract) *float64 {
+	if c.LastQuote != nil && c.LastQuote.Bid != nil && c.LastQuote.Ask != nil && *c.LastQuote.Ask > 0 {
+		return c.LastQuote.MidPrice()
+	}
+	if c.LastTrade != nil && c.LastTrade.Price != nil && *c.LastTrade.Price > 0 {
+		return c.LastTrade.Price
+	}
+	if c.Day != nil && c.Day.Close != nil && *c.Day.Close > 0 {
+		return c.Day.Close
+	}
+	if c.Session != nil && c.Session.Close != nil && *c.Session.Close > 0 {
+		return c.Session.Close
+	}
+	return nil
+}
+
+// StrikePair holds the call and put listed at the same strike and expiration
+type StrikePair struct {
+	Strike float64
+	Call   *models.OptionContract
+	Put    *models.OptionContract
+}
+
+// StrikePairs groups contracts of a single expiration into call/put pairs, sorted by strike
+func StrikePairs(contracts []models.OptionContract) []StrikePair {
+	byStrike := make(map[float64]*StrikePair)
+	for i := range contracts {
+		c := &contracts[i]
+		if c.Details == nil || c.Details.StrikePrice == nil || c.Details.ContractType == nil {
+			continue
+		}
+		strike := *c.Details.StrikePrice
+		pair, ok := byStrike[strike]
+		if !ok {
+			pair = &StrikePair{Strike: strike}
+			byStrike[strike] = pair
+		}
+		switch *c.Details.ContractType {
+		case "call":
+			pair.Call = c
+		case "put":
+			pair.Put = c
+		}
+	}
+
+	pairs := make([]StrikePair, 0, len(byStrike))
+	for _, p := range byStrike {
+		pairs = append(pairs, *p)
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].Strike < pairs[j].Strike })
+	return pairs
+}
+
+// ATMPair returns the strike pair closest to spot that has both a call and a put listed
+func ATMPair(contracts []models.OptionContract, spot float64) *StrikePair {
+	var best *StrikePair
+	pairs := StrikePairs(contracts)
+	for i := range pairs {
+		if pairs[i].Call == nil || pairs[i].Put == nil {
+			continue
+		}
+		if best == nil || math.Abs(pairs[i].Strike-spot) < math.Abs(best.Strike-spot) {
+			best = &pairs[i]
+		}
+	}
+	return best
+}
+
+// ATMImpliedVol returns the average call/put implied volatility at the ATM strike
+// of a single expiration, or nil when neither leg reports IV
+func ATMImpliedVol(contracts []models.OptionContract, spot float64) *float64 {
+	pair := ATMPair(contracts, spot)
+	if pair == nil {
+		return nil
+	}
+
+	sum, n := 0.0, 0
+	for _, leg := range []*models.OptionContract{pair.Call, pair.Put} {
+		if leg.ImpliedVol != nil && *leg.ImpliedVol > 0 {
+			sum += *leg.ImpliedVol
+			n++
+		}
+	}
+	if n == 0 {
+		return nil
+	}
+	iv := sum / float64(n)
+	return &iv
+}
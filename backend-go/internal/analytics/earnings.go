@@ -0,0 +1,125 @@
+package analytics
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+)
+
+// minBackMonthGap is the minimum number of days between the front and back expirations
+// used for the term-structure comparison
+const minBackMonthGap = 21
+
+// EarningsCrush estimates how much implied volatility is priced to collapse after earnings
+type EarningsCrush struct {
+	Ticker           string         `json:"ticker"`
+	UnderlyingPrice  float64        `json:"underlying_price"`
+	EarningsDate     string         `json:"earnings_date"`
+	BeforeOpen       bool           `json:"before_open"`
+	FrontExpiration  string         `json:"front_expiration"`
+	FrontDTE         int            `json:"front_dte"`
+	FrontIV          float64        `json:"front_iv"`
+	BackExpiration   string         `json:"back_expiration"`
+	BackDTE          int            `json:"back_dte"`
+	BackIV           float64        `json:"back_iv"`
+	TermSpread       float64        `json:"term_spread"`                  // front IV minus back IV
+	Method           string         `json:"method"`                       // "term_structure" or "back_month"
+	EventMovePct     *float64       `json:"event_move_pct,omitempty"`     // implied one-day earnings move (1 std dev), %
+	PostEarningsIV   *float64       `json:"post_earnings_iv,omitempty"`   // expected front IV once the event passes
+	ExpectedCrush    *float64       `json:"expected_crush,omitempty"`     // front IV minus post-earnings IV
+	ExpectedCrushPct *float64       `json:"expected_crush_pct,omitempty"` // crush as % of front IV
+	Straddle         *StraddleQuote `json:"straddle,omitempty"`
+}
+
+// AnalyzeEarningsCrush compares the first expiration capturing the earnings release with a
+// later back-month expiration and decomposes the front variance into a baseline diffusion
+// component and a one-day event component:
+//
+//	σf²·Tf = σ²·Tf + E²,  σb²·Tb = σ²·Tb + E²
+//
+// The baseline σ is the expected front IV after the release. When the term structure is too
+// inverted for the decomposition to hold, the back-month IV is used as the baseline instead.
+func AnalyzeEarningsCrush(contracts []models.OptionContract, spot float64, earningsDate time.Time, beforeOpen bool, now time.Time) (*EarningsCrush, error) {
+	type expIV struct {
+		expiration string
+		dte        int
+		iv         float64
+	}
+
+	// Collect expirations that capture the release and have an ATM IV
+	var candidates []expIV
+	for _, exp := range Expirations(contracts) {
+		expDate, err := ParseDate(exp)
+		if err != nil {
+			continue
+		}
+		if expDate.Before(earningsDate) || (expDate.Equal(earningsDate) && !beforeOpen) {
+			continue
+		}
+		dte, err := DaysToExpiration(exp, now)
+		if err != nil || dte <= 0 {
+			continue
+		}
+		iv := ATMImpliedVol(ForExpiration(contracts, exp), spot)
+		if iv == nil {
+			continue
+		}
+		candidates = append(candidates, expIV{expiration: exp, dte: dte, iv: *iv})
+	}
+
+	if len(candidates) < 2 {
+		return nil, fmt.Errorf("need at least two expirations after %s with implied volatility, found %d",
+			earningsDate.Format("2006-01-02"), len(candidates))
+	}
+
+	front := candidates[0]
+	back := candidates[len(candidates)-1]
+	for _, c := range candidates[1:] {
+		if c.dte-front.dte >= minBackMonthGap {
+			back = c
+			break
+		}
+	}
+
+	result := &EarningsCrush{
+		UnderlyingPrice: spot,
+		EarningsDate:    earningsDate.Format("2006-01-02"),
+		BeforeOpen:      beforeOpen,
+		FrontExpiration: front.expiration,
+		FrontDTE:        front.dte,
+		FrontIV:         front.iv,
+		BackExpiration:  back.expiration,
+		BackDTE:         back.dte,
+		BackIV:          back.iv,
+		TermSpread:      front.iv - back.iv,
+		Straddle:        ATMStraddle(ForExpiration(contracts, front.expiration), spot),
+	}
+
+	tf := YearFraction(front.dte)
+	tb := YearFraction(back.dte)
+	baselineVar := (back.iv*back.iv*tb - front.iv*front.iv*tf) / (tb - tf)
+	eventVar := (front.iv*front.iv - baselineVar) * tf
+
+	var postIV float64
+	if baselineVar > 0 && eventVar > 0 {
+		result.Method = "term_structure"
+		postIV = math.Sqrt(baselineVar)
+		move := math.Sqrt(eventVar) * 100.0
+		result.EventMovePct = &move
+	} else {
+		result.Method = "back_month"
+		postIV = back.iv
+	}
+
+	crush := front.iv - postIV
+	result.PostEarningsIV = &postIV
+	result.ExpectedCrush = &crush
+	if front.iv > 0 {
+		crushPct := crush / front.iv * 100.0
+		result.ExpectedCrushPct = &crushPct
+	}
+
+	return result, nil
+}
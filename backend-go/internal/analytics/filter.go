@@ -0,0 +1,119 @@
+package analytics
+
+import (
+	"math"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+)
+
+// Moneyness buckets accepted by the chain filter
+const (
+	MoneynessITM = "itm"
+	MoneynessOTM = "otm"
+	MoneynessATM = "atm"
+)
+
+// ChainFilter narrows a chain after it has been fetched. Nil/empty fields are not applied.
+type ChainFilter struct {
+	MinDTE    *int
+	MaxDTE    *int
+	Moneyness string   // "itm", "otm" or "atm"
+	ATMBand   float64  // half-width of the ATM bucket as % of spot
+	StrikePct *float64 // keep strikes within this % of spot
+	DeltaMin  *float64 // bounds on absolute delta, so one range covers calls and puts
+	DeltaMax  *float64
+
+	MinLiquidity *float64 // minimum liquidity score (0-100)
+}
+
+// Active reports whether any filter is set
+func (f *ChainFilter) Active() bool {
+	return f.MinDTE != nil || f.MaxDTE != nil || f.Moneyness != "" || f.StrikePct != nil ||
+		f.DeltaMin != nil || f.DeltaMax != nil || f.MinLiquidity != nil
+}
+
+// FilterChain returns the contracts matching every active filter. Moneyness uses the
+// underlying price attached to each contract; contracts missing the data a filter
+// needs are excluded by that filter.
+func FilterChain(contracts []models.OptionContract, f ChainFilter, now time.Time) []models.OptionContract {
+	if !f.Active() {
+		return contracts
+	}
+
+	out := make([]models.OptionContract, 0, len(contracts))
+	for i := range contracts {
+		if f.matches(&contracts[i], now) {
+			out = append(out, contracts[i])
+		}
+	}
+	return out
+}
+
+func (f *ChainFilter) matches(c *models.OptionContract, now time.Time) bool {
+	d := c.Details
+	if d == nil {
+		return false
+	}
+
+	if f.MinDTE != nil || f.MaxDTE != nil {
+		if d.ExpirationDate == nil {
+			return false
+		}
+		dte, err := DaysToExpiration(*d.ExpirationDate, now)
+		if err != nil {
+			return false
+		}
+		if (f.MinDTE != nil && dte < *f.MinDTE) || (f.MaxDTE != nil && dte > *f.MaxDTE) {
+			return false
+		}
+	}
+
+	if f.Moneyness != "" || f.StrikePct != nil {
+		if d.StrikePrice == nil || d.ContractType == nil || c.UnderlyingAsset == nil ||
+			c.UnderlyingAsset.Price == nil || *c.UnderlyingAsset.Price <= 0 {
+			return false
+		}
+		spot := *c.UnderlyingAsset.Price
+		distancePct := math.Abs(*d.StrikePrice/spot-1) * 100.0
+
+		if f.StrikePct != nil && distancePct > *f.StrikePct {
+			return false
+		}
+
+		switch f.Moneyness {
+		case MoneynessATM:
+			if distancePct > f.ATMBand {
+				return false
+			}
+		case MoneynessITM, MoneynessOTM:
+			itm := (*d.ContractType == "call" && *d.StrikePrice < spot) ||
+				(*d.ContractType == "put" && *d.StrikePrice > spot)
+			if itm != (f.Moneyness == MoneynessITM) {
+				return false
+			}
+		}
+	}
+
+	if f.DeltaMin != nil || f.DeltaMax != nil {
+		if c.Greeks == nil || c.Greeks.Delta == nil {
+			return false
+		}
+		delta := math.Abs(*c.Greeks.Delta)
+		if (f.DeltaMin != nil && delta < *f.DeltaMin) || (f.DeltaMax != nil && delta > *f.DeltaMax) {
+			return false
+		}
+	}
+
+	if f.MinLiquidity != nil {
+		score := c.LiquidityScore
+		if score == nil {
+			score = LiquidityScore(c)
+		}
+		if score == nil || *score < *f.MinLiquidity {
+			return false
+		}
+	}
+
+	return true
+}
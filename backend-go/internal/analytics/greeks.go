@@ -0,0 +1,68 @@
+package analytics
+
+import (
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/pricing"
+)
+
+// PricingInputs builds Black-Scholes inputs from a contract's own strike, expiration,
+// implied volatility and underlying price. Returns false when any input is missing.
+func PricingInputs(c *models.OptionContract, rate float64, now time.Time) (pricing.Inputs, bool) {
+	d := c.Details
+	if d == nil || d.StrikePrice == nil || d.ExpirationDate == nil || d.ContractType == nil ||
+		c.ImpliedVol == nil || *c.ImpliedVol <= 0 ||
+		c.UnderlyingAsset == nil || c.UnderlyingAsset.Price == nil {
+		return pricing.Inputs{}, false
+	}
+
+	dte, err := DaysToExpiration(*d.ExpirationDate, now)
+	if err != nil || dte <= 0 {
+		return pricing.Inputs{}, false
+	}
+
+	return pricing.Inputs{
+		Type:   pricing.OptionType(*d.ContractType),
+		Spot:   *c.UnderlyingAsset.Price,
+		Strike: *d.StrikePrice,
+		Years:  YearFraction(dte),
+		Rate:   rate,
+		Vol:    *c.ImpliedVol,
+	}, true
+}
+
+// AttachSecondOrderGreeks computes vanna, charm and vomma for every contract that has the
+// required inputs and stores them on the contract's greeks in place
+func AttachSecondOrderGreeks(contracts []models.OptionContract, rate float64, now time.Time) int {
+	computed := 0
+	for i := range contracts {
+		in, ok := PricingInputs(&contracts[i], rate, now)
+		if !ok {
+			continue
+		}
+
+		so := pricing.ComputeSecondOrder(in)
+		if contracts[i].Greeks == nil {
+			contracts[i].Greeks = &models.Greeks{}
+		}
+		contracts[i].Greeks.Vanna = &so.Vanna
+		contracts[i].Greeks.Charm = &so.Charm
+		contracts[i].Greeks.Vomma = &so.Vomma
+		computed++
+	}
+	return computed
+}
+
+// ContractDelta returns the snapshot delta of a contract, or the model delta when the
+// snapshot has none and the contract has pricing inputs
+func ContractDelta(c *models.OptionContract, rate float64, now time.Time) *float64 {
+	if c.Greeks != nil && c.Greeks.Delta != nil {
+		return c.Greeks.Delta
+	}
+	if in, ok := PricingInputs(c, rate, now); ok {
+		delta := pricing.Compute(in).Delta
+		return &delta
+	}
+	return nil
+}
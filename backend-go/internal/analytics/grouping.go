@@ -0,0 +1,86 @@
+package analytics
+
+import (
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+)
+
+// Volume returns the contract's day volume, falling back to session volume
+func Volume(c *models.OptionContract) int64 {
+	if c.Day != nil && c.Day.Volume != nil {
+		return *c.Day.Volume
+	}
+	if c.Session != nil && c.Session.Volume != nil {
+		return *c.Session.Volume
+	}
+	return 0
+}
+
+// UnderlyingPrice returns the first positive underlying price reported on the contracts
+func UnderlyingPrice(contracts []models.OptionContract) *float64 {
+	for i := range contracts {
+		ua := contracts[i].UnderlyingAsset
+		if ua != nil && ua.Price != nil && *ua.Price > 0 {
+			return ua.Price
+		}
+	}
+	return nil
+}
+
+// GroupByExpiration nests the chain as expiration → strike → call/put pair and computes
+// per-expiration ATM IV, volume and open interest. ATM statistics are omitted when the
+// underlying price is unknown.
+func GroupByExpiration(contracts []models.OptionContract, spot *float64, now time.Time) []models.ExpirationGroup {
+	expirations := Expirations(contracts)
+	groups := make([]models.ExpirationGroup, 0, len(expirations))
+
+	for _, exp := range expirations {
+		expContracts := ForExpiration(contracts, exp)
+		dte, _ := DaysToExpiration(exp, now)
+
+		group := models.ExpirationGroup{
+			ExpirationDate: exp,
+			DTE:            dte,
+			Summary:        models.ExpirationSummary{ContractCount: len(expContracts)},
+		}
+
+		for _, pair := range StrikePairs(expContracts) {
+			group.Strikes = append(group.Strikes, models.StrikeRow{
+				Strike: pair.Strike,
+				Call:   pair.Call,
+				Put:    pair.Put,
+			})
+		}
+
+		for i := range expContracts {
+			c := &expContracts[i]
+			var oi int64
+			if c.OpenInterest != nil {
+				oi = *c.OpenInterest
+			}
+			vol := Volume(c)
+			if c.Details != nil && c.Details.ContractType != nil && *c.Details.ContractType == "put" {
+				group.Summary.PutVolume += vol
+				group.Summary.PutOpenInterest += oi
+			} else {
+				group.Summary.CallVolume += vol
+				group.Summary.CallOpenInterest += oi
+			}
+		}
+		group.Summary.TotalVolume = group.Summary.CallVolume + group.Summary.PutVolume
+		group.Summary.TotalOpenInterest = group.Summary.CallOpenInterest + group.Summary.PutOpenInterest
+
+		if spot != nil {
+			if atm := ATMPair(expContracts, *spot); atm != nil {
+				strike := atm.Strike
+				group.Summary.ATMStrike = &strike
+			}
+			group.Summary.ATMIV = ATMImpliedVol(expContracts, *spot)
+		}
+
+		groups = append(groups, group)
+	}
+
+	return groups
+}
@@ -0,0 +1,84 @@
+package analytics
+
+import (
+	"math"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+)
+
+// ConstantMaturityIV interpolates ATM IV to a fixed maturity (e.g. 30 days) using total
+// variance between the two expirations bracketing the target. Outside the listed range
+// the nearest expiration's ATM IV is used.
+func ConstantMaturityIV(contracts []models.OptionContract, spot float64, targetDays int, now time.Time) *float64 {
+	type point struct {
+		dte int
+		iv  float64
+	}
+
+	var points []point
+	for _, exp := range Expirations(contracts) {
+		dte, err := DaysToExpiration(exp, now)
+		if err != nil || dte <= 0 {
+			continue
+		}
+		if iv := ATMImpliedVol(ForExpiration(contracts, exp), spot); iv != nil {
+			points = append(points, point{dte: dte, iv: *iv})
+		}
+	}
+	if len(points) == 0 {
+		return nil
+	}
+
+	// Expirations are sorted, so points are in ascending DTE order
+	if targetDays <= points[0].dte {
+		return &points[0].iv
+	}
+	last := points[len(points)-1]
+	if targetDays >= last.dte {
+		return &last.iv
+	}
+
+	for i := 1; i < len(points); i++ {
+		lo, hi := points[i-1], points[i]
+		if targetDays > hi.dte {
+			continue
+		}
+		loVar := lo.iv * lo.iv * float64(lo.dte)
+		hiVar := hi.iv * hi.iv * float64(hi.dte)
+		w := float64(targetDays-lo.dte) / float64(hi.dte-lo.dte)
+		iv := math.Sqrt((loVar + (hiVar-loVar)*w) / float64(targetDays))
+		return &iv
+	}
+	return nil
+}
+
+// IVRank computes IV rank and IV percentile of current against the historical readings
+func IVRank(window string, history []float64, current float64) models.IVRankWindow {
+	result := models.IVRankWindow{Window: window, Observations: len(history)}
+	if len(history) == 0 {
+		return result
+	}
+
+	low, high := history[0], history[0]
+	below := 0
+	for _, iv := range history {
+		low = math.Min(low, iv)
+		high = math.Max(high, iv)
+		if iv < current {
+			below++
+		}
+	}
+	result.Low = &low
+	result.High = &high
+
+	percentile := float64(below) / float64(len(history)) * 100.0
+	result.IVPercentile = &percentile
+
+	if high > low {
+		rank := math.Max(0, math.Min(100, (current-low)/(high-low)*100.0))
+		result.IVRank = &rank
+	}
+
+	return result
+}
@@ -0,0 +1,79 @@
+package analytics
+
+import (
+	"math"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+)
+
+// Liquidity score component weights and saturation points. A component reaches its full
+// score at the saturation point; volume, open interest and size are scored on a log scale.
+const (
+	spreadWeight  = 0.40
+	volumeWeight  = 0.20
+	oiWeight      = 0.25
+	sizeWeight    = 0.15
+	maxRelSpread  = 0.30 // relative spread at or above this scores zero
+	fullVolume    = 1000
+	fullOI        = 5000
+	fullQuoteSize = 100
+)
+
+// LiquidityScore returns a 0-100 composite of relative spread, volume, open interest and
+// quote size. Components without data are dropped and the remaining weights renormalized,
+// so contracts without quotes are scored on volume and open interest alone. Returns nil
+// when no component is available.
+func LiquidityScore(c *models.OptionContract) *float64 {
+	total, weights := 0.0, 0.0
+
+	if q := c.LastQuote; q != nil && q.Bid != nil && q.Ask != nil && *q.Ask > 0 && *q.Ask >= *q.Bid {
+		mid := *q.MidPrice()
+		relSpread := 1.0
+		if mid > 0 {
+			relSpread = *q.Spread() / mid
+		}
+		total += spreadWeight * clamp01(1-relSpread/maxRelSpread)
+		weights += spreadWeight
+
+		if q.BidSize != nil && q.AskSize != nil {
+			size := math.Min(float64(*q.BidSize), float64(*q.AskSize))
+			total += sizeWeight * logScore(size, fullQuoteSize)
+			weights += sizeWeight
+		}
+	}
+
+	if c.Day != nil && c.Day.Volume != nil || c.Session != nil && c.Session.Volume != nil {
+		total += volumeWeight * logScore(float64(Volume(c)), fullVolume)
+		weights += volumeWeight
+	}
+
+	if c.OpenInterest != nil {
+		total += oiWeight * logScore(float64(*c.OpenInterest), fullOI)
+		weights += oiWeight
+	}
+
+	if weights == 0 {
+		return nil
+	}
+	score := math.Round(total/weights*1000) / 10
+	return &score
+}
+
+// ScoreLiquidity attaches a liquidity score to every contract in place
+func ScoreLiquidity(contracts []models.OptionContract) {
+	for i := range contracts {
+		contracts[i].LiquidityScore = LiquidityScore(&contracts[i])
+	}
+}
+
+// logScore maps v onto [0,1] logarithmically, reaching 1 at full
+func logScore(v, full float64) float64 {
+	if v <= 0 {
+		return 0
+	}
+	return clamp01(math.Log10(1+v) / math.Log10(1+full))
+}
+
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
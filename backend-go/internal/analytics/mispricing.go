@@ -0,0 +1,134 @@
+package analytics
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/pricing"
+)
+
+// VolSource selects the volatility input for theoretical pricing
+type VolSource string
+
+const (
+	VolSourceATMIV      VolSource = "atm_iv"     // ATM IV of each contract's own expiration
+	VolSourceHistorical VolSource = "historical" // realized volatility of the underlying
+	VolSourceFixed      VolSource = "fixed"      // caller-supplied volatility
+)
+
+// MispricingParams configures a mispricing scan
+type MispricingParams struct {
+	VolSource VolSource
+	Vol       float64 // used for historical and fixed sources
+	Rate      float64
+	DivYield  float64
+	MinPrice  float64 // ignore contracts priced below this
+	RankBy    string  // "pct" (default) or "abs"
+	Limit     int
+}
+
+// Mispricing compares a contract's market price with its Black-Scholes value
+type Mispricing struct {
+	Ticker           *string  `json:"ticker,omitempty"`
+	ContractType     string   `json:"contract_type"`
+	StrikePrice      float64  `json:"strike_price"`
+	ExpirationDate   string   `json:"expiration_date"`
+	DTE              int      `json:"dte"`
+	MarketPrice      float64  `json:"market_price"`
+	TheoreticalPrice float64  `json:"theoretical_price"`
+	Difference       float64  `json:"difference"`     // market minus theoretical
+	DifferencePct    float64  `json:"difference_pct"` // difference as % of theoretical
+	ModelVol         float64  `json:"model_vol"`
+	MarketIV         *float64 `json:"market_iv,omitempty"`
+	Bid              *float64 `json:"bid,omitempty"`
+	Ask              *float64 `json:"ask,omitempty"`
+}
+
+// ScanMispricing prices every contract in the chain and returns those with the largest
+// discrepancies between market price and theoretical value
+func ScanMispricing(contracts []models.OptionContract, spot float64, params MispricingParams, now time.Time) []Mispricing {
+	// Cache ATM IV per expiration for the atm_iv source
+	atmIVs := make(map[string]*float64)
+	if params.VolSource == VolSourceATMIV {
+		for _, exp := range Expirations(contracts) {
+			atmIVs[exp] = ATMImpliedVol(ForExpiration(contracts, exp), spot)
+		}
+	}
+
+	var results []Mispricing
+	for i := range contracts {
+		c := &contracts[i]
+		d := c.Details
+		if d == nil || d.StrikePrice == nil || d.ExpirationDate == nil || d.ContractType == nil {
+			continue
+		}
+
+		market := ContractPrice(c)
+		if market == nil || *market < params.MinPrice {
+			continue
+		}
+
+		dte, err := DaysToExpiration(*d.ExpirationDate, now)
+		if err != nil || dte <= 0 {
+			continue
+		}
+
+		vol := params.Vol
+		if params.VolSource == VolSourceATMIV {
+			iv := atmIVs[*d.ExpirationDate]
+			if iv == nil {
+				continue
+			}
+			vol = *iv
+		}
+		if vol <= 0 {
+			continue
+		}
+
+		theo := pricing.Price(pricing.Inputs{
+			Type:     pricing.OptionType(*d.ContractType),
+			Spot:     spot,
+			Strike:   *d.StrikePrice,
+			Years:    YearFraction(dte),
+			Rate:     params.Rate,
+			DivYield: params.DivYield,
+			Vol:      vol,
+		})
+		if theo <= 0 {
+			continue
+		}
+
+		m := Mispricing{
+			Ticker:           d.Ticker,
+			ContractType:     *d.ContractType,
+			StrikePrice:      *d.StrikePrice,
+			ExpirationDate:   *d.ExpirationDate,
+			DTE:              dte,
+			MarketPrice:      *market,
+			TheoreticalPrice: theo,
+			Difference:       *market - theo,
+			DifferencePct:    (*market - theo) / theo * 100.0,
+			ModelVol:         vol,
+			MarketIV:         c.ImpliedVol,
+		}
+		if c.LastQuote != nil {
+			m.Bid = c.LastQuote.Bid
+			m.Ask = c.LastQuote.Ask
+		}
+		results = append(results, m)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if params.RankBy == "abs" {
+			return math.Abs(results[i].Difference) > math.Abs(results[j].Difference)
+		}
+		return math.Abs(results[i].DifferencePct) > math.Abs(results[j].DifferencePct)
+	})
+
+	if params.Limit > 0 && len(results) > params.Limit {
+		results = results[:params.Limit]
+	}
+	return results
+}
@@ -0,0 +1,175 @@
+package analytics
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+)
+
+// MaxChainPageSize is the largest page of contracts a chain request may ask for
+const MaxChainPageSize = 1000
+
+// ErrInvalidCursor is returned for a cursor this server did not issue, or one issued for a
+// different sort
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Fields a chain can be sorted by
+const (
+	SortVolume       = "volume"
+	SortOpenInterest = "open_interest"
+	SortIV           = "iv"
+	SortStrike       = "strike"
+)
+
+// ChainSort orders a chain by one field. An empty Field orders by contract ticker, which for
+// OCC symbols is by expiration, type and strike.
+type ChainSort struct {
+	Field string
+	Desc  bool
+}
+
+// ChainPage selects one page of a chain in an order. A zero Size means the whole chain.
+type ChainPage struct {
+	Order  ChainSort
+	Cursor string // from the previous page's next_cursor; empty for the first page
+	Size   int
+}
+
+// chainKey is a contract's position in a sorted chain: the sorted field's value, nil when the
+// contract lacks it, with the ticker breaking ties
+type chainKey struct {
+	Value  *float64 `json:"v,omitempty"`
+	Ticker string   `json:"t"`
+}
+
+// chainCursor is what a cursor encodes: the sort it was issued for and the key of the last
+// contract returned
+type chainCursor struct {
+	Sort string `json:"s,omitempty"`
+	chainKey
+}
+
+// String names the sort as cursors record it: the field, prefixed with "-" when descending
+func (s ChainSort) String() string {
+	if s.Desc {
+		return "-" + s.Field
+	}
+	return s.Field
+}
+
+func (c chainCursor) encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeChainCursor(s string, order ChainSort) (chainCursor, error) {
+	var c chainCursor
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || json.Unmarshal(raw, &c) != nil || c.Sort != order.String() {
+		return c, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// Validate reports whether the page's cursor can be read for its order, so a request can be
+// rejected before its chain is fetched
+func (p ChainPage) Validate() error {
+	if p.Cursor == "" {
+		return nil
+	}
+	_, err := decodeChainCursor(p.Cursor, p.Order)
+	return err
+}
+
+// SortChain orders contracts in place. Contracts missing the sorted field go last whichever
+// the direction, and ties are broken by ticker so the order is the same on every request.
+func SortChain(contracts []models.OptionContract, order ChainSort) {
+	sort.SliceStable(contracts, func(i, j int) bool {
+		return compareKeys(keyOf(&contracts[i], order), keyOf(&contracts[j], order), order.Desc) < 0
+	})
+}
+
+// PageChain orders contracts as p.Order asks and returns the page after p.Cursor with the
+// cursor of the page after it ("" on the last page). The cursor is keyed on the last contract
+// rather than an offset, so a chain that gains or loses contracts between requests neither
+// repeats nor skips the others, though a contract whose sorted value changes may move across
+// pages.
+func PageChain(contracts []models.OptionContract, p ChainPage) ([]models.OptionContract, string, error) {
+	order := p.Order
+	if order.Field != "" || p.Size > 0 || p.Cursor != "" {
+		SortChain(contracts, order)
+	}
+	if p.Size == 0 && p.Cursor == "" {
+		return contracts, "", nil
+	}
+
+	start := 0
+	if p.Cursor != "" {
+		cursor, err := decodeChainCursor(p.Cursor, order)
+		if err != nil {
+			return nil, "", err
+		}
+		start = sort.Search(len(contracts), func(i int) bool {
+			return compareKeys(keyOf(&contracts[i], order), cursor.chainKey, order.Desc) > 0
+		})
+	}
+
+	end := len(contracts)
+	if p.Size > 0 && start+p.Size < end {
+		end = start + p.Size
+	}
+	page := contracts[start:end]
+	if end == len(contracts) || len(page) == 0 {
+		return page, "", nil
+	}
+	next := chainCursor{Sort: order.String(), chainKey: keyOf(&page[len(page)-1], order)}
+	return page, next.encode(), nil
+}
+
+// keyOf returns a contract's sort key
+func keyOf(c *models.OptionContract, order ChainSort) chainKey {
+	key := chainKey{}
+	if c.Details != nil && c.Details.Ticker != nil {
+		key.Ticker = *c.Details.Ticker
+	}
+	switch order.Field {
+	case SortVolume:
+		if c.Day != nil && c.Day.Volume != nil {
+			v := float64(*c.Day.Volume)
+			key.Value = &v
+		}
+	case SortOpenInterest:
+		if c.OpenInterest != nil {
+			v := float64(*c.OpenInterest)
+			key.Value = &v
+		}
+	case SortIV:
+		key.Value = c.ImpliedVol
+	case SortStrike:
+		if c.Details != nil {
+			key.Value = c.Details.StrikePrice
+		}
+	}
+	return key
+}
+
+// compareKeys orders two keys of the same sort: by value in the sort's direction with missing
+// values last, then by ticker ascending
+func compareKeys(a, b chainKey, desc bool) int {
+	switch {
+	case a.Value == nil && b.Value != nil:
+		return 1
+	case a.Value != nil && b.Value == nil:
+		return -1
+	case a.Value != nil && *a.Value != *b.Value:
+		if (*a.Value < *b.Value) != desc {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(a.Ticker, b.Ticker)
+}
@@ -0,0 +1,110 @@
+package analytics
+
+import "math"
+
+// irrIterations bounds the bisection used to solve for the internal rate of return
+const irrIterations = 200
+
+// NetFlows returns the capital added to (positive) or withdrawn from (negative) a portfolio
+// each day: the change in equity not explained by the change in total P/L. Opening a
+// position adds its cost; closing one withdraws its proceeds. The first day has no flow.
+func NetFlows(equity, pnl []float64) []float64 {
+	flows := make([]float64, len(equity))
+	for i := 1; i < len(equity); i++ {
+		flows[i] = (equity[i] - equity[i-1]) - (pnl[i] - pnl[i-1])
+	}
+	return flows
+}
+
+// TimeWeightedReturns chains daily returns into the cumulative time-weighted return as of
+// each day. Flows land at the start of the day, so a day's return is its equity over the
+// previous equity plus the flow. Days without positive starting capital (for example when
+// short premium outweighs long holdings) add no return and are counted as undefined.
+func TimeWeightedReturns(equity, flows []float64) ([]float64, int) {
+	cumulative := make([]float64, len(equity))
+	growth := 1.0
+	undefined := 0
+	for i := 1; i < len(equity); i++ {
+		base := equity[i-1] + flows[i]
+		if base <= 0 {
+			undefined++
+		} else {
+			growth *= equity[i] / base
+		}
+		cumulative[i] = growth - 1
+	}
+	return cumulative, undefined
+}
+
+// ModifiedDietzReturns returns the money-weighted return from the first day through each
+// day, weighting each flow by the share of the period it was invested. days are offsets in
+// calendar days from the first day. A return is nil when the average invested capital is not
+// positive.
+func ModifiedDietzReturns(days, equity, flows []float64) []*float64 {
+	returns := make([]*float64, len(equity))
+	if len(equity) == 0 {
+		return returns
+	}
+	zero := 0.0
+	returns[0] = &zero
+
+	var sumFlows, sumFlowStart float64
+	for k := 1; k < len(equity); k++ {
+		// A flow at the start of day k is invested from the close of day k-1
+		sumFlows += flows[k]
+		sumFlowStart += flows[k] * days[k-1]
+
+		period := days[k] - days[0]
+		if period <= 0 {
+			continue
+		}
+		capital := equity[0] + (days[k]*sumFlows-sumFlowStart)/period
+		if capital <= 0 {
+			continue
+		}
+		r := (equity[k] - equity[0] - sumFlows) / capital
+		returns[k] = &r
+	}
+	return returns
+}
+
+// AnnualizedIRR solves for the annual rate at which the starting equity and every flow
+// grow to the ending equity. Returns nil when there is no solution in range, such as when
+// the portfolio's capital changes sign.
+func AnnualizedIRR(days, equity, flows []float64) *float64 {
+	n := len(equity)
+	if n < 2 || days[n-1] <= days[0] {
+		return nil
+	}
+
+	// Future value of the contributions at rate r, less the ending equity
+	excess := func(r float64) float64 {
+		total := equity[0] * math.Pow(1+r, (days[n-1]-days[0])/365)
+		for i := 1; i < n; i++ {
+			total += flows[i] * math.Pow(1+r, (days[n-1]-days[i-1])/365)
+		}
+		return total - equity[n-1]
+	}
+
+	lo, hi := -0.9999, 100.0
+	fLo, fHi := excess(lo), excess(hi)
+	if math.IsNaN(fLo) || math.IsNaN(fHi) || fLo*fHi > 0 {
+		return nil
+	}
+	for i := 0; i < irrIterations; i++ {
+		mid := (lo + hi) / 2
+		fMid := excess(mid)
+		if fMid == 0 {
+			lo, hi = mid, mid
+			break
+		}
+		if (fMid < 0) == (fLo < 0) {
+			lo, fLo = mid, fMid
+		} else {
+			hi = mid
+		}
+	}
+
+	irr := (lo + hi) / 2
+	return &irr
+}
@@ -0,0 +1,126 @@
+package analytics
+
+import (
+	"math"
+	"testing"
+)
+
+// approx compares returns to a millionth
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
+
+func TestNetFlows(t *testing.T) {
+	// Day 1 adds 100 of capital and earns 10; day 2 loses that 10 with no flow
+	got := NetFlows([]float64{100, 210, 200}, []float64{0, 10, 0})
+	want := []float64{0, 100, 0}
+	for i := range want {
+		if !approx(got[i], want[i]) {
+			t.Errorf("day %d: got %g, want %g", i, got[i], want[i])
+		}
+	}
+}
+
+func TestTimeWeightedReturns(t *testing.T) {
+	tests := []struct {
+		name          string
+		equity, flows []float64
+		want          []float64
+		wantUndefined int
+	}{
+		{"no flows", []float64{100, 110, 121}, []float64{0, 0, 0}, []float64{0, 0.1, 0.21}, 0},
+		{"flow at the start of a day", []float64{100, 210, 231}, []float64{0, 100, 0}, []float64{0, 0.05, 0.155}, 0},
+		{"zero capital day", []float64{100, 0, 11}, []float64{0, -100, 10}, []float64{0, 0, 0.1}, 1},
+		{"negative capital days", []float64{-50, -40, -30}, []float64{0, 0, 0}, []float64{0, 0, 0}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, undefined := TimeWeightedReturns(tt.equity, tt.flows)
+			if undefined != tt.wantUndefined {
+				t.Errorf("got %d undefined days, want %d", undefined, tt.wantUndefined)
+			}
+			for i := range tt.want {
+				if !approx(got[i], tt.want[i]) {
+					t.Errorf("day %d: got %g, want %g", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestModifiedDietzReturns(t *testing.T) {
+	tests := []struct {
+		name                string
+		days, equity, flows []float64
+		want                []*float64
+	}{
+		{
+			name: "no flows",
+			days: []float64{0, 1, 2}, equity: []float64{100, 110, 121}, flows: []float64{0, 0, 0},
+			want: []*float64{ptr(0), ptr(0.1), ptr(0.21)},
+		},
+		{
+			// 100 added at the start of day 20 is invested from day 10 for half the period
+			name: "flow weighted by the time invested",
+			days: []float64{0, 10, 20}, equity: []float64{100, 100, 210}, flows: []float64{0, 0, 100},
+			want: []*float64{ptr(0), ptr(0), ptr(10.0 / 150)},
+		},
+		{
+			name: "no capital invested",
+			days: []float64{0, 1}, equity: []float64{0, 5}, flows: []float64{0, 0},
+			want: []*float64{ptr(0), nil},
+		},
+		{
+			name: "no time elapsed",
+			days: []float64{0, 0}, equity: []float64{100, 110}, flows: []float64{0, 0},
+			want: []*float64{ptr(0), nil},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ModifiedDietzReturns(tt.days, tt.equity, tt.flows)
+			for i, want := range tt.want {
+				if (got[i] == nil) != (want == nil) || (want != nil && !approx(*got[i], *want)) {
+					t.Errorf("day %d: got %v, want %v", i, deref(got[i]), deref(want))
+				}
+			}
+		})
+	}
+}
+
+func TestAnnualizedIRR(t *testing.T) {
+	tests := []struct {
+		name                string
+		days, equity, flows []float64
+		want                *float64
+	}{
+		{"one year without flows", []float64{0, 365}, []float64{100, 110}, []float64{0, 0}, ptr(0.1)},
+		// 100 grows for two years and 100 added at the start of day 730 for one: 121 + 110 = 231
+		{"flow after a year", []float64{0, 365, 730}, []float64{100, 110, 231}, []float64{0, 0, 100}, ptr(0.1)},
+		{"loss", []float64{0, 365}, []float64{100, 80}, []float64{0, 0}, ptr(-0.2)},
+		// Capital turns negative, so no rate in the bracket brings 100 to -50
+		{"no sign change across the bracket", []float64{0, 365}, []float64{100, -50}, []float64{0, 0}, nil},
+		// A 999x gain in a year is above the 10000% top of the bracket
+		{"rate above the bracket", []float64{0, 365}, []float64{1, 1000}, []float64{0, 0}, nil},
+		{"no time elapsed", []float64{0, 0}, []float64{100, 110}, []float64{0, 0}, nil},
+		{"one day", []float64{0}, []float64{100}, []float64{0}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := AnnualizedIRR(tt.days, tt.equity, tt.flows)
+			if (got == nil) != (tt.want == nil) || (tt.want != nil && !approx(*got, *tt.want)) {
+				t.Errorf("got %v, want %v", deref(got), deref(tt.want))
+			}
+		})
+	}
+}
+
+func ptr(v float64) *float64 { return &v }
+
+// deref formats an optional return for failure messages
+func deref(v *float64) any {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
@@ -0,0 +1,60 @@
+package analytics
+
+import (
+	"math"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+)
+
+// Roll threat thresholds: a short leg is threatened when it is in the money, its absolute
+// delta reaches threatDelta, or it expires within threatDays
+const (
+	threatDelta = 0.40
+	threatDays  = 7
+)
+
+// Roll score component weights and scales. Net credit and delta reduction score 0.5 when
+// neutral and reach 0 or 1 at their scale in either direction; added duration scores 1 for
+// none and 0 at the scan's maximum.
+const (
+	rollCreditWeight   = 0.45
+	rollDeltaWeight    = 0.35
+	rollDurationWeight = 0.20
+	rollCreditScale    = 0.01 // of the underlying price
+	rollDeltaScale     = 0.30
+)
+
+// RollThreats returns why a short option leg is threatened, if at all. Delta may be nil
+// when unavailable.
+func RollThreats(contractType string, strike, spot float64, delta *float64, dte int) []string {
+	threats := []string{}
+	if contractType == "call" && spot > strike || contractType == "put" && spot < strike {
+		threats = append(threats, models.RollThreatInTheMoney)
+	}
+	if delta != nil && math.Abs(*delta) >= threatDelta {
+		threats = append(threats, models.RollThreatHighDelta)
+	}
+	if dte <= threatDays {
+		threats = append(threats, models.RollThreatExpiring)
+	}
+	return threats
+}
+
+// RollScore returns a 0-100 composite of a roll's net credit, delta reduction and added
+// duration. Candidates without a delta are scored on credit and duration alone.
+func RollScore(c *models.RollCandidate, spot float64, maxAddedDays int) float64 {
+	total := rollCreditWeight * clamp01(0.5+0.5*c.NetCredit/(rollCreditScale*spot))
+	weights := rollCreditWeight
+
+	if c.DeltaReduction != nil {
+		total += rollDeltaWeight * clamp01(0.5+0.5**c.DeltaReduction/rollDeltaScale)
+		weights += rollDeltaWeight
+	}
+
+	if maxAddedDays > 0 {
+		total += rollDurationWeight * clamp01(1-float64(c.AddedDays)/float64(maxAddedDays))
+		weights += rollDurationWeight
+	}
+
+	return math.Round(total/weights*1000) / 10
+}
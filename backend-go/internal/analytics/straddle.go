@@ -0,0 +1,107 @@
+package analytics
+
+import (
+	"math"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+)
+
+// StraddleQuote prices a long call + long put at the same strike
+type StraddleQuote struct {
+	Strike         float64 `json:"strike"`
+	CallPrice      float64 `json:"call_price"`
+	PutPrice       float64 `json:"put_price"`
+	Price          float64 `json:"price"`
+	ImpliedMove    float64 `json:"implied_move"`     // dollar move priced in by expiration
+	ImpliedMovePct float64 `json:"implied_move_pct"` // implied move as % of spot
+	LowerBreakeven float64 `json:"lower_breakeven"`
+	UpperBreakeven float64 `json:"upper_breakeven"`
+	CallTicker     *string `json:"call_ticker,omitempty"`
+	PutTicker      *string `json:"put_ticker,omitempty"`
+}
+
+// ATMStraddle prices the straddle at the strike closest to spot for a single expiration.
+// Returns nil when no strike has priced call and put legs.
+func ATMStraddle(contracts []models.OptionContract, spot float64) *StraddleQuote {
+	pair := ATMPair(contracts, spot)
+	if pair == nil {
+		return nil
+	}
+
+	callPrice := ContractPrice(pair.Call)
+	putPrice := ContractPrice(pair.Put)
+	if callPrice == nil || putPrice == nil {
+		return nil
+	}
+
+	price := *callPrice + *putPrice
+	quote := &StraddleQuote{
+		Strike:         pair.Strike,
+		CallPrice:      *callPrice,
+		PutPrice:       *putPrice,
+		Price:          price,
+		ImpliedMove:    price,
+		LowerBreakeven: pair.Strike - price,
+		UpperBreakeven: pair.Strike + price,
+		CallTicker:     pair.Call.Details.Ticker,
+		PutTicker:      pair.Put.Details.Ticker,
+	}
+	if spot > 0 {
+		quote.ImpliedMovePct = price / spot * 100.0
+	}
+	return quote
+}
+
+// StrangleQuote prices a long OTM call + long OTM put
+type StrangleQuote struct {
+	CallStrike     float64 `json:"call_strike"`
+	PutStrike      float64 `json:"put_strike"`
+	CallPrice      float64 `json:"call_price"`
+	PutPrice       float64 `json:"put_price"`
+	Price          float64 `json:"price"`
+	LowerBreakeven float64 `json:"lower_breakeven"`
+	UpperBreakeven float64 `json:"upper_breakeven"`
+	BreakevenPct   float64 `json:"breakeven_pct"` // average distance of breakevens from spot, %
+	CallTicker     *string `json:"call_ticker,omitempty"`
+	PutTicker      *string `json:"put_ticker,omitempty"`
+}
+
+// Strangle prices the call listed nearest spot+width and the put listed nearest spot-width
+// for a single expiration. Returns nil when either leg is missing or unpriced.
+func Strangle(contracts []models.OptionContract, spot, width float64) *StrangleQuote {
+	var call, put *models.OptionContract
+	var callStrike, putStrike float64
+	for _, pair := range StrikePairs(contracts) {
+		if pair.Call != nil && pair.Strike >= spot && ContractPrice(pair.Call) != nil &&
+			(call == nil || math.Abs(pair.Strike-(spot+width)) < math.Abs(callStrike-(spot+width))) {
+			call, callStrike = pair.Call, pair.Strike
+		}
+		if pair.Put != nil && pair.Strike <= spot && ContractPrice(pair.Put) != nil &&
+			(put == nil || math.Abs(pair.Strike-(spot-width)) < math.Abs(putStrike-(spot-width))) {
+			put, putStrike = pair.Put, pair.Strike
+		}
+	}
+	if call == nil || put == nil {
+		return nil
+	}
+
+	callPrice := *ContractPrice(call)
+	putPrice := *ContractPrice(put)
+	price := callPrice + putPrice
+
+	quote := &StrangleQuote{
+		CallStrike:     callStrike,
+		PutStrike:      putStrike,
+		CallPrice:      callPrice,
+		PutPrice:       putPrice,
+		Price:          price,
+		LowerBreakeven: putStrike - price,
+		UpperBreakeven: callStrike + price,
+		CallTicker:     call.Details.Ticker,
+		PutTicker:      put.Details.Ticker,
+	}
+	if spot > 0 {
+		quote.BreakevenPct = ((quote.UpperBreakeven - spot) + (spot - quote.LowerBreakeven)) / 2 / spot * 100.0
+	}
+	return quote
+}
@@ -0,0 +1,38 @@
+package analytics
+
+import (
+	"math"
+)
+
+// tradingDaysPerYear annualizes daily return volatility
+const tradingDaysPerYear = 252.0
+
+// HistoricalVolatility returns the annualized close-to-close volatility of the given
+// closing prices (oldest first), or nil when fewer than two returns are available
+func HistoricalVolatility(closes []float64) *float64 {
+	var returns []float64
+	for i := 1; i < len(closes); i++ {
+		if closes[i-1] <= 0 || closes[i] <= 0 {
+			continue
+		}
+		returns = append(returns, math.Log(closes[i]/closes[i-1]))
+	}
+	if len(returns) < 2 {
+		return nil
+	}
+
+	mean := 0.0
+	for _, r := range returns {
+		mean += r
+	}
+	mean /= float64(len(returns))
+
+	variance := 0.0
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+	}
+	variance /= float64(len(returns) - 1)
+
+	vol := math.Sqrt(variance * tradingDaysPerYear)
+	return &vol
+}
@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/api/middleware"
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// AccountHandler lets users see their account, take their data away and delete it
+type AccountHandler struct {
+	users     *repository.UserRepository
+	accounts  *repository.AccountRepository
+	audit     *repository.AuditRepository
+	graceDays int
+}
+
+// NewAccountHandler creates a new account handler. Deletions are carried out graceDays after
+// they are asked for.
+func NewAccountHandler(users *repository.UserRepository, accounts *repository.AccountRepository, audit *repository.AuditRepository, graceDays int) *AccountHandler {
+	return &AccountHandler{users: users, accounts: accounts, audit: audit, graceDays: graceDays}
+}
+
+// exportManifest describes an export archive in its manifest.json
+type exportManifest struct {
+	UserID     string    `json:"user_id"`
+	ExportedAt time.Time `json:"exported_at"`
+	Files      []string  `json:"files"`
+}
+
+// GetAccount handles GET /api/v1/me
+func (h *AccountHandler) GetAccount(c *gin.Context) {
+	id, appErr := accountOwner(c)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	user, err := h.users.Get(c.Request.Context(), id)
+	if err != nil {
+		appErr := repositoryError(err, "user", "failed to get account")
+		_ = c.Error(appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// ExportAccount handles POST /api/v1/me/export: a zip archive of everything stored for the
+// user, one JSON file per kind of record, with a manifest.json listing them
+func (h *AccountHandler) ExportAccount(c *gin.Context) {
+	id, appErr := accountOwner(c)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	sections, err := h.accounts.Export(c.Request.Context(), id)
+	if err != nil {
+		log.Printf("[Handler] ✗ Failed to export account %s: %v", id, err)
+		appErr := errors.NewInternalError("failed to export account", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	now := time.Now().UTC()
+	manifest := exportManifest{UserID: id, ExportedAt: now, Files: make([]string, 0, len(sections))}
+	for _, s := range sections {
+		manifest.Files = append(manifest.Files, s.Name+".json")
+	}
+
+	recordAudit(c, h.audit, models.AuditAccountExport, id, nil, nil)
+	filename := fmt.Sprintf("periscope-export-%s.zip", now.Format("2006-01-02"))
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(http.StatusOK)
+	if err := writeExportArchive(c.Writer, manifest, sections); err != nil {
+		log.Printf("[Handler] ✗ Failed to write export archive for account %s: %v", id, err)
+		return
+	}
+	log.Printf("[Handler] ✓ Exported account %s", id)
+}
+
+// DeleteAccount handles DELETE /api/v1/me, scheduling the user's data to be purged once the
+// grace period is over. Until then the account works as before and the deletion can be
+// cancelled.
+func (h *AccountHandler) DeleteAccount(c *gin.Context) {
+	id, appErr := accountOwner(c)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	before, err := h.users.Get(c.Request.Context(), id)
+	if err != nil {
+		appErr := repositoryError(err, "user", "failed to get account")
+		_ = c.Error(appErr)
+		return
+	}
+	now := time.Now()
+	user, err := h.users.ScheduleDeletion(c.Request.Context(), id, now, now.AddDate(0, 0, h.graceDays))
+	if err != nil {
+		appErr := repositoryError(err, "user", "failed to schedule account deletion")
+		_ = c.Error(appErr)
+		return
+	}
+
+	recordAudit(c, h.audit, models.AuditAccountDeletionSchedule, user.ID, before, user)
+	log.Printf("[Handler] ✓ Scheduled deletion of account %s for %s", user.ID, user.DeletionScheduledAt.Format(time.RFC3339))
+	c.JSON(http.StatusAccepted, user)
+}
+
+// CancelAccountDeletion handles POST /api/v1/me/deletion/cancel
+func (h *AccountHandler) CancelAccountDeletion(c *gin.Context) {
+	id, appErr := accountOwner(c)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	before, err := h.users.Get(c.Request.Context(), id)
+	if err != nil {
+		appErr := repositoryError(err, "user", "failed to get account")
+		_ = c.Error(appErr)
+		return
+	}
+	if before.DeletionScheduledAt == nil {
+		appErr := errors.NewConflictError("account deletion is not scheduled")
+		_ = c.Error(appErr)
+		return
+	}
+	user, err := h.users.CancelDeletion(c.Request.Context(), id)
+	if err != nil {
+		appErr := repositoryError(err, "user", "failed to cancel account deletion")
+		_ = c.Error(appErr)
+		return
+	}
+
+	recordAudit(c, h.audit, models.AuditAccountDeletionCancel, user.ID, before, user)
+	log.Printf("[Handler] ✓ Cancelled deletion of account %s", user.ID)
+	c.JSON(http.StatusOK, user)
+}
+
+// accountOwner returns the signed-in user managing their account. Exporting and deleting an
+// account take a session, so a leaked API key cannot be used to take the data or destroy it.
+func accountOwner(c *gin.Context) (string, *errors.AppError) {
+	if _, ok := c.Get(middleware.APIKeyKey); ok {
+		return "", errors.NewForbiddenError("API keys cannot manage the account; sign in instead")
+	}
+	user := userID(c)
+	if user == nil {
+		return "", errors.NewUnauthorizedError("accounts require a signed-in user")
+	}
+	return *user, nil
+}
+
+// writeExportArchive writes the manifest and each section to a zip archive
+func writeExportArchive(w io.Writer, manifest exportManifest, sections []models.AccountExportSection) error {
+	zw := zip.NewWriter(w)
+	f, err := zw.Create("manifest.json")
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(manifest); err != nil {
+		return err
+	}
+	for _, s := range sections {
+		f, err := zw.Create(s.Name + ".json")
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(s.Rows); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// Admin user list limits
+const (
+	defaultUserLimit = 50
+	maxUserLimit     = 200
+)
+
+// AdminHandler lets admins look after the users of the API
+type AdminHandler struct {
+	users *repository.UserRepository
+	audit *repository.AuditRepository
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(users *repository.UserRepository, audit *repository.AuditRepository) *AdminHandler {
+	return &AdminHandler{users: users, audit: audit}
+}
+
+// DisableUserRequest represents the request body for disabling a user, e.g.
+// {"reason": "Scraping the options endpoints"}
+type DisableUserRequest struct {
+	Reason *string `json:"reason" binding:"omitempty,max=500"`
+}
+
+// SetUserTierRequest represents the request body for moving a user to a usage tier, e.g.
+// {"tier": "pro"}
+type SetUserTierRequest struct {
+	Tier string `json:"tier" binding:"required,oneof=free pro unlimited"`
+}
+
+// ListUsers handles GET /api/v1/admin/users?email=&status=&limit=&offset=, most recently
+// seen first. status is active or disabled.
+func (h *AdminHandler) ListUsers(c *gin.Context) {
+	filter := models.UserFilter{Email: strings.TrimSpace(c.Query("email"))}
+	switch c.Query("status") {
+	case "":
+	case "active":
+		disabled := false
+		filter.Disabled = &disabled
+	case "disabled":
+		disabled := true
+		filter.Disabled = &disabled
+	default:
+		appErr := errors.NewBadRequestError("status must be active or disabled", nil)
+		_ = c.Error(appErr)
+		return
+	}
+	var appErr *errors.AppError
+	if filter.Limit, appErr = queryInt(c, "limit", defaultUserLimit); appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	if filter.Limit < 1 || filter.Limit > maxUserLimit {
+		appErr := errors.NewBadRequestError("limit must be between 1 and 200", nil)
+		_ = c.Error(appErr)
+		return
+	}
+	if filter.Offset, appErr = queryInt(c, "offset", 0); appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	if filter.Offset < 0 {
+		appErr := errors.NewBadRequestError("offset must not be negative", nil)
+		_ = c.Error(appErr)
+		return
+	}
+
+	users, err := h.users.List(c.Request.Context(), filter)
+	if err != nil {
+		appErr := repositoryError(err, "user", "failed to list users")
+		_ = c.Error(appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": users})
+}
+
+// GetUser handles GET /api/v1/admin/users/:id
+func (h *AdminHandler) GetUser(c *gin.Context) {
+	id, appErr := paramUUID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	user, err := h.users.Get(c.Request.Context(), id)
+	if err != nil {
+		appErr := repositoryError(err, "user", "failed to get user")
+		_ = c.Error(appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// GetUserUsage handles GET /api/v1/admin/users/:id/usage
+func (h *AdminHandler) GetUserUsage(c *gin.Context) {
+	id, appErr := paramUUID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	usage, err := h.users.Usage(c.Request.Context(), id)
+	if err != nil {
+		appErr := repositoryError(err, "user", "failed to get user usage")
+		_ = c.Error(appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, usage)
+}
+
+// DisableUser handles POST /api/v1/admin/users/:id/disable. The user's requests are rejected
+// with 403 from then on, including those made with their API keys.
+func (h *AdminHandler) DisableUser(c *gin.Context) {
+	id, appErr := paramUUID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	var req DisableUserRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			appErr := errors.NewBadRequestError("invalid request body", err)
+			_ = c.Error(appErr)
+			return
+		}
+	}
+	if self := userID(c); self != nil && strings.EqualFold(*self, id) {
+		appErr := errors.NewBadRequestError("admins cannot disable themselves", nil)
+		_ = c.Error(appErr)
+		return
+	}
+
+	before, err := h.users.Get(c.Request.Context(), id)
+	if err != nil {
+		appErr := repositoryError(err, "user", "failed to get user")
+		_ = c.Error(appErr)
+		return
+	}
+	user, err := h.users.SetDisabled(c.Request.Context(), id, true, optionalText(req.Reason))
+	if err != nil {
+		appErr := repositoryError(err, "user", "failed to disable user")
+		_ = c.Error(appErr)
+		return
+	}
+
+	recordAudit(c, h.audit, models.AuditUserDisable, user.ID, before, user)
+	log.Printf("[Handler] ✓ Disabled user %s", user.ID)
+	c.JSON(http.StatusOK, user)
+}
+
+// EnableUser handles POST /api/v1/admin/users/:id/enable
+func (h *AdminHandler) EnableUser(c *gin.Context) {
+	id, appErr := paramUUID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	before, err := h.users.Get(c.Request.Context(), id)
+	if err != nil {
+		appErr := repositoryError(err, "user", "failed to get user")
+		_ = c.Error(appErr)
+		return
+	}
+	user, err := h.users.SetDisabled(c.Request.Context(), id, false, nil)
+	if err != nil {
+		appErr := repositoryError(err, "user", "failed to enable user")
+		_ = c.Error(appErr)
+		return
+	}
+
+	recordAudit(c, h.audit, models.AuditUserEnable, user.ID, before, user)
+	log.Printf("[Handler] ✓ Enabled user %s", user.ID)
+	c.JSON(http.StatusOK, user)
+}
+
+// ResetUserQuota handles POST /api/v1/admin/users/:id/quota/reset, forgiving the usage the
+// user has counted against their quota so far
+func (h *AdminHandler) ResetUserQuota(c *gin.Context) {
+	id, appErr := paramUUID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	before, err := h.users.Get(c.Request.Context(), id)
+	if err != nil {
+		appErr := repositoryError(err, "user", "failed to get user")
+		_ = c.Error(appErr)
+		return
+	}
+	user, err := h.users.ResetQuota(c.Request.Context(), id, time.Now())
+	if err != nil {
+		appErr := repositoryError(err, "user", "failed to reset user quota")
+		_ = c.Error(appErr)
+		return
+	}
+
+	recordAudit(c, h.audit, models.AuditUserQuotaReset, user.ID, before, user)
+	log.Printf("[Handler] ✓ Reset quota of user %s", user.ID)
+	c.JSON(http.StatusOK, user)
+}
+
+// SetUserTier handles PUT /api/v1/admin/users/:id/tier, changing the monthly usage quota the
+// user is held to from the current period on
+func (h *AdminHandler) SetUserTier(c *gin.Context) {
+	id, appErr := paramUUID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	var req SetUserTierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := errors.NewBadRequestError("invalid request body", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	before, err := h.users.Get(c.Request.Context(), id)
+	if err != nil {
+		appErr := repositoryError(err, "user", "failed to get user")
+		_ = c.Error(appErr)
+		return
+	}
+	user, err := h.users.SetTier(c.Request.Context(), id, req.Tier)
+	if err != nil {
+		appErr := repositoryError(err, "user", "failed to set user tier")
+		_ = c.Error(appErr)
+		return
+	}
+
+	recordAudit(c, h.audit, models.AuditUserTier, user.ID, before, user)
+	log.Printf("[Handler] ✓ Moved user %s to the %s tier", user.ID, user.Tier)
+	c.JSON(http.StatusOK, user)
+}
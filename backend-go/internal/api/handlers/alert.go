@@ -0,0 +1,428 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/alerts"
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// AlertHandler manages market alert rules on stocks and option contracts and the earnings
+// alert settings
+type AlertHandler struct {
+	alerts   repository.AlertRepo
+	earnings *alerts.EarningsSync
+	settings *repository.SettingsRepository
+	audit    *repository.AuditRepository
+}
+
+// NewAlertHandler creates a new alert handler
+func NewAlertHandler(alertRepo repository.AlertRepo, earnings *alerts.EarningsSync, settings *repository.SettingsRepository, audit *repository.AuditRepository) *AlertHandler {
+	return &AlertHandler{
+		alerts:   alertRepo,
+		earnings: earnings,
+		settings: settings,
+		audit:    audit,
+	}
+}
+
+// CreateAlertRequest represents the request body for creating a market alert, e.g.
+// {"ticker": "AAPL", "metric": "price", "operator": "crosses_above", "threshold": 200} or
+// {"ticker": "AAPL", "metric": "change_percent", "operator": ">", "threshold": 3, "mode": "recurring"},
+// or a composite rule given as a condition instead of metric, operator and threshold, e.g.
+// {"ticker": "O:AAPL261120P00200000", "condition": {"all": [{"metric": "iv_rank", "operator": ">", "threshold": 50},
+// {"metric": "strike_distance_percent", "operator": "<", "threshold": 2}]}}.
+// The mode and cooldown default to the user's alert settings (one-shot, and an hour for
+// recurring alerts, unless changed); the cooldown only applies to recurring alerts.
+type CreateAlertRequest struct {
+	Ticker          string                 `json:"ticker" binding:"required"`
+	Metric          string                 `json:"metric"`
+	Operator        string                 `json:"operator" binding:"omitempty,oneof=> >= < <= crosses_above crosses_below"`
+	Threshold       *float64               `json:"threshold"`
+	Condition       *models.AlertCondition `json:"condition"`
+	Note            *string                `json:"note" binding:"omitempty,max=500"`
+	Mode            string                 `json:"mode" binding:"omitempty,oneof=one_shot recurring"`
+	CooldownSeconds *int                   `json:"cooldown_seconds" binding:"omitempty,gte=0,lte=604800"`
+}
+
+// UpdateAlertRequest represents the request body for changing a market alert.
+// Omitted fields are left unchanged; status "armed" re-arms a triggered alert. A composite
+// alert's rule is changed by replacing its condition.
+type UpdateAlertRequest struct {
+	Metric          *string                `json:"metric"`
+	Operator        *string                `json:"operator" binding:"omitempty,oneof=> >= < <= crosses_above crosses_below"`
+	Threshold       *float64               `json:"threshold"`
+	Condition       *models.AlertCondition `json:"condition"`
+	Note            *string                `json:"note" binding:"omitempty,max=500"`
+	Mode            *string                `json:"mode" binding:"omitempty,oneof=one_shot recurring"`
+	CooldownSeconds *int                   `json:"cooldown_seconds" binding:"omitempty,gte=0,lte=604800"`
+	Status          *string                `json:"status" binding:"omitempty,oneof=armed disabled"`
+}
+
+// EarningsSettingsRequest represents the request body for the earnings alert settings, e.g.
+// {"days_before": 3, "portfolios": true, "watchlists": false}. Omitted flags default to true.
+type EarningsSettingsRequest struct {
+	Enabled    *bool `json:"enabled"`
+	DaysBefore *int  `json:"days_before" binding:"required,gte=0,lte=30"`
+	Portfolios *bool `json:"portfolios"`
+	Watchlists *bool `json:"watchlists"`
+}
+
+// ListAlerts handles GET /api/v1/alerts?status=
+func (h *AlertHandler) ListAlerts(c *gin.Context) {
+	status := c.Query("status")
+	switch status {
+	case "", models.AlertArmed, models.AlertTriggered, models.AlertCooldown, models.AlertSnoozed, models.AlertDisabled:
+	default:
+		appErr := errors.NewBadRequestError("status must be armed, triggered, cooldown, snoozed or disabled", nil)
+		_ = c.Error(appErr)
+		return
+	}
+
+	alerts, err := h.alerts.List(c.Request.Context(), userID(c), status)
+	if err != nil {
+		appErr := repositoryError(err, "alert", "failed to list alerts")
+		_ = c.Error(appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": alerts})
+}
+
+// CreateAlert handles POST /api/v1/alerts
+func (h *AlertHandler) CreateAlert(c *gin.Context) {
+	var req CreateAlertRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := errors.NewBadRequestError("invalid request body", err)
+		_ = c.Error(appErr)
+		return
+	}
+	ticker, ok := normalizeTicker(req.Ticker)
+	if !ok {
+		appErr := errors.NewBadRequestError(fmt.Sprintf("invalid ticker %q", req.Ticker), nil)
+		_ = c.Error(appErr)
+		return
+	}
+	alert := &models.Alert{
+		UserID:    userID(c),
+		Ticker:    ticker,
+		AssetType: models.TickerAssetType(ticker),
+		Note:      req.Note,
+		Mode:      req.Mode,
+	}
+	if req.Condition != nil {
+		if req.Metric != "" || req.Operator != "" || req.Threshold != nil {
+			appErr := errors.NewBadRequestError("give either a condition or metric, operator and threshold", nil)
+			_ = c.Error(appErr)
+			return
+		}
+		if appErr := setCondition(alert, req.Condition); appErr != nil {
+			_ = c.Error(appErr)
+			return
+		}
+	} else {
+		if req.Metric == "" || req.Operator == "" || req.Threshold == nil {
+			appErr := errors.NewBadRequestError("metric, operator and threshold are required without a condition", nil)
+			_ = c.Error(appErr)
+			return
+		}
+		if err := alerts.ValidateMetric(req.Metric, ticker); err != nil {
+			appErr := errors.NewBadRequestError(err.Error(), err)
+			_ = c.Error(appErr)
+			return
+		}
+		alert.Metric = req.Metric
+		alert.Operator = req.Operator
+		alert.Threshold = *req.Threshold
+	}
+	defaults, err := h.settings.Get(c.Request.Context(), alert.UserID)
+	if err != nil {
+		appErr := repositoryError(err, "settings", "failed to get alert defaults")
+		_ = c.Error(appErr)
+		return
+	}
+	if alert.Mode == "" {
+		alert.Mode = defaults.AlertMode
+	}
+	switch {
+	case alert.Mode == models.AlertOneShot && req.CooldownSeconds != nil:
+		appErr := errors.NewBadRequestError("cooldown_seconds only applies to recurring alerts", nil)
+		_ = c.Error(appErr)
+		return
+	case alert.Mode == models.AlertRecurring && req.CooldownSeconds != nil:
+		alert.CooldownSeconds = *req.CooldownSeconds
+	case alert.Mode == models.AlertRecurring:
+		alert.CooldownSeconds = defaults.AlertCooldownSeconds
+	}
+	if err := h.alerts.Create(c.Request.Context(), alert); err != nil {
+		appErr := repositoryError(err, "alert", "failed to create alert")
+		_ = c.Error(appErr)
+		return
+	}
+
+	recordAudit(c, h.audit, models.AuditAlertCreate, alert.ID, nil, alert)
+	if alert.Condition != nil {
+		log.Printf("[Handler] ✓ Created %s alert %d: %s %s", alert.Mode, alert.ID, alert.Ticker, alert.Condition)
+	} else {
+		log.Printf("[Handler] ✓ Created %s alert %d: %s %s %s %g", alert.Mode, alert.ID, alert.Ticker, alert.Metric, alert.Operator, alert.Threshold)
+	}
+	c.JSON(http.StatusCreated, alert)
+}
+
+// GetAlert handles GET /api/v1/alerts/:id
+func (h *AlertHandler) GetAlert(c *gin.Context) {
+	alert, appErr := h.loadAlert(c)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, alert)
+}
+
+// UpdateAlert handles PATCH /api/v1/alerts/:id
+func (h *AlertHandler) UpdateAlert(c *gin.Context) {
+	var req UpdateAlertRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := errors.NewBadRequestError("invalid request body", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	alert, appErr := h.loadAlert(c)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	before := *alert
+	if alert.Source != models.AlertSourceUser && (req.Metric != nil || req.Operator != nil || req.Threshold != nil ||
+		req.Condition != nil || req.Mode != nil || req.CooldownSeconds != nil) {
+		appErr := errors.NewBadRequestError("the rule of an earnings alert is set by the earnings alert settings; only note and status can change", nil)
+		_ = c.Error(appErr)
+		return
+	}
+	if (alert.Condition != nil || req.Condition != nil) && (req.Metric != nil || req.Operator != nil || req.Threshold != nil) {
+		appErr := errors.NewBadRequestError("the rule of a composite alert is changed by replacing its condition", nil)
+		_ = c.Error(appErr)
+		return
+	}
+	if req.Condition != nil {
+		if appErr := setCondition(alert, req.Condition); appErr != nil {
+			_ = c.Error(appErr)
+			return
+		}
+	}
+
+	if req.Metric != nil {
+		if err := alerts.ValidateMetric(*req.Metric, alert.Ticker); err != nil {
+			appErr := errors.NewBadRequestError(err.Error(), err)
+			_ = c.Error(appErr)
+			return
+		}
+		alert.Metric = *req.Metric
+	}
+	if req.Operator != nil {
+		alert.Operator = *req.Operator
+	}
+	if req.Threshold != nil {
+		alert.Threshold = *req.Threshold
+	}
+	if req.Note != nil {
+		alert.Note = req.Note
+	}
+	if req.Mode != nil {
+		if *req.Mode == models.AlertRecurring && alert.Mode != models.AlertRecurring && req.CooldownSeconds == nil {
+			defaults, err := h.settings.Get(c.Request.Context(), alert.UserID)
+			if err != nil {
+				appErr := repositoryError(err, "settings", "failed to get alert defaults")
+				_ = c.Error(appErr)
+				return
+			}
+			alert.CooldownSeconds = defaults.AlertCooldownSeconds
+		}
+		alert.Mode = *req.Mode
+	}
+	if req.CooldownSeconds != nil {
+		if alert.Mode != models.AlertRecurring {
+			appErr := errors.NewBadRequestError("cooldown_seconds only applies to recurring alerts", nil)
+			_ = c.Error(appErr)
+			return
+		}
+		alert.CooldownSeconds = *req.CooldownSeconds
+	}
+	if req.Status != nil {
+		alert.Status = *req.Status
+	}
+
+	if err := h.alerts.Update(c.Request.Context(), alert); err != nil {
+		appErr := repositoryError(err, "alert", "failed to update alert")
+		_ = c.Error(appErr)
+		return
+	}
+
+	recordAudit(c, h.audit, models.AuditAlertUpdate, alert.ID, before, alert)
+	log.Printf("[Handler] ✓ Updated alert %d (%s)", alert.ID, alert.Status)
+	c.JSON(http.StatusOK, alert)
+}
+
+// DeleteAlert handles DELETE /api/v1/alerts/:id
+func (h *AlertHandler) DeleteAlert(c *gin.Context) {
+	alert, appErr := h.loadAlert(c)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	if alert.Source != models.AlertSourceUser {
+		appErr := errors.NewBadRequestError("earnings alerts are removed through the earnings alert settings; disable it instead", nil)
+		_ = c.Error(appErr)
+		return
+	}
+	id := alert.ID
+
+	if err := h.alerts.Delete(c.Request.Context(), userID(c), id); err != nil {
+		appErr := repositoryError(err, "alert", "failed to delete alert")
+		_ = c.Error(appErr)
+		return
+	}
+
+	recordAudit(c, h.audit, models.AuditAlertDelete, id, alert, nil)
+	log.Printf("[Handler] ✓ Deleted alert %d", id)
+	c.Status(http.StatusNoContent)
+}
+
+// ListDeletedAlerts handles GET /api/v1/alerts/deleted
+func (h *AlertHandler) ListDeletedAlerts(c *gin.Context) {
+	alerts, err := h.alerts.ListDeleted(c.Request.Context(), userID(c))
+	if err != nil {
+		appErr := repositoryError(err, "alert", "failed to list deleted alerts")
+		_ = c.Error(appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": alerts})
+}
+
+// RestoreAlert handles POST /api/v1/alerts/deleted/:id/restore
+func (h *AlertHandler) RestoreAlert(c *gin.Context) {
+	id, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	alert, err := h.alerts.Restore(c.Request.Context(), userID(c), id)
+	if err != nil {
+		appErr := repositoryError(err, "alert", "failed to restore alert")
+		_ = c.Error(appErr)
+		return
+	}
+
+	recordAudit(c, h.audit, models.AuditAlertRestore, id, nil, alert)
+	log.Printf("[Handler] ✓ Restored alert %d", id)
+	c.JSON(http.StatusOK, alert)
+}
+
+// PurgeAlert handles DELETE /api/v1/alerts/deleted/:id, removing a deleted alert and its
+// triggers for good
+func (h *AlertHandler) PurgeAlert(c *gin.Context) {
+	id, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	if err := h.alerts.Purge(c.Request.Context(), userID(c), id); err != nil {
+		appErr := repositoryError(err, "alert", "failed to purge alert")
+		_ = c.Error(appErr)
+		return
+	}
+
+	recordAudit(c, h.audit, models.AuditAlertPurge, id, nil, nil)
+	log.Printf("[Handler] ✓ Purged alert %d", id)
+	c.Status(http.StatusNoContent)
+}
+
+// GetEarningsSettings handles GET /api/v1/alerts/earnings
+func (h *AlertHandler) GetEarningsSettings(c *gin.Context) {
+	settings, err := h.alerts.GetEarningsSettings(c.Request.Context(), userID(c))
+	if err != nil {
+		appErr := repositoryError(err, "earnings alert settings", "failed to get earnings alert settings")
+		_ = c.Error(appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// SetEarningsSettings handles PUT /api/v1/alerts/earnings, replacing the settings and
+// syncing the earnings alerts right away
+func (h *AlertHandler) SetEarningsSettings(c *gin.Context) {
+	var req EarningsSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := errors.NewBadRequestError("invalid request body", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	settings := &models.EarningsAlertSettings{
+		UserID:     userID(c),
+		Enabled:    req.Enabled == nil || *req.Enabled,
+		DaysBefore: *req.DaysBefore,
+		Portfolios: req.Portfolios == nil || *req.Portfolios,
+		Watchlists: req.Watchlists == nil || *req.Watchlists,
+	}
+	if settings.Enabled && !settings.Portfolios && !settings.Watchlists {
+		appErr := errors.NewBadRequestError("enabled earnings alerts need portfolios or watchlists", nil)
+		_ = c.Error(appErr)
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.alerts.SaveEarningsSettings(ctx, settings); err != nil {
+		appErr := repositoryError(err, "earnings alert settings", "failed to save earnings alert settings")
+		_ = c.Error(appErr)
+		return
+	}
+	if err := h.earnings.Sync(ctx, settings); err != nil {
+		log.Printf("[Handler] ✗ Failed to sync earnings alerts: %v", err)
+		appErr := errors.NewInternalError("failed to sync earnings alerts", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	log.Printf("[Handler] ✓ Set earnings alerts %d days ahead on %d tickers", settings.DaysBefore, len(settings.Tickers))
+	c.JSON(http.StatusOK, settings)
+}
+
+// setCondition validates a composite rule for the alert's ticker and sets it, with its first
+// comparison as the alert's metric, operator and threshold
+func setCondition(alert *models.Alert, condition *models.AlertCondition) *errors.AppError {
+	if err := alerts.ValidateCondition(condition, alert.Ticker); err != nil {
+		return errors.NewBadRequestError(err.Error(), err)
+	}
+	first := condition.Comparisons()[0]
+	alert.Condition = condition
+	alert.Metric = first.Metric
+	alert.Operator = first.Operator
+	alert.Threshold = *first.Threshold
+	return nil
+}
+
+// loadAlert resolves the :id path parameter to an alert
+func (h *AlertHandler) loadAlert(c *gin.Context) (*models.Alert, *errors.AppError) {
+	id, appErr := paramID(c, "id")
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	alert, err := h.alerts.Get(c.Request.Context(), userID(c), id)
+	if err != nil {
+		return nil, repositoryError(err, "alert", "failed to get alert")
+	}
+	return alert, nil
+}
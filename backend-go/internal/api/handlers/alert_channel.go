@@ -0,0 +1,294 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/aaronbengochea/periscope/backend-go/internal/webhook"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// AlertChannelHandler manages where a user's alert triggers are delivered
+type AlertChannelHandler struct {
+	channels     *repository.AlertChannelRepository
+	watchlists   repository.WatchlistRepo
+	emailEnabled bool // whether an SMTP server is configured for email channels
+}
+
+// NewAlertChannelHandler creates a new alert channel handler
+func NewAlertChannelHandler(channels *repository.AlertChannelRepository, watchlists repository.WatchlistRepo, emailEnabled bool) *AlertChannelHandler {
+	return &AlertChannelHandler{
+		channels:     channels,
+		watchlists:   watchlists,
+		emailEnabled: emailEnabled,
+	}
+}
+
+// chatWebhookPrefixes are the URL prefixes of the incoming webhooks chat channels post to
+var chatWebhookPrefixes = map[string][]string{
+	models.ChannelSlack:   {"https://hooks.slack.com/"},
+	models.ChannelDiscord: {"https://discord.com/api/webhooks/", "https://discordapp.com/api/webhooks/"},
+}
+
+// CreateAlertChannelRequest represents the request body for adding an alert channel, e.g.
+// {"kind": "webhook", "name": "Ops hook", "url": "https://example.com/hooks/alerts"},
+// {"kind": "email", "address": "me@example.com"} or
+// {"kind": "slack", "url": "https://hooks.slack.com/services/...", "watchlist_id": 3}
+type CreateAlertChannelRequest struct {
+	Kind        string  `json:"kind" binding:"required,oneof=webhook email slack discord"`
+	Name        *string `json:"name" binding:"omitempty,max=100"`
+	URL         *string `json:"url" binding:"omitempty,http_url,max=2000"`
+	Address     *string `json:"address" binding:"omitempty,email,max=320"`
+	WatchlistID *int64  `json:"watchlist_id" binding:"omitempty,gt=0"`
+}
+
+// UpdateAlertChannelRequest represents the request body for editing an alert channel.
+// Omitted fields are left unchanged; a watchlist_id of 0 delivers all of the user's alerts
+// again, and reactivating a disabled channel clears its failure count.
+type UpdateAlertChannelRequest struct {
+	Name        *string `json:"name" binding:"omitempty,max=100"`
+	URL         *string `json:"url" binding:"omitempty,http_url,max=2000"`
+	Address     *string `json:"address" binding:"omitempty,email,max=320"`
+	WatchlistID *int64  `json:"watchlist_id" binding:"omitempty,gte=0"`
+	Active      *bool   `json:"active"`
+}
+
+// ListChannels handles GET /api/v1/alerts/channels
+func (h *AlertChannelHandler) ListChannels(c *gin.Context) {
+	channels, err := h.channels.List(c.Request.Context(), userID(c))
+	if err != nil {
+		appErr := repositoryError(err, "alert channel", "failed to list alert channels")
+		_ = c.Error(appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": channels})
+}
+
+// CreateChannel handles POST /api/v1/alerts/channels. The response of a webhook channel
+// includes its signing secret, which is not shown again.
+func (h *AlertChannelHandler) CreateChannel(c *gin.Context) {
+	var req CreateAlertChannelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := errors.NewBadRequestError("invalid request body", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	ch := &models.AlertChannel{
+		UserID:      userID(c),
+		Kind:        req.Kind,
+		Name:        optionalText(req.Name),
+		URL:         req.URL,
+		Address:     req.Address,
+		WatchlistID: req.WatchlistID,
+		Active:      true,
+	}
+	if ch.Kind == models.ChannelEmail && !h.emailEnabled {
+		appErr := errors.NewBadRequestError("email channels are not available: no mail server is configured", nil)
+		_ = c.Error(appErr)
+		return
+	}
+	if appErr := h.checkTarget(c, ch); appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	if ch.Kind == models.ChannelWebhook {
+		secret, err := webhook.NewSecret()
+		if err != nil {
+			appErr := errors.NewInternalError("failed to create alert channel", err)
+			_ = c.Error(appErr)
+			return
+		}
+		ch.Secret = secret
+	}
+
+	if err := h.channels.Create(c.Request.Context(), ch); err != nil {
+		appErr := repositoryError(err, "alert channel", "failed to create alert channel")
+		_ = c.Error(appErr)
+		return
+	}
+
+	log.Printf("[Handler] ✓ Created %s alert channel %d", ch.Kind, ch.ID)
+	c.JSON(http.StatusCreated, ch)
+}
+
+// UpdateChannel handles PATCH /api/v1/alerts/channels/:id
+func (h *AlertChannelHandler) UpdateChannel(c *gin.Context) {
+	var req UpdateAlertChannelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := errors.NewBadRequestError("invalid request body", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	ch, appErr := h.loadChannel(c)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	if req.Name != nil {
+		ch.Name = optionalText(req.Name)
+	}
+	if req.URL != nil {
+		ch.URL = req.URL
+	}
+	if req.Address != nil {
+		ch.Address = req.Address
+	}
+	if req.WatchlistID != nil {
+		ch.WatchlistID = req.WatchlistID
+		if *req.WatchlistID == 0 {
+			ch.WatchlistID = nil
+		}
+	}
+	if req.Active != nil {
+		ch.Active = *req.Active
+	}
+	if appErr := h.checkTarget(c, ch); appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	if err := h.channels.Update(c.Request.Context(), ch); err != nil {
+		appErr := repositoryError(err, "alert channel", "failed to update alert channel")
+		_ = c.Error(appErr)
+		return
+	}
+
+	log.Printf("[Handler] ✓ Updated alert channel %d", ch.ID)
+	c.JSON(http.StatusOK, ch)
+}
+
+// DeleteChannel handles DELETE /api/v1/alerts/channels/:id
+func (h *AlertChannelHandler) DeleteChannel(c *gin.Context) {
+	id, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	if err := h.channels.Delete(c.Request.Context(), userID(c), id); err != nil {
+		appErr := repositoryError(err, "alert channel", "failed to delete alert channel")
+		_ = c.Error(appErr)
+		return
+	}
+
+	log.Printf("[Handler] ✓ Deleted alert channel %d", id)
+	c.Status(http.StatusNoContent)
+}
+
+// ListDeliveries handles GET /api/v1/alerts/channels/:id/deliveries?limit=
+func (h *AlertChannelHandler) ListDeliveries(c *gin.Context) {
+	limit, appErr := queryInt(c, "limit", defaultDeliveryLimit)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	if limit < 1 || limit > maxDeliveryLimit {
+		appErr := errors.NewBadRequestError("limit must be between 1 and 200", nil)
+		_ = c.Error(appErr)
+		return
+	}
+
+	ch, appErr := h.loadChannel(c)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	deliveries, err := h.channels.ListDeliveries(c.Request.Context(), ch.ID, limit)
+	if err != nil {
+		appErr := repositoryError(err, "alert delivery", "failed to list alert deliveries")
+		_ = c.Error(appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": deliveries})
+}
+
+// TestChannel handles POST /api/v1/alerts/channels/:id/test, queueing a ping that is sent
+// with the next delivery run
+func (h *AlertChannelHandler) TestChannel(c *gin.Context) {
+	ch, appErr := h.loadChannel(c)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	if !ch.Active {
+		appErr := errors.NewConflictError("alert channel is disabled; reactivate it first")
+		_ = c.Error(appErr)
+		return
+	}
+
+	d, err := h.channels.Enqueue(c.Request.Context(), ch, models.EventPing, gin.H{
+		"channel_id": ch.ID,
+		"sent_at":    time.Now().UTC(),
+	})
+	if err != nil {
+		appErr := repositoryError(err, "alert delivery", "failed to queue test notification")
+		_ = c.Error(appErr)
+		return
+	}
+
+	log.Printf("[Handler] ✓ Queued ping %d for alert channel %d", d.ID, ch.ID)
+	c.JSON(http.StatusAccepted, d)
+}
+
+// checkTarget checks that a channel has the destination its kind sends to, an address for
+// email and a public URL otherwise, and that the watchlist it is scoped to exists
+func (h *AlertChannelHandler) checkTarget(c *gin.Context, ch *models.AlertChannel) *errors.AppError {
+	if ch.Kind == models.ChannelEmail {
+		if ch.Address == nil || ch.URL != nil {
+			return errors.NewBadRequestError("email channels take an address and no url", nil)
+		}
+	} else if ch.URL == nil || ch.Address != nil {
+		return errors.NewBadRequestError(ch.Kind+" channels take a url and no address", nil)
+	}
+
+	if prefixes, ok := chatWebhookPrefixes[ch.Kind]; ok {
+		valid := false
+		for _, prefix := range prefixes {
+			valid = valid || strings.HasPrefix(*ch.URL, prefix)
+		}
+		if !valid {
+			return errors.NewBadRequestError("url must be a "+ch.Kind+" webhook URL starting with "+prefixes[0], nil)
+		}
+	}
+	if ch.URL != nil {
+		if appErr := publicURL(c.Request.Context(), *ch.URL); appErr != nil {
+			return appErr
+		}
+	}
+
+	if ch.WatchlistID != nil {
+		if _, err := h.watchlists.Get(c.Request.Context(), userID(c), *ch.WatchlistID); err != nil {
+			if stderrors.Is(err, repository.ErrNotFound) {
+				return errors.NewBadRequestError("watchlist_id must be an existing watchlist", err)
+			}
+			return repositoryError(err, "watchlist", "failed to get watchlist")
+		}
+	}
+	return nil
+}
+
+// loadChannel resolves the :id path parameter to an alert channel
+func (h *AlertChannelHandler) loadChannel(c *gin.Context) (*models.AlertChannel, *errors.AppError) {
+	id, appErr := paramID(c, "id")
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	ch, err := h.channels.Get(c.Request.Context(), userID(c), id)
+	if err != nil {
+		return nil, repositoryError(err, "alert channel", "failed to get alert channel")
+	}
+	return ch, nil
+}
@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// Alert history limits
+const (
+	defaultTriggerLimit = 50
+	maxTriggerLimit     = 200
+	maxSnooze           = 30 * 24 * time.Hour
+)
+
+// AcknowledgeTriggersRequest represents the request body for acknowledging triggers in bulk:
+// every unacknowledged trigger, or only those of one alert
+type AcknowledgeTriggersRequest struct {
+	AlertID *int64 `json:"alert_id" binding:"omitempty,gt=0"`
+}
+
+// SnoozeAlertRequest represents the request body for snoozing an alert, for a number of
+// minutes, e.g. {"minutes": 60}, or until a time, e.g. {"until": "2026-10-19T13:30:00Z"}
+type SnoozeAlertRequest struct {
+	Minutes *int       `json:"minutes" binding:"omitempty,gt=0"`
+	Until   *time.Time `json:"until"`
+}
+
+// ListTriggers handles GET /api/v1/alerts/triggers?alert_id=&unacknowledged=&before=&limit=,
+// the user's alert history with the market data each trigger was evaluated against
+func (h *AlertHandler) ListTriggers(c *gin.Context) {
+	alertID, appErr := queryInt(c, "alert_id", 0)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	h.listTriggers(c, int64(alertID))
+}
+
+// ListAlertTriggers handles GET /api/v1/alerts/:id/triggers?unacknowledged=&before=&limit=
+func (h *AlertHandler) ListAlertTriggers(c *gin.Context) {
+	alert, appErr := h.loadAlert(c)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	h.listTriggers(c, alert.ID)
+}
+
+// listTriggers lists triggers with the paging and acknowledgment filters of the query
+func (h *AlertHandler) listTriggers(c *gin.Context, alertID int64) {
+	filter := models.AlertTriggerFilter{
+		AlertID:        alertID,
+		Unacknowledged: c.Query("unacknowledged") == "true",
+	}
+	before, appErr := queryInt(c, "before", 0)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	filter.Before = int64(before)
+	if filter.Limit, appErr = queryInt(c, "limit", defaultTriggerLimit); appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	if filter.Limit < 1 || filter.Limit > maxTriggerLimit {
+		appErr := errors.NewBadRequestError("limit must be between 1 and 200", nil)
+		_ = c.Error(appErr)
+		return
+	}
+
+	triggers, err := h.alerts.ListTriggers(c.Request.Context(), userID(c), filter)
+	if err != nil {
+		appErr := repositoryError(err, "alert trigger", "failed to list alert triggers")
+		_ = c.Error(appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": triggers})
+}
+
+// AcknowledgeTrigger handles POST /api/v1/alerts/triggers/:id/ack
+func (h *AlertHandler) AcknowledgeTrigger(c *gin.Context) {
+	id, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	trigger, err := h.alerts.Acknowledge(c.Request.Context(), userID(c), id)
+	if err != nil {
+		appErr := repositoryError(err, "alert trigger", "failed to acknowledge alert trigger")
+		_ = c.Error(appErr)
+		return
+	}
+
+	log.Printf("[Handler] ✓ Acknowledged alert trigger %d", trigger.ID)
+	c.JSON(http.StatusOK, trigger)
+}
+
+// AcknowledgeTriggers handles POST /api/v1/alerts/triggers/ack
+func (h *AlertHandler) AcknowledgeTriggers(c *gin.Context) {
+	var req AcknowledgeTriggersRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			appErr := errors.NewBadRequestError("invalid request body", err)
+			_ = c.Error(appErr)
+			return
+		}
+	}
+	var alertID int64
+	if req.AlertID != nil {
+		alertID = *req.AlertID
+	}
+
+	count, err := h.alerts.AcknowledgeAll(c.Request.Context(), userID(c), alertID)
+	if err != nil {
+		appErr := repositoryError(err, "alert trigger", "failed to acknowledge alert triggers")
+		_ = c.Error(appErr)
+		return
+	}
+
+	log.Printf("[Handler] ✓ Acknowledged %d alert triggers", count)
+	c.JSON(http.StatusOK, gin.H{"acknowledged": count})
+}
+
+// SnoozeAlert handles POST /api/v1/alerts/:id/snooze. The alert is held until the time
+// given, up to 30 days ahead, then re-arms; a triggered or cooling down alert re-arms then
+// too.
+func (h *AlertHandler) SnoozeAlert(c *gin.Context) {
+	var req SnoozeAlertRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := errors.NewBadRequestError("invalid request body", err)
+		_ = c.Error(appErr)
+		return
+	}
+	if (req.Minutes == nil) == (req.Until == nil) {
+		appErr := errors.NewBadRequestError("give either minutes or until", nil)
+		_ = c.Error(appErr)
+		return
+	}
+	now := time.Now()
+	var until time.Time
+	if req.Minutes != nil {
+		until = now.Add(time.Duration(*req.Minutes) * time.Minute)
+	} else {
+		until = *req.Until
+	}
+	if !until.After(now) || until.Sub(now) > maxSnooze {
+		appErr := errors.NewBadRequestError("an alert can be snoozed for up to 30 days", nil)
+		_ = c.Error(appErr)
+		return
+	}
+
+	alert, appErr := h.loadAlert(c)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	if alert.Status == models.AlertDisabled {
+		appErr := errors.NewConflictError("alert is disabled; re-arm it instead")
+		_ = c.Error(appErr)
+		return
+	}
+
+	if err := h.alerts.Snooze(c.Request.Context(), alert, until); err != nil {
+		appErr := repositoryError(err, "alert", "failed to snooze alert")
+		_ = c.Error(appErr)
+		return
+	}
+
+	log.Printf("[Handler] ✓ Snoozed alert %d until %s", alert.ID, until.Format(time.RFC3339))
+	c.JSON(http.StatusOK, alert)
+}
+
+// RearmAlert handles POST /api/v1/alerts/:id/rearm, arming a triggered, cooling down,
+// snoozed or disabled alert right away
+func (h *AlertHandler) RearmAlert(c *gin.Context) {
+	alert, appErr := h.loadAlert(c)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	alert.Status = models.AlertArmed
+	if err := h.alerts.Update(c.Request.Context(), alert); err != nil {
+		appErr := repositoryError(err, "alert", "failed to re-arm alert")
+		_ = c.Error(appErr)
+		return
+	}
+
+	log.Printf("[Handler] ✓ Re-armed alert %d", alert.ID)
+	c.JSON(http.StatusOK, alert)
+}
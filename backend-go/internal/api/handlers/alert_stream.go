@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/alerts"
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// Alert stream settings
+const (
+	streamHeartbeat    = 25 * time.Second // comment sent to keep idle connections open through proxies
+	streamWriteTimeout = 10 * time.Second // per write, replacing the server's write timeout
+	streamRetryMillis  = 5000             // reconnect delay suggested to clients
+	maxStreamReplay    = 100              // missed triggers sent on reconnect
+)
+
+// AlertStreamHandler pushes alert triggers to the frontend as server-sent events
+type AlertStreamHandler struct {
+	alertRepo *repository.AlertRepository
+	stream    *alerts.TriggerStream
+}
+
+// NewAlertStreamHandler creates a new alert stream handler
+func NewAlertStreamHandler(alertRepo *repository.AlertRepository, stream *alerts.TriggerStream) *AlertStreamHandler {
+	return &AlertStreamHandler{
+		alertRepo: alertRepo,
+		stream:    stream,
+	}
+}
+
+// StreamTriggers handles GET /api/v1/alerts/stream, an event stream of the user's alert
+// triggers as they are recorded. Each event has the trigger ID as its ID and the alert and
+// trigger as its data; a client reconnecting with Last-Event-ID (or ?after=) first receives
+// the triggers it missed.
+func (h *AlertStreamHandler) StreamTriggers(c *gin.Context) {
+	after, appErr := queryInt(c, "after", 0)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	lastID := int64(after)
+	if header := c.GetHeader("Last-Event-ID"); header != "" {
+		id, err := strconv.ParseInt(header, 10, 64)
+		if err != nil || id < 0 {
+			appErr := errors.NewBadRequestError("Last-Event-ID must be a trigger ID", err)
+			_ = c.Error(appErr)
+			return
+		}
+		lastID = id
+	}
+
+	ctx := c.Request.Context()
+	user := userID(c)
+	events, unsubscribe := h.stream.Subscribe(user)
+	defer unsubscribe()
+
+	// Subscribed first, so a trigger recorded while catching up is not missed
+	var missed []models.AlertNotification
+	if lastID > 0 {
+		var err error
+		missed, err = h.alertRepo.ListNotificationsSince(ctx, user, lastID, maxStreamReplay)
+		if err != nil {
+			appErr := repositoryError(err, "alert trigger", "failed to list missed alert triggers")
+			_ = c.Error(appErr)
+			return
+		}
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	rc := http.NewResponseController(c.Writer)
+	write := func(frame string) bool {
+		_ = rc.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+		if _, err := c.Writer.WriteString(frame); err != nil {
+			return false
+		}
+		c.Writer.Flush()
+		return true
+	}
+	send := func(n models.AlertNotification) bool {
+		if n.Trigger.ID <= lastID {
+			return true
+		}
+		data, err := json.Marshal(n)
+		if err != nil {
+			return true
+		}
+		lastID = n.Trigger.ID
+		return write(fmt.Sprintf("id: %d\nevent: %s\ndata: %s\n\n", n.Trigger.ID, models.EventAlertTriggered, data))
+	}
+
+	log.Printf("[Handler] ✓ Streaming alert triggers after %d (%d missed)", lastID, len(missed))
+	if !write(fmt.Sprintf("retry: %d\n\n", streamRetryMillis)) {
+		return
+	}
+	for _, n := range missed {
+		if !send(n) {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case n, ok := <-events:
+			if !ok {
+				// Dropped for falling behind; the client reconnects from the last ID it got
+				return
+			}
+			if !send(n) {
+				return
+			}
+		case <-heartbeat.C:
+			if !write(": keep-alive\n\n") {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/rebalance"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/aaronbengochea/periscope/backend-go/internal/services"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// defaultRebalanceThreshold is the drift in percent points that triggers rebalance trades
+const defaultRebalanceThreshold = 5
+
+// AllocationHandler manages target allocations and suggests rebalancing trades
+type AllocationHandler struct {
+	portfolios  repository.PortfolioRepo
+	allocations *repository.AllocationRepository
+	valuation   *services.ValuationService
+}
+
+// NewAllocationHandler creates a new allocation handler
+func NewAllocationHandler(portfolios repository.PortfolioRepo, allocations *repository.AllocationRepository, valuation *services.ValuationService) *AllocationHandler {
+	return &AllocationHandler{
+		portfolios:  portfolios,
+		allocations: allocations,
+		valuation:   valuation,
+	}
+}
+
+// TargetRequest is one allocation target: either a ticker, or a sector with its tickers
+type TargetRequest struct {
+	Ticker  string   `json:"ticker" binding:"omitempty,max=20"`
+	Sector  string   `json:"sector" binding:"omitempty,max=100"`
+	Tickers []string `json:"tickers" binding:"omitempty,max=50,dive,min=1,max=20"`
+	Percent float64  `json:"percent" binding:"gt=0,lte=100"`
+}
+
+// SetTargetsRequest represents the request body for replacing a portfolio's targets, e.g.
+// {"targets": [{"ticker": "AAPL", "percent": 20}, {"sector": "Energy", "tickers": ["XOM", "CVX"], "percent": 10}]}
+type SetTargetsRequest struct {
+	Targets []TargetRequest `json:"targets" binding:"max=100,dive"`
+}
+
+// GetTargets handles GET /api/v1/portfolio/:id/targets
+func (h *AllocationHandler) GetTargets(c *gin.Context) {
+	portfolioID, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	targets, err := h.allocations.List(c.Request.Context(), portfolioID)
+	if err != nil {
+		appErr := repositoryError(err, "allocation target", "failed to list allocation targets")
+		_ = c.Error(appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": targets})
+}
+
+// SetTargets handles PUT /api/v1/portfolio/:id/targets, replacing every target
+func (h *AllocationHandler) SetTargets(c *gin.Context) {
+	portfolioID, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	var req SetTargetsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := errors.NewBadRequestError("invalid request body", err)
+		_ = c.Error(appErr)
+		return
+	}
+	targets, appErr := allocationTargets(req.Targets)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	if _, err := h.portfolios.Get(c.Request.Context(), userID(c), portfolioID); err != nil {
+		appErr := repositoryError(err, "portfolio", "failed to get portfolio")
+		_ = c.Error(appErr)
+		return
+	}
+	if err := h.allocations.Replace(c.Request.Context(), portfolioID, targets); err != nil {
+		appErr := repositoryError(err, "allocation target", "failed to save allocation targets")
+		_ = c.Error(appErr)
+		return
+	}
+
+	log.Printf("[Handler] ✓ Set %d allocation targets for portfolio %d", len(targets), portfolioID)
+	c.JSON(http.StatusOK, gin.H{"results": targets})
+}
+
+// GetRebalance handles GET /api/v1/portfolio/:id/rebalance?threshold=5&base=
+func (h *AllocationHandler) GetRebalance(c *gin.Context) {
+	portfolioID, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	threshold, appErr := queryFloat(c, "threshold", defaultRebalanceThreshold)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	if threshold < 0 || threshold > 100 {
+		appErr := errors.NewBadRequestError("threshold must be between 0 and 100", nil)
+		_ = c.Error(appErr)
+		return
+	}
+	base, appErr := queryOptionalFloat(c, "base")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	if base != nil && *base <= 0 {
+		appErr := errors.NewBadRequestError("base must be positive", nil)
+		_ = c.Error(appErr)
+		return
+	}
+
+	if _, err := h.portfolios.Get(c.Request.Context(), userID(c), portfolioID); err != nil {
+		appErr := repositoryError(err, "portfolio", "failed to get portfolio")
+		_ = c.Error(appErr)
+		return
+	}
+	targets, err := h.allocations.List(c.Request.Context(), portfolioID)
+	if err != nil {
+		appErr := repositoryError(err, "allocation target", "failed to list allocation targets")
+		_ = c.Error(appErr)
+		return
+	}
+	if len(targets) == 0 {
+		appErr := errors.NewBadRequestError("portfolio has no allocation targets", nil)
+		_ = c.Error(appErr)
+		return
+	}
+
+	plan, err := h.valuation.Rebalance(c.Request.Context(), portfolioID, targets, base, threshold)
+	if stderrors.Is(err, rebalance.ErrNoBase) {
+		appErr := errors.NewBadRequestError("portfolio has no net long exposure to allocate; pass base", err)
+		_ = c.Error(appErr)
+		return
+	}
+	if err != nil {
+		log.Printf("[Handler] ✗ Failed to plan rebalance for portfolio %d: %v", portfolioID, err)
+		appErr := errors.NewInternalError("failed to plan rebalance", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, plan)
+}
+
+// allocationTargets validates and normalizes requested targets: tickers are uppercased,
+// every underlying belongs to one target, and the percentages add up to at most 100
+func allocationTargets(reqs []TargetRequest) ([]models.AllocationTarget, *errors.AppError) {
+	targets := make([]models.AllocationTarget, 0, len(reqs))
+	names := make(map[string]bool)
+	owner := make(map[string]string)
+	var total float64
+
+	for _, req := range reqs {
+		ticker := strings.ToUpper(strings.TrimSpace(req.Ticker))
+		sector := strings.TrimSpace(req.Sector)
+
+		var target models.AllocationTarget
+		switch {
+		case ticker != "" && sector != "":
+			return nil, errors.NewBadRequestError("a target has either a ticker or a sector", nil)
+		case ticker != "":
+			if len(req.Tickers) > 0 {
+				return nil, errors.NewBadRequestError("tickers are only allowed on sector targets", nil)
+			}
+			target = models.AllocationTarget{Kind: models.TargetTicker, Name: ticker, Tickers: []string{ticker}}
+		case sector != "":
+			tickers := []string{}
+			for _, t := range req.Tickers {
+				if t = strings.ToUpper(strings.TrimSpace(t)); t != "" {
+					tickers = append(tickers, t)
+				}
+			}
+			slices.Sort(tickers)
+			tickers = slices.Compact(tickers)
+			if len(tickers) == 0 {
+				return nil, errors.NewBadRequestError(fmt.Sprintf("sector %q needs at least one ticker", sector), nil)
+			}
+			target = models.AllocationTarget{Kind: models.TargetSector, Name: sector, Tickers: tickers}
+		default:
+			return nil, errors.NewBadRequestError("a target needs a ticker or a sector", nil)
+		}
+		target.Percent = req.Percent
+
+		key := strings.ToLower(target.Name)
+		if names[key] {
+			return nil, errors.NewBadRequestError(fmt.Sprintf("target %q is listed twice", target.Name), nil)
+		}
+		names[key] = true
+		for _, t := range target.Tickers {
+			if other, ok := owner[t]; ok {
+				return nil, errors.NewBadRequestError(fmt.Sprintf("%s is in both %q and %q", t, other, target.Name), nil)
+			}
+			owner[t] = target.Name
+		}
+
+		total += target.Percent
+		targets = append(targets, target)
+	}
+
+	if total > 100+1e-9 {
+		return nil, errors.NewBadRequestError(fmt.Sprintf("target percentages add up to %.2f, more than 100", total), nil)
+	}
+	return targets, nil
+}
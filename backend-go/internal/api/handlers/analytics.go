@@ -0,0 +1,399 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/analytics"
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/aaronbengochea/periscope/backend-go/internal/services"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/massive"
+	"github.com/gin-gonic/gin"
+)
+
+// AnalyticsHandler handles chain analytics requests
+type AnalyticsHandler struct {
+	massiveClient *massive.Client
+	chainService  *services.ChainService
+	ivHistory     *repository.IVHistoryRepository
+	riskFreeRate  float64
+}
+
+// NewAnalyticsHandler creates a new analytics handler
+func NewAnalyticsHandler(massiveClient *massive.Client, chainService *services.ChainService, ivHistory *repository.IVHistoryRepository, riskFreeRate float64) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		massiveClient: massiveClient,
+		chainService:  chainService,
+		ivHistory:     ivHistory,
+		riskFreeRate:  riskFreeRate,
+	}
+}
+
+// GetEarningsCrush handles GET /api/v1/analytics/:ticker/earnings-crush
+// Compares front-expiration IV with back-month IV around the next earnings release
+func (h *AnalyticsHandler) GetEarningsCrush(c *gin.Context) {
+	ticker := strings.ToUpper(c.Param("ticker"))
+	if ticker == "" {
+		appErr := errors.NewBadRequestError("ticker is required", nil)
+		_ = c.Error(appErr)
+		return
+	}
+
+	// Only consider releases within this many days
+	withinDays, appErr := queryInt(c, "within_days", 45)
+	if appErr == nil && withinDays <= 0 {
+		appErr = errors.NewBadRequestError("within_days must be positive", nil)
+	}
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	now := time.Now()
+	today := analytics.MarketDate(now)
+
+	events, err := h.massiveClient.GetUpcomingEarnings(c.Request.Context(), ticker, today)
+	if err != nil {
+		log.Printf("[Handler] ✗ Failed to fetch earnings calendar: %v", err)
+		appErr := errors.NewInternalError("failed to fetch earnings calendar", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	var event *massive.EarningsEvent
+	var earningsDate time.Time
+	for i := range events {
+		date, err := analytics.ParseDate(events[i].Date)
+		if err != nil || date.Before(today) {
+			continue
+		}
+		event = &events[i]
+		earningsDate = date
+		break
+	}
+	if event == nil || earningsDate.Sub(today) > time.Duration(withinDays)*24*time.Hour {
+		appErr := errors.NewNotFoundError("no upcoming earnings found for " + ticker)
+		_ = c.Error(appErr)
+		return
+	}
+
+	log.Printf("[Handler] Analyzing earnings IV crush for %s (earnings %s)", ticker, event.Date)
+
+	snapshot, err := h.chainService.GetSnapshot(c.Request.Context(), ticker, nil)
+	if err != nil {
+		log.Printf("[Handler] ✗ Failed to fetch chain snapshot: %v", err)
+		appErr := errors.NewInternalError("failed to fetch options chain", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	result, err := analytics.AnalyzeEarningsCrush(snapshot.Contracts, snapshot.Spot, earningsDate, event.BeforeOpen(), now)
+	if err != nil {
+		appErr := errors.NewNotFoundError("insufficient chain data: " + err.Error())
+		_ = c.Error(appErr)
+		return
+	}
+	result.Ticker = ticker
+
+	log.Printf("[Handler] ✓ Earnings crush for %s: front IV %.4f, back IV %.4f", ticker, result.FrontIV, result.BackIV)
+
+	c.JSON(http.StatusOK, gin.H{
+		"earnings": event,
+		"analysis": result,
+	})
+}
+
+// GetMispricing handles GET /api/v1/analytics/:ticker/mispricing
+// Ranks contracts by the gap between market mid-price and Black-Scholes value
+func (h *AnalyticsHandler) GetMispricing(c *gin.Context) {
+	ticker := strings.ToUpper(c.Param("ticker"))
+	if ticker == "" {
+		appErr := errors.NewBadRequestError("ticker is required", nil)
+		_ = c.Error(appErr)
+		return
+	}
+
+	params := analytics.MispricingParams{
+		VolSource: analytics.VolSource(c.DefaultQuery("vol_source", string(analytics.VolSourceATMIV))),
+		Rate:      h.riskFreeRate,
+		RankBy:    c.DefaultQuery("rank_by", "pct"),
+	}
+
+	var appErr *errors.AppError
+	if params.Vol, appErr = queryFloat(c, "vol", 0); appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	if params.DivYield, appErr = queryFloat(c, "dividend_yield", 0); appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	if params.MinPrice, appErr = queryFloat(c, "min_price", 0.05); appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	if params.Limit, appErr = queryInt(c, "limit", 25); appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	hvDays, appErr := queryInt(c, "hv_days", 30)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	switch params.VolSource {
+	case analytics.VolSourceATMIV:
+	case analytics.VolSourceFixed:
+		if params.Vol <= 0 {
+			appErr := errors.NewBadRequestError("vol is required when vol_source=fixed", nil)
+			_ = c.Error(appErr)
+			return
+		}
+	case analytics.VolSourceHistorical:
+		if hvDays < 5 {
+			appErr := errors.NewBadRequestError("hv_days must be at least 5", nil)
+			_ = c.Error(appErr)
+			return
+		}
+		hv, err := h.historicalVol(c, ticker, hvDays)
+		if err != nil {
+			log.Printf("[Handler] ✗ Failed to compute historical volatility: %v", err)
+			appErr := errors.NewInternalError("failed to compute historical volatility", err)
+			_ = c.Error(appErr)
+			return
+		}
+		params.Vol = hv
+	default:
+		appErr := errors.NewBadRequestError("vol_source must be one of atm_iv, historical, fixed", nil)
+		_ = c.Error(appErr)
+		return
+	}
+
+	chainParams := &massive.OptionsChainParams{}
+	if expiration := c.Query("expiration_date"); expiration != "" {
+		chainParams.ExpirationDate = &expiration
+	}
+	if contractType := c.Query("contract_type"); contractType != "" {
+		chainParams.ContractType = &contractType
+	}
+
+	log.Printf("[Handler] Scanning %s chain for mispricing (vol_source=%s)", ticker, params.VolSource)
+
+	snapshot, err := h.chainService.GetSnapshot(c.Request.Context(), ticker, chainParams)
+	if err != nil {
+		log.Printf("[Handler] ✗ Failed to fetch chain snapshot: %v", err)
+		appErr := errors.NewInternalError("failed to fetch options chain", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	results := analytics.ScanMispricing(snapshot.Contracts, snapshot.Spot, params, time.Now())
+
+	log.Printf("[Handler] ✓ Mispricing scan returned %d of %d contracts", len(results), len(snapshot.Contracts))
+
+	response := gin.H{
+		"ticker":           ticker,
+		"underlying_price": snapshot.Spot,
+		"vol_source":       params.VolSource,
+		"risk_free_rate":   params.Rate,
+		"results":          results,
+	}
+	if params.VolSource != analytics.VolSourceATMIV {
+		response["vol"] = params.Vol
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// historicalVol computes annualized close-to-close volatility over the last n trading days
+func (h *AnalyticsHandler) historicalVol(c *gin.Context, ticker string, n int) (float64, error) {
+	to := time.Now()
+	// Pad the calendar window so weekends and holidays still leave n+1 closes
+	from := to.AddDate(0, 0, -(n*7/5 + 10))
+
+	bars, err := h.massiveClient.GetDailyBars(c.Request.Context(), ticker, from, to)
+	if err != nil {
+		return 0, err
+	}
+	if len(bars) > n+1 {
+		bars = bars[len(bars)-(n+1):]
+	}
+
+	closes := make([]float64, len(bars))
+	for i, b := range bars {
+		closes[i] = b.Close
+	}
+
+	hv := analytics.HistoricalVolatility(closes)
+	if hv == nil {
+		return 0, fmt.Errorf("not enough price history for %s", ticker)
+	}
+	return *hv, nil
+}
+
+// GetStraddle handles GET /api/v1/analytics/:ticker/straddle
+// Prices the ATM straddle and a strangle of configurable width for one expiration
+func (h *AnalyticsHandler) GetStraddle(c *gin.Context) {
+	ticker := strings.ToUpper(c.Param("ticker"))
+	if ticker == "" {
+		appErr := errors.NewBadRequestError("ticker is required", nil)
+		_ = c.Error(appErr)
+		return
+	}
+
+	// Strangle width as % of spot, or in dollars when width is given
+	widthPct, appErr := queryFloat(c, "width_pct", 5)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	width, appErr := queryFloat(c, "width", 0)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	if widthPct <= 0 || width < 0 {
+		appErr := errors.NewBadRequestError("strangle width must be positive", nil)
+		_ = c.Error(appErr)
+		return
+	}
+
+	// Without an expiration the full chain is fetched and the nearest expiration is used
+	chainParams := &massive.OptionsChainParams{}
+	expiration := c.Query("expiration_date")
+	if expiration != "" {
+		if _, err := analytics.ParseDate(expiration); err != nil {
+			appErr := errors.NewBadRequestError("invalid expiration_date parameter", err)
+			_ = c.Error(appErr)
+			return
+		}
+		chainParams.ExpirationDate = &expiration
+	}
+
+	snapshot, err := h.chainService.GetSnapshot(c.Request.Context(), ticker, chainParams)
+	if err != nil {
+		log.Printf("[Handler] ✗ Failed to fetch chain snapshot: %v", err)
+		appErr := errors.NewInternalError("failed to fetch options chain", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	now := time.Now()
+	if expiration == "" {
+		for _, exp := range analytics.Expirations(snapshot.Contracts) {
+			if dte, err := analytics.DaysToExpiration(exp, now); err == nil && dte >= 0 {
+				expiration = exp
+				break
+			}
+		}
+	}
+
+	contracts := analytics.ForExpiration(snapshot.Contracts, expiration)
+	if len(contracts) == 0 {
+		appErr := errors.NewNotFoundError("no contracts found for expiration " + expiration)
+		_ = c.Error(appErr)
+		return
+	}
+
+	if width == 0 {
+		width = snapshot.Spot * widthPct / 100.0
+	}
+
+	straddle := analytics.ATMStraddle(contracts, snapshot.Spot)
+	strangle := analytics.Strangle(contracts, snapshot.Spot, width)
+	if straddle == nil && strangle == nil {
+		appErr := errors.NewNotFoundError("no priced call/put legs for expiration " + expiration)
+		_ = c.Error(appErr)
+		return
+	}
+
+	dte, _ := analytics.DaysToExpiration(expiration, now)
+	log.Printf("[Handler] ✓ Priced straddle/strangle for %s %s", ticker, expiration)
+
+	c.JSON(http.StatusOK, gin.H{
+		"ticker":           ticker,
+		"underlying_price": snapshot.Spot,
+		"expiration_date":  expiration,
+		"dte":              dte,
+		"strangle_width":   width,
+		"straddle":         straddle,
+		"strangle":         strangle,
+	})
+}
+
+// ivRankMaturityDays is the constant maturity used for IV rank readings
+const ivRankMaturityDays = 30
+
+// GetIVRank handles GET /api/v1/analytics/:ticker/iv-rank
+// Records today's 30-day ATM IV and ranks it against the stored 30-day and 52-week history
+func (h *AnalyticsHandler) GetIVRank(c *gin.Context) {
+	ticker := strings.ToUpper(c.Param("ticker"))
+	if ticker == "" {
+		appErr := errors.NewBadRequestError("ticker is required", nil)
+		_ = c.Error(appErr)
+		return
+	}
+
+	snapshot, err := h.chainService.GetSnapshot(c.Request.Context(), ticker, nil)
+	if err != nil {
+		log.Printf("[Handler] ✗ Failed to fetch chain snapshot: %v", err)
+		appErr := errors.NewInternalError("failed to fetch options chain", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	now := time.Now()
+	current := analytics.ConstantMaturityIV(snapshot.Contracts, snapshot.Spot, ivRankMaturityDays, now)
+	if current == nil {
+		appErr := errors.NewNotFoundError("no ATM implied volatility available for " + ticker)
+		_ = c.Error(appErr)
+		return
+	}
+
+	today := analytics.MarketDate(now)
+	spot := snapshot.Spot
+	if err := h.ivHistory.Upsert(c.Request.Context(), &models.IVObservation{
+		Ticker:          ticker,
+		ObservedOn:      today,
+		ATMIV:           *current,
+		UnderlyingPrice: &spot,
+	}); err != nil {
+		// Ranking still works from existing history
+		log.Printf("[Handler] ⚠ Failed to record IV observation: %v", err)
+	}
+
+	history, err := h.ivHistory.ListSince(c.Request.Context(), ticker, today.AddDate(0, 0, -52*7))
+	if err != nil {
+		log.Printf("[Handler] ✗ Failed to load IV history: %v", err)
+		appErr := errors.NewInternalError("failed to load IV history", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	monthStart := today.AddDate(0, 0, -30)
+	var year, month []float64
+	for _, obs := range history {
+		year = append(year, obs.ATMIV)
+		if !obs.ObservedOn.Before(monthStart) {
+			month = append(month, obs.ATMIV)
+		}
+	}
+
+	log.Printf("[Handler] ✓ IV rank for %s computed from %d observations", ticker, len(year))
+
+	c.JSON(http.StatusOK, gin.H{
+		"ticker":           ticker,
+		"underlying_price": snapshot.Spot,
+		"current_iv":       *current,
+		"maturity_days":    ivRankMaturityDays,
+		"windows": []models.IVRankWindow{
+			analytics.IVRank("30d", month, *current),
+			analytics.IVRank("52w", year, *current),
+		},
+	})
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/api/middleware"
+	"github.com/aaronbengochea/periscope/backend-go/internal/auth"
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHandler issues and revokes the API keys scripts and bots call the API with
+type APIKeyHandler struct {
+	keys  *repository.APIKeyRepository
+	audit *repository.AuditRepository
+}
+
+// NewAPIKeyHandler creates a new API key handler
+func NewAPIKeyHandler(keys *repository.APIKeyRepository, audit *repository.AuditRepository) *APIKeyHandler {
+	return &APIKeyHandler{keys: keys, audit: audit}
+}
+
+// CreateAPIKeyRequest represents the request body for issuing an API key, e.g.
+// {"name": "Dashboard", "scopes": ["market:read", "portfolio:read"], "expires_in_days": 90}.
+// Keys without an expiry last until revoked, and keys without scopes get every scope.
+type CreateAPIKeyRequest struct {
+	Name          string   `json:"name" binding:"required,max=100"`
+	Scopes        []string `json:"scopes" binding:"omitempty,dive,oneof=market:read portfolio:read portfolio:write watchlist:read watchlist:write alert:read alert:write account:read account:write"`
+	ExpiresInDays *int     `json:"expires_in_days" binding:"omitempty,gte=1,lte=365"`
+}
+
+// ListAPIKeys handles GET /api/v1/me/api-keys
+func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	user, appErr := keyOwner(c)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	keys, err := h.keys.List(c.Request.Context(), user)
+	if err != nil {
+		appErr := repositoryError(err, "API key", "failed to list API keys")
+		_ = c.Error(appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": keys})
+}
+
+// CreateAPIKey handles POST /api/v1/me/api-keys. The response includes the key, which is
+// not shown again.
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	var req CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := errors.NewBadRequestError("invalid request body", err)
+		_ = c.Error(appErr)
+		return
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		appErr := errors.NewBadRequestError("name must not be blank", nil)
+		_ = c.Error(appErr)
+		return
+	}
+	if req.Scopes != nil && len(req.Scopes) == 0 {
+		appErr := errors.NewBadRequestError("scopes must not be empty; omit them for every scope", nil)
+		_ = c.Error(appErr)
+		return
+	}
+	user, appErr := keyOwner(c)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	key, prefix, hash, err := auth.NewAPIKey()
+	if err != nil {
+		appErr := errors.NewInternalError("failed to create API key", err)
+		_ = c.Error(appErr)
+		return
+	}
+	apiKey := &models.APIKey{
+		UserID: user,
+		Name:   name,
+		Prefix: prefix,
+		Scopes: apiKeyScopes(req.Scopes),
+	}
+	if req.ExpiresInDays != nil {
+		expiresAt := time.Now().AddDate(0, 0, *req.ExpiresInDays)
+		apiKey.ExpiresAt = &expiresAt
+	}
+
+	if err := h.keys.Create(c.Request.Context(), apiKey, hash); err != nil {
+		if stderrors.Is(err, repository.ErrTooManyAPIKeys) {
+			appErr := errors.NewConflictError("API key limit reached; revoke an unused key first")
+			_ = c.Error(appErr)
+			return
+		}
+		appErr := repositoryError(err, "API key", "failed to create API key")
+		_ = c.Error(appErr)
+		return
+	}
+	recordAudit(c, h.audit, models.AuditAPIKeyIssue, apiKey.ID, nil, apiKey)
+	apiKey.Key = key
+
+	log.Printf("[Handler] ✓ Issued API key %d (%s) with %v", apiKey.ID, apiKey.Prefix, apiKey.Scopes)
+	c.JSON(http.StatusCreated, apiKey)
+}
+
+// RevokeAPIKey handles DELETE /api/v1/me/api-keys/:id. Requests with the key fail from then
+// on; the key stays listed as revoked.
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	id, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	user, appErr := keyOwner(c)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	key, err := h.keys.Revoke(c.Request.Context(), user, id)
+	if err != nil {
+		appErr := repositoryError(err, "API key", "failed to revoke API key")
+		_ = c.Error(appErr)
+		return
+	}
+
+	recordAudit(c, h.audit, models.AuditAPIKeyRevoke, key.ID, nil, key)
+	log.Printf("[Handler] ✓ Revoked API key %d (%s)", key.ID, key.Prefix)
+	c.Status(http.StatusNoContent)
+}
+
+// apiKeyScopes returns the scopes asked for without duplicates, in the order of
+// models.AllScopes, or every scope when none are asked for
+func apiKeyScopes(requested []string) []string {
+	if requested == nil {
+		return models.AllScopes
+	}
+	scopes := []string{}
+	for _, scope := range models.AllScopes {
+		if slices.Contains(requested, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+// keyOwner returns the user managing their API keys. Keys belong to a signed-in user and are
+// managed from a session, so a leaked key cannot be used to issue more.
+func keyOwner(c *gin.Context) (string, *errors.AppError) {
+	if _, ok := c.Get(middleware.APIKeyKey); ok {
+		return "", errors.NewForbiddenError("API keys cannot manage API keys; sign in instead")
+	}
+	user := userID(c)
+	if user == nil {
+		return "", errors.NewUnauthorizedError("API keys require a signed-in user")
+	}
+	return *user, nil
+}
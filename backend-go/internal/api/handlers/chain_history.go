@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"strings"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/analytics"
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/protobuf/proto"
+)
+
+// ChainHistoryHandler serves options chains as they were captured by the snapshot job
+type ChainHistoryHandler struct {
+	snapshots *repository.OptionsSnapshotRepository
+}
+
+// NewChainHistoryHandler creates a new chain history handler
+func NewChainHistoryHandler(snapshots *repository.OptionsSnapshotRepository) *ChainHistoryHandler {
+	return &ChainHistoryHandler{snapshots: snapshots}
+}
+
+// GetChainHistory handles GET /api/v1/options/:ticker/history?date=2026-03-20&expiration=2026-04-17&format=json|parquet
+//
+// date is a trading date, for the last capture taken that day or before, or an RFC 3339
+// timestamp, for the last capture at or before that moment. Without it the latest capture
+// is returned. expiration limits the chain to one expiration. format=parquet downloads the
+// capture as a Parquet file, one row per contract.
+func (h *ChainHistoryHandler) GetChainHistory(c *gin.Context) {
+	ticker := strings.ToUpper(c.Param("ticker"))
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "parquet" {
+		appErr := errors.NewBadRequestError("format must be json or parquet", nil)
+		_ = c.Error(appErr)
+		return
+	}
+
+	var before time.Time
+	if raw := c.Query("date"); raw != "" {
+		if date, err := analytics.ParseDate(raw); err == nil {
+			before = analytics.MarketDayEnd(date)
+		} else if at, err := time.Parse(time.RFC3339, raw); err == nil {
+			before = at.Add(time.Nanosecond)
+		} else {
+			appErr := errors.NewBadRequestError("invalid date parameter, expected YYYY-MM-DD or an RFC 3339 timestamp", err)
+			_ = c.Error(appErr)
+			return
+		}
+	}
+
+	var expiration *string
+	if raw := c.Query("expiration"); raw != "" {
+		if _, err := analytics.ParseDate(raw); err != nil {
+			appErr := errors.NewBadRequestError("invalid expiration parameter, expected YYYY-MM-DD", err)
+			_ = c.Error(appErr)
+			return
+		}
+		expiration = &raw
+	}
+
+	var snapshot *models.OptionsSnapshot
+	var err error
+	if before.IsZero() {
+		snapshot, err = h.snapshots.Latest(c.Request.Context(), ticker)
+	} else {
+		snapshot, err = h.snapshots.LatestBefore(c.Request.Context(), ticker, before)
+	}
+	if err != nil {
+		appErr := repositoryError(err, "chain snapshot", "failed to get chain snapshot")
+		_ = c.Error(appErr)
+		return
+	}
+
+	contracts, err := h.snapshots.ListContracts(c.Request.Context(), snapshot, expiration)
+	if err != nil {
+		appErr := repositoryError(err, "chain snapshot", "failed to load chain snapshot")
+		_ = c.Error(appErr)
+		return
+	}
+
+	results := make([]models.OptionContract, len(contracts))
+	for i := range contracts {
+		results[i] = contracts[i].OptionContract()
+	}
+	if format == "parquet" {
+		writeChainParquet(c, results, &snapshot.Spot, snapshot.CapturedAt)
+		return
+	}
+	response := models.HistoricalChainResponse{
+		Ticker:          snapshot.Ticker,
+		CapturedAt:      snapshot.CapturedAt,
+		UnderlyingPrice: snapshot.Spot,
+		Results:         results,
+	}
+	toProto := func() proto.Message {
+		return chainMessage(snapshot.Ticker, &snapshot.Spot, results, snapshot.CapturedAt, "")
+	}
+	if writeBinary(c, &response, toProto, true) {
+		return
+	}
+	writeTaggedJSON(c, response, "")
+}
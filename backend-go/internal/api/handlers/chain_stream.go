@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/api/middleware"
+	"github.com/aaronbengochea/periscope/backend-go/internal/services"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// ChainStreamHandler pushes chain refreshes as server-sent events, for clients that cannot
+// use the WebSocket stream
+type ChainStreamHandler struct {
+	stream *services.ChainStream
+}
+
+// NewChainStreamHandler creates a new chain stream handler
+func NewChainStreamHandler(stream *services.ChainStream) *ChainStreamHandler {
+	return &ChainStreamHandler{
+		stream: stream,
+	}
+}
+
+// StreamChain handles GET /api/v1/options/:ticker/stream?expiration_date=&contract_type=,
+// an event stream of the chain as it is re-fetched. The first event, "snapshot", holds every
+// contract; each "changes" event after it holds the contracts whose quotes or greeks changed
+// and the tickers of contracts that left the chain, along with the underlying price. An
+// "error" event reports a failed refresh; the stream carries on with the next one.
+func (h *ChainStreamHandler) StreamChain(c *gin.Context) {
+	ticker := strings.ToUpper(c.Param("ticker"))
+	expiration := c.Query("expiration_date")
+	if expiration != "" {
+		if _, err := time.Parse("2006-01-02", expiration); err != nil {
+			appErr := errors.NewBadRequestError("expiration_date must be YYYY-MM-DD", err)
+			_ = c.Error(appErr)
+			return
+		}
+	}
+	contractType := strings.ToLower(c.Query("contract_type"))
+	if contractType != "" && contractType != "call" && contractType != "put" {
+		appErr := errors.NewBadRequestError("contract_type must be call or put", nil)
+		_ = c.Error(appErr)
+		return
+	}
+
+	ctx := c.Request.Context()
+	updates, unsubscribe := h.stream.Subscribe(ticker, expiration, contractType)
+	defer unsubscribe()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	rc := http.NewResponseController(c.Writer)
+	write := func(frame string) bool {
+		_ = rc.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+		if _, err := c.Writer.WriteString(frame); err != nil {
+			return false
+		}
+		c.Writer.Flush()
+		return true
+	}
+
+	log.Printf("[Handler] ✓ Streaming %s chain", ticker)
+	if !write(fmt.Sprintf("retry: %d\n\n", streamRetryMillis)) {
+		return
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case u, ok := <-updates:
+			if !ok {
+				// Dropped for falling behind; the client reconnects for a fresh snapshot
+				return
+			}
+			event, body := "changes", any(u)
+			switch {
+			case u.Err != nil:
+				event, body = "error", middleware.NewErrorResponse(c, errors.NewInternalError("failed to fetch options chain", u.Err))
+			case u.Snapshot:
+				event = "snapshot"
+			}
+			data, err := json.Marshal(body)
+			if err != nil {
+				continue
+			}
+			if !write(fmt.Sprintf("event: %s\ndata: %s\n\n", event, data)) {
+				return
+			}
+		case <-heartbeat.C:
+			if !write(": keep-alive\n\n") {
+				return
+			}
+		}
+	}
+}
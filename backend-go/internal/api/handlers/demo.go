@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/api/middleware"
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/services"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// DemoHandler serves the anonymous product demo: options chains for a few tickers from
+// delayed or sample snapshots, without calling Massive
+type DemoHandler struct {
+	demo         *services.DemoService
+	riskFreeRate float64
+}
+
+// NewDemoHandler creates a new demo handler
+func NewDemoHandler(demo *services.DemoService, riskFreeRate float64) *DemoHandler {
+	return &DemoHandler{demo: demo, riskFreeRate: riskFreeRate}
+}
+
+// DemoTicker describes a ticker the demo serves
+type DemoTicker struct {
+	Ticker string    `json:"ticker"`
+	Source string    `json:"source"` // sample or delayed
+	AsOf   time.Time `json:"as_of"`
+	Spot   float64   `json:"spot"`
+}
+
+// ListTickers handles GET /api/v1/demo/tickers
+func (h *DemoHandler) ListTickers(c *gin.Context) {
+	now := time.Now()
+	tickers := []DemoTicker{}
+	for _, ticker := range h.demo.Tickers() {
+		snapshot, err := h.demo.Snapshot(ticker, now)
+		if err != nil {
+			log.Printf("[Handler] ⚠ Demo snapshot for %s unavailable: %v", ticker, err)
+			continue
+		}
+		tickers = append(tickers, DemoTicker{Ticker: ticker, Source: snapshot.Source, AsOf: snapshot.AsOf, Spot: snapshot.Spot})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": tickers})
+}
+
+// GetOptionsChain handles GET /api/v1/demo/options/:ticker, taking the same parameters as
+// GET /api/v1/options/:ticker except limit. The X-Demo-Data header says whether the chain is
+// sample or delayed data, and X-Demo-As-Of when it was taken.
+func (h *DemoHandler) GetOptionsChain(c *gin.Context) {
+	ticker := strings.ToUpper(c.Param("ticker"))
+
+	var strike *float64
+	if strikeStr := c.Query("strike_price"); strikeStr != "" {
+		value, err := strconv.ParseFloat(strikeStr, 64)
+		if err != nil {
+			appErr := errors.NewBadRequestError("invalid strike_price parameter", err)
+			_ = c.Error(appErr)
+			return
+		}
+		strike = &value
+	}
+	expiration := c.Query("expiration_date")
+	contractType := strings.ToLower(c.Query("contract_type"))
+
+	groupBy := c.Query("group_by")
+	if groupBy != "" && groupBy != "expiration" {
+		appErr := errors.NewBadRequestError("group_by must be expiration", nil)
+		_ = c.Error(appErr)
+		return
+	}
+	filter, appErr := parseChainFilter(c)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	page, appErr := parseChainPage(c)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	if appErr := checkChainFormat(c, groupBy, page); appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	snapshot, err := h.demo.Snapshot(ticker, time.Now())
+	if err != nil {
+		if stderrors.Is(err, services.ErrNotDemoTicker) {
+			appErr := errors.NewNotFoundError("ticker is not available in the demo; see /api/v1/demo/tickers")
+			_ = c.Error(appErr)
+			return
+		}
+		log.Printf("[Handler] ✗ Failed to load demo chain: %v", err)
+		appErr := errors.NewInternalError("failed to load demo chain", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	// The parameters Massive would apply upstream
+	contracts := make([]models.OptionContract, 0, len(snapshot.Contracts))
+	for _, contract := range snapshot.Contracts {
+		d := contract.Details
+		if d == nil {
+			continue
+		}
+		if expiration != "" && (d.ExpirationDate == nil || *d.ExpirationDate != expiration) {
+			continue
+		}
+		if contractType != "" && (d.ContractType == nil || *d.ContractType != contractType) {
+			continue
+		}
+		if strike != nil && (d.StrikePrice == nil || *d.StrikePrice != *strike) {
+			continue
+		}
+		contracts = append(contracts, contract)
+	}
+
+	c.Header("X-Demo-Data", snapshot.Source)
+	c.Header("X-Demo-As-Of", snapshot.AsOf.UTC().Format(time.RFC3339))
+	if snapshot.Source == services.DemoSourceSample {
+		middleware.AddWarning(c, "demo data is a generated sample, not market data")
+	} else {
+		middleware.AddWarning(c, "demo data is delayed, as of "+snapshot.AsOf.UTC().Format(time.RFC3339))
+	}
+	response := &models.OptionsChainResponse{
+		Status:    "OK",
+		RequestID: c.GetString(middleware.RequestIDKey),
+		Results:   contracts,
+	}
+	writeChain(c, response, &snapshot.Spot, groupBy, filter, page, h.riskFreeRate)
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/aaronbengochea/periscope/backend-go/internal/services"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// DividendHandler serves dividend income for share positions
+type DividendHandler struct {
+	portfolios repository.PortfolioRepo
+	dividends  *services.DividendService
+}
+
+// NewDividendHandler creates a new dividend handler
+func NewDividendHandler(portfolios repository.PortfolioRepo, dividends *services.DividendService) *DividendHandler {
+	return &DividendHandler{
+		portfolios: portfolios,
+		dividends:  dividends,
+	}
+}
+
+// GetDividends handles GET /api/v1/portfolio/:id/dividends
+func (h *DividendHandler) GetDividends(c *gin.Context) {
+	portfolioID, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	if _, err := h.portfolios.Get(c.Request.Context(), userID(c), portfolioID); err != nil {
+		appErr := repositoryError(err, "portfolio", "failed to get portfolio")
+		_ = c.Error(appErr)
+		return
+	}
+
+	dividends, err := h.dividends.PortfolioDividends(c.Request.Context(), portfolioID)
+	if err != nil {
+		log.Printf("[Handler] ✗ Failed to load dividends for portfolio %d: %v", portfolioID, err)
+		appErr := errors.NewInternalError("failed to load dividends", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, dividends)
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// writeTaggedJSON responds with body as JSON tagged with a hash of its content, or with 304
+// Not Modified when the request's If-None-Match already holds that tag, so clients polling a
+// chain that hasn't changed don't download it again. The tag is weak because the compression
+// middleware may encode the bytes differently for each client.
+//
+// A request ID differs on every fetch of the same data, so it is left out of the hash: body
+// is encoded with an empty request_id and requestID is filled in afterwards.
+func writeTaggedJSON(c *gin.Context, body any, requestID string) {
+	raw, err := json.Marshal(body)
+	if err != nil {
+		log.Printf("[Handler] ✗ Failed to encode response: %v", err)
+		appErr := errors.NewInternalError("failed to encode response", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	if !tagResponse(c, raw) {
+		return
+	}
+	if requestID != "" {
+		quoted, _ := json.Marshal(requestID)
+		raw = bytes.Replace(raw, []byte(`"request_id":""`), append([]byte(`"request_id":`), quoted...), 1)
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", raw)
+}
+
+// tagResponse sets the ETag hashed from a response body and reports whether the body must
+// still be sent; it answers 304 Not Modified instead when If-None-Match holds the tag
+func tagResponse(c *gin.Context, raw []byte) bool {
+	sum := sha256.Sum256(raw)
+	etag := `W/"` + hex.EncodeToString(sum[:16]) + `"`
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "no-cache")
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return false
+	}
+	return true
+}
+
+// etagMatches reports whether an If-None-Match header holds etag, comparing weakly as
+// RFC 9110 asks for GET requests
+func etagMatches(header, etag string) bool {
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
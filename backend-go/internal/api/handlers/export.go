@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/export"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/aaronbengochea/periscope/backend-go/internal/services"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/xlsx"
+	"github.com/gin-gonic/gin"
+)
+
+// Media types of the spreadsheet and columnar downloads
+const (
+	xlsxContentType    = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	parquetContentType = "application/vnd.apache.parquet"
+)
+
+// ExportHandler serves portfolio downloads for spreadsheets
+type ExportHandler struct {
+	portfolios   repository.PortfolioRepo
+	positions    *repository.PositionRepository
+	transactions *repository.TransactionRepository
+	valuation    *services.ValuationService
+}
+
+// NewExportHandler creates a new export handler
+func NewExportHandler(portfolios repository.PortfolioRepo, positions *repository.PositionRepository, transactions *repository.TransactionRepository, valuation *services.ValuationService) *ExportHandler {
+	return &ExportHandler{
+		portfolios:   portfolios,
+		positions:    positions,
+		transactions: transactions,
+		valuation:    valuation,
+	}
+}
+
+// ExportPortfolio handles GET /api/v1/portfolio/:id/export?format=csv|xlsx&section=positions
+//
+// The Excel workbook has a summary, positions and ledger sheet; CSV exports one section,
+// positions by default.
+func (h *ExportHandler) ExportPortfolio(c *gin.Context) {
+	portfolioID, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	format := c.DefaultQuery("format", "csv")
+	if format != "csv" && format != "xlsx" {
+		appErr := errors.NewBadRequestError("format must be csv or xlsx", nil)
+		_ = c.Error(appErr)
+		return
+	}
+	section := c.DefaultQuery("section", export.SectionPositions)
+	if !slices.Contains(export.Sections, section) {
+		appErr := errors.NewBadRequestError("section must be summary, positions or ledger", nil)
+		_ = c.Error(appErr)
+		return
+	}
+
+	ctx := c.Request.Context()
+	portfolio, err := h.portfolios.Get(ctx, userID(c), portfolioID)
+	if err != nil {
+		appErr := repositoryError(err, "portfolio", "failed to get portfolio")
+		_ = c.Error(appErr)
+		return
+	}
+
+	positions, err := h.positions.ListByPortfolio(ctx, portfolioID, "")
+	if err != nil {
+		appErr := repositoryError(err, "position", "failed to list positions")
+		_ = c.Error(appErr)
+		return
+	}
+	transactions, err := h.transactions.List(ctx, portfolioID, 0)
+	if err != nil {
+		appErr := repositoryError(err, "transaction", "failed to list transactions")
+		_ = c.Error(appErr)
+		return
+	}
+	valuation, err := h.valuation.ValuePortfolio(ctx, portfolioID)
+	if err != nil {
+		log.Printf("[Handler] ✗ Failed to value portfolio %d for export: %v", portfolioID, err)
+		appErr := errors.NewInternalError("failed to value portfolio", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	now := time.Now()
+	sheets := export.Portfolio(portfolio, positions, valuation, transactions, now)
+	date := now.Format("2006-01-02")
+
+	if format == "xlsx" {
+		filename := fmt.Sprintf("portfolio-%d-%s.xlsx", portfolioID, date)
+		var buf bytes.Buffer
+		if err := xlsx.Write(&buf, sheets); err != nil {
+			log.Printf("[Handler] ✗ Failed to write workbook for portfolio %d: %v", portfolioID, err)
+			appErr := errors.NewInternalError("failed to write workbook", err)
+			_ = c.Error(appErr)
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+		c.Header("Content-Length", strconv.Itoa(buf.Len()))
+		c.Data(http.StatusOK, xlsxContentType, buf.Bytes())
+		return
+	}
+
+	sheet := &sheets[slices.Index(export.Sections, section)]
+	filename := fmt.Sprintf("portfolio-%d-%s-%s.csv", portfolioID, section, date)
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(http.StatusOK)
+	if err := export.WriteCSV(c.Writer, sheet); err != nil {
+		log.Printf("[Handler] ✗ Failed to write %s CSV for portfolio %d: %v", section, portfolioID, err)
+	}
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/api/middleware"
+	"github.com/aaronbengochea/periscope/backend-go/internal/graph"
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// GraphQLHandler serves the GraphQL endpoint
+type GraphQLHandler struct {
+	server      http.Handler
+	authEnabled bool
+}
+
+// NewGraphQLHandler creates a new GraphQL handler serving queries with server. authEnabled
+// tells the resolvers that callers without a token are anonymous rather than the single user
+// of a setup without auth.
+func NewGraphQLHandler(server http.Handler, authEnabled bool) *GraphQLHandler {
+	return &GraphQLHandler{
+		server:      server,
+		authEnabled: authEnabled,
+	}
+}
+
+// Query answers a GraphQL query sent to /graphql, as a POST body or GET query parameters,
+// as the caller the auth middleware identified. Portfolios and watchlists are only the
+// caller's own, and API keys reach the fields their scopes allow.
+func (h *GraphQLHandler) Query(c *gin.Context) {
+	caller := graph.Caller{UserID: userID(c)}
+	caller.Anonymous = h.authEnabled && caller.UserID == nil
+	if value, ok := c.Get(middleware.APIKeyKey); ok {
+		caller.APIKey, _ = value.(*models.APIKey)
+	}
+	h.server.ServeHTTP(c.Writer, c.Request.WithContext(graph.WithCaller(c.Request.Context(), caller)))
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/analytics"
+	"github.com/aaronbengochea/periscope/backend-go/internal/export"
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/aaronbengochea/periscope/backend-go/internal/services"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// defaultHistoryRange is the history window when no range is given
+const defaultHistoryRange = "90d"
+
+// HistoryHandler serves stored end-of-day portfolio snapshots and the returns computed
+// from them
+type HistoryHandler struct {
+	portfolios  repository.PortfolioRepo
+	snapshots   repository.SnapshotRepo
+	performance *services.PerformanceService
+}
+
+// NewHistoryHandler creates a new history handler
+func NewHistoryHandler(portfolios repository.PortfolioRepo, snapshots repository.SnapshotRepo, performance *services.PerformanceService) *HistoryHandler {
+	return &HistoryHandler{
+		portfolios:  portfolios,
+		snapshots:   snapshots,
+		performance: performance,
+	}
+}
+
+// GetHistory handles GET /api/v1/portfolio/:id/history?range=90d&format=json|parquet
+//
+// range is a number of days, weeks, months or years (30d, 12w, 6m, 1y), ytd or all.
+// format=parquet downloads the snapshots as a Parquet file, one row per trading day.
+func (h *HistoryHandler) GetHistory(c *gin.Context) {
+	portfolioID, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "parquet" {
+		appErr := errors.NewBadRequestError("format must be json or parquet", nil)
+		_ = c.Error(appErr)
+		return
+	}
+
+	rng := strings.ToLower(c.DefaultQuery("range", defaultHistoryRange))
+	today := analytics.MarketDate(time.Now())
+	from, err := historyStart(rng, today)
+	if err != nil {
+		appErr := errors.NewBadRequestError(err.Error(), err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	if _, err := h.portfolios.Get(c.Request.Context(), userID(c), portfolioID); err != nil {
+		appErr := repositoryError(err, "portfolio", "failed to get portfolio")
+		_ = c.Error(appErr)
+		return
+	}
+
+	snapshots, err := h.snapshots.ListSince(c.Request.Context(), portfolioID, from.Format("2006-01-02"))
+	if err != nil {
+		log.Printf("[Handler] ✗ Failed to load history for portfolio %d: %v", portfolioID, err)
+		appErr := errors.NewInternalError("failed to load portfolio history", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	if format == "parquet" {
+		var buf bytes.Buffer
+		if err := export.WritePortfolioHistoryParquet(&buf, snapshots); err != nil {
+			log.Printf("[Handler] ✗ Failed to write history parquet for portfolio %d: %v", portfolioID, err)
+			appErr := errors.NewInternalError("failed to write parquet file", err)
+			_ = c.Error(appErr)
+			return
+		}
+		filename := fmt.Sprintf("portfolio-%d-history-%s.parquet", portfolioID, rng)
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+		c.Data(http.StatusOK, parquetContentType, buf.Bytes())
+		return
+	}
+
+	c.JSON(http.StatusOK, models.PortfolioHistory{
+		PortfolioID: portfolioID,
+		Range:       rng,
+		From:        from.Format("2006-01-02"),
+		To:          today.Format("2006-01-02"),
+		Snapshots:   snapshots,
+	})
+}
+
+// GetPerformance handles GET /api/v1/portfolio/:id/performance?range=1y
+//
+// range takes the same values as the history endpoint.
+func (h *HistoryHandler) GetPerformance(c *gin.Context) {
+	portfolioID, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	rng := strings.ToLower(c.DefaultQuery("range", defaultHistoryRange))
+	from, err := historyStart(rng, analytics.MarketDate(time.Now()))
+	if err != nil {
+		appErr := errors.NewBadRequestError(err.Error(), err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	if _, err := h.portfolios.Get(c.Request.Context(), userID(c), portfolioID); err != nil {
+		appErr := repositoryError(err, "portfolio", "failed to get portfolio")
+		_ = c.Error(appErr)
+		return
+	}
+
+	perf, err := h.performance.Performance(c.Request.Context(), portfolioID, from.Format("2006-01-02"))
+	if err != nil {
+		log.Printf("[Handler] ✗ Failed to compute performance for portfolio %d: %v", portfolioID, err)
+		appErr := errors.NewInternalError("failed to compute portfolio performance", err)
+		_ = c.Error(appErr)
+		return
+	}
+	perf.Range = rng
+
+	c.JSON(http.StatusOK, perf)
+}
+
+// historyStart returns the first date covered by a history range
+func historyStart(rng string, today time.Time) (time.Time, error) {
+	switch rng {
+	case "all":
+		return time.Time{}, nil
+	case "ytd":
+		return time.Date(today.Year(), time.January, 1, 0, 0, 0, 0, time.UTC), nil
+	}
+
+	invalid := fmt.Errorf("invalid range %q: use e.g. 30d, 12w, 6m, 1y, ytd or all", rng)
+	if len(rng) < 2 {
+		return time.Time{}, invalid
+	}
+	n, err := strconv.Atoi(rng[:len(rng)-1])
+	if err != nil || n <= 0 {
+		return time.Time{}, invalid
+	}
+
+	switch rng[len(rng)-1] {
+	case 'd':
+		return today.AddDate(0, 0, -n), nil
+	case 'w':
+		return today.AddDate(0, 0, -7*n), nil
+	case 'm':
+		return today.AddDate(0, -n, 0), nil
+	case 'y':
+		return today.AddDate(-n, 0, 0), nil
+	}
+	return time.Time{}, invalid
+}
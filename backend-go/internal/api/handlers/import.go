@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/analytics"
+	"github.com/aaronbengochea/periscope/backend-go/internal/importer"
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// MaxImportBytes caps the size of an uploaded import file; the route's BodyLimit enforces it
+const MaxImportBytes = 5 << 20
+
+// ImportHandler handles position and trade imports into a portfolio
+type ImportHandler struct {
+	portfolios   repository.PortfolioRepo
+	transactions *repository.TransactionRepository
+}
+
+// NewImportHandler creates a new import handler
+func NewImportHandler(portfolios repository.PortfolioRepo, transactions *repository.TransactionRepository) *ImportHandler {
+	return &ImportHandler{
+		portfolios:   portfolios,
+		transactions: transactions,
+	}
+}
+
+// ImportTrades handles POST /api/v1/portfolio/:id/import?format=periscope&dry_run=true
+//
+// format selects the parser: periscope (default), schwab, fidelity or ibkr. The CSV is sent
+// either as a multipart form file named "file" or as the raw request body.
+// Nothing is written when any row fails; the response lists the outcome of every row.
+func (h *ImportHandler) ImportTrades(c *gin.Context) {
+	portfolioID, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	format := c.DefaultQuery("format", importer.FormatPeriscope)
+	dryRun := c.Query("dry_run") == "true"
+
+	if _, err := h.portfolios.Get(c.Request.Context(), userID(c), portfolioID); err != nil {
+		appErr := repositoryError(err, "portfolio", "failed to get portfolio")
+		_ = c.Error(appErr)
+		return
+	}
+
+	body, appErr := importBody(c)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	defer body.Close()
+
+	today := analytics.MarketDate(time.Now()).Format("2006-01-02")
+	trades, parsed, err := importer.Parse(format, body, today)
+	if err != nil {
+		appErr := errors.NewBadRequestError(err.Error(), err)
+		_ = c.Error(appErr.WithDetails(gin.H{"formats": importer.Formats()}))
+		return
+	}
+
+	result := &models.ImportResult{
+		Format: format,
+		DryRun: dryRun,
+		Rows:   len(trades) + len(parsed),
+	}
+	for _, r := range parsed {
+		if r.Status == models.ImportRowError {
+			result.Errors++
+		}
+	}
+
+	// Only apply trades when every row parsed; otherwise report the parse failures alone
+	applied := []models.ImportRowResult{}
+	if result.Errors == 0 {
+		applied, result.Committed, err = h.transactions.Import(c.Request.Context(), portfolioID, trades, dryRun)
+		if err != nil {
+			log.Printf("[Handler] ✗ Failed to import into portfolio %d: %v", portfolioID, err)
+			appErr := errors.NewInternalError("failed to import trades", err)
+			_ = c.Error(appErr)
+			return
+		}
+	}
+
+	result.Results = append(parsed, applied...)
+	sort.SliceStable(result.Results, func(i, j int) bool { return result.Results[i].Row < result.Results[j].Row })
+	for _, r := range applied {
+		if r.Status == models.ImportRowError {
+			result.Errors++
+		}
+	}
+	for _, r := range parsed {
+		if r.Status == models.ImportRowSkipped {
+			result.Skipped++
+		}
+	}
+
+	if result.Errors > 0 {
+		log.Printf("[Handler] ✗ Import into portfolio %d rejected: %d of %d rows invalid", portfolioID, result.Errors, result.Rows)
+		c.JSON(http.StatusUnprocessableEntity, result)
+		return
+	}
+
+	log.Printf("[Handler] ✓ Imported %d rows into portfolio %d (dry_run=%v)", result.Rows, portfolioID, dryRun)
+	c.JSON(http.StatusOK, result)
+}
+
+// importBody returns the uploaded file from a multipart form or the raw request body
+func importBody(c *gin.Context) (io.ReadCloser, *errors.AppError) {
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		header, err := c.FormFile("file")
+		if err != nil {
+			return nil, errors.NewBadRequestError("multipart upload must include a \"file\" field", err)
+		}
+		file, err := header.Open()
+		if err != nil {
+			return nil, errors.NewBadRequestError("failed to read uploaded file", err)
+		}
+		return file, nil
+	}
+
+	return c.Request.Body, nil
+}
@@ -0,0 +1,321 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// Journal listing limits
+const (
+	defaultJournalLimit = 50
+	maxJournalLimit     = 200
+)
+
+// JournalHandler manages trade journal entries and searches them
+type JournalHandler struct {
+	portfolios   repository.PortfolioRepo
+	positions    *repository.PositionRepository
+	transactions *repository.TransactionRepository
+	journal      *repository.JournalRepository
+}
+
+// NewJournalHandler creates a new journal handler
+func NewJournalHandler(portfolios repository.PortfolioRepo, positions *repository.PositionRepository, transactions *repository.TransactionRepository, journal *repository.JournalRepository) *JournalHandler {
+	return &JournalHandler{
+		portfolios:   portfolios,
+		positions:    positions,
+		transactions: transactions,
+		journal:      journal,
+	}
+}
+
+// AttachmentRequest is a link to a file attached to a journal entry
+type AttachmentRequest struct {
+	Name string `json:"name" binding:"required,max=200"`
+	URL  string `json:"url" binding:"required,http_url,max=2000"`
+}
+
+// CreateJournalEntryRequest represents the request body for writing a journal entry, e.g.
+// {"position_id": 7, "kind": "thesis", "body": "Selling premium into earnings...", "tags": ["earnings"]}.
+// An entry on a transaction is also attached to the transaction's position.
+type CreateJournalEntryRequest struct {
+	PositionID    *int64              `json:"position_id" binding:"omitempty,gt=0"`
+	TransactionID *int64              `json:"transaction_id" binding:"omitempty,gt=0"`
+	Kind          string              `json:"kind" binding:"omitempty,oneof=thesis exit note"`
+	Title         *string             `json:"title" binding:"omitempty,max=200"`
+	Body          string              `json:"body" binding:"required,max=20000"`
+	Tags          []string            `json:"tags" binding:"omitempty,max=20,dive,max=50"`
+	Attachments   []AttachmentRequest `json:"attachments" binding:"omitempty,max=20,dive"`
+}
+
+// UpdateJournalEntryRequest represents the request body for editing a journal entry.
+// Omitted fields are left unchanged; tags and attachments replace the existing ones.
+type UpdateJournalEntryRequest struct {
+	Kind        *string              `json:"kind" binding:"omitempty,oneof=thesis exit note"`
+	Title       *string              `json:"title" binding:"omitempty,max=200"`
+	Body        *string              `json:"body" binding:"omitempty,min=1,max=20000"`
+	Tags        *[]string            `json:"tags" binding:"omitempty,max=20,dive,max=50"`
+	Attachments *[]AttachmentRequest `json:"attachments" binding:"omitempty,max=20,dive"`
+}
+
+// ListEntries handles GET /api/v1/portfolio/:id/journal?q=&tag=&kind=&position_id=&limit=
+func (h *JournalHandler) ListEntries(c *gin.Context) {
+	portfolioID, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	filter := models.JournalFilter{
+		Query: strings.TrimSpace(c.Query("q")),
+		Tag:   normalizeTag(c.Query("tag")),
+		Kind:  c.Query("kind"),
+	}
+	switch filter.Kind {
+	case "", models.JournalThesis, models.JournalExit, models.JournalNote:
+	default:
+		appErr := errors.NewBadRequestError("kind must be thesis, exit or note", nil)
+		_ = c.Error(appErr)
+		return
+	}
+	positionID, appErr := queryInt(c, "position_id", 0)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	filter.PositionID = int64(positionID)
+	if filter.Limit, appErr = queryInt(c, "limit", defaultJournalLimit); appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	if filter.Limit < 1 || filter.Limit > maxJournalLimit {
+		appErr := errors.NewBadRequestError("limit must be between 1 and 200", nil)
+		_ = c.Error(appErr)
+		return
+	}
+
+	entries, err := h.journal.List(c.Request.Context(), portfolioID, filter)
+	if err != nil {
+		appErr := repositoryError(err, "journal entry", "failed to list journal entries")
+		_ = c.Error(appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": entries})
+}
+
+// CreateEntry handles POST /api/v1/portfolio/:id/journal
+func (h *JournalHandler) CreateEntry(c *gin.Context) {
+	portfolioID, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	var req CreateJournalEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := errors.NewBadRequestError("invalid request body", err)
+		_ = c.Error(appErr)
+		return
+	}
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		appErr := errors.NewBadRequestError("body must not be blank", nil)
+		_ = c.Error(appErr)
+		return
+	}
+
+	ctx := c.Request.Context()
+	if _, err := h.portfolios.Get(ctx, userID(c), portfolioID); err != nil {
+		appErr := repositoryError(err, "portfolio", "failed to get portfolio")
+		_ = c.Error(appErr)
+		return
+	}
+
+	entry := &models.JournalEntry{
+		PortfolioID:   portfolioID,
+		PositionID:    req.PositionID,
+		TransactionID: req.TransactionID,
+		Kind:          req.Kind,
+		Title:         optionalText(req.Title),
+		Body:          body,
+		Tags:          normalizeTags(req.Tags),
+		Attachments:   journalAttachments(req.Attachments),
+	}
+	if entry.Kind == "" {
+		entry.Kind = models.JournalNote
+	}
+
+	if entry.TransactionID != nil {
+		t, err := h.transactions.Get(ctx, portfolioID, *entry.TransactionID)
+		if err != nil {
+			appErr := journalLinkError(err, "transaction_id must be a transaction in this portfolio", "failed to get transaction")
+			_ = c.Error(appErr)
+			return
+		}
+		if entry.PositionID != nil && *entry.PositionID != t.PositionID {
+			appErr := errors.NewBadRequestError("transaction_id belongs to a different position", nil)
+			_ = c.Error(appErr)
+			return
+		}
+		entry.PositionID = &t.PositionID
+	} else if entry.PositionID != nil {
+		if _, err := h.positions.Get(ctx, portfolioID, *entry.PositionID); err != nil {
+			appErr := journalLinkError(err, "position_id must be a position in this portfolio", "failed to get position")
+			_ = c.Error(appErr)
+			return
+		}
+	}
+
+	if err := h.journal.Create(ctx, entry); err != nil {
+		appErr := repositoryError(err, "journal entry", "failed to create journal entry")
+		_ = c.Error(appErr)
+		return
+	}
+
+	log.Printf("[Handler] ✓ Created %s journal entry %d in portfolio %d", entry.Kind, entry.ID, portfolioID)
+	c.JSON(http.StatusCreated, entry)
+}
+
+// GetEntry handles GET /api/v1/portfolio/:id/journal/:entryId
+func (h *JournalHandler) GetEntry(c *gin.Context) {
+	entry, appErr := h.loadEntry(c)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+// UpdateEntry handles PATCH /api/v1/portfolio/:id/journal/:entryId
+func (h *JournalHandler) UpdateEntry(c *gin.Context) {
+	var req UpdateJournalEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := errors.NewBadRequestError("invalid request body", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	entry, appErr := h.loadEntry(c)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	if req.Kind != nil {
+		entry.Kind = *req.Kind
+	}
+	if req.Title != nil {
+		entry.Title = optionalText(req.Title)
+	}
+	if req.Body != nil {
+		body := strings.TrimSpace(*req.Body)
+		if body == "" {
+			appErr := errors.NewBadRequestError("body must not be blank", nil)
+			_ = c.Error(appErr)
+			return
+		}
+		entry.Body = body
+	}
+	if req.Tags != nil {
+		entry.Tags = normalizeTags(*req.Tags)
+	}
+	var attachments []models.JournalAttachment
+	if req.Attachments != nil {
+		attachments = journalAttachments(*req.Attachments)
+	}
+
+	if err := h.journal.Update(c.Request.Context(), entry, attachments); err != nil {
+		appErr := repositoryError(err, "journal entry", "failed to update journal entry")
+		_ = c.Error(appErr)
+		return
+	}
+
+	log.Printf("[Handler] ✓ Updated journal entry %d", entry.ID)
+	c.JSON(http.StatusOK, entry)
+}
+
+// DeleteEntry handles DELETE /api/v1/portfolio/:id/journal/:entryId
+func (h *JournalHandler) DeleteEntry(c *gin.Context) {
+	portfolioID, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	entryID, appErr := paramID(c, "entryId")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	if err := h.journal.Delete(c.Request.Context(), portfolioID, entryID); err != nil {
+		appErr := repositoryError(err, "journal entry", "failed to delete journal entry")
+		_ = c.Error(appErr)
+		return
+	}
+
+	log.Printf("[Handler] ✓ Deleted journal entry %d", entryID)
+	c.Status(http.StatusNoContent)
+}
+
+// loadEntry resolves the :id and :entryId path parameters to a journal entry
+func (h *JournalHandler) loadEntry(c *gin.Context) (*models.JournalEntry, *errors.AppError) {
+	portfolioID, appErr := paramID(c, "id")
+	if appErr != nil {
+		return nil, appErr
+	}
+	entryID, appErr := paramID(c, "entryId")
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	entry, err := h.journal.Get(c.Request.Context(), portfolioID, entryID)
+	if err != nil {
+		return nil, repositoryError(err, "journal entry", "failed to get journal entry")
+	}
+	return entry, nil
+}
+
+// journalLinkError reports a position or transaction outside the portfolio as a bad request
+func journalLinkError(err error, invalid, message string) *errors.AppError {
+	if stderrors.Is(err, repository.ErrNotFound) {
+		return errors.NewBadRequestError(invalid, err)
+	}
+	return repositoryError(err, "journal entry", message)
+}
+
+// optionalText trims optional text such as a title, treating blank text as none
+func optionalText(text *string) *string {
+	if text == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*text)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
+
+// journalAttachments converts requested attachments, naming unnamed ones after their URL
+func journalAttachments(reqs []AttachmentRequest) []models.JournalAttachment {
+	attachments := make([]models.JournalAttachment, 0, len(reqs))
+	for _, req := range reqs {
+		name := strings.TrimSpace(req.Name)
+		if name == "" {
+			name = req.URL
+		}
+		if len(name) > 200 {
+			name = name[:200]
+		}
+		attachments = append(attachments, models.JournalAttachment{Name: name, URL: req.URL})
+	}
+	return attachments
+}
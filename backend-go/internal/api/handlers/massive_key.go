@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/aaronbengochea/periscope/backend-go/internal/secrets"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/massive"
+	"github.com/gin-gonic/gin"
+)
+
+// massiveKeyHintLength is how many trailing characters of a user's Massive key are kept in
+// the clear to tell keys apart
+const massiveKeyHintLength = 4
+
+// MassiveKeyHandler lets users store their own Massive API key, so the market data they
+// request counts against their own plan
+type MassiveKeyHandler struct {
+	users  *repository.UserRepository
+	client *massive.Client
+	box    *secrets.Box
+	audit  *repository.AuditRepository
+}
+
+// NewMassiveKeyHandler creates a new Massive key handler. Keys cannot be stored without a
+// box to encrypt them with.
+func NewMassiveKeyHandler(users *repository.UserRepository, client *massive.Client, box *secrets.Box, audit *repository.AuditRepository) *MassiveKeyHandler {
+	return &MassiveKeyHandler{users: users, client: client, box: box, audit: audit}
+}
+
+// MassiveKeyRequest represents the request body for storing a Massive API key
+type MassiveKeyRequest struct {
+	APIKey string `json:"api_key" binding:"required,min=8,max=256"`
+}
+
+// GetMassiveKey handles GET /api/v1/me/massive-key: whether the user has stored a key, and
+// its last characters
+func (h *MassiveKeyHandler) GetMassiveKey(c *gin.Context) {
+	id, appErr := accountOwner(c)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	user, err := h.users.Get(c.Request.Context(), id)
+	if err != nil {
+		appErr := repositoryError(err, "user", "failed to get massive key")
+		_ = c.Error(appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, user.MassiveKey())
+}
+
+// SetMassiveKey handles PUT /api/v1/me/massive-key. The key is checked with Massive before
+// it is encrypted and stored, replacing any previous one.
+func (h *MassiveKeyHandler) SetMassiveKey(c *gin.Context) {
+	id, appErr := accountOwner(c)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	if h.box == nil {
+		appErr := errors.NewServiceUnavailableError("storing Massive API keys is not enabled on this server")
+		_ = c.Error(appErr)
+		return
+	}
+
+	var req MassiveKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := errors.NewBadRequestError("invalid request body", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	if err := h.client.VerifyAPIKey(c.Request.Context(), req.APIKey); err != nil {
+		if stderrors.Is(err, massive.ErrInvalidAPIKey) {
+			appErr := errors.NewBadRequestError("Massive rejected the API key", err)
+			_ = c.Error(appErr)
+			return
+		}
+		log.Printf("[Handler] ✗ Failed to verify Massive key: %v", err)
+		appErr := errors.NewServiceUnavailableError("could not verify the API key with Massive; try again later")
+		_ = c.Error(appErr)
+		return
+	}
+
+	before, err := h.users.Get(c.Request.Context(), id)
+	if err != nil {
+		appErr := repositoryError(err, "user", "failed to get account")
+		_ = c.Error(appErr)
+		return
+	}
+	ciphertext, err := h.box.Seal(req.APIKey, id)
+	if err != nil {
+		appErr := errors.NewInternalError("failed to encrypt massive key", err)
+		_ = c.Error(appErr)
+		return
+	}
+	hint := req.APIKey[len(req.APIKey)-massiveKeyHintLength:]
+	user, err := h.users.SetMassiveKey(c.Request.Context(), id, ciphertext, hint, time.Now())
+	if err != nil {
+		appErr := repositoryError(err, "user", "failed to set massive key")
+		_ = c.Error(appErr)
+		return
+	}
+
+	var prior any
+	if before.MassiveKeyCiphertext != nil {
+		prior = before.MassiveKey()
+	}
+	recordAudit(c, h.audit, models.AuditMassiveKeySet, user.ID, prior, user.MassiveKey())
+	log.Printf("[Handler] ✓ Stored Massive key of user %s", user.ID)
+	c.JSON(http.StatusOK, user.MassiveKey())
+}
+
+// DeleteMassiveKey handles DELETE /api/v1/me/massive-key, going back to the server's key
+func (h *MassiveKeyHandler) DeleteMassiveKey(c *gin.Context) {
+	id, appErr := accountOwner(c)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	before, err := h.users.Get(c.Request.Context(), id)
+	if err != nil {
+		appErr := repositoryError(err, "user", "failed to get account")
+		_ = c.Error(appErr)
+		return
+	}
+	if before.MassiveKeyCiphertext == nil {
+		appErr := errors.NewNotFoundError("no massive key is stored")
+		_ = c.Error(appErr)
+		return
+	}
+	user, err := h.users.ClearMassiveKey(c.Request.Context(), id)
+	if err != nil {
+		appErr := repositoryError(err, "user", "failed to delete massive key")
+		_ = c.Error(appErr)
+		return
+	}
+
+	recordAudit(c, h.audit, models.AuditMassiveKeyDelete, user.ID, before.MassiveKey(), nil)
+	log.Printf("[Handler] ✓ Deleted Massive key of user %s", user.ID)
+	c.Status(http.StatusNoContent)
+}
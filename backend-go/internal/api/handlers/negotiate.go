@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/rpc"
+	"github.com/aaronbengochea/periscope/backend-go/internal/rpc/periscopev1"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/tinylib/msgp/msgp"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Binary encodings programmatic clients may ask for in Accept on the high-volume market data
+// endpoints instead of JSON
+const (
+	protobufContentType = "application/x-protobuf"
+	msgpackContentType  = "application/msgpack"
+)
+
+// binaryEncoding returns the binary media type the request's Accept header asks for, or ""
+// for JSON. The msgpack types in use (application/msgpack, application/x-msgpack and
+// application/vnd.msgpack) are all accepted. Media ranges are weighed by their q-values: a
+// binary type refused with q=0 is never sent, and one is sent only if it is preferred at
+// least as much as JSON, which application/* and */* also accept.
+func binaryEncoding(c *gin.Context) string {
+	var protobufQ, msgpackQ float64
+	jsonQ := -1.0 // application/json, else the best wildcard that covers it
+	wildcardQ := -1.0
+	for _, part := range strings.Split(c.GetHeader("Accept"), ",") {
+		mediaType, q := mediaRange(part)
+		switch mediaType {
+		case protobufContentType:
+			protobufQ = q
+		case msgpackContentType, "application/x-msgpack", "application/vnd.msgpack":
+			msgpackQ = max(msgpackQ, q)
+		case "application/json":
+			jsonQ = q
+		case "application/*", "*/*":
+			wildcardQ = max(wildcardQ, q)
+		}
+	}
+	if jsonQ < 0 {
+		jsonQ = max(wildcardQ, 0)
+	}
+
+	switch {
+	case protobufQ > 0 && protobufQ >= msgpackQ && protobufQ >= jsonQ:
+		return protobufContentType
+	case msgpackQ > 0 && msgpackQ >= jsonQ:
+		return msgpackContentType
+	}
+	return ""
+}
+
+// mediaRange splits one entry of an Accept header into its lower-cased media type and its
+// q-value, 1 when it gives none or an unreadable one
+func mediaRange(part string) (string, float64) {
+	mediaType, params, _ := strings.Cut(part, ";")
+	q := 1.0
+	for _, param := range strings.Split(params, ";") {
+		name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+		if !strings.EqualFold(name, "q") {
+			continue
+		}
+		if v, err := strconv.ParseFloat(value, 64); err == nil && v >= 0 && v <= 1 {
+			q = v
+		}
+	}
+	return strings.ToLower(strings.TrimSpace(mediaType)), q
+}
+
+// writeBinary sends body as protobuf or MessagePack when the request accepts one, and reports
+// whether it did; when it didn't, the caller sends JSON. MessagePack mirrors the JSON field for
+// field with the generated encoders in models. Protobuf sends the message toProto builds, from
+// proto/periscope/v1; bodies without one pass a nil toProto and are refused with 406. Tagged
+// responses get an ETag and honour If-None-Match like writeTaggedJSON.
+func writeBinary(c *gin.Context, body msgp.Marshaler, toProto func() proto.Message, tagged bool) bool {
+	c.Writer.Header().Add("Vary", "Accept")
+
+	var raw []byte
+	var err error
+	contentType := binaryEncoding(c)
+	switch contentType {
+	case "":
+		return false
+	case protobufContentType:
+		if toProto == nil {
+			appErr := errors.NewNotAcceptableError("this response has no protobuf encoding; accept application/json or application/msgpack")
+			_ = c.Error(appErr)
+			return true
+		}
+		raw, err = proto.Marshal(toProto())
+	case msgpackContentType:
+		raw, err = body.MarshalMsg(nil)
+	}
+	if err != nil {
+		log.Printf("[Handler] ✗ Failed to encode %s response: %v", contentType, err)
+		appErr := errors.NewInternalError("failed to encode response", err)
+		_ = c.Error(appErr)
+		return true
+	}
+
+	if tagged && !tagResponse(c, raw) {
+		return true
+	}
+	c.Data(http.StatusOK, contentType, raw)
+	return true
+}
+
+// chainMessage builds the protobuf message of a chain, filling in the underlying ticker of
+// contracts whose snapshot left it out. fetchedAt is zero for live chains and left unset, so
+// an unchanged chain keeps its ETag.
+func chainMessage(ticker string, underlyingPrice *float64, contracts []models.OptionContract, fetchedAt time.Time, nextCursor string) *periscopev1.Chain {
+	msg := &periscopev1.Chain{
+		Ticker:     ticker,
+		Contracts:  rpc.ContractMessages(contracts),
+		NextCursor: nextCursor,
+	}
+	if !fetchedAt.IsZero() {
+		msg.FetchedAt = timestamppb.New(fetchedAt)
+	}
+	if underlyingPrice != nil {
+		msg.UnderlyingPrice = *underlyingPrice
+	}
+	for _, contract := range msg.Contracts {
+		if contract.UnderlyingTicker == "" {
+			contract.UnderlyingTicker = ticker
+		}
+	}
+	return msg
+}
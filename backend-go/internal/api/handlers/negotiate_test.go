@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestBinaryEncodingHonoursQValues(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct{ accept, want string }{
+		{"", ""},
+		{"application/json", ""},
+		{"application/x-protobuf", protobufContentType},
+		{"application/x-protobuf, application/json", protobufContentType},
+		{"application/json, application/x-protobuf;q=0", ""},
+		{"application/json, application/x-protobuf; q=0.0", ""},
+		{"application/json;q=0.9, application/x-protobuf", protobufContentType},
+		{"application/json, application/x-protobuf;q=0.5", ""},
+		{"*/*, application/x-protobuf;q=0.5", ""},
+		{"*/*;q=0.1, application/x-protobuf;q=0.5", protobufContentType},
+		{"application/vnd.msgpack", msgpackContentType},
+		{"application/x-msgpack;q=0, application/json", ""},
+		{"application/x-protobuf;q=0.4, application/msgpack;q=0.8", msgpackContentType},
+	}
+	for _, tt := range tests {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/options/chain/SPY", nil)
+		c.Request.Header.Set("Accept", tt.accept)
+		if got := binaryEncoding(c); got != tt.want {
+			t.Errorf("Accept %q: got %q, want %q", tt.accept, got, tt.want)
+		}
+	}
+}
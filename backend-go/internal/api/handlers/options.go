@@ -1,34 +1,59 @@
 package handlers
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/aaronbengochea/periscope/backend-go/internal/analytics"
+	"github.com/aaronbengochea/periscope/backend-go/internal/api/middleware"
+	"github.com/aaronbengochea/periscope/backend-go/internal/export"
 	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/rpc"
+	"github.com/aaronbengochea/periscope/backend-go/internal/rpc/periscopev1"
 	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
 	"github.com/aaronbengochea/periscope/backend-go/pkg/massive"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/xlsx"
 	"github.com/gin-gonic/gin"
+	"google.golang.org/protobuf/proto"
 )
 
 // OptionsHandler handles options-related requests
 type OptionsHandler struct {
 	massiveClient *massive.Client
+	riskFreeRate  float64
 }
 
 // NewOptionsHandler creates a new options handler
-func NewOptionsHandler(massiveClient *massive.Client) *OptionsHandler {
+func NewOptionsHandler(massiveClient *massive.Client, riskFreeRate float64) *OptionsHandler {
 	return &OptionsHandler{
 		massiveClient: massiveClient,
+		riskFreeRate:  riskFreeRate,
 	}
 }
 
-// GetOptionsChain handles GET /api/v1/options/:ticker
+// defaultChainPageSize is the page size of a chain request with a cursor but no page_size
+const defaultChainPageSize = 250
+
+// includeSecondOrder reports whether the request asked for vanna, charm and vomma
+func includeSecondOrder(c *gin.Context) bool {
+	return c.Query("include_greeks") == "second_order"
+}
+
+// GetOptionsChain handles GET /api/v1/options/:ticker?format=json|xlsx|parquet. With
+// Accept: application/x-ndjson the chain is streamed one contract per line as its pages
+// arrive; format=xlsx downloads it as a workbook with calls and puts sheets per expiration,
+// and format=parquet as a Parquet file with a row per contract.
 func (h *OptionsHandler) GetOptionsChain(c *gin.Context) {
 	ticker := c.Param("ticker")
 	if ticker == "" {
 		err := errors.NewBadRequestError("ticker is required", nil)
-		c.JSON(err.StatusCode, gin.H{"error": err.Message})
+		_ = c.Error(err)
 		return
 	}
 
@@ -42,7 +67,7 @@ func (h *OptionsHandler) GetOptionsChain(c *gin.Context) {
 		limit, err := strconv.Atoi(limitStr)
 		if err != nil {
 			appErr := errors.NewBadRequestError("invalid limit parameter", err)
-			c.JSON(appErr.StatusCode, gin.H{"error": appErr.Message})
+			_ = c.Error(appErr)
 			return
 		}
 		params.Limit = &limit
@@ -54,7 +79,7 @@ func (h *OptionsHandler) GetOptionsChain(c *gin.Context) {
 		strike, err := strconv.ParseFloat(strikeStr, 64)
 		if err != nil {
 			appErr := errors.NewBadRequestError("invalid strike_price parameter", err)
-			c.JSON(appErr.StatusCode, gin.H{"error": appErr.Message})
+			_ = c.Error(appErr)
 			return
 		}
 		params.StrikePrice = &strike
@@ -68,6 +93,39 @@ func (h *OptionsHandler) GetOptionsChain(c *gin.Context) {
 		params.ContractType = &contractType
 	}
 
+	groupBy := c.Query("group_by")
+	if groupBy != "" && groupBy != "expiration" {
+		appErr := errors.NewBadRequestError("group_by must be expiration", nil)
+		_ = c.Error(appErr)
+		return
+	}
+
+	// Server-side filters applied after the full chain is fetched
+	filter, appErr := parseChainFilter(c)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	page, appErr := parseChainPage(c)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	if appErr := checkChainFormat(c, groupBy, page); appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	if ndjsonRequested(c) && c.DefaultQuery("format", "json") == "json" {
+		if !wholeChain(groupBy, page) {
+			appErr := errors.NewBadRequestError("group_by, sort, page_size and cursor need the whole chain and cannot be streamed as NDJSON", nil)
+			_ = c.Error(appErr)
+			return
+		}
+		h.streamChain(c, ticker, params, filter)
+		return
+	}
+
 	log.Printf("[Handler] Fetching options chain for ticker: %s", ticker)
 
 	// Fetch options chain from Massive API
@@ -75,42 +133,341 @@ func (h *OptionsHandler) GetOptionsChain(c *gin.Context) {
 	if err != nil {
 		log.Printf("[Handler] ✗ Failed to fetch options chain: %v", err)
 		appErr := errors.NewInternalError("failed to fetch options chain", err)
-		c.JSON(appErr.StatusCode, gin.H{"error": appErr.Message})
+		_ = c.Error(appErr)
 		return
 	}
 
 	log.Printf("[Handler] ✓ Received %d option contracts", len(response.Results))
 
-	// Fetch underlying stock price separately (required if user doesn't have stocks subscription)
+	stockPrice := h.fetchStockPrice(c, ticker)
+	if stockPrice != nil {
+		log.Printf("[Handler] Injecting stock price into %d contracts", len(response.Results))
+		injected := injectStockPrice(response.Results, ticker, stockPrice)
+		log.Printf("[Handler] ✓ Injected price into %d contracts (already had price: %d)", injected, len(response.Results)-injected)
+	}
+
+	writeChain(c, response, stockPrice, groupBy, filter, page, h.riskFreeRate)
+}
+
+// fetchStockPrice fetches the underlying's price separately (required if user doesn't have
+// stocks subscription), reporting in headers whether it is injected into the contracts. A
+// failure is only logged, since the price might be in the options response.
+func (h *OptionsHandler) fetchStockPrice(c *gin.Context, ticker string) *float64 {
 	stockPrice, err := h.massiveClient.GetStockPrice(c.Request.Context(), ticker)
 	if err != nil {
-		// Log warning but don't fail the request - underlying price might be in options response
 		log.Printf("[Handler] ⚠ Stock price fetch failed: %v", err)
 		c.Writer.Header().Set("X-Stock-Price-Fetch-Failed", "true")
-	} else if stockPrice != nil {
+		middleware.AddWarning(c, "the underlying's price could not be fetched; contracts may be missing it")
+		return nil
+	}
+	if stockPrice != nil {
 		log.Printf("[Handler] ✓ Stock price fetched: $%.2f", *stockPrice)
-		log.Printf("[Handler] Injecting stock price into %d contracts", len(response.Results))
+		c.Writer.Header().Set("X-Stock-Price-Injected", "true")
+	}
+	return stockPrice
+}
 
-		// Count how many contracts needed price injection
-		injected := 0
-		for i := range response.Results {
-			if response.Results[i].UnderlyingAsset == nil {
-				response.Results[i].UnderlyingAsset = &models.UnderlyingAsset{}
-			}
-			if response.Results[i].UnderlyingAsset.Price == nil {
-				response.Results[i].UnderlyingAsset.Price = stockPrice
-				injected++
+// injectStockPrice sets the underlying price and ticker on contracts missing them and returns
+// how many needed the price
+func injectStockPrice(contracts []models.OptionContract, ticker string, stockPrice *float64) int {
+	injected := 0
+	for i := range contracts {
+		if contracts[i].UnderlyingAsset == nil {
+			contracts[i].UnderlyingAsset = &models.UnderlyingAsset{}
+		}
+		if contracts[i].UnderlyingAsset.Price == nil {
+			contracts[i].UnderlyingAsset.Price = stockPrice
+			injected++
+		}
+		if contracts[i].UnderlyingAsset.Ticker == nil {
+			contracts[i].UnderlyingAsset.Ticker = &ticker
+		}
+	}
+	return injected
+}
+
+// ndjsonRequested reports whether the client asked for the chain as newline-delimited JSON
+func ndjsonRequested(c *gin.Context) bool {
+	return strings.Contains(c.GetHeader("Accept"), "application/x-ndjson")
+}
+
+// streamChain writes the chain as newline-delimited JSON, one contract per line, each page
+// as soon as Massive returns it rather than after the last. Contracts are scored and
+// filtered like a JSON chain, page by page. A page that fails after the response has started
+// ends it with an {"error": ...} line.
+func (h *OptionsHandler) streamChain(c *gin.Context, ticker string, params *massive.OptionsChainParams, filter analytics.ChainFilter) {
+	log.Printf("[Handler] Streaming options chain for ticker: %s", ticker)
+	stockPrice := h.fetchStockPrice(c, ticker)
+	liquidity := c.Query("include_liquidity") == "true"
+	secondOrder := includeSecondOrder(c)
+
+	rc := http.NewResponseController(c.Writer)
+	enc := json.NewEncoder(c.Writer)
+	started := false
+	sent := 0
+	for response, err := range h.massiveClient.OptionsChainPages(c.Request.Context(), ticker, params) {
+		if err != nil {
+			log.Printf("[Handler] ✗ Failed to fetch options chain: %v", err)
+			appErr := errors.NewInternalError("failed to fetch options chain", err)
+			if !started {
+				_ = c.Error(appErr)
+			} else {
+				_ = enc.Encode(middleware.NewErrorResponse(c, appErr))
 			}
-			if response.Results[i].UnderlyingAsset.Ticker == nil {
-				response.Results[i].UnderlyingAsset.Ticker = &ticker
+			return
+		}
+
+		contracts := response.Results
+		if stockPrice != nil {
+			injectStockPrice(contracts, ticker, stockPrice)
+		}
+		if liquidity {
+			analytics.ScoreLiquidity(contracts)
+		}
+		if secondOrder {
+			analytics.AttachSecondOrderGreeks(contracts, h.riskFreeRate, time.Now())
+		}
+		contracts = analytics.FilterChain(contracts, filter, time.Now())
+
+		if !started {
+			c.Header("Content-Type", "application/x-ndjson")
+			c.Header("X-Content-Type-Options", "nosniff")
+			c.Status(http.StatusOK)
+			started = true
+		}
+		_ = rc.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+		for i := range contracts {
+			if err := enc.Encode(&contracts[i]); err != nil {
+				return // the client went away
 			}
 		}
-		log.Printf("[Handler] ✓ Injected price into %d contracts (already had price: %d)", injected, len(response.Results)-injected)
-		c.Writer.Header().Set("X-Stock-Price-Injected", "true")
+		sent += len(contracts)
+		c.Writer.Flush()
+	}
+	log.Printf("[Handler] ✓ Streamed %d contracts", sent)
+}
+
+// writeChain scores, filters, sorts, pages and optionally groups a fetched chain as the request
+// asked (include_liquidity, include_greeks and the parseChainFilter and parseChainPage
+// parameters) and responds with it, tagged for conditional requests
+func writeChain(c *gin.Context, response *models.OptionsChainResponse, stockPrice *float64, groupBy string, filter analytics.ChainFilter, page analytics.ChainPage, riskFreeRate float64) {
+	if c.Query("include_liquidity") == "true" {
+		analytics.ScoreLiquidity(response.Results)
+	}
+
+	if includeSecondOrder(c) {
+		computed := analytics.AttachSecondOrderGreeks(response.Results, riskFreeRate, time.Now())
+		log.Printf("[Handler] ✓ Computed second-order greeks for %d contracts", computed)
+	}
+
+	if filter.Active() {
+		before := len(response.Results)
+		response.Results = analytics.FilterChain(response.Results, filter, time.Now())
+		log.Printf("[Handler] ✓ Filters kept %d of %d contracts", len(response.Results), before)
+	}
+
+	switch c.Query("format") {
+	case "xlsx":
+		writeChainWorkbook(c, response.Results)
+		return
+	case "parquet":
+		writeChainParquet(c, response.Results, stockPrice, time.Now())
+		return
+	}
+
+	// Sorting and paging follow filtering so every page is full, and precede grouping so a
+	// page's expirations hold only its own contracts
+	results, next, err := analytics.PageChain(response.Results, page)
+	if err != nil {
+		appErr := errors.NewBadRequestError("invalid cursor parameter", err)
+		_ = c.Error(appErr)
+		return
+	}
+	response.Results = results
+	response.NextCursor = next
+
+	if groupBy == "expiration" {
+		grouped := models.GroupedChainResponse{
+			Status:          response.Status,
+			RequestID:       response.RequestID,
+			UnderlyingPrice: stockPrice,
+			NextCursor:      response.NextCursor,
+		}
+		if grouped.UnderlyingPrice == nil {
+			grouped.UnderlyingPrice = analytics.UnderlyingPrice(response.Results)
+		}
+		grouped.Expirations = analytics.GroupByExpiration(response.Results, grouped.UnderlyingPrice, time.Now())
+
+		log.Printf("[Handler] Sending %d contracts grouped into %d expirations", len(response.Results), len(grouped.Expirations))
+		requestID := grouped.RequestID
+		grouped.RequestID = ""
+		if writeBinary(c, &grouped, nil, true) {
+			return
+		}
+		writeTaggedJSON(c, grouped, requestID)
+		return
 	}
 
 	log.Printf("[Handler] Sending response with %d contracts to client", len(response.Results))
-	c.JSON(http.StatusOK, response)
+	requestID := response.RequestID
+	response.RequestID = ""
+	toProto := func() proto.Message {
+		if stockPrice == nil {
+			stockPrice = analytics.UnderlyingPrice(response.Results)
+		}
+		return chainMessage(strings.ToUpper(c.Param("ticker")), stockPrice, response.Results, time.Time{}, response.NextCursor)
+	}
+	if writeBinary(c, response, toProto, true) {
+		return
+	}
+	writeTaggedJSON(c, response, requestID)
+}
+
+// checkChainFormat validates the chain's format parameter. Workbooks and Parquet files hold
+// the whole chain, so they refuse grouping, sorting and paging.
+func checkChainFormat(c *gin.Context, groupBy string, page analytics.ChainPage) *errors.AppError {
+	switch format := c.DefaultQuery("format", "json"); format {
+	case "json":
+		return nil
+	case "xlsx", "parquet":
+		if !wholeChain(groupBy, page) {
+			return errors.NewBadRequestError("group_by, sort, page_size and cursor cannot be used with format="+format, nil)
+		}
+		return nil
+	default:
+		return errors.NewBadRequestError("format must be json, xlsx or parquet", nil)
+	}
+}
+
+// wholeChain reports whether a request asks for the whole chain as fetched, neither grouped,
+// sorted nor paged
+func wholeChain(groupBy string, page analytics.ChainPage) bool {
+	return groupBy == "" && page.Order.Field == "" && page.Size == 0 && page.Cursor == ""
+}
+
+// writeChainWorkbook sends a scored and filtered chain as an Excel download
+func writeChainWorkbook(c *gin.Context, contracts []models.OptionContract) {
+	now := time.Now()
+	sheets := export.Chain(contracts, now)
+	filename := fmt.Sprintf("chain-%s-%s.xlsx", strings.ToUpper(c.Param("ticker")), now.Format("2006-01-02"))
+	// Built in full before anything is sent, so a failure still gets an error response
+	var buf bytes.Buffer
+	if err := xlsx.Write(&buf, sheets); err != nil {
+		log.Printf("[Handler] ✗ Failed to write chain workbook: %v", err)
+		appErr := errors.NewInternalError("failed to write workbook", err)
+		_ = c.Error(appErr)
+		return
+	}
+	log.Printf("[Handler] Sending %d contracts as a workbook of %d sheets (%d bytes)", len(contracts), len(sheets), buf.Len())
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Header("Content-Length", strconv.Itoa(buf.Len()))
+	c.Data(http.StatusOK, xlsxContentType, buf.Bytes())
+}
+
+// writeChainParquet sends a filtered chain as a Parquet download, in the same schema as
+// historical chains
+func writeChainParquet(c *gin.Context, contracts []models.OptionContract, underlyingPrice *float64, capturedAt time.Time) {
+	ticker := strings.ToUpper(c.Param("ticker"))
+	if underlyingPrice == nil {
+		underlyingPrice = analytics.UnderlyingPrice(contracts)
+	}
+	filename := fmt.Sprintf("chain-%s-%s.parquet", ticker, capturedAt.UTC().Format("20060102T150405Z"))
+	var buf bytes.Buffer
+	if err := export.WriteChainParquet(&buf, ticker, underlyingPrice, capturedAt, contracts); err != nil {
+		log.Printf("[Handler] ✗ Failed to write chain parquet: %v", err)
+		appErr := errors.NewInternalError("failed to write parquet file", err)
+		_ = c.Error(appErr)
+		return
+	}
+	log.Printf("[Handler] Sending %d contracts as parquet (%d bytes)", len(contracts), buf.Len())
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Data(http.StatusOK, parquetContentType, buf.Bytes())
+}
+
+// parseChainFilter reads the min_dte/max_dte, moneyness, strike_pct, delta_min/delta_max and
+// min_liquidity query parameters. moneyness accepts itm, otm or atm (atm_band sets the ATM width in %).
+func parseChainFilter(c *gin.Context) (analytics.ChainFilter, *errors.AppError) {
+	var filter analytics.ChainFilter
+	var appErr *errors.AppError
+
+	if filter.MinDTE, appErr = queryOptionalInt(c, "min_dte"); appErr != nil {
+		return filter, appErr
+	}
+	if filter.MaxDTE, appErr = queryOptionalInt(c, "max_dte"); appErr != nil {
+		return filter, appErr
+	}
+	if filter.MinDTE != nil && filter.MaxDTE != nil && *filter.MinDTE > *filter.MaxDTE {
+		return filter, errors.NewBadRequestError("min_dte must not exceed max_dte", nil)
+	}
+
+	filter.Moneyness = strings.ToLower(c.Query("moneyness"))
+	switch filter.Moneyness {
+	case "", analytics.MoneynessITM, analytics.MoneynessOTM, analytics.MoneynessATM:
+	default:
+		return filter, errors.NewBadRequestError("moneyness must be one of itm, otm, atm", nil)
+	}
+	if filter.ATMBand, appErr = queryFloat(c, "atm_band", 2.5); appErr != nil {
+		return filter, appErr
+	}
+	if filter.StrikePct, appErr = queryOptionalFloat(c, "strike_pct"); appErr != nil {
+		return filter, appErr
+	}
+
+	if filter.DeltaMin, appErr = queryOptionalFloat(c, "delta_min"); appErr != nil {
+		return filter, appErr
+	}
+	if filter.DeltaMax, appErr = queryOptionalFloat(c, "delta_max"); appErr != nil {
+		return filter, appErr
+	}
+	if filter.DeltaMin != nil && filter.DeltaMax != nil && *filter.DeltaMin > *filter.DeltaMax {
+		return filter, errors.NewBadRequestError("delta_min must not exceed delta_max", nil)
+	}
+
+	if filter.MinLiquidity, appErr = queryOptionalFloat(c, "min_liquidity"); appErr != nil {
+		return filter, appErr
+	}
+
+	return filter, nil
+}
+
+// parseChainPage reads the sort, order, cursor and page_size query parameters. Without
+// page_size or cursor the whole chain is returned; a cursor alone pages by
+// defaultChainPageSize.
+func parseChainPage(c *gin.Context) (analytics.ChainPage, *errors.AppError) {
+	page := analytics.ChainPage{Cursor: c.Query("cursor")}
+
+	page.Order.Field = strings.ToLower(c.Query("sort"))
+	switch page.Order.Field {
+	case "", analytics.SortVolume, analytics.SortOpenInterest, analytics.SortIV, analytics.SortStrike:
+	default:
+		return page, errors.NewBadRequestError("sort must be one of volume, open_interest, iv, strike", nil)
+	}
+	switch strings.ToLower(c.Query("order")) {
+	case "", "asc":
+	case "desc":
+		page.Order.Desc = true
+	default:
+		return page, errors.NewBadRequestError("order must be asc or desc", nil)
+	}
+	if page.Order.Desc && page.Order.Field == "" {
+		return page, errors.NewBadRequestError("order requires sort", nil)
+	}
+
+	def := 0
+	if page.Cursor != "" {
+		def = defaultChainPageSize
+	}
+	var appErr *errors.AppError
+	if page.Size, appErr = queryInt(c, "page_size", def); appErr != nil {
+		return page, appErr
+	}
+	if c.Query("page_size") != "" && (page.Size < 1 || page.Size > analytics.MaxChainPageSize) {
+		return page, errors.NewBadRequestError("page_size must be between 1 and 1000", nil)
+	}
+	if err := page.Validate(); err != nil {
+		return page, errors.NewBadRequestError("invalid cursor parameter", err)
+	}
+	return page, nil
 }
 
 // GetContractDetailsRequest represents the request body for fetching contract details
@@ -124,7 +481,7 @@ func (h *OptionsHandler) GetContractDetails(c *gin.Context) {
 	var req GetContractDetailsRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		appErr := errors.NewBadRequestError("invalid request body", err)
-		c.JSON(appErr.StatusCode, gin.H{"error": appErr.Message})
+		_ = c.Error(appErr)
 		return
 	}
 
@@ -135,18 +492,32 @@ func (h *OptionsHandler) GetContractDetails(c *gin.Context) {
 	if err != nil {
 		log.Printf("[Handler] ✗ Failed to fetch contract details: %v", err)
 		appErr := errors.NewInternalError("failed to fetch contract details", err)
-		c.JSON(appErr.StatusCode, gin.H{"error": appErr.Message})
+		_ = c.Error(appErr)
 		return
 	}
 
 	log.Printf("[Handler] ✓ Received %d contract details", len(contracts))
 
+	if c.Query("include_liquidity") == "true" {
+		analytics.ScoreLiquidity(contracts)
+	}
+
+	if includeSecondOrder(c) {
+		analytics.AttachSecondOrderGreeks(contracts, h.riskFreeRate, time.Now())
+	}
+
 	// Return the contracts in the same format as options chain
 	response := models.OptionsChainResponse{
 		Status:    "OK",
-		RequestID: c.GetString("request_id"),
+		RequestID: c.GetString(middleware.RequestIDKey),
 		Results:   contracts,
 	}
 
+	toProto := func() proto.Message {
+		return &periscopev1.GetContractsResponse{Contracts: rpc.ContractMessages(contracts)}
+	}
+	if writeBinary(c, &response, toProto, false) {
+		return
+	}
 	c.JSON(http.StatusOK, response)
 }
@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/analytics"
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/aaronbengochea/periscope/backend-go/internal/services"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// Paper order history limits
+const (
+	defaultOrderLimit = 50
+	maxOrderLimit     = 200
+)
+
+// PaperOrderHandler places simulated orders in paper portfolios
+type PaperOrderHandler struct {
+	portfolios repository.PortfolioRepo
+	orders     *repository.PaperOrderRepository
+	paper      *services.PaperTradingService
+}
+
+// NewPaperOrderHandler creates a new paper order handler
+func NewPaperOrderHandler(portfolios repository.PortfolioRepo, orders *repository.PaperOrderRepository, paper *services.PaperTradingService) *PaperOrderHandler {
+	return &PaperOrderHandler{
+		portfolios: portfolios,
+		orders:     orders,
+		paper:      paper,
+	}
+}
+
+// PaperOrderRequest represents the request body for a simulated order, e.g.
+// {"asset_type": "option", "ticker": "O:AAPL261120P00180000", "instruction": "sell_to_open", "quantity": 2, "limit_price": 3.10}
+type PaperOrderRequest struct {
+	AssetType   string   `json:"asset_type" binding:"required,oneof=option stock"`
+	Ticker      string   `json:"ticker" binding:"required"`
+	Instruction string   `json:"instruction" binding:"required,oneof=buy_to_open sell_to_open buy_to_close sell_to_close"`
+	Quantity    float64  `json:"quantity" binding:"required,gt=0"`
+	LimitPrice  *float64 `json:"limit_price" binding:"omitempty,gte=0"`
+	SlippageBps *float64 `json:"slippage_bps" binding:"omitempty,gte=0,lte=1000"` // defaults to PAPER_SLIPPAGE_BPS
+	Fees        float64  `json:"fees" binding:"gte=0"`
+}
+
+// PlaceOrder handles POST /api/v1/portfolio/:id/orders
+func (h *PaperOrderHandler) PlaceOrder(c *gin.Context) {
+	portfolioID, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	var req PaperOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := errors.NewBadRequestError("invalid request body", err)
+		_ = c.Error(appErr)
+		return
+	}
+	if req.AssetType == models.AssetTypeOption && req.Quantity != math.Trunc(req.Quantity) {
+		appErr := errors.NewBadRequestError("option orders must be for whole contracts", nil)
+		_ = c.Error(appErr)
+		return
+	}
+
+	order := &models.PaperOrder{
+		PortfolioID: portfolioID,
+		AssetType:   req.AssetType,
+		Instruction: req.Instruction,
+		Quantity:    req.Quantity,
+		LimitPrice:  req.LimitPrice,
+		Fees:        req.Fees,
+	}
+	position, appErr := newPosition(portfolioID, &CreatePositionRequest{
+		AssetType: req.AssetType,
+		Ticker:    req.Ticker,
+		Side:      order.PositionSide(),
+		Quantity:  req.Quantity,
+	})
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	order.Ticker = position.Ticker
+	today := analytics.MarketDate(time.Now()).Format("2006-01-02")
+	if position.IsOption() && *position.ExpirationDate < today {
+		appErr := errors.NewBadRequestError("contract has expired", nil)
+		_ = c.Error(appErr)
+		return
+	}
+
+	portfolio, err := h.portfolios.Get(c.Request.Context(), userID(c), portfolioID)
+	if err != nil {
+		appErr := repositoryError(err, "portfolio", "failed to get portfolio")
+		_ = c.Error(appErr)
+		return
+	}
+	if portfolio.Settings.AccountType != models.AccountPaper {
+		appErr := errors.NewConflictError("orders can only be placed in paper portfolios")
+		_ = c.Error(appErr)
+		return
+	}
+
+	if err := h.paper.Submit(c.Request.Context(), order, position, req.SlippageBps); err != nil {
+		appErr := paperOrderError(err, order)
+		_ = c.Error(appErr)
+		return
+	}
+
+	log.Printf("[Handler] ✓ Paper order %d %s in portfolio %d", order.ID, order.Status, portfolioID)
+	c.JSON(http.StatusCreated, order)
+}
+
+// ListOrders handles GET /api/v1/portfolio/:id/orders?limit=50
+func (h *PaperOrderHandler) ListOrders(c *gin.Context) {
+	portfolioID, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	limit, appErr := queryInt(c, "limit", defaultOrderLimit)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	if limit < 1 || limit > maxOrderLimit {
+		appErr := errors.NewBadRequestError("limit must be between 1 and 200", nil)
+		_ = c.Error(appErr)
+		return
+	}
+
+	orders, err := h.orders.List(c.Request.Context(), portfolioID, limit)
+	if err != nil {
+		appErr := repositoryError(err, "order", "failed to list orders")
+		_ = c.Error(appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": orders})
+}
+
+// paperOrderError maps order failures: missing quotes, nothing to close and ledger errors
+func paperOrderError(err error, order *models.PaperOrder) *errors.AppError {
+	switch {
+	case stderrors.Is(err, services.ErrNoQuote):
+		return errors.NewServiceUnavailableError("no quote available for " + order.Ticker)
+	case stderrors.Is(err, repository.ErrNothingToClose):
+		return errors.NewConflictError(fmt.Sprintf("no open %s position in %s to close", order.PositionSide(), order.Ticker))
+	}
+	log.Printf("[Handler] ✗ Failed to place paper order for %s: %v", order.Ticker, err)
+	return ledgerError(err, "failed to place order")
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/api/middleware"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// queryFloat parses an optional float query parameter, returning def when absent
+func queryFloat(c *gin.Context, name string, def float64) (float64, *errors.AppError) {
+	raw := c.Query(name)
+	if raw == "" {
+		return def, nil
+	}
+	v, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0, errors.NewBadRequestError("invalid "+name+" parameter", err)
+	}
+	return v, nil
+}
+
+// queryInt parses an optional integer query parameter, returning def when absent
+func queryInt(c *gin.Context, name string, def int) (int, *errors.AppError) {
+	raw := c.Query(name)
+	if raw == "" {
+		return def, nil
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, errors.NewBadRequestError("invalid "+name+" parameter", err)
+	}
+	return v, nil
+}
+
+// queryOptionalFloat parses an optional float query parameter, returning nil when absent
+func queryOptionalFloat(c *gin.Context, name string) (*float64, *errors.AppError) {
+	if c.Query(name) == "" {
+		return nil, nil
+	}
+	v, appErr := queryFloat(c, name, 0)
+	if appErr != nil {
+		return nil, appErr
+	}
+	return &v, nil
+}
+
+// queryOptionalInt parses an optional integer query parameter, returning nil when absent
+func queryOptionalInt(c *gin.Context, name string) (*int, *errors.AppError) {
+	if c.Query(name) == "" {
+		return nil, nil
+	}
+	v, appErr := queryInt(c, name, 0)
+	if appErr != nil {
+		return nil, appErr
+	}
+	return &v, nil
+}
+
+// paramID parses a positive integer path parameter such as :id
+func paramID(c *gin.Context, name string) (int64, *errors.AppError) {
+	id, err := strconv.ParseInt(c.Param(name), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, errors.NewBadRequestError("invalid "+name+" parameter", err)
+	}
+	return id, nil
+}
+
+// uuidPattern matches a UUID in its canonical hyphenated form
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// paramUUID parses a UUID path parameter such as a user's :id, returning it in lower case
+func paramUUID(c *gin.Context, name string) (string, *errors.AppError) {
+	id := c.Param(name)
+	if !uuidPattern.MatchString(id) {
+		return "", errors.NewBadRequestError("invalid "+name+" parameter", nil)
+	}
+	return strings.ToLower(id), nil
+}
+
+// userID returns the authenticated user's ID, or nil when authentication is disabled
+func userID(c *gin.Context) *string {
+	if id := c.GetString(middleware.UserIDKey); id != "" {
+		return &id
+	}
+	return nil
+}
@@ -0,0 +1,264 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// PortfolioHandler handles portfolio CRUD requests
+type PortfolioHandler struct {
+	portfolios repository.PortfolioRepo
+	audit      *repository.AuditRepository
+}
+
+// NewPortfolioHandler creates a new portfolio handler
+func NewPortfolioHandler(portfolios repository.PortfolioRepo, audit *repository.AuditRepository) *PortfolioHandler {
+	return &PortfolioHandler{
+		portfolios: portfolios,
+		audit:      audit,
+	}
+}
+
+// PortfolioSettingsRequest carries portfolio settings. Omitted fields keep their
+// current (or default) value.
+type PortfolioSettingsRequest struct {
+	AccountType     *string `json:"account_type" binding:"omitempty,oneof=taxable ira roth_ira margin paper other"`
+	IncludeInRollup *bool   `json:"include_in_rollup"`
+}
+
+// apply copies the provided settings onto s
+func (r *PortfolioSettingsRequest) apply(s *models.PortfolioSettings) {
+	if r == nil {
+		return
+	}
+	if r.AccountType != nil {
+		s.AccountType = *r.AccountType
+	}
+	if r.IncludeInRollup != nil {
+		s.IncludeInRollup = *r.IncludeInRollup
+	}
+}
+
+// CreatePortfolioRequest represents the request body for creating a portfolio
+type CreatePortfolioRequest struct {
+	Name        string                    `json:"name" binding:"required,max=100"`
+	Description *string                   `json:"description"`
+	Settings    *PortfolioSettingsRequest `json:"settings"`
+}
+
+// UpdatePortfolioRequest represents the request body for updating a portfolio.
+// Omitted fields are left unchanged.
+type UpdatePortfolioRequest struct {
+	Name        *string                   `json:"name" binding:"omitempty,min=1,max=100"`
+	Description *string                   `json:"description"`
+	Settings    *PortfolioSettingsRequest `json:"settings"`
+}
+
+// ListPortfolios handles GET /api/v1/portfolio
+func (h *PortfolioHandler) ListPortfolios(c *gin.Context) {
+	portfolios, err := h.portfolios.List(c.Request.Context(), userID(c))
+	if err != nil {
+		log.Printf("[Handler] ✗ Failed to list portfolios: %v", err)
+		appErr := errors.NewInternalError("failed to list portfolios", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": portfolios})
+}
+
+// CreatePortfolio handles POST /api/v1/portfolio
+func (h *PortfolioHandler) CreatePortfolio(c *gin.Context) {
+	var req CreatePortfolioRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := errors.NewBadRequestError("invalid request body", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	portfolio := &models.Portfolio{
+		UserID:      userID(c),
+		Name:        strings.TrimSpace(req.Name),
+		Description: req.Description,
+		Settings:    models.DefaultPortfolioSettings(),
+	}
+	req.Settings.apply(&portfolio.Settings)
+	if portfolio.Name == "" {
+		appErr := errors.NewBadRequestError("name must not be blank", nil)
+		_ = c.Error(appErr)
+		return
+	}
+
+	if err := h.portfolios.Create(c.Request.Context(), portfolio); err != nil {
+		appErr := repositoryError(err, "portfolio", "failed to create portfolio")
+		_ = c.Error(appErr)
+		return
+	}
+
+	recordAudit(c, h.audit, models.AuditPortfolioCreate, portfolio.ID, nil, portfolio)
+	log.Printf("[Handler] ✓ Created portfolio %d (%s)", portfolio.ID, portfolio.Name)
+	c.JSON(http.StatusCreated, portfolio)
+}
+
+// GetPortfolio handles GET /api/v1/portfolio/:id
+func (h *PortfolioHandler) GetPortfolio(c *gin.Context) {
+	id, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	portfolio, err := h.portfolios.Get(c.Request.Context(), userID(c), id)
+	if err != nil {
+		appErr := repositoryError(err, "portfolio", "failed to get portfolio")
+		_ = c.Error(appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, portfolio)
+}
+
+// UpdatePortfolio handles PATCH /api/v1/portfolio/:id
+func (h *PortfolioHandler) UpdatePortfolio(c *gin.Context) {
+	id, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	var req UpdatePortfolioRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := errors.NewBadRequestError("invalid request body", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	portfolio, err := h.portfolios.Get(c.Request.Context(), userID(c), id)
+	if err != nil {
+		appErr := repositoryError(err, "portfolio", "failed to get portfolio")
+		_ = c.Error(appErr)
+		return
+	}
+	before := *portfolio
+
+	if req.Name != nil {
+		portfolio.Name = strings.TrimSpace(*req.Name)
+		if portfolio.Name == "" {
+			appErr := errors.NewBadRequestError("name must not be blank", nil)
+			_ = c.Error(appErr)
+			return
+		}
+	}
+	if req.Description != nil {
+		portfolio.Description = req.Description
+	}
+	req.Settings.apply(&portfolio.Settings)
+
+	if err := h.portfolios.Update(c.Request.Context(), portfolio); err != nil {
+		appErr := repositoryError(err, "portfolio", "failed to update portfolio")
+		_ = c.Error(appErr)
+		return
+	}
+
+	recordAudit(c, h.audit, models.AuditPortfolioUpdate, portfolio.ID, before, portfolio)
+	log.Printf("[Handler] ✓ Updated portfolio %d", portfolio.ID)
+	c.JSON(http.StatusOK, portfolio)
+}
+
+// DeletePortfolio handles DELETE /api/v1/portfolio/:id
+func (h *PortfolioHandler) DeletePortfolio(c *gin.Context) {
+	id, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	portfolio, err := h.portfolios.Get(c.Request.Context(), userID(c), id)
+	if err != nil {
+		appErr := repositoryError(err, "portfolio", "failed to get portfolio")
+		_ = c.Error(appErr)
+		return
+	}
+
+	if err := h.portfolios.Delete(c.Request.Context(), userID(c), id); err != nil {
+		appErr := repositoryError(err, "portfolio", "failed to delete portfolio")
+		_ = c.Error(appErr)
+		return
+	}
+
+	recordAudit(c, h.audit, models.AuditPortfolioDelete, id, portfolio, nil)
+	log.Printf("[Handler] ✓ Deleted portfolio %d", id)
+	c.Status(http.StatusNoContent)
+}
+
+// ListDeletedPortfolios handles GET /api/v1/portfolio/deleted
+func (h *PortfolioHandler) ListDeletedPortfolios(c *gin.Context) {
+	portfolios, err := h.portfolios.ListDeleted(c.Request.Context(), userID(c))
+	if err != nil {
+		appErr := repositoryError(err, "portfolio", "failed to list deleted portfolios")
+		_ = c.Error(appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": portfolios})
+}
+
+// RestorePortfolio handles POST /api/v1/portfolio/deleted/:id/restore, bringing a deleted
+// portfolio back with everything recorded under it
+func (h *PortfolioHandler) RestorePortfolio(c *gin.Context) {
+	id, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	portfolio, err := h.portfolios.Restore(c.Request.Context(), userID(c), id)
+	if err != nil {
+		appErr := repositoryError(err, "portfolio", "failed to restore portfolio")
+		_ = c.Error(appErr)
+		return
+	}
+
+	recordAudit(c, h.audit, models.AuditPortfolioRestore, id, nil, portfolio)
+	log.Printf("[Handler] ✓ Restored portfolio %d", id)
+	c.JSON(http.StatusOK, portfolio)
+}
+
+// PurgePortfolio handles DELETE /api/v1/portfolio/deleted/:id, removing a deleted portfolio
+// and its trade history for good
+func (h *PortfolioHandler) PurgePortfolio(c *gin.Context) {
+	id, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	if err := h.portfolios.Purge(c.Request.Context(), userID(c), id); err != nil {
+		appErr := repositoryError(err, "portfolio", "failed to purge portfolio")
+		_ = c.Error(appErr)
+		return
+	}
+
+	recordAudit(c, h.audit, models.AuditPortfolioPurge, id, nil, nil)
+	log.Printf("[Handler] ✓ Purged portfolio %d", id)
+	c.Status(http.StatusNoContent)
+}
+
+// repositoryError maps repository errors to API errors, logging unexpected failures
+func repositoryError(err error, resource, message string) *errors.AppError {
+	if stderrors.Is(err, repository.ErrNotFound) {
+		return errors.NewNotFoundError(resource + " not found")
+	}
+	if stderrors.Is(err, repository.ErrDuplicate) {
+		return errors.NewConflictError("a " + resource + " with that name already exists")
+	}
+	log.Printf("[Handler] ✗ %s: %v", message, err)
+	return errors.NewInternalError(message, err)
+}
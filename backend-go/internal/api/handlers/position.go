@@ -0,0 +1,485 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/analytics"
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// defaultOptionMultiplier is the standard equity option contract size
+const defaultOptionMultiplier = 100
+
+// PositionHandler handles position requests within a portfolio.
+// Every trade is recorded through the transaction ledger.
+type PositionHandler struct {
+	portfolios   repository.PortfolioRepo
+	positions    *repository.PositionRepository
+	transactions *repository.TransactionRepository
+}
+
+// NewPositionHandler creates a new position handler
+func NewPositionHandler(portfolios repository.PortfolioRepo, positions *repository.PositionRepository, transactions *repository.TransactionRepository) *PositionHandler {
+	return &PositionHandler{
+		portfolios:   portfolios,
+		positions:    positions,
+		transactions: transactions,
+	}
+}
+
+// CreatePositionRequest represents the request body for adding a position.
+// Option legs are identified by OCC ticker; strike, expiration and type are derived from it.
+type CreatePositionRequest struct {
+	AssetType  string  `json:"asset_type" binding:"required,oneof=option stock"`
+	Ticker     string  `json:"ticker" binding:"required"`
+	Side       string  `json:"side" binding:"required,oneof=long short"`
+	Quantity   float64 `json:"quantity" binding:"required,gt=0"`
+	OpenPrice  float64 `json:"open_price" binding:"gte=0"`
+	OpenedAt   string  `json:"opened_at"` // YYYY-MM-DD, defaults to today
+	Multiplier *int    `json:"multiplier" binding:"omitempty,gt=0"`
+	Fees       float64 `json:"fees" binding:"gte=0"`
+}
+
+// UpdatePositionRequest represents the request body for correcting the opening trade
+// of a position that has no later trades. Omitted fields are left unchanged.
+type UpdatePositionRequest struct {
+	Side      *string  `json:"side" binding:"omitempty,oneof=long short"`
+	Quantity  *float64 `json:"quantity" binding:"omitempty,gt=0"`
+	OpenPrice *float64 `json:"open_price" binding:"omitempty,gte=0"`
+	OpenedAt  *string  `json:"opened_at"`
+	Fees      *float64 `json:"fees" binding:"omitempty,gte=0"`
+}
+
+// ClosePositionRequest represents the request body for closing all or part of a position
+type ClosePositionRequest struct {
+	ClosePrice float64  `json:"close_price" binding:"gte=0"`
+	ClosedAt   string   `json:"closed_at"`                         // YYYY-MM-DD, defaults to today
+	Quantity   *float64 `json:"quantity" binding:"omitempty,gt=0"` // defaults to the full open quantity
+	Fees       float64  `json:"fees" binding:"gte=0"`
+}
+
+// AddToPositionRequest represents the request body for adding to an open position
+type AddToPositionRequest struct {
+	Quantity float64 `json:"quantity" binding:"required,gt=0"`
+	Price    float64 `json:"price" binding:"gte=0"`
+	Fees     float64 `json:"fees" binding:"gte=0"`
+	TradedAt string  `json:"traded_at"` // YYYY-MM-DD, defaults to today
+}
+
+// RollPositionRequest represents the request body for rolling an option leg into a
+// new contract: the old leg is closed and the replacement opened with the same side
+type RollPositionRequest struct {
+	Ticker     string   `json:"ticker" binding:"required"`         // replacement OCC symbol
+	Quantity   *float64 `json:"quantity" binding:"omitempty,gt=0"` // defaults to the full open quantity
+	ClosePrice float64  `json:"close_price" binding:"gte=0"`
+	CloseFees  float64  `json:"close_fees" binding:"gte=0"`
+	OpenPrice  float64  `json:"open_price" binding:"gte=0"`
+	OpenFees   float64  `json:"open_fees" binding:"gte=0"`
+	TradedAt   string   `json:"traded_at"` // YYYY-MM-DD, defaults to today
+}
+
+// ListPositions handles GET /api/v1/portfolio/:id/positions?status=open|closed|all
+func (h *PositionHandler) ListPositions(c *gin.Context) {
+	portfolioID, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	status := c.DefaultQuery("status", models.PositionOpen)
+	switch status {
+	case models.PositionOpen, models.PositionClosed:
+	case "all":
+		status = ""
+	default:
+		appErr := errors.NewBadRequestError("status must be one of open, closed, all", nil)
+		_ = c.Error(appErr)
+		return
+	}
+
+	if _, err := h.portfolios.Get(c.Request.Context(), userID(c), portfolioID); err != nil {
+		appErr := repositoryError(err, "portfolio", "failed to get portfolio")
+		_ = c.Error(appErr)
+		return
+	}
+
+	positions, err := h.positions.ListByPortfolio(c.Request.Context(), portfolioID, status)
+	if err != nil {
+		appErr := repositoryError(err, "position", "failed to list positions")
+		_ = c.Error(appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": positions})
+}
+
+// CreatePosition handles POST /api/v1/portfolio/:id/positions
+func (h *PositionHandler) CreatePosition(c *gin.Context) {
+	portfolioID, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	var req CreatePositionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := errors.NewBadRequestError("invalid request body", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	position, appErr := newPosition(portfolioID, &req)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	if _, err := h.portfolios.Get(c.Request.Context(), userID(c), portfolioID); err != nil {
+		appErr := repositoryError(err, "portfolio", "failed to get portfolio")
+		_ = c.Error(appErr)
+		return
+	}
+
+	if _, err := h.transactions.Open(c.Request.Context(), position, req.Fees); err != nil {
+		appErr := repositoryError(err, "position", "failed to create position")
+		_ = c.Error(appErr)
+		return
+	}
+
+	log.Printf("[Handler] ✓ Added %s %s position %d to portfolio %d", position.Side, position.Ticker, position.ID, portfolioID)
+	c.JSON(http.StatusCreated, position)
+}
+
+// UpdatePosition handles PATCH /api/v1/portfolio/:id/positions/:positionId
+func (h *PositionHandler) UpdatePosition(c *gin.Context) {
+	portfolioID, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	positionID, appErr := paramID(c, "positionId")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	var req UpdatePositionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := errors.NewBadRequestError("invalid request body", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	position, err := h.positions.Get(c.Request.Context(), portfolioID, positionID)
+	if err != nil {
+		appErr := repositoryError(err, "position", "failed to get position")
+		_ = c.Error(appErr)
+		return
+	}
+	if position.Status != models.PositionOpen {
+		appErr := errors.NewConflictError("only open positions can be updated")
+		_ = c.Error(appErr)
+		return
+	}
+
+	if req.Side != nil {
+		position.Side = *req.Side
+	}
+	if req.Quantity != nil {
+		position.Quantity = *req.Quantity
+	}
+	if req.OpenPrice != nil {
+		position.OpenPrice = *req.OpenPrice
+	}
+	if req.OpenedAt != nil {
+		if _, err := analytics.ParseDate(*req.OpenedAt); err != nil {
+			appErr := errors.NewBadRequestError("opened_at must be YYYY-MM-DD", err)
+			_ = c.Error(appErr)
+			return
+		}
+		position.OpenedAt = *req.OpenedAt
+	}
+	if req.Fees != nil {
+		position.Fees = *req.Fees
+	}
+
+	if err := h.transactions.CorrectOpen(c.Request.Context(), position); err != nil {
+		appErr := ledgerError(err, "failed to update position")
+		_ = c.Error(appErr)
+		return
+	}
+
+	log.Printf("[Handler] ✓ Updated position %d", position.ID)
+	c.JSON(http.StatusOK, position)
+}
+
+// ClosePosition handles POST /api/v1/portfolio/:id/positions/:positionId/close
+func (h *PositionHandler) ClosePosition(c *gin.Context) {
+	portfolioID, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	positionID, appErr := paramID(c, "positionId")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	var req ClosePositionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := errors.NewBadRequestError("invalid request body", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	closedAt, appErr := dateOrToday(req.ClosedAt, "closed_at")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	position, err := h.positions.Get(c.Request.Context(), portfolioID, positionID)
+	if err != nil {
+		appErr := repositoryError(err, "position", "failed to get position")
+		_ = c.Error(appErr)
+		return
+	}
+	if position.Status != models.PositionOpen {
+		appErr := errors.NewConflictError("position is already " + position.Status)
+		_ = c.Error(appErr)
+		return
+	}
+
+	trade := repository.Trade{
+		Quantity: position.Quantity,
+		Price:    req.ClosePrice,
+		Fees:     req.Fees,
+		TradedAt: closedAt,
+	}
+	if req.Quantity != nil {
+		trade.Quantity = *req.Quantity
+	}
+
+	position, _, err = h.transactions.Close(c.Request.Context(), portfolioID, positionID, trade)
+	if err != nil {
+		appErr := ledgerError(err, "failed to close position")
+		_ = c.Error(appErr)
+		return
+	}
+
+	log.Printf("[Handler] ✓ Closed %.4f of position %d at %.4f (%s)", trade.Quantity, position.ID, req.ClosePrice, position.Status)
+	c.JSON(http.StatusOK, position)
+}
+
+// AddToPosition handles POST /api/v1/portfolio/:id/positions/:positionId/add
+func (h *PositionHandler) AddToPosition(c *gin.Context) {
+	portfolioID, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	positionID, appErr := paramID(c, "positionId")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	var req AddToPositionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := errors.NewBadRequestError("invalid request body", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	tradedAt, appErr := dateOrToday(req.TradedAt, "traded_at")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	trade := repository.Trade{
+		Quantity: req.Quantity,
+		Price:    req.Price,
+		Fees:     req.Fees,
+		TradedAt: tradedAt,
+	}
+	position, _, err := h.transactions.Add(c.Request.Context(), portfolioID, positionID, trade)
+	if err != nil {
+		appErr := ledgerError(err, "failed to add to position")
+		_ = c.Error(appErr)
+		return
+	}
+
+	log.Printf("[Handler] ✓ Added %.4f to position %d at %.4f", req.Quantity, position.ID, req.Price)
+	c.JSON(http.StatusOK, position)
+}
+
+// RollPosition handles POST /api/v1/portfolio/:id/positions/:positionId/roll
+func (h *PositionHandler) RollPosition(c *gin.Context) {
+	portfolioID, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	positionID, appErr := paramID(c, "positionId")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	var req RollPositionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := errors.NewBadRequestError("invalid request body", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	tradedAt, appErr := dateOrToday(req.TradedAt, "traded_at")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	position, err := h.positions.Get(c.Request.Context(), portfolioID, positionID)
+	if err != nil {
+		appErr := repositoryError(err, "position", "failed to get position")
+		_ = c.Error(appErr)
+		return
+	}
+	if !position.IsOption() {
+		appErr := errors.NewBadRequestError("only option positions can be rolled", nil)
+		_ = c.Error(appErr)
+		return
+	}
+
+	quantity := position.Quantity
+	if req.Quantity != nil {
+		quantity = *req.Quantity
+	}
+
+	next, appErr := newPosition(portfolioID, &CreatePositionRequest{
+		AssetType:  position.AssetType,
+		Ticker:     req.Ticker,
+		Side:       position.Side,
+		Quantity:   quantity,
+		OpenPrice:  req.OpenPrice,
+		OpenedAt:   tradedAt,
+		Multiplier: &position.Multiplier,
+	})
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	if next.Ticker == position.Ticker {
+		appErr := errors.NewBadRequestError("roll must target a different contract", nil)
+		_ = c.Error(appErr)
+		return
+	}
+
+	trade := repository.Trade{
+		Quantity: quantity,
+		Price:    req.ClosePrice,
+		Fees:     req.CloseFees,
+		TradedAt: tradedAt,
+	}
+	rollOut, rollIn, err := h.transactions.Roll(c.Request.Context(), portfolioID, positionID, trade, next, req.OpenFees)
+	if err != nil {
+		appErr := ledgerError(err, "failed to roll position")
+		_ = c.Error(appErr)
+		return
+	}
+
+	log.Printf("[Handler] ✓ Rolled position %d (%s) into %d (%s)", position.ID, position.Ticker, next.ID, next.Ticker)
+	c.JSON(http.StatusCreated, gin.H{
+		"position": next,
+		"roll_out": rollOut,
+		"roll_in":  rollIn,
+	})
+}
+
+// ListLots handles GET /api/v1/portfolio/:id/positions/:positionId/lots
+func (h *PositionHandler) ListLots(c *gin.Context) {
+	portfolioID, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	positionID, appErr := paramID(c, "positionId")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	if _, err := h.positions.Get(c.Request.Context(), portfolioID, positionID); err != nil {
+		appErr := repositoryError(err, "position", "failed to get position")
+		_ = c.Error(appErr)
+		return
+	}
+
+	lots, err := h.transactions.ListLots(c.Request.Context(), portfolioID, positionID)
+	if err != nil {
+		appErr := repositoryError(err, "lot", "failed to list lots")
+		_ = c.Error(appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": lots})
+}
+
+// newPosition validates a create request and derives option fields from the OCC ticker
+func newPosition(portfolioID int64, req *CreatePositionRequest) (*models.Position, *errors.AppError) {
+	openedAt, appErr := dateOrToday(req.OpenedAt, "opened_at")
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	position := &models.Position{
+		PortfolioID: portfolioID,
+		AssetType:   req.AssetType,
+		Side:        req.Side,
+		Quantity:    req.Quantity,
+		Multiplier:  1,
+		OpenPrice:   req.OpenPrice,
+		OpenedAt:    openedAt,
+	}
+
+	if req.AssetType == models.AssetTypeOption {
+		symbol, err := models.ParseOptionTicker(req.Ticker)
+		if err != nil {
+			return nil, errors.NewBadRequestError("ticker must be an OCC option symbol (e.g. O:AAPL250117C00150000)", err)
+		}
+		position.Ticker = symbol.Ticker()
+		position.UnderlyingTicker = symbol.Underlying
+		position.ContractType = &symbol.ContractType
+		position.StrikePrice = &symbol.StrikePrice
+		position.ExpirationDate = &symbol.ExpirationDate
+		position.Multiplier = defaultOptionMultiplier
+	} else {
+		position.Ticker = strings.ToUpper(strings.TrimSpace(req.Ticker))
+		position.UnderlyingTicker = position.Ticker
+	}
+
+	if req.Multiplier != nil {
+		position.Multiplier = *req.Multiplier
+	}
+
+	return position, nil
+}
+
+// dateOrToday validates a YYYY-MM-DD date, defaulting to today's market date when empty
+func dateOrToday(date, field string) (string, *errors.AppError) {
+	if date == "" {
+		return analytics.MarketDate(time.Now()).Format("2006-01-02"), nil
+	}
+	if _, err := analytics.ParseDate(date); err != nil {
+		return "", errors.NewBadRequestError(field+" must be YYYY-MM-DD", err)
+	}
+	return date, nil
+}
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// PositionAlertHandler manages alert rules attached to positions
+type PositionAlertHandler struct {
+	positions *repository.PositionRepository
+	alerts    *repository.PositionAlertRepository
+}
+
+// NewPositionAlertHandler creates a new position alert handler
+func NewPositionAlertHandler(positions *repository.PositionRepository, alerts *repository.PositionAlertRepository) *PositionAlertHandler {
+	return &PositionAlertHandler{
+		positions: positions,
+		alerts:    alerts,
+	}
+}
+
+// CreatePositionAlertRequest represents the request body for attaching an alert rule,
+// e.g. {"metric": "delta", "operator": ">", "threshold": 0.40}
+type CreatePositionAlertRequest struct {
+	Metric    string   `json:"metric" binding:"required,oneof=delta gamma theta vega implied_volatility mark_price underlying_price pnl pnl_percent days_to_expiration"`
+	Operator  string   `json:"operator" binding:"required,oneof=> >= < <="`
+	Threshold *float64 `json:"threshold" binding:"required"`
+	Note      *string  `json:"note" binding:"omitempty,max=500"`
+}
+
+// UpdatePositionAlertRequest represents the request body for changing an alert rule.
+// Omitted fields are left unchanged; status "armed" re-arms a triggered alert.
+type UpdatePositionAlertRequest struct {
+	Metric    *string  `json:"metric" binding:"omitempty,oneof=delta gamma theta vega implied_volatility mark_price underlying_price pnl pnl_percent days_to_expiration"`
+	Operator  *string  `json:"operator" binding:"omitempty,oneof=> >= < <="`
+	Threshold *float64 `json:"threshold"`
+	Note      *string  `json:"note" binding:"omitempty,max=500"`
+	Status    *string  `json:"status" binding:"omitempty,oneof=armed disabled"`
+}
+
+// ListAlerts handles GET /api/v1/portfolio/:id/alerts?position_id=
+func (h *PositionAlertHandler) ListAlerts(c *gin.Context) {
+	portfolioID, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	positionID, appErr := queryInt(c, "position_id", 0)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	alerts, err := h.alerts.List(c.Request.Context(), portfolioID, int64(positionID))
+	if err != nil {
+		appErr := repositoryError(err, "alert", "failed to list alerts")
+		_ = c.Error(appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": alerts})
+}
+
+// CreateAlert handles POST /api/v1/portfolio/:id/positions/:positionId/alerts
+func (h *PositionAlertHandler) CreateAlert(c *gin.Context) {
+	portfolioID, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	positionID, appErr := paramID(c, "positionId")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	var req CreatePositionAlertRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := errors.NewBadRequestError("invalid request body", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	position, err := h.positions.Get(c.Request.Context(), portfolioID, positionID)
+	if err != nil {
+		appErr := repositoryError(err, "position", "failed to get position")
+		_ = c.Error(appErr)
+		return
+	}
+	if position.Status != models.PositionOpen {
+		appErr := errors.NewConflictError("alerts can only be attached to open positions")
+		_ = c.Error(appErr)
+		return
+	}
+
+	alert := &models.PositionAlert{
+		PortfolioID: portfolioID,
+		PositionID:  positionID,
+		Metric:      req.Metric,
+		Operator:    req.Operator,
+		Threshold:   *req.Threshold,
+		Note:        req.Note,
+	}
+	if err := h.alerts.Create(c.Request.Context(), alert); err != nil {
+		appErr := repositoryError(err, "alert", "failed to create alert")
+		_ = c.Error(appErr)
+		return
+	}
+
+	log.Printf("[Handler] ✓ Created alert %d on position %d: %s %s %g", alert.ID, positionID, alert.Metric, alert.Operator, alert.Threshold)
+	c.JSON(http.StatusCreated, alert)
+}
+
+// UpdateAlert handles PATCH /api/v1/portfolio/:id/alerts/:alertId
+func (h *PositionAlertHandler) UpdateAlert(c *gin.Context) {
+	portfolioID, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	alertID, appErr := paramID(c, "alertId")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	var req UpdatePositionAlertRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := errors.NewBadRequestError("invalid request body", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	alert, err := h.alerts.Get(c.Request.Context(), portfolioID, alertID)
+	if err != nil {
+		appErr := repositoryError(err, "alert", "failed to get alert")
+		_ = c.Error(appErr)
+		return
+	}
+
+	if req.Metric != nil {
+		alert.Metric = *req.Metric
+	}
+	if req.Operator != nil {
+		alert.Operator = *req.Operator
+	}
+	if req.Threshold != nil {
+		alert.Threshold = *req.Threshold
+	}
+	if req.Note != nil {
+		alert.Note = req.Note
+	}
+	if req.Status != nil {
+		alert.Status = *req.Status
+	}
+
+	if err := h.alerts.Update(c.Request.Context(), alert); err != nil {
+		appErr := repositoryError(err, "alert", "failed to update alert")
+		_ = c.Error(appErr)
+		return
+	}
+
+	log.Printf("[Handler] ✓ Updated alert %d (%s)", alert.ID, alert.Status)
+	c.JSON(http.StatusOK, alert)
+}
+
+// DeleteAlert handles DELETE /api/v1/portfolio/:id/alerts/:alertId
+func (h *PositionAlertHandler) DeleteAlert(c *gin.Context) {
+	portfolioID, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	alertID, appErr := paramID(c, "alertId")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	if err := h.alerts.Delete(c.Request.Context(), portfolioID, alertID); err != nil {
+		appErr := repositoryError(err, "alert", "failed to delete alert")
+		_ = c.Error(appErr)
+		return
+	}
+
+	log.Printf("[Handler] ✓ Deleted alert %d", alertID)
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/services"
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/gin-gonic/gin"
+)
+
+// stockSymbolPattern matches the stock tickers a quote stream accepts
+var stockSymbolPattern = regexp.MustCompile(`^[A-Z][A-Z0-9.]{0,9}$`)
+
+// quoteStreamRequest is a message from a quote stream client
+type quoteStreamRequest struct {
+	Action  string   `json:"action"` // subscribe or unsubscribe
+	Symbols []string `json:"symbols"`
+}
+
+// quoteStreamMessage is a message to a quote stream client
+type quoteStreamMessage struct {
+	Type    string   `json:"type"` // subscribed, quote, contract or error
+	Symbols []string `json:"symbols,omitempty"`
+	Data    any      `json:"data,omitempty"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// QuoteStreamHandler pushes quote updates to the frontend over WebSocket
+type QuoteStreamHandler struct {
+	stream     *services.QuoteStream
+	maxSymbols int
+	origins    []string
+}
+
+// NewQuoteStreamHandler creates a new quote stream handler. Browsers on origins other than
+// the API's own and those listed are refused, so another site cannot open a stream with a
+// signed-in user's cookie.
+func NewQuoteStreamHandler(stream *services.QuoteStream, maxSymbols int, origins []string) *QuoteStreamHandler {
+	return &QuoteStreamHandler{
+		stream:     stream,
+		maxSymbols: maxSymbols,
+		origins:    origins,
+	}
+}
+
+// StreamQuotes handles GET /api/v1/stream, a WebSocket pushing quote updates. Clients send
+// {"action": "subscribe" | "unsubscribe", "symbols": [...]} with stock tickers or OCC option
+// tickers (O:...), and are answered with {"type": "subscribed", "symbols": [...]} listing
+// every symbol they follow. Each followed symbol is then sent as {"type": "quote"} (stocks)
+// or {"type": "contract"} (options) with the watchlist quote shape as data, once on
+// subscribing and again whenever it changes. A rejected message is answered with
+// {"type": "error"}.
+func (h *QuoteStreamHandler) StreamQuotes(c *gin.Context) {
+	// The server's read and write timeouts would otherwise close the connection
+	rc := http.NewResponseController(c.Writer)
+	_ = rc.SetReadDeadline(time.Time{})
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	conn, err := websocket.Accept(upgradeWriter{c.Writer}, c.Request, &websocket.AcceptOptions{OriginPatterns: h.origins})
+	if err != nil {
+		log.Printf("[Handler] ✗ Failed to open quote stream: %v", err)
+		return
+	}
+	defer conn.CloseNow()
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+	sub := h.stream.Subscribe()
+	defer sub.Close()
+	log.Println("[Handler] ✓ Opened quote stream")
+
+	write := func(msg quoteStreamMessage) bool {
+		writeCtx, cancelWrite := context.WithTimeout(ctx, streamWriteTimeout)
+		defer cancelWrite()
+		return wsjson.Write(writeCtx, conn, msg) == nil
+	}
+
+	// Client messages are read on their own goroutine, which also answers pings
+	go func() {
+		defer cancel()
+		for {
+			_, data, err := conn.Read(ctx)
+			if err != nil {
+				return
+			}
+			msg := quoteStreamMessage{Type: "error", Error: "invalid message"}
+			var req quoteStreamRequest
+			if json.Unmarshal(data, &req) == nil {
+				msg = h.apply(sub, req)
+			}
+			if !write(msg) {
+				return
+			}
+		}
+	}()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			conn.Close(websocket.StatusNormalClosure, "")
+			return
+		case u, ok := <-sub.Updates():
+			if !ok {
+				// Dropped for falling behind; the client reconnects and resubscribes
+				conn.Close(websocket.StatusTryAgainLater, "too far behind")
+				return
+			}
+			msg := quoteStreamMessage{Type: "quote", Data: u.Stock}
+			if u.Contract != nil {
+				msg = quoteStreamMessage{Type: "contract", Data: u.Contract}
+			}
+			if !write(msg) {
+				return
+			}
+		case <-heartbeat.C:
+			pingCtx, cancelPing := context.WithTimeout(ctx, streamWriteTimeout)
+			err := conn.Ping(pingCtx)
+			cancelPing()
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+// upgradeWriter hands a WebSocket upgrade gin's writer without its WriteHeaderNow method,
+// which the library calls for gin but after which gin refuses to hijack. A 101 status also goes
+// straight to net/http, which sends it on hijack, and hijacking through gin marks the response
+// written so gin doesn't write one after the handler returns.
+type upgradeWriter struct {
+	w gin.ResponseWriter
+}
+
+func (u upgradeWriter) Header() http.Header { return u.w.Header() }
+
+func (u upgradeWriter) Write(p []byte) (int, error) { return u.w.Write(p) }
+
+func (u upgradeWriter) WriteHeader(code int) {
+	u.w.WriteHeader(code) // for the access log and metrics
+	if code != http.StatusSwitchingProtocols {
+		return
+	}
+	var raw http.ResponseWriter = u.w
+	for {
+		unwrapper, ok := raw.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			break
+		}
+		raw = unwrapper.Unwrap()
+	}
+	raw.WriteHeader(code)
+}
+
+func (u upgradeWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) { return u.w.Hijack() }
+
+// apply carries out a client's subscribe or unsubscribe request and returns the reply
+func (h *QuoteStreamHandler) apply(sub *services.QuoteSubscription, req quoteStreamRequest) quoteStreamMessage {
+	symbols := make([]string, 0, len(req.Symbols))
+	for _, symbol := range req.Symbols {
+		symbol = strings.ToUpper(strings.TrimSpace(symbol))
+		if strings.HasPrefix(symbol, "O:") {
+			if _, err := models.ParseOptionTicker(symbol); err != nil {
+				return quoteStreamMessage{Type: "error", Error: fmt.Sprintf("invalid option ticker %q", symbol)}
+			}
+		} else if !stockSymbolPattern.MatchString(symbol) {
+			return quoteStreamMessage{Type: "error", Error: fmt.Sprintf("invalid ticker %q", symbol)}
+		}
+		symbols = append(symbols, symbol)
+	}
+
+	switch req.Action {
+	case "subscribe":
+		following := make(map[string]struct{})
+		for _, symbol := range sub.Symbols() {
+			following[symbol] = struct{}{}
+		}
+		for _, symbol := range symbols {
+			following[symbol] = struct{}{}
+		}
+		if len(following) > h.maxSymbols {
+			return quoteStreamMessage{Type: "error", Error: fmt.Sprintf("at most %d symbols may be followed", h.maxSymbols)}
+		}
+		sub.Add(symbols)
+	case "unsubscribe":
+		sub.Remove(symbols)
+	default:
+		return quoteStreamMessage{Type: "error", Error: "action must be subscribe or unsubscribe"}
+	}
+	return quoteStreamMessage{Type: "subscribed", Symbols: sub.Symbols()}
+}
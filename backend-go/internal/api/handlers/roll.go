@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/aaronbengochea/periscope/backend-go/internal/services"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// Roll scan limits
+const (
+	defaultRollMaxDTE = 90
+	maxRollMaxDTE     = 730
+	defaultRollLimit  = 10
+	maxRollLimit      = 50
+)
+
+// RollHandler suggests rolls for short option legs
+type RollHandler struct {
+	positions *repository.PositionRepository
+	rolls     *services.RollService
+}
+
+// NewRollHandler creates a new roll suggestion handler
+func NewRollHandler(positions *repository.PositionRepository, rolls *services.RollService) *RollHandler {
+	return &RollHandler{
+		positions: positions,
+		rolls:     rolls,
+	}
+}
+
+// GetRollSuggestions handles GET /api/v1/portfolio/:id/positions/:positionId/rolls?max_dte=90&limit=10&credit_only=true
+func (h *RollHandler) GetRollSuggestions(c *gin.Context) {
+	portfolioID, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	positionID, appErr := paramID(c, "positionId")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	maxDTE, appErr := queryInt(c, "max_dte", defaultRollMaxDTE)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	limit, appErr := queryInt(c, "limit", defaultRollLimit)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	if maxDTE < 1 || maxDTE > maxRollMaxDTE || limit < 1 || limit > maxRollLimit {
+		appErr := errors.NewBadRequestError("max_dte must be 1-730 and limit 1-50", nil)
+		_ = c.Error(appErr)
+		return
+	}
+
+	position, err := h.positions.Get(c.Request.Context(), portfolioID, positionID)
+	if err != nil {
+		appErr := repositoryError(err, "position", "failed to get position")
+		_ = c.Error(appErr)
+		return
+	}
+	if !position.IsOption() || position.Side != models.SideShort {
+		appErr := errors.NewBadRequestError("roll suggestions are only available for short option legs", nil)
+		_ = c.Error(appErr)
+		return
+	}
+	if position.Status != models.PositionOpen {
+		appErr := errors.NewConflictError("only open positions can be rolled")
+		_ = c.Error(appErr)
+		return
+	}
+
+	suggestions, err := h.rolls.Suggest(c.Request.Context(), position, services.RollParams{
+		MaxDTE:     maxDTE,
+		Limit:      limit,
+		CreditOnly: c.Query("credit_only") == "true",
+	})
+	if err != nil {
+		log.Printf("[Handler] ✗ Failed to suggest rolls for position %d: %v", positionID, err)
+		appErr := errors.NewInternalError("failed to suggest rolls", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, suggestions)
+}
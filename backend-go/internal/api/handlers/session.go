@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"log"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/api/middleware"
+	"github.com/aaronbengochea/periscope/backend-go/internal/auth"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// refreshCookieMaxAge is how long a browser keeps the refresh token, in seconds. Each refresh
+// rotates the token and renews the cookie, so only sessions idle this long sign out.
+const refreshCookieMaxAge = 30 * 24 * 60 * 60
+
+// SessionHandler signs the frontend in and out through Supabase Auth, keeping the tokens in
+// HttpOnly cookies out of reach of page scripts
+type SessionHandler struct {
+	sessions      *auth.SessionClient
+	secureCookies bool
+}
+
+// NewSessionHandler creates a new session handler. sessions is nil when no Supabase anon key
+// is configured, which disables sign-in; secureCookies restricts the cookies to HTTPS.
+func NewSessionHandler(sessions *auth.SessionClient, secureCookies bool) *SessionHandler {
+	return &SessionHandler{
+		sessions:      sessions,
+		secureCookies: secureCookies,
+	}
+}
+
+// LoginRequest represents the request body for signing in with an email and password
+type LoginRequest struct {
+	Email    string `json:"email" binding:"required,email,max=320"`
+	Password string `json:"password" binding:"required,max=256"`
+}
+
+// SessionResponse describes the signed-in session; its tokens are only set as cookies
+type SessionResponse struct {
+	User      auth.SessionUser `json:"user"`
+	ExpiresAt int64            `json:"expires_at"` // when the access token expires, in Unix seconds
+}
+
+// Login handles POST /api/v1/auth/login
+func (h *SessionHandler) Login(c *gin.Context) {
+	var req LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := errors.NewBadRequestError("invalid request body", err)
+		_ = c.Error(appErr)
+		return
+	}
+	if h.sessions == nil {
+		appErr := errors.NewServiceUnavailableError("sign-in is not configured")
+		_ = c.Error(appErr)
+		return
+	}
+
+	session, err := h.sessions.Login(c.Request.Context(), strings.TrimSpace(req.Email), req.Password)
+	if err != nil {
+		appErr := sessionError(err, "failed to sign in")
+		_ = c.Error(appErr)
+		return
+	}
+
+	h.setCookies(c, session)
+	log.Printf("[Handler] ✓ Signed in user %s", session.User.ID)
+	c.JSON(http.StatusOK, SessionResponse{User: session.User, ExpiresAt: session.ExpiresAt})
+}
+
+// Refresh handles POST /api/v1/auth/refresh, exchanging the refresh token cookie for a new
+// token pair. Call it shortly before the access token expires.
+func (h *SessionHandler) Refresh(c *gin.Context) {
+	if h.sessions == nil {
+		appErr := errors.NewServiceUnavailableError("sign-in is not configured")
+		_ = c.Error(appErr)
+		return
+	}
+	refreshToken, err := c.Cookie(middleware.RefreshTokenCookie)
+	if err != nil || refreshToken == "" {
+		appErr := errors.NewUnauthorizedError("not signed in")
+		_ = c.Error(appErr)
+		return
+	}
+
+	session, err := h.sessions.Refresh(c.Request.Context(), refreshToken)
+	if err != nil {
+		if stderrors.Is(err, auth.ErrSessionExpired) {
+			h.clearCookies(c)
+		}
+		appErr := sessionError(err, "failed to refresh session")
+		_ = c.Error(appErr)
+		return
+	}
+
+	h.setCookies(c, session)
+	c.JSON(http.StatusOK, SessionResponse{User: session.User, ExpiresAt: session.ExpiresAt})
+}
+
+// Logout handles POST /api/v1/auth/logout, revoking the session and clearing its cookies.
+// Signing out without a session succeeds too.
+func (h *SessionHandler) Logout(c *gin.Context) {
+	accessToken, _ := c.Cookie(middleware.AccessTokenCookie)
+	if scheme, token, ok := strings.Cut(c.GetHeader("Authorization"), " "); ok && strings.EqualFold(scheme, "Bearer") {
+		accessToken = strings.TrimSpace(token)
+	}
+	if h.sessions != nil && accessToken != "" && !auth.IsAPIKey(accessToken) {
+		if err := h.sessions.Logout(c.Request.Context(), accessToken); err != nil {
+			// The cookies are cleared regardless; the access token lapses on its own
+			log.Printf("[Handler] ⚠ Failed to revoke session: %v", err)
+		}
+	}
+
+	h.clearCookies(c)
+	log.Println("[Handler] ✓ Signed out")
+	c.Status(http.StatusNoContent)
+}
+
+// setCookies stores a session's tokens: the access token for every API request until it
+// expires, and the refresh token for the auth endpoints of the API version signed in through
+func (h *SessionHandler) setCookies(c *gin.Context, s *auth.Session) {
+	c.SetSameSite(http.SameSiteStrictMode)
+	c.SetCookie(middleware.AccessTokenCookie, s.AccessToken, s.ExpiresIn, "/api", "", h.secureCookies, true)
+	c.SetCookie(middleware.RefreshTokenCookie, s.RefreshToken, refreshCookieMaxAge, refreshCookiePath(c), "", h.secureCookies, true)
+}
+
+// clearCookies expires the session cookies
+func (h *SessionHandler) clearCookies(c *gin.Context) {
+	c.SetSameSite(http.SameSiteStrictMode)
+	c.SetCookie(middleware.AccessTokenCookie, "", -1, "/api", "", h.secureCookies, true)
+	c.SetCookie(middleware.RefreshTokenCookie, "", -1, refreshCookiePath(c), "", h.secureCookies, true)
+}
+
+// refreshCookiePath is the auth route group the request came through, such as /api/v2/auth,
+// so the refresh token goes back to the refresh endpoint of the same API version
+func refreshCookiePath(c *gin.Context) string {
+	return path.Dir(c.FullPath())
+}
+
+// sessionError maps a Supabase Auth failure to an API error
+func sessionError(err error, message string) *errors.AppError {
+	switch {
+	case stderrors.Is(err, auth.ErrInvalidCredentials), stderrors.Is(err, auth.ErrSessionExpired):
+		return errors.NewUnauthorizedError(err.Error())
+	case stderrors.Is(err, auth.ErrAuthRateLimited):
+		return errors.NewRateLimitError(err.Error())
+	}
+	log.Printf("[Handler] ✗ %s: %v", message, err)
+	return errors.NewServiceUnavailableError("authentication service unavailable")
+}
@@ -0,0 +1,141 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/api/handlers"
+	"github.com/aaronbengochea/periscope/backend-go/internal/api/middleware"
+	"github.com/aaronbengochea/periscope/backend-go/internal/auth"
+	"github.com/gin-gonic/gin"
+)
+
+// fakeSupabaseAuth issues sessions whose refresh tokens can each be used once, as Supabase
+// Auth does
+type fakeSupabaseAuth struct {
+	mu      sync.Mutex
+	issued  int
+	current string // the refresh token that may be exchanged next
+}
+
+func (f *fakeSupabaseAuth) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var body map[string]string
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	switch {
+	case r.URL.Path == "/auth/v1/logout":
+		f.current = ""
+		w.WriteHeader(http.StatusNoContent)
+		return
+	case r.URL.Path != "/auth/v1/token":
+		http.NotFound(w, r)
+		return
+	case r.URL.Query().Get("grant_type") == "password":
+		if body["password"] != "secret" {
+			http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+	case r.URL.Query().Get("grant_type") == "refresh_token":
+		if body["refresh_token"] == "" || body["refresh_token"] != f.current {
+			http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+	}
+
+	f.issued++
+	f.current = fmt.Sprintf("refresh-%d", f.issued)
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(auth.Session{
+		AccessToken:  fmt.Sprintf("access-%d", f.issued),
+		RefreshToken: f.current,
+		ExpiresIn:    3600,
+		ExpiresAt:    int64(1_800_000_000 + f.issued),
+		User:         auth.SessionUser{ID: "user-1", Email: "user@example.com"},
+	})
+}
+
+// sessionServer serves the session endpoints under both API versions, as the router does
+func sessionServer(t *testing.T) *httptest.Server {
+	supabase := httptest.NewServer(&fakeSupabaseAuth{})
+	t.Cleanup(supabase.Close)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.Errors())
+	h := handlers.NewSessionHandler(auth.NewSessionClient(supabase.URL, "anon-key"), false)
+	for _, api := range []*gin.RouterGroup{router.Group("/api/v1"), router.Group("/api/v2", middleware.EnvelopeV2())} {
+		session := api.Group("/auth")
+		session.POST("/login", h.Login)
+		session.POST("/refresh", h.Refresh)
+		session.POST("/logout", h.Logout)
+	}
+
+	srv := httptest.NewServer(router)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func post(t *testing.T, client *http.Client, url, body string) (int, []byte) {
+	t.Helper()
+	resp, err := client.Post(url, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var raw json.RawMessage
+	_ = json.NewDecoder(resp.Body).Decode(&raw)
+	return resp.StatusCode, raw
+}
+
+// TestSessionRoundTrip signs in, refreshes twice and signs out through each API version,
+// relying on the browser's cookie rules to send the refresh token back
+func TestSessionRoundTrip(t *testing.T) {
+	for _, version := range []string{"v1", "v2"} {
+		t.Run(version, func(t *testing.T) {
+			srv := sessionServer(t)
+			jar, _ := cookiejar.New(nil)
+			client := &http.Client{Jar: jar}
+			base := srv.URL + "/api/" + version + "/auth"
+
+			status, body := post(t, client, base+"/login", `{"email":"user@example.com","password":"secret"}`)
+			if status != http.StatusOK {
+				t.Fatalf("login: got %d %s", status, body)
+			}
+			for i := 2; i <= 3; i++ {
+				status, body = post(t, client, base+"/refresh", "")
+				if status != http.StatusOK {
+					t.Fatalf("refresh %d: got %d %s", i, status, body)
+				}
+				var session handlers.SessionResponse
+				if version == "v2" {
+					var envelope middleware.Envelope
+					if err := json.Unmarshal(body, &envelope); err != nil {
+						t.Fatal(err)
+					}
+					body = envelope.Data
+				}
+				if err := json.Unmarshal(body, &session); err != nil {
+					t.Fatal(err)
+				}
+				if session.User.ID != "user-1" || session.ExpiresAt != int64(1_800_000_000+i) {
+					t.Errorf("refresh %d: got %+v", i, session)
+				}
+			}
+
+			if status, body = post(t, client, base+"/logout", ""); status != http.StatusNoContent {
+				t.Fatalf("logout: got %d %s", status, body)
+			}
+			if status, body = post(t, client, base+"/refresh", ""); status != http.StatusUnauthorized {
+				t.Errorf("refresh after logout: got %d %s, want 401", status, body)
+			}
+		})
+	}
+}
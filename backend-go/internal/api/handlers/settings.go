@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// SettingsHandler serves the signed-in user's preferences
+type SettingsHandler struct {
+	settings *repository.SettingsRepository
+	audit    *repository.AuditRepository
+}
+
+// NewSettingsHandler creates a new settings handler
+func NewSettingsHandler(settings *repository.SettingsRepository, audit *repository.AuditRepository) *SettingsHandler {
+	return &SettingsHandler{settings: settings, audit: audit}
+}
+
+// SettingsRequest represents the request body for the user's settings, e.g.
+// {"expiration_window_days": 60, "greeks_display": "per_contract", "theme": "dark",
+// "alert_mode": "recurring", "alert_cooldown_seconds": 1800}. The settings are replaced:
+// omitted fields take their defaults, and a null risk_free_rate uses the server's rate.
+type SettingsRequest struct {
+	ExpirationWindowDays *int     `json:"expiration_window_days" binding:"omitempty,gte=1,lte=730"`
+	GreeksDisplay        *string  `json:"greeks_display" binding:"omitempty,oneof=per_share per_contract"`
+	SecondOrderGreeks    *bool    `json:"second_order_greeks"`
+	RiskFreeRate         *float64 `json:"risk_free_rate" binding:"omitempty,gte=0,lte=0.25"`
+	Theme                *string  `json:"theme" binding:"omitempty,oneof=system light dark"`
+	AlertMode            *string  `json:"alert_mode" binding:"omitempty,oneof=one_shot recurring"`
+	AlertCooldownSeconds *int     `json:"alert_cooldown_seconds" binding:"omitempty,gte=0,lte=604800"`
+}
+
+// GetSettings handles GET /api/v1/me/settings
+func (h *SettingsHandler) GetSettings(c *gin.Context) {
+	settings, err := h.settings.Get(c.Request.Context(), userID(c))
+	if err != nil {
+		appErr := repositoryError(err, "settings", "failed to get settings")
+		_ = c.Error(appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// SetSettings handles PUT /api/v1/me/settings
+func (h *SettingsHandler) SetSettings(c *gin.Context) {
+	var req SettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := errors.NewBadRequestError("invalid request body", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	before, err := h.settings.Get(c.Request.Context(), userID(c))
+	if err != nil {
+		appErr := repositoryError(err, "settings", "failed to get settings")
+		_ = c.Error(appErr)
+		return
+	}
+
+	settings := models.DefaultUserSettings(userID(c))
+	if req.ExpirationWindowDays != nil {
+		settings.ExpirationWindowDays = *req.ExpirationWindowDays
+	}
+	if req.GreeksDisplay != nil {
+		settings.GreeksDisplay = *req.GreeksDisplay
+	}
+	if req.SecondOrderGreeks != nil {
+		settings.SecondOrderGreeks = *req.SecondOrderGreeks
+	}
+	settings.RiskFreeRate = req.RiskFreeRate
+	if req.Theme != nil {
+		settings.Theme = *req.Theme
+	}
+	if req.AlertMode != nil {
+		settings.AlertMode = *req.AlertMode
+	}
+	if req.AlertCooldownSeconds != nil {
+		settings.AlertCooldownSeconds = *req.AlertCooldownSeconds
+	}
+
+	if err := h.settings.Save(c.Request.Context(), settings); err != nil {
+		appErr := repositoryError(err, "settings", "failed to save settings")
+		_ = c.Error(appErr)
+		return
+	}
+
+	recordAudit(c, h.audit, models.AuditSettingsSave, nil, before, settings)
+	log.Println("[Handler] ✓ Saved settings")
+	c.JSON(http.StatusOK, settings)
+}
@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/aaronbengochea/periscope/backend-go/internal/services"
+	"github.com/aaronbengochea/periscope/backend-go/internal/sharelink"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// defaultShareLinkDays is how long a share link lasts when the request does not say
+const defaultShareLinkDays = 7
+
+// sharedPath is the route prefix of the read-only shared portfolio views
+const sharedPath = "/api/v1/shared/"
+
+// ShareLinkHandler issues read-only portfolio share links and serves the views they open
+type ShareLinkHandler struct {
+	portfolios repository.PortfolioRepo
+	links      *repository.ShareLinkRepository
+	valuation  *services.ValuationService
+	signer     *sharelink.Signer // nil when SHARE_LINK_SECRET is not configured
+}
+
+// NewShareLinkHandler creates a new share link handler. A nil signer disables share links.
+func NewShareLinkHandler(portfolios repository.PortfolioRepo, links *repository.ShareLinkRepository, valuation *services.ValuationService, signer *sharelink.Signer) *ShareLinkHandler {
+	return &ShareLinkHandler{
+		portfolios: portfolios,
+		links:      links,
+		valuation:  valuation,
+		signer:     signer,
+	}
+}
+
+// CreateShareLinkRequest represents the request body for sharing a portfolio
+type CreateShareLinkRequest struct {
+	Label         *string `json:"label" binding:"omitempty,max=200"`
+	ExpiresInDays *int    `json:"expires_in_days" binding:"omitempty,gte=1,lte=365"`
+	HideCostBasis bool    `json:"hide_cost_basis"`
+}
+
+// CreateShareLink handles POST /api/v1/portfolio/:id/share-links
+func (h *ShareLinkHandler) CreateShareLink(c *gin.Context) {
+	if appErr := h.requireSigner(); appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	portfolioID, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	var req CreateShareLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := errors.NewBadRequestError("invalid request body", err)
+		_ = c.Error(appErr)
+		return
+	}
+	days := defaultShareLinkDays
+	if req.ExpiresInDays != nil {
+		days = *req.ExpiresInDays
+	}
+
+	if _, err := h.portfolios.Get(c.Request.Context(), userID(c), portfolioID); err != nil {
+		appErr := repositoryError(err, "portfolio", "failed to get portfolio")
+		_ = c.Error(appErr)
+		return
+	}
+
+	link := &models.ShareLink{
+		PortfolioID:   portfolioID,
+		Label:         req.Label,
+		HideCostBasis: req.HideCostBasis,
+		ExpiresAt:     time.Now().Add(time.Duration(days) * 24 * time.Hour).Truncate(time.Second),
+	}
+	if err := h.links.Create(c.Request.Context(), link); err != nil {
+		appErr := repositoryError(err, "share link", "failed to create share link")
+		_ = c.Error(appErr)
+		return
+	}
+	h.attachToken(link)
+
+	log.Printf("[Handler] ✓ Created share link %d for portfolio %d, expires %s", link.ID, portfolioID, link.ExpiresAt.Format(time.RFC3339))
+	c.JSON(http.StatusCreated, link)
+}
+
+// ListShareLinks handles GET /api/v1/portfolio/:id/share-links
+func (h *ShareLinkHandler) ListShareLinks(c *gin.Context) {
+	portfolioID, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	links, err := h.links.List(c.Request.Context(), portfolioID)
+	if err != nil {
+		appErr := repositoryError(err, "share link", "failed to list share links")
+		_ = c.Error(appErr)
+		return
+	}
+	now := time.Now()
+	for i := range links {
+		if links[i].Active(now) {
+			h.attachToken(&links[i])
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": links})
+}
+
+// RevokeShareLink handles DELETE /api/v1/portfolio/:id/share-links/:linkId
+func (h *ShareLinkHandler) RevokeShareLink(c *gin.Context) {
+	portfolioID, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	linkID, appErr := paramID(c, "linkId")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	if err := h.links.Revoke(c.Request.Context(), portfolioID, linkID); err != nil {
+		appErr := repositoryError(err, "share link", "failed to revoke share link")
+		_ = c.Error(appErr)
+		return
+	}
+
+	log.Printf("[Handler] ✓ Revoked share link %d of portfolio %d", linkID, portfolioID)
+	c.Status(http.StatusNoContent)
+}
+
+// GetSharedPortfolio handles GET /api/v1/shared/:token, the read-only portfolio view
+func (h *ShareLinkHandler) GetSharedPortfolio(c *gin.Context) {
+	link, portfolio, appErr := h.resolve(c)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	valuation, err := h.valuation.ValuePortfolio(c.Request.Context(), portfolio.ID)
+	if err != nil {
+		log.Printf("[Handler] ✗ Failed to value shared portfolio %d: %v", portfolio.ID, err)
+		appErr := errors.NewInternalError("failed to value portfolio", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, sharelink.View(portfolio, valuation, link))
+}
+
+// GetSharedGreeks handles GET /api/v1/shared/:token/greeks
+func (h *ShareLinkHandler) GetSharedGreeks(c *gin.Context) {
+	_, portfolio, appErr := h.resolve(c)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	greeks, err := h.valuation.PortfolioGreeks(c.Request.Context(), portfolio.ID)
+	if err != nil {
+		log.Printf("[Handler] ✗ Failed to aggregate greeks for shared portfolio %d: %v", portfolio.ID, err)
+		appErr := errors.NewInternalError("failed to aggregate greeks", err)
+		_ = c.Error(appErr)
+		return
+	}
+	// Only the portfolio totals are shared; the per-position breakdown carries internal IDs
+	greeks.PortfolioID = 0
+	greeks.Positions = nil
+
+	c.JSON(http.StatusOK, greeks)
+}
+
+// resolve verifies the :token path parameter and loads the active link and its portfolio,
+// recording the access. Bad, expired and revoked tokens are all reported as not found.
+func (h *ShareLinkHandler) resolve(c *gin.Context) (*models.ShareLink, *models.Portfolio, *errors.AppError) {
+	if appErr := h.requireSigner(); appErr != nil {
+		return nil, nil, appErr
+	}
+	notFound := errors.NewNotFoundError("share link not found or expired")
+
+	now := time.Now()
+	claims, err := h.signer.Verify(c.Param("token"), now)
+	if err != nil {
+		return nil, nil, notFound
+	}
+
+	ctx := c.Request.Context()
+	link, err := h.links.Get(ctx, claims.PortfolioID, claims.LinkID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return nil, nil, notFound
+		}
+		return nil, nil, repositoryError(err, "share link", "failed to get share link")
+	}
+	if !link.Active(now) {
+		return nil, nil, notFound
+	}
+
+	portfolio, err := h.portfolios.GetAny(ctx, link.PortfolioID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return nil, nil, notFound
+		}
+		return nil, nil, repositoryError(err, "portfolio", "failed to get portfolio")
+	}
+
+	if err := h.links.RecordAccess(ctx, link.ID); err != nil {
+		log.Printf("[Handler] ⚠ %v", err)
+	}
+	return link, portfolio, nil
+}
+
+// attachToken signs the link's token and sets its shared view URL
+func (h *ShareLinkHandler) attachToken(link *models.ShareLink) {
+	if h.signer == nil {
+		return
+	}
+	link.Token = h.signer.Sign(sharelink.Claims{
+		LinkID:      link.ID,
+		PortfolioID: link.PortfolioID,
+		ExpiresAt:   link.ExpiresAt.Unix(),
+	})
+	link.URL = sharedPath + link.Token
+}
+
+// requireSigner rejects share link requests when no signing secret is configured
+func (h *ShareLinkHandler) requireSigner() *errors.AppError {
+	if h.signer == nil {
+		return errors.NewServiceUnavailableError("share links are not configured")
+	}
+	return nil
+}
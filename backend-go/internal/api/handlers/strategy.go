@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/aaronbengochea/periscope/backend-go/internal/services"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// StrategyHandler groups positions into named, tagged strategies and values them
+type StrategyHandler struct {
+	portfolios repository.PortfolioRepo
+	strategies *repository.StrategyRepository
+	valuation  *services.ValuationService
+}
+
+// NewStrategyHandler creates a new strategy handler
+func NewStrategyHandler(portfolios repository.PortfolioRepo, strategies *repository.StrategyRepository, valuation *services.ValuationService) *StrategyHandler {
+	return &StrategyHandler{
+		portfolios: portfolios,
+		strategies: strategies,
+		valuation:  valuation,
+	}
+}
+
+// CreateStrategyRequest represents the request body for grouping positions into a strategy,
+// e.g. {"name": "AAPL iron condor Jan", "tags": ["income"], "position_ids": [1, 2, 3, 4]}
+type CreateStrategyRequest struct {
+	Name        string   `json:"name" binding:"required,max=200"`
+	Tags        []string `json:"tags" binding:"omitempty,max=20,dive,max=50"`
+	Notes       *string  `json:"notes" binding:"omitempty,max=2000"`
+	PositionIDs []int64  `json:"position_ids" binding:"omitempty,max=50,dive,gt=0"`
+}
+
+// UpdateStrategyRequest represents the request body for changing a strategy. Omitted fields
+// are left unchanged; tags replace the existing tags.
+type UpdateStrategyRequest struct {
+	Name              *string   `json:"name" binding:"omitempty,min=1,max=200"`
+	Tags              *[]string `json:"tags" binding:"omitempty,max=20,dive,max=50"`
+	Notes             *string   `json:"notes" binding:"omitempty,max=2000"`
+	AddPositionIDs    []int64   `json:"add_position_ids" binding:"omitempty,max=50,dive,gt=0"`
+	RemovePositionIDs []int64   `json:"remove_position_ids" binding:"omitempty,max=50,dive,gt=0"`
+}
+
+// ListStrategies handles GET /api/v1/portfolio/:id/strategies?tag=
+func (h *StrategyHandler) ListStrategies(c *gin.Context) {
+	portfolioID, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	strategies, err := h.strategies.List(c.Request.Context(), portfolioID, normalizeTag(c.Query("tag")))
+	if err != nil {
+		appErr := repositoryError(err, "strategy", "failed to list strategies")
+		_ = c.Error(appErr)
+		return
+	}
+	if len(strategies) == 0 {
+		c.JSON(http.StatusOK, gin.H{"results": []models.StrategySummary{}})
+		return
+	}
+
+	summaries, err := h.valuation.StrategySummaries(c.Request.Context(), portfolioID, strategies)
+	if err != nil {
+		log.Printf("[Handler] ✗ Failed to value strategies for portfolio %d: %v", portfolioID, err)
+		appErr := errors.NewInternalError("failed to value strategies", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": summaries})
+}
+
+// CreateStrategy handles POST /api/v1/portfolio/:id/strategies
+func (h *StrategyHandler) CreateStrategy(c *gin.Context) {
+	portfolioID, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	var req CreateStrategyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := errors.NewBadRequestError("invalid request body", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	strategy := &models.Strategy{
+		PortfolioID: portfolioID,
+		Name:        strings.TrimSpace(req.Name),
+		Tags:        normalizeTags(req.Tags),
+		Notes:       req.Notes,
+	}
+	if strategy.Name == "" {
+		appErr := errors.NewBadRequestError("name must not be blank", nil)
+		_ = c.Error(appErr)
+		return
+	}
+
+	if _, err := h.portfolios.Get(c.Request.Context(), userID(c), portfolioID); err != nil {
+		appErr := repositoryError(err, "portfolio", "failed to get portfolio")
+		_ = c.Error(appErr)
+		return
+	}
+
+	if err := h.strategies.Create(c.Request.Context(), strategy, req.PositionIDs); err != nil {
+		appErr := strategyError(err, "failed to create strategy")
+		_ = c.Error(appErr)
+		return
+	}
+
+	log.Printf("[Handler] ✓ Created strategy %d (%s) with %d positions", strategy.ID, strategy.Name, len(strategy.PositionIDs))
+	c.JSON(http.StatusCreated, strategy)
+}
+
+// GetStrategy handles GET /api/v1/portfolio/:id/strategies/:strategyId, valuing its legs
+func (h *StrategyHandler) GetStrategy(c *gin.Context) {
+	strategy, appErr := h.loadStrategy(c)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	valuation, err := h.valuation.ValueStrategy(c.Request.Context(), strategy)
+	if err != nil {
+		log.Printf("[Handler] ✗ Failed to value strategy %d: %v", strategy.ID, err)
+		appErr := errors.NewInternalError("failed to value strategy", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, valuation)
+}
+
+// UpdateStrategy handles PATCH /api/v1/portfolio/:id/strategies/:strategyId
+func (h *StrategyHandler) UpdateStrategy(c *gin.Context) {
+	var req UpdateStrategyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := errors.NewBadRequestError("invalid request body", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	strategy, appErr := h.loadStrategy(c)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	if req.Name != nil {
+		strategy.Name = strings.TrimSpace(*req.Name)
+		if strategy.Name == "" {
+			appErr := errors.NewBadRequestError("name must not be blank", nil)
+			_ = c.Error(appErr)
+			return
+		}
+	}
+	if req.Tags != nil {
+		strategy.Tags = normalizeTags(*req.Tags)
+	}
+	if req.Notes != nil {
+		strategy.Notes = req.Notes
+	}
+
+	if err := h.strategies.Update(c.Request.Context(), strategy, req.AddPositionIDs, req.RemovePositionIDs); err != nil {
+		appErr := strategyError(err, "failed to update strategy")
+		_ = c.Error(appErr)
+		return
+	}
+
+	log.Printf("[Handler] ✓ Updated strategy %d (%s)", strategy.ID, strategy.Name)
+	c.JSON(http.StatusOK, strategy)
+}
+
+// DeleteStrategy handles DELETE /api/v1/portfolio/:id/strategies/:strategyId. Its
+// positions are ungrouped, not deleted.
+func (h *StrategyHandler) DeleteStrategy(c *gin.Context) {
+	portfolioID, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	strategyID, appErr := paramID(c, "strategyId")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	if err := h.strategies.Delete(c.Request.Context(), portfolioID, strategyID); err != nil {
+		appErr := repositoryError(err, "strategy", "failed to delete strategy")
+		_ = c.Error(appErr)
+		return
+	}
+
+	log.Printf("[Handler] ✓ Deleted strategy %d", strategyID)
+	c.Status(http.StatusNoContent)
+}
+
+// loadStrategy resolves the :id and :strategyId path parameters to a strategy
+func (h *StrategyHandler) loadStrategy(c *gin.Context) (*models.Strategy, *errors.AppError) {
+	portfolioID, appErr := paramID(c, "id")
+	if appErr != nil {
+		return nil, appErr
+	}
+	strategyID, appErr := paramID(c, "strategyId")
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	strategy, err := h.strategies.Get(c.Request.Context(), portfolioID, strategyID)
+	if err != nil {
+		return nil, repositoryError(err, "strategy", "failed to get strategy")
+	}
+	return strategy, nil
+}
+
+// strategyError maps strategy write errors, reporting positions outside the portfolio
+func strategyError(err error, message string) *errors.AppError {
+	if stderrors.Is(err, repository.ErrUnknownPosition) {
+		return errors.NewBadRequestError("position_ids must be positions in this portfolio", err)
+	}
+	return repositoryError(err, "strategy", message)
+}
+
+// normalizeTags lowercases and trims tags, dropping blanks and duplicates, sorted
+func normalizeTags(tags []string) []string {
+	normalized := []string{}
+	for _, tag := range tags {
+		if tag = normalizeTag(tag); tag != "" {
+			normalized = append(normalized, tag)
+		}
+	}
+	slices.Sort(normalized)
+	return slices.Compact(normalized)
+}
+
+// normalizeTag lowercases and trims a tag
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/analytics"
+	"github.com/aaronbengochea/periscope/backend-go/internal/ledger"
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// taxLotColumns is the header row of the CSV export
+var taxLotColumns = []string{
+	"closed_at", "opened_at", "ticker", "underlying_ticker", "asset_type", "side", "action", "quantity",
+	"holding_days", "term", "proceeds", "cost_basis", "realized_pnl", "wash_sale", "disallowed_loss",
+	"position_id", "lot_id", "transaction_id",
+}
+
+// TaxLotHandler serves realized gains reports built on the trade ledger
+type TaxLotHandler struct {
+	portfolios   repository.PortfolioRepo
+	transactions *repository.TransactionRepository
+}
+
+// NewTaxLotHandler creates a new tax lot handler
+func NewTaxLotHandler(portfolios repository.PortfolioRepo, transactions *repository.TransactionRepository) *TaxLotHandler {
+	return &TaxLotHandler{
+		portfolios:   portfolios,
+		transactions: transactions,
+	}
+}
+
+// GetTaxLots handles GET /api/v1/portfolio/:id/tax-lots?year=2026&format=json|csv
+//
+// Reports every lot closed during the calendar year with its holding period term and wash
+// sale flag. year defaults to the current year.
+func (h *TaxLotHandler) GetTaxLots(c *gin.Context) {
+	portfolioID, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	today := analytics.MarketDate(time.Now())
+	year, appErr := queryInt(c, "year", today.Year())
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	if year < 1970 || year > today.Year() {
+		appErr := errors.NewBadRequestError(fmt.Sprintf("year must be between 1970 and %d", today.Year()), nil)
+		_ = c.Error(appErr)
+		return
+	}
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		appErr := errors.NewBadRequestError("format must be json or csv", nil)
+		_ = c.Error(appErr)
+		return
+	}
+
+	if _, err := h.portfolios.Get(c.Request.Context(), userID(c), portfolioID); err != nil {
+		appErr := repositoryError(err, "portfolio", "failed to get portfolio")
+		_ = c.Error(appErr)
+		return
+	}
+
+	report, err := h.report(c, portfolioID, year)
+	if err != nil {
+		log.Printf("[Handler] ✗ Failed to build tax lot report for portfolio %d: %v", portfolioID, err)
+		appErr := errors.NewInternalError("failed to build tax lot report", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	if format == "json" {
+		c.JSON(http.StatusOK, report)
+		return
+	}
+
+	filename := fmt.Sprintf("portfolio-%d-tax-lots-%d.csv", portfolioID, year)
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(http.StatusOK)
+	if err := writeTaxLots(csv.NewWriter(c.Writer), report.Lots); err != nil {
+		log.Printf("[Handler] ✗ Failed to write tax lot CSV for portfolio %d: %v", portfolioID, err)
+	}
+}
+
+// report loads the lots closed during the year, classifies them and flags wash sales
+// against lots opened within the wash sale window of the year
+func (h *TaxLotHandler) report(c *gin.Context, portfolioID int64, year int) (*models.TaxLotReport, error) {
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+	from, to := start.Format("2006-01-02"), end.Format("2006-01-02")
+
+	realized, err := h.transactions.ListRealized(c.Request.Context(), portfolioID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	for i := range realized {
+		if err := ledger.Classify(&realized[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	acquisitions, err := h.transactions.ListAcquisitions(c.Request.Context(), portfolioID,
+		start.AddDate(0, 0, -ledger.WashSaleWindowDays).Format("2006-01-02"),
+		end.AddDate(0, 0, ledger.WashSaleWindowDays).Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	if err := ledger.FlagWashSales(realized, acquisitions); err != nil {
+		return nil, err
+	}
+
+	return &models.TaxLotReport{
+		PortfolioID: portfolioID,
+		From:        from,
+		To:          to,
+		Summary:     ledger.SummarizeRealized(realized),
+		Lots:        realized,
+	}, nil
+}
+
+// writeTaxLots writes realized lots as CSV with a header row
+func writeTaxLots(w *csv.Writer, lots []models.RealizedLot) error {
+	money := func(v float64) string { return strconv.FormatFloat(v, 'f', 2, 64) }
+
+	if err := w.Write(taxLotColumns); err != nil {
+		return err
+	}
+	for _, l := range lots {
+		record := []string{
+			l.ClosedAt, l.OpenedAt, l.Ticker, l.UnderlyingTicker, l.AssetType, l.Side, l.Action,
+			strconv.FormatFloat(l.Quantity, 'f', -1, 64), strconv.Itoa(l.HoldingDays), l.Term,
+			money(l.Proceeds), money(l.CostBasis), money(l.RealizedPnL),
+			strconv.FormatBool(l.WashSale), money(l.DisallowedLoss),
+			strconv.FormatInt(l.PositionID, 10), strconv.FormatInt(l.LotID, 10), strconv.FormatInt(l.TransactionID, 10),
+		}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
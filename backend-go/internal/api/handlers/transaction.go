@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"net/http"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/ledger"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// TransactionHandler serves a portfolio's trade ledger
+type TransactionHandler struct {
+	portfolios   repository.PortfolioRepo
+	transactions *repository.TransactionRepository
+}
+
+// NewTransactionHandler creates a new transaction handler
+func NewTransactionHandler(portfolios repository.PortfolioRepo, transactions *repository.TransactionRepository) *TransactionHandler {
+	return &TransactionHandler{
+		portfolios:   portfolios,
+		transactions: transactions,
+	}
+}
+
+// ListTransactions handles GET /api/v1/portfolio/:id/transactions?position_id=
+func (h *TransactionHandler) ListTransactions(c *gin.Context) {
+	portfolioID, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	positionID, appErr := queryInt(c, "position_id", 0)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	if _, err := h.portfolios.Get(c.Request.Context(), userID(c), portfolioID); err != nil {
+		appErr := repositoryError(err, "portfolio", "failed to get portfolio")
+		_ = c.Error(appErr)
+		return
+	}
+
+	transactions, err := h.transactions.List(c.Request.Context(), portfolioID, int64(positionID))
+	if err != nil {
+		appErr := repositoryError(err, "transaction", "failed to list transactions")
+		_ = c.Error(appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": transactions})
+}
+
+// GetTransaction handles GET /api/v1/portfolio/:id/transactions/:transactionId,
+// including the lots a closing transaction consumed
+func (h *TransactionHandler) GetTransaction(c *gin.Context) {
+	portfolioID, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	transactionID, appErr := paramID(c, "transactionId")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	transaction, err := h.transactions.Get(c.Request.Context(), portfolioID, transactionID)
+	if err != nil {
+		appErr := repositoryError(err, "transaction", "failed to get transaction")
+		_ = c.Error(appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, transaction)
+}
+
+// ledgerError maps trade ledger errors to API errors
+func ledgerError(err error, message string) *errors.AppError {
+	switch {
+	case stderrors.Is(err, repository.ErrPositionClosed):
+		return errors.NewConflictError("position is closed")
+	case stderrors.Is(err, repository.ErrLedgerHistory):
+		return errors.NewConflictError("position has later trades; record a transaction instead of editing the opening trade")
+	case stderrors.Is(err, ledger.ErrInsufficientQuantity):
+		return errors.NewBadRequestError("quantity exceeds the open quantity", err)
+	}
+	return repositoryError(err, "position", message)
+}
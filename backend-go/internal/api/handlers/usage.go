@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// usageHistoryDays is how many days of daily usage GET /me/usage returns
+const usageHistoryDays = 30
+
+// UsageHandler reports the signed-in user's Massive API usage against their quota
+type UsageHandler struct {
+	users *repository.UserRepository
+}
+
+// NewUsageHandler creates a new usage handler
+func NewUsageHandler(users *repository.UserRepository) *UsageHandler {
+	return &UsageHandler{users: users}
+}
+
+// GetUsage handles GET /api/v1/me/usage: the user's usage in the current quota period and
+// on each of the last 30 days
+func (h *UsageHandler) GetUsage(c *gin.Context) {
+	id := userID(c)
+	if id == nil {
+		appErr := errors.NewUnauthorizedError("usage is metered for signed-in users only")
+		_ = c.Error(appErr)
+		return
+	}
+
+	user, err := h.users.Get(c.Request.Context(), *id)
+	if err != nil {
+		appErr := repositoryError(err, "user", "failed to get usage")
+		_ = c.Error(appErr)
+		return
+	}
+	now := time.Now()
+	daily, err := h.users.DailyUsage(c.Request.Context(), user.ID, now.AddDate(0, 0, 1-usageHistoryDays))
+	if err != nil {
+		appErr := repositoryError(err, "user", "failed to get usage")
+		_ = c.Error(appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"quota": user.Quota(now), "daily": daily})
+}
@@ -0,0 +1,261 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/aaronbengochea/periscope/backend-go/internal/services"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// ValuationHandler serves live portfolio valuations and greeks
+type ValuationHandler struct {
+	portfolios repository.PortfolioRepo
+	valuation  *services.ValuationService
+}
+
+// NewValuationHandler creates a new valuation handler
+func NewValuationHandler(portfolios repository.PortfolioRepo, valuation *services.ValuationService) *ValuationHandler {
+	return &ValuationHandler{
+		portfolios: portfolios,
+		valuation:  valuation,
+	}
+}
+
+// GetValuation handles GET /api/v1/portfolio/:id/valuation
+func (h *ValuationHandler) GetValuation(c *gin.Context) {
+	portfolioID, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	if _, err := h.portfolios.Get(c.Request.Context(), userID(c), portfolioID); err != nil {
+		appErr := repositoryError(err, "portfolio", "failed to get portfolio")
+		_ = c.Error(appErr)
+		return
+	}
+
+	valuation, err := h.valuation.ValuePortfolio(c.Request.Context(), portfolioID)
+	if err != nil {
+		log.Printf("[Handler] ✗ Failed to value portfolio %d: %v", portfolioID, err)
+		appErr := errors.NewInternalError("failed to value portfolio", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, valuation)
+}
+
+// GetGreeks handles GET /api/v1/portfolio/:id/greeks
+func (h *ValuationHandler) GetGreeks(c *gin.Context) {
+	portfolioID, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	if _, err := h.portfolios.Get(c.Request.Context(), userID(c), portfolioID); err != nil {
+		appErr := repositoryError(err, "portfolio", "failed to get portfolio")
+		_ = c.Error(appErr)
+		return
+	}
+
+	greeks, err := h.valuation.PortfolioGreeks(c.Request.Context(), portfolioID)
+	if err != nil {
+		log.Printf("[Handler] ✗ Failed to aggregate greeks for portfolio %d: %v", portfolioID, err)
+		appErr := errors.NewInternalError("failed to aggregate portfolio greeks", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, greeks)
+}
+
+// maxRiskHorizonDays caps the VaR horizon
+const maxRiskHorizonDays = 30
+
+// GetRisk handles GET /api/v1/portfolio/:id/risk?horizon_days=1
+func (h *ValuationHandler) GetRisk(c *gin.Context) {
+	portfolioID, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	horizon, appErr := queryInt(c, "horizon_days", 1)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	if horizon < 1 || horizon > maxRiskHorizonDays {
+		appErr := errors.NewBadRequestError(fmt.Sprintf("horizon_days must be between 1 and %d", maxRiskHorizonDays), nil)
+		_ = c.Error(appErr)
+		return
+	}
+
+	if _, err := h.portfolios.Get(c.Request.Context(), userID(c), portfolioID); err != nil {
+		appErr := repositoryError(err, "portfolio", "failed to get portfolio")
+		_ = c.Error(appErr)
+		return
+	}
+
+	risk, err := h.valuation.PortfolioRisk(c.Request.Context(), portfolioID, horizon)
+	if err != nil {
+		log.Printf("[Handler] ✗ Failed to assess risk for portfolio %d: %v", portfolioID, err)
+		appErr := errors.NewInternalError("failed to assess portfolio risk", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, risk)
+}
+
+// SimulateRequest is the body of a what-if simulation. Changes are relative: spot_change
+// -0.05 moves every underlying down 5% and iv_change 0.20 raises implied volatility by a fifth.
+type SimulateRequest struct {
+	SpotChange  float64            `json:"spot_change" binding:"gt=-1"`
+	SpotChanges map[string]float64 `json:"spot_changes" binding:"omitempty,dive,gt=-1"`
+	IVChange    float64            `json:"iv_change" binding:"gt=-1"`
+	DaysForward int                `json:"days_forward" binding:"gte=0,lte=1095"`
+}
+
+// Simulate handles POST /api/v1/portfolio/:id/simulate
+func (h *ValuationHandler) Simulate(c *gin.Context) {
+	portfolioID, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	var req SimulateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := errors.NewBadRequestError("invalid request body", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	if _, err := h.portfolios.Get(c.Request.Context(), userID(c), portfolioID); err != nil {
+		appErr := repositoryError(err, "portfolio", "failed to get portfolio")
+		_ = c.Error(appErr)
+		return
+	}
+
+	inputs := models.SimulationInputs{
+		SpotChange:  req.SpotChange,
+		SpotChanges: make(map[string]float64, len(req.SpotChanges)),
+		IVChange:    req.IVChange,
+		DaysForward: req.DaysForward,
+	}
+	for ticker, change := range req.SpotChanges {
+		inputs.SpotChanges[strings.ToUpper(ticker)] = change
+	}
+
+	sim, err := h.valuation.Simulate(c.Request.Context(), portfolioID, inputs)
+	if err != nil {
+		log.Printf("[Handler] ✗ Failed to simulate portfolio %d: %v", portfolioID, err)
+		appErr := errors.NewInternalError("failed to simulate portfolio", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, sim)
+}
+
+// MarginTrade is a proposed trade whose margin impact is estimated
+type MarginTrade struct {
+	AssetType  string  `json:"asset_type" binding:"required,oneof=option stock"`
+	Ticker     string  `json:"ticker" binding:"required"`
+	Side       string  `json:"side" binding:"required,oneof=long short"`
+	Quantity   float64 `json:"quantity" binding:"required,gt=0"`
+	Multiplier *int    `json:"multiplier" binding:"omitempty,gt=0"`
+}
+
+// MarginRequest is the body of a margin estimate. Trades are added to the open positions;
+// account_equity, when given, is used to report the remaining buying power.
+type MarginRequest struct {
+	Trades        []MarginTrade `json:"trades" binding:"omitempty,max=50,dive"`
+	AccountEquity *float64      `json:"account_equity" binding:"omitempty,gte=0"`
+}
+
+// Margin handles POST /api/v1/portfolio/:id/margin
+func (h *ValuationHandler) Margin(c *gin.Context) {
+	portfolioID, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	var req MarginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := errors.NewBadRequestError("invalid request body", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	trades := make([]models.Position, 0, len(req.Trades))
+	for _, t := range req.Trades {
+		trade, appErr := newPosition(portfolioID, &CreatePositionRequest{
+			AssetType:  t.AssetType,
+			Ticker:     t.Ticker,
+			Side:       t.Side,
+			Quantity:   t.Quantity,
+			Multiplier: t.Multiplier,
+		})
+		if appErr != nil {
+			_ = c.Error(appErr)
+			return
+		}
+		trade.Status = models.PositionOpen
+		trades = append(trades, *trade)
+	}
+
+	if _, err := h.portfolios.Get(c.Request.Context(), userID(c), portfolioID); err != nil {
+		appErr := repositoryError(err, "portfolio", "failed to get portfolio")
+		_ = c.Error(appErr)
+		return
+	}
+
+	estimate, err := h.valuation.Margin(c.Request.Context(), portfolioID, trades, req.AccountEquity)
+	if err != nil {
+		log.Printf("[Handler] ✗ Failed to estimate margin for portfolio %d: %v", portfolioID, err)
+		appErr := errors.NewInternalError("failed to estimate margin", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, estimate)
+}
+
+// GetRollup handles GET /api/v1/portfolio/rollup, combining every portfolio of the
+// current user that has include_in_rollup enabled
+func (h *ValuationHandler) GetRollup(c *gin.Context) {
+	portfolios, err := h.portfolios.List(c.Request.Context(), userID(c))
+	if err != nil {
+		appErr := repositoryError(err, "portfolio", "failed to list portfolios")
+		_ = c.Error(appErr)
+		return
+	}
+
+	included := []models.Portfolio{}
+	for _, p := range portfolios {
+		if p.Settings.IncludeInRollup {
+			included = append(included, p)
+		}
+	}
+
+	rollup, err := h.valuation.Rollup(c.Request.Context(), included)
+	if err != nil {
+		log.Printf("[Handler] ✗ Failed to roll up portfolios: %v", err)
+		appErr := errors.NewInternalError("failed to roll up portfolios", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, rollup)
+}
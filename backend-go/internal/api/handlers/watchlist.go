@@ -0,0 +1,400 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/aaronbengochea/periscope/backend-go/internal/services"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// maxIVTickers caps the stocks quoted with ATM IV, which needs a chain fetch per ticker
+const maxIVTickers = 50
+
+// WatchlistHandler handles watchlist CRUD and quote requests
+type WatchlistHandler struct {
+	watchlists repository.WatchlistRepo
+	quotes     *services.WatchlistService
+}
+
+// NewWatchlistHandler creates a new watchlist handler
+func NewWatchlistHandler(watchlists repository.WatchlistRepo, quotes *services.WatchlistService) *WatchlistHandler {
+	return &WatchlistHandler{
+		watchlists: watchlists,
+		quotes:     quotes,
+	}
+}
+
+// CreateWatchlistRequest represents the request body for creating a watchlist, e.g.
+// {"name": "Megacaps", "tickers": ["AAPL", "MSFT", "NVDA"]}
+type CreateWatchlistRequest struct {
+	Name        string   `json:"name" binding:"required,max=100"`
+	Description *string  `json:"description"`
+	Tickers     []string `json:"tickers" binding:"omitempty,max=200"`
+}
+
+// UpdateWatchlistRequest represents the request body for renaming a watchlist.
+// Omitted fields are left unchanged.
+type UpdateWatchlistRequest struct {
+	Name        *string `json:"name" binding:"omitempty,min=1,max=100"`
+	Description *string `json:"description"`
+}
+
+// WatchlistItemsRequest lists tickers to add to a watchlist, or its complete new order
+type WatchlistItemsRequest struct {
+	Tickers []string `json:"tickers" binding:"required,max=200"`
+}
+
+// ListWatchlists handles GET /api/v1/watchlists
+func (h *WatchlistHandler) ListWatchlists(c *gin.Context) {
+	watchlists, err := h.watchlists.List(c.Request.Context(), userID(c))
+	if err != nil {
+		appErr := repositoryError(err, "watchlist", "failed to list watchlists")
+		_ = c.Error(appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": watchlists})
+}
+
+// CreateWatchlist handles POST /api/v1/watchlists
+func (h *WatchlistHandler) CreateWatchlist(c *gin.Context) {
+	var req CreateWatchlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := errors.NewBadRequestError("invalid request body", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	watchlist := &models.Watchlist{
+		UserID:      userID(c),
+		Name:        strings.TrimSpace(req.Name),
+		Description: req.Description,
+	}
+	if watchlist.Name == "" {
+		appErr := errors.NewBadRequestError("name must not be blank", nil)
+		_ = c.Error(appErr)
+		return
+	}
+	tickers, appErr := watchlistTickers(req.Tickers)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	if err := h.watchlists.Create(c.Request.Context(), watchlist, tickers); err != nil {
+		appErr := repositoryError(err, "watchlist", "failed to create watchlist")
+		_ = c.Error(appErr)
+		return
+	}
+
+	log.Printf("[Handler] ✓ Created watchlist %d (%s) with %d tickers", watchlist.ID, watchlist.Name, len(watchlist.Items))
+	c.JSON(http.StatusCreated, watchlist)
+}
+
+// GetWatchlist handles GET /api/v1/watchlists/:id
+func (h *WatchlistHandler) GetWatchlist(c *gin.Context) {
+	watchlist, appErr := h.loadWatchlist(c)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, watchlist)
+}
+
+// GetQuotes handles GET /api/v1/watchlists/:id/quotes?iv=true, quoting every ticker in
+// list order
+func (h *WatchlistHandler) GetQuotes(c *gin.Context) {
+	watchlist, appErr := h.loadWatchlist(c)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	withIV := c.Query("iv") == "true"
+	if withIV && len(watchlist.Tickers(models.AssetTypeStock)) > maxIVTickers {
+		appErr := errors.NewBadRequestError(fmt.Sprintf("iv is limited to watchlists of %d stocks", maxIVTickers), nil)
+		_ = c.Error(appErr)
+		return
+	}
+
+	quotes, err := h.quotes.Quotes(c.Request.Context(), watchlist, withIV)
+	if err != nil {
+		log.Printf("[Handler] ✗ Failed to quote watchlist %d: %v", watchlist.ID, err)
+		appErr := errors.NewInternalError("failed to fetch watchlist quotes", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	log.Printf("[Handler] ✓ Quoted %d of %d tickers on watchlist %d (iv=%v)",
+		len(quotes.Quotes), len(watchlist.Items), watchlist.ID, withIV)
+	c.JSON(http.StatusOK, quotes)
+}
+
+// UpdateWatchlist handles PATCH /api/v1/watchlists/:id
+func (h *WatchlistHandler) UpdateWatchlist(c *gin.Context) {
+	var req UpdateWatchlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := errors.NewBadRequestError("invalid request body", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	watchlist, appErr := h.loadWatchlist(c)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	if req.Name != nil {
+		watchlist.Name = strings.TrimSpace(*req.Name)
+		if watchlist.Name == "" {
+			appErr := errors.NewBadRequestError("name must not be blank", nil)
+			_ = c.Error(appErr)
+			return
+		}
+	}
+	if req.Description != nil {
+		watchlist.Description = req.Description
+	}
+
+	if err := h.watchlists.Update(c.Request.Context(), watchlist); err != nil {
+		appErr := repositoryError(err, "watchlist", "failed to update watchlist")
+		_ = c.Error(appErr)
+		return
+	}
+
+	log.Printf("[Handler] ✓ Updated watchlist %d", watchlist.ID)
+	c.JSON(http.StatusOK, watchlist)
+}
+
+// DeleteWatchlist handles DELETE /api/v1/watchlists/:id
+func (h *WatchlistHandler) DeleteWatchlist(c *gin.Context) {
+	id, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	if err := h.watchlists.Delete(c.Request.Context(), userID(c), id); err != nil {
+		appErr := repositoryError(err, "watchlist", "failed to delete watchlist")
+		_ = c.Error(appErr)
+		return
+	}
+
+	log.Printf("[Handler] ✓ Deleted watchlist %d", id)
+	c.Status(http.StatusNoContent)
+}
+
+// ListDeletedWatchlists handles GET /api/v1/watchlists/deleted
+func (h *WatchlistHandler) ListDeletedWatchlists(c *gin.Context) {
+	watchlists, err := h.watchlists.ListDeleted(c.Request.Context(), userID(c))
+	if err != nil {
+		appErr := repositoryError(err, "watchlist", "failed to list deleted watchlists")
+		_ = c.Error(appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": watchlists})
+}
+
+// RestoreWatchlist handles POST /api/v1/watchlists/deleted/:id/restore
+func (h *WatchlistHandler) RestoreWatchlist(c *gin.Context) {
+	id, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	watchlist, err := h.watchlists.Restore(c.Request.Context(), userID(c), id)
+	if err != nil {
+		appErr := repositoryError(err, "watchlist", "failed to restore watchlist")
+		_ = c.Error(appErr)
+		return
+	}
+
+	log.Printf("[Handler] ✓ Restored watchlist %d", id)
+	c.JSON(http.StatusOK, watchlist)
+}
+
+// PurgeWatchlist handles DELETE /api/v1/watchlists/deleted/:id
+func (h *WatchlistHandler) PurgeWatchlist(c *gin.Context) {
+	id, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	if err := h.watchlists.Purge(c.Request.Context(), userID(c), id); err != nil {
+		appErr := repositoryError(err, "watchlist", "failed to purge watchlist")
+		_ = c.Error(appErr)
+		return
+	}
+
+	log.Printf("[Handler] ✓ Purged watchlist %d", id)
+	c.Status(http.StatusNoContent)
+}
+
+// AddItems handles POST /api/v1/watchlists/:id/items, appending tickers to the list.
+// Tickers already on it keep their place.
+func (h *WatchlistHandler) AddItems(c *gin.Context) {
+	var req WatchlistItemsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := errors.NewBadRequestError("invalid request body", err)
+		_ = c.Error(appErr)
+		return
+	}
+	tickers, appErr := watchlistTickers(req.Tickers)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	if len(tickers) == 0 {
+		appErr := errors.NewBadRequestError("tickers must not be empty", nil)
+		_ = c.Error(appErr)
+		return
+	}
+
+	watchlist, appErr := h.loadWatchlist(c)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	added := 0
+	for _, t := range tickers {
+		if !hasTicker(watchlist, t) {
+			added++
+		}
+	}
+	if len(watchlist.Items)+added > models.MaxWatchlistItems {
+		appErr := errors.NewBadRequestError(fmt.Sprintf("a watchlist holds at most %d tickers", models.MaxWatchlistItems), nil)
+		_ = c.Error(appErr)
+		return
+	}
+
+	if err := h.watchlists.AddItems(c.Request.Context(), watchlist, tickers); err != nil {
+		appErr := repositoryError(err, "watchlist", "failed to add watchlist tickers")
+		_ = c.Error(appErr)
+		return
+	}
+
+	log.Printf("[Handler] ✓ Added %d tickers to watchlist %d", added, watchlist.ID)
+	c.JSON(http.StatusOK, watchlist)
+}
+
+// ReplaceItems handles PUT /api/v1/watchlists/:id/items, setting the list to exactly the
+// given tickers in that order. Sending the current tickers in a new order reorders it.
+func (h *WatchlistHandler) ReplaceItems(c *gin.Context) {
+	var req WatchlistItemsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := errors.NewBadRequestError("invalid request body", err)
+		_ = c.Error(appErr)
+		return
+	}
+	tickers, appErr := watchlistTickers(req.Tickers)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	watchlist, appErr := h.loadWatchlist(c)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	if err := h.watchlists.ReplaceItems(c.Request.Context(), watchlist, tickers); err != nil {
+		appErr := repositoryError(err, "watchlist", "failed to save watchlist tickers")
+		_ = c.Error(appErr)
+		return
+	}
+
+	log.Printf("[Handler] ✓ Set %d tickers on watchlist %d", len(watchlist.Items), watchlist.ID)
+	c.JSON(http.StatusOK, watchlist)
+}
+
+// RemoveItem handles DELETE /api/v1/watchlists/:id/items/:ticker
+func (h *WatchlistHandler) RemoveItem(c *gin.Context) {
+	watchlist, appErr := h.loadWatchlist(c)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	ticker, ok := normalizeTicker(c.Param("ticker"))
+	if !ok {
+		appErr := errors.NewBadRequestError(fmt.Sprintf("invalid ticker %q", c.Param("ticker")), nil)
+		_ = c.Error(appErr)
+		return
+	}
+	if err := h.watchlists.RemoveItem(c.Request.Context(), watchlist, ticker); err != nil {
+		appErr := repositoryError(err, "watchlist ticker", "failed to remove watchlist ticker")
+		_ = c.Error(appErr)
+		return
+	}
+
+	log.Printf("[Handler] ✓ Removed %s from watchlist %d", ticker, watchlist.ID)
+	c.Status(http.StatusNoContent)
+}
+
+// loadWatchlist resolves the :id path parameter to a watchlist
+func (h *WatchlistHandler) loadWatchlist(c *gin.Context) (*models.Watchlist, *errors.AppError) {
+	id, appErr := paramID(c, "id")
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	watchlist, err := h.watchlists.Get(c.Request.Context(), userID(c), id)
+	if err != nil {
+		return nil, repositoryError(err, "watchlist", "failed to get watchlist")
+	}
+	return watchlist, nil
+}
+
+// watchlistTickers normalizes and validates tickers, dropping blanks and repeats while
+// keeping the order they were given in
+func watchlistTickers(raw []string) ([]string, *errors.AppError) {
+	tickers := make([]string, 0, len(raw))
+	seen := make(map[string]bool, len(raw))
+	for _, t := range raw {
+		if strings.TrimSpace(t) == "" {
+			continue
+		}
+		ticker, ok := normalizeTicker(t)
+		if !ok {
+			return nil, errors.NewBadRequestError(fmt.Sprintf("invalid ticker %q", t), nil)
+		}
+		if !seen[ticker] {
+			seen[ticker] = true
+			tickers = append(tickers, ticker)
+		}
+	}
+	return tickers, nil
+}
+
+// normalizeTicker uppercases a stock ticker, or rewrites an OCC option symbol with or
+// without its O: prefix in the canonical O: form
+func normalizeTicker(raw string) (string, bool) {
+	ticker := strings.ToUpper(strings.TrimSpace(raw))
+	if models.IsStockTicker(ticker) {
+		return ticker, true
+	}
+	if symbol, err := models.ParseOptionTicker(ticker); err == nil {
+		return symbol.Ticker(), true
+	}
+	return "", false
+}
+
+// hasTicker reports whether the ticker is on the watchlist
+func hasTicker(w *models.Watchlist, ticker string) bool {
+	for _, item := range w.Items {
+		if item.Ticker == ticker {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,304 @@
+package handlers
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/aaronbengochea/periscope/backend-go/internal/webhook"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// Webhook delivery listing limits
+const (
+	defaultDeliveryLimit = 50
+	maxDeliveryLimit     = 200
+)
+
+// WebhookHandler manages the webhooks notified of a portfolio's events
+type WebhookHandler struct {
+	portfolios repository.PortfolioRepo
+	webhooks   *repository.WebhookRepository
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(portfolios repository.PortfolioRepo, webhooks *repository.WebhookRepository) *WebhookHandler {
+	return &WebhookHandler{
+		portfolios: portfolios,
+		webhooks:   webhooks,
+	}
+}
+
+// CreateWebhookRequest represents the request body for registering a webhook, e.g.
+// {"url": "https://example.com/hooks/periscope", "events": ["position.opened", "position.closed"]}.
+// Without events the webhook receives every event.
+type CreateWebhookRequest struct {
+	URL         string   `json:"url" binding:"required,http_url,max=2000"`
+	Description *string  `json:"description" binding:"omitempty,max=200"`
+	Events      []string `json:"events" binding:"omitempty,max=10"`
+}
+
+// UpdateWebhookRequest represents the request body for editing a webhook. Omitted fields
+// are left unchanged; reactivating a disabled webhook clears its failure count.
+type UpdateWebhookRequest struct {
+	URL         *string   `json:"url" binding:"omitempty,http_url,max=2000"`
+	Description *string   `json:"description" binding:"omitempty,max=200"`
+	Events      *[]string `json:"events" binding:"omitempty,min=1,max=10"`
+	Active      *bool     `json:"active"`
+}
+
+// ListWebhooks handles GET /api/v1/portfolio/:id/webhooks
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	portfolioID, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	webhooks, err := h.webhooks.List(c.Request.Context(), portfolioID)
+	if err != nil {
+		appErr := repositoryError(err, "webhook", "failed to list webhooks")
+		_ = c.Error(appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": webhooks})
+}
+
+// CreateWebhook handles POST /api/v1/portfolio/:id/webhooks. The response includes the
+// signing secret, which is not shown again.
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	portfolioID, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	var req CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := errors.NewBadRequestError("invalid request body", err)
+		_ = c.Error(appErr)
+		return
+	}
+	if appErr := publicURL(c.Request.Context(), req.URL); appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	events := models.WebhookEvents
+	if len(req.Events) > 0 {
+		if events, appErr = webhookEvents(req.Events); appErr != nil {
+			_ = c.Error(appErr)
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+	if _, err := h.portfolios.Get(ctx, userID(c), portfolioID); err != nil {
+		appErr := repositoryError(err, "portfolio", "failed to get portfolio")
+		_ = c.Error(appErr)
+		return
+	}
+
+	secret, err := webhook.NewSecret()
+	if err != nil {
+		appErr := errors.NewInternalError("failed to create webhook", err)
+		_ = c.Error(appErr)
+		return
+	}
+	w := &models.Webhook{
+		PortfolioID: portfolioID,
+		URL:         req.URL,
+		Description: optionalText(req.Description),
+		Events:      events,
+		Secret:      secret,
+		Active:      true,
+	}
+	if err := h.webhooks.Create(ctx, w); err != nil {
+		appErr := repositoryError(err, "webhook", "failed to create webhook")
+		_ = c.Error(appErr)
+		return
+	}
+
+	log.Printf("[Handler] ✓ Created webhook %d for portfolio %d (%s)", w.ID, portfolioID, strings.Join(events, ", "))
+	c.JSON(http.StatusCreated, w)
+}
+
+// UpdateWebhook handles PATCH /api/v1/portfolio/:id/webhooks/:webhookId
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	var req UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := errors.NewBadRequestError("invalid request body", err)
+		_ = c.Error(appErr)
+		return
+	}
+
+	w, appErr := h.loadWebhook(c)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	if req.URL != nil {
+		if appErr := publicURL(c.Request.Context(), *req.URL); appErr != nil {
+			_ = c.Error(appErr)
+			return
+		}
+		w.URL = *req.URL
+	}
+	if req.Description != nil {
+		w.Description = optionalText(req.Description)
+	}
+	if req.Events != nil {
+		if w.Events, appErr = webhookEvents(*req.Events); appErr != nil {
+			_ = c.Error(appErr)
+			return
+		}
+	}
+	if req.Active != nil {
+		w.Active = *req.Active
+	}
+
+	if err := h.webhooks.Update(c.Request.Context(), w); err != nil {
+		appErr := repositoryError(err, "webhook", "failed to update webhook")
+		_ = c.Error(appErr)
+		return
+	}
+
+	log.Printf("[Handler] ✓ Updated webhook %d", w.ID)
+	c.JSON(http.StatusOK, w)
+}
+
+// DeleteWebhook handles DELETE /api/v1/portfolio/:id/webhooks/:webhookId
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	portfolioID, appErr := paramID(c, "id")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	webhookID, appErr := paramID(c, "webhookId")
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	if err := h.webhooks.Delete(c.Request.Context(), portfolioID, webhookID); err != nil {
+		appErr := repositoryError(err, "webhook", "failed to delete webhook")
+		_ = c.Error(appErr)
+		return
+	}
+
+	log.Printf("[Handler] ✓ Deleted webhook %d", webhookID)
+	c.Status(http.StatusNoContent)
+}
+
+// ListDeliveries handles GET /api/v1/portfolio/:id/webhooks/:webhookId/deliveries?limit=
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	limit, appErr := queryInt(c, "limit", defaultDeliveryLimit)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	if limit < 1 || limit > maxDeliveryLimit {
+		appErr := errors.NewBadRequestError("limit must be between 1 and 200", nil)
+		_ = c.Error(appErr)
+		return
+	}
+
+	w, appErr := h.loadWebhook(c)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+
+	deliveries, err := h.webhooks.ListDeliveries(c.Request.Context(), w.ID, limit)
+	if err != nil {
+		appErr := repositoryError(err, "webhook delivery", "failed to list webhook deliveries")
+		_ = c.Error(appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": deliveries})
+}
+
+// TestWebhook handles POST /api/v1/portfolio/:id/webhooks/:webhookId/test, queueing a ping
+// event that is sent with the next delivery run
+func (h *WebhookHandler) TestWebhook(c *gin.Context) {
+	w, appErr := h.loadWebhook(c)
+	if appErr != nil {
+		_ = c.Error(appErr)
+		return
+	}
+	if !w.Active {
+		appErr := errors.NewConflictError("webhook is disabled; reactivate it first")
+		_ = c.Error(appErr)
+		return
+	}
+
+	d, err := h.webhooks.Enqueue(c.Request.Context(), w, models.EventPing, gin.H{
+		"webhook_id": w.ID,
+		"sent_at":    time.Now().UTC(),
+	})
+	if err != nil {
+		appErr := repositoryError(err, "webhook delivery", "failed to queue test event")
+		_ = c.Error(appErr)
+		return
+	}
+
+	log.Printf("[Handler] ✓ Queued ping %d for webhook %d", d.ID, w.ID)
+	c.JSON(http.StatusAccepted, d)
+}
+
+// loadWebhook resolves the :id and :webhookId path parameters to a webhook
+func (h *WebhookHandler) loadWebhook(c *gin.Context) (*models.Webhook, *errors.AppError) {
+	portfolioID, appErr := paramID(c, "id")
+	if appErr != nil {
+		return nil, appErr
+	}
+	webhookID, appErr := paramID(c, "webhookId")
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	w, err := h.webhooks.Get(c.Request.Context(), portfolioID, webhookID)
+	if err != nil {
+		return nil, repositoryError(err, "webhook", "failed to get webhook")
+	}
+	return w, nil
+}
+
+// webhookEvents validates subscribed events, dropping duplicates
+func webhookEvents(events []string) ([]string, *errors.AppError) {
+	subscribed := make([]string, 0, len(events))
+	for _, e := range events {
+		e = strings.ToLower(strings.TrimSpace(e))
+		if !slices.Contains(models.WebhookEvents, e) {
+			return nil, errors.NewBadRequestError(fmt.Sprintf("unknown event %q; must be one of %s", e, strings.Join(models.WebhookEvents, ", ")), nil)
+		}
+		if !slices.Contains(subscribed, e) {
+			subscribed = append(subscribed, e)
+		}
+	}
+	return subscribed, nil
+}
+
+// publicURL refuses webhook URLs whose host is, or resolves to, a loopback, private,
+// link-local or unspecified address
+func publicURL(ctx context.Context, rawURL string) *errors.AppError {
+	err := webhook.CheckURL(ctx, rawURL)
+	switch {
+	case err == nil:
+		return nil
+	case stderrors.Is(err, webhook.ErrPrivateAddress):
+		return errors.NewBadRequestError("url must point to a public address", err)
+	default:
+		return errors.NewBadRequestError("url host could not be resolved", err)
+	}
+}
@@ -0,0 +1,231 @@
+package middleware
+
+import (
+	stderrors "errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/auth"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// Context keys set for authenticated requests
+const (
+	UserIDKey       = "user_id"
+	UserKey         = "user"          // the *models.User, set when users are recorded
+	AuthClaimsKey   = "auth_claims"   // set for requests made with an access token
+	GoogleClaimsKey = "google_claims" // set for requests made with a Google ID token
+	APIKeyKey       = "api_key"       // set for requests made with an API key
+)
+
+// Cookies the session endpoints keep a browser's tokens in
+const (
+	AccessTokenCookie  = "periscope_access"
+	RefreshTokenCookie = "periscope_refresh"
+)
+
+// RequireAuth rejects requests without a valid Supabase access token or Periscope API key
+// with 401 and stores the user's ID, and the token claims or API key, in the context. The
+// token is read from the Authorization bearer header, the session cookie set at sign-in, or
+// the access_token query parameter for event streams and WebSocket upgrades, since browsers
+// cannot set headers on EventSource or WebSocket requests.
+// Google ID tokens are accepted when google and users are set, acting as the user provisioned
+// for the Google account; API keys are only accepted when apiKeys is set. When users is set,
+// each user is recorded and those an admin has disabled are rejected with 403. A nil
+// verifier disables authentication, for single-user local setups: requests then run without
+// a user.
+func RequireAuth(verifier *auth.Verifier, google *auth.GoogleVerifier, apiKeys *repository.APIKeyRepository, users *repository.UserRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if verifier == nil {
+			c.Next()
+			return
+		}
+
+		token := bearerToken(c)
+		if token == "" {
+			appErr := errors.NewUnauthorizedError("missing access token")
+			c.Header("WWW-Authenticate", `Bearer realm="periscope"`)
+			abortWithError(c, appErr)
+			return
+		}
+		if auth.IsAPIKey(token) {
+			authenticateAPIKey(c, apiKeys, users, token)
+			return
+		}
+		if google != nil && users != nil && auth.IsGoogleToken(token) {
+			authenticateGoogle(c, google, users, token)
+			return
+		}
+
+		claims, err := verifier.Verify(c.Request.Context(), token, time.Now())
+		if err != nil {
+			if stderrors.Is(err, auth.ErrKeysUnavailable) {
+				appErr := errors.NewServiceUnavailableError("unable to verify access token")
+				abortWithError(c, appErr)
+				return
+			}
+			appErr := errors.NewUnauthorizedError(err.Error())
+			c.Header("WWW-Authenticate", `Bearer realm="periscope", error="invalid_token"`)
+			abortWithError(c, appErr)
+			return
+		}
+
+		var email *string
+		if claims.Email != "" {
+			email = &claims.Email
+		}
+		if !admitUser(c, users, claims.Subject, email) {
+			return
+		}
+
+		c.Set(UserIDKey, claims.Subject)
+		c.Set(AuthClaimsKey, claims)
+		c.Next()
+	}
+}
+
+// OptionalAuth authenticates requests that carry a token like RequireAuth, and lets those
+// without one through anonymously, for public endpoints that attribute their use to signed-in
+// callers. A token that fails verification is still rejected.
+func OptionalAuth(verifier *auth.Verifier, google *auth.GoogleVerifier, apiKeys *repository.APIKeyRepository, users *repository.UserRepository) gin.HandlerFunc {
+	requireAuth := RequireAuth(verifier, google, apiKeys, users)
+	return func(c *gin.Context) {
+		if bearerToken(c) == "" {
+			c.Next()
+			return
+		}
+		requireAuth(c)
+	}
+}
+
+// RequireAdmin rejects requests from users without the admin role with 403. Admins are
+// identified by their access token alone: API keys never carry the role, so a leaked key
+// cannot reach the admin API.
+func RequireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, _ := c.Get(AuthClaimsKey)
+		claims, _ := value.(*auth.Claims)
+		if claims == nil || !claims.IsAdmin() {
+			appErr := errors.NewForbiddenError("admin role required")
+			abortWithError(c, appErr)
+			return
+		}
+		c.Next()
+	}
+}
+
+// admitUser records the user's request and reports whether they may continue, aborting the
+// request when an admin has disabled them. Users are let through unrecorded while the
+// database is still being reconnected.
+func admitUser(c *gin.Context, users *repository.UserRepository, id string, email *string) bool {
+	if users == nil || !users.Connected() {
+		return true
+	}
+	user, err := users.Touch(c.Request.Context(), id, email, time.Now())
+	if err != nil {
+		log.Printf("[Auth] ✗ Failed to record user: %v", err)
+		appErr := errors.NewInternalError("failed to verify user", err)
+		abortWithError(c, appErr)
+		return false
+	}
+	if user.Disabled() {
+		appErr := errors.NewForbiddenError("account disabled")
+		abortWithError(c, appErr)
+		return false
+	}
+	c.Set(UserKey, user)
+	return true
+}
+
+// authenticateGoogle continues the request as the user a Google account signs in as,
+// provisioning one on the account's first request
+func authenticateGoogle(c *gin.Context, google *auth.GoogleVerifier, users *repository.UserRepository, token string) {
+	now := time.Now()
+	claims, err := google.Verify(c.Request.Context(), token, now)
+	if err != nil {
+		if stderrors.Is(err, auth.ErrKeysUnavailable) {
+			appErr := errors.NewServiceUnavailableError("unable to verify Google ID token")
+			abortWithError(c, appErr)
+			return
+		}
+		appErr := errors.NewUnauthorizedError(err.Error())
+		c.Header("WWW-Authenticate", `Bearer realm="periscope", error="invalid_token"`)
+		abortWithError(c, appErr)
+		return
+	}
+
+	// Only an address Google has verified is recorded; the account itself is identified by
+	// its subject
+	var email *string
+	if claims.EmailVerified && claims.Email != "" {
+		email = &claims.Email
+	}
+	user, err := users.TouchGoogle(c.Request.Context(), claims.Subject, email, now)
+	if err != nil {
+		log.Printf("[Auth] ✗ Failed to provision Google user: %v", err)
+		appErr := errors.NewInternalError("failed to verify user", err)
+		abortWithError(c, appErr)
+		return
+	}
+	if user.Disabled() {
+		appErr := errors.NewForbiddenError("account disabled")
+		abortWithError(c, appErr)
+		return
+	}
+
+	c.Set(UserKey, user)
+	c.Set(UserIDKey, user.ID)
+	c.Set(GoogleClaimsKey, claims)
+	c.Next()
+}
+
+// authenticateAPIKey continues the request as the owner of an active API key
+func authenticateAPIKey(c *gin.Context, apiKeys *repository.APIKeyRepository, users *repository.UserRepository, token string) {
+	if apiKeys == nil {
+		appErr := errors.NewUnauthorizedError("API keys are not accepted")
+		abortWithError(c, appErr)
+		return
+	}
+	now := time.Now()
+	key, err := apiKeys.Use(c.Request.Context(), auth.HashAPIKey(token), now)
+	if err != nil && !stderrors.Is(err, repository.ErrNotFound) {
+		log.Printf("[Auth] ✗ Failed to look up API key: %v", err)
+		appErr := errors.NewInternalError("failed to verify API key", err)
+		abortWithError(c, appErr)
+		return
+	}
+	if key == nil || !key.Active(now) {
+		appErr := errors.NewUnauthorizedError("invalid, expired or revoked API key")
+		c.Header("WWW-Authenticate", `Bearer realm="periscope", error="invalid_token"`)
+		abortWithError(c, appErr)
+		return
+	}
+	if !admitUser(c, users, key.UserID, nil) {
+		return
+	}
+
+	c.Set(UserIDKey, key.UserID)
+	c.Set(APIKeyKey, key)
+	c.Next()
+}
+
+// bearerToken returns the request's access token, or "" when it has none
+func bearerToken(c *gin.Context) string {
+	if header := c.GetHeader("Authorization"); header != "" {
+		scheme, token, ok := strings.Cut(header, " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") {
+			return ""
+		}
+		return strings.TrimSpace(token)
+	}
+	if token, err := c.Cookie(AccessTokenCookie); err == nil && token != "" {
+		return token
+	}
+	if strings.Contains(c.GetHeader("Accept"), "text/event-stream") || c.IsWebsocket() {
+		return c.Query("access_token")
+	}
+	return ""
+}
@@ -0,0 +1,202 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// compressibleTypes are the content types worth compressing; everything else, such as images
+// and already-zipped XLSX exports, is sent as the handler wrote it. Event streams are left
+// alone too, so each event reaches the client as it is sent.
+var compressibleTypes = []string{
+	"application/json",
+	"application/x-ndjson",
+	"application/x-protobuf",
+	"application/msgpack",
+	"application/xml",
+	"application/javascript",
+	"text/",
+}
+
+// encoder is a gzip or deflate writer
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// Compress gzip- or deflate-encodes responses for clients that accept it, at a compression
+// level from 1 (fastest) to 9 (smallest). Bodies shorter than minSize bytes are sent as they
+// are, since compressing them costs more than it saves. Responses that set their own
+// Content-Encoding, such as /metrics, pass through.
+func Compress(level, minSize int) gin.HandlerFunc {
+	pools := map[string]*sync.Pool{
+		"gzip": {New: func() any {
+			w, _ := gzip.NewWriterLevel(io.Discard, level)
+			return w
+		}},
+		"deflate": {New: func() any {
+			// HTTP's deflate is the zlib format, not raw DEFLATE
+			w, _ := zlib.NewWriterLevel(io.Discard, level)
+			return w
+		}},
+	}
+
+	return func(c *gin.Context) {
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+		encoding := acceptedEncoding(c.GetHeader("Accept-Encoding"))
+		if encoding == "" || c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+
+		w := &compressWriter{ResponseWriter: c.Writer, encoding: encoding, pool: pools[encoding], minSize: minSize}
+		c.Writer = w
+		defer func() {
+			w.finish()
+			c.Writer = w.ResponseWriter
+		}()
+		c.Next()
+	}
+}
+
+// acceptedEncoding picks gzip, else deflate, from an Accept-Encoding header, skipping those
+// refused with q=0; "" when neither is accepted
+func acceptedEncoding(header string) string {
+	accepted := map[string]bool{}
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		ok := true
+		if q, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			if v, err := strconv.ParseFloat(q, 64); err == nil && v == 0 {
+				ok = false
+			}
+		}
+		accepted[strings.ToLower(strings.TrimSpace(name))] = ok
+	}
+	for _, encoding := range []string{"gzip", "deflate"} {
+		if ok, listed := accepted[encoding]; ok || (!listed && accepted["*"]) {
+			return encoding
+		}
+	}
+	return ""
+}
+
+// compressWriter holds back the start of a body until it reaches minSize, then encodes it
+// and everything after it. Whether to compress is decided on the first write, once the
+// handler has set its headers.
+type compressWriter struct {
+	gin.ResponseWriter
+	encoding string
+	pool     *sync.Pool
+	minSize  int
+
+	decided bool // the first write has been seen
+	plain   bool // the body is sent unencoded
+	buf     []byte
+	enc     encoder
+}
+
+func (w *compressWriter) Write(p []byte) (int, error) {
+	if !w.decided {
+		w.decided = true
+		w.plain = !w.compressible()
+	}
+	switch {
+	case w.plain:
+		return w.ResponseWriter.Write(p)
+	case w.enc != nil:
+		return w.enc.Write(p)
+	}
+
+	w.buf = append(w.buf, p...)
+	if len(w.buf) >= w.minSize {
+		if err := w.start(); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Written reports a held-back body as written, so later middleware does not answer again
+func (w *compressWriter) Written() bool {
+	return len(w.buf) > 0 || w.enc != nil || w.ResponseWriter.Written()
+}
+
+// Flush starts encoding whatever is held back, so a streamed response is not delayed until
+// it reaches minSize, and sends what has been encoded so far
+func (w *compressWriter) Flush() {
+	if w.decided && !w.plain && w.enc == nil && len(w.buf) > 0 {
+		_ = w.start()
+	}
+	if w.enc != nil {
+		_ = w.enc.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// Unwrap returns the wrapped writer, so http.ResponseController reaches the connection to set
+// deadlines on streams
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// compressible reports whether the response about to be written should be encoded
+func (w *compressWriter) compressible() bool {
+	h := w.Header()
+	if h.Get("Content-Encoding") != "" {
+		return false
+	}
+	switch w.Status() {
+	case http.StatusNoContent, http.StatusNotModified, http.StatusPartialContent:
+		return false
+	}
+	contentType := h.Get("Content-Type")
+	if strings.HasPrefix(contentType, "text/event-stream") {
+		return false
+	}
+	for _, prefix := range compressibleTypes {
+		if strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// start sets the encoding headers and encodes the held-back bytes
+func (w *compressWriter) start() error {
+	h := w.Header()
+	h.Set("Content-Encoding", w.encoding)
+	h.Del("Content-Length")
+	w.enc = w.pool.Get().(encoder)
+	w.enc.Reset(w.ResponseWriter)
+	_, err := w.enc.Write(w.buf)
+	w.buf = nil
+	return err
+}
+
+// finish writes out a short body as it is, or completes the encoded one
+func (w *compressWriter) finish() {
+	if w.enc != nil {
+		_ = w.enc.Close()
+		w.enc.Reset(io.Discard)
+		w.pool.Put(w.enc)
+		w.enc = nil
+		return
+	}
+	if len(w.buf) > 0 {
+		_, _ = w.ResponseWriter.Write(w.buf)
+		w.buf = nil
+	}
+}
@@ -0,0 +1,40 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/api/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func TestCompressHeldBackBodyCountsAsWritten(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	var errs []*gin.Error
+	router.Use(middleware.Errors(), middleware.Compress(6, 1024), func(c *gin.Context) {
+		c.Next()
+		errs = c.Errors
+	})
+	router.GET("/slow", middleware.Timeout(10*time.Millisecond), func(c *gin.Context) {
+		<-c.Request.Context().Done()
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/slow", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK || w.Body.String() != `{"ok":true}` {
+		t.Errorf("got %d %s", w.Code, w.Body)
+	}
+	if len(errs) > 0 {
+		t.Errorf("answered body was also timed out: %v", errs)
+	}
+	if w.Header().Get("Content-Encoding") != "" {
+		t.Errorf("short body was encoded: %s", w.Header().Get("Content-Encoding"))
+	}
+}
@@ -0,0 +1,48 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/api/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func TestCORSEchoesOnlyAllowedOrigins(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.CORS([]string{"http://localhost:3000"}))
+	router.GET("/ping", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	tests := []struct {
+		origin      string
+		method      string
+		allowOrigin string
+		credentials string
+	}{
+		{"http://localhost:3000", http.MethodGet, "http://localhost:3000", "true"},
+		{"http://localhost:3000", http.MethodOptions, "http://localhost:3000", "true"},
+		{"https://evil.example", http.MethodGet, "", ""},
+		{"https://evil.example", http.MethodOptions, "", ""},
+		{"", http.MethodGet, "", ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, "/ping", nil)
+		if tt.origin != "" {
+			req.Header.Set("Origin", tt.origin)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tt.allowOrigin {
+			t.Errorf("%s from %q: Access-Control-Allow-Origin = %q, want %q", tt.method, tt.origin, got, tt.allowOrigin)
+		}
+		if got := rec.Header().Get("Access-Control-Allow-Credentials"); got != tt.credentials {
+			t.Errorf("%s from %q: Access-Control-Allow-Credentials = %q, want %q", tt.method, tt.origin, got, tt.credentials)
+		}
+		if got := rec.Header().Get("Vary"); got != "Origin" {
+			t.Errorf("%s from %q: Vary = %q, want Origin", tt.method, tt.origin, got)
+		}
+	}
+}
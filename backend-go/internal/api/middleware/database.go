@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"github.com/aaronbengochea/periscope/backend-go/pkg/database"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/database/rest"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireDatabase rejects requests with 503 when the server runs without a database connection
+func RequireDatabase(db *database.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !db.Connected() {
+			appErr := errors.NewServiceUnavailableError("database not connected")
+			abortWithError(c, appErr)
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireStorage is RequireDatabase for routes whose data can also be persisted through the
+// Supabase REST API, letting requests through when client is set instead
+func RequireStorage(db *database.DB, client *rest.Client) gin.HandlerFunc {
+	if client != nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return RequireDatabase(db)
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Deprecated marks the responses of the routes it guards as deprecated since deprecatedAt
+// and due to be removed at sunset, with the Deprecation (RFC 9745) and Sunset (RFC 8594)
+// headers and a Link to the same path under successor, which replaces prefix
+func Deprecated(deprecatedAt, sunset time.Time, prefix, successor string) gin.HandlerFunc {
+	deprecation := "@" + strconv.FormatInt(deprecatedAt.Unix(), 10)
+	sunsetDate := sunset.UTC().Format(http.TimeFormat)
+	return func(c *gin.Context) {
+		c.Header("Deprecation", deprecation)
+		c.Header("Sunset", sunsetDate)
+		if path, ok := strings.CutPrefix(c.Request.URL.Path, prefix); ok {
+			c.Header("Link", "<"+successor+path+`>; rel="successor-version"`)
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,208 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WarningsKey is the gin context key of the warnings a handler raised about its response
+const WarningsKey = "warnings"
+
+// AddWarning records something the client should know about an otherwise successful
+// response, such as data that is stale or incomplete. API v2 returns the warnings in its
+// envelope; v1 responses leave them out.
+func AddWarning(c *gin.Context, warning string) {
+	warnings := c.GetStringSlice(WarningsKey)
+	c.Set(WarningsKey, append(warnings, warning))
+}
+
+// Envelope is the body of a successful API v2 JSON response
+type Envelope struct {
+	Data       json.RawMessage `json:"data"`
+	Meta       EnvelopeMeta    `json:"meta"`
+	Pagination *Pagination     `json:"pagination,omitempty"`
+	Warnings   []string        `json:"warnings"`
+}
+
+// EnvelopeMeta describes the response
+type EnvelopeMeta struct {
+	RequestID   string    `json:"request_id"`
+	APIVersion  string    `json:"api_version"`
+	GeneratedAt time.Time `json:"generated_at"`
+}
+
+// Pagination gathers the paging parameters of a request and where the next page starts. It
+// is sent for paged requests and for responses with a next_cursor.
+type Pagination struct {
+	Cursor     string `json:"cursor,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+	Limit      *int   `json:"limit,omitempty"`
+	Offset     *int   `json:"offset,omitempty"`
+	PageSize   *int   `json:"page_size,omitempty"`
+}
+
+// EnvelopeV2 wraps the successful JSON responses of the routes it guards in an Envelope: the
+// v1 body under data, the next_cursor it carried moved into pagination, and the handler's
+// warnings. Errors keep the ErrorResponse shape, and every other response (event and NDJSON
+// streams, binary encodings, downloads) is sent as the handler wrote it.
+func EnvelopeV2() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+
+		w := &envelopeWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		defer func() {
+			c.Writer = w.ResponseWriter
+			if w.wrap {
+				writeEnvelope(c, w.buf.Bytes())
+			}
+		}()
+		c.Next()
+	}
+}
+
+// writeEnvelope sends body, a v1 JSON response, inside an Envelope
+func writeEnvelope(c *gin.Context, body []byte) {
+	envelope := Envelope{
+		Data: body,
+		Meta: EnvelopeMeta{
+			RequestID:   c.GetString(RequestIDKey),
+			APIVersion:  "v2",
+			GeneratedAt: time.Now().UTC(),
+		},
+		Pagination: requestPagination(c),
+		Warnings:   c.GetStringSlice(WarningsKey),
+	}
+	if envelope.Warnings == nil {
+		envelope.Warnings = []string{}
+	}
+
+	// A paged body's next_cursor moves into pagination
+	if data, raw, ok := cutMember(body, "next_cursor"); ok {
+		var next string
+		_ = json.Unmarshal(raw, &next)
+		envelope.Data = data
+		if envelope.Pagination == nil {
+			envelope.Pagination = &Pagination{}
+		}
+		envelope.Pagination.NextCursor = next
+		envelope.Pagination.HasMore = next != ""
+	}
+
+	out, err := json.Marshal(envelope)
+	if err != nil {
+		// The body was valid JSON, so this cannot happen; send it as it was
+		out = body
+	}
+	c.Writer.Header().Del("Content-Length")
+	_, _ = c.Writer.Write(out)
+}
+
+// cutMember removes the member name from body, a JSON object, returning the object without
+// it and the member's value. The other members are copied as they were written, keeping their
+// order and their numbers' precision. ok is false if body is not an object or lacks the member.
+func cutMember(body []byte, name string) (rest []byte, value json.RawMessage, ok bool) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, nil, false
+	}
+
+	var out bytes.Buffer
+	out.WriteByte('{')
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, nil, false
+		}
+		key, _ := tok.(string)
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, nil, false
+		}
+		if key == name && value == nil {
+			value = raw
+			continue
+		}
+		if out.Len() > 1 {
+			out.WriteByte(',')
+		}
+		keyJSON, err := json.Marshal(key)
+		if err != nil {
+			return nil, nil, false
+		}
+		out.Write(keyJSON)
+		out.WriteByte(':')
+		out.Write(raw)
+	}
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('}') || value == nil {
+		return nil, nil, false
+	}
+	out.WriteByte('}')
+	return out.Bytes(), value, true
+}
+
+// requestPagination returns the paging parameters the request sent, or nil if it sent none
+func requestPagination(c *gin.Context) *Pagination {
+	p := &Pagination{Cursor: c.Query("cursor")}
+	number := func(name string) *int {
+		n, err := strconv.Atoi(c.Query(name))
+		if err != nil {
+			return nil
+		}
+		return &n
+	}
+	p.Limit, p.Offset, p.PageSize = number("limit"), number("offset"), number("page_size")
+	if p.Cursor == "" && p.Limit == nil && p.Offset == nil && p.PageSize == nil {
+		return nil
+	}
+	return p
+}
+
+// envelopeWriter holds back a successful JSON body so it can be wrapped once the handler is
+// done, and passes every other response straight through. Which it is is decided on the
+// first write, once the handler has set its status and headers.
+type envelopeWriter struct {
+	gin.ResponseWriter
+
+	decided bool // the first write has been seen
+	wrap    bool // the body is held back to be wrapped
+	buf     bytes.Buffer
+}
+
+func (w *envelopeWriter) Write(p []byte) (int, error) {
+	if !w.decided {
+		w.decided = true
+		status := w.Status()
+		w.wrap = status >= 200 && status < 300 && status != http.StatusNoContent &&
+			strings.HasPrefix(w.Header().Get("Content-Type"), "application/json")
+	}
+	if w.wrap {
+		return w.buf.Write(p)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *envelopeWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Written reports a held-back body as written, so later middleware does not answer again
+func (w *envelopeWriter) Written() bool {
+	return w.wrap || w.ResponseWriter.Written()
+}
+
+// Unwrap returns the wrapped writer, so http.ResponseController reaches the connection to set
+// deadlines on streams
+func (w *envelopeWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,58 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/api/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func envelopeRouter(body string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/v2/things", middleware.EnvelopeV2(), func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json; charset=utf-8", []byte(body))
+	})
+	return router
+}
+
+func TestEnvelopeMovesNextCursor(t *testing.T) {
+	body := `{"zeta":1,"items":[{"id":12345678901234567890,"price":0.10000000000000000001}],"next_cursor":"abc","alpha":"first"}`
+	w := serve(envelopeRouter(body), "", http.MethodGet, "/api/v2/things?limit=1", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("got %d %s", w.Code, w.Body)
+	}
+	var envelope middleware.Envelope
+	if err := json.Unmarshal(w.Body.Bytes(), &envelope); err != nil {
+		t.Fatal(err)
+	}
+
+	// The other members keep their order and their numbers as written
+	want := `{"zeta":1,"items":[{"id":12345678901234567890,"price":0.10000000000000000001}],"alpha":"first"}`
+	if string(envelope.Data) != want {
+		t.Errorf("data:\n got %s\nwant %s", envelope.Data, want)
+	}
+	p := envelope.Pagination
+	if p == nil || p.NextCursor != "abc" || !p.HasMore || p.Limit == nil || *p.Limit != 1 {
+		t.Errorf("pagination: got %+v", p)
+	}
+}
+
+func TestEnvelopeKeepsBodiesWithoutCursor(t *testing.T) {
+	for _, body := range []string{
+		`{"b":2,"a":1.000000000000000000001}`,
+		`[{"next_cursor":"not paging"}]`,
+		`{"nested":{"next_cursor":"x"}}`,
+	} {
+		w := serve(envelopeRouter(body), "", http.MethodGet, "/api/v2/things", "")
+		var envelope middleware.Envelope
+		if err := json.Unmarshal(w.Body.Bytes(), &envelope); err != nil {
+			t.Fatal(err)
+		}
+		if string(envelope.Data) != body || envelope.Pagination != nil {
+			t.Errorf("%s: got data %s, pagination %+v", body, envelope.Data, envelope.Pagination)
+		}
+	}
+}
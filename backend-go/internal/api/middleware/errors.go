@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	stderrors "errors"
+	"fmt"
+	"log"
+
+	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorResponse is the body of every error response
+type ErrorResponse struct {
+	Error ErrorBody `json:"error"`
+}
+
+// ErrorBody describes what went wrong: a stable code, a message for people, the ID of the
+// request for support and, for some errors, structured details
+type ErrorBody struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	RequestID string `json:"request_id,omitempty"`
+	Details   any    `json:"details,omitempty"`
+}
+
+// NewErrorResponse builds the error envelope of appErr for the request. Handlers that have
+// already started a stream write it themselves; everything else attaches the error with
+// c.Error and leaves the response to Errors.
+func NewErrorResponse(c *gin.Context, appErr *errors.AppError) ErrorResponse {
+	return ErrorResponse{Error: ErrorBody{
+		Code:      appErr.Code,
+		Message:   appErr.Message,
+		RequestID: c.GetString(RequestIDKey),
+		Details:   appErr.Details,
+	}}
+}
+
+// Errors sends the last error a handler or middleware attached with c.Error as an
+// ErrorResponse, with the AppError's status. Errors that are not AppErrors are answered as
+// internal errors without their text, and bad requests from a failed body binding are
+// explained field by field (see describeBindingError). Errors wrapping a cause, and every
+// server error, are logged with it. Nothing is sent when the response has already been
+// written.
+func Errors() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		last := c.Errors.Last()
+		if last == nil {
+			return
+		}
+		var appErr *errors.AppError
+		if !stderrors.As(last.Err, &appErr) {
+			appErr = errors.NewInternalError("internal server error", last.Err)
+		}
+		appErr = describeBindingError(appErr)
+
+		if appErr.Err != nil || appErr.StatusCode >= 500 {
+			mark := "⚠"
+			if appErr.StatusCode >= 500 {
+				mark = "✗"
+			}
+			log.Printf("[Error] %s %s %s | %d %s | %v | %s",
+				mark, c.Request.Method, c.Request.URL.Path, appErr.StatusCode, appErr.Code, appErr, c.GetString(RequestIDKey))
+		}
+
+		if c.Writer.Written() {
+			return
+		}
+		c.JSON(appErr.StatusCode, NewErrorResponse(c, appErr))
+	}
+}
+
+// Recovery turns a panic into an internal error for Errors to send, so the client gets the
+// standard envelope with its request ID. It must come after Errors in the chain; gin logs the
+// panic with its stack.
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecovery(func(c *gin.Context, recovered any) {
+		abortWithError(c, errors.NewInternalError("internal server error", fmt.Errorf("panic: %v", recovered)))
+	})
+}
+
+// abortWithError stops the chain and leaves appErr for Errors to send
+func abortWithError(c *gin.Context, appErr *errors.AppError) {
+	c.Abort()
+	_ = c.Error(appErr)
+}
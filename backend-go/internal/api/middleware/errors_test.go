@@ -0,0 +1,32 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/api/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func TestRecoveryAnswersPanicsWithEnvelope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.RequestID(), middleware.Errors(), middleware.Recovery())
+	router.GET("/boom", func(c *gin.Context) { panic("boom") })
+
+	w := serve(router, "", http.MethodGet, "/boom", "")
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("got %d, want 500", w.Code)
+	}
+	var resp middleware.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("body %q is not an error envelope: %v", w.Body, err)
+	}
+	if resp.Error.Code != "internal_error" || resp.Error.RequestID == "" {
+		t.Errorf("got %+v, want internal_error with a request ID", resp.Error)
+	}
+	if resp.Error.RequestID != w.Header().Get("X-Request-ID") {
+		t.Errorf("request_id %q does not match X-Request-ID %q", resp.Error.RequestID, w.Header().Get("X-Request-ID"))
+	}
+}
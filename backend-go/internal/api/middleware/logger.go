@@ -22,13 +22,14 @@ func Logger() gin.HandlerFunc {
 		statusCode := c.Writer.Status()
 		clientIP := c.ClientIP()
 
-		log.Printf("[%s] %s %s | %d | %v | %s",
+		log.Printf("[%s] %s %s | %d | %v | %s | %s",
 			method,
 			path,
 			c.Request.Proto,
 			statusCode,
 			latency,
 			clientIP,
+			c.GetString(RequestIDKey),
 		)
 	}
 }
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"log"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/secrets"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/massive"
+	"github.com/gin-gonic/gin"
+)
+
+// UseOwnMassiveKey makes the Massive API calls of a recorded user who has stored their own
+// key with that key, so they count against the user's plan rather than the server's. A key
+// that no longer decrypts, as after the encryption key is changed, is logged and the
+// server's key used instead. Without a box, every request uses the server's key.
+func UseOwnMassiveKey(box *secrets.Box) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, _ := c.Get(UserKey)
+		user, _ := value.(*models.User)
+		if box == nil || user == nil || user.MassiveKeyCiphertext == nil {
+			c.Next()
+			return
+		}
+
+		key, err := box.Open(*user.MassiveKeyCiphertext, user.ID)
+		if err != nil {
+			log.Printf("[Auth] ⚠ Failed to decrypt Massive key of user %s: %v", user.ID, err)
+			c.Next()
+			return
+		}
+		c.Request = c.Request.WithContext(massive.WithAPIKey(c.Request.Context(), key))
+		c.Next()
+	}
+}
@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// rateLimitPeriod is the window requests are counted in
+const rateLimitPeriod = time.Minute
+
+// rateWindow counts one caller's requests in the current window
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// rateWindows holds the windows of every caller of a route group
+type rateWindows struct {
+	mu      sync.Mutex
+	windows map[string]*rateWindow
+	sweptAt time.Time
+}
+
+// take counts a request of key at now and returns how many the caller has left in the
+// window, whether the request is allowed and when the window ends
+func (w *rateWindows) take(key string, limit int, period time.Duration, now time.Time) (int, bool, time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	// Drop the windows of callers who have gone quiet, at most once a period
+	if now.Sub(w.sweptAt) >= period {
+		for k, win := range w.windows {
+			if now.Sub(win.start) >= period {
+				delete(w.windows, k)
+			}
+		}
+		w.sweptAt = now
+	}
+
+	win, ok := w.windows[key]
+	if !ok || now.Sub(win.start) >= period {
+		win = &rateWindow{start: now}
+		w.windows[key] = win
+	}
+	reset := win.start.Add(period)
+	if win.count >= limit {
+		return 0, false, reset
+	}
+	win.count++
+	return limit - win.count, true, reset
+}
+
+// RateLimit allows each caller perMinute requests a minute on the routes it guards, counting
+// signed-in users by user ID and everyone else by client IP, so it goes after the auth
+// middleware. Each call makes a separate budget, shared by the routes it is given to.
+// Responses carry X-RateLimit-Limit, X-RateLimit-Remaining and X-RateLimit-Reset (the Unix
+// time the window ends); requests over the limit are refused with 429 and Retry-After.
+// Counts are kept in memory, so each server instance limits on its own. A limit below 1
+// disables it.
+func RateLimit(perMinute int) gin.HandlerFunc {
+	if perMinute < 1 {
+		return func(c *gin.Context) { c.Next() }
+	}
+	windows := &rateWindows{windows: make(map[string]*rateWindow)}
+
+	return func(c *gin.Context) {
+		key := "ip:" + c.ClientIP()
+		if id := c.GetString(UserIDKey); id != "" {
+			key = "user:" + id
+		}
+
+		now := time.Now()
+		remaining, allowed, reset := windows.take(key, perMinute, rateLimitPeriod, now)
+		c.Header("X-RateLimit-Limit", strconv.Itoa(perMinute))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		if !allowed {
+			retryAfter := int(reset.Sub(now).Round(time.Second) / time.Second)
+			c.Header("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+			appErr := errors.NewRateLimitError(fmt.Sprintf("rate limit of %d requests per minute exceeded", perMinute))
+			abortWithError(c, appErr)
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/aaronbengochea/periscope/backend-go/pkg/massive"
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDKey is the gin context key of the request's ID
+const RequestIDKey = "request_id"
+
+// maxRequestIDLength bounds the incoming request IDs that are honoured
+const maxRequestIDLength = 128
+
+// RequestID gives each request an ID: the caller's X-Request-ID when it sends a usable one,
+// otherwise a random one. The ID is stored under RequestIDKey, returned in the X-Request-ID
+// response header and sent with the Massive API calls made for the request.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(massive.RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+
+		c.Set(RequestIDKey, id)
+		c.Header(massive.RequestIDHeader, id)
+		c.Request = c.Request.WithContext(massive.WithRequestID(c.Request.Context(), id))
+		c.Next()
+	}
+}
+
+// validRequestID reports whether an incoming ID is short and made of characters that are
+// safe to echo in a header and write to the logs
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID returns 16 random bytes hex encoded
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireScope rejects requests made with an API key that lacks the route's scope with 403.
+// Safe requests (GET, HEAD, OPTIONS) need read and the rest need write; routes that only
+// read pass the same scope for both. Requests without an API key, made from a session or
+// anonymously where a route allows it, are not scoped.
+func RequireScope(read, write string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, ok := c.Get(APIKeyKey)
+		if !ok {
+			c.Next()
+			return
+		}
+		key, _ := value.(*models.APIKey)
+
+		scope := write
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			scope = read
+		}
+		if key == nil || !key.HasScope(scope) {
+			appErr := errors.NewForbiddenError("API key lacks the " + scope + " scope")
+			c.Header("WWW-Authenticate", `Bearer realm="periscope", error="insufficient_scope", scope="`+scope+`"`)
+			abortWithError(c, appErr)
+			return
+		}
+		c.Next()
+	}
+}
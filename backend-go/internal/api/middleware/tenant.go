@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	stderrors "errors"
+	"log"
+	"strconv"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// RequirePortfolioOwner rejects requests for a portfolio the user does not own with 404, the
+// same response as for a portfolio that does not exist, so other users' portfolio IDs cannot
+// be probed. It guards every route under /portfolio/:id, including those whose handlers only
+// read the portfolio's positions, orders or journal. Routes without an :id pass through, as
+// do malformed IDs, which the handler rejects.
+func RequirePortfolioOwner(portfolios repository.PortfolioRepo) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.Next()
+			return
+		}
+
+		var user *string
+		if id := c.GetString(UserIDKey); id != "" {
+			user = &id
+		}
+		if _, err := portfolios.Get(c.Request.Context(), user, id); err != nil {
+			if stderrors.Is(err, repository.ErrNotFound) {
+				appErr := errors.NewNotFoundError("portfolio not found")
+				abortWithError(c, appErr)
+				return
+			}
+			log.Printf("[Auth] ✗ Failed to check portfolio owner: %v", err)
+			appErr := errors.NewInternalError("failed to get portfolio", err)
+			abortWithError(c, appErr)
+			return
+		}
+		c.Next()
+	}
+}
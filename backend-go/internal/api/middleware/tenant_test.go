@@ -0,0 +1,678 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/api/handlers"
+	"github.com/aaronbengochea/periscope/backend-go/internal/api/middleware"
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	alice = "00000000-0000-0000-0000-00000000000a"
+	bob   = "00000000-0000-0000-0000-00000000000b"
+)
+
+// sameOwner matches owners as user_id IS NOT DISTINCT FROM does
+func sameOwner(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// fakePortfolios keeps portfolios in memory, scoping them to their owner as the
+// repositories do
+type fakePortfolios struct {
+	mu         sync.Mutex
+	portfolios map[int64]models.Portfolio
+	deleted    map[int64]bool
+}
+
+func newFakePortfolios(portfolios ...models.Portfolio) *fakePortfolios {
+	f := &fakePortfolios{portfolios: make(map[int64]models.Portfolio), deleted: make(map[int64]bool)}
+	for _, p := range portfolios {
+		f.portfolios[p.ID] = p
+	}
+	return f
+}
+
+func (f *fakePortfolios) Create(ctx context.Context, p *models.Portfolio) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	p.ID = int64(len(f.portfolios) + 1)
+	f.portfolios[p.ID] = *p
+	return nil
+}
+
+func (f *fakePortfolios) list(userID *string, deleted bool) []models.Portfolio {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []models.Portfolio
+	for id, p := range f.portfolios {
+		if sameOwner(p.UserID, userID) && f.deleted[id] == deleted {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func (f *fakePortfolios) List(ctx context.Context, userID *string) ([]models.Portfolio, error) {
+	return f.list(userID, false), nil
+}
+
+func (f *fakePortfolios) ListAll(ctx context.Context) ([]models.Portfolio, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []models.Portfolio
+	for id, p := range f.portfolios {
+		if !f.deleted[id] {
+			out = append(out, p)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakePortfolios) ListDeleted(ctx context.Context, userID *string) ([]models.Portfolio, error) {
+	return f.list(userID, true), nil
+}
+
+func (f *fakePortfolios) find(userID *string, id int64, deleted bool) (*models.Portfolio, error) {
+	p, ok := f.portfolios[id]
+	if !ok || !sameOwner(p.UserID, userID) || f.deleted[id] != deleted {
+		return nil, repository.ErrNotFound
+	}
+	return &p, nil
+}
+
+func (f *fakePortfolios) Get(ctx context.Context, userID *string, id int64) (*models.Portfolio, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.find(userID, id, false)
+}
+
+func (f *fakePortfolios) GetAny(ctx context.Context, id int64) (*models.Portfolio, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	p, ok := f.portfolios[id]
+	if !ok || f.deleted[id] {
+		return nil, repository.ErrNotFound
+	}
+	return &p, nil
+}
+
+func (f *fakePortfolios) Update(ctx context.Context, p *models.Portfolio) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, err := f.find(p.UserID, p.ID, false); err != nil {
+		return err
+	}
+	f.portfolios[p.ID] = *p
+	return nil
+}
+
+func (f *fakePortfolios) Delete(ctx context.Context, userID *string, id int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, err := f.find(userID, id, false); err != nil {
+		return err
+	}
+	f.deleted[id] = true
+	return nil
+}
+
+func (f *fakePortfolios) Restore(ctx context.Context, userID *string, id int64) (*models.Portfolio, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	p, err := f.find(userID, id, true)
+	if err != nil {
+		return nil, err
+	}
+	delete(f.deleted, id)
+	return p, nil
+}
+
+func (f *fakePortfolios) Purge(ctx context.Context, userID *string, id int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, err := f.find(userID, id, true); err != nil {
+		return err
+	}
+	delete(f.portfolios, id)
+	delete(f.deleted, id)
+	return nil
+}
+
+// fakeWatchlists keeps watchlists in memory, scoping them to their owner as the
+// repositories do
+type fakeWatchlists struct {
+	mu         sync.Mutex
+	watchlists map[int64]models.Watchlist
+	deleted    map[int64]bool
+}
+
+func newFakeWatchlists(watchlists ...models.Watchlist) *fakeWatchlists {
+	f := &fakeWatchlists{watchlists: make(map[int64]models.Watchlist), deleted: make(map[int64]bool)}
+	for _, w := range watchlists {
+		f.watchlists[w.ID] = w
+	}
+	return f
+}
+
+func (f *fakeWatchlists) find(userID *string, id int64, deleted bool) (*models.Watchlist, error) {
+	w, ok := f.watchlists[id]
+	if !ok || !sameOwner(w.UserID, userID) || f.deleted[id] != deleted {
+		return nil, repository.ErrNotFound
+	}
+	return &w, nil
+}
+
+func (f *fakeWatchlists) Create(ctx context.Context, w *models.Watchlist, tickers []string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w.ID = int64(len(f.watchlists) + 1)
+	for _, t := range tickers {
+		w.Items = append(w.Items, models.WatchlistItem{Ticker: t})
+	}
+	f.watchlists[w.ID] = *w
+	return nil
+}
+
+func (f *fakeWatchlists) list(userID *string, deleted bool) []models.Watchlist {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []models.Watchlist
+	for id, w := range f.watchlists {
+		if sameOwner(w.UserID, userID) && f.deleted[id] == deleted {
+			out = append(out, w)
+		}
+	}
+	return out
+}
+
+func (f *fakeWatchlists) List(ctx context.Context, userID *string) ([]models.Watchlist, error) {
+	return f.list(userID, false), nil
+}
+
+func (f *fakeWatchlists) ListDeleted(ctx context.Context, userID *string) ([]models.Watchlist, error) {
+	return f.list(userID, true), nil
+}
+
+func (f *fakeWatchlists) Get(ctx context.Context, userID *string, id int64) (*models.Watchlist, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.find(userID, id, false)
+}
+
+func (f *fakeWatchlists) Update(ctx context.Context, w *models.Watchlist) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, err := f.find(w.UserID, w.ID, false); err != nil {
+		return err
+	}
+	f.watchlists[w.ID] = *w
+	return nil
+}
+
+func (f *fakeWatchlists) Delete(ctx context.Context, userID *string, id int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, err := f.find(userID, id, false); err != nil {
+		return err
+	}
+	f.deleted[id] = true
+	return nil
+}
+
+func (f *fakeWatchlists) Restore(ctx context.Context, userID *string, id int64) (*models.Watchlist, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w, err := f.find(userID, id, true)
+	if err != nil {
+		return nil, err
+	}
+	delete(f.deleted, id)
+	return w, nil
+}
+
+func (f *fakeWatchlists) Purge(ctx context.Context, userID *string, id int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, err := f.find(userID, id, true); err != nil {
+		return err
+	}
+	delete(f.watchlists, id)
+	delete(f.deleted, id)
+	return nil
+}
+
+func (f *fakeWatchlists) setItems(w *models.Watchlist, tickers []string, replace bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	stored, err := f.find(w.UserID, w.ID, false)
+	if err != nil {
+		return err
+	}
+	if replace {
+		stored.Items = nil
+	}
+	for _, t := range tickers {
+		stored.Items = append(stored.Items, models.WatchlistItem{Ticker: t})
+	}
+	f.watchlists[w.ID] = *stored
+	*w = *stored
+	return nil
+}
+
+func (f *fakeWatchlists) AddItems(ctx context.Context, w *models.Watchlist, tickers []string) error {
+	return f.setItems(w, tickers, false)
+}
+
+func (f *fakeWatchlists) ReplaceItems(ctx context.Context, w *models.Watchlist, tickers []string) error {
+	return f.setItems(w, tickers, true)
+}
+
+func (f *fakeWatchlists) RemoveItem(ctx context.Context, w *models.Watchlist, ticker string) error {
+	return f.setItems(w, nil, false)
+}
+
+var (
+	_ repository.PortfolioRepo = (*fakePortfolios)(nil)
+	_ repository.WatchlistRepo = (*fakeWatchlists)(nil)
+)
+
+// fakeAlerts keeps alerts and their triggers in memory, scoping them to the alert's owner as
+// the repository does
+type fakeAlerts struct {
+	mu       sync.Mutex
+	alerts   map[int64]models.Alert
+	deleted  map[int64]bool
+	triggers map[int64]models.AlertTrigger
+}
+
+func newFakeAlerts(triggers []models.AlertTrigger, alerts ...models.Alert) *fakeAlerts {
+	f := &fakeAlerts{alerts: make(map[int64]models.Alert), deleted: make(map[int64]bool), triggers: make(map[int64]models.AlertTrigger)}
+	for _, a := range alerts {
+		f.alerts[a.ID] = a
+	}
+	for _, t := range triggers {
+		f.triggers[t.ID] = t
+	}
+	return f
+}
+
+func (f *fakeAlerts) find(userID *string, id int64, deleted bool) (*models.Alert, error) {
+	a, ok := f.alerts[id]
+	if !ok || !sameOwner(a.UserID, userID) || f.deleted[id] != deleted {
+		return nil, repository.ErrNotFound
+	}
+	return &a, nil
+}
+
+func (f *fakeAlerts) Create(ctx context.Context, a *models.Alert) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	a.ID = int64(len(f.alerts) + 1)
+	f.alerts[a.ID] = *a
+	return nil
+}
+
+func (f *fakeAlerts) list(userID *string, deleted bool) []models.Alert {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []models.Alert
+	for id, a := range f.alerts {
+		if sameOwner(a.UserID, userID) && f.deleted[id] == deleted {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+func (f *fakeAlerts) List(ctx context.Context, userID *string, status string) ([]models.Alert, error) {
+	return f.list(userID, false), nil
+}
+
+func (f *fakeAlerts) ListDeleted(ctx context.Context, userID *string) ([]models.Alert, error) {
+	return f.list(userID, true), nil
+}
+
+func (f *fakeAlerts) Get(ctx context.Context, userID *string, id int64) (*models.Alert, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.find(userID, id, false)
+}
+
+func (f *fakeAlerts) Update(ctx context.Context, a *models.Alert) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, err := f.find(a.UserID, a.ID, false); err != nil {
+		return err
+	}
+	f.alerts[a.ID] = *a
+	return nil
+}
+
+func (f *fakeAlerts) Delete(ctx context.Context, userID *string, id int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, err := f.find(userID, id, false); err != nil {
+		return err
+	}
+	f.deleted[id] = true
+	return nil
+}
+
+func (f *fakeAlerts) Restore(ctx context.Context, userID *string, id int64) (*models.Alert, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	a, err := f.find(userID, id, true)
+	if err != nil {
+		return nil, err
+	}
+	delete(f.deleted, id)
+	return a, nil
+}
+
+func (f *fakeAlerts) Purge(ctx context.Context, userID *string, id int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, err := f.find(userID, id, true); err != nil {
+		return err
+	}
+	delete(f.alerts, id)
+	delete(f.deleted, id)
+	return nil
+}
+
+func (f *fakeAlerts) Snooze(ctx context.Context, a *models.Alert, until time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	stored, err := f.find(a.UserID, a.ID, false)
+	if err != nil {
+		return err
+	}
+	stored.Status = models.AlertSnoozed
+	stored.SnoozedUntil = &until
+	f.alerts[a.ID] = *stored
+	*a = *stored
+	return nil
+}
+
+func (f *fakeAlerts) ListTriggers(ctx context.Context, userID *string, filter models.AlertTriggerFilter) ([]models.AlertNotification, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []models.AlertNotification
+	for _, t := range f.triggers {
+		a, err := f.find(userID, t.AlertID, false)
+		if err != nil || (filter.AlertID != 0 && t.AlertID != filter.AlertID) {
+			continue
+		}
+		out = append(out, models.AlertNotification{Alert: *a, Trigger: t})
+	}
+	return out, nil
+}
+
+func (f *fakeAlerts) Acknowledge(ctx context.Context, userID *string, triggerID int64) (*models.AlertTrigger, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	t, ok := f.triggers[triggerID]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	if _, err := f.find(userID, t.AlertID, false); err != nil {
+		return nil, err
+	}
+	if t.AcknowledgedAt == nil {
+		now := time.Now()
+		t.AcknowledgedAt = &now
+		f.triggers[triggerID] = t
+	}
+	return &t, nil
+}
+
+func (f *fakeAlerts) AcknowledgeAll(ctx context.Context, userID *string, alertID int64) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var count int64
+	for id, t := range f.triggers {
+		if _, err := f.find(userID, t.AlertID, false); err != nil || t.AcknowledgedAt != nil || (alertID != 0 && t.AlertID != alertID) {
+			continue
+		}
+		now := time.Now()
+		t.AcknowledgedAt = &now
+		f.triggers[id] = t
+		count++
+	}
+	return count, nil
+}
+
+func (f *fakeAlerts) GetEarningsSettings(ctx context.Context, userID *string) (*models.EarningsAlertSettings, error) {
+	return &models.EarningsAlertSettings{UserID: userID}, nil
+}
+
+func (f *fakeAlerts) SaveEarningsSettings(ctx context.Context, s *models.EarningsAlertSettings) error {
+	return nil
+}
+
+// tenantRouter mounts the portfolio and watchlist routes as the API does, signing requests in
+// as the user named by the X-Test-User header
+func tenantRouter(portfolios repository.PortfolioRepo, watchlists repository.WatchlistRepo) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.Errors(), func(c *gin.Context) {
+		if id := c.GetHeader("X-Test-User"); id != "" {
+			c.Set(middleware.UserIDKey, id)
+		}
+		c.Next()
+	})
+
+	portfolioHandler := handlers.NewPortfolioHandler(portfolios, repository.NewAuditRepository(nil))
+	portfolio := router.Group("/api/v1/portfolio", middleware.RequirePortfolioOwner(portfolios))
+	portfolio.GET("/:id", portfolioHandler.GetPortfolio)
+	portfolio.PATCH("/:id", portfolioHandler.UpdatePortfolio)
+	portfolio.DELETE("/:id", portfolioHandler.DeletePortfolio)
+	// A handler that only reads the portfolio's data, relying on the middleware alone
+	portfolio.PUT("/:id/targets", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	watchlistHandler := handlers.NewWatchlistHandler(watchlists, nil)
+	watchlist := router.Group("/api/v1/watchlists")
+	watchlist.GET("/:id", watchlistHandler.GetWatchlist)
+	watchlist.PATCH("/:id", watchlistHandler.UpdateWatchlist)
+	watchlist.DELETE("/:id", watchlistHandler.DeleteWatchlist)
+	watchlist.PUT("/:id/items", watchlistHandler.ReplaceItems)
+	return router
+}
+
+func serve(router http.Handler, user, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("X-Test-User", user)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func owner(id string) *string { return &id }
+
+func TestForeignPortfolioIsNotFound(t *testing.T) {
+	portfolios := newFakePortfolios(models.Portfolio{ID: 1, UserID: owner(alice), Name: "Alice's"})
+	router := tenantRouter(portfolios, newFakeWatchlists())
+
+	requests := []struct{ method, path, body string }{
+		{http.MethodGet, "/api/v1/portfolio/1", ""},
+		{http.MethodPatch, "/api/v1/portfolio/1", `{"name":"Bob's now"}`},
+		{http.MethodPut, "/api/v1/portfolio/1/targets", `{"targets":[]}`},
+		{http.MethodDelete, "/api/v1/portfolio/1", ""},
+	}
+	for _, r := range requests {
+		rec := serve(router, bob, r.method, r.path, r.body)
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("%s %s as another user: got %d, want 404: %s", r.method, r.path, rec.Code, rec.Body)
+		}
+		if !strings.Contains(rec.Body.String(), `"code":"not_found"`) {
+			t.Errorf("%s %s as another user: body %s is not a not_found error", r.method, r.path, rec.Body)
+		}
+	}
+
+	p, err := portfolios.Get(context.Background(), owner(alice), 1)
+	if err != nil {
+		t.Fatalf("portfolio was deleted by another user: %v", err)
+	}
+	if p.Name != "Alice's" {
+		t.Errorf("portfolio was renamed by another user to %q", p.Name)
+	}
+
+	// The owner is let through, so the 404s above are not the routes failing
+	for _, r := range requests {
+		want := http.StatusOK
+		if r.method == http.MethodDelete {
+			want = http.StatusNoContent
+		}
+		if rec := serve(router, alice, r.method, r.path, r.body); rec.Code != want {
+			t.Errorf("%s %s as the owner: got %d, want %d: %s", r.method, r.path, rec.Code, want, rec.Body)
+		}
+	}
+}
+
+func TestForeignWatchlistIsNotFound(t *testing.T) {
+	watchlists := newFakeWatchlists(models.Watchlist{
+		ID: 7, UserID: owner(alice), Name: "Alice's",
+		Items: []models.WatchlistItem{{Ticker: "AAPL"}},
+	})
+	router := tenantRouter(newFakePortfolios(), watchlists)
+
+	requests := []struct{ method, path, body string }{
+		{http.MethodGet, "/api/v1/watchlists/7", ""},
+		{http.MethodPatch, "/api/v1/watchlists/7", `{"name":"Bob's now"}`},
+		{http.MethodPut, "/api/v1/watchlists/7/items", `{"tickers":["TSLA"]}`},
+		{http.MethodDelete, "/api/v1/watchlists/7", ""},
+	}
+	for _, r := range requests {
+		rec := serve(router, bob, r.method, r.path, r.body)
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("%s %s as another user: got %d, want 404: %s", r.method, r.path, rec.Code, rec.Body)
+		}
+	}
+
+	w, err := watchlists.Get(context.Background(), owner(alice), 7)
+	if err != nil {
+		t.Fatalf("watchlist was deleted by another user: %v", err)
+	}
+	if w.Name != "Alice's" || len(w.Items) != 1 || w.Items[0].Ticker != "AAPL" {
+		t.Errorf("watchlist was changed by another user: %+v", w)
+	}
+
+	for _, r := range requests {
+		want := http.StatusOK
+		if r.method == http.MethodDelete {
+			want = http.StatusNoContent
+		}
+		if rec := serve(router, alice, r.method, r.path, r.body); rec.Code != want {
+			t.Errorf("%s %s as the owner: got %d, want %d: %s", r.method, r.path, rec.Code, want, rec.Body)
+		}
+	}
+}
+
+func TestAnonymousCannotReachOwnedPortfolio(t *testing.T) {
+	router := tenantRouter(newFakePortfolios(models.Portfolio{ID: 1, UserID: owner(alice)}), newFakeWatchlists())
+	if rec := serve(router, "", http.MethodGet, "/api/v1/portfolio/1", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("GET without a user: got %d, want 404", rec.Code)
+	}
+}
+
+// alertRouter mounts the market alert routes as the API does, signing requests in as the
+// user named by the X-Test-User header
+func alertRouter(alerts repository.AlertRepo) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.Errors(), func(c *gin.Context) {
+		if id := c.GetHeader("X-Test-User"); id != "" {
+			c.Set(middleware.UserIDKey, id)
+		}
+		c.Next()
+	})
+
+	alertHandler := handlers.NewAlertHandler(alerts, nil, repository.NewSettingsRepository(nil), repository.NewAuditRepository(nil))
+	marketAlerts := router.Group("/api/v1/alerts")
+	marketAlerts.GET("/triggers", alertHandler.ListTriggers)
+	marketAlerts.POST("/triggers/ack", alertHandler.AcknowledgeTriggers)
+	marketAlerts.POST("/triggers/:id/ack", alertHandler.AcknowledgeTrigger)
+	marketAlerts.GET("/:id", alertHandler.GetAlert)
+	marketAlerts.PATCH("/:id", alertHandler.UpdateAlert)
+	marketAlerts.DELETE("/:id", alertHandler.DeleteAlert)
+	marketAlerts.GET("/:id/triggers", alertHandler.ListAlertTriggers)
+	marketAlerts.POST("/:id/snooze", alertHandler.SnoozeAlert)
+	marketAlerts.POST("/:id/rearm", alertHandler.RearmAlert)
+	return router
+}
+
+func TestForeignAlertIsNotFound(t *testing.T) {
+	alerts := newFakeAlerts(
+		[]models.AlertTrigger{{ID: 9, AlertID: 3, Value: 201, Threshold: 200}},
+		models.Alert{
+			ID: 3, UserID: owner(alice), Ticker: "AAPL", Metric: "price", Operator: ">", Threshold: 200,
+			Mode: models.AlertOneShot, Status: models.AlertTriggered, Source: models.AlertSourceUser,
+		},
+	)
+	router := alertRouter(alerts)
+
+	requests := []struct{ method, path, body string }{
+		{http.MethodGet, "/api/v1/alerts/3", ""},
+		{http.MethodPatch, "/api/v1/alerts/3", `{"note":"Bob's now"}`},
+		{http.MethodGet, "/api/v1/alerts/3/triggers", ""},
+		{http.MethodPost, "/api/v1/alerts/3/snooze", `{"minutes":60}`},
+		{http.MethodPost, "/api/v1/alerts/3/rearm", ""},
+		{http.MethodPost, "/api/v1/alerts/triggers/9/ack", ""},
+		{http.MethodDelete, "/api/v1/alerts/3", ""},
+	}
+	for _, r := range requests {
+		rec := serve(router, bob, r.method, r.path, r.body)
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("%s %s as another user: got %d, want 404: %s", r.method, r.path, rec.Code, rec.Body)
+		}
+		if !strings.Contains(rec.Body.String(), `"code":"not_found"`) {
+			t.Errorf("%s %s as another user: body %s is not a not_found error", r.method, r.path, rec.Body)
+		}
+	}
+
+	// Listing and acknowledging in bulk scope to the caller's own alerts rather than failing
+	if rec := serve(router, bob, http.MethodGet, "/api/v1/alerts/triggers", ""); rec.Code != http.StatusOK || strings.Contains(rec.Body.String(), `"id":9`) {
+		t.Errorf("GET /api/v1/alerts/triggers as another user: got %d %s, want none of the owner's triggers", rec.Code, rec.Body)
+	}
+	if rec := serve(router, bob, http.MethodPost, "/api/v1/alerts/triggers/ack", `{"alert_id":3}`); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"acknowledged":0`) {
+		t.Errorf("POST /api/v1/alerts/triggers/ack as another user: got %d %s, want 0 acknowledged", rec.Code, rec.Body)
+	}
+
+	a, err := alerts.Get(context.Background(), owner(alice), 3)
+	if err != nil {
+		t.Fatalf("alert was deleted by another user: %v", err)
+	}
+	if a.Note != nil || a.Status != models.AlertTriggered {
+		t.Errorf("alert was changed by another user: %+v", a)
+	}
+	if trigger := alerts.triggers[9]; trigger.AcknowledgedAt != nil {
+		t.Error("trigger was acknowledged by another user")
+	}
+
+	// The owner is let through, so the 404s above are not the routes failing
+	for _, r := range requests {
+		want := http.StatusOK
+		if r.method == http.MethodDelete {
+			want = http.StatusNoContent
+		}
+		if rec := serve(router, alice, r.method, r.path, r.body); rec.Code != want {
+			t.Errorf("%s %s as the owner: got %d, want %d: %s", r.method, r.path, rec.Code, want, rec.Body)
+		}
+	}
+}
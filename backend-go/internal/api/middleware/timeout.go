@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// timeoutWriteGrace is how long past its deadline a request still has to write its response
+const timeoutWriteGrace = 5 * time.Second
+
+// Timeout gives the routes it guards d to answer: the request's context is cancelled once
+// d has passed, so the upstream calls and queries made with it give up, and a request that
+// had not answered by then is answered with 504. The server's write timeout is extended to
+// match, so routes may be given longer than it. Streams are not to be given one. A d of
+// zero or less leaves requests without a deadline of their own.
+func Timeout(d time.Duration) gin.HandlerFunc {
+	if d <= 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), d)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Now().Add(d + timeoutWriteGrace))
+
+		c.Next()
+
+		if !stderrors.Is(ctx.Err(), context.DeadlineExceeded) || c.Writer.Written() {
+			return
+		}
+		appErr := errors.NewTimeoutError(fmt.Sprintf("request did not complete within %s", d), ctx.Err())
+		_ = c.Error(appErr)
+	}
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/massive"
+	"github.com/gin-gonic/gin"
+)
+
+// MeterUsage counts the Massive API pages and contracts each request of a recorded user
+// consumes and adds them to the user's usage once the request is done. When enforce is set,
+// a user who has used up their tier's monthly quota is rejected with 429 until the next
+// period. Requests without a recorded user, anonymous or with no database, are not metered,
+// nor are those made with the user's own Massive key (see UseOwnMassiveKey), which Massive
+// meters against the user's plan.
+func MeterUsage(users *repository.UserRepository, enforce bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, _ := c.Get(UserKey)
+		user, _ := value.(*models.User)
+		if users == nil || user == nil || massive.HasAPIKey(c.Request.Context()) {
+			c.Next()
+			return
+		}
+
+		if enforce {
+			if quota := user.Quota(time.Now()); quota.Exceeded {
+				retryAfter := math.Ceil(time.Until(quota.PeriodEnd).Seconds())
+				c.Header("Retry-After", strconv.Itoa(int(retryAfter)))
+				appErr := errors.NewRateLimitError("usage quota exceeded for the " + quota.Tier + " tier")
+				abortWithError(c, appErr)
+				return
+			}
+		}
+
+		usage := &massive.Usage{}
+		c.Request = c.Request.WithContext(massive.WithUsage(c.Request.Context(), usage))
+		c.Next()
+
+		if usage.Pages() == 0 && usage.Contracts() == 0 {
+			return
+		}
+		// The upstream calls were made whether or not the client stayed for the response
+		err := users.RecordUsage(context.WithoutCancel(c.Request.Context()), user.ID, usage.Pages(), usage.Contracts(), time.Now())
+		if err != nil {
+			log.Printf("[Usage] ⚠ Failed to record usage of user %s: %v", user.ID, err)
+		}
+	}
+}
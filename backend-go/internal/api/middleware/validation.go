@@ -0,0 +1,211 @@
+package middleware
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// bodyLimitKey holds the body limit of the request's route. The last BodyLimit in the chain
+// sets it, and it is applied when the body is first read, so a route's limit replaces the
+// global one instead of nesting inside it.
+const bodyLimitKey = "body_limit"
+
+// FieldError is one invalid field of a request body, named by its JSON path
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// BodyLimit refuses request bodies over maxBytes with 413. The check runs when the body is
+// first read: it fails at once when Content-Length is over the limit, and otherwise once
+// reading passes it. A BodyLimit given to a route replaces the global one, for uploads that
+// may be larger.
+func BodyLimit(maxBytes int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+		if _, ok := c.Get(bodyLimitKey); !ok {
+			c.Request.Body = &limitedBody{c: c, body: c.Request.Body}
+		}
+		c.Set(bodyLimitKey, maxBytes)
+		c.Next()
+	}
+}
+
+// limitedBody applies the route's body limit once the handler starts reading
+type limitedBody struct {
+	c       *gin.Context
+	body    io.ReadCloser
+	limited io.ReadCloser
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.limited == nil {
+		maxBytes := b.c.GetInt64(bodyLimitKey)
+		if b.c.Request.ContentLength > maxBytes {
+			return 0, &http.MaxBytesError{Limit: maxBytes}
+		}
+		b.limited = http.MaxBytesReader(b.c.Writer, b.body, maxBytes)
+	}
+	return b.limited.Read(p)
+}
+
+func (b *limitedBody) Close() error {
+	return b.body.Close()
+}
+
+// UseJSONFieldNames makes validation errors name fields by their JSON keys rather than their
+// Go names, so FieldErrors match the body the client sent
+func UseJSONFieldNames() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+}
+
+// describeBindingError explains a bad request whose cause is a failed body binding: an
+// oversized body becomes 413, failed validation lists each field and what it must be, and
+// malformed JSON says where. Other errors are returned as they are.
+func describeBindingError(appErr *errors.AppError) *errors.AppError {
+	if appErr.Code != "bad_request" || appErr.Err == nil {
+		return appErr
+	}
+	var tooLarge *http.MaxBytesError
+	if stderrors.As(appErr.Err, &tooLarge) {
+		return errors.NewPayloadTooLargeError(fmt.Sprintf("request body must not exceed %d bytes", tooLarge.Limit), appErr.Err)
+	}
+	if appErr.Details != nil {
+		return appErr
+	}
+
+	var invalid validator.ValidationErrors
+	var syntax *json.SyntaxError
+	var mistyped *json.UnmarshalTypeError
+	switch {
+	case stderrors.As(appErr.Err, &invalid):
+		fields := make([]FieldError, len(invalid))
+		for i, fe := range invalid {
+			fields[i] = FieldError{Field: fieldPath(fe.Namespace()), Message: validationMessage(fe)}
+		}
+		return errors.NewBadRequestError(fmt.Sprintf("%s: %s %s", appErr.Message, fields[0].Field, fields[0].Message), appErr.Err).
+			WithDetails(gin.H{"fields": fields})
+	case stderrors.As(appErr.Err, &mistyped):
+		field := FieldError{Field: mistyped.Field, Message: "must be " + jsonKind(mistyped.Type)}
+		return errors.NewBadRequestError(fmt.Sprintf("%s: %s %s", appErr.Message, field.Field, field.Message), appErr.Err).
+			WithDetails(gin.H{"fields": []FieldError{field}})
+	case stderrors.As(appErr.Err, &syntax):
+		return errors.NewBadRequestError(fmt.Sprintf("%s: malformed JSON at byte %d", appErr.Message, syntax.Offset), appErr.Err)
+	case stderrors.Is(appErr.Err, io.EOF):
+		return errors.NewBadRequestError(appErr.Message+": body is empty", appErr.Err)
+	case stderrors.Is(appErr.Err, io.ErrUnexpectedEOF):
+		return errors.NewBadRequestError(appErr.Message+": body ends early", appErr.Err)
+	}
+	return appErr
+}
+
+// fieldPath drops the request struct's name from a validation namespace,
+// CreateAlertRequest.legs[0].strike becoming legs[0].strike
+func fieldPath(namespace string) string {
+	_, path, ok := strings.Cut(namespace, ".")
+	if !ok {
+		return namespace
+	}
+	return path
+}
+
+// validationMessage says what a field failing a validation tag must be
+func validationMessage(fe validator.FieldError) string {
+	param := fe.Param()
+	unit := ""
+	switch fe.Kind() {
+	case reflect.String:
+		unit = " characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		unit = " items"
+	}
+	if param == "1" {
+		unit = strings.TrimSuffix(unit, "s")
+	}
+
+	// Lengths of strings and collections are counted, other values compared
+	if unit != "" {
+		switch fe.Tag() {
+		case "min", "gte":
+			return "must have at least " + param + unit
+		case "max", "lte":
+			return "must have at most " + param + unit
+		case "len":
+			return "must have exactly " + param + unit
+		case "gt":
+			return "must have more than " + param + unit
+		case "lt":
+			return "must have fewer than " + param + unit
+		}
+	}
+
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "min", "gte":
+		return "must be at least " + param
+	case "max", "lte":
+		return "must be at most " + param
+	case "len", "eq":
+		return "must be " + param
+	case "gt":
+		return "must be greater than " + param
+	case "lt":
+		return "must be less than " + param
+	case "oneof":
+		return "must be one of " + strings.Join(strings.Fields(param), ", ")
+	case "email":
+		return "must be an email address"
+	case "http_url":
+		return "must be an http or https URL"
+	}
+	return "failed the " + fe.Tag() + " check"
+}
+
+// jsonKind names the JSON type a Go type is decoded from
+func jsonKind(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.String:
+		return "a string"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Map, reflect.Struct:
+		return "an object"
+	case reflect.Pointer:
+		return jsonKind(t.Elem())
+	}
+	return "a " + t.Kind().String()
+}
@@ -0,0 +1,92 @@
+package middleware_test
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/api/handlers"
+	"github.com/aaronbengochea/periscope/backend-go/internal/api/middleware"
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// bodyLimitRouter registers the global limit and the import route's own as the API router does
+func bodyLimitRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.Errors(), func(c *gin.Context) {
+		c.Set(middleware.UserIDKey, alice)
+		c.Next()
+	}, middleware.BodyLimit(1<<20))
+
+	portfolios := newFakePortfolios(models.Portfolio{ID: 1, UserID: owner(alice), Name: "Alice's"})
+	importHandler := handlers.NewImportHandler(portfolios, repository.NewTransactionRepository(nil))
+	router.POST("/api/v1/portfolio/:id/import", middleware.BodyLimit(handlers.MaxImportBytes), importHandler.ImportTrades)
+	router.POST("/echo", func(c *gin.Context) {
+		if _, err := io.ReadAll(c.Request.Body); err != nil {
+			_ = c.Error(errors.NewBadRequestError("failed to read body", err))
+			return
+		}
+		c.Status(http.StatusNoContent)
+	})
+	return router
+}
+
+// importCSV returns a native-format CSV of at least size bytes whose rows all fail to
+// parse, so the import answers 422 without reaching the database
+func importCSV(size int) []byte {
+	var b bytes.Buffer
+	b.WriteString("ticker,quantity,price\n")
+	for b.Len() < size {
+		b.WriteString("AAPL,many,1\n")
+	}
+	return b.Bytes()
+}
+
+func multipartImport(t *testing.T, csv []byte) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", "trades.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write(csv)
+	form.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/portfolio/1/import", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	return req
+}
+
+func TestRouteBodyLimitReplacesGlobal(t *testing.T) {
+	router := bodyLimitRouter()
+
+	tests := []struct {
+		name string
+		req  *http.Request
+		want int
+	}{
+		{"2 MiB multipart import", multipartImport(t, importCSV(2<<20)), http.StatusUnprocessableEntity},
+		{"2 MiB raw import", httptest.NewRequest(http.MethodPost, "/api/v1/portfolio/1/import", bytes.NewReader(importCSV(2<<20))), http.StatusUnprocessableEntity},
+		{"import over its own limit", multipartImport(t, importCSV(handlers.MaxImportBytes+1)), http.StatusRequestEntityTooLarge},
+		{"2 MiB to a route without its own limit", httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader(strings.Repeat("x", 2<<20))), http.StatusRequestEntityTooLarge},
+		{"small body to a route without its own limit", httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader("x")), http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, tt.req)
+			if rec.Code != tt.want {
+				t.Errorf("got %d, want %d: %.200s", rec.Code, tt.want, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+// Package openapi serves the OpenAPI 3 document describing Periscope's API, generated from the
+// handlers by cmd/openapi, and a Swagger UI page for browsing it
+package openapi
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+//go:generate sh -c "cd ../../.. && go run ./cmd/openapi"
+
+// Spec is the generated OpenAPI document
+//
+//go:embed openapi.json
+var Spec []byte
+
+// swaggerUI loads Swagger UI from a CDN and points it at the spec
+const swaggerUI = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Periscope API</title>
+  <link rel="stylesheet" href="https://cdn.jsdelivr.net/npm/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://cdn.jsdelivr.net/npm/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "/api/v1/openapi.json", dom_id: "#swagger-ui", persistAuthorization: true });
+  </script>
+</body>
+</html>
+`
+
+// ServeSpec handles GET /api/v1/openapi.json, returning the OpenAPI document
+func ServeSpec(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.Data(http.StatusOK, "application/json; charset=utf-8", Spec)
+}
+
+// ServeDocs handles GET /docs, returning the Swagger UI page
+func ServeDocs(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(swaggerUI))
+}
@@ -2,66 +2,458 @@ package api
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/aaronbengochea/periscope/backend-go/config"
+	"github.com/aaronbengochea/periscope/backend-go/internal/alerts"
 	"github.com/aaronbengochea/periscope/backend-go/internal/api/handlers"
 	"github.com/aaronbengochea/periscope/backend-go/internal/api/middleware"
+	"github.com/aaronbengochea/periscope/backend-go/internal/api/openapi"
+	"github.com/aaronbengochea/periscope/backend-go/internal/auth"
+	"github.com/aaronbengochea/periscope/backend-go/internal/graph"
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/aaronbengochea/periscope/backend-go/internal/secrets"
+	"github.com/aaronbengochea/periscope/backend-go/internal/services"
+	"github.com/aaronbengochea/periscope/backend-go/internal/sharelink"
 	"github.com/aaronbengochea/periscope/backend-go/pkg/database"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/database/rest"
 	"github.com/aaronbengochea/periscope/backend-go/pkg/massive"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
-// NewRouter creates and configures the HTTP router
-func NewRouter(cfg *config.Config, db *database.DB, massiveClient *massive.Client) *gin.Engine {
+// NewRouter creates and configures the HTTP router. The demo endpoints are served when demo
+// is set, and users may store their own Massive API keys when secretBox is.
+func NewRouter(cfg *config.Config, db *database.DB, massiveClient *massive.Client, demo *services.DemoService, secretBox *secrets.Box) *gin.Engine {
 	router := gin.New()
+	middleware.UseJSONFieldNames()
+	// The client IP the rate limits and audit log use comes from X-Forwarded-For only when
+	// the connection is from one of these proxies; Load has checked the list
+	_ = router.SetTrustedProxies(cfg.TrustedProxies)
 
 	// Global middleware
-	router.Use(gin.Recovery())                     // Recover from panics
+	router.Use(middleware.RequestID())             // Tag each request with an X-Request-ID
 	router.Use(middleware.Logger())                // Structured logging
-	router.Use(middleware.CORS())                  // CORS for frontend
+	router.Use(middleware.Errors())                // Send attached errors as a standard envelope
+	router.Use(middleware.Recovery())              // Answer panics with a 500 envelope
+	router.Use(middleware.CORS(cfg.CORSOrigins))   // CORS for frontend
+	if cfg.CompressionEnabled {
+		router.Use(middleware.Compress(cfg.CompressionLevel, cfg.CompressionMinSize)) // gzip/deflate large responses
+	}
+	router.Use(middleware.BodyLimit(cfg.MaxBodyBytes)) // Refuse oversized request bodies with 413
 
 	// Health check endpoint (supports both GET and HEAD for Docker healthcheck)
-	healthHandler := func(c *gin.Context) {
+	health := func(c *gin.Context, details bool) {
 		status := gin.H{
 			"status": "healthy",
 			"service": "periscope-api",
 		}
+		code := http.StatusOK
 
 		// Check database connection if available
-		if db != nil {
+		if db != nil && !db.Connected() {
+			// Serving without the database while it reconnects in the background
+			status["status"] = "degraded"
+			status["database"] = "reconnecting"
+			if err := db.LastError(); err != nil {
+				status["database_error"] = err.Error()
+			}
+		} else if db != nil {
 			if err := db.Health(c.Request.Context()); err != nil {
 				status["database"] = "unhealthy"
 				status["database_error"] = err.Error()
-				c.JSON(http.StatusServiceUnavailable, status)
-				return
+				code = http.StatusServiceUnavailable
+			} else {
+				status["database"] = "healthy"
 			}
-			status["database"] = "healthy"
 		} else {
 			status["database"] = "not_connected"
 		}
 
-		c.JSON(http.StatusOK, status)
+		// Connection pool usage and the last failed ping, for diagnosing a slow or flapping database
+		if details && db != nil {
+			status["database_stats"] = db.Stats()
+		}
+
+		c.JSON(code, status)
 	}
+	healthHandler := func(c *gin.Context) { health(c, false) }
 	router.GET("/health", healthHandler)
 	router.HEAD("/health", healthHandler)
+	router.GET("/health/details", func(c *gin.Context) { health(c, true) })
+
+	// Prometheus metrics, including database query durations, rows and errors
+	if cfg.MetricsEnabled {
+		router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+	}
+
+	// The OpenAPI document, generated from the handlers, and Swagger UI for browsing it
+	router.GET("/api/v1/openapi.json", openapi.ServeSpec)
+	router.GET("/docs", openapi.ServeDocs)
+
+	// Without a database, portfolios and watchlists may be persisted through the Supabase
+	// REST API instead; everything else stays unavailable
+	var restClient *rest.Client
+	if db == nil && cfg.RESTFallback {
+		restClient = rest.NewClient(cfg.SupabaseURL, cfg.SupabaseServiceKey)
+	}
+
+	// Initialize repositories (routes using them are guarded by RequireDatabase)
+	ivHistoryRepo := repository.NewIVHistoryRepository(db)
+	var portfolioRepo repository.PortfolioRepo = repository.NewPortfolioRepository(db)
+	var watchlistRepo repository.WatchlistRepo = repository.NewWatchlistRepository(db)
+	if restClient != nil {
+		portfolioRepo = repository.NewRESTPortfolioRepository(restClient)
+		watchlistRepo = repository.NewRESTWatchlistRepository(restClient)
+	}
+	positionRepo := repository.NewPositionRepository(db)
+	transactionRepo := repository.NewTransactionRepository(db)
+	snapshotRepo := repository.NewSnapshotRepository(db)
+	positionAlertRepo := repository.NewPositionAlertRepository(db)
+	dividendRepo := repository.NewDividendRepository(db)
+	strategyRepo := repository.NewStrategyRepository(db)
+	shareLinkRepo := repository.NewShareLinkRepository(db)
+	allocationRepo := repository.NewAllocationRepository(db)
+	journalRepo := repository.NewJournalRepository(db)
+	paperOrderRepo := repository.NewPaperOrderRepository(db)
+	webhookRepo := repository.NewWebhookRepository(db, secretBox)
+	alertRepo := repository.NewAlertRepository(db)
+	alertChannelRepo := repository.NewAlertChannelRepository(db, secretBox)
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	settingsRepo := repository.NewSettingsRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
+	accountRepo := repository.NewAccountRepository(db)
+	optionsSnapshotRepo := repository.NewOptionsSnapshotRepository(db)
+
+	// Initialize services
+	chainService := services.NewChainService(massiveClient)
+	betaService := services.NewBetaService(massiveClient)
+	valuationService := services.NewValuationService(massiveClient, positionRepo, betaService, cfg.RiskFreeRate)
+	performanceService := services.NewPerformanceService(massiveClient, snapshotRepo)
+	dividendService := services.NewDividendService(massiveClient, positionRepo, transactionRepo, dividendRepo)
+	rollService := services.NewRollService(massiveClient, chainService, cfg.RiskFreeRate)
+	paperService := services.NewPaperTradingService(valuationService, paperOrderRepo, cfg.PaperSlippageBps)
+	watchlistService := services.NewWatchlistService(massiveClient)
 
 	// Initialize handlers
-	optionsHandler := handlers.NewOptionsHandler(massiveClient)
+	optionsHandler := handlers.NewOptionsHandler(massiveClient, cfg.RiskFreeRate)
+	chainHistoryHandler := handlers.NewChainHistoryHandler(optionsSnapshotRepo)
+	portfolioHandler := handlers.NewPortfolioHandler(portfolioRepo, auditRepo)
+	positionHandler := handlers.NewPositionHandler(portfolioRepo, positionRepo, transactionRepo)
+	transactionHandler := handlers.NewTransactionHandler(portfolioRepo, transactionRepo)
+	valuationHandler := handlers.NewValuationHandler(portfolioRepo, valuationService)
+	importHandler := handlers.NewImportHandler(portfolioRepo, transactionRepo)
+	historyHandler := handlers.NewHistoryHandler(portfolioRepo, snapshotRepo, performanceService)
+	taxLotHandler := handlers.NewTaxLotHandler(portfolioRepo, transactionRepo)
+	positionAlertHandler := handlers.NewPositionAlertHandler(positionRepo, positionAlertRepo)
+	dividendHandler := handlers.NewDividendHandler(portfolioRepo, dividendService)
+	rollHandler := handlers.NewRollHandler(positionRepo, rollService)
+	strategyHandler := handlers.NewStrategyHandler(portfolioRepo, strategyRepo, valuationService)
+	paperOrderHandler := handlers.NewPaperOrderHandler(portfolioRepo, paperOrderRepo, paperService)
+	journalHandler := handlers.NewJournalHandler(portfolioRepo, positionRepo, transactionRepo, journalRepo)
+	allocationHandler := handlers.NewAllocationHandler(portfolioRepo, allocationRepo, valuationService)
+	exportHandler := handlers.NewExportHandler(portfolioRepo, positionRepo, transactionRepo, valuationService)
+	var shareLinkSigner *sharelink.Signer
+	if cfg.ShareLinkSecret != "" {
+		shareLinkSigner = sharelink.NewSigner(cfg.ShareLinkSecret)
+	}
+	earningsSync := alerts.NewEarningsSync(alertRepo, portfolioRepo, positionRepo, watchlistRepo)
+	alertHandler := handlers.NewAlertHandler(alertRepo, earningsSync, settingsRepo, auditRepo)
+	alertStreamHandler := handlers.NewAlertStreamHandler(alertRepo, alerts.NewTriggerStream(alertRepo))
+	quoteStreamHandler := handlers.NewQuoteStreamHandler(
+		services.NewQuoteStream(massiveClient, time.Duration(cfg.QuoteStreamSeconds)*time.Second),
+		cfg.QuoteStreamMaxSymbols, cfg.StreamOrigins)
+	chainStreamHandler := handlers.NewChainStreamHandler(
+		services.NewChainStream(chainService, time.Duration(cfg.ChainStreamSeconds)*time.Second))
+	alertChannelHandler := handlers.NewAlertChannelHandler(alertChannelRepo, watchlistRepo, cfg.SMTPHost != "")
+	watchlistHandler := handlers.NewWatchlistHandler(watchlistRepo, watchlistService)
+	webhookHandler := handlers.NewWebhookHandler(portfolioRepo, webhookRepo)
+	shareLinkHandler := handlers.NewShareLinkHandler(portfolioRepo, shareLinkRepo, valuationService, shareLinkSigner)
+	var sessionClient *auth.SessionClient
+	if cfg.SupabaseAnonKey != "" {
+		sessionClient = auth.NewSessionClient(cfg.SupabaseURL, cfg.SupabaseAnonKey)
+	}
+	sessionHandler := handlers.NewSessionHandler(sessionClient, cfg.AuthCookieSecure)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyRepo, auditRepo)
+	settingsHandler := handlers.NewSettingsHandler(settingsRepo, auditRepo)
+	adminHandler := handlers.NewAdminHandler(userRepo, auditRepo)
+	usageHandler := handlers.NewUsageHandler(userRepo)
+	massiveKeyHandler := handlers.NewMassiveKeyHandler(userRepo, massiveClient, secretBox, auditRepo)
+	accountHandler := handlers.NewAccountHandler(userRepo, accountRepo, auditRepo, cfg.DeletionGraceDays)
+	analyticsHandler := handlers.NewAnalyticsHandler(massiveClient, chainService, ivHistoryRepo, cfg.RiskFreeRate)
+
+	// User-scoped routes require a Supabase access token, or an API key when a database is
+	// connected, unless auth is disabled. With a database, users are recorded and disabled
+	// users turned away.
+	var verifier *auth.Verifier
+	if cfg.AuthEnabled {
+		verifier = auth.NewVerifier(cfg.SupabaseURL, cfg.SupabaseJWTSecret)
+	}
+	var apiKeys *repository.APIKeyRepository
+	var users *repository.UserRepository
+	if db != nil {
+		apiKeys = apiKeyRepo
+		users = userRepo
+	}
+	// Google ID tokens are accepted alongside when OAuth client IDs are configured, provisioning
+	// a user for each Google account, which needs the database
+	var google *auth.GoogleVerifier
+	if cfg.AuthEnabled && len(cfg.GoogleClientIDs) > 0 && db != nil {
+		google = auth.NewGoogleVerifier(cfg.GoogleClientIDs)
+	}
+	requireAuth := middleware.RequireAuth(verifier, google, apiKeys, users)
+	// The public market data endpoints authenticate callers who send a token, so their Massive
+	// usage is metered along with that of the user-scoped routes
+	optionalAuth := middleware.OptionalAuth(verifier, google, apiKeys, users)
+	// Users who stored their own Massive key have their requests made with it, and those are
+	// not metered against their tier
+	ownMassiveKey := middleware.UseOwnMassiveKey(secretBox)
+	meterUsage := middleware.MeterUsage(users, cfg.UsageQuotasEnabled)
+	// Callers are rate limited per user, or per client IP when anonymous, with a budget for
+	// each kind of route, independently of the limiter on the Massive API
+	rateLimit := func(perMinute int) gin.HandlerFunc {
+		if !cfg.RateLimitEnabled {
+			perMinute = 0
+		}
+		return middleware.RateLimit(perMinute)
+	}
+	defaultLimit := rateLimit(cfg.RateLimitDefault)
+	marketLimit := rateLimit(cfg.RateLimitMarket)
+	authLimit := rateLimit(cfg.RateLimitAuth)
+	demoLimit := rateLimit(cfg.RateLimitDemo)
+	// Requests are given a deadline by the kind of route, the full chain longest; streams have
+	// none
+	requestTimeout := middleware.Timeout(time.Duration(cfg.RequestTimeoutSeconds) * time.Second)
+	chainTimeout := middleware.Timeout(time.Duration(cfg.ChainTimeoutSeconds) * time.Second)
+	quoteTimeout := middleware.Timeout(time.Duration(cfg.QuoteTimeoutSeconds) * time.Second)
+	// API keys only reach the routes their scopes allow; sessions reach them all
+	marketScope := middleware.RequireScope(models.ScopeMarketRead, models.ScopeMarketRead)
+	portfolioScope := middleware.RequireScope(models.ScopePortfolioRead, models.ScopePortfolioWrite)
+	watchlistScope := middleware.RequireScope(models.ScopeWatchlistRead, models.ScopeWatchlistWrite)
+	alertScope := middleware.RequireScope(models.ScopeAlertRead, models.ScopeAlertWrite)
+	accountScope := middleware.RequireScope(models.ScopeAccountRead, models.ScopeAccountWrite)
+
+	// API routes. v1 sends the bodies as they have always been and is deprecated; v2 serves the
+	// same routes with successful JSON responses wrapped in an envelope.
+	routes := func(api *gin.RouterGroup) {
+		// Session endpoints: sign in and out through Supabase Auth with cookies
+		session := api.Group("/auth", requestTimeout, authLimit)
+		{
+			session.POST("/login", sessionHandler.Login)
+			session.POST("/refresh", sessionHandler.Refresh)
+			session.POST("/logout", sessionHandler.Logout)
+		}
 
-	// API v1 routes
-	v1 := router.Group("/api/v1")
-	{
 		// Options endpoints
-		v1.GET("/options/:ticker", optionsHandler.GetOptionsChain)
-		v1.POST("/options/details", optionsHandler.GetContractDetails)
-
-		// Portfolio endpoints (to be implemented)
-		v1.GET("/portfolio", func(c *gin.Context) {
-			c.JSON(http.StatusOK, gin.H{
-				"message": "Portfolio endpoint - coming soon",
-			})
-		})
+		api.GET("/options/:ticker", chainTimeout, optionalAuth, marketLimit, marketScope, ownMassiveKey, meterUsage, optionsHandler.GetOptionsChain)
+		api.GET("/options/:ticker/history", chainTimeout, middleware.RequireDatabase(db), optionalAuth, marketLimit, marketScope, chainHistoryHandler.GetChainHistory)
+		api.GET("/options/:ticker/stream", requireAuth, marketLimit, marketScope, chainStreamHandler.StreamChain)
+		api.POST("/options/details", chainTimeout, optionalAuth, marketLimit, marketScope, ownMassiveKey, meterUsage, optionsHandler.GetContractDetails)
+
+		// Quotes pushed over WebSocket as they change
+		api.GET("/stream", requireAuth, marketLimit, marketScope, quoteStreamHandler.StreamQuotes)
+
+		// Analytics endpoints
+		api.GET("/analytics/:ticker/earnings-crush", chainTimeout, optionalAuth, marketLimit, marketScope, ownMassiveKey, meterUsage, analyticsHandler.GetEarningsCrush)
+		api.GET("/analytics/:ticker/mispricing", chainTimeout, optionalAuth, marketLimit, marketScope, ownMassiveKey, meterUsage, analyticsHandler.GetMispricing)
+		api.GET("/analytics/:ticker/straddle", chainTimeout, optionalAuth, marketLimit, marketScope, ownMassiveKey, meterUsage, analyticsHandler.GetStraddle)
+		api.GET("/analytics/:ticker/iv-rank", chainTimeout, middleware.RequireDatabase(db), optionalAuth, marketLimit, marketScope, ownMassiveKey, meterUsage, analyticsHandler.GetIVRank)
+
+		// Portfolio endpoints (require auth and database). Routes under /:id only reach the
+		// portfolio's owner. Portfolios themselves can also be kept through the REST API,
+		// what is recorded under them only in the database.
+		portfolio := api.Group("/portfolio", requestTimeout, requireAuth, defaultLimit, portfolioScope, middleware.RequireStorage(db, restClient), middleware.RequirePortfolioOwner(portfolioRepo), ownMassiveKey, meterUsage)
+		portfolioData := portfolio.Group("", middleware.RequireDatabase(db))
+		{
+			portfolio.GET("", portfolioHandler.ListPortfolios)
+			portfolio.POST("", portfolioHandler.CreatePortfolio)
+			portfolio.GET("/:id", portfolioHandler.GetPortfolio)
+			portfolio.PATCH("/:id", portfolioHandler.UpdatePortfolio)
+			portfolio.DELETE("/:id", portfolioHandler.DeletePortfolio)
+
+			portfolioData.GET("/rollup", valuationHandler.GetRollup)
+			portfolioData.GET("/:id/valuation", valuationHandler.GetValuation)
+			portfolioData.GET("/:id/greeks", valuationHandler.GetGreeks)
+			portfolioData.GET("/:id/risk", valuationHandler.GetRisk)
+			portfolioData.POST("/:id/simulate", valuationHandler.Simulate)
+			portfolioData.POST("/:id/margin", valuationHandler.Margin)
+			portfolioData.GET("/:id/history", historyHandler.GetHistory)
+			portfolioData.GET("/:id/performance", historyHandler.GetPerformance)
+			portfolioData.GET("/:id/dividends", dividendHandler.GetDividends)
+
+			portfolioData.GET("/:id/positions", positionHandler.ListPositions)
+			portfolioData.POST("/:id/positions", positionHandler.CreatePosition)
+			portfolioData.PATCH("/:id/positions/:positionId", positionHandler.UpdatePosition)
+			portfolioData.POST("/:id/positions/:positionId/close", positionHandler.ClosePosition)
+			portfolioData.POST("/:id/positions/:positionId/add", positionHandler.AddToPosition)
+			portfolioData.POST("/:id/positions/:positionId/roll", positionHandler.RollPosition)
+			portfolioData.GET("/:id/positions/:positionId/rolls", rollHandler.GetRollSuggestions)
+			portfolioData.GET("/:id/positions/:positionId/lots", positionHandler.ListLots)
+			portfolioData.POST("/:id/positions/:positionId/alerts", positionAlertHandler.CreateAlert)
+
+			portfolioData.GET("/:id/orders", paperOrderHandler.ListOrders)
+			portfolioData.POST("/:id/orders", paperOrderHandler.PlaceOrder)
+
+			portfolioData.GET("/:id/alerts", positionAlertHandler.ListAlerts)
+			portfolioData.PATCH("/:id/alerts/:alertId", positionAlertHandler.UpdateAlert)
+			portfolioData.DELETE("/:id/alerts/:alertId", positionAlertHandler.DeleteAlert)
+
+			portfolioData.GET("/:id/strategies", strategyHandler.ListStrategies)
+			portfolioData.POST("/:id/strategies", strategyHandler.CreateStrategy)
+			portfolioData.GET("/:id/strategies/:strategyId", strategyHandler.GetStrategy)
+			portfolioData.PATCH("/:id/strategies/:strategyId", strategyHandler.UpdateStrategy)
+			portfolioData.DELETE("/:id/strategies/:strategyId", strategyHandler.DeleteStrategy)
+
+			portfolioData.GET("/:id/journal", journalHandler.ListEntries)
+			portfolioData.POST("/:id/journal", journalHandler.CreateEntry)
+			portfolioData.GET("/:id/journal/:entryId", journalHandler.GetEntry)
+			portfolioData.PATCH("/:id/journal/:entryId", journalHandler.UpdateEntry)
+			portfolioData.DELETE("/:id/journal/:entryId", journalHandler.DeleteEntry)
+
+			portfolioData.GET("/:id/targets", allocationHandler.GetTargets)
+			portfolioData.PUT("/:id/targets", allocationHandler.SetTargets)
+			portfolioData.GET("/:id/rebalance", allocationHandler.GetRebalance)
+
+			portfolioData.POST("/:id/import", middleware.BodyLimit(handlers.MaxImportBytes), importHandler.ImportTrades)
+			portfolioData.GET("/:id/export", exportHandler.ExportPortfolio)
+
+			portfolioData.GET("/:id/share-links", shareLinkHandler.ListShareLinks)
+			portfolioData.POST("/:id/share-links", shareLinkHandler.CreateShareLink)
+			portfolioData.DELETE("/:id/share-links/:linkId", shareLinkHandler.RevokeShareLink)
+
+			portfolioData.GET("/:id/webhooks", webhookHandler.ListWebhooks)
+			portfolioData.POST("/:id/webhooks", webhookHandler.CreateWebhook)
+			portfolioData.PATCH("/:id/webhooks/:webhookId", webhookHandler.UpdateWebhook)
+			portfolioData.DELETE("/:id/webhooks/:webhookId", webhookHandler.DeleteWebhook)
+			portfolioData.GET("/:id/webhooks/:webhookId/deliveries", webhookHandler.ListDeliveries)
+			portfolioData.POST("/:id/webhooks/:webhookId/test", webhookHandler.TestWebhook)
+
+			portfolioData.GET("/:id/transactions", transactionHandler.ListTransactions)
+			portfolioData.GET("/:id/transactions/:transactionId", transactionHandler.GetTransaction)
+			portfolioData.GET("/:id/tax-lots", taxLotHandler.GetTaxLots)
+		}
+
+		// Deleted portfolios are outside RequirePortfolioOwner, which only finds live ones;
+		// the handlers look them up by owner themselves
+		deletedPortfolios := api.Group("/portfolio/deleted", requestTimeout, requireAuth, defaultLimit, portfolioScope, middleware.RequireStorage(db, restClient), meterUsage)
+		{
+			deletedPortfolios.GET("", portfolioHandler.ListDeletedPortfolios)
+			deletedPortfolios.POST("/:id/restore", portfolioHandler.RestorePortfolio)
+			deletedPortfolios.DELETE("/:id", portfolioHandler.PurgePortfolio)
+		}
+
+		// Watchlist endpoints (require auth and the database or REST API)
+		watchlists := api.Group("/watchlists", requestTimeout, requireAuth, defaultLimit, watchlistScope, middleware.RequireStorage(db, restClient), ownMassiveKey, meterUsage)
+		{
+			watchlists.GET("", watchlistHandler.ListWatchlists)
+			watchlists.POST("", watchlistHandler.CreateWatchlist)
+			watchlists.GET("/deleted", watchlistHandler.ListDeletedWatchlists)
+			watchlists.POST("/deleted/:id/restore", watchlistHandler.RestoreWatchlist)
+			watchlists.DELETE("/deleted/:id", watchlistHandler.PurgeWatchlist)
+			watchlists.GET("/:id", watchlistHandler.GetWatchlist)
+			watchlists.GET("/:id/quotes", quoteTimeout, watchlistHandler.GetQuotes)
+			watchlists.PATCH("/:id", watchlistHandler.UpdateWatchlist)
+			watchlists.DELETE("/:id", watchlistHandler.DeleteWatchlist)
+			watchlists.POST("/:id/items", watchlistHandler.AddItems)
+			watchlists.PUT("/:id/items", watchlistHandler.ReplaceItems)
+			watchlists.DELETE("/:id/items/:ticker", watchlistHandler.RemoveItem)
+		}
+
+		// Triggers pushed as they happen, outside the alerts group for having no deadline
+		api.GET("/alerts/stream", requireAuth, defaultLimit, alertScope, middleware.RequireDatabase(db), ownMassiveKey, meterUsage, alertStreamHandler.StreamTriggers)
+
+		// Market alert endpoints (require auth and database)
+		marketAlerts := api.Group("/alerts", requestTimeout, requireAuth, defaultLimit, alertScope, middleware.RequireDatabase(db), ownMassiveKey, meterUsage)
+		{
+			marketAlerts.GET("", alertHandler.ListAlerts)
+			marketAlerts.POST("", alertHandler.CreateAlert)
+			marketAlerts.GET("/deleted", alertHandler.ListDeletedAlerts)
+			marketAlerts.POST("/deleted/:id/restore", alertHandler.RestoreAlert)
+			marketAlerts.DELETE("/deleted/:id", alertHandler.PurgeAlert)
+			marketAlerts.GET("/earnings", alertHandler.GetEarningsSettings)
+			marketAlerts.PUT("/earnings", alertHandler.SetEarningsSettings)
+			marketAlerts.GET("/triggers", alertHandler.ListTriggers)
+			marketAlerts.POST("/triggers/ack", alertHandler.AcknowledgeTriggers)
+			marketAlerts.POST("/triggers/:id/ack", alertHandler.AcknowledgeTrigger)
+			marketAlerts.GET("/channels", alertChannelHandler.ListChannels)
+			marketAlerts.POST("/channels", alertChannelHandler.CreateChannel)
+			marketAlerts.PATCH("/channels/:id", alertChannelHandler.UpdateChannel)
+			marketAlerts.DELETE("/channels/:id", alertChannelHandler.DeleteChannel)
+			marketAlerts.GET("/channels/:id/deliveries", alertChannelHandler.ListDeliveries)
+			marketAlerts.POST("/channels/:id/test", alertChannelHandler.TestChannel)
+			marketAlerts.GET("/:id", alertHandler.GetAlert)
+			marketAlerts.PATCH("/:id", alertHandler.UpdateAlert)
+			marketAlerts.DELETE("/:id", alertHandler.DeleteAlert)
+			marketAlerts.GET("/:id/triggers", alertHandler.ListAlertTriggers)
+			marketAlerts.POST("/:id/snooze", alertHandler.SnoozeAlert)
+			marketAlerts.POST("/:id/rearm", alertHandler.RearmAlert)
+		}
+
+		// Account endpoints (require auth and database)
+		me := api.Group("/me", requestTimeout, requireAuth, defaultLimit, accountScope, middleware.RequireDatabase(db))
+		{
+			me.GET("", accountHandler.GetAccount)
+			me.DELETE("", accountHandler.DeleteAccount)
+			me.POST("/deletion/cancel", accountHandler.CancelAccountDeletion)
+			me.POST("/export", accountHandler.ExportAccount)
+			me.GET("/settings", settingsHandler.GetSettings)
+			me.PUT("/settings", settingsHandler.SetSettings)
+			me.GET("/api-keys", apiKeyHandler.ListAPIKeys)
+			me.POST("/api-keys", apiKeyHandler.CreateAPIKey)
+			me.DELETE("/api-keys/:id", apiKeyHandler.RevokeAPIKey)
+			me.GET("/usage", usageHandler.GetUsage)
+			me.GET("/massive-key", massiveKeyHandler.GetMassiveKey)
+			me.PUT("/massive-key", massiveKeyHandler.SetMassiveKey)
+			me.DELETE("/massive-key", massiveKeyHandler.DeleteMassiveKey)
+		}
+
+		// Admin endpoints (require the admin role and database)
+		admin := api.Group("/admin", requestTimeout, requireAuth, defaultLimit, middleware.RequireAdmin(), middleware.RequireDatabase(db))
+		{
+			admin.GET("/users", adminHandler.ListUsers)
+			admin.GET("/users/:id", adminHandler.GetUser)
+			admin.GET("/users/:id/usage", adminHandler.GetUserUsage)
+			admin.POST("/users/:id/disable", adminHandler.DisableUser)
+			admin.POST("/users/:id/enable", adminHandler.EnableUser)
+			admin.POST("/users/:id/quota/reset", adminHandler.ResetUserQuota)
+			admin.PUT("/users/:id/tier", adminHandler.SetUserTier)
+			admin.GET("/audit", adminHandler.ListAuditLog)
+		}
+
+		// Shared portfolio views: read-only, authorized by the signed token alone
+		shared := api.Group("/shared", requestTimeout, demoLimit, middleware.RequireDatabase(db))
+		{
+			shared.GET("/:token", shareLinkHandler.GetSharedPortfolio)
+			shared.GET("/:token/greeks", shareLinkHandler.GetSharedGreeks)
+		}
+
+		// Demo endpoints: anonymous, served from delayed or sample snapshots (demo mode only)
+		if demo != nil {
+			demoHandler := handlers.NewDemoHandler(demo, cfg.RiskFreeRate)
+			demoRoutes := api.Group("/demo", requestTimeout, demoLimit)
+			{
+				demoRoutes.GET("/tickers", demoHandler.ListTickers)
+				demoRoutes.GET("/options/:ticker", demoHandler.GetOptionsChain)
+			}
+		}
 	}
 
+	// GraphQL over the same services and repositories, for clients that fetch nested data in
+	// one round trip. One query can mix market data with portfolios and watchlists, so the
+	// resolvers check scopes and storage field by field.
+	graphResolver := graph.NewResolver(massiveClient, chainService, valuationService, watchlistService,
+		portfolioRepo, positionRepo, watchlistRepo, graph.Storage{DB: db, REST: restClient != nil})
+	graphQLHandler := handlers.NewGraphQLHandler(graph.NewHandler(graphResolver), verifier != nil)
+	graphQL := router.Group("/graphql", chainTimeout, optionalAuth, marketLimit, ownMassiveKey, meterUsage)
+	{
+		graphQL.GET("", graphQLHandler.Query)
+		graphQL.POST("", graphQLHandler.Query)
+	}
+
+	routes(router.Group("/api/v1", middleware.Deprecated(cfg.V1DeprecatedAt, cfg.V1Sunset, "/api/v1", "/api/v2")))
+	routes(router.Group("/api/v2", middleware.EnvelopeV2()))
+
 	return router
 }
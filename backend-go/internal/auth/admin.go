@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// AdminClient manages accounts through the project's Supabase Auth admin API, authorized with
+// the service role key
+type AdminClient struct {
+	baseURL    string
+	serviceKey string
+	client     *http.Client
+}
+
+// NewAdminClient creates an admin client for the project at supabaseURL
+func NewAdminClient(supabaseURL, serviceKey string) *AdminClient {
+	return &AdminClient{
+		baseURL:    strings.TrimRight(supabaseURL, "/") + "/auth/v1",
+		serviceKey: serviceKey,
+		client:     &http.Client{Timeout: sessionTimeout},
+	}
+}
+
+// DeleteUser deletes a Supabase account, signing it out everywhere. An account that no
+// longer exists counts as deleted.
+func (a *AdminClient) DeleteUser(ctx context.Context, id string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, a.baseURL+"/admin/users/"+url.PathEscape(id), nil)
+	if err != nil {
+		return fmt.Errorf("invalid auth request: %w", err)
+	}
+	req.Header.Set("apikey", a.serviceKey)
+	req.Header.Set("Authorization", "Bearer "+a.serviceKey)
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("auth request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound || (resp.StatusCode >= 200 && resp.StatusCode < 300) {
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		return nil
+	}
+	excerpt, _ := io.ReadAll(io.LimitReader(resp.Body, 200))
+	return fmt.Errorf("auth server responded %d: %s", resp.StatusCode, bytes.TrimSpace(excerpt))
+}
@@ -0,0 +1,38 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// APIKeyPrefix starts every Periscope API key, which tells keys apart from access tokens
+const APIKeyPrefix = "psk_"
+
+// apiKeyShown is how many leading characters of a key are kept to identify it in listings
+const apiKeyShown = len(APIKeyPrefix) + 8
+
+// NewAPIKey returns a random API key with the prefix shown in listings and the hash it is
+// stored as
+func NewAPIKey() (key, prefix, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", "", fmt.Errorf("failed to generate API key: %w", err)
+	}
+	key = APIKeyPrefix + hex.EncodeToString(b)
+	return key, key[:apiKeyShown], HashAPIKey(key), nil
+}
+
+// IsAPIKey reports whether a bearer token is an API key rather than an access token
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, APIKeyPrefix)
+}
+
+// HashAPIKey returns the hex SHA-256 an API key is stored and looked up by. Keys carry 256
+// random bits, so an unsalted fast hash is enough to keep a leaked table from being usable.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
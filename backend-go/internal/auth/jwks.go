@@ -0,0 +1,212 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// ErrKeysUnavailable is returned when a token names a signing key that is not cached and the
+// project's keys cannot be fetched
+var ErrKeysUnavailable = errors.New("signing keys unavailable")
+
+// Signing key cache settings
+const (
+	keysTTL         = 10 * time.Minute // how long fetched keys are trusted before refreshing
+	keysMinRefresh  = 30 * time.Second // least time between fetches prompted by unknown key IDs
+	keysTimeout     = 5 * time.Second
+	keysMaxResponse = 1 << 20
+)
+
+// keySet caches the public keys an issuer (the Supabase project, or Google) publishes as a
+// JWKS. Keys are refreshed when they go stale or a token names a key not seen yet, which is
+// how rotated keys are picked up; stale keys stay in use while the endpoint cannot be
+// reached. Fetches run outside the lock, one at a time, so a slow issuer never holds up
+// tokens whose keys are cached.
+type keySet struct {
+	url    string
+	client *http.Client
+	group  singleflight.Group
+
+	mu        sync.RWMutex
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+	triedAt   time.Time
+}
+
+func newKeySet(url string) *keySet {
+	return &keySet{
+		url:    url,
+		client: &http.Client{Timeout: keysTimeout},
+		keys:   make(map[string]crypto.PublicKey),
+	}
+}
+
+// get returns the key with the ID. A stale key is returned at once while the set is
+// refreshed in the background; a key not cached waits for a fetch, or for ctx.
+func (s *keySet) get(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	s.mu.RLock()
+	key, ok := s.keys[kid]
+	now := time.Now()
+	stale := now.Sub(s.fetchedAt) > keysTTL
+	throttled := now.Sub(s.triedAt) < keysMinRefresh
+	s.mu.RUnlock()
+
+	if ok && (!stale || throttled) {
+		return key, nil
+	}
+	if throttled {
+		return nil, s.missing()
+	}
+
+	done := s.group.DoChan("refresh", func() (any, error) {
+		s.refresh()
+		return nil, nil
+	})
+	if ok {
+		return key, nil
+	}
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return nil, s.missing()
+	}
+
+	s.mu.RLock()
+	key, ok = s.keys[kid]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, s.missing()
+	}
+	return key, nil
+}
+
+// refresh fetches the key set and replaces the cached keys, keeping them when the fetch
+// fails. It is detached from any one request, so a caller giving up does not cancel it for
+// the others waiting.
+func (s *keySet) refresh() {
+	s.mu.Lock()
+	if time.Since(s.triedAt) < keysMinRefresh {
+		s.mu.Unlock()
+		return
+	}
+	s.triedAt = time.Now()
+	s.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), keysTimeout)
+	defer cancel()
+	keys, err := s.fetch(ctx)
+	if err != nil {
+		log.Printf("[Auth] ⚠ Failed to fetch signing keys: %v", err)
+		return
+	}
+
+	s.mu.Lock()
+	s.keys = keys
+	s.fetchedAt = time.Now()
+	s.mu.Unlock()
+	log.Printf("[Auth] ✓ Fetched %d signing keys", len(keys))
+}
+
+// missing is the error for a key ID that is not cached: the token is invalid when the key set
+// is current, and cannot be checked when it could not be fetched
+func (s *keySet) missing() error {
+	s.mu.RLock()
+	fetchedAt := s.fetchedAt
+	s.mu.RUnlock()
+	if fetchedAt.IsZero() || time.Since(fetchedAt) > keysTTL {
+		return ErrKeysUnavailable
+	}
+	return ErrInvalidToken
+}
+
+// fetch downloads and parses the key set, skipping keys of types it cannot use
+func (s *keySet) fetch(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("JWKS endpoint responded %d", resp.StatusCode)
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, keysMaxResponse)).Decode(&set); err != nil {
+		return nil, fmt.Errorf("invalid JWKS: %w", err)
+	}
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		key, err := k.publicKey()
+		if err != nil {
+			log.Printf("[Auth] ⚠ Skipping signing key %q: %v", k.Kid, err)
+			continue
+		}
+		keys[k.Kid] = key
+	}
+	return keys, nil
+}
+
+// jwk is a JSON web key as published by Supabase Auth
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// publicKey decodes a P-256 EC or RSA signing key
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	if k.Use != "" && k.Use != "sig" {
+		return nil, fmt.Errorf("not a signing key")
+	}
+	switch k.Kty {
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, fmt.Errorf("unsupported curve %s", k.Crv)
+		}
+		x, errX := base64.RawURLEncoding.DecodeString(k.X)
+		y, errY := base64.RawURLEncoding.DecodeString(k.Y)
+		if errX != nil || errY != nil || len(x) != 32 || len(y) != 32 {
+			return nil, fmt.Errorf("invalid EC coordinates")
+		}
+		point := append(append([]byte{4}, x...), y...)
+		if _, err := ecdh.P256().NewPublicKey(point); err != nil {
+			return nil, fmt.Errorf("point is not on the curve")
+		}
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	case "RSA":
+		n, errN := base64.RawURLEncoding.DecodeString(k.N)
+		e, errE := base64.RawURLEncoding.DecodeString(k.E)
+		if errN != nil || errE != nil || len(e) == 0 || len(e) > 4 {
+			return nil, fmt.Errorf("invalid RSA key")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %s", k.Kty)
+}
@@ -0,0 +1,223 @@
+// Package auth verifies the access tokens Supabase Auth issues to signed-in users
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// ErrInvalidToken is returned for tokens that are malformed, wrongly signed, expired or not
+// issued to a signed-in user of the project
+var ErrInvalidToken = errors.New("invalid or expired access token")
+
+// clockSkew is the leeway allowed between our clock and the auth server's
+const clockSkew = 30 * time.Second
+
+// Claims are the claims of a Supabase access token the API relies on
+type Claims struct {
+	Subject     string   `json:"sub"` // the user's ID
+	Email       string   `json:"email"`
+	Role        string   `json:"role"`
+	SessionID   string   `json:"session_id"`
+	AAL         string   `json:"aal"` // authenticator assurance level, aal1 or aal2
+	IsAnonymous bool     `json:"is_anonymous"`
+	Issuer      string   `json:"iss"`
+	Audience    audience `json:"aud"`
+	ExpiresAt   int64    `json:"exp"` // Unix seconds
+	NotBefore   int64    `json:"nbf"`
+	IssuedAt    int64    `json:"iat"`
+
+	AppMetadata AppMetadata `json:"app_metadata"`
+}
+
+// RoleAdmin is the app_metadata role that grants access to the admin API
+const RoleAdmin = "admin"
+
+// AppMetadata is the part of a user's metadata only the project's service role can change,
+// so it can be trusted for authorization
+type AppMetadata struct {
+	Provider string `json:"provider"`
+	Role     string `json:"role"`
+}
+
+// IsAdmin reports whether the token's user may use the admin API
+func (c *Claims) IsAdmin() bool {
+	return c.AppMetadata.Role == RoleAdmin
+}
+
+// audience is a token's aud claim, which may be a string or a list of strings
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*a = audience{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*a = many
+	return nil
+}
+
+func (a audience) has(want string) bool {
+	for _, aud := range a {
+		if aud == want {
+			return true
+		}
+	}
+	return false
+}
+
+// Verifier verifies Supabase access tokens: asymmetric ES256 and RS256 tokens against the
+// project's published signing keys, and HS256 tokens of projects still on the legacy shared
+// JWT secret when one is configured
+type Verifier struct {
+	issuer string
+	keys   *keySet
+	secret []byte
+}
+
+// NewVerifier creates a verifier for the project at supabaseURL. jwtSecret is the legacy JWT
+// secret; when empty, HS256 tokens are rejected.
+func NewVerifier(supabaseURL, jwtSecret string) *Verifier {
+	issuer := strings.TrimRight(supabaseURL, "/") + "/auth/v1"
+	v := &Verifier{
+		issuer: issuer,
+		keys:   newKeySet(issuer + "/.well-known/jwks.json"),
+	}
+	if jwtSecret != "" {
+		v.secret = []byte(jwtSecret)
+	}
+	return v
+}
+
+// Verify checks a token's signature, issuer, audience and lifetime and returns its claims
+func (v *Verifier) Verify(ctx context.Context, token string, now time.Time) (*Claims, error) {
+	t, err := splitToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if err := v.verifySignature(ctx, t.header.Alg, t.header.Kid, t.signed, t.signature); err != nil {
+		return nil, err
+	}
+
+	var c Claims
+	if err := decodeSegment(t.payload, &c); err != nil {
+		return nil, ErrInvalidToken
+	}
+	switch {
+	case c.Subject == "" || c.Issuer != v.issuer || !c.Audience.has("authenticated") || c.Role != "authenticated":
+		return nil, ErrInvalidToken
+	case c.ExpiresAt == 0 || now.Add(-clockSkew).Unix() >= c.ExpiresAt:
+		return nil, ErrInvalidToken
+	case c.NotBefore != 0 && now.Add(clockSkew).Unix() < c.NotBefore:
+		return nil, ErrInvalidToken
+	}
+	return &c, nil
+}
+
+// verifySignature checks the signature of the token's header and payload with the key its
+// header names
+func (v *Verifier) verifySignature(ctx context.Context, alg, kid, signed string, signature []byte) error {
+	digest := sha256.Sum256([]byte(signed))
+	switch alg {
+	case "HS256":
+		if v.secret == nil {
+			return ErrInvalidToken
+		}
+		mac := hmac.New(sha256.New, v.secret)
+		mac.Write([]byte(signed))
+		if !hmac.Equal(signature, mac.Sum(nil)) {
+			return ErrInvalidToken
+		}
+		return nil
+	case "ES256":
+		key, err := v.keys.get(ctx, kid)
+		if err != nil {
+			return err
+		}
+		ec, ok := key.(*ecdsa.PublicKey)
+		if !ok || len(signature) != 64 {
+			return ErrInvalidToken
+		}
+		r, s := new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:])
+		if !ecdsa.Verify(ec, digest[:], r, s) {
+			return ErrInvalidToken
+		}
+		return nil
+	case "RS256":
+		return verifyRS256(ctx, v.keys, kid, signed, signature)
+	}
+	return ErrInvalidToken
+}
+
+// verifyRS256 checks an RS256 signature with the key of the set its header names
+func verifyRS256(ctx context.Context, keys *keySet, kid, signed string, signature []byte) error {
+	key, err := keys.get(ctx, kid)
+	if err != nil {
+		return err
+	}
+	digest := sha256.Sum256([]byte(signed))
+	rs, ok := key.(*rsa.PublicKey)
+	if !ok || rsa.VerifyPKCS1v15(rs, crypto.SHA256, digest[:], signature) != nil {
+		return ErrInvalidToken
+	}
+	return nil
+}
+
+// token is a compact JWS split into the parts verification needs
+type token struct {
+	header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	signed    string // the header and payload segments the signature covers
+	payload   string
+	signature []byte
+}
+
+// splitToken splits a compact JWS and decodes its header and signature
+func splitToken(raw string) (*token, error) {
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidToken
+	}
+	var t token
+	if err := decodeSegment(parts[0], &t.header); err != nil {
+		return nil, ErrInvalidToken
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	t.signed = parts[0] + "." + parts[1]
+	t.payload = parts[1]
+	t.signature = signature
+	return &t, nil
+}
+
+// decodeSegment decodes a base64url-encoded JSON token segment
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("invalid token segment: %w", err)
+	}
+	return nil
+}
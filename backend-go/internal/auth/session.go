@@ -0,0 +1,139 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Session errors from Supabase Auth
+var (
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	ErrSessionExpired     = errors.New("session expired; sign in again")
+	ErrAuthRateLimited    = errors.New("too many sign-in attempts; try again later")
+)
+
+// sessionTimeout bounds each call to Supabase Auth
+const sessionTimeout = 10 * time.Second
+
+// Session is a signed-in user's token pair. Supabase rotates the refresh token on every
+// refresh, so the one returned replaces the one used.
+type Session struct {
+	AccessToken  string      `json:"access_token"`
+	RefreshToken string      `json:"refresh_token"`
+	ExpiresIn    int         `json:"expires_in"` // seconds the access token is valid for
+	ExpiresAt    int64       `json:"expires_at"` // Unix seconds
+	User         SessionUser `json:"user"`
+}
+
+// SessionUser is the user a session belongs to
+type SessionUser struct {
+	ID    string `json:"id"`
+	Email string `json:"email"`
+}
+
+// SessionClient signs users in and out through the project's Supabase Auth API on behalf of
+// the frontend
+type SessionClient struct {
+	baseURL string
+	anonKey string
+	client  *http.Client
+}
+
+// NewSessionClient creates a session client for the project at supabaseURL, authorized with
+// its anon key
+func NewSessionClient(supabaseURL, anonKey string) *SessionClient {
+	return &SessionClient{
+		baseURL: strings.TrimRight(supabaseURL, "/") + "/auth/v1",
+		anonKey: anonKey,
+		client:  &http.Client{Timeout: sessionTimeout},
+	}
+}
+
+// Login exchanges an email and password for a session
+func (s *SessionClient) Login(ctx context.Context, email, password string) (*Session, error) {
+	var session Session
+	err := s.call(ctx, "/token?grant_type=password", "", map[string]string{"email": email, "password": password}, &session)
+	if errors.Is(err, errRejected) {
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// Refresh exchanges a refresh token for a new session. The refresh token cannot be used again
+// once the new one is, and Supabase revokes the whole session when a used token is replayed.
+func (s *SessionClient) Refresh(ctx context.Context, refreshToken string) (*Session, error) {
+	var session Session
+	err := s.call(ctx, "/token?grant_type=refresh_token", "", map[string]string{"refresh_token": refreshToken}, &session)
+	if errors.Is(err, errRejected) {
+		return nil, ErrSessionExpired
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// Logout revokes the session of an access token and its refresh tokens. A token that is
+// already invalid counts as signed out.
+func (s *SessionClient) Logout(ctx context.Context, accessToken string) error {
+	err := s.call(ctx, "/logout?scope=local", accessToken, struct{}{}, nil)
+	if errors.Is(err, errRejected) {
+		return nil
+	}
+	return err
+}
+
+// errRejected is returned by call when Supabase Auth rejects the credentials or token sent
+var errRejected = errors.New("rejected by Supabase Auth")
+
+// call posts a JSON body to a Supabase Auth endpoint and decodes the response into out
+func (s *SessionClient) call(ctx context.Context, path, bearer string, body, out any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode auth request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.baseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("invalid auth request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("apikey", s.anonKey)
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("auth request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return ErrAuthRateLimited
+	case resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusUnauthorized ||
+		resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusNotFound:
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		return errRejected
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		excerpt, _ := io.ReadAll(io.LimitReader(resp.Body, 200))
+		return fmt.Errorf("auth server responded %d: %s", resp.StatusCode, bytes.TrimSpace(excerpt))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(out); err != nil {
+		return fmt.Errorf("invalid auth response: %w", err)
+	}
+	return nil
+}
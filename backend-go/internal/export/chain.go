@@ -0,0 +1,119 @@
+package export
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/xlsx"
+)
+
+// chainColumns are the columns of a chain sheet, one contract per row
+var chainColumns = []xlsx.Column{
+	{Header: "ticker", Width: 24}, {Header: "strike", Format: xlsx.Price},
+	{Header: "dte", Format: xlsx.Integer}, {Header: "bid", Format: xlsx.Price},
+	{Header: "ask", Format: xlsx.Price}, {Header: "mid", Format: xlsx.Price},
+	{Header: "last", Format: xlsx.Price}, {Header: "volume", Format: xlsx.Integer},
+	{Header: "open_interest", Format: xlsx.Integer}, {Header: "implied_volatility", Format: xlsx.Percent},
+	{Header: "delta", Format: xlsx.Price}, {Header: "gamma", Format: xlsx.Price},
+	{Header: "theta", Format: xlsx.Price}, {Header: "vega", Format: xlsx.Price},
+	{Header: "vanna", Format: xlsx.Price}, {Header: "charm", Format: xlsx.Price},
+	{Header: "vomma", Format: xlsx.Price}, {Header: "liquidity_score"},
+	{Header: "underlying_price", Format: xlsx.Price},
+}
+
+// Chain lays out an options chain as a calls sheet and a puts sheet per expiration, nearest
+// expiration first and each sheet ordered by strike. An empty chain is one empty sheet, since
+// a workbook needs at least one.
+func Chain(contracts []models.OptionContract, exportedAt time.Time) []xlsx.Sheet {
+	type sheetKey struct{ expiration, contractType string }
+	bySheet := make(map[sheetKey][]*models.OptionContract)
+	for i := range contracts {
+		d := contracts[i].Details
+		if d == nil || d.ExpirationDate == nil || d.ContractType == nil {
+			continue
+		}
+		key := sheetKey{*d.ExpirationDate, *d.ContractType}
+		bySheet[key] = append(bySheet[key], &contracts[i])
+	}
+	if len(bySheet) == 0 {
+		return []xlsx.Sheet{{Name: "Chain", Columns: chainColumns, Rows: [][]any{}}}
+	}
+
+	keys := make([]sheetKey, 0, len(bySheet))
+	for key := range bySheet {
+		keys = append(keys, key)
+	}
+	// Calls sort before puts, which is the order of the type names
+	slices.SortFunc(keys, func(a, b sheetKey) int {
+		return cmp.Or(cmp.Compare(a.expiration, b.expiration), cmp.Compare(a.contractType, b.contractType))
+	})
+
+	today := time.Date(exportedAt.Year(), exportedAt.Month(), exportedAt.Day(), 0, 0, 0, 0, time.UTC)
+	sheets := make([]xlsx.Sheet, 0, len(keys))
+	for _, key := range keys {
+		name := key.expiration + " Puts"
+		if key.contractType == "call" {
+			name = key.expiration + " Calls"
+		}
+		rows := bySheet[key]
+		slices.SortStableFunc(rows, func(a, b *models.OptionContract) int {
+			return cmp.Compare(derefFloat(a.Details.StrikePrice), derefFloat(b.Details.StrikePrice))
+		})
+
+		var dte any
+		if expiry, err := time.Parse("2006-01-02", key.expiration); err == nil {
+			dte = int(expiry.Sub(today).Hours() / 24)
+		}
+		sheet := xlsx.Sheet{Name: name, Columns: chainColumns, Rows: make([][]any, 0, len(rows))}
+		for _, contract := range rows {
+			sheet.Rows = append(sheet.Rows, chainRow(contract, dte))
+		}
+		sheets = append(sheets, sheet)
+	}
+	return sheets
+}
+
+// chainRow lays out one contract in chainColumns order
+func chainRow(contract *models.OptionContract, dte any) []any {
+	var bid, ask, mid, last, volume, openInterest, iv any
+	var delta, gamma, theta, vega, vanna, charm, vomma, underlying any
+	if q := contract.LastQuote; q != nil {
+		bid, ask, mid = optional(q.Bid), optional(q.Ask), optional(q.MidPrice())
+	}
+	if t := contract.LastTrade; t != nil {
+		last = optional(t.Price)
+	}
+	if d := contract.Day; d != nil {
+		volume = optionalInt(d.Volume)
+	}
+	openInterest = optionalInt(contract.OpenInterest)
+	iv = optional(contract.ImpliedVol)
+	if g := contract.Greeks; g != nil {
+		delta, gamma, theta, vega = optional(g.Delta), optional(g.Gamma), optional(g.Theta), optional(g.Vega)
+		vanna, charm, vomma = optional(g.Vanna), optional(g.Charm), optional(g.Vomma)
+	}
+	if u := contract.UnderlyingAsset; u != nil {
+		underlying = optional(u.Price)
+	}
+	return []any{
+		optionalString(contract.Details.Ticker), optional(contract.Details.StrikePrice),
+		dte, bid,
+		ask, mid,
+		last, volume,
+		openInterest, iv,
+		delta, gamma,
+		theta, vega,
+		vanna, charm,
+		vomma, optional(contract.LiquidityScore),
+		underlying,
+	}
+}
+
+func derefFloat(v *float64) float64 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
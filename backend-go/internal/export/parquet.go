@@ -0,0 +1,154 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// chainParquetRow is a chain contract as a Parquet row. Live and historical chains share it,
+// so files of either can be read as one table.
+type chainParquetRow struct {
+	CapturedAt      int64    `parquet:"name=captured_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	Underlying      string   `parquet:"name=underlying, type=BYTE_ARRAY, convertedtype=UTF8"`
+	UnderlyingPrice *float64 `parquet:"name=underlying_price, type=DOUBLE, repetitiontype=OPTIONAL"`
+	Ticker          string   `parquet:"name=ticker, type=BYTE_ARRAY, convertedtype=UTF8"`
+	ContractType    string   `parquet:"name=contract_type, type=BYTE_ARRAY, convertedtype=UTF8"`
+	StrikePrice     float64  `parquet:"name=strike_price, type=DOUBLE"`
+	ExpirationDate  int32    `parquet:"name=expiration_date, type=INT32, convertedtype=DATE"`
+	Bid             *float64 `parquet:"name=bid, type=DOUBLE, repetitiontype=OPTIONAL"`
+	Ask             *float64 `parquet:"name=ask, type=DOUBLE, repetitiontype=OPTIONAL"`
+	LastPrice       *float64 `parquet:"name=last_price, type=DOUBLE, repetitiontype=OPTIONAL"`
+	Volume          *int64   `parquet:"name=volume, type=INT64, repetitiontype=OPTIONAL"`
+	OpenInterest    *int64   `parquet:"name=open_interest, type=INT64, repetitiontype=OPTIONAL"`
+	ImpliedVol      *float64 `parquet:"name=implied_volatility, type=DOUBLE, repetitiontype=OPTIONAL"`
+	Delta           *float64 `parquet:"name=delta, type=DOUBLE, repetitiontype=OPTIONAL"`
+	Gamma           *float64 `parquet:"name=gamma, type=DOUBLE, repetitiontype=OPTIONAL"`
+	Theta           *float64 `parquet:"name=theta, type=DOUBLE, repetitiontype=OPTIONAL"`
+	Vega            *float64 `parquet:"name=vega, type=DOUBLE, repetitiontype=OPTIONAL"`
+	Rho             *float64 `parquet:"name=rho, type=DOUBLE, repetitiontype=OPTIONAL"`
+}
+
+// portfolioHistoryParquetRow is a portfolio's daily snapshot as a Parquet row
+type portfolioHistoryParquetRow struct {
+	PortfolioID       int64   `parquet:"name=portfolio_id, type=INT64"`
+	Date              int32   `parquet:"name=date, type=INT32, convertedtype=DATE"`
+	MarketValue       float64 `parquet:"name=market_value, type=DOUBLE"`
+	CostBasis         float64 `parquet:"name=cost_basis, type=DOUBLE"`
+	UnrealizedPnL     float64 `parquet:"name=unrealized_pnl, type=DOUBLE"`
+	RealizedPnL       float64 `parquet:"name=realized_pnl, type=DOUBLE"`
+	Dividends         float64 `parquet:"name=dividends, type=DOUBLE"`
+	TotalPnL          float64 `parquet:"name=total_pnl, type=DOUBLE"`
+	Fees              float64 `parquet:"name=fees, type=DOUBLE"`
+	OpenPositions     int32   `parquet:"name=open_positions, type=INT32"`
+	Unpriced          int32   `parquet:"name=unpriced, type=INT32"`
+	Delta             float64 `parquet:"name=delta, type=DOUBLE"`
+	DollarDelta       float64 `parquet:"name=dollar_delta, type=DOUBLE"`
+	Gamma             float64 `parquet:"name=gamma, type=DOUBLE"`
+	Theta             float64 `parquet:"name=theta, type=DOUBLE"`
+	Vega              float64 `parquet:"name=vega, type=DOUBLE"`
+	BetaWeightedDelta float64 `parquet:"name=beta_weighted_delta, type=DOUBLE"`
+	BetaWeightedGamma float64 `parquet:"name=beta_weighted_gamma, type=DOUBLE"`
+}
+
+// WriteChainParquet writes a chain as a Snappy-compressed Parquet file, one row per contract
+// stamped with its underlying and capture time. Contracts missing their ticker, type, strike
+// or expiration are left out, as they are from stored snapshots.
+func WriteChainParquet(w io.Writer, underlying string, underlyingPrice *float64, capturedAt time.Time, contracts []models.OptionContract) error {
+	rows := make([]chainParquetRow, 0, len(contracts))
+	for i := range contracts {
+		contract, ok := models.NewOptionsSnapshotContract(underlying, capturedAt, &contracts[i])
+		if !ok {
+			continue
+		}
+		expiration, err := parquetDate(contract.ExpirationDate)
+		if err != nil {
+			continue
+		}
+		rows = append(rows, chainParquetRow{
+			CapturedAt:      capturedAt.UnixMilli(),
+			Underlying:      underlying,
+			UnderlyingPrice: underlyingPrice,
+			Ticker:          contract.Ticker,
+			ContractType:    contract.ContractType,
+			StrikePrice:     contract.StrikePrice,
+			ExpirationDate:  expiration,
+			Bid:             contract.Bid,
+			Ask:             contract.Ask,
+			LastPrice:       contract.LastPrice,
+			Volume:          contract.Volume,
+			OpenInterest:    contract.OpenInterest,
+			ImpliedVol:      contract.ImpliedVol,
+			Delta:           contract.Delta,
+			Gamma:           contract.Gamma,
+			Theta:           contract.Theta,
+			Vega:            contract.Vega,
+			Rho:             contract.Rho,
+		})
+	}
+	return writeParquet(w, rows)
+}
+
+// WritePortfolioHistoryParquet writes a portfolio's daily snapshots as a Snappy-compressed
+// Parquet file, one row per trading day
+func WritePortfolioHistoryParquet(w io.Writer, snapshots []models.PortfolioSnapshot) error {
+	rows := make([]portfolioHistoryParquetRow, 0, len(snapshots))
+	for _, s := range snapshots {
+		date, err := parquetDate(s.Date)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, portfolioHistoryParquetRow{
+			PortfolioID:       s.PortfolioID,
+			Date:              date,
+			MarketValue:       s.MarketValue,
+			CostBasis:         s.CostBasis,
+			UnrealizedPnL:     s.UnrealizedPnL,
+			RealizedPnL:       s.RealizedPnL,
+			Dividends:         s.Dividends,
+			TotalPnL:          s.TotalPnL,
+			Fees:              s.Fees,
+			OpenPositions:     int32(s.OpenPositions),
+			Unpriced:          int32(s.Unpriced),
+			Delta:             s.Delta,
+			DollarDelta:       s.DollarDelta,
+			Gamma:             s.Gamma,
+			Theta:             s.Theta,
+			Vega:              s.Vega,
+			BetaWeightedDelta: s.BetaWeightedDelta,
+			BetaWeightedGamma: s.BetaWeightedGamma,
+		})
+	}
+	return writeParquet(w, rows)
+}
+
+// writeParquet encodes rows whose struct tags describe the schema
+func writeParquet[T any](w io.Writer, rows []T) error {
+	pw, err := writer.NewParquetWriterFromWriter(w, new(T), 1)
+	if err != nil {
+		return fmt.Errorf("failed to create parquet writer: %w", err)
+	}
+	pw.CompressionType = parquet.CompressionCodec_SNAPPY
+	for _, row := range rows {
+		if err := pw.Write(row); err != nil {
+			return fmt.Errorf("failed to write parquet row: %w", err)
+		}
+	}
+	if err := pw.WriteStop(); err != nil {
+		return fmt.Errorf("failed to finish parquet file: %w", err)
+	}
+	return nil
+}
+
+// parquetDate converts a YYYY-MM-DD date to Parquet's days since the Unix epoch
+func parquetDate(date string) (int32, error) {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return 0, fmt.Errorf("invalid date %q", date)
+	}
+	return int32(t.Unix() / 86400), nil
+}
@@ -0,0 +1,295 @@
+// Package export lays out portfolio and chain data as spreadsheet tables for CSV and Excel
+// downloads
+package export
+
+import (
+	"cmp"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/xlsx"
+)
+
+// Sections of a portfolio export: each is a sheet in Excel and a separate CSV file
+const (
+	SectionSummary   = "summary"
+	SectionPositions = "positions"
+	SectionLedger    = "ledger"
+)
+
+// Sections lists the export sections in workbook order
+var Sections = []string{SectionSummary, SectionPositions, SectionLedger}
+
+// Portfolio lays out a portfolio's P/L summary, every position (open legs marked to
+// market) and its trade ledger oldest first, one sheet per section in Sections order. The
+// open option legs follow in a calls sheet and a puts sheet per expiration, which only the
+// Excel workbook includes.
+func Portfolio(p *models.Portfolio, positions []models.Position, valuation *models.PortfolioValuation, transactions []models.Transaction, exportedAt time.Time) []xlsx.Sheet {
+	sheets := []xlsx.Sheet{
+		summarySheet(p, valuation, exportedAt),
+		positionsSheet(positions, valuation),
+		ledgerSheet(positions, transactions),
+	}
+	return append(sheets, optionSheets(positions, valuation)...)
+}
+
+func summarySheet(p *models.Portfolio, v *models.PortfolioValuation, exportedAt time.Time) xlsx.Sheet {
+	return xlsx.Sheet{
+		Name: "Summary",
+		Columns: []xlsx.Column{
+			{Header: "portfolio", Width: 24}, {Header: "account_type"}, {Header: "exported_at", Width: 22},
+			{Header: "market_value", Format: xlsx.Money}, {Header: "cost_basis", Format: xlsx.Money},
+			{Header: "unrealized_pnl", Format: xlsx.Money}, {Header: "realized_pnl", Format: xlsx.Money},
+			{Header: "dividends", Format: xlsx.Money}, {Header: "total_pnl", Format: xlsx.Money},
+			{Header: "fees", Format: xlsx.Money}, {Header: "open_positions", Format: xlsx.Integer},
+			{Header: "unpriced", Format: xlsx.Integer},
+		},
+		Rows: [][]any{{
+			p.Name, p.Settings.AccountType, exportedAt.UTC().Format(time.RFC3339),
+			v.MarketValue, v.CostBasis, v.UnrealizedPnL, v.RealizedPnL,
+			v.Dividends, v.TotalPnL, v.Fees, v.OpenPositions, v.Unpriced,
+		}},
+	}
+}
+
+func positionsSheet(positions []models.Position, v *models.PortfolioValuation) xlsx.Sheet {
+	marked := make(map[int64]*models.PositionValuation, len(v.Positions))
+	for i := range v.Positions {
+		marked[v.Positions[i].Position.ID] = &v.Positions[i]
+	}
+
+	sheet := xlsx.Sheet{
+		Name: "Positions",
+		Columns: []xlsx.Column{
+			{Header: "id", Format: xlsx.Integer}, {Header: "ticker", Width: 24}, {Header: "underlying"},
+			{Header: "asset_type"}, {Header: "contract_type"}, {Header: "strike", Format: xlsx.Price},
+			{Header: "expiration", Format: xlsx.Date}, {Header: "side"}, {Header: "quantity"},
+			{Header: "multiplier", Format: xlsx.Integer}, {Header: "open_price", Format: xlsx.Price},
+			{Header: "opened_at", Format: xlsx.Date}, {Header: "status"},
+			{Header: "mark_price", Format: xlsx.Price}, {Header: "market_value", Format: xlsx.Money},
+			{Header: "cost_basis", Format: xlsx.Money}, {Header: "unrealized_pnl", Format: xlsx.Money},
+			{Header: "unrealized_pnl_percent", Format: xlsx.Percent}, {Header: "realized_pnl", Format: xlsx.Money},
+			{Header: "dividends", Format: xlsx.Money}, {Header: "fees", Format: xlsx.Money},
+			{Header: "close_price", Format: xlsx.Price}, {Header: "closed_at", Format: xlsx.Date},
+			{Header: "close_reason"}, {Header: "strategy_id", Format: xlsx.Integer},
+		},
+		Rows: [][]any{},
+	}
+
+	for i := range positions {
+		p := &positions[i]
+		var mark, marketValue, costBasis, unrealized, unrealizedPct any
+		if pv, ok := marked[p.ID]; ok {
+			mark, marketValue, unrealized = optional(pv.MarkPrice), optional(pv.MarketValue), optional(pv.UnrealizedPnL)
+			costBasis = pv.CostBasis
+			if pv.UnrealizedPnLPercent != nil {
+				unrealizedPct = *pv.UnrealizedPnLPercent / 100
+			}
+		}
+		sheet.Rows = append(sheet.Rows, []any{
+			p.ID, p.Ticker, p.UnderlyingTicker, p.AssetType, optionalString(p.ContractType), optional(p.StrikePrice),
+			optionalString(p.ExpirationDate), p.Side, p.Quantity, p.Multiplier, p.OpenPrice,
+			p.OpenedAt, p.Status, mark, marketValue,
+			costBasis, unrealized, unrealizedPct, p.RealizedPnL,
+			p.Dividends, p.Fees, optional(p.ClosePrice), optionalString(p.ClosedAt),
+			optionalString(p.CloseReason), optionalInt(p.StrategyID),
+		})
+	}
+	return sheet
+}
+
+// optionSheets lays out the open option legs by expiration, nearest first, calls before puts
+// and each sheet ordered by strike
+func optionSheets(positions []models.Position, v *models.PortfolioValuation) []xlsx.Sheet {
+	marked := make(map[int64]*models.PositionValuation, len(v.Positions))
+	for i := range v.Positions {
+		marked[v.Positions[i].Position.ID] = &v.Positions[i]
+	}
+
+	type sheetKey struct{ expiration, contractType string }
+	bySheet := make(map[sheetKey][]*models.Position)
+	for i := range positions {
+		p := &positions[i]
+		if !p.IsOption() || p.Status != models.PositionOpen || p.ExpirationDate == nil || p.ContractType == nil {
+			continue
+		}
+		key := sheetKey{*p.ExpirationDate, *p.ContractType}
+		bySheet[key] = append(bySheet[key], p)
+	}
+	keys := make([]sheetKey, 0, len(bySheet))
+	for key := range bySheet {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b sheetKey) int {
+		return cmp.Or(cmp.Compare(a.expiration, b.expiration), cmp.Compare(a.contractType, b.contractType))
+	})
+
+	sheets := make([]xlsx.Sheet, 0, len(keys))
+	for _, key := range keys {
+		name := key.expiration + " Puts"
+		if key.contractType == "call" {
+			name = key.expiration + " Calls"
+		}
+		legs := bySheet[key]
+		slices.SortStableFunc(legs, func(a, b *models.Position) int {
+			return cmp.Or(cmp.Compare(derefFloat(a.StrikePrice), derefFloat(b.StrikePrice)), cmp.Compare(a.ID, b.ID))
+		})
+
+		sheet := xlsx.Sheet{
+			Name: name,
+			Columns: []xlsx.Column{
+				{Header: "id", Format: xlsx.Integer}, {Header: "ticker", Width: 24}, {Header: "underlying"},
+				{Header: "strike", Format: xlsx.Price}, {Header: "side"}, {Header: "quantity"},
+				{Header: "open_price", Format: xlsx.Price}, {Header: "mark_price", Format: xlsx.Price},
+				{Header: "market_value", Format: xlsx.Money}, {Header: "cost_basis", Format: xlsx.Money},
+				{Header: "unrealized_pnl", Format: xlsx.Money}, {Header: "unrealized_pnl_percent", Format: xlsx.Percent},
+			},
+			Rows: make([][]any, 0, len(legs)),
+		}
+		for _, p := range legs {
+			var mark, marketValue, unrealized, unrealizedPct any
+			costBasis := any(p.CostBasis())
+			if pv, ok := marked[p.ID]; ok {
+				mark, marketValue, unrealized = optional(pv.MarkPrice), optional(pv.MarketValue), optional(pv.UnrealizedPnL)
+				costBasis = pv.CostBasis
+				if pv.UnrealizedPnLPercent != nil {
+					unrealizedPct = *pv.UnrealizedPnLPercent / 100
+				}
+			}
+			sheet.Rows = append(sheet.Rows, []any{
+				p.ID, p.Ticker, p.UnderlyingTicker,
+				optional(p.StrikePrice), p.Side, p.Quantity,
+				p.OpenPrice, mark,
+				marketValue, costBasis,
+				unrealized, unrealizedPct,
+			})
+		}
+		sheets = append(sheets, sheet)
+	}
+	return sheets
+}
+
+func ledgerSheet(positions []models.Position, transactions []models.Transaction) xlsx.Sheet {
+	tickers := make(map[int64]string, len(positions))
+	for _, p := range positions {
+		tickers[p.ID] = p.Ticker
+	}
+
+	sheet := xlsx.Sheet{
+		Name: "Ledger",
+		Columns: []xlsx.Column{
+			{Header: "id", Format: xlsx.Integer}, {Header: "traded_at", Format: xlsx.Date},
+			{Header: "position_id", Format: xlsx.Integer}, {Header: "ticker", Width: 24}, {Header: "action"},
+			{Header: "quantity"}, {Header: "price", Format: xlsx.Price}, {Header: "fees", Format: xlsx.Money},
+			{Header: "amount", Format: xlsx.Money}, {Header: "realized_pnl", Format: xlsx.Money},
+			{Header: "related_transaction_id", Format: xlsx.Integer},
+		},
+		Rows: [][]any{},
+	}
+
+	ordered := slices.Clone(transactions)
+	slices.SortStableFunc(ordered, func(a, b models.Transaction) int {
+		return cmp.Or(strings.Compare(a.TradedAt, b.TradedAt), cmp.Compare(a.ID, b.ID))
+	})
+	for _, t := range ordered {
+		sheet.Rows = append(sheet.Rows, []any{
+			t.ID, t.TradedAt, t.PositionID, tickers[t.PositionID], t.Action,
+			t.Quantity, t.Price, t.Fees, t.Amount, optional(t.RealizedPnL),
+			optionalInt(t.RelatedTransactionID),
+		})
+	}
+	return sheet
+}
+
+// WriteCSV writes a sheet as CSV with a header row. Money is written to two decimals,
+// prices to four and percentages in percent points to two, without thousands separators.
+func WriteCSV(w io.Writer, sheet *xlsx.Sheet) error {
+	cw := csv.NewWriter(w)
+	header := make([]string, len(sheet.Columns))
+	for i, col := range sheet.Columns {
+		header[i] = col.Header
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, row := range sheet.Rows {
+		record := make([]string, len(row))
+		for i, v := range row {
+			format := xlsx.General
+			if i < len(sheet.Columns) {
+				format = sheet.Columns[i].Format
+			}
+			record[i] = formatCSV(format, v)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// formatCSV renders one value for CSV according to its column format
+func formatCSV(format xlsx.Format, v any) string {
+	var n float64
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.Format("2006-01-02")
+	case int:
+		n = float64(v)
+	case int64:
+		n = float64(v)
+	case float64:
+		n = v
+	default:
+		return fmt.Sprint(v)
+	}
+
+	switch format {
+	case xlsx.Integer:
+		return strconv.FormatFloat(n, 'f', 0, 64)
+	case xlsx.Money:
+		return strconv.FormatFloat(n, 'f', 2, 64)
+	case xlsx.Price:
+		return strconv.FormatFloat(n, 'f', 4, 64)
+	case xlsx.Percent:
+		return strconv.FormatFloat(n*100, 'f', 2, 64)
+	default:
+		return strconv.FormatFloat(n, 'f', -1, 64)
+	}
+}
+
+// optional unwraps a nullable number into a cell value, nil for an empty cell
+func optional(v *float64) any {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+func optionalInt(v *int64) any {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+func optionalString(v *string) any {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
@@ -0,0 +1,173 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/aaronbengochea/periscope/backend-go/internal/analytics"
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/services"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
+)
+
+// Contract is an option contract snapshot flattened into the fields the schema serves
+type Contract struct {
+	Ticker            string
+	Underlying        string
+	ContractType      string
+	Strike            float64
+	Expiration        string
+	Bid               *float64
+	Ask               *float64
+	Mark              *float64
+	Last              *float64
+	Volume            *int64
+	OpenInterest      *int64
+	ImpliedVolatility *float64
+	UnderlyingPrice   *float64
+	Greeks            *models.Greeks
+}
+
+// newContract flattens a snapshot, taking the underlying ticker from underlying when the
+// snapshot leaves it out
+func newContract(c *models.OptionContract, underlying string) *Contract {
+	out := &Contract{
+		Underlying:        underlying,
+		Mark:              analytics.ContractPrice(c),
+		OpenInterest:      c.OpenInterest,
+		ImpliedVolatility: c.ImpliedVol,
+		Greeks:            c.Greeks,
+	}
+	if d := c.Details; d != nil {
+		out.Ticker = deref(d.Ticker)
+		out.ContractType = deref(d.ContractType)
+		out.Strike = deref(d.StrikePrice)
+		out.Expiration = deref(d.ExpirationDate)
+	}
+	if u := c.UnderlyingAsset; u != nil {
+		if u.Ticker != nil {
+			out.Underlying = *u.Ticker
+		}
+		out.UnderlyingPrice = u.Price
+	}
+	if q := c.LastQuote; q != nil {
+		out.Bid, out.Ask = q.Bid, q.Ask
+	}
+	if t := c.LastTrade; t != nil {
+		out.Last = t.Price
+	}
+	if day := c.Day; day != nil {
+		out.Volume = day.Volume
+	}
+	return out
+}
+
+// newContracts flattens snapshots, as newContract does
+func newContracts(contracts []models.OptionContract, underlying string) []*Contract {
+	out := make([]*Contract, len(contracts))
+	for i := range contracts {
+		out[i] = newContract(&contracts[i], underlying)
+	}
+	return out
+}
+
+// Position is a portfolio position whose market fields are resolved from quotes fetched once
+// for every position listed with it, when the first of those fields is asked for
+type Position struct {
+	models.Position
+	quotes *quoteBatch
+}
+
+// quoteBatch fetches the quotes of a list of positions on first use
+type quoteBatch struct {
+	valuations *services.ValuationService
+	positions  []models.Position // the open ones
+
+	once   sync.Once
+	quotes *services.Quotes
+	err    error
+}
+
+// get returns the positions' quotes, fetching them the first time it is called
+func (b *quoteBatch) get(ctx context.Context) (*services.Quotes, error) {
+	b.once.Do(func() {
+		b.quotes, b.err = b.valuations.FetchQuotes(ctx, b.positions)
+	})
+	return b.quotes, b.err
+}
+
+// newPositions wraps positions to share one quote batch
+func newPositions(positions []models.Position, valuations *services.ValuationService) []*Position {
+	batch := &quoteBatch{valuations: valuations}
+	out := make([]*Position, len(positions))
+	for i := range positions {
+		out[i] = &Position{Position: positions[i], quotes: batch}
+		if positions[i].Status == models.PositionOpen {
+			batch.positions = append(batch.positions, positions[i])
+		}
+	}
+	return out
+}
+
+// openQuotes returns the quotes of an open position's batch, or nil for a closed position
+func openQuotes(ctx context.Context, p *Position) (*services.Quotes, error) {
+	if p.Status != models.PositionOpen {
+		return nil, nil
+	}
+	quotes, err := p.quotes.get(ctx)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to fetch quotes", err)
+	}
+	return quotes, nil
+}
+
+// marketValue returns the signed value of a position at its mark, as Valuate computes it, or
+// nil when it is unpriced
+func marketValue(p *models.Position, quotes *services.Quotes) *float64 {
+	mark := quotes.MarkPrice(p)
+	if mark == nil {
+		return nil
+	}
+	value := p.SignedQuantity() * *mark * float64(p.Multiplier)
+	return &value
+}
+
+// pointers returns pointers to the elements of items, the form gqlgen resolves lists in
+func pointers[T any](items []T) []*T {
+	out := make([]*T, len(items))
+	for i := range items {
+		out[i] = &items[i]
+	}
+	return out
+}
+
+// MarshalInt64ID writes the database IDs of portfolios, positions and watchlists as GraphQL
+// IDs, which are strings
+func MarshalInt64ID(id int64) graphql.Marshaler {
+	return graphql.MarshalID(strconv.FormatInt(id, 10))
+}
+
+// UnmarshalInt64ID reads a database ID from a GraphQL ID, given as a string or a number
+func UnmarshalInt64ID(v any) (int64, error) {
+	s, err := graphql.UnmarshalID(v)
+	if err != nil {
+		return 0, err
+	}
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a valid ID", s)
+	}
+	return id, nil
+}
+
+// deref returns the value a pointer points to, or the zero value for nil
+func deref[T any](p *T) T {
+	if p == nil {
+		var zero T
+		return zero
+	}
+	return *p
+}
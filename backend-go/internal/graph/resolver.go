@@ -0,0 +1,168 @@
+// Package graph serves Periscope's chains, contracts, portfolios and watchlists over GraphQL
+// at /graphql, from the same services and repositories the REST API uses. The schema is
+// schema.graphqls; generated.go and models_gen.go are generated from it with gqlgen
+// (make gqlgen), and the resolvers in *.resolvers.go are kept by hand.
+package graph
+
+import (
+	"context"
+	stderrors "errors"
+	"log"
+	"net/http"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/aaronbengochea/periscope/backend-go/internal/services"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/database"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/massive"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// complexityLimit caps the fields one query may select. The schema has no cycles, so this
+// also bounds how deep a query can go.
+const complexityLimit = 500
+
+// maxTickers is the most contracts one query may ask for, as over REST
+const maxTickers = 250
+
+// maxIVTickers is the most stocks a watchlist may have for its quotes to include ATM IV, as
+// over REST
+const maxIVTickers = 50
+
+// Storage is where portfolios and watchlists are kept: the database, or the Supabase REST
+// API when the server runs without one
+type Storage struct {
+	DB   *database.DB
+	REST bool // portfolios and watchlists are kept through the REST API
+}
+
+// Resolver resolves the schema's fields
+type Resolver struct {
+	massiveClient   *massive.Client
+	chains          *services.ChainService
+	valuations      *services.ValuationService
+	watchlistQuotes *services.WatchlistService
+	portfolios      repository.PortfolioRepo
+	positions       *repository.PositionRepository
+	watchlists      repository.WatchlistRepo
+	storage         Storage
+}
+
+// NewResolver creates a new resolver
+func NewResolver(massiveClient *massive.Client, chains *services.ChainService, valuations *services.ValuationService, watchlistQuotes *services.WatchlistService,
+	portfolios repository.PortfolioRepo, positions *repository.PositionRepository, watchlists repository.WatchlistRepo, storage Storage) *Resolver {
+	return &Resolver{
+		massiveClient:   massiveClient,
+		chains:          chains,
+		valuations:      valuations,
+		watchlistQuotes: watchlistQuotes,
+		portfolios:      portfolios,
+		positions:       positions,
+		watchlists:      watchlists,
+		storage:         storage,
+	}
+}
+
+// NewHandler serves queries over GET and POST. Mutations and subscriptions are left to the
+// REST API and streams. Errors carry the code of the REST API's error envelope in their
+// extensions.
+func NewHandler(resolver *Resolver) http.Handler {
+	server := handler.New(NewExecutableSchema(Config{Resolvers: resolver}))
+	server.AddTransport(transport.GET{})
+	server.AddTransport(transport.POST{})
+	server.Use(extension.Introspection{})
+	server.Use(extension.FixedComplexityLimit(complexityLimit))
+	server.SetErrorPresenter(presentError)
+	return server
+}
+
+// presentError sends an AppError's message and code, logging the cause of server errors.
+// Resolvers only return AppErrors, so anything else is gqlgen's own report of an invalid
+// query or argument and is sent as it is.
+func presentError(ctx context.Context, err error) *gqlerror.Error {
+	gqlErr := graphql.DefaultErrorPresenter(ctx, err)
+	var appErr *errors.AppError
+	if !stderrors.As(err, &appErr) {
+		return gqlErr
+	}
+	if appErr.StatusCode >= 500 && appErr.Err != nil {
+		log.Printf("[GraphQL] ✗ %v", appErr)
+	}
+	gqlErr.Message = appErr.Message
+	if gqlErr.Extensions == nil {
+		gqlErr.Extensions = map[string]any{}
+	}
+	gqlErr.Extensions["code"] = appErr.Code
+	if appErr.Details != nil {
+		gqlErr.Extensions["details"] = appErr.Details
+	}
+	return gqlErr
+}
+
+// Caller is who a query is made by, as the auth middleware identified them
+type Caller struct {
+	UserID    *string        // nil when auth is disabled, or for anonymous callers
+	APIKey    *models.APIKey // set for callers using an API key
+	Anonymous bool           // auth is enabled and the caller sent no token
+}
+
+type callerKey struct{}
+
+// WithCaller returns a copy of ctx carrying the caller of the query
+func WithCaller(ctx context.Context, caller Caller) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller)
+}
+
+// callerFrom returns the caller of the query
+func callerFrom(ctx context.Context) Caller {
+	caller, _ := ctx.Value(callerKey{}).(Caller)
+	return caller
+}
+
+// requireScope rejects callers using an API key without scope, as RequireScope does over REST
+func requireScope(ctx context.Context, scope string) error {
+	caller := callerFrom(ctx)
+	if caller.APIKey != nil && !caller.APIKey.HasScope(scope) {
+		return errors.NewForbiddenError("API key lacks the " + scope + " scope")
+	}
+	return nil
+}
+
+// requireUser returns the caller's user ID for their portfolios and watchlists, rejecting
+// anonymous callers and those whose API key lacks scope, and requests made while neither the
+// database nor the REST API is there to read them from
+func (r *Resolver) requireUser(ctx context.Context, scope string) (*string, error) {
+	caller := callerFrom(ctx)
+	if caller.Anonymous {
+		return nil, errors.NewUnauthorizedError("missing access token")
+	}
+	if err := requireScope(ctx, scope); err != nil {
+		return nil, err
+	}
+	if !r.storage.REST && !r.storage.DB.Connected() {
+		return nil, errors.NewServiceUnavailableError("database not connected")
+	}
+	return caller.UserID, nil
+}
+
+// requireDatabase rejects requests for data only the database holds while it is not connected
+func (r *Resolver) requireDatabase() error {
+	if !r.storage.DB.Connected() {
+		return errors.NewServiceUnavailableError("database not connected")
+	}
+	return nil
+}
+
+// notFoundAsNull turns ErrNotFound into a null result, for lookups by ID that answer null
+// when the caller has nothing with that ID
+func notFoundAsNull(err error, message string) error {
+	if stderrors.Is(err, repository.ErrNotFound) {
+		return nil
+	}
+	return errors.NewInternalError(message, err)
+}
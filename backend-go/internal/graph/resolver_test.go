@@ -0,0 +1,110 @@
+package graph_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/graph"
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/aaronbengochea/periscope/backend-go/internal/services"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/massive"
+)
+
+// fakeMassive serves a two-contract SPY chain and no stock snapshot, so the underlying price
+// comes from the contracts
+func fakeMassive(t *testing.T) *massive.Client {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/snapshot/options/SPY" {
+			_, _ = w.Write([]byte(`{"status":"OK","results":[]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"OK","results":[
+			{"details":{"ticker":"O:SPY261218C00600000","contract_type":"call","strike_price":600,"expiration_date":"2026-12-18"},
+			 "last_quote":{"bid":10,"ask":11},"greeks":{"delta":0.5},"open_interest":1200,
+			 "underlying_asset":{"ticker":"SPY","price":598.5}},
+			{"details":{"ticker":"O:SPY261218P00600000","contract_type":"put","strike_price":600,"expiration_date":"2026-12-18"},
+			 "underlying_asset":{"ticker":"SPY","price":598.5}}
+		]}`))
+	}))
+	t.Cleanup(srv.Close)
+	return massive.NewClient(srv.URL, "test")
+}
+
+// query posts a query as caller and returns the decoded response
+func query(t *testing.T, caller graph.Caller, q string) (data map[string]any, errs []map[string]any) {
+	client := fakeMassive(t)
+	resolver := graph.NewResolver(client, services.NewChainService(client), nil, services.NewWatchlistService(client),
+		repository.NewPortfolioRepository(nil), repository.NewPositionRepository(nil), repository.NewWatchlistRepository(nil), graph.Storage{})
+	server := graph.NewHandler(resolver)
+
+	body, _ := json.Marshal(map[string]string{"query": q})
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	req = req.WithContext(graph.WithCaller(req.Context(), caller))
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+
+	var resp struct {
+		Data   map[string]any   `json:"data"`
+		Errors []map[string]any `json:"errors"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("%d %s: %v", rec.Code, rec.Body, err)
+	}
+	return resp.Data, resp.Errors
+}
+
+func TestChainResolvesNestedFields(t *testing.T) {
+	data, errs := query(t, graph.Caller{}, `{
+		chain(ticker: "spy") {
+			ticker
+			underlyingPrice
+			contracts(first: 1) { ticker underlying contractType strike mark openInterest greeks { delta } }
+		}
+	}`)
+	if len(errs) > 0 {
+		t.Fatalf("errors: %v", errs)
+	}
+
+	got, _ := json.Marshal(data)
+	want := `{"chain":{"contracts":[{"contractType":"call","greeks":{"delta":0.5},"mark":10.5,"openInterest":1200,` +
+		`"strike":600,"ticker":"O:SPY261218C00600000","underlying":"SPY"}],"ticker":"SPY","underlyingPrice":598.5}}`
+	if string(got) != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
+
+func TestQueriesCheckTheCaller(t *testing.T) {
+	tests := []struct {
+		name   string
+		caller graph.Caller
+		query  string
+		code   string
+	}{
+		{"anonymous portfolios", graph.Caller{Anonymous: true}, `{ portfolios { id } }`, "unauthorized"},
+		{"anonymous watchlists", graph.Caller{Anonymous: true}, `{ watchlists { id } }`, "unauthorized"},
+		{"key without market scope", graph.Caller{APIKey: &models.APIKey{Scopes: []string{models.ScopePortfolioRead}}},
+			`{ chain(ticker: "SPY") { ticker } }`, "forbidden"},
+		{"key without portfolio scope", graph.Caller{APIKey: &models.APIKey{Scopes: []string{models.ScopeMarketRead}}},
+			`{ portfolios { id } }`, "forbidden"},
+		{"no database", graph.Caller{}, `{ portfolios { id } }`, "service_unavailable"},
+		{"bad contract type", graph.Caller{}, `{ chain(ticker: "SPY", contractType: "straddle") { ticker } }`, "bad_request"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, errs := query(t, tt.caller, tt.query)
+			if len(errs) != 1 {
+				t.Fatalf("got errors %v", errs)
+			}
+			extensions, _ := errs[0]["extensions"].(map[string]any)
+			if extensions["code"] != tt.code {
+				t.Errorf("got %v, want code %s", errs[0], tt.code)
+			}
+		})
+	}
+}
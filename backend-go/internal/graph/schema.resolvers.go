@@ -0,0 +1,234 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.78
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/services"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/errors"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/massive"
+)
+
+func (r *chainResolver) Contracts(ctx context.Context, obj *services.ChainSnapshot, first *int) ([]*Contract, error) {
+	contracts := obj.Contracts
+	if first != nil {
+		if *first < 0 {
+			return nil, errors.NewBadRequestError("first must not be negative", nil)
+		}
+		contracts = contracts[:min(*first, len(contracts))]
+	}
+	return newContracts(contracts, obj.Ticker), nil
+}
+
+func (r *portfolioResolver) Positions(ctx context.Context, obj *models.Portfolio, status *string) ([]*Position, error) {
+	if err := r.requireDatabase(); err != nil {
+		return nil, err
+	}
+	filter := ""
+	if status != nil {
+		filter = *status
+	}
+	if filter != "" && filter != models.PositionOpen && filter != models.PositionClosed {
+		return nil, errors.NewBadRequestError("status must be open or closed", nil)
+	}
+
+	positions, err := r.positions.ListByPortfolio(ctx, obj.ID, filter)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to list positions", err)
+	}
+	return newPositions(positions, r.valuations), nil
+}
+
+func (r *portfolioResolver) Valuation(ctx context.Context, obj *models.Portfolio) (*models.ValuationTotals, error) {
+	if err := r.requireDatabase(); err != nil {
+		return nil, err
+	}
+	valuation, err := r.valuations.ValuePortfolio(ctx, obj.ID)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to value portfolio", err)
+	}
+	return &valuation.ValuationTotals, nil
+}
+
+func (r *positionResolver) Contract(ctx context.Context, obj *Position) (*Contract, error) {
+	if !obj.IsOption() {
+		return nil, nil
+	}
+	quotes, err := openQuotes(ctx, obj)
+	if quotes == nil {
+		return nil, err
+	}
+	contract, ok := quotes.Contracts[obj.Ticker]
+	if !ok {
+		return nil, nil
+	}
+	return newContract(contract, obj.UnderlyingTicker), nil
+}
+
+func (r *positionResolver) MarkPrice(ctx context.Context, obj *Position) (*float64, error) {
+	quotes, err := openQuotes(ctx, obj)
+	if quotes == nil {
+		return nil, err
+	}
+	return quotes.MarkPrice(&obj.Position), nil
+}
+
+func (r *positionResolver) UnderlyingPrice(ctx context.Context, obj *Position) (*float64, error) {
+	quotes, err := openQuotes(ctx, obj)
+	if quotes == nil {
+		return nil, err
+	}
+	return quotes.UnderlyingPrice(&obj.Position), nil
+}
+
+func (r *positionResolver) MarketValue(ctx context.Context, obj *Position) (*float64, error) {
+	quotes, err := openQuotes(ctx, obj)
+	if quotes == nil {
+		return nil, err
+	}
+	return marketValue(&obj.Position, quotes), nil
+}
+
+func (r *positionResolver) UnrealizedPnl(ctx context.Context, obj *Position) (*float64, error) {
+	quotes, err := openQuotes(ctx, obj)
+	if quotes == nil {
+		return nil, err
+	}
+	value := marketValue(&obj.Position, quotes)
+	if value == nil {
+		return nil, nil
+	}
+	unrealized := *value - obj.CostBasis()
+	return &unrealized, nil
+}
+
+func (r *queryResolver) Chain(ctx context.Context, ticker string, expiration *string, contractType *string, strike *float64) (*services.ChainSnapshot, error) {
+	if err := requireScope(ctx, models.ScopeMarketRead); err != nil {
+		return nil, err
+	}
+	ticker = strings.ToUpper(strings.TrimSpace(ticker))
+	if ticker == "" {
+		return nil, errors.NewBadRequestError("ticker is required", nil)
+	}
+	params := &massive.OptionsChainParams{StrikePrice: strike}
+	if expiration != nil {
+		if _, err := time.Parse("2006-01-02", *expiration); err != nil {
+			return nil, errors.NewBadRequestError("expiration must be YYYY-MM-DD", err)
+		}
+		params.ExpirationDate = expiration
+	}
+	if contractType != nil {
+		if *contractType != "call" && *contractType != "put" {
+			return nil, errors.NewBadRequestError("contractType must be call or put", nil)
+		}
+		params.ContractType = contractType
+	}
+
+	snapshot, err := r.chains.GetSnapshot(ctx, ticker, params)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to fetch options chain", err)
+	}
+	log.Printf("[GraphQL] ✓ Fetched %d contracts for %s", len(snapshot.Contracts), ticker)
+	return snapshot, nil
+}
+
+func (r *queryResolver) Contracts(ctx context.Context, tickers []string) ([]*Contract, error) {
+	if err := requireScope(ctx, models.ScopeMarketRead); err != nil {
+		return nil, err
+	}
+	if len(tickers) == 0 || len(tickers) > maxTickers {
+		return nil, errors.NewBadRequestError(fmt.Sprintf("between 1 and %d tickers are required", maxTickers), nil)
+	}
+	contracts, err := r.massiveClient.GetContractDetails(ctx, tickers)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to fetch contract details", err)
+	}
+	return newContracts(contracts, ""), nil
+}
+
+func (r *queryResolver) Portfolios(ctx context.Context) ([]*models.Portfolio, error) {
+	userID, err := r.requireUser(ctx, models.ScopePortfolioRead)
+	if err != nil {
+		return nil, err
+	}
+	portfolios, err := r.portfolios.List(ctx, userID)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to list portfolios", err)
+	}
+	return pointers(portfolios), nil
+}
+
+func (r *queryResolver) Portfolio(ctx context.Context, id int64) (*models.Portfolio, error) {
+	userID, err := r.requireUser(ctx, models.ScopePortfolioRead)
+	if err != nil {
+		return nil, err
+	}
+	portfolio, err := r.portfolios.Get(ctx, userID, id)
+	if err != nil {
+		return nil, notFoundAsNull(err, "failed to get portfolio")
+	}
+	return portfolio, nil
+}
+
+func (r *queryResolver) Watchlists(ctx context.Context) ([]*models.Watchlist, error) {
+	userID, err := r.requireUser(ctx, models.ScopeWatchlistRead)
+	if err != nil {
+		return nil, err
+	}
+	watchlists, err := r.watchlists.List(ctx, userID)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to list watchlists", err)
+	}
+	return pointers(watchlists), nil
+}
+
+func (r *queryResolver) Watchlist(ctx context.Context, id int64) (*models.Watchlist, error) {
+	userID, err := r.requireUser(ctx, models.ScopeWatchlistRead)
+	if err != nil {
+		return nil, err
+	}
+	watchlist, err := r.watchlists.Get(ctx, userID, id)
+	if err != nil {
+		return nil, notFoundAsNull(err, "failed to get watchlist")
+	}
+	return watchlist, nil
+}
+
+func (r *watchlistResolver) Quotes(ctx context.Context, obj *models.Watchlist, iv *bool) (*models.WatchlistQuotes, error) {
+	if err := requireScope(ctx, models.ScopeMarketRead); err != nil {
+		return nil, err
+	}
+	withIV := iv != nil && *iv
+	if withIV && len(obj.Tickers(models.AssetTypeStock)) > maxIVTickers {
+		return nil, errors.NewBadRequestError(fmt.Sprintf("iv is limited to watchlists of %d stocks", maxIVTickers), nil)
+	}
+	quotes, err := r.watchlistQuotes.Quotes(ctx, obj, withIV)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to fetch watchlist quotes", err)
+	}
+	return quotes, nil
+}
+
+func (r *Resolver) Chain() ChainResolver { return &chainResolver{r} }
+
+func (r *Resolver) Portfolio() PortfolioResolver { return &portfolioResolver{r} }
+
+func (r *Resolver) Position() PositionResolver { return &positionResolver{r} }
+
+func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
+
+func (r *Resolver) Watchlist() WatchlistResolver { return &watchlistResolver{r} }
+
+type chainResolver struct{ *Resolver }
+type portfolioResolver struct{ *Resolver }
+type positionResolver struct{ *Resolver }
+type queryResolver struct{ *Resolver }
+type watchlistResolver struct{ *Resolver }
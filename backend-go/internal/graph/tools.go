@@ -0,0 +1,7 @@
+//go:build tools
+
+package graph
+
+// The gqlgen command is run from go.mod's version, so the generated code always matches the
+// runtime it is compiled against
+import _ "github.com/99designs/gqlgen"
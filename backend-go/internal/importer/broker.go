@@ -0,0 +1,76 @@
+package importer
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+)
+
+// brokerAction is what a broker transaction code means for the ledger
+type brokerAction struct {
+	action string // open or close
+	side   string // side of the position opened or closed; empty matches either side on close
+	expiry bool   // expiration, assignment or exercise: the option leg closes at zero
+}
+
+// newBrokerTrade builds a trade from a classified broker row. Quantities are taken as
+// absolute values since brokers sign them by direction, and fees are summed across
+// the commission and fee columns.
+func newBrokerTrade(row int, today string, act brokerAction, date, quantity, price string, fees ...string) (*models.ImportedTrade, error) {
+	trade := &models.ImportedTrade{
+		Row:       row,
+		Action:    act.action,
+		Side:      act.side,
+		TradedAt:  today,
+		AddToOpen: act.action == models.TransactionOpen,
+	}
+
+	q, err := parseNumber(quantity)
+	if err != nil || q == 0 {
+		return nil, fmt.Errorf("quantity must be a non-zero number")
+	}
+	trade.Quantity = math.Abs(q)
+
+	if !act.expiry {
+		p, err := parseNumber(price)
+		if err != nil {
+			return nil, fmt.Errorf("price must be a number")
+		}
+		trade.Price = math.Abs(p)
+	}
+
+	for _, raw := range fees {
+		if raw == "" {
+			continue
+		}
+		f, err := parseNumber(raw)
+		if err != nil {
+			return nil, fmt.Errorf("fees must be a number")
+		}
+		// Commissions are reported as debits (negative) by some brokers
+		trade.Fees += math.Abs(f)
+	}
+
+	if date != "" {
+		if trade.TradedAt, err = parseDate(date); err != nil {
+			return nil, err
+		}
+	}
+	return trade, nil
+}
+
+// setBrokerInstrument resolves a broker's option symbol into an OCC ticker with toOCC,
+// falling back to a share position when it is not an option
+func setBrokerInstrument(trade *models.ImportedTrade, symbol string, option bool, toOCC func(string) (*models.OptionSymbol, error)) error {
+	if !option {
+		return setInstrument(trade, strings.TrimSpace(symbol), models.AssetTypeStock, "")
+	}
+
+	parsed, err := toOCC(symbol)
+	if err != nil {
+		return fmt.Errorf("unrecognized option symbol %q", symbol)
+	}
+	return setInstrument(trade, parsed.Ticker(), models.AssetTypeOption, "")
+}
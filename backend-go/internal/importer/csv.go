@@ -0,0 +1,337 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+)
+
+// FormatPeriscope is the native CSV layout documented in the README
+const FormatPeriscope = "periscope"
+
+// defaultOptionMultiplier is the standard equity option contract size
+const defaultOptionMultiplier = 100
+
+// columnAliases maps accepted header names to canonical column names
+var columnAliases = map[string]string{
+	"action":            "action",
+	"asset_type":        "asset_type",
+	"type":              "asset_type",
+	"ticker":            "ticker",
+	"symbol":            "ticker",
+	"side":              "side",
+	"quantity":          "quantity",
+	"qty":               "quantity",
+	"price":             "price",
+	"fees":              "fees",
+	"commission":        "fees",
+	"commissions":       "fees",
+	"multiplier":        "multiplier",
+	"date":              "date",
+	"trade_date":        "date",
+	"traded_at":         "date",
+	"opened_at":         "date",
+	"underlying":        "underlying",
+	"underlying_ticker": "underlying",
+}
+
+// actionAliases maps accepted action values to a ledger action and, for the
+// buy/sell forms, the side of the position they apply to
+var actionAliases = map[string][2]string{
+	"open":          {models.TransactionOpen, ""},
+	"add":           {models.TransactionAdd, ""},
+	"close":         {models.TransactionClose, ""},
+	"buy_to_open":   {models.TransactionOpen, models.SideLong},
+	"sell_to_open":  {models.TransactionOpen, models.SideShort},
+	"buy_to_close":  {models.TransactionClose, models.SideShort},
+	"sell_to_close": {models.TransactionClose, models.SideLong},
+}
+
+// maxPreambleRows bounds how far into a file the header row is searched for; broker
+// exports often start with an account title or blank lines
+const maxPreambleRows = 10
+
+// skipRow marks a row that is not a trade. Skipped rows with a reason are reported;
+// those without one (totals, repeated headers) are dropped silently.
+type skipRow struct {
+	reason string
+}
+
+func (e *skipRow) Error() string { return e.reason }
+
+// rowParser converts one data row into a trade. get returns the trimmed cell for a
+// canonical column name, or "" when the column is absent.
+type rowParser func(row int, get func(string) string, today string) (*models.ImportedTrade, error)
+
+// ParseCSV reads the native Periscope CSV format. The first line must be a header; column
+// order is free, names are case-insensitive and unknown columns are ignored. Rows that fail
+// validation are returned as error results alongside the trades that parsed.
+func ParseCSV(r io.Reader, today string) ([]models.ImportedTrade, []models.ImportRowResult, error) {
+	return parseTable(r, today, columnAliases, []string{"ticker", "quantity", "price"}, parseRow)
+}
+
+// parseTable reads a CSV, locates its header by the required columns and parses every
+// data row after it
+func parseTable(r io.Reader, today string, aliases map[string]string, required []string, parse rowParser) ([]models.ImportedTrade, []models.ImportRowResult, error) {
+	records, err := readCSV(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	header, columns, err := findHeader(records, aliases, required)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var trades []models.ImportedTrade
+	var results []models.ImportRowResult
+	for i := header + 1; i < len(records); i++ {
+		record := records[i]
+		if blankRecord(record) {
+			continue
+		}
+
+		row := i + 1
+		get := func(column string) string {
+			idx, ok := columns[column]
+			if !ok || idx >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[idx])
+		}
+
+		trade, err := parse(row, get, today)
+		var skip *skipRow
+		switch {
+		case errors.As(err, &skip):
+			if skip.reason != "" {
+				results = append(results, models.ImportRowResult{
+					Row:    row,
+					Status: models.ImportRowSkipped,
+					Ticker: get("ticker"),
+					Reason: skip.reason,
+				})
+			}
+		case err != nil:
+			results = append(results, models.ImportRowResult{
+				Row:    row,
+				Status: models.ImportRowError,
+				Ticker: get("ticker"),
+				Error:  err.Error(),
+			})
+		default:
+			trades = append(trades, *trade)
+		}
+	}
+
+	if len(trades) == 0 && len(results) == 0 {
+		return nil, nil, errors.New("CSV contains no trades")
+	}
+	return trades, results, nil
+}
+
+// readCSV reads every record, requiring a header and at least one data row
+func readCSV(r io.Reader) ([][]string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.LazyQuotes = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	if len(records) < 2 {
+		return nil, errors.New("CSV must contain a header row and at least one trade")
+	}
+
+	// Drop a UTF-8 byte order mark left by spreadsheet exports
+	records[0][0] = strings.TrimPrefix(records[0][0], "\ufeff")
+	return records, nil
+}
+
+// findHeader returns the index of the first row that names every required column,
+// together with the position of each recognized column
+func findHeader(records [][]string, aliases map[string]string, required []string) (int, map[string]int, error) {
+	for i := 0; i < len(records) && i < maxPreambleRows; i++ {
+		columns := make(map[string]int)
+		for j, name := range records[i] {
+			if canonical, ok := aliases[headerKey(name)]; ok {
+				if _, dup := columns[canonical]; !dup {
+					columns[canonical] = j
+				}
+			}
+		}
+
+		found := true
+		for _, column := range required {
+			if _, ok := columns[column]; !ok {
+				found = false
+				break
+			}
+		}
+		if found {
+			return i, columns, nil
+		}
+	}
+
+	names := make([]string, len(required))
+	for i, column := range required {
+		names[i] = strconv.Quote(column)
+	}
+	return 0, nil, fmt.Errorf("no header row with the required columns %s", strings.Join(names, ", "))
+}
+
+// headerKey normalizes a header cell for alias lookup: "Fees & Comm" → "fees_&_comm"
+func headerKey(name string) string {
+	key := strings.ToLower(strings.TrimSpace(name))
+	return strings.Join(strings.Fields(key), "_")
+}
+
+// blankRecord reports whether every cell of a record is empty
+func blankRecord(record []string) bool {
+	for _, cell := range record {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// parseRow validates a single data row
+func parseRow(row int, get func(string) string, today string) (*models.ImportedTrade, error) {
+	trade := &models.ImportedTrade{
+		Row:      row,
+		Action:   models.TransactionOpen,
+		TradedAt: today,
+	}
+
+	if raw := strings.ToLower(get("action")); raw != "" {
+		alias, ok := actionAliases[strings.ReplaceAll(raw, " ", "_")]
+		if !ok {
+			return nil, fmt.Errorf("unknown action %q", raw)
+		}
+		trade.Action = alias[0]
+		trade.Side = alias[1]
+	}
+
+	if side := strings.ToLower(get("side")); side != "" {
+		if side != models.SideLong && side != models.SideShort {
+			return nil, fmt.Errorf("side must be long or short")
+		}
+		if trade.Side != "" && trade.Side != side {
+			return nil, fmt.Errorf("side %s conflicts with action %s", side, get("action"))
+		}
+		trade.Side = side
+	}
+	if trade.Action == models.TransactionOpen && trade.Side == "" {
+		return nil, fmt.Errorf("side is required to open a position")
+	}
+
+	var err error
+	if trade.Quantity, err = parseNumber(get("quantity")); err != nil || trade.Quantity <= 0 {
+		return nil, fmt.Errorf("quantity must be a positive number")
+	}
+	if trade.Price, err = parseNumber(get("price")); err != nil || trade.Price < 0 {
+		return nil, fmt.Errorf("price must be a non-negative number")
+	}
+	if raw := get("fees"); raw != "" {
+		if trade.Fees, err = parseNumber(raw); err != nil || trade.Fees < 0 {
+			return nil, fmt.Errorf("fees must be a non-negative number")
+		}
+	}
+	if raw := get("date"); raw != "" {
+		date, err := parseDate(raw)
+		if err != nil {
+			return nil, err
+		}
+		trade.TradedAt = date
+	}
+
+	if err := setInstrument(trade, get("ticker"), strings.ToLower(get("asset_type")), get("underlying")); err != nil {
+		return nil, err
+	}
+
+	if raw := get("multiplier"); raw != "" {
+		m, err := strconv.Atoi(raw)
+		if err != nil || m <= 0 {
+			return nil, fmt.Errorf("multiplier must be a positive integer")
+		}
+		trade.Multiplier = m
+	}
+
+	return trade, nil
+}
+
+// setInstrument resolves the ticker into an option leg (OCC symbol) or share position
+func setInstrument(trade *models.ImportedTrade, ticker, assetType, underlying string) error {
+	if ticker == "" {
+		return fmt.Errorf("ticker is required")
+	}
+
+	symbol, occErr := models.ParseOptionTicker(ticker)
+	switch {
+	case assetType == models.AssetTypeOption || (assetType == "" && occErr == nil):
+		if occErr != nil {
+			return fmt.Errorf("ticker must be an OCC option symbol (e.g. O:AAPL250117C00150000)")
+		}
+		trade.AssetType = models.AssetTypeOption
+		trade.Ticker = symbol.Ticker()
+		trade.UnderlyingTicker = symbol.Underlying
+		trade.ContractType = &symbol.ContractType
+		trade.StrikePrice = &symbol.StrikePrice
+		trade.ExpirationDate = &symbol.ExpirationDate
+		trade.Multiplier = defaultOptionMultiplier
+	case assetType == models.AssetTypeStock || assetType == "":
+		trade.AssetType = models.AssetTypeStock
+		trade.Ticker = strings.ToUpper(ticker)
+		trade.UnderlyingTicker = trade.Ticker
+		trade.Multiplier = 1
+	default:
+		return fmt.Errorf("asset_type must be option or stock")
+	}
+
+	if underlying != "" && !strings.EqualFold(underlying, trade.UnderlyingTicker) {
+		return fmt.Errorf("underlying %s does not match ticker %s", underlying, trade.Ticker)
+	}
+	return nil
+}
+
+// parseNumber parses a number, tolerating currency symbols, thousands separators and
+// accounting-style parentheses for negatives
+func parseNumber(raw string) (float64, error) {
+	s := strings.NewReplacer("$", "", ",", "", " ", "").Replace(raw)
+	negative := strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")")
+	s = strings.Trim(s, "()")
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	if negative {
+		v = -v
+	}
+	return v, nil
+}
+
+// dateLayouts are the accepted date formats, ISO first
+var dateLayouts = []string{"2006-01-02", "01/02/2006", "1/2/2006", "01/02/06", "1/2/06", "20060102"}
+
+// parseDate normalizes a date to YYYY-MM-DD
+func parseDate(raw string) (string, error) {
+	// Broker exports sometimes append a time or an "as of" date
+	if fields := strings.FieldsFunc(raw, func(r rune) bool { return r == ' ' || r == ';' || r == ',' }); len(fields) > 0 {
+		raw = fields[0]
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, raw); err == nil {
+			return t.Format("2006-01-02"), nil
+		}
+	}
+	return "", fmt.Errorf("date %q must be YYYY-MM-DD or MM/DD/YYYY", raw)
+}
@@ -0,0 +1,110 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+)
+
+// FormatFidelity is the Fidelity account history export
+const FormatFidelity = "fidelity"
+
+// fidelityColumns maps Fidelity's history headers to canonical column names
+var fidelityColumns = map[string]string{
+	"run_date":       "date",
+	"date":           "date",
+	"action":         "action",
+	"symbol":         "ticker",
+	"quantity":       "quantity",
+	"price_($)":      "price",
+	"price":          "price",
+	"commission_($)": "commission",
+	"commission":     "commission",
+	"fees_($)":       "fees",
+	"fees":           "fees",
+}
+
+// fidelityOptionPattern matches Fidelity option symbols, e.g. "-AAPL260116P180" or "-SPY260320P512.5"
+var fidelityOptionPattern = regexp.MustCompile(`^-([A-Z0-9.]+?)(\d{6})([CP])(\d+(?:\.\d+)?)$`)
+
+// ParseFidelity reads a Fidelity account history CSV. Fidelity describes each transaction in
+// free text ("YOU SOLD OPENING TRANSACTION PUT (AAPL) ..."); rows that are not trades are skipped.
+func ParseFidelity(r io.Reader, today string) ([]models.ImportedTrade, []models.ImportRowResult, error) {
+	return parseTable(r, today, fidelityColumns, []string{"date", "action", "ticker", "quantity", "price"}, parseFidelityRow)
+}
+
+// parseFidelityRow converts one Fidelity history row
+func parseFidelityRow(row int, get func(string) string, today string) (*models.ImportedTrade, error) {
+	action := get("action")
+	if action == "" {
+		// Disclaimer text at the end of the export fills only the first column
+		return nil, &skipRow{}
+	}
+	act, ok := fidelityAction(strings.ToUpper(action))
+	if !ok || get("quantity") == "" {
+		return nil, &skipRow{reason: fmt.Sprintf("not a trade: %s", action)}
+	}
+
+	trade, err := newBrokerTrade(row, today, act, get("date"), get("quantity"), get("price"), get("commission"), get("fees"))
+	if err != nil {
+		return nil, err
+	}
+
+	symbol := strings.ToUpper(strings.TrimSpace(get("ticker")))
+	if err := setBrokerInstrument(trade, symbol, strings.HasPrefix(symbol, "-"), parseFidelityOption); err != nil {
+		return nil, err
+	}
+	return trade, nil
+}
+
+// fidelityAction classifies Fidelity's transaction description. Option trades state
+// whether they open or close; share buys open (or add to) a long position and sells close
+// one unless marked as a short sale or cover.
+func fidelityAction(action string) (brokerAction, bool) {
+	bought := strings.HasPrefix(action, "YOU BOUGHT") || strings.HasPrefix(action, "REINVESTMENT")
+	sold := strings.HasPrefix(action, "YOU SOLD")
+
+	switch {
+	case strings.HasPrefix(action, "EXPIRED"), strings.HasPrefix(action, "ASSIGNED"), strings.HasPrefix(action, "EXERCISED"):
+		return brokerAction{action: models.TransactionClose, expiry: true}, true
+	case strings.Contains(action, "OPENING TRANSACTION") && bought:
+		return brokerAction{action: models.TransactionOpen, side: models.SideLong}, true
+	case strings.Contains(action, "OPENING TRANSACTION") && sold:
+		return brokerAction{action: models.TransactionOpen, side: models.SideShort}, true
+	case strings.Contains(action, "CLOSING TRANSACTION") && bought:
+		return brokerAction{action: models.TransactionClose, side: models.SideShort}, true
+	case strings.Contains(action, "CLOSING TRANSACTION") && sold:
+		return brokerAction{action: models.TransactionClose, side: models.SideLong}, true
+	case strings.Contains(action, "SHORT SALE") && sold:
+		return brokerAction{action: models.TransactionOpen, side: models.SideShort}, true
+	case strings.Contains(action, "SHORT COVER") && bought:
+		return brokerAction{action: models.TransactionClose, side: models.SideShort}, true
+	case bought:
+		return brokerAction{action: models.TransactionOpen, side: models.SideLong}, true
+	case sold:
+		return brokerAction{action: models.TransactionClose, side: models.SideLong}, true
+	}
+	return brokerAction{}, false
+}
+
+// parseFidelityOption decodes a Fidelity option symbol
+func parseFidelityOption(symbol string) (*models.OptionSymbol, error) {
+	m := fidelityOptionPattern.FindStringSubmatch(symbol)
+	if m == nil {
+		return nil, fmt.Errorf("invalid Fidelity option symbol %q", symbol)
+	}
+	exp, err := time.Parse("060102", m[2])
+	if err != nil {
+		return nil, err
+	}
+	strike, err := strconv.ParseFloat(m[4], 64)
+	if err != nil {
+		return nil, err
+	}
+	return newOptionSymbol(m[1], exp, m[3], strike), nil
+}
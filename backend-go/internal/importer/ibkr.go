@@ -0,0 +1,108 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+)
+
+// FormatIBKR is the Interactive Brokers Flex Query trades export
+const FormatIBKR = "ibkr"
+
+// ibkrColumns maps Flex Query trade fields to canonical column names
+var ibkrColumns = map[string]string{
+	"symbol":               "ticker",
+	"assetclass":           "asset_class",
+	"asset_class":          "asset_class",
+	"buy/sell":             "buy_sell",
+	"open/closeindicator":  "open_close",
+	"open/close_indicator": "open_close",
+	"quantity":             "quantity",
+	"tradeprice":           "price",
+	"trade_price":          "price",
+	"ibcommission":         "fees",
+	"ib_commission":        "fees",
+	"tradedate":            "date",
+	"trade_date":           "date",
+	"multiplier":           "multiplier",
+	"levelofdetail":        "level_of_detail",
+	"level_of_detail":      "level_of_detail",
+}
+
+// ParseIBKR reads an Interactive Brokers Flex Query trades CSV. The query must include the
+// Symbol, Asset Class, Buy/Sell, Open/Close Indicator, Quantity, Trade Price and Trade Date
+// fields; IB Commission and Multiplier are used when present. Option symbols are OCC
+// symbols padded with spaces, e.g. "AAPL  260116P00180000".
+func ParseIBKR(r io.Reader, today string) ([]models.ImportedTrade, []models.ImportRowResult, error) {
+	required := []string{"ticker", "asset_class", "buy_sell", "open_close", "quantity", "price", "date"}
+	return parseTable(r, today, ibkrColumns, required, parseIBKRRow)
+}
+
+// parseIBKRRow converts one Flex Query trade row
+func parseIBKRRow(row int, get func(string) string, today string) (*models.ImportedTrade, error) {
+	// Multi-account queries repeat the header, and order-level rows duplicate executions
+	if strings.EqualFold(get("ticker"), "symbol") {
+		return nil, &skipRow{}
+	}
+	if level := strings.ToUpper(get("level_of_detail")); level != "" && level != "EXECUTION" {
+		return nil, &skipRow{}
+	}
+
+	var option bool
+	switch assetClass := strings.ToUpper(get("asset_class")); assetClass {
+	case "OPT":
+		option = true
+	case "STK":
+	default:
+		return nil, &skipRow{reason: fmt.Sprintf("unsupported asset class %s", assetClass)}
+	}
+
+	buy := strings.EqualFold(get("buy_sell"), "BUY")
+	if !buy && !strings.EqualFold(get("buy_sell"), "SELL") {
+		return nil, fmt.Errorf("buy/sell must be BUY or SELL")
+	}
+
+	var act brokerAction
+	switch strings.ToUpper(get("open_close")) {
+	case "O":
+		act = brokerAction{action: models.TransactionOpen, side: models.SideShort}
+		if buy {
+			act.side = models.SideLong
+		}
+	case "C":
+		// Expirations, assignments and exercises are reported as closing trades at zero
+		act = brokerAction{action: models.TransactionClose, side: models.SideLong}
+		if buy {
+			act.side = models.SideShort
+		}
+	case "C;O", "O;C":
+		return nil, fmt.Errorf("fills that close and reverse a position are not supported; split them into a close and an open")
+	default:
+		return nil, fmt.Errorf("open/close indicator must be O or C")
+	}
+
+	trade, err := newBrokerTrade(row, today, act, get("date"), get("quantity"), get("price"), get("fees"))
+	if err != nil {
+		return nil, err
+	}
+
+	symbol := strings.ToUpper(get("ticker"))
+	parseOCC := func(s string) (*models.OptionSymbol, error) {
+		return models.ParseOptionTicker(strings.ReplaceAll(s, " ", ""))
+	}
+	if err := setBrokerInstrument(trade, symbol, option, parseOCC); err != nil {
+		return nil, err
+	}
+
+	if raw := get("multiplier"); raw != "" {
+		m, err := strconv.ParseFloat(raw, 64)
+		if err != nil || m <= 0 || m != float64(int(m)) {
+			return nil, fmt.Errorf("multiplier must be a positive integer")
+		}
+		trade.Multiplier = int(m)
+	}
+	return trade, nil
+}
@@ -0,0 +1,41 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+)
+
+// Parser reads an import file into trades and per-row results for rows that failed
+// validation or were skipped.
+// today is the YYYY-MM-DD date used for rows without a trade date.
+type Parser func(r io.Reader, today string) ([]models.ImportedTrade, []models.ImportRowResult, error)
+
+// parsers maps the format query parameter to its parser
+var parsers = map[string]Parser{
+	FormatPeriscope: ParseCSV,
+	FormatSchwab:    ParseSchwab,
+	FormatFidelity:  ParseFidelity,
+	FormatIBKR:      ParseIBKR,
+}
+
+// Formats returns the supported import formats, sorted
+func Formats() []string {
+	formats := make([]string, 0, len(parsers))
+	for name := range parsers {
+		formats = append(formats, name)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// Parse reads r using the named format
+func Parse(format string, r io.Reader, today string) ([]models.ImportedTrade, []models.ImportRowResult, error) {
+	parse, ok := parsers[format]
+	if !ok {
+		return nil, nil, fmt.Errorf("unsupported format %q", format)
+	}
+	return parse(r, today)
+}
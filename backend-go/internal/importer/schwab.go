@@ -0,0 +1,108 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+)
+
+// FormatSchwab is the Charles Schwab transaction history export
+const FormatSchwab = "schwab"
+
+// schwabColumns maps Schwab's history headers to canonical column names
+var schwabColumns = map[string]string{
+	"date":        "date",
+	"action":      "action",
+	"symbol":      "ticker",
+	"description": "description",
+	"quantity":    "quantity",
+	"price":       "price",
+	"fees_&_comm": "fees",
+}
+
+// schwabActions maps Schwab transaction actions to ledger actions. Stock buys and sells
+// do not say whether they open or close, so buys open (or add to) a long position and
+// sells close one; short sales use the explicit short actions.
+var schwabActions = map[string]brokerAction{
+	"buy to open":          {action: models.TransactionOpen, side: models.SideLong},
+	"sell to open":         {action: models.TransactionOpen, side: models.SideShort},
+	"buy to close":         {action: models.TransactionClose, side: models.SideShort},
+	"sell to close":        {action: models.TransactionClose, side: models.SideLong},
+	"buy":                  {action: models.TransactionOpen, side: models.SideLong},
+	"reinvest shares":      {action: models.TransactionOpen, side: models.SideLong},
+	"sell":                 {action: models.TransactionClose, side: models.SideLong},
+	"sell short":           {action: models.TransactionOpen, side: models.SideShort},
+	"buy to cover":         {action: models.TransactionClose, side: models.SideShort},
+	"expired":              {action: models.TransactionClose, expiry: true},
+	"assigned":             {action: models.TransactionClose, expiry: true},
+	"exchange or exercise": {action: models.TransactionClose, expiry: true},
+}
+
+// schwabOptionPattern matches Schwab option symbols, e.g. "AAPL 01/16/2026 180.00 P"
+var schwabOptionPattern = regexp.MustCompile(`^([A-Z0-9./]+)\s+(\d{2}/\d{2}/\d{4})\s+(\d+(?:\.\d+)?)\s+([CP])$`)
+
+// ParseSchwab reads a Schwab transaction history CSV. Non-trade rows such as dividends,
+// interest and transfers are skipped.
+func ParseSchwab(r io.Reader, today string) ([]models.ImportedTrade, []models.ImportRowResult, error) {
+	return parseTable(r, today, schwabColumns, []string{"date", "action", "ticker", "quantity", "price"}, parseSchwabRow)
+}
+
+// parseSchwabRow converts one Schwab history row
+func parseSchwabRow(row int, get func(string) string, today string) (*models.ImportedTrade, error) {
+	action := get("action")
+	if action == "" {
+		// The export ends with a "Transactions Total" line
+		return nil, &skipRow{}
+	}
+	act, ok := schwabActions[strings.ToLower(action)]
+	if !ok {
+		return nil, &skipRow{reason: fmt.Sprintf("not a trade: %s", action)}
+	}
+
+	trade, err := newBrokerTrade(row, today, act, get("date"), get("quantity"), get("price"), get("fees"))
+	if err != nil {
+		return nil, err
+	}
+
+	symbol := strings.ToUpper(get("ticker"))
+	if err := setBrokerInstrument(trade, symbol, schwabOptionPattern.MatchString(symbol), parseSchwabOption); err != nil {
+		return nil, err
+	}
+	return trade, nil
+}
+
+// parseSchwabOption decodes a Schwab option symbol
+func parseSchwabOption(symbol string) (*models.OptionSymbol, error) {
+	m := schwabOptionPattern.FindStringSubmatch(symbol)
+	if m == nil {
+		return nil, fmt.Errorf("invalid Schwab option symbol %q", symbol)
+	}
+	exp, err := time.Parse("01/02/2006", m[2])
+	if err != nil {
+		return nil, err
+	}
+	strike, err := strconv.ParseFloat(m[3], 64)
+	if err != nil {
+		return nil, err
+	}
+	return newOptionSymbol(m[1], exp, m[4], strike), nil
+}
+
+// newOptionSymbol builds an option symbol from its parts; cp is "C" or "P"
+func newOptionSymbol(underlying string, expiration time.Time, cp string, strike float64) *models.OptionSymbol {
+	contractType := "call"
+	if cp == "P" {
+		contractType = "put"
+	}
+	return &models.OptionSymbol{
+		Underlying:     strings.ReplaceAll(underlying, "/", "."),
+		ExpirationDate: expiration.Format("2006-01-02"),
+		ContractType:   contractType,
+		StrikePrice:    strike,
+	}
+}
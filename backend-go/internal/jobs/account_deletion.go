@@ -0,0 +1,32 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/services"
+)
+
+// accountDeletionInterval is how often due account deletions are carried out
+const accountDeletionInterval = time.Hour
+
+// AccountDeletionJob purges the accounts whose deletion grace period is over
+type AccountDeletionJob struct {
+	deletions *services.AccountDeletionService
+}
+
+// NewAccountDeletionJob creates a new account deletion job
+func NewAccountDeletionJob(deletions *services.AccountDeletionService) *AccountDeletionJob {
+	return &AccountDeletionJob{deletions: deletions}
+}
+
+// Start purges due accounts every hour until ctx is cancelled
+func (j *AccountDeletionJob) Start(ctx context.Context) {
+	runEvery(ctx, "AccountDeletionJob", accountDeletionInterval, j.Run)
+}
+
+// Run purges every account whose deletion is due
+func (j *AccountDeletionJob) Run(ctx context.Context) error {
+	_, err := j.deletions.PurgeDue(ctx, time.Now())
+	return err
+}
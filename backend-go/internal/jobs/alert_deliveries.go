@@ -0,0 +1,29 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/services"
+)
+
+// AlertDeliveryJob sends queued alert notifications and their retries around the clock,
+// as often as webhook deliveries
+type AlertDeliveryJob struct {
+	deliveries *services.AlertDeliveryService
+}
+
+// NewAlertDeliveryJob creates a new alert delivery job
+func NewAlertDeliveryJob(deliveries *services.AlertDeliveryService) *AlertDeliveryJob {
+	return &AlertDeliveryJob{deliveries: deliveries}
+}
+
+// Start delivers due alert notifications every interval until ctx is cancelled
+func (j *AlertDeliveryJob) Start(ctx context.Context) {
+	runEvery(ctx, "AlertDeliveryJob", webhookInterval, j.Run)
+}
+
+// Run sends every delivery that is due
+func (j *AlertDeliveryJob) Run(ctx context.Context) error {
+	_, err := j.deliveries.DeliverDue(ctx)
+	return err
+}
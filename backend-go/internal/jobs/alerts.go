@@ -0,0 +1,32 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/alerts"
+)
+
+// alertInterval is how often armed alerts are evaluated during market hours
+const alertInterval = time.Minute
+
+// AlertJob runs the alert engine during market hours, evaluating market alerts and
+// position alerts against live snapshots
+type AlertJob struct {
+	engine *alerts.Engine
+}
+
+// NewAlertJob creates a new alert job
+func NewAlertJob(engine *alerts.Engine) *AlertJob {
+	return &AlertJob{engine: engine}
+}
+
+// Start evaluates alerts every minute while the market is open until ctx is cancelled
+func (j *AlertJob) Start(ctx context.Context) {
+	runDuringMarketHours(ctx, "AlertJob", alertInterval, j.Run)
+}
+
+// Run evaluates every armed alert once
+func (j *AlertJob) Run(ctx context.Context) error {
+	return j.engine.Run(ctx)
+}
@@ -0,0 +1,35 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/services"
+)
+
+// The backup job runs after the retention job, once the day's data is in and pruned
+const (
+	backupHour   = 21
+	backupMinute = 0
+)
+
+// BackupJob backs up Periscope's tables to object storage every trading day
+type BackupJob struct {
+	backups *services.BackupService
+}
+
+// NewBackupJob creates a new backup job
+func NewBackupJob(backups *services.BackupService) *BackupJob {
+	return &BackupJob{backups: backups}
+}
+
+// Start takes a backup every trading day in the evening until ctx is cancelled
+func (j *BackupJob) Start(ctx context.Context) {
+	runDaily(ctx, "BackupJob", backupHour, backupMinute, j.Run)
+}
+
+// Run takes a backup
+func (j *BackupJob) Run(ctx context.Context, date string) error {
+	_, err := j.backups.Run(ctx, time.Now())
+	return err
+}
@@ -0,0 +1,30 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/services"
+)
+
+// DemoJob refreshes the demo's delayed chains around the clock, so demo requests never call
+// Massive themselves
+type DemoJob struct {
+	demo     *services.DemoService
+	interval time.Duration
+}
+
+// NewDemoJob creates a new demo refresh job running every interval
+func NewDemoJob(demo *services.DemoService, interval time.Duration) *DemoJob {
+	return &DemoJob{demo: demo, interval: interval}
+}
+
+// Start refreshes the demo chains now and then every interval until ctx is cancelled
+func (j *DemoJob) Start(ctx context.Context) {
+	runEvery(ctx, "DemoJob", j.interval, j.Run)
+}
+
+// Run refreshes every demo chain once
+func (j *DemoJob) Run(ctx context.Context) error {
+	return j.demo.Refresh(ctx)
+}
@@ -0,0 +1,35 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/services"
+)
+
+// dividendHour and dividendMinute set when dividends are synced, in exchange time: after
+// the close and before the daily snapshot so the day's payments are in its P/L
+const (
+	dividendHour   = 16
+	dividendMinute = 20
+)
+
+// DividendJob records the dividends earned by share positions each trading day and marks
+// announced dividends paid once their pay date arrives
+type DividendJob struct {
+	dividends *services.DividendService
+}
+
+// NewDividendJob creates a new daily dividend job
+func NewDividendJob(dividends *services.DividendService) *DividendJob {
+	return &DividendJob{dividends: dividends}
+}
+
+// Start runs the job after the close each trading day until ctx is cancelled
+func (j *DividendJob) Start(ctx context.Context) {
+	runDaily(ctx, "DividendJob", dividendHour, dividendMinute, j.Run)
+}
+
+// Run syncs dividends as of the given YYYY-MM-DD date
+func (j *DividendJob) Run(ctx context.Context, date string) error {
+	return j.dividends.Sync(ctx, date)
+}
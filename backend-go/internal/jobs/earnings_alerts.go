@@ -0,0 +1,35 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/alerts"
+)
+
+// earningsAlertHour and earningsAlertMinute set when earnings alerts are synced, in
+// exchange time: before the open so the day's first alert run sees the current holdings
+const (
+	earningsAlertHour   = 8
+	earningsAlertMinute = 0
+)
+
+// EarningsAlertJob re-syncs every user's managed earnings alerts each trading day, covering
+// positions opened and tickers watched since the last sync
+type EarningsAlertJob struct {
+	sync *alerts.EarningsSync
+}
+
+// NewEarningsAlertJob creates a new daily earnings alert job
+func NewEarningsAlertJob(sync *alerts.EarningsSync) *EarningsAlertJob {
+	return &EarningsAlertJob{sync: sync}
+}
+
+// Start runs the job before the open each trading day until ctx is cancelled
+func (j *EarningsAlertJob) Start(ctx context.Context) {
+	runDaily(ctx, "EarningsAlertJob", earningsAlertHour, earningsAlertMinute, j.Run)
+}
+
+// Run syncs the earnings alerts of every user with enabled settings
+func (j *EarningsAlertJob) Run(ctx context.Context, _ string) error {
+	return j.sync.SyncAll(ctx)
+}
@@ -0,0 +1,101 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/analytics"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/massive"
+)
+
+// expirationHour and expirationMinute set when expired legs are settled, in exchange time:
+// after the close that determines moneyness and before the daily snapshot
+const (
+	expirationHour   = 16
+	expirationMinute = 15
+)
+
+// ExpirationJob settles option legs once they expire: out-of-the-money legs expire
+// worthless, in-the-money legs are assigned or exercised and the resulting shares booked
+// in the ledger
+type ExpirationJob struct {
+	positions     *repository.PositionRepository
+	transactions  *repository.TransactionRepository
+	massiveClient *massive.Client
+}
+
+// NewExpirationJob creates a new expiration job
+func NewExpirationJob(positions *repository.PositionRepository, transactions *repository.TransactionRepository, massiveClient *massive.Client) *ExpirationJob {
+	return &ExpirationJob{
+		positions:     positions,
+		transactions:  transactions,
+		massiveClient: massiveClient,
+	}
+}
+
+// Start runs the job after the close each trading day until ctx is cancelled
+func (j *ExpirationJob) Start(ctx context.Context) {
+	runDaily(ctx, "ExpirationJob", expirationHour, expirationMinute, j.Run)
+}
+
+// Run settles every open option leg expiring on or before the given YYYY-MM-DD date.
+// Legs whose underlying close is not available yet are left open for the next run.
+func (j *ExpirationJob) Run(ctx context.Context, date string) error {
+	legs, err := j.positions.ListExpired(ctx, date)
+	if err != nil {
+		return err
+	}
+	if len(legs) == 0 {
+		return nil
+	}
+
+	closes := make(map[string]*float64)
+	settled := 0
+	for i := range legs {
+		p := &legs[i]
+
+		key := p.UnderlyingTicker + "@" + *p.ExpirationDate
+		if _, ok := closes[key]; !ok {
+			price, err := j.closingPrice(ctx, p.UnderlyingTicker, *p.ExpirationDate)
+			if err != nil {
+				log.Printf("[ExpirationJob] ⚠ No close for %s on %s: %v", p.UnderlyingTicker, *p.ExpirationDate, err)
+			}
+			closes[key] = price
+		}
+		underlyingClose := closes[key]
+		if underlyingClose == nil {
+			continue
+		}
+
+		expiration, err := j.transactions.Expire(ctx, p.PortfolioID, p.ID, *underlyingClose)
+		if err != nil {
+			log.Printf("[ExpirationJob] ✗ Failed to settle position %d (%s): %v", p.ID, p.Ticker, err)
+			continue
+		}
+		settled++
+		log.Printf("[ExpirationJob] ✓ Position %d (%s) %s with %s at %.2f, %d share deliveries",
+			p.ID, p.Ticker, *expiration.Position.CloseReason, p.UnderlyingTicker, *underlyingClose, len(expiration.Deliveries))
+	}
+
+	log.Printf("[ExpirationJob] ✓ Settled %d of %d expired legs through %s", settled, len(legs), date)
+	return nil
+}
+
+// closingPrice returns the underlying's closing price on the given date
+func (j *ExpirationJob) closingPrice(ctx context.Context, ticker, date string) (*float64, error) {
+	day, err := analytics.ParseDate(date)
+	if err != nil {
+		return nil, err
+	}
+	bars, err := j.massiveClient.GetDailyBars(ctx, ticker, day, day)
+	if err != nil {
+		return nil, err
+	}
+	if len(bars) == 0 || bars[len(bars)-1].Close <= 0 {
+		return nil, fmt.Errorf("no daily bar")
+	}
+	price := bars[len(bars)-1].Close
+	return &price, nil
+}
@@ -0,0 +1,31 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/services"
+)
+
+// OptionsSnapshotJob captures the chains of a fixed list of tickers into the snapshot
+// history every interval while the market is open
+type OptionsSnapshotJob struct {
+	snapshots *services.OptionsSnapshotService
+	tickers   []string
+	interval  time.Duration
+}
+
+// NewOptionsSnapshotJob creates a new options snapshot job
+func NewOptionsSnapshotJob(snapshots *services.OptionsSnapshotService, tickers []string, interval time.Duration) *OptionsSnapshotJob {
+	return &OptionsSnapshotJob{snapshots: snapshots, tickers: tickers, interval: interval}
+}
+
+// Start captures the chains every interval during market hours until ctx is cancelled
+func (j *OptionsSnapshotJob) Start(ctx context.Context) {
+	runDuringMarketHours(ctx, "OptionsSnapshotJob", j.interval, j.Run)
+}
+
+// Run captures every ticker's chain once
+func (j *OptionsSnapshotJob) Run(ctx context.Context) error {
+	return j.snapshots.CaptureAll(ctx, j.tickers)
+}
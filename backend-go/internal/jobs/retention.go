@@ -0,0 +1,139 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/analytics"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+)
+
+// The retention job runs after the evening jobs, once the day's captures are in
+const (
+	retentionHour   = 20
+	retentionMinute = 0
+)
+
+// retentionBatch is how many chain captures are deleted per statement; on plain Postgres
+// each takes its contracts, thousands of rows, with it
+const retentionBatch = 50
+
+// RetentionJob keeps the chain snapshot history within its retention: every capture for
+// the most recent intraday days, then only each trading day's last capture (a daily rollup)
+// up to the daily retention, and nothing older. With TimescaleDB, contracts are pruned a
+// chunk at a time: the rollups are copied out of the intraday chunks before those are
+// dropped, and rollup chunks dropped in turn, so no rows are deleted out of compressed
+// chunks. On plain Postgres captures are deleted with their contracts in batches.
+type RetentionJob struct {
+	snapshots    *repository.OptionsSnapshotRepository
+	intradayDays int
+	dailyDays    int
+}
+
+// NewRetentionJob creates a new retention job
+func NewRetentionJob(snapshots *repository.OptionsSnapshotRepository, intradayDays, dailyDays int) *RetentionJob {
+	return &RetentionJob{snapshots: snapshots, intradayDays: intradayDays, dailyDays: dailyDays}
+}
+
+// Start prunes the history every trading day after the close until ctx is cancelled
+func (j *RetentionJob) Start(ctx context.Context) {
+	runDaily(ctx, "RetentionJob", retentionHour, retentionMinute, j.Run)
+}
+
+// Run rolls up captures older than the intraday retention and deletes those older than the
+// daily retention, counted in calendar days back from date
+func (j *RetentionJob) Run(ctx context.Context, date string) error {
+	today, err := analytics.ParseDate(date)
+	if err != nil {
+		return err
+	}
+	intradayBefore := analytics.MarketDayEnd(today.AddDate(0, 0, -j.intradayDays-1))
+	dailyBefore := analytics.MarketDayEnd(today.AddDate(0, 0, -j.dailyDays-1))
+
+	chunked, err := j.snapshots.Hypertables(ctx)
+	if err != nil {
+		return err
+	}
+	if chunked {
+		return j.runChunked(ctx, intradayBefore, dailyBefore)
+	}
+
+	deleted, err := prune(ctx, func(ctx context.Context) (int64, error) {
+		return j.snapshots.DeleteBefore(ctx, dailyBefore, retentionBatch)
+	})
+	if err != nil {
+		return err
+	}
+	rolledUp, err := prune(ctx, func(ctx context.Context) (int64, error) {
+		return j.snapshots.RollUpBefore(ctx, intradayBefore, retentionBatch)
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[RetentionJob] ✓ Rolled up %d intraday captures before %s, deleted %d captures before %s",
+		rolledUp, intradayBefore.Format(time.DateOnly), deleted, dailyBefore.Format(time.DateOnly))
+	return nil
+}
+
+// runChunked prunes hypertables: the last captures of each day are copied out of the
+// intraday chunks that have ended before those chunks are dropped, then the rollup chunks
+// past the daily retention are dropped. Captures are removed up to the chunk boundaries, so
+// the ones whose contracts are still stored stay readable.
+func (j *RetentionJob) runChunked(ctx context.Context, intradayBefore, dailyBefore time.Time) error {
+	intradayCutoff, copied, err := j.snapshots.RollUpChunksBefore(ctx, intradayBefore)
+	if err != nil {
+		return err
+	}
+	var rolledUp int64
+	if !intradayCutoff.IsZero() {
+		rolledUp, err = prune(ctx, func(ctx context.Context) (int64, error) {
+			return j.snapshots.RollUpBefore(ctx, intradayCutoff, retentionBatch)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	dailyCutoff, err := j.snapshots.DropDailyChunksBefore(ctx, dailyBefore)
+	if err != nil {
+		return err
+	}
+	var deleted int64
+	if !dailyCutoff.IsZero() {
+		deleted, err = prune(ctx, func(ctx context.Context) (int64, error) {
+			return j.snapshots.DeleteBefore(ctx, dailyCutoff, retentionBatch)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Printf("[RetentionJob] ✓ Rolled up %d intraday captures (%d contracts kept) in chunks before %s, deleted %d captures in chunks before %s",
+		rolledUp, copied, cutoffDate(intradayCutoff), deleted, cutoffDate(dailyCutoff))
+	return nil
+}
+
+// cutoffDate formats a chunk boundary for the log, "none" when no chunk had ended
+func cutoffDate(t time.Time) string {
+	if t.IsZero() {
+		return "none"
+	}
+	return t.Format(time.DateOnly)
+}
+
+// prune calls batch until it deletes less than a full batch, returning the total deleted
+func prune(ctx context.Context, batch func(ctx context.Context) (int64, error)) (int64, error) {
+	var total int64
+	for {
+		n, err := batch(ctx)
+		total += n
+		if err != nil || n < retentionBatch {
+			return total, err
+		}
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/analytics"
+)
+
+// runTimeout bounds a single run of a daily job
+const runTimeout = 10 * time.Minute
+
+// runDaily calls run with the trading date (YYYY-MM-DD) at hour:minute exchange time on
+// every trading day until ctx is cancelled. When started after that time on a trading day
+// it runs immediately so a restart does not skip the day.
+func runDaily(ctx context.Context, name string, hour, minute int, run func(ctx context.Context, date string) error) {
+	now := time.Now()
+	if isTradingDay(now) && !now.Before(runAt(now, hour, minute)) {
+		runOnce(ctx, name, now, run)
+	}
+
+	for {
+		next := nextRun(time.Now(), hour, minute)
+		log.Printf("[%s] Next run at %s", name, next.Format(time.RFC3339))
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			runOnce(ctx, name, next, run)
+		}
+	}
+}
+
+// runDuringMarketHours calls run every interval while the regular session is open on a
+// trading day, until ctx is cancelled
+func runDuringMarketHours(ctx context.Context, name string, interval time.Duration, run func(ctx context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if now := time.Now(); isTradingDay(now) && marketOpen(now) {
+			runCtx, cancel := context.WithTimeout(ctx, interval)
+			if err := run(runCtx); err != nil {
+				log.Printf("[%s] ✗ Run failed: %v", name, err)
+			}
+			cancel()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runEvery calls run every interval, regardless of market hours, until ctx is cancelled.
+// A run may take up to runTimeout.
+func runEvery(ctx context.Context, name string, interval time.Duration, run func(ctx context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		runCtx, cancel := context.WithTimeout(ctx, runTimeout)
+		if err := run(runCtx); err != nil {
+			log.Printf("[%s] ✗ Run failed: %v", name, err)
+		}
+		cancel()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runOnce runs a job for the trading day containing at, logging any failure
+func runOnce(ctx context.Context, name string, at time.Time, run func(ctx context.Context, date string) error) {
+	ctx, cancel := context.WithTimeout(ctx, runTimeout)
+	defer cancel()
+
+	if err := run(ctx, analytics.MarketDate(at).Format("2006-01-02")); err != nil {
+		log.Printf("[%s] ✗ Run failed: %v", name, err)
+	}
+}
+
+// runAt returns hour:minute exchange time on t's trading date
+func runAt(t time.Time, hour, minute int) time.Time {
+	m := analytics.MarketTime(t)
+	return time.Date(m.Year(), m.Month(), m.Day(), hour, minute, 0, 0, m.Location())
+}
+
+// nextRun returns the first hour:minute exchange time after now on a trading day
+func nextRun(now time.Time, hour, minute int) time.Time {
+	next := runAt(now, hour, minute)
+	for !next.After(now) || !isTradingDay(next) {
+		next = runAt(analytics.MarketTime(next).AddDate(0, 0, 1), hour, minute)
+	}
+	return next
+}
+
+// marketOpen reports whether t falls within the 9:30 AM to 4:00 PM regular session in
+// exchange time
+func marketOpen(t time.Time) bool {
+	return !t.Before(runAt(t, 9, 30)) && t.Before(runAt(t, 16, 0))
+}
+
+// isTradingDay reports whether t falls on a weekday in exchange time. Exchange holidays
+// are not tracked; jobs that run on a holiday see the previous close.
+func isTradingDay(t time.Time) bool {
+	switch analytics.MarketTime(t).Weekday() {
+	case time.Saturday, time.Sunday:
+		return false
+	}
+	return true
+}
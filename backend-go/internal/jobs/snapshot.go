@@ -0,0 +1,68 @@
+package jobs
+
+import (
+	"context"
+	"log"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/aaronbengochea/periscope/backend-go/internal/services"
+)
+
+// snapshotHour and snapshotMinute set when the daily snapshot runs, in exchange time:
+// late enough after the 4:00 PM close for snapshots to carry closing prices and to follow
+// the expiration job
+const (
+	snapshotHour   = 16
+	snapshotMinute = 30
+)
+
+// SnapshotJob values every portfolio after the market close each trading day and stores
+// its equity, P/L and greeks in the snapshot history
+type SnapshotJob struct {
+	portfolios repository.PortfolioRepo
+	snapshots  repository.SnapshotRepo
+	valuation  *services.ValuationService
+}
+
+// NewSnapshotJob creates a new daily snapshot job
+func NewSnapshotJob(portfolios repository.PortfolioRepo, snapshots repository.SnapshotRepo, valuation *services.ValuationService) *SnapshotJob {
+	return &SnapshotJob{
+		portfolios: portfolios,
+		snapshots:  snapshots,
+		valuation:  valuation,
+	}
+}
+
+// Start runs the job after the close each trading day until ctx is cancelled
+func (j *SnapshotJob) Start(ctx context.Context) {
+	runDaily(ctx, "SnapshotJob", snapshotHour, snapshotMinute, j.Run)
+}
+
+// Run values every portfolio and stores its snapshot for the given YYYY-MM-DD date,
+// replacing any existing snapshot for that day
+func (j *SnapshotJob) Run(ctx context.Context, date string) error {
+	portfolios, err := j.portfolios.ListAll(ctx)
+	if err != nil {
+		return err
+	}
+	if len(portfolios) == 0 {
+		return nil
+	}
+
+	snapshots, err := j.valuation.Snapshots(ctx, portfolios, date)
+	if err != nil {
+		return err
+	}
+
+	stored := 0
+	for i := range snapshots {
+		if err := j.snapshots.Upsert(ctx, &snapshots[i]); err != nil {
+			log.Printf("[SnapshotJob] ⚠ Failed to store snapshot for portfolio %d: %v", snapshots[i].PortfolioID, err)
+			continue
+		}
+		stored++
+	}
+
+	log.Printf("[SnapshotJob] ✓ Stored %d of %d portfolio snapshots for %s", stored, len(snapshots), date)
+	return nil
+}
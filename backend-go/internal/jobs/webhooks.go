@@ -0,0 +1,32 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/services"
+)
+
+// webhookInterval is how often queued webhook deliveries are sent
+const webhookInterval = 15 * time.Second
+
+// WebhookJob sends queued webhook deliveries and their retries around the clock
+type WebhookJob struct {
+	webhooks *services.WebhookService
+}
+
+// NewWebhookJob creates a new webhook delivery job
+func NewWebhookJob(webhooks *services.WebhookService) *WebhookJob {
+	return &WebhookJob{webhooks: webhooks}
+}
+
+// Start delivers due webhooks every interval until ctx is cancelled
+func (j *WebhookJob) Start(ctx context.Context) {
+	runEvery(ctx, "WebhookJob", webhookInterval, j.Run)
+}
+
+// Run sends every delivery that is due
+func (j *WebhookJob) Run(ctx context.Context) error {
+	_, err := j.webhooks.DeliverDue(ctx)
+	return err
+}
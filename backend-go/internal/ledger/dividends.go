@@ -0,0 +1,24 @@
+package ledger
+
+// QuantityChange is a dated change in a position's quantity: positive when a lot opens,
+// negative when a closing transaction consumes one
+type QuantityChange struct {
+	Date     string // YYYY-MM-DD
+	Quantity float64
+}
+
+// HeldAt returns the quantity held going into the given YYYY-MM-DD date, which is what
+// earns a dividend with that ex-dividend date: shares bought before the ex-date receive it
+// and shares sold on or after the ex-date keep it
+func HeldAt(changes []QuantityChange, date string) float64 {
+	var held float64
+	for _, c := range changes {
+		if c.Date < date {
+			held += c.Quantity
+		}
+	}
+	if held < epsilon {
+		return 0
+	}
+	return held
+}
@@ -0,0 +1,40 @@
+package ledger
+
+import "github.com/aaronbengochea/periscope/backend-go/internal/models"
+
+// exerciseThreshold is how far in the money an option must finish to be exercised
+// automatically, following the OCC's exercise-by-exception rule
+const exerciseThreshold = 0.01
+
+// Settlement is the outcome of an option leg at expiration
+type Settlement struct {
+	Action      string // expire, assign or exercise
+	CloseReason string // expired, assigned or exercised
+	DeliverSide string // side of the share trade delivered (long = shares bought), empty when none
+}
+
+// Settle decides what happens to an option leg that expired with the underlying at
+// underlyingClose. Out-of-the-money legs expire worthless; in-the-money long legs are
+// exercised and short legs assigned, delivering shares at the strike.
+func Settle(contractType, side string, strike, underlyingClose float64) Settlement {
+	var intrinsic float64
+	if contractType == "call" {
+		intrinsic = underlyingClose - strike
+	} else {
+		intrinsic = strike - underlyingClose
+	}
+	if intrinsic < exerciseThreshold {
+		return Settlement{Action: models.TransactionExpire, CloseReason: models.CloseReasonExpired}
+	}
+
+	// Long calls and short puts buy shares; long puts and short calls sell them
+	deliver := models.SideShort
+	if (contractType == "call") == (side == models.SideLong) {
+		deliver = models.SideLong
+	}
+
+	if side == models.SideLong {
+		return Settlement{Action: models.TransactionExercise, CloseReason: models.CloseReasonExercised, DeliverSide: deliver}
+	}
+	return Settlement{Action: models.TransactionAssign, CloseReason: models.CloseReasonAssigned, DeliverSide: deliver}
+}
@@ -0,0 +1,111 @@
+package ledger
+
+import (
+	"errors"
+	"math"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+)
+
+// epsilon absorbs floating point error when comparing quantities
+const epsilon = 1e-9
+
+// ErrInsufficientQuantity is returned when a close exceeds the open quantity
+var ErrInsufficientQuantity = errors.New("close quantity exceeds open quantity")
+
+// Fill is the portion of a lot consumed by a closing trade
+type Fill struct {
+	Lot         *models.Lot
+	Quantity    float64
+	OpenValue   float64 // opening cash of the closed quantity, fees included
+	CloseValue  float64 // closing cash of the closed quantity, fees included
+	RealizedPnL float64
+}
+
+// Amount returns the signed cash flow of a trade net of fees: negative when paid
+// (buying to open or close), positive when received (selling to open or close)
+func Amount(side string, closing bool, quantity, price, fees float64, multiplier int) float64 {
+	gross := quantity * price * float64(multiplier)
+	if (side == models.SideLong) == closing {
+		return gross - fees
+	}
+	return -gross - fees
+}
+
+// OpenQuantity returns the quantity still open across lots
+func OpenQuantity(lots []models.Lot) float64 {
+	var total float64
+	for _, lot := range lots {
+		total += lot.RemainingQuantity
+	}
+	return total
+}
+
+// AveragePrice returns the quantity-weighted price of the open lots, or zero when none remain
+func AveragePrice(lots []models.Lot) float64 {
+	var qty, value float64
+	for _, lot := range lots {
+		qty += lot.RemainingQuantity
+		value += lot.RemainingQuantity * lot.Price
+	}
+	if qty < epsilon {
+		return 0
+	}
+	return value / qty
+}
+
+// CloseFIFO consumes quantity from lots oldest first and returns the fills with
+// their realized P/L. Opening fees are charged pro rata to the lot quantity closed and
+// closing fees pro rata across the fills. Lots must be ordered oldest first; their
+// remaining quantities are reduced in place.
+func CloseFIFO(lots []models.Lot, side string, multiplier int, quantity, price, fees float64) ([]Fill, error) {
+	if quantity > OpenQuantity(lots)+epsilon {
+		return nil, ErrInsufficientQuantity
+	}
+
+	m := float64(multiplier)
+	left := quantity
+	var fills []Fill
+	for i := range lots {
+		lot := &lots[i]
+		if left < epsilon {
+			break
+		}
+		if lot.RemainingQuantity < epsilon {
+			continue
+		}
+
+		take := math.Min(lot.RemainingQuantity, left)
+		openFees := lot.Fees * take / lot.Quantity
+		closeFees := fees * take / quantity
+
+		fill := Fill{Lot: lot, Quantity: take}
+		if side == models.SideShort {
+			fill.OpenValue = take*lot.Price*m - openFees
+			fill.CloseValue = take*price*m + closeFees
+			fill.RealizedPnL = fill.OpenValue - fill.CloseValue
+		} else {
+			fill.OpenValue = take*lot.Price*m + openFees
+			fill.CloseValue = take*price*m - closeFees
+			fill.RealizedPnL = fill.CloseValue - fill.OpenValue
+		}
+		fills = append(fills, fill)
+
+		lot.RemainingQuantity -= take
+		if lot.RemainingQuantity < epsilon {
+			lot.RemainingQuantity = 0
+		}
+		left -= take
+	}
+
+	return fills, nil
+}
+
+// RealizedPnL sums the P/L realized across fills
+func RealizedPnL(fills []Fill) float64 {
+	var total float64
+	for _, f := range fills {
+		total += f.RealizedPnL
+	}
+	return total
+}
@@ -0,0 +1,118 @@
+package ledger
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+)
+
+func TestCloseFIFO(t *testing.T) {
+	type fill struct {
+		lotID                      int64
+		quantity, open, close, pnl float64
+	}
+	tests := []struct {
+		name       string
+		lots       []models.Lot
+		side       string
+		multiplier int
+		quantity   float64
+		price      float64
+		fees       float64
+		want       []fill
+		remaining  []float64
+		wantErr    error
+	}{
+		{
+			name: "long close across lots, oldest first",
+			lots: []models.Lot{
+				{ID: 1, Quantity: 10, RemainingQuantity: 10, Price: 100, Fees: 10},
+				{ID: 2, Quantity: 10, RemainingQuantity: 10, Price: 110, Fees: 5},
+			},
+			side: models.SideLong, multiplier: 1, quantity: 15, price: 120, fees: 3,
+			want: []fill{
+				// 10 of 15 closed: all the lot's opening fees, two thirds of the closing fees
+				{lotID: 1, quantity: 10, open: 1010, close: 1198, pnl: 188},
+				// 5 of the second lot's 10: half its opening fees, a third of the closing fees
+				{lotID: 2, quantity: 5, open: 552.5, close: 599, pnl: 46.5},
+			},
+			remaining: []float64{0, 5},
+		},
+		{
+			name: "short fees prorated to the quantity closed",
+			lots: []models.Lot{
+				{ID: 1, Quantity: 4, RemainingQuantity: 4, Price: 3, Fees: 2.6},
+			},
+			side: models.SideShort, multiplier: 100, quantity: 1, price: 1, fees: 0.65,
+			want: []fill{
+				// Sold 1 at 3.00 less a quarter of 2.60 in opening fees; bought back at 1.00 plus 0.65
+				{lotID: 1, quantity: 1, open: 299.35, close: 100.65, pnl: 198.7},
+			},
+			remaining: []float64{3},
+		},
+		{
+			name: "short closed across lots at a loss",
+			lots: []models.Lot{
+				{ID: 1, Quantity: 1, RemainingQuantity: 1, Price: 2, Fees: 1},
+				{ID: 2, Quantity: 2, RemainingQuantity: 2, Price: 2.5, Fees: 2},
+			},
+			side: models.SideShort, multiplier: 100, quantity: 2, price: 4, fees: 2,
+			want: []fill{
+				{lotID: 1, quantity: 1, open: 199, close: 401, pnl: -202},
+				{lotID: 2, quantity: 1, open: 249, close: 401, pnl: -152},
+			},
+			remaining: []float64{0, 1},
+		},
+		{
+			name: "closed lots are skipped",
+			lots: []models.Lot{
+				{ID: 1, Quantity: 5, RemainingQuantity: 0, Price: 50},
+				{ID: 2, Quantity: 5, RemainingQuantity: 5, Price: 60},
+			},
+			side: models.SideLong, multiplier: 1, quantity: 5, price: 55,
+			want:      []fill{{lotID: 2, quantity: 5, open: 300, close: 275, pnl: -25}},
+			remaining: []float64{0, 0},
+		},
+		{
+			name: "close larger than the open quantity",
+			lots: []models.Lot{
+				{ID: 1, Quantity: 5, RemainingQuantity: 3, Price: 50},
+			},
+			side: models.SideLong, multiplier: 1, quantity: 4, price: 55,
+			remaining: []float64{3},
+			wantErr:   ErrInsufficientQuantity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fills, err := CloseFIFO(tt.lots, tt.side, tt.multiplier, tt.quantity, tt.price, tt.fees)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if len(fills) != len(tt.want) {
+				t.Fatalf("got %d fills, want %d", len(fills), len(tt.want))
+			}
+			for i, want := range tt.want {
+				got := fills[i]
+				if got.Lot.ID != want.lotID || !near(got.Quantity, want.quantity) || !near(got.OpenValue, want.open) ||
+					!near(got.CloseValue, want.close) || !near(got.RealizedPnL, want.pnl) {
+					t.Errorf("fill %d: got lot %d qty %g open %g close %g pnl %g, want %+v",
+						i, got.Lot.ID, got.Quantity, got.OpenValue, got.CloseValue, got.RealizedPnL, want)
+				}
+			}
+			for i, want := range tt.remaining {
+				if !near(tt.lots[i].RemainingQuantity, want) {
+					t.Errorf("lot %d remaining: got %g, want %g", tt.lots[i].ID, tt.lots[i].RemainingQuantity, want)
+				}
+			}
+		})
+	}
+}
+
+// near compares money amounts to a hundredth of a cent
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-4
+}
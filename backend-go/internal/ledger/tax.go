@@ -0,0 +1,124 @@
+package ledger
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+)
+
+// WashSaleWindowDays is how many days before or after a loss a replacement purchase
+// triggers the wash sale rule
+const WashSaleWindowDays = 30
+
+// Acquisition is a lot opened in a ticker, matched against realized losses as a
+// possible wash sale replacement
+type Acquisition struct {
+	LotID    int64
+	Ticker   string
+	Side     string
+	Quantity float64
+	OpenedAt string // YYYY-MM-DD
+}
+
+// Classify sets the holding period and term of a realized lot. Gains are long-term when
+// the lot closes after the anniversary of its opening; short positions are always
+// short-term.
+func Classify(r *models.RealizedLot) error {
+	opened, err := time.Parse("2006-01-02", r.OpenedAt)
+	if err != nil {
+		return fmt.Errorf("invalid opened date %q: %w", r.OpenedAt, err)
+	}
+	closed, err := time.Parse("2006-01-02", r.ClosedAt)
+	if err != nil {
+		return fmt.Errorf("invalid closed date %q: %w", r.ClosedAt, err)
+	}
+
+	r.HoldingDays = int(closed.Sub(opened).Hours() / 24)
+	r.Term = models.TermShort
+	if r.Side == models.SideLong && closed.After(opened.AddDate(1, 0, 0)) {
+		r.Term = models.TermLong
+	}
+	return nil
+}
+
+// FlagWashSales marks realized losses with a replacement lot of the same ticker and side
+// opened within WashSaleWindowDays of the close. Lots closed by the same transaction are not
+// replacements of each other, and each replacement lot covers losses up to its quantity, oldest
+// loss first. The disallowed loss is the share of the loss covered by replacements. Realized lots
+// must be ordered by close date.
+func FlagWashSales(realized []models.RealizedLot, acquisitions []Acquisition) error {
+	closedBy := make(map[int64]map[int64]bool)
+	for _, r := range realized {
+		if closedBy[r.TransactionID] == nil {
+			closedBy[r.TransactionID] = make(map[int64]bool)
+		}
+		closedBy[r.TransactionID][r.LotID] = true
+	}
+
+	opened := make([]time.Time, len(acquisitions))
+	available := make([]float64, len(acquisitions))
+	for i, a := range acquisitions {
+		t, err := time.Parse("2006-01-02", a.OpenedAt)
+		if err != nil {
+			return fmt.Errorf("invalid opened date %q: %w", a.OpenedAt, err)
+		}
+		opened[i] = t
+		available[i] = a.Quantity
+	}
+
+	for i := range realized {
+		r := &realized[i]
+		if r.RealizedPnL >= 0 {
+			continue
+		}
+		closed, err := time.Parse("2006-01-02", r.ClosedAt)
+		if err != nil {
+			return fmt.Errorf("invalid closed date %q: %w", r.ClosedAt, err)
+		}
+
+		var matched float64
+		for j, a := range acquisitions {
+			if matched >= r.Quantity-epsilon {
+				break
+			}
+			if available[j] < epsilon || a.Ticker != r.Ticker || a.Side != r.Side || closedBy[r.TransactionID][a.LotID] {
+				continue
+			}
+			if days := math.Abs(opened[j].Sub(closed).Hours() / 24); days > WashSaleWindowDays {
+				continue
+			}
+
+			take := math.Min(available[j], r.Quantity-matched)
+			available[j] -= take
+			matched += take
+		}
+
+		if matched > epsilon {
+			r.WashSale = true
+			r.DisallowedLoss = -r.RealizedPnL * math.Min(matched/r.Quantity, 1)
+		}
+	}
+	return nil
+}
+
+// SummarizeRealized totals realized lots by term
+func SummarizeRealized(realized []models.RealizedLot) models.RealizedSummary {
+	var s models.RealizedSummary
+	for _, r := range realized {
+		if r.Term == models.TermLong {
+			s.LongTermPnL += r.RealizedPnL
+		} else {
+			s.ShortTermPnL += r.RealizedPnL
+		}
+		s.TotalPnL += r.RealizedPnL
+		s.Proceeds += r.Proceeds
+		s.CostBasis += r.CostBasis
+		if r.WashSale {
+			s.WashSales++
+			s.DisallowedLoss += r.DisallowedLoss
+		}
+	}
+	return s
+}
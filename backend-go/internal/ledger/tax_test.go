@@ -0,0 +1,148 @@
+package ledger
+
+import (
+	"testing"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name     string
+		side     string
+		opened   string
+		closed   string
+		wantDays int
+		wantTerm string
+	}{
+		{"long closed on the anniversary", models.SideLong, "2025-03-10", "2026-03-10", 365, models.TermShort},
+		{"long closed the day after the anniversary", models.SideLong, "2025-03-10", "2026-03-11", 366, models.TermLong},
+		{"long closed the same day", models.SideLong, "2026-01-05", "2026-01-05", 0, models.TermShort},
+		{"short held over a year", models.SideShort, "2024-03-10", "2026-03-11", 731, models.TermShort},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := models.RealizedLot{Side: tt.side, OpenedAt: tt.opened, ClosedAt: tt.closed}
+			if err := Classify(&r); err != nil {
+				t.Fatal(err)
+			}
+			if r.HoldingDays != tt.wantDays || r.Term != tt.wantTerm {
+				t.Errorf("got %d days %s, want %d days %s", r.HoldingDays, r.Term, tt.wantDays, tt.wantTerm)
+			}
+		})
+	}
+
+	if err := Classify(&models.RealizedLot{OpenedAt: "2026-13-01", ClosedAt: "2026-01-05"}); err == nil {
+		t.Error("invalid opened date: got no error")
+	}
+}
+
+func TestFlagWashSales(t *testing.T) {
+	// loss closes lotID in transaction txn on closed, losing pnl over quantity
+	loss := func(txn, lotID int64, quantity, pnl float64, closed string) models.RealizedLot {
+		return models.RealizedLot{
+			TransactionID: txn, LotID: lotID, Ticker: "AAPL", Side: models.SideLong,
+			Quantity: quantity, RealizedPnL: pnl, ClosedAt: closed,
+		}
+	}
+	buy := func(lotID int64, quantity float64, opened string) Acquisition {
+		return Acquisition{LotID: lotID, Ticker: "AAPL", Side: models.SideLong, Quantity: quantity, OpenedAt: opened}
+	}
+	type flag struct {
+		wash       bool
+		disallowed float64
+	}
+
+	tests := []struct {
+		name         string
+		realized     []models.RealizedLot
+		acquisitions []Acquisition
+		want         []flag
+	}{
+		{
+			name:         "replacement 30 days after the loss",
+			realized:     []models.RealizedLot{loss(10, 1, 10, -500, "2026-03-02")},
+			acquisitions: []Acquisition{buy(1, 10, "2026-01-05"), buy(2, 10, "2026-04-01")},
+			want:         []flag{{true, 500}},
+		},
+		{
+			name:         "replacement 31 days after the loss",
+			realized:     []models.RealizedLot{loss(10, 1, 10, -500, "2026-03-02")},
+			acquisitions: []Acquisition{buy(1, 10, "2026-01-05"), buy(2, 10, "2026-04-02")},
+			want:         []flag{{false, 0}},
+		},
+		{
+			name:         "replacement 30 days before the loss",
+			realized:     []models.RealizedLot{loss(10, 1, 10, -500, "2026-03-02")},
+			acquisitions: []Acquisition{buy(1, 10, "2026-01-05"), buy(2, 10, "2026-01-31")},
+			want:         []flag{{true, 500}},
+		},
+		{
+			name:         "replacement 31 days before the loss",
+			realized:     []models.RealizedLot{loss(10, 1, 10, -500, "2026-03-02")},
+			acquisitions: []Acquisition{buy(1, 10, "2026-01-05"), buy(2, 10, "2026-01-30")},
+			want:         []flag{{false, 0}},
+		},
+		{
+			name:         "partial replacement quantity",
+			realized:     []models.RealizedLot{loss(10, 1, 10, -500, "2026-03-02")},
+			acquisitions: []Acquisition{buy(1, 10, "2026-01-05"), buy(2, 4, "2026-03-09")},
+			want:         []flag{{true, 200}},
+		},
+		{
+			// Both lots were bought within 30 days and sold together; neither replaces the other
+			name: "lots closed by the same transaction",
+			realized: []models.RealizedLot{
+				loss(10, 1, 5, -100, "2026-03-02"),
+				loss(10, 2, 5, -150, "2026-03-02"),
+			},
+			acquisitions: []Acquisition{buy(1, 5, "2026-02-10"), buy(2, 5, "2026-02-20")},
+			want:         []flag{{false, 0}, {false, 0}},
+		},
+		{
+			name: "a lot closed by another transaction is a replacement",
+			realized: []models.RealizedLot{
+				loss(10, 1, 5, -100, "2026-03-02"),
+				loss(11, 2, 5, -150, "2026-03-05"),
+			},
+			acquisitions: []Acquisition{buy(1, 5, "2026-01-05"), buy(2, 5, "2026-02-20")},
+			want:         []flag{{true, 100}, {false, 0}},
+		},
+		{
+			name: "a replacement covers the oldest loss first",
+			realized: []models.RealizedLot{
+				loss(10, 1, 5, -100, "2026-03-02"),
+				loss(11, 2, 5, -80, "2026-03-04"),
+			},
+			acquisitions: []Acquisition{buy(1, 5, "2026-01-05"), buy(2, 5, "2026-01-06"), buy(3, 7, "2026-03-10")},
+			want:         []flag{{true, 100}, {true, 32}},
+		},
+		{
+			name: "gains and other tickers or sides are not matched",
+			realized: []models.RealizedLot{
+				loss(10, 1, 5, 100, "2026-03-02"),
+				loss(11, 2, 5, -100, "2026-03-02"),
+			},
+			acquisitions: []Acquisition{
+				{LotID: 3, Ticker: "MSFT", Side: models.SideLong, Quantity: 5, OpenedAt: "2026-03-03"},
+				{LotID: 4, Ticker: "AAPL", Side: models.SideShort, Quantity: 5, OpenedAt: "2026-03-03"},
+			},
+			want: []flag{{false, 0}, {false, 0}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := FlagWashSales(tt.realized, tt.acquisitions); err != nil {
+				t.Fatal(err)
+			}
+			for i, want := range tt.want {
+				r := tt.realized[i]
+				if r.WashSale != want.wash || !near(r.DisallowedLoss, want.disallowed) {
+					t.Errorf("lot %d: got wash sale %v disallowing %g, want %v disallowing %g",
+						r.LotID, r.WashSale, r.DisallowedLoss, want.wash, want.disallowed)
+				}
+			}
+		})
+	}
+}
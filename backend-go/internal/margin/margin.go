@@ -0,0 +1,291 @@
+package margin
+
+import (
+	"math"
+	"sort"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/pricing"
+)
+
+// Reg-T requirement rates
+const (
+	StockRate        = 0.50 // initial requirement on long and short stock
+	NakedRate        = 0.20 // of the underlying, less the out-of-the-money amount
+	NakedMinimumRate = 0.10 // of the underlying for calls, of the strike for puts
+)
+
+// Portfolio margin scan: the underlying is moved by up to ScanRange in both directions
+// and the requirement is the largest loss, but at least ContractMinimum per share of each
+// option contract ($37.50 for a standard contract)
+const (
+	ScanRange       = 0.15
+	scanSteps       = 10
+	ContractMinimum = 0.375
+)
+
+// Leg is an open or proposed position on one underlying
+type Leg struct {
+	PositionID int64 // zero for a proposed trade
+	Ticker     string
+	Option     bool
+	Call       bool
+	Strike     float64
+	Expiration string          // YYYY-MM-DD
+	Quantity   float64         // signed contracts or shares: negative for short
+	Multiplier float64         // shares per contract (1 for stock)
+	Mark       float64         // current price per share
+	Model      *pricing.Inputs // pricing inputs for the scan, nil to use intrinsic value
+}
+
+// Underlying returns the Reg-T and portfolio margin requirements of the legs on one
+// underlying trading at spot
+func Underlying(ticker string, legs []Leg, spot float64) models.UnderlyingMargin {
+	requirements := RegT(legs, spot)
+	m := models.UnderlyingMargin{
+		Underlying:      ticker,
+		UnderlyingPrice: spot,
+		Requirements:    requirements,
+	}
+	for _, r := range requirements {
+		m.RegT += r.Amount
+	}
+	m.PortfolioMargin, m.WorstMove = PortfolioMargin(legs, spot)
+	return m
+}
+
+// remaining tracks the unpaired quantity of a leg while strategies are matched
+type remaining struct {
+	*Leg
+	left float64 // absolute contracts or shares not yet assigned to a strategy
+}
+
+// RegT groups the legs into Reg-T strategies and returns the requirement of each. Short
+// calls are covered by long shares and short puts by short shares, most in-the-money
+// first; the rest are paired into spreads with long options of the same type that expire
+// no earlier, choosing the long leg with the smallest requirement. Whatever is left is
+// margined as long options paid in full or naked short options.
+func RegT(legs []Leg, spot float64) []models.MarginRequirement {
+	var stocks []*Leg
+	var shortCalls, shortPuts, longCalls, longPuts []*remaining
+	var longShares, shortShares float64
+	for i := range legs {
+		l := &legs[i]
+		if l.Quantity == 0 {
+			continue
+		}
+		if !l.Option {
+			stocks = append(stocks, l)
+			if l.Quantity > 0 {
+				longShares += l.Quantity
+			} else {
+				shortShares -= l.Quantity
+			}
+			continue
+		}
+		r := &remaining{Leg: l, left: math.Abs(l.Quantity)}
+		switch {
+		case l.Call && l.Quantity < 0:
+			shortCalls = append(shortCalls, r)
+		case l.Call:
+			longCalls = append(longCalls, r)
+		case l.Quantity < 0:
+			shortPuts = append(shortPuts, r)
+		default:
+			longPuts = append(longPuts, r)
+		}
+	}
+	sort.SliceStable(shortCalls, func(i, j int) bool { return shortCalls[i].Strike < shortCalls[j].Strike })
+	sort.SliceStable(shortPuts, func(i, j int) bool { return shortPuts[i].Strike > shortPuts[j].Strike })
+
+	requirements := []models.MarginRequirement{}
+	for _, s := range stocks {
+		strategy := models.MarginLongStock
+		if s.Quantity < 0 {
+			strategy = models.MarginShortStock
+		}
+		requirements = append(requirements, models.MarginRequirement{
+			Strategy:    strategy,
+			Tickers:     []string{s.Ticker},
+			PositionIDs: positionIDs(s),
+			Quantity:    math.Abs(s.Quantity),
+			Amount:      StockRate * math.Abs(s.Quantity) * s.Mark,
+		})
+	}
+
+	requirements = cover(requirements, models.MarginCoveredCall, shortCalls, stocks, longShares, 1)
+	requirements = cover(requirements, models.MarginCoveredPut, shortPuts, stocks, shortShares, -1)
+	requirements = pairSpreads(requirements, shortCalls, longCalls)
+	requirements = pairSpreads(requirements, shortPuts, longPuts)
+
+	for _, long := range append(longCalls, longPuts...) {
+		if long.left > 0 {
+			requirements = append(requirements, models.MarginRequirement{
+				Strategy:    models.MarginLongOption,
+				Tickers:     []string{long.Ticker},
+				PositionIDs: positionIDs(long.Leg),
+				Quantity:    long.left,
+				Amount:      long.left * long.Multiplier * long.Mark,
+			})
+		}
+	}
+	for _, short := range append(shortCalls, shortPuts...) {
+		if short.left > 0 {
+			strategy := models.MarginNakedPut
+			if short.Call {
+				strategy = models.MarginNakedCall
+			}
+			requirements = append(requirements, models.MarginRequirement{
+				Strategy:    strategy,
+				Tickers:     []string{short.Ticker},
+				PositionIDs: positionIDs(short.Leg),
+				Quantity:    short.left,
+				Amount:      short.left * short.Multiplier * NakedRequirement(short.Leg, spot),
+			})
+		}
+	}
+
+	return requirements
+}
+
+// cover assigns shares on the side given by sign (1 long, -1 short) to the short options,
+// one contract's multiplier of shares each. The shares keep their stock requirement, so
+// the covered options add none.
+func cover(requirements []models.MarginRequirement, strategy string, shorts []*remaining, stocks []*Leg, shares float64, sign float64) []models.MarginRequirement {
+	var tickers []string
+	var ids []int64
+	for _, s := range stocks {
+		if s.Quantity*sign > 0 {
+			tickers = append(tickers, s.Ticker)
+			ids = append(ids, positionIDs(s)...)
+		}
+	}
+
+	for _, short := range shorts {
+		if shares <= 0 {
+			break
+		}
+		n := math.Min(short.left, math.Floor(shares/short.Multiplier))
+		if n <= 0 {
+			continue
+		}
+		short.left -= n
+		shares -= n * short.Multiplier
+		requirements = append(requirements, models.MarginRequirement{
+			Strategy:    strategy,
+			Tickers:     append([]string{short.Ticker}, tickers...),
+			PositionIDs: append(positionIDs(short.Leg), ids...),
+			Quantity:    n,
+		})
+	}
+	return requirements
+}
+
+// pairSpreads pairs each short option with long options of the same type, multiplier and
+// an equal or later expiration, cheapest requirement first
+func pairSpreads(requirements []models.MarginRequirement, shorts, longs []*remaining) []models.MarginRequirement {
+	for _, short := range shorts {
+		for short.left > 0 {
+			var best *remaining
+			var bestAmount float64
+			for _, long := range longs {
+				if long.left <= 0 || long.Multiplier != short.Multiplier || long.Expiration < short.Expiration {
+					continue
+				}
+				amount := SpreadRequirement(short.Leg, long.Leg)
+				if best == nil || amount < bestAmount {
+					best, bestAmount = long, amount
+				}
+			}
+			if best == nil {
+				break
+			}
+
+			n := math.Min(short.left, best.left)
+			short.left -= n
+			best.left -= n
+			requirements = append(requirements, models.MarginRequirement{
+				Strategy:    models.MarginSpread,
+				Tickers:     []string{short.Ticker, best.Ticker},
+				PositionIDs: append(positionIDs(short.Leg), positionIDs(best.Leg)...),
+				Quantity:    n,
+				Amount:      n * short.Multiplier * bestAmount,
+			})
+		}
+	}
+	return requirements
+}
+
+// SpreadRequirement returns the per-share requirement of a short option paired with a
+// long option of the same type: the maximum loss between the strikes for a credit
+// spread, and the net debit still carried by the long leg for a debit spread
+func SpreadRequirement(short, long *Leg) float64 {
+	width := long.Strike - short.Strike
+	if !short.Call {
+		width = short.Strike - long.Strike
+	}
+	return math.Max(width, 0) + math.Max(long.Mark-short.Mark, 0)
+}
+
+// NakedRequirement returns the per-share Reg-T requirement of an uncovered short option:
+// its current price plus 20% of the underlying less the out-of-the-money amount, but at
+// least 10% of the underlying (calls) or strike (puts)
+func NakedRequirement(short *Leg, spot float64) float64 {
+	if short.Call {
+		otm := math.Max(short.Strike-spot, 0)
+		return short.Mark + math.Max(NakedRate*spot-otm, NakedMinimumRate*spot)
+	}
+	otm := math.Max(spot-short.Strike, 0)
+	return short.Mark + math.Max(NakedRate*spot-otm, NakedMinimumRate*short.Strike)
+}
+
+// PortfolioMargin revalues the legs with the underlying moved across ±ScanRange and
+// returns the largest loss, floored at ContractMinimum per option share, together with
+// the move that produced it
+func PortfolioMargin(legs []Leg, spot float64) (float64, float64) {
+	var minimum float64
+	for i := range legs {
+		if legs[i].Option {
+			minimum += ContractMinimum * math.Abs(legs[i].Quantity) * legs[i].Multiplier
+		}
+	}
+
+	var worstLoss, worstMove float64
+	for step := 0; step <= scanSteps; step++ {
+		move := -ScanRange + 2*ScanRange*float64(step)/scanSteps
+		var loss float64
+		for i := range legs {
+			l := &legs[i]
+			loss += l.Quantity * l.Multiplier * (value(l, spot) - value(l, spot*(1+move)))
+		}
+		if loss > worstLoss {
+			worstLoss, worstMove = loss, move
+		}
+	}
+
+	return math.Max(worstLoss, minimum), worstMove
+}
+
+// value prices a leg per share with the underlying at spot
+func value(l *Leg, spot float64) float64 {
+	if !l.Option {
+		return spot
+	}
+	if l.Model != nil {
+		in := *l.Model
+		in.Spot = spot
+		return pricing.Price(in)
+	}
+	if l.Call {
+		return math.Max(spot-l.Strike, 0)
+	}
+	return math.Max(l.Strike-spot, 0)
+}
+
+// positionIDs returns the leg's position ID, or none for a proposed trade
+func positionIDs(l *Leg) []int64 {
+	if l.PositionID == 0 {
+		return []int64{}
+	}
+	return []int64{l.PositionID}
+}
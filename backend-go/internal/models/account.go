@@ -0,0 +1,10 @@
+package models
+
+import "encoding/json"
+
+// AccountExportSection is one table of a user's data export: every row stored for the user,
+// as a JSON array of objects keyed by column
+type AccountExportSection struct {
+	Name string
+	Rows json.RawMessage
+}
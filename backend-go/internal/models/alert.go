@@ -0,0 +1,219 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Market alert statuses besides those shared with position alerts
+const (
+	AlertCooldown = "cooldown" // fired, and re-arms itself once its cooldown passes
+	AlertSnoozed  = "snoozed"  // held by the user, and re-arms itself once the snooze ends
+)
+
+// Market alert metrics
+const (
+	MarketMetricPrice         = "price"            // last stock price
+	MarketMetricChangePercent = "change_percent"   // session change from the previous close, in percent
+	MarketMetricATMIV         = "atm_iv"           // 30-day constant maturity ATM implied volatility
+	MarketMetricIVRank        = "iv_rank"          // 0-100, ATM IV within its 52-week low and high
+	MarketMetricIVPercentile  = "iv_percentile"    // 0-100, share of the last 52 weeks with lower ATM IV
+	MarketMetricIV            = "iv"               // implied volatility of an option contract
+	MarketMetricDelta         = "delta"            // contract delta, negative for puts
+	MarketMetricAbsDelta      = "abs_delta"        // contract delta without its sign
+	MarketMetricGamma         = "gamma"            // contract gamma
+	MarketMetricTheta         = "theta"            // contract theta per day, negative for long premium
+	MarketMetricVega          = "vega"             // contract vega per vol point
+	MarketMetricSpreadPercent = "spread_percent"   // contract bid-ask spread as a percent of the mid
+	MarketMetricQuoteSize     = "quote_size"       // smaller of the contract's bid and ask sizes
+	MarketMetricLiquidity     = "liquidity_score"  // 0-100 composite of spread, volume, open interest and size
+	MarketMetricEarningsDays  = "days_to_earnings" // calendar days until the next earnings release, 0 on the day
+
+	MarketMetricStrikeDistance   = "strike_distance_percent" // distance of the underlying price from the strike, in percent
+	MarketMetricDaysToExpiration = "days_to_expiration"      // calendar days until the contract expires
+)
+
+// Market alert modes
+const (
+	AlertOneShot   = "one_shot"  // stays triggered after firing until re-armed
+	AlertRecurring = "recurring" // re-arms itself once its cooldown passes
+)
+
+// Alert sources: rules created by the user, or managed by their earnings alert settings
+const (
+	AlertSourceUser     = "user"
+	AlertSourceEarnings = "earnings"
+)
+
+// Crossing operators fire when the value moves across the threshold between two
+// evaluations, in addition to the >, >=, < and <= comparisons
+const (
+	OperatorCrossesAbove = "crosses_above"
+	OperatorCrossesBelow = "crosses_below"
+)
+
+// Alert is a user's threshold rule on a market metric of a stock or option contract,
+// such as "AAPL price > 200"
+type Alert struct {
+	ID              int64      `json:"id"`
+	UserID          *string    `json:"user_id,omitempty"`
+	Ticker          string     `json:"ticker"`
+	AssetType       string     `json:"asset_type"` // "stock" or "option", from the ticker
+	Metric          string     `json:"metric"`
+	Operator        string     `json:"operator"` // >, >=, <, <=, crosses_above or crosses_below
+	Threshold       float64    `json:"threshold"`
+	Note            *string    `json:"note,omitempty"`
+	Mode            string     `json:"mode"`             // one_shot or recurring
+	CooldownSeconds int        `json:"cooldown_seconds"` // wait before a recurring alert re-arms
+	Status          string     `json:"status"`
+	Source          string     `json:"source"` // user or earnings
+	LastValue       *float64   `json:"last_value"`
+	LastEvaluatedAt *time.Time `json:"last_evaluated_at"`
+	TriggeredValue  *float64   `json:"triggered_value,omitempty"`
+	TriggeredAt     *time.Time `json:"triggered_at,omitempty"`
+	CooldownUntil   *time.Time `json:"cooldown_until,omitempty"`
+	SnoozedUntil    *time.Time `json:"snoozed_until,omitempty"`
+	TriggerCount    int        `json:"trigger_count"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	DeletedAt       *time.Time `json:"deleted_at,omitempty"` // set while it is deleted and restorable
+
+	// Condition is a composite rule, with its first comparison as the metric, operator and
+	// threshold above; nil for single-comparison rules
+	Condition *AlertCondition `json:"condition,omitempty"`
+}
+
+// Matches reports whether value fires the rule. Crossing operators compare it with the
+// previous evaluation's LastValue and never fire without one.
+func (a *Alert) Matches(value float64) bool {
+	switch a.Operator {
+	case OperatorCrossesAbove:
+		return a.LastValue != nil && *a.LastValue < a.Threshold && value >= a.Threshold
+	case OperatorCrossesBelow:
+		return a.LastValue != nil && *a.LastValue > a.Threshold && value <= a.Threshold
+	}
+	return Compare(a.Operator, value, a.Threshold)
+}
+
+// Composite alert rule limits
+const (
+	MaxConditionComparisons = 10
+	MaxConditionDepth       = 3
+)
+
+// AlertCondition is a composite alert rule: a comparison of a metric with a threshold, or a
+// group of conditions that must all hold (AND) or of which any must hold (OR), e.g.
+// {"all": [{"metric": "iv_rank", "operator": ">", "threshold": 50},
+// {"metric": "days_to_expiration", "operator": "<", "threshold": 10}]}
+type AlertCondition struct {
+	Metric    string           `json:"metric,omitempty"`
+	Operator  string           `json:"operator,omitempty"`
+	Threshold *float64         `json:"threshold,omitempty"`
+	All       []AlertCondition `json:"all,omitempty"`
+	Any       []AlertCondition `json:"any,omitempty"`
+}
+
+// IsComparison reports whether the condition compares a metric rather than grouping
+func (c *AlertCondition) IsComparison() bool {
+	return c.All == nil && c.Any == nil
+}
+
+// Comparisons returns the condition's comparisons in order
+func (c *AlertCondition) Comparisons() []AlertCondition {
+	if c.IsComparison() {
+		return []AlertCondition{*c}
+	}
+	var comparisons []AlertCondition
+	for i := range c.All {
+		comparisons = append(comparisons, c.All[i].Comparisons()...)
+	}
+	for i := range c.Any {
+		comparisons = append(comparisons, c.Any[i].Comparisons()...)
+	}
+	return comparisons
+}
+
+// Holds evaluates the condition with the metric values value looks up. A comparison whose
+// metric has no value does not hold.
+func (c *AlertCondition) Holds(value func(metric string) (float64, bool)) bool {
+	if c.IsComparison() {
+		v, ok := value(c.Metric)
+		return ok && c.Threshold != nil && Compare(c.Operator, v, *c.Threshold)
+	}
+	for i := range c.All {
+		if !c.All[i].Holds(value) {
+			return false
+		}
+	}
+	if c.All != nil {
+		return true
+	}
+	for i := range c.Any {
+		if c.Any[i].Holds(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// String writes the condition as an expression, e.g. "iv_rank > 50 AND (delta > 0.5 OR ...)"
+func (c *AlertCondition) String() string {
+	if c.IsComparison() {
+		threshold := "?"
+		if c.Threshold != nil {
+			threshold = strconv.FormatFloat(*c.Threshold, 'g', -1, 64)
+		}
+		return c.Metric + " " + c.Operator + " " + threshold
+	}
+	parts, join := c.All, " AND "
+	if c.All == nil {
+		parts, join = c.Any, " OR "
+	}
+	terms := make([]string, len(parts))
+	for i := range parts {
+		terms[i] = parts[i].String()
+		if !parts[i].IsComparison() {
+			terms[i] = "(" + terms[i] + ")"
+		}
+	}
+	return strings.Join(terms, join)
+}
+
+// AlertTrigger records one time an alert fired, with the market data it was evaluated
+// against
+type AlertTrigger struct {
+	ID             int64              `json:"id"`
+	AlertID        int64              `json:"alert_id"`
+	Value          float64            `json:"value"`
+	PreviousValue  *float64           `json:"previous_value,omitempty"` // the evaluation before, for crossings
+	Threshold      float64            `json:"threshold"`
+	Snapshot       map[string]float64 `json:"snapshot"` // every metric fetched for the ticker that run
+	TriggeredAt    time.Time          `json:"triggered_at"`
+	AcknowledgedAt *time.Time         `json:"acknowledged_at,omitempty"`
+}
+
+// AlertTriggerFilter narrows a listing of a user's alert triggers, newest first
+type AlertTriggerFilter struct {
+	AlertID        int64 // 0 for every alert
+	Unacknowledged bool
+	Before         int64 // trigger ID to page back from, 0 for the newest
+	Limit          int
+}
+
+// EarningsAlertSettings choose which tickers a user is alerted about ahead of earnings: the
+// underlyings of open positions in their portfolios, the tickers and option underlyings in
+// their watchlists, or both. Each covered ticker gets a managed days_to_earnings alert.
+type EarningsAlertSettings struct {
+	UserID     *string    `json:"user_id,omitempty"`
+	Enabled    bool       `json:"enabled"`
+	DaysBefore int        `json:"days_before"`
+	Portfolios bool       `json:"portfolios"`
+	Watchlists bool       `json:"watchlists"`
+	Tickers    []string   `json:"tickers"` // covered as of the last sync
+	SyncedAt   *time.Time `json:"synced_at,omitempty"`
+	UpdatedAt  *time.Time `json:"updated_at,omitempty"`
+}
+
+// DefaultEarningsDaysBefore is how many days ahead of earnings alerts fire by default
+const DefaultEarningsDaysBefore = 3
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Alert channel kinds
+const (
+	ChannelWebhook = "webhook" // signed JSON POST to a URL
+	ChannelEmail   = "email"   // plain-text email to an address
+	ChannelSlack   = "slack"   // message posted to a Slack incoming webhook
+	ChannelDiscord = "discord" // message posted to a Discord channel webhook
+)
+
+// AlertChannelKinds lists the supported alert channel kinds
+var AlertChannelKinds = []string{ChannelWebhook, ChannelEmail, ChannelSlack, ChannelDiscord}
+
+// Alert delivery events
+const (
+	EventAlertTriggered = "alert.triggered" // data: the alert and its trigger
+)
+
+// AlertChannel is a destination a user's alert triggers are delivered to, all of them or
+// those on one watchlist's tickers. The webhook signing secret is only returned when the
+// channel is created.
+type AlertChannel struct {
+	ID             int64      `json:"id"`
+	UserID         *string    `json:"user_id,omitempty"`
+	Kind           string     `json:"kind"`
+	Name           *string    `json:"name,omitempty"`
+	URL            *string    `json:"url,omitempty"`
+	Address        *string    `json:"address,omitempty"`      // email channels
+	WatchlistID    *int64     `json:"watchlist_id,omitempty"` // only alerts on this watchlist's tickers, when set
+	Secret         string     `json:"secret,omitempty"`
+	Active         bool       `json:"active"`
+	FailureCount   int        `json:"failure_count"`
+	LastDeliveryAt *time.Time `json:"last_delivery_at,omitempty"`
+	LastStatus     *int       `json:"last_status,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// AlertDelivery is one notification queued for a channel and its delivery attempts
+type AlertDelivery struct {
+	ID             int64           `json:"id"`
+	ChannelID      int64           `json:"channel_id"`
+	TriggerID      *int64          `json:"trigger_id,omitempty"` // nil for test pings
+	Event          string          `json:"event"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	ResponseStatus *int            `json:"response_status,omitempty"`
+	LastError      *string         `json:"last_error,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+}
+
+// AlertNotification is the payload of an alert.triggered delivery
+type AlertNotification struct {
+	Alert   Alert        `json:"alert"`
+	Trigger AlertTrigger `json:"trigger"`
+}
+
+// AlertEvent is the JSON body posted to an alert webhook
+type AlertEvent struct {
+	ID        int64           `json:"id"` // delivery ID, the same across retries
+	Event     string          `json:"event"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// PendingAlertDelivery is a due delivery with the channel, including its secret, it is
+// sent to
+type PendingAlertDelivery struct {
+	Delivery AlertDelivery
+	Channel  AlertChannel
+}
@@ -0,0 +1,70 @@
+package models
+
+import "time"
+
+// Allocation target kinds
+const (
+	TargetTicker = "ticker" // a single underlying
+	TargetSector = "sector" // a named group of underlyings
+)
+
+// Rebalance trade instruments
+const (
+	RebalanceShares  = "shares"
+	RebalanceOptions = "options"
+)
+
+// AllocationTarget is the share of a portfolio's allocation base meant for one underlying
+// or a sector of underlyings
+type AllocationTarget struct {
+	Kind    string   `json:"kind"`
+	Name    string   `json:"name"`    // the ticker, or the sector name
+	Tickers []string `json:"tickers"` // underlyings counted in the target
+	Percent float64  `json:"percent"`
+}
+
+// UnderlyingAllocation is the dollar delta held in one underlying: shares at market value,
+// option legs at delta times the underlying price
+type UnderlyingAllocation struct {
+	Ticker   string   `json:"ticker"`
+	Price    *float64 `json:"price"`
+	Exposure float64  `json:"exposure"`
+	Percent  float64  `json:"percent"` // of the allocation base
+}
+
+// RebalanceTrade is one way to move an underlying's exposure toward its target: a share
+// trade, or opening or closing contracts of an option leg already held
+type RebalanceTrade struct {
+	Instrument  string  `json:"instrument"` // "shares" or "options"
+	Ticker      string  `json:"ticker"`     // stock symbol or OCC contract
+	PositionID  *int64  `json:"position_id,omitempty"`
+	Action      string  `json:"action"` // "open" or "close"
+	Side        string  `json:"side"`   // side of the position opened or closed
+	Quantity    float64 `json:"quantity"`
+	DeltaShares float64 `json:"delta_shares"` // signed share-equivalent delta change
+	Amount      float64 `json:"amount"`       // signed dollar delta change
+}
+
+// TargetDrift compares a target with the exposure held in its underlyings
+type TargetDrift struct {
+	Target        AllocationTarget       `json:"target"`
+	Exposure      float64                `json:"exposure"`
+	ActualPercent float64                `json:"actual_percent"`
+	DriftPercent  float64                `json:"drift_percent"` // actual minus target
+	Adjustment    float64                `json:"adjustment"`    // dollar delta to add (negative to reduce)
+	Rebalance     bool                   `json:"rebalance"`     // drift is beyond the threshold
+	Underlyings   []UnderlyingAllocation `json:"underlyings"`
+	Trades        []RebalanceTrade       `json:"trades"` // alternatives per underlying, empty unless rebalancing
+}
+
+// RebalancePlan is a portfolio's drift from its target allocations with suggested trades
+type RebalancePlan struct {
+	PortfolioID        int64                  `json:"portfolio_id"`
+	Base               float64                `json:"base"`      // dollar amount the percentages apply to
+	Threshold          float64                `json:"threshold"` // drift in percent points that triggers a rebalance
+	Targets            []TargetDrift          `json:"targets"`
+	Untargeted         []UnderlyingAllocation `json:"untargeted"`          // held underlyings outside every target
+	UnallocatedPercent float64                `json:"unallocated_percent"` // 100 minus the target percentages
+	Incomplete         int                    `json:"incomplete"`          // open positions without a delta or price, left out
+	ValuedAt           time.Time              `json:"valued_at"`
+}
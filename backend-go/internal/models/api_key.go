@@ -0,0 +1,64 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// MaxAPIKeys is how many unrevoked API keys a user may hold
+const MaxAPIKeys = 25
+
+// API key scopes, named resource:access. A write scope grants the read scope of its resource
+// too.
+const (
+	ScopeMarketRead     = "market:read"
+	ScopePortfolioRead  = "portfolio:read"
+	ScopePortfolioWrite = "portfolio:write"
+	ScopeWatchlistRead  = "watchlist:read"
+	ScopeWatchlistWrite = "watchlist:write"
+	ScopeAlertRead      = "alert:read"
+	ScopeAlertWrite     = "alert:write"
+	ScopeAccountRead    = "account:read"
+	ScopeAccountWrite   = "account:write"
+)
+
+// AllScopes are the scopes an API key is issued with when none are asked for
+var AllScopes = []string{
+	ScopeMarketRead,
+	ScopePortfolioRead, ScopePortfolioWrite,
+	ScopeWatchlistRead, ScopeWatchlistWrite,
+	ScopeAlertRead, ScopeAlertWrite,
+	ScopeAccountRead, ScopeAccountWrite,
+}
+
+// APIKey is a bearer key a script or bot calls the API with as its owner. The key itself is
+// only returned when it is issued; listings show its prefix.
+type APIKey struct {
+	ID         int64      `json:"id"`
+	UserID     string     `json:"user_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	Key        string     `json:"key,omitempty"`
+}
+
+// Active reports whether the key can still be used
+func (k *APIKey) Active(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+// HasScope reports whether the key grants scope, directly or through the write scope of the
+// same resource
+func (k *APIKey) HasScope(scope string) bool {
+	resource, access, _ := strings.Cut(scope, ":")
+	for _, s := range k.Scopes {
+		if s == scope || (access == "read" && s == resource+":write") {
+			return true
+		}
+	}
+	return false
+}
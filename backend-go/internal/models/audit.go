@@ -0,0 +1,58 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Audited actions, named resource.verb; the part before the dot is the resource type
+const (
+	AuditPortfolioCreate  = "portfolio.create"
+	AuditPortfolioUpdate  = "portfolio.update"
+	AuditPortfolioDelete  = "portfolio.delete"
+	AuditPortfolioRestore = "portfolio.restore"
+	AuditPortfolioPurge   = "portfolio.purge"
+	AuditAlertCreate      = "alert.create"
+	AuditAlertUpdate      = "alert.update"
+	AuditAlertDelete      = "alert.delete"
+	AuditAlertRestore     = "alert.restore"
+	AuditAlertPurge       = "alert.purge"
+	AuditAPIKeyIssue      = "api_key.issue"
+	AuditAPIKeyRevoke     = "api_key.revoke"
+	AuditSettingsSave     = "settings.save"
+	AuditUserDisable      = "user.disable"
+	AuditUserEnable       = "user.enable"
+	AuditUserQuotaReset   = "user.quota_reset"
+	AuditUserTier         = "user.tier"
+
+	AuditAccountExport           = "account.export"
+	AuditAccountDeletionSchedule = "account.deletion_schedule"
+	AuditAccountDeletionCancel   = "account.deletion_cancel"
+	AuditMassiveKeySet           = "massive_key.set"
+	AuditMassiveKeyDelete        = "massive_key.delete"
+)
+
+// AuditEntry records a change made through the API: who made it, from where, and the
+// resource before and after. Before is null for creations and After for deletions.
+type AuditEntry struct {
+	ID           int64           `json:"id"`
+	ActorID      *string         `json:"actor_id,omitempty"`
+	APIKeyID     *int64          `json:"api_key_id,omitempty"`
+	IP           *string         `json:"ip,omitempty"`
+	Action       string          `json:"action"`
+	ResourceType string          `json:"resource_type"`
+	ResourceID   *string         `json:"resource_id,omitempty"`
+	Before       json.RawMessage `json:"before,omitempty"`
+	After        json.RawMessage `json:"after,omitempty"`
+	CreatedAt    time.Time       `json:"created_at"`
+}
+
+// AuditFilter narrows a listing of the audit log, newest first; zero values match everything
+type AuditFilter struct {
+	ActorID      string
+	Action       string
+	ResourceType string
+	ResourceID   string
+	Before       int64 // entry ID to page back from, 0 for the newest
+	Limit        int
+}
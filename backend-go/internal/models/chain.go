@@ -0,0 +1,41 @@
+package models
+
+//go:generate go run github.com/tinylib/msgp@v1.4.0 -tests=false -io=false
+//msgp:tag json
+
+// GroupedChainResponse is the options chain nested by expiration and strike
+type GroupedChainResponse struct {
+	Status          string            `json:"status"`
+	RequestID       string            `json:"request_id"`
+	UnderlyingPrice *float64          `json:"underlying_price,omitempty"`
+	Expirations     []ExpirationGroup `json:"expirations"`
+	NextCursor      string            `json:"next_cursor,omitempty"` // next page of a paged chain
+}
+
+// ExpirationGroup holds every strike of a single expiration plus summary statistics
+type ExpirationGroup struct {
+	ExpirationDate string            `json:"expiration_date"`
+	DTE            int               `json:"dte"`
+	Summary        ExpirationSummary `json:"summary"`
+	Strikes        []StrikeRow       `json:"strikes"`
+}
+
+// ExpirationSummary aggregates activity across an expiration
+type ExpirationSummary struct {
+	ContractCount     int      `json:"contract_count"`
+	ATMStrike         *float64 `json:"atm_strike,omitempty"`
+	ATMIV             *float64 `json:"atm_iv,omitempty"`
+	CallVolume        int64    `json:"call_volume"`
+	PutVolume         int64    `json:"put_volume"`
+	TotalVolume       int64    `json:"total_volume"`
+	CallOpenInterest  int64    `json:"call_open_interest"`
+	PutOpenInterest   int64    `json:"put_open_interest"`
+	TotalOpenInterest int64    `json:"total_open_interest"`
+}
+
+// StrikeRow pairs the call and put listed at one strike
+type StrikeRow struct {
+	Strike float64         `json:"strike"`
+	Call   *OptionContract `json:"call,omitempty"`
+	Put    *OptionContract `json:"put,omitempty"`
+}
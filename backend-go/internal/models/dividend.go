@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// Dividend statuses
+const (
+	DividendAnnounced = "announced" // declared, not yet paid
+	DividendPaid      = "paid"      // pay date reached; included in P/L
+)
+
+// Dividend is a cash dividend earned by a long share position, or owed by a short one,
+// on the shares it held at the ex-dividend date
+type Dividend struct {
+	ID             int64     `json:"id"`
+	PortfolioID    int64     `json:"portfolio_id"`
+	PositionID     int64     `json:"position_id"`
+	Ticker         string    `json:"ticker"`
+	ExDividendDate string    `json:"ex_dividend_date"` // YYYY-MM-DD
+	PayDate        *string   `json:"pay_date"`
+	CashAmount     float64   `json:"cash_amount"` // per share
+	Frequency      int       `json:"frequency"`   // payments per year, 0 for one-time
+	Quantity       float64   `json:"quantity"`    // signed shares held at the ex-dividend date
+	Amount         float64   `json:"amount"`      // signed cash: positive received, negative paid
+	Status         string    `json:"status"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// DividendProjection is the forward annual income of an open share position at its
+// ticker's latest regular dividend rate
+type DividendProjection struct {
+	PositionID   int64    `json:"position_id"`
+	Ticker       string   `json:"ticker"`
+	Quantity     float64  `json:"quantity"`    // signed
+	CashAmount   float64  `json:"cash_amount"` // latest regular dividend per share
+	Frequency    int      `json:"frequency"`
+	AnnualIncome float64  `json:"annual_income"` // signed: negative when short
+	Price        *float64 `json:"price,omitempty"`
+	Yield        *float64 `json:"yield,omitempty"` // annual dividend over price, as a fraction
+}
+
+// PortfolioDividends is a portfolio's dividend history and projected income
+type PortfolioDividends struct {
+	PortfolioID           int64                `json:"portfolio_id"`
+	Received              float64              `json:"received"`  // paid dividends, net of dividends owed on short shares
+	Announced             float64              `json:"announced"` // declared but not yet paid
+	ProjectedAnnualIncome float64              `json:"projected_annual_income"`
+	Projections           []DividendProjection `json:"projections"`
+	Dividends             []Dividend           `json:"dividends"` // newest ex-dividend date first
+}
@@ -0,0 +1,10 @@
+package models
+
+// Expiration records how an expired option leg was settled: the closing ledger transaction
+// and, for assignments and exercises, the share trades delivered at the strike
+type Expiration struct {
+	Position        Position      `json:"position"`
+	UnderlyingClose float64       `json:"underlying_close"`
+	Transaction     Transaction   `json:"transaction"`
+	Deliveries      []Transaction `json:"deliveries,omitempty"`
+}
@@ -0,0 +1,55 @@
+package models
+
+// Import row statuses
+const (
+	ImportRowOK      = "ok"
+	ImportRowError   = "error"
+	ImportRowSkipped = "skipped" // not a trade, e.g. a dividend or transfer in a broker export
+)
+
+// ImportedTrade is a trade parsed from an import file. Opens describe the new position;
+// adds and closes identify an open position by ticker and, optionally, side. Broker
+// exports do not distinguish opening from adding, so their opens set AddToOpen and scale
+// into an open position in the same ticker and side when there is one.
+type ImportedTrade struct {
+	Row              int      `json:"row"`    // 1-based line number in the file, header included
+	Action           string   `json:"action"` // open, add or close
+	AssetType        string   `json:"asset_type"`
+	Ticker           string   `json:"ticker"`
+	UnderlyingTicker string   `json:"underlying_ticker"`
+	ContractType     *string  `json:"contract_type,omitempty"`
+	StrikePrice      *float64 `json:"strike_price,omitempty"`
+	ExpirationDate   *string  `json:"expiration_date,omitempty"`
+	Side             string   `json:"side,omitempty"`
+	Quantity         float64  `json:"quantity"`
+	Price            float64  `json:"price"`
+	Fees             float64  `json:"fees"`
+	Multiplier       int      `json:"multiplier"`
+	TradedAt         string   `json:"traded_at"`
+	AddToOpen        bool     `json:"-"`
+}
+
+// ImportRowResult reports what happened to a single import row
+type ImportRowResult struct {
+	Row           int      `json:"row"`
+	Status        string   `json:"status"` // "ok", "error" or "skipped"
+	Action        string   `json:"action,omitempty"`
+	Ticker        string   `json:"ticker,omitempty"`
+	PositionID    *int64   `json:"position_id,omitempty"`
+	TransactionID *int64   `json:"transaction_id,omitempty"`
+	RealizedPnL   *float64 `json:"realized_pnl,omitempty"`
+	Error         string   `json:"error,omitempty"`
+	Reason        string   `json:"reason,omitempty"` // why a row was skipped
+}
+
+// ImportResult summarizes an import. Nothing is written unless every row succeeds
+// and the import is not a dry run.
+type ImportResult struct {
+	Format    string            `json:"format"`
+	DryRun    bool              `json:"dry_run"`
+	Committed bool              `json:"committed"`
+	Rows      int               `json:"rows"`
+	Errors    int               `json:"errors"`
+	Skipped   int               `json:"skipped"`
+	Results   []ImportRowResult `json:"results"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Journal entry kinds
+const (
+	JournalThesis = "thesis" // why a trade was entered
+	JournalExit   = "exit"   // why it was closed
+	JournalNote   = "note"
+)
+
+// JournalAttachment links a file, such as a chart screenshot, to a journal entry
+type JournalAttachment struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// JournalEntry is a trade journal note, optionally attached to a position or transaction
+type JournalEntry struct {
+	ID            int64               `json:"id"`
+	PortfolioID   int64               `json:"portfolio_id"`
+	PositionID    *int64              `json:"position_id,omitempty"`
+	TransactionID *int64              `json:"transaction_id,omitempty"`
+	Kind          string              `json:"kind"`
+	Title         *string             `json:"title,omitempty"`
+	Body          string              `json:"body"`
+	Tags          []string            `json:"tags"`
+	Attachments   []JournalAttachment `json:"attachments"`
+	Rank          *float64            `json:"rank,omitempty"`    // search relevance
+	Snippet       *string             `json:"snippet,omitempty"` // body excerpt with matches in <b> tags
+	CreatedAt     time.Time           `json:"created_at"`
+	UpdatedAt     time.Time           `json:"updated_at"`
+}
+
+// JournalFilter selects journal entries; zero values match everything
+type JournalFilter struct {
+	Query      string // full-text search in web search syntax: words, "phrases", or, -exclusions
+	Tag        string
+	Kind       string
+	PositionID int64
+	Limit      int
+}
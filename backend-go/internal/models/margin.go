@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+// Margin strategies: how a position, or part of one, is grouped for its requirement
+const (
+	MarginLongStock   = "long_stock"   // 50% of market value
+	MarginShortStock  = "short_stock"  // 50% of market value beyond the sale proceeds
+	MarginLongOption  = "long_option"  // paid in full
+	MarginCoveredCall = "covered_call" // short call against 100 long shares per contract
+	MarginCoveredPut  = "covered_put"  // short put against 100 short shares per contract
+	MarginSpread      = "spread"       // short option paired with a long option of the same type
+	MarginNakedCall   = "naked_call"   // uncovered short call
+	MarginNakedPut    = "naked_put"    // uncovered short put
+)
+
+// MarginRequirement is the Reg-T requirement of one strategy. Tickers lists its legs, short
+// leg first for covered positions and spreads; Quantity is in contracts, or shares for stock.
+type MarginRequirement struct {
+	Strategy    string   `json:"strategy"`
+	Tickers     []string `json:"tickers"`
+	PositionIDs []int64  `json:"position_ids"` // open positions only: proposed trades have none
+	Quantity    float64  `json:"quantity"`
+	Amount      float64  `json:"amount"`
+}
+
+// UnderlyingMargin is the requirement of the positions on one underlying under Reg-T
+// strategy rules and under a portfolio margin scan of its price
+type UnderlyingMargin struct {
+	Underlying      string              `json:"underlying"`
+	UnderlyingPrice float64             `json:"underlying_price"`
+	RegT            float64             `json:"reg_t"`
+	PortfolioMargin float64             `json:"portfolio_margin"`
+	WorstMove       float64             `json:"worst_move"` // scan move with the largest loss, e.g. -0.15
+	Requirements    []MarginRequirement `json:"requirements"`
+}
+
+// MarginSummary totals the requirements of a set of positions. Buying power is the account
+// equity left after the requirement and is only reported when the equity is known.
+type MarginSummary struct {
+	RegT                       float64            `json:"reg_t"`
+	PortfolioMargin            float64            `json:"portfolio_margin"`
+	RegTBuyingPower            *float64           `json:"reg_t_buying_power,omitempty"`
+	PortfolioMarginBuyingPower *float64           `json:"portfolio_margin_buying_power,omitempty"`
+	Underlyings                []UnderlyingMargin `json:"underlyings"`
+	Unpriced                   []string           `json:"unpriced"` // underlyings left out for lack of a price
+}
+
+// MarginEstimate compares a portfolio's margin requirement before and after proposed trades
+type MarginEstimate struct {
+	PortfolioID           int64          `json:"portfolio_id"`
+	AccountEquity         *float64       `json:"account_equity,omitempty"`
+	Current               MarginSummary  `json:"current"`
+	WithTrades            *MarginSummary `json:"with_trades,omitempty"`
+	RegTChange            *float64       `json:"reg_t_change,omitempty"`
+	PortfolioMarginChange *float64       `json:"portfolio_margin_change,omitempty"`
+	AsOf                  time.Time      `json:"as_of"`
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// occPattern matches OCC option symbols with an optional Massive "O:" prefix,
+// e.g. O:AAPL250117C00150000 → AAPL, 2025-01-17, call, 150.000
+var occPattern = regexp.MustCompile(`^(?:O:)?([A-Z0-9.]{1,6})(\d{6})([CP])(\d{8})$`)
+
+// OptionSymbol is a decoded OCC option symbol
+type OptionSymbol struct {
+	Underlying     string
+	ExpirationDate string // YYYY-MM-DD
+	ContractType   string // "call" or "put"
+	StrikePrice    float64
+}
+
+// ParseOptionTicker decodes an OCC option symbol
+func ParseOptionTicker(ticker string) (*OptionSymbol, error) {
+	m := occPattern.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(ticker)))
+	if m == nil {
+		return nil, fmt.Errorf("invalid option ticker %q", ticker)
+	}
+
+	exp, err := time.Parse("060102", m[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid expiration in option ticker %q", ticker)
+	}
+
+	strike, err := strconv.ParseInt(m[4], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid strike in option ticker %q", ticker)
+	}
+
+	contractType := "call"
+	if m[3] == "P" {
+		contractType = "put"
+	}
+
+	return &OptionSymbol{
+		Underlying:     m[1],
+		ExpirationDate: exp.Format("2006-01-02"),
+		ContractType:   contractType,
+		StrikePrice:    float64(strike) / 1000.0,
+	}, nil
+}
+
+// Ticker formats the symbol in Massive's O:-prefixed OCC form
+func (s *OptionSymbol) Ticker() string {
+	exp, _ := time.Parse("2006-01-02", s.ExpirationDate)
+	cp := "C"
+	if s.ContractType == "put" {
+		cp = "P"
+	}
+	return fmt.Sprintf("O:%s%s%s%08d", s.Underlying, exp.Format("060102"), cp, int64(s.StrikePrice*1000+0.5))
+}
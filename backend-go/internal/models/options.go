@@ -1,11 +1,17 @@
 package models
 
+//go:generate go run github.com/tinylib/msgp@v1.4.0 -tests=false -io=false
+//msgp:tag json
+
 // OptionsChainResponse represents the response from Massive API
 type OptionsChainResponse struct {
 	Status    string           `json:"status"`
 	RequestID string           `json:"request_id"`
 	Results   []OptionContract `json:"results"`
 	NextURL   *string          `json:"next_url,omitempty"`
+
+	// Set by Periscope on a paged chain to fetch the next page; empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 // OptionContract represents a single options contract with all market data
@@ -19,6 +25,9 @@ type OptionContract struct {
 	Day              *DayBar          `json:"day,omitempty"`
 	Session          *Session         `json:"session,omitempty"`
 	UnderlyingAsset  *UnderlyingAsset `json:"underlying_asset,omitempty"`
+
+	// Computed by Periscope (not part of the Massive payload)
+	LiquidityScore *float64 `json:"liquidity_score,omitempty"`
 }
 
 // ContractDetails contains the contract specifications
@@ -38,6 +47,11 @@ type Greeks struct {
 	Theta *float64 `json:"theta,omitempty"`
 	Vega  *float64 `json:"vega,omitempty"`
 	Rho   *float64 `json:"rho,omitempty"`
+
+	// Second-order greeks, computed by Periscope on request
+	Vanna *float64 `json:"vanna,omitempty"`
+	Charm *float64 `json:"charm,omitempty"`
+	Vomma *float64 `json:"vomma,omitempty"`
 }
 
 // LastQuote contains the most recent bid/ask data
@@ -0,0 +1,106 @@
+package models
+
+//go:generate go run github.com/tinylib/msgp@v1.4.0 -tests=false -io=false
+//msgp:tag json
+//msgp:ignore OptionsSnapshot OptionsSnapshotContract
+
+import "time"
+
+// OptionsSnapshot is a stored capture of an underlying's whole options chain at one moment
+type OptionsSnapshot struct {
+	ID         int64     `json:"id"`
+	Ticker     string    `json:"ticker"`
+	CapturedAt time.Time `json:"captured_at"`
+	Spot       float64   `json:"spot"`
+	Contracts  int       `json:"contracts"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// OptionsSnapshotContract is a contract's quote, greeks and activity in a stored snapshot
+type OptionsSnapshotContract struct {
+	SnapshotID     int64     `json:"snapshot_id"`
+	CapturedAt     time.Time `json:"captured_at"`
+	Underlying     string    `json:"underlying"`
+	Ticker         string    `json:"ticker"`
+	ContractType   string    `json:"contract_type"`
+	StrikePrice    float64   `json:"strike_price"`
+	ExpirationDate string    `json:"expiration_date"`
+	Bid            *float64  `json:"bid,omitempty"`
+	Ask            *float64  `json:"ask,omitempty"`
+	LastPrice      *float64  `json:"last_price,omitempty"`
+	Volume         *int64    `json:"volume,omitempty"`
+	OpenInterest   *int64    `json:"open_interest,omitempty"`
+	ImpliedVol     *float64  `json:"implied_volatility,omitempty"`
+	Delta          *float64  `json:"delta,omitempty"`
+	Gamma          *float64  `json:"gamma,omitempty"`
+	Theta          *float64  `json:"theta,omitempty"`
+	Vega           *float64  `json:"vega,omitempty"`
+	Rho            *float64  `json:"rho,omitempty"`
+}
+
+// NewOptionsSnapshotContract flattens a contract from a live chain for storage. Contracts
+// missing their ticker, type, strike or expiration cannot be stored and return false.
+func NewOptionsSnapshotContract(underlying string, capturedAt time.Time, c *OptionContract) (OptionsSnapshotContract, bool) {
+	d := c.Details
+	if d == nil || d.Ticker == nil || d.ContractType == nil || d.StrikePrice == nil || d.ExpirationDate == nil {
+		return OptionsSnapshotContract{}, false
+	}
+
+	row := OptionsSnapshotContract{
+		CapturedAt:     capturedAt,
+		Underlying:     underlying,
+		Ticker:         *d.Ticker,
+		ContractType:   *d.ContractType,
+		StrikePrice:    *d.StrikePrice,
+		ExpirationDate: *d.ExpirationDate,
+		OpenInterest:   c.OpenInterest,
+		ImpliedVol:     c.ImpliedVol,
+	}
+	if q := c.LastQuote; q != nil {
+		row.Bid, row.Ask = q.Bid, q.Ask
+	}
+	if t := c.LastTrade; t != nil {
+		row.LastPrice = t.Price
+	}
+	if day := c.Day; day != nil {
+		row.Volume = day.Volume
+	}
+	if g := c.Greeks; g != nil {
+		row.Delta, row.Gamma, row.Theta, row.Vega, row.Rho = g.Delta, g.Gamma, g.Theta, g.Vega, g.Rho
+	}
+	return row, true
+}
+
+// OptionContract returns the stored contract in the shape of a live chain's contracts
+func (c *OptionsSnapshotContract) OptionContract() OptionContract {
+	ticker, contractType, strike, expiration := c.Ticker, c.ContractType, c.StrikePrice, c.ExpirationDate
+	contract := OptionContract{
+		Details: &ContractDetails{
+			Ticker:         &ticker,
+			ContractType:   &contractType,
+			StrikePrice:    &strike,
+			ExpirationDate: &expiration,
+		},
+		Greeks:       &Greeks{Delta: c.Delta, Gamma: c.Gamma, Theta: c.Theta, Vega: c.Vega, Rho: c.Rho},
+		ImpliedVol:   c.ImpliedVol,
+		OpenInterest: c.OpenInterest,
+	}
+	if c.Bid != nil || c.Ask != nil {
+		contract.LastQuote = &LastQuote{Bid: c.Bid, Ask: c.Ask}
+	}
+	if c.LastPrice != nil {
+		contract.LastTrade = &LastTrade{Price: c.LastPrice}
+	}
+	if c.Volume != nil {
+		contract.Day = &DayBar{Volume: c.Volume}
+	}
+	return contract
+}
+
+// HistoricalChainResponse is a chain as it was stored in a snapshot
+type HistoricalChainResponse struct {
+	Ticker          string           `json:"ticker"`
+	CapturedAt      time.Time        `json:"captured_at"`
+	UnderlyingPrice float64          `json:"underlying_price"`
+	Results         []OptionContract `json:"results"`
+}
@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// Paper order instructions
+const (
+	BuyToOpen   = "buy_to_open"   // opens or adds to a long position
+	SellToOpen  = "sell_to_open"  // opens or adds to a short position
+	BuyToClose  = "buy_to_close"  // reduces a short position
+	SellToClose = "sell_to_close" // reduces a long position
+)
+
+// Paper order statuses
+const (
+	OrderFilled   = "filled"
+	OrderRejected = "rejected"
+)
+
+// PaperOrder is a simulated order of a paper portfolio, filled against the live quote.
+// A filled order is recorded in the ledger like a real trade.
+type PaperOrder struct {
+	ID             int64     `json:"id"`
+	PortfolioID    int64     `json:"portfolio_id"`
+	AssetType      string    `json:"asset_type"`
+	Ticker         string    `json:"ticker"`
+	Instruction    string    `json:"instruction"`
+	Quantity       float64   `json:"quantity"`
+	LimitPrice     *float64  `json:"limit_price,omitempty"`
+	Status         string    `json:"status"`
+	Bid            *float64  `json:"bid,omitempty"`
+	Ask            *float64  `json:"ask,omitempty"`
+	ReferencePrice float64   `json:"reference_price"` // mid, or the last price without a two-sided quote
+	SlippageBps    float64   `json:"slippage_bps"`
+	FillPrice      *float64  `json:"fill_price,omitempty"`
+	Fees           float64   `json:"fees"`
+	RejectReason   *string   `json:"reject_reason,omitempty"`
+	PositionID     *int64    `json:"position_id,omitempty"`
+	TransactionID  *int64    `json:"transaction_id,omitempty"`
+	Position       *Position `json:"position,omitempty"` // the position after the fill
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// Opens reports whether the order opens or adds to a position
+func (o *PaperOrder) Opens() bool {
+	return o.Instruction == BuyToOpen || o.Instruction == SellToOpen
+}
+
+// Buys reports whether the order buys
+func (o *PaperOrder) Buys() bool {
+	return o.Instruction == BuyToOpen || o.Instruction == BuyToClose
+}
+
+// PositionSide returns the side of the position the order opens or closes
+func (o *PaperOrder) PositionSide() string {
+	if o.Instruction == BuyToOpen || o.Instruction == SellToClose {
+		return SideLong
+	}
+	return SideShort
+}
@@ -0,0 +1,32 @@
+package models
+
+// PerformancePoint is one trading day of a portfolio's return series. Returns are
+// cumulative from the first day of the range, as fractions (0.05 = 5%).
+type PerformancePoint struct {
+	Date            string   `json:"date"`
+	Equity          float64  `json:"equity"`
+	NetFlow         float64  `json:"net_flow"` // capital added (positive) or withdrawn (negative) that day
+	PnL             float64  `json:"pnl"`      // change in total P/L that day
+	TWR             float64  `json:"twr"`
+	MWR             *float64 `json:"mwr"` // Modified Dietz, nil when invested capital was not positive
+	BenchmarkReturn *float64 `json:"benchmark_return"`
+}
+
+// PortfolioPerformance is a portfolio's time-weighted and money-weighted returns over a
+// range of snapshots, compared with the benchmark. Annualized figures are only reported for
+// ranges of at least a year.
+type PortfolioPerformance struct {
+	PortfolioID         int64              `json:"portfolio_id"`
+	Range               string             `json:"range"`
+	From                string             `json:"from"`
+	To                  string             `json:"to"`
+	Benchmark           string             `json:"benchmark"`
+	TWR                 float64            `json:"twr"`
+	MWR                 *float64           `json:"mwr"`
+	BenchmarkReturn     *float64           `json:"benchmark_return"`
+	AnnualizedTWR       *float64           `json:"annualized_twr,omitempty"`
+	AnnualizedMWR       *float64           `json:"annualized_mwr,omitempty"` // internal rate of return
+	AnnualizedBenchmark *float64           `json:"annualized_benchmark,omitempty"`
+	UndefinedDays       int                `json:"undefined_days"` // days left out of the TWR for lack of positive capital
+	Points              []PerformancePoint `json:"points"`
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// Portfolio account types
+const (
+	AccountTaxable = "taxable"
+	AccountIRA     = "ira"
+	AccountRothIRA = "roth_ira"
+	AccountMargin  = "margin"
+	AccountPaper   = "paper"
+	AccountOther   = "other"
+)
+
+// Portfolio is a named collection of positions, such as a brokerage account
+type Portfolio struct {
+	ID          int64             `json:"id"`
+	UserID      *string           `json:"user_id,omitempty"`
+	Name        string            `json:"name"`
+	Description *string           `json:"description,omitempty"`
+	Settings    PortfolioSettings `json:"settings"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+	DeletedAt   *time.Time        `json:"deleted_at,omitempty"` // set while it is deleted and restorable
+}
+
+// PortfolioSettings are per-portfolio preferences
+type PortfolioSettings struct {
+	AccountType     string `json:"account_type"`
+	IncludeInRollup bool   `json:"include_in_rollup"` // counted in the all-accounts rollup
+}
+
+// DefaultPortfolioSettings returns the settings of a newly created portfolio
+func DefaultPortfolioSettings() PortfolioSettings {
+	return PortfolioSettings{
+		AccountType:     AccountTaxable,
+		IncludeInRollup: true,
+	}
+}
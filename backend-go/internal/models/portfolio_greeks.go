@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// Greek sources for a position
+const (
+	GreeksFromSnapshot = "snapshot" // reported by the options snapshot
+	GreeksFromModel    = "model"    // computed from the contract's implied volatility
+	GreeksFromStock    = "stock"    // shares: delta of one per share
+	GreeksPartial      = "partial"  // some greeks unavailable
+	GreeksMissing      = "missing"  // no snapshot data or pricing inputs
+)
+
+// PositionGreeks are a position's greeks scaled by its signed quantity and multiplier:
+// delta and gamma in share equivalents, theta in dollars per day, vega in dollars per vol point
+type PositionGreeks struct {
+	PositionID        int64    `json:"position_id"`
+	Ticker            string   `json:"ticker"`
+	UnderlyingTicker  string   `json:"underlying_ticker"`
+	Quantity          float64  `json:"quantity"` // signed: negative for short
+	UnderlyingPrice   *float64 `json:"underlying_price,omitempty"`
+	Beta              *float64 `json:"beta,omitempty"`
+	Source            string   `json:"source"`
+	Delta             *float64 `json:"delta"`
+	Gamma             *float64 `json:"gamma"`
+	Theta             *float64 `json:"theta"`
+	Vega              *float64 `json:"vega"`
+	BetaWeightedDelta *float64 `json:"beta_weighted_delta,omitempty"`
+	BetaWeightedGamma *float64 `json:"beta_weighted_gamma,omitempty"`
+}
+
+// PortfolioGreeks totals position greeks across a portfolio's open legs. Beta-weighted
+// figures are expressed in benchmark share equivalents.
+type PortfolioGreeks struct {
+	PortfolioID       int64            `json:"portfolio_id"`
+	Benchmark         string           `json:"benchmark"`
+	BenchmarkPrice    *float64         `json:"benchmark_price"`
+	Delta             float64          `json:"delta"`
+	DollarDelta       float64          `json:"dollar_delta"`
+	Gamma             float64          `json:"gamma"`
+	Theta             float64          `json:"theta"`
+	Vega              float64          `json:"vega"`
+	BetaWeightedDelta float64          `json:"beta_weighted_delta"`
+	BetaWeightedGamma float64          `json:"beta_weighted_gamma"`
+	Incomplete        int              `json:"incomplete"` // positions with partial or missing greeks
+	Unweighted        int              `json:"unweighted"` // positions left out of the beta-weighted totals
+	Positions         []PositionGreeks `json:"positions,omitempty"`
+	AsOf              time.Time        `json:"as_of"`
+}
@@ -0,0 +1,74 @@
+package models
+
+import "time"
+
+// Asset types held in a portfolio
+const (
+	AssetTypeOption = "option"
+	AssetTypeStock  = "stock"
+)
+
+// Position sides
+const (
+	SideLong  = "long"
+	SideShort = "short"
+)
+
+// Position statuses
+const (
+	PositionOpen   = "open"
+	PositionClosed = "closed"
+)
+
+// Reasons an option leg was closed by its expiration rather than a trade
+const (
+	CloseReasonExpired   = "expired"
+	CloseReasonAssigned  = "assigned"
+	CloseReasonExercised = "exercised"
+)
+
+// Position is an option leg or share lot held in a portfolio
+type Position struct {
+	ID               int64     `json:"id"`
+	PortfolioID      int64     `json:"portfolio_id"`
+	AssetType        string    `json:"asset_type"` // "option" or "stock"
+	Ticker           string    `json:"ticker"`     // OCC symbol for options, stock symbol for shares
+	UnderlyingTicker string    `json:"underlying_ticker"`
+	ContractType     *string   `json:"contract_type,omitempty"`
+	StrikePrice      *float64  `json:"strike_price,omitempty"`
+	ExpirationDate   *string   `json:"expiration_date,omitempty"`
+	Side             string    `json:"side"`       // "long" or "short"
+	Quantity         float64   `json:"quantity"`   // open contracts or shares, zero once closed
+	Multiplier       int       `json:"multiplier"` // shares per contract (1 for stock)
+	OpenPrice        float64   `json:"open_price"` // FIFO average premium of the open lots
+	OpenedAt         string    `json:"opened_at"`  // YYYY-MM-DD
+	Status           string    `json:"status"`
+	ClosePrice       *float64  `json:"close_price,omitempty"`
+	ClosedAt         *string   `json:"closed_at,omitempty"`
+	CloseReason      *string   `json:"close_reason,omitempty"` // expired, assigned or exercised
+	Fees             float64   `json:"fees"`                   // total fees recorded in the ledger
+	RealizedPnL      float64   `json:"realized_pnl"`           // FIFO P/L realized by closing transactions, net of fees
+	Dividends        float64   `json:"dividends"`              // paid dividends: received when long, paid when short
+	StrategyID       *int64    `json:"strategy_id,omitempty"`  // strategy the position is grouped into
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// SignedQuantity returns the quantity as positive for long and negative for short positions
+func (p *Position) SignedQuantity() float64 {
+	if p.Side == SideShort {
+		return -p.Quantity
+	}
+	return p.Quantity
+}
+
+// CostBasis returns the signed opening value: paid for long positions (positive),
+// received for short positions (negative)
+func (p *Position) CostBasis() float64 {
+	return p.SignedQuantity() * p.OpenPrice * float64(p.Multiplier)
+}
+
+// IsOption reports whether the position is an option leg
+func (p *Position) IsOption() bool {
+	return p.AssetType == AssetTypeOption
+}
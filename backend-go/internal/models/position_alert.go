@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+// Position alert metrics. Greeks are per share and signed by side, so a short put with a
+// -0.40 contract delta has a delta of 0.40. Percent P/L is relative to the absolute cost basis.
+const (
+	AlertMetricDelta             = "delta"
+	AlertMetricGamma             = "gamma"
+	AlertMetricTheta             = "theta"
+	AlertMetricVega              = "vega"
+	AlertMetricImpliedVolatility = "implied_volatility"
+	AlertMetricMarkPrice         = "mark_price"
+	AlertMetricUnderlyingPrice   = "underlying_price"
+	AlertMetricPnL               = "pnl"
+	AlertMetricPnLPercent        = "pnl_percent"
+	AlertMetricDaysToExpiration  = "days_to_expiration"
+)
+
+// Position alert statuses
+const (
+	AlertArmed     = "armed"     // evaluated on every run
+	AlertTriggered = "triggered" // fired; not evaluated until re-armed
+	AlertDisabled  = "disabled"  // paused by the user
+)
+
+// PositionAlert is a threshold rule on one of a position's metrics, such as
+// "delta > 0.40" or "pnl_percent < -50"
+type PositionAlert struct {
+	ID              int64      `json:"id"`
+	PortfolioID     int64      `json:"portfolio_id"`
+	PositionID      int64      `json:"position_id"`
+	Metric          string     `json:"metric"`
+	Operator        string     `json:"operator"` // >, >=, < or <=
+	Threshold       float64    `json:"threshold"`
+	Note            *string    `json:"note,omitempty"`
+	Status          string     `json:"status"`
+	LastValue       *float64   `json:"last_value"`
+	LastEvaluatedAt *time.Time `json:"last_evaluated_at"`
+	TriggeredValue  *float64   `json:"triggered_value,omitempty"`
+	TriggeredAt     *time.Time `json:"triggered_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// Matches reports whether value crosses the rule's threshold
+func (a *PositionAlert) Matches(value float64) bool {
+	return Compare(a.Operator, value, a.Threshold)
+}
+
+// Compare reports whether value satisfies "value <operator> threshold" for the >, >=, <
+// and <= operators
+func Compare(operator string, value, threshold float64) bool {
+	switch operator {
+	case ">":
+		return value > threshold
+	case ">=":
+		return value >= threshold
+	case "<":
+		return value < threshold
+	case "<=":
+		return value <= threshold
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package models
+
+import "time"
+
+// Volatility sources for an underlying's risk estimate
+const (
+	VolFromImplied    = "implied"    // average implied volatility of the portfolio's option legs
+	VolFromHistorical = "historical" // one year of daily closes
+)
+
+// UnderlyingExposure sums a portfolio's open positions on one underlying. Delta is in
+// shares and dollar delta in dollars per 1.00 return; dollar gamma is gamma × price², so a
+// return r adds ½·dollar_gamma·r² of P/L. Theta is in dollars per day and vega in dollars
+// per vol point.
+type UnderlyingExposure struct {
+	Ticker      string   `json:"ticker"`
+	Price       *float64 `json:"price"`
+	Beta        *float64 `json:"beta,omitempty"`
+	Volatility  *float64 `json:"volatility"` // annualized, nil when unavailable
+	VolSource   string   `json:"vol_source,omitempty"`
+	Positions   int      `json:"positions"`
+	Delta       float64  `json:"delta"`
+	DollarDelta float64  `json:"dollar_delta"`
+	DollarGamma float64  `json:"dollar_gamma"`
+	Theta       float64  `json:"theta"`
+	Vega        float64  `json:"vega"`
+}
+
+// ValueAtRisk is a parametric loss estimate: the loss not expected to be exceeded at the
+// given confidence over the horizon
+type ValueAtRisk struct {
+	Confidence  float64 `json:"confidence"`
+	HorizonDays int     `json:"horizon_days"`
+	VaR         float64 `json:"var"`     // positive number, dollars
+	StdDev      float64 `json:"std_dev"` // of the delta P/L, dollars
+	Drift       float64 `json:"drift"`   // expected gamma and theta P/L over the horizon, dollars
+}
+
+// StressScenario is the P/L of the open positions under a spot and/or implied volatility shock
+type StressScenario struct {
+	Name      string  `json:"name"`
+	SpotShock float64 `json:"spot_shock"` // relative move applied to every underlying, e.g. -0.05
+	IVShock   float64 `json:"iv_shock"`   // relative change applied to every implied volatility, e.g. 0.20
+	PnL       float64 `json:"pnl"`
+	Unpriced  int     `json:"unpriced"` // positions left out for lack of prices or greeks
+}
+
+// PortfolioRisk summarizes a portfolio's market risk: beta-weighted exposure, parametric
+// VaR and stress test P/L
+type PortfolioRisk struct {
+	PortfolioID             int64                `json:"portfolio_id"`
+	Benchmark               string               `json:"benchmark"`
+	BenchmarkPrice          *float64             `json:"benchmark_price"`
+	BenchmarkVolatility     *float64             `json:"benchmark_volatility"`
+	BetaWeightedDelta       float64              `json:"beta_weighted_delta"`        // benchmark share equivalents
+	BetaWeightedDollarDelta float64              `json:"beta_weighted_dollar_delta"` // dollars per 1.00 benchmark return
+	BetaWeightedGamma       float64              `json:"beta_weighted_gamma"`
+	VaR                     []ValueAtRisk        `json:"var"`
+	Scenarios               []StressScenario     `json:"scenarios"`
+	Underlyings             []UnderlyingExposure `json:"underlyings"`
+	Excluded                int                  `json:"excluded"` // underlyings left out of VaR for lack of price or volatility
+	AsOf                    time.Time            `json:"as_of"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// Reasons a short option leg is threatened
+const (
+	RollThreatInTheMoney = "in_the_money"
+	RollThreatHighDelta  = "high_delta"
+	RollThreatExpiring   = "expiring"
+)
+
+// RollCandidate is a contract a short leg could be rolled into. Prices are per-share mids;
+// the net credit is the candidate's price less the cost to close the current leg, and the
+// delta reduction is the drop in absolute per-share delta.
+type RollCandidate struct {
+	Ticker         string   `json:"ticker"`
+	ExpirationDate string   `json:"expiration_date"`
+	StrikePrice    float64  `json:"strike_price"`
+	DTE            int      `json:"dte"`
+	Price          float64  `json:"price"`
+	Delta          *float64 `json:"delta"`
+	NetCredit      float64  `json:"net_credit"`
+	NetCreditTotal float64  `json:"net_credit_total"` // net credit × quantity × multiplier
+	DeltaReduction *float64 `json:"delta_reduction"`
+	AddedDays      int      `json:"added_days"`
+	LiquidityScore *float64 `json:"liquidity_score,omitempty"`
+	Score          float64  `json:"score"` // 0-100
+}
+
+// RollSuggestions ranks roll candidates for a short option leg, best first
+type RollSuggestions struct {
+	PositionID       int64           `json:"position_id"`
+	Ticker           string          `json:"ticker"`
+	UnderlyingTicker string          `json:"underlying_ticker"`
+	ContractType     string          `json:"contract_type"`
+	StrikePrice      float64         `json:"strike_price"`
+	ExpirationDate   string          `json:"expiration_date"`
+	DTE              int             `json:"dte"`
+	Quantity         float64         `json:"quantity"`
+	UnderlyingPrice  float64         `json:"underlying_price"`
+	ClosePrice       *float64        `json:"close_price"`
+	Delta            *float64        `json:"delta"`
+	Threatened       bool            `json:"threatened"`
+	Threats          []string        `json:"threats"`
+	Candidates       []RollCandidate `json:"candidates"`
+	AsOf             time.Time       `json:"as_of"`
+}
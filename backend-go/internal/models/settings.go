@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// Greeks display modes
+const (
+	GreeksPerShare    = "per_share"    // as quoted on the chain
+	GreeksPerContract = "per_contract" // scaled by the 100-share contract multiplier
+)
+
+// DefaultAlertCooldownSeconds is how long a recurring alert waits before re-arming unless the
+// user or the alert says otherwise
+const DefaultAlertCooldownSeconds = 3600
+
+// UserSettings are a user's preferences: display defaults the frontend applies to the options
+// chain and greeks, the theme, and the mode and cooldown new alerts get when none is given
+type UserSettings struct {
+	UserID               *string    `json:"user_id,omitempty"`
+	ExpirationWindowDays int        `json:"expiration_window_days"` // expirations shown on the chain by default
+	GreeksDisplay        string     `json:"greeks_display"`
+	SecondOrderGreeks    bool       `json:"second_order_greeks"`
+	RiskFreeRate         *float64   `json:"risk_free_rate"` // nil uses the server's rate
+	Theme                string     `json:"theme"`
+	AlertMode            string     `json:"alert_mode"`
+	AlertCooldownSeconds int        `json:"alert_cooldown_seconds"`
+	UpdatedAt            *time.Time `json:"updated_at,omitempty"`
+}
+
+// DefaultUserSettings returns the settings of a user who has not saved any
+func DefaultUserSettings(userID *string) *UserSettings {
+	return &UserSettings{
+		UserID:               userID,
+		ExpirationWindowDays: 45,
+		GreeksDisplay:        GreeksPerShare,
+		Theme:                "system",
+		AlertMode:            AlertOneShot,
+		AlertCooldownSeconds: DefaultAlertCooldownSeconds,
+	}
+}
@@ -0,0 +1,70 @@
+package models
+
+import "time"
+
+// ShareLink is an expiring, revocable read-only link to a portfolio. Token and URL are
+// derived from the link when it is returned to the owner and are not stored.
+type ShareLink struct {
+	ID             int64      `json:"id"`
+	PortfolioID    int64      `json:"portfolio_id"`
+	Label          *string    `json:"label,omitempty"`
+	HideCostBasis  bool       `json:"hide_cost_basis"`
+	ExpiresAt      time.Time  `json:"expires_at"`
+	RevokedAt      *time.Time `json:"revoked_at,omitempty"`
+	AccessCount    int        `json:"access_count"`
+	LastAccessedAt *time.Time `json:"last_accessed_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	Token          string     `json:"token,omitempty"`
+	URL            string     `json:"url,omitempty"`
+}
+
+// Active reports whether the link can still be used
+func (l *ShareLink) Active(now time.Time) bool {
+	return l.RevokedAt == nil && now.Before(l.ExpiresAt)
+}
+
+// SharedTotals are a shared portfolio's totals; cost and P/L fields are omitted when the
+// link hides cost basis
+type SharedTotals struct {
+	MarketValue   float64  `json:"market_value"`
+	CostBasis     *float64 `json:"cost_basis,omitempty"`
+	UnrealizedPnL *float64 `json:"unrealized_pnl,omitempty"`
+	RealizedPnL   *float64 `json:"realized_pnl,omitempty"`
+	Dividends     *float64 `json:"dividends,omitempty"`
+	TotalPnL      *float64 `json:"total_pnl,omitempty"`
+	Fees          *float64 `json:"fees,omitempty"`
+	OpenPositions int      `json:"open_positions"`
+	Unpriced      int      `json:"unpriced"`
+}
+
+// SharedPosition is an open position in a shared view, without internal IDs
+type SharedPosition struct {
+	AssetType            string   `json:"asset_type"`
+	Ticker               string   `json:"ticker"`
+	UnderlyingTicker     string   `json:"underlying_ticker"`
+	ContractType         *string  `json:"contract_type,omitempty"`
+	StrikePrice          *float64 `json:"strike_price,omitempty"`
+	ExpirationDate       *string  `json:"expiration_date,omitempty"`
+	Side                 string   `json:"side"`
+	Quantity             float64  `json:"quantity"`
+	Multiplier           int      `json:"multiplier"`
+	OpenedAt             string   `json:"opened_at"`
+	MarkPrice            *float64 `json:"mark_price"`
+	UnderlyingPrice      *float64 `json:"underlying_price,omitempty"`
+	MarketValue          *float64 `json:"market_value"`
+	OpenPrice            *float64 `json:"open_price,omitempty"`
+	CostBasis            *float64 `json:"cost_basis,omitempty"`
+	UnrealizedPnL        *float64 `json:"unrealized_pnl,omitempty"`
+	UnrealizedPnLPercent *float64 `json:"unrealized_pnl_percent,omitempty"`
+}
+
+// SharedPortfolio is the read-only view of a portfolio exposed by a share link
+type SharedPortfolio struct {
+	Name          string           `json:"name"`
+	Description   *string          `json:"description,omitempty"`
+	HideCostBasis bool             `json:"hide_cost_basis"`
+	Totals        SharedTotals     `json:"totals"`
+	Positions     []SharedPosition `json:"positions"`
+	ValuedAt      time.Time        `json:"valued_at"`
+	ExpiresAt     time.Time        `json:"expires_at"`
+}
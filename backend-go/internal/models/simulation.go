@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// Simulation sources for a position
+const (
+	SimulatedByModel     = "model"     // repriced with Black-Scholes from the contract's implied volatility
+	SimulatedAtIntrinsic = "intrinsic" // shifted past expiration: valued at exercise value
+	SimulatedStock       = "stock"     // shares move one-for-one with the underlying
+	SimulatedUnpriced    = "unpriced"  // no price or pricing inputs
+)
+
+// SimulationInputs is a what-if shift applied to every open position
+type SimulationInputs struct {
+	SpotChange  float64            `json:"spot_change"`            // relative move applied to every underlying, e.g. -0.05
+	SpotChanges map[string]float64 `json:"spot_changes,omitempty"` // per-underlying overrides of spot_change
+	IVChange    float64            `json:"iv_change"`              // relative change in implied volatility, e.g. 0.20
+	DaysForward int                `json:"days_forward"`           // calendar days of time decay
+}
+
+// PositionSimulation is an open position valued today and under the shift. Values and
+// greeks are scaled by the signed quantity and multiplier.
+type PositionSimulation struct {
+	PositionID               int64    `json:"position_id"`
+	Ticker                   string   `json:"ticker"`
+	UnderlyingTicker         string   `json:"underlying_ticker"`
+	Quantity                 float64  `json:"quantity"` // signed: negative for short
+	Source                   string   `json:"source"`
+	UnderlyingPrice          *float64 `json:"underlying_price"`
+	ProjectedUnderlyingPrice *float64 `json:"projected_underlying_price"`
+	CurrentValue             *float64 `json:"current_value"`
+	ProjectedValue           *float64 `json:"projected_value"`
+	PnL                      *float64 `json:"pnl"`
+	Delta                    *float64 `json:"delta"`
+	Gamma                    *float64 `json:"gamma"`
+	Theta                    *float64 `json:"theta"`
+	Vega                     *float64 `json:"vega"`
+}
+
+// PortfolioSimulation totals the projected values, P/L and greeks of a portfolio's open
+// positions under a what-if shift. Unpriced positions are listed but left out of the totals.
+type PortfolioSimulation struct {
+	PortfolioID    int64                `json:"portfolio_id"`
+	Inputs         SimulationInputs     `json:"inputs"`
+	CurrentValue   float64              `json:"current_value"`
+	ProjectedValue float64              `json:"projected_value"`
+	PnL            float64              `json:"pnl"`
+	Delta          float64              `json:"delta"`
+	Gamma          float64              `json:"gamma"`
+	Theta          float64              `json:"theta"`
+	Vega           float64              `json:"vega"`
+	Unpriced       int                  `json:"unpriced"`
+	Positions      []PositionSimulation `json:"positions"`
+	AsOf           time.Time            `json:"as_of"`
+}
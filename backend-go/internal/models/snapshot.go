@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// PortfolioSnapshot is a portfolio's end-of-day valuation and greeks. MarketValue is the
+// portfolio's equity: the net liquidation value of its priced open positions.
+type PortfolioSnapshot struct {
+	PortfolioID int64  `json:"portfolio_id"`
+	Date        string `json:"date"` // trading day, YYYY-MM-DD
+	ValuationTotals
+	Delta             float64   `json:"delta"`
+	DollarDelta       float64   `json:"dollar_delta"`
+	Gamma             float64   `json:"gamma"`
+	Theta             float64   `json:"theta"`
+	Vega              float64   `json:"vega"`
+	BetaWeightedDelta float64   `json:"beta_weighted_delta"`
+	BetaWeightedGamma float64   `json:"beta_weighted_gamma"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// PortfolioHistory is a portfolio's snapshots over a date range, oldest first
+type PortfolioHistory struct {
+	PortfolioID int64               `json:"portfolio_id"`
+	Range       string              `json:"range"`
+	From        string              `json:"from"`
+	To          string              `json:"to"`
+	Snapshots   []PortfolioSnapshot `json:"snapshots"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// Strategy is a named, tagged group of positions in a portfolio, such as the four legs of
+// an iron condor, valued and risk-managed as one
+type Strategy struct {
+	ID          int64     `json:"id"`
+	PortfolioID int64     `json:"portfolio_id"`
+	Name        string    `json:"name"`
+	Tags        []string  `json:"tags"`
+	Notes       *string   `json:"notes,omitempty"`
+	PositionIDs []int64   `json:"position_ids"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// StrategySummary is a strategy with the totals and net greeks of its legs
+type StrategySummary struct {
+	Strategy Strategy         `json:"strategy"`
+	Totals   ValuationTotals  `json:"totals"`
+	Greeks   *PortfolioGreeks `json:"greeks"` // per-position detail omitted
+}
+
+// StrategyValuation is a strategy with every leg marked to market and its greeks
+type StrategyValuation struct {
+	Strategy Strategy `json:"strategy"`
+	ValuationTotals
+	Positions []PositionValuation `json:"positions"`
+	Greeks    *PortfolioGreeks    `json:"greeks"`
+	ValuedAt  time.Time           `json:"valued_at"`
+}
@@ -0,0 +1,51 @@
+package models
+
+// Holding period terms of a realized gain
+const (
+	TermShort = "short" // held one year or less
+	TermLong  = "long"  // held more than one year
+)
+
+// RealizedLot is the portion of a tax lot closed by one closing transaction. Proceeds and
+// cost basis include fees; for short positions the proceeds are the opening premium or sale
+// and the cost basis is the cost to close.
+type RealizedLot struct {
+	TransactionID    int64   `json:"transaction_id"` // closing transaction
+	LotID            int64   `json:"lot_id"`
+	PositionID       int64   `json:"position_id"`
+	Ticker           string  `json:"ticker"`
+	UnderlyingTicker string  `json:"underlying_ticker"`
+	AssetType        string  `json:"asset_type"`
+	Side             string  `json:"side"`
+	Action           string  `json:"action"` // close, roll_out, expire, assign or exercise
+	Quantity         float64 `json:"quantity"`
+	OpenedAt         string  `json:"opened_at"` // YYYY-MM-DD
+	ClosedAt         string  `json:"closed_at"` // YYYY-MM-DD
+	HoldingDays      int     `json:"holding_days"`
+	Term             string  `json:"term"`
+	Proceeds         float64 `json:"proceeds"`
+	CostBasis        float64 `json:"cost_basis"`
+	RealizedPnL      float64 `json:"realized_pnl"`
+	WashSale         bool    `json:"wash_sale"`
+	DisallowedLoss   float64 `json:"disallowed_loss"` // positive, the part of the loss deferred by a wash sale
+}
+
+// RealizedSummary totals realized gains by holding period
+type RealizedSummary struct {
+	ShortTermPnL   float64 `json:"short_term_pnl"`
+	LongTermPnL    float64 `json:"long_term_pnl"`
+	TotalPnL       float64 `json:"total_pnl"`
+	Proceeds       float64 `json:"proceeds"`
+	CostBasis      float64 `json:"cost_basis"`
+	WashSales      int     `json:"wash_sales"`
+	DisallowedLoss float64 `json:"disallowed_loss"`
+}
+
+// TaxLotReport is a portfolio's realized gains over a date range, one row per closed lot
+type TaxLotReport struct {
+	PortfolioID int64           `json:"portfolio_id"`
+	From        string          `json:"from"`
+	To          string          `json:"to"`
+	Summary     RealizedSummary `json:"summary"`
+	Lots        []RealizedLot   `json:"lots"`
+}
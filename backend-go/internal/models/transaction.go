@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+// Ledger transaction actions
+const (
+	TransactionOpen     = "open"     // opens a position
+	TransactionAdd      = "add"      // adds to an open position
+	TransactionClose    = "close"    // partially or fully closes a position
+	TransactionRollOut  = "roll_out" // closes a position as the first half of a roll
+	TransactionRollIn   = "roll_in"  // opens the replacement position of a roll
+	TransactionExpire   = "expire"   // closes an out-of-the-money option leg at expiration
+	TransactionAssign   = "assign"   // closes a short in-the-money option leg that was assigned
+	TransactionExercise = "exercise" // closes a long in-the-money option leg that was exercised
+)
+
+// Transaction is a single fill recorded in a portfolio's trade ledger
+type Transaction struct {
+	ID                   int64        `json:"id"`
+	PortfolioID          int64        `json:"portfolio_id"`
+	PositionID           int64        `json:"position_id"`
+	Action               string       `json:"action"`
+	Quantity             float64      `json:"quantity"`
+	Price                float64      `json:"price"`  // per share / per contract premium
+	Fees                 float64      `json:"fees"`   // commissions and fees for the fill
+	Amount               float64      `json:"amount"` // signed cash flow net of fees (negative = paid)
+	RealizedPnL          *float64     `json:"realized_pnl,omitempty"`
+	RelatedTransactionID *int64       `json:"related_transaction_id,omitempty"` // roll_in -> roll_out, share delivery -> assign/exercise
+	TradedAt             string       `json:"traded_at"`                        // YYYY-MM-DD
+	CreatedAt            time.Time    `json:"created_at"`
+	Closures             []LotClosure `json:"closures,omitempty"`
+}
+
+// IsClosing reports whether the transaction reduces a position
+func (t *Transaction) IsClosing() bool {
+	switch t.Action {
+	case TransactionClose, TransactionRollOut, TransactionExpire, TransactionAssign, TransactionExercise:
+		return true
+	}
+	return false
+}
+
+// Lot is a FIFO cost basis lot opened by an open, add or roll_in transaction
+type Lot struct {
+	ID                int64     `json:"id"`
+	PositionID        int64     `json:"position_id"`
+	TransactionID     int64     `json:"transaction_id"`
+	Quantity          float64   `json:"quantity"`
+	RemainingQuantity float64   `json:"remaining_quantity"`
+	Price             float64   `json:"price"`
+	Fees              float64   `json:"fees"` // opening fees allocated to the whole lot
+	OpenedAt          string    `json:"opened_at"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// LotClosure records the quantity of a lot consumed by a closing transaction
+type LotClosure struct {
+	ID            int64   `json:"id"`
+	TransactionID int64   `json:"transaction_id"`
+	LotID         int64   `json:"lot_id"`
+	Quantity      float64 `json:"quantity"`
+	OpenValue     float64 `json:"open_value"`  // opening cash of the closed quantity, fees included
+	CloseValue    float64 `json:"close_value"` // closing cash of the closed quantity, fees included
+	RealizedPnL   float64 `json:"realized_pnl"`
+}
@@ -0,0 +1,73 @@
+package models
+
+import "time"
+
+// Usage tiers, which set how much upstream API usage a user's monthly quota allows
+const (
+	TierFree      = "free"
+	TierPro       = "pro"
+	TierUnlimited = "unlimited"
+)
+
+// UsageQuota is a tier's monthly allowance of upstream usage; zero limits are unlimited
+type UsageQuota struct {
+	Pages     int64
+	Contracts int64
+}
+
+// UsageTiers are the quotas of each tier
+var UsageTiers = map[string]UsageQuota{
+	TierFree:      {Pages: 5000, Contracts: 50000},
+	TierPro:       {Pages: 100000, Contracts: 1000000},
+	TierUnlimited: {},
+}
+
+// QuotaUsage is a user's upstream usage in the current quota period against their tier's
+// quota. The period is the calendar month (UTC), starting afresh early if an admin resets
+// the quota.
+type QuotaUsage struct {
+	Tier          string    `json:"tier"`
+	PeriodStart   time.Time `json:"period_start"`
+	PeriodEnd     time.Time `json:"period_end"`
+	Pages         int64     `json:"pages"`
+	Contracts     int64     `json:"contracts_detailed"`
+	PageLimit     int64     `json:"page_limit,omitempty"` // omitted when unlimited
+	ContractLimit int64     `json:"contract_limit,omitempty"`
+	Exceeded      bool      `json:"exceeded"`
+}
+
+// UsageDay is a user's upstream usage on one UTC day
+type UsageDay struct {
+	Day       string `json:"day"` // YYYY-MM-DD
+	Pages     int64  `json:"pages"`
+	Contracts int64  `json:"contracts_detailed"`
+	Requests  int64  `json:"requests"`
+}
+
+// QuotaPeriod returns the first day of the quota period containing now, and the start of
+// the next
+func QuotaPeriod(now time.Time) (time.Time, time.Time) {
+	now = now.UTC()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 1, 0)
+}
+
+// Quota returns the user's usage in the quota period containing now. Counts from an earlier
+// period no longer apply.
+func (u *User) Quota(now time.Time) QuotaUsage {
+	start, end := QuotaPeriod(now)
+	quota := UsageTiers[u.Tier]
+	q := QuotaUsage{
+		Tier:          u.Tier,
+		PeriodStart:   start,
+		PeriodEnd:     end,
+		PageLimit:     quota.Pages,
+		ContractLimit: quota.Contracts,
+	}
+	if u.QuotaPeriodStart != nil && u.QuotaPeriodStart.Equal(start) {
+		q.Pages = u.QuotaPages
+		q.Contracts = u.QuotaContracts
+	}
+	q.Exceeded = (q.PageLimit > 0 && q.Pages >= q.PageLimit) || (q.ContractLimit > 0 && q.Contracts >= q.ContractLimit)
+	return q
+}
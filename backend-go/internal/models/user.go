@@ -0,0 +1,84 @@
+package models
+
+import "time"
+
+// Who authenticates a user
+const (
+	UserProviderSupabase = "supabase"
+	UserProviderGoogle   = "google" // signs in with Google ID tokens
+)
+
+// User is someone who has called the API, as Periscope knows them. Supabase Auth or Google
+// holds the account; this records when they were last seen and any action an admin has
+// taken.
+type User struct {
+	ID             string     `json:"id"`
+	Provider       string     `json:"provider"`
+	Email          *string    `json:"email,omitempty"`
+	DisabledAt     *time.Time `json:"disabled_at,omitempty"`
+	DisabledReason *string    `json:"disabled_reason,omitempty"`
+	QuotaResetAt   *time.Time `json:"quota_reset_at,omitempty"`
+	LastSeenAt     time.Time  `json:"last_seen_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+
+	// Upstream usage in the current quota period; see Quota
+	Tier             string     `json:"tier"`
+	QuotaPeriodStart *time.Time `json:"-"`
+	QuotaPages       int64      `json:"-"`
+	QuotaContracts   int64      `json:"-"`
+
+	// Set when the user has asked to delete their account; see AccountRepository.Purge
+	DeletionRequestedAt *time.Time `json:"deletion_requested_at,omitempty"`
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at,omitempty"`
+	DeletedAt           *time.Time `json:"deleted_at,omitempty"`
+
+	// The user's own Massive API key, sealed with secrets.Box; see MassiveKeyStatus
+	MassiveKeyCiphertext *string    `json:"-"`
+	MassiveKeyHint       *string    `json:"-"`
+	MassiveKeySetAt      *time.Time `json:"-"`
+}
+
+// MassiveKeyStatus describes the Massive API key a user has stored, without the key itself
+type MassiveKeyStatus struct {
+	Configured bool       `json:"configured"`
+	Hint       *string    `json:"hint,omitempty"` // the key's last four characters
+	SetAt      *time.Time `json:"set_at,omitempty"`
+}
+
+// MassiveKey returns the status of the user's own Massive API key
+func (u *User) MassiveKey() MassiveKeyStatus {
+	return MassiveKeyStatus{
+		Configured: u.MassiveKeyCiphertext != nil,
+		Hint:       u.MassiveKeyHint,
+		SetAt:      u.MassiveKeySetAt,
+	}
+}
+
+// Disabled reports whether an admin has disabled the user
+func (u *User) Disabled() bool {
+	return u.DisabledAt != nil
+}
+
+// UserFilter narrows the admin user list
+type UserFilter struct {
+	Email    string // case-insensitive substring of the email
+	Disabled *bool
+	Limit    int
+	Offset   int
+}
+
+// UserUsage summarizes what a user has stored and how they have used the API lately
+type UserUsage struct {
+	UserID           string     `json:"user_id"`
+	Portfolios       int        `json:"portfolios"`
+	OpenPositions    int        `json:"open_positions"`
+	Watchlists       int        `json:"watchlists"`
+	Alerts           int        `json:"alerts"`
+	AlertTriggers30d int        `json:"alert_triggers_30d"`
+	AlertChannels    int        `json:"alert_channels"`
+	ActiveAPIKeys    int        `json:"active_api_keys"`
+	APIKeyLastUsedAt *time.Time `json:"api_key_last_used_at,omitempty"`
+	LastSeenAt       time.Time  `json:"last_seen_at"`
+	QuotaResetAt     *time.Time `json:"quota_reset_at,omitempty"`
+	Quota            QuotaUsage `json:"quota"`
+}
@@ -0,0 +1,63 @@
+package models
+
+import "time"
+
+// PositionValuation is an open position marked to the latest market price.
+// Values are signed: long positions are assets (positive), short positions liabilities (negative).
+type PositionValuation struct {
+	Position             Position `json:"position"`
+	MarkPrice            *float64 `json:"mark_price"` // per share / per contract premium, nil when unpriced
+	UnderlyingPrice      *float64 `json:"underlying_price,omitempty"`
+	MarketValue          *float64 `json:"market_value"`
+	CostBasis            float64  `json:"cost_basis"`
+	UnrealizedPnL        *float64 `json:"unrealized_pnl"`
+	UnrealizedPnLPercent *float64 `json:"unrealized_pnl_percent,omitempty"`
+}
+
+// ValuationTotals sums open positions at market together with realized ledger P/L
+type ValuationTotals struct {
+	MarketValue   float64 `json:"market_value"`   // net value of priced open positions
+	CostBasis     float64 `json:"cost_basis"`     // net cost of priced open positions
+	UnrealizedPnL float64 `json:"unrealized_pnl"` // across priced open positions
+	RealizedPnL   float64 `json:"realized_pnl"`   // from the trade ledger, net of fees
+	Dividends     float64 `json:"dividends"`      // paid dividends on share positions
+	TotalPnL      float64 `json:"total_pnl"`
+	Fees          float64 `json:"fees"` // all fees recorded in the ledger
+	OpenPositions int     `json:"open_positions"`
+	Unpriced      int     `json:"unpriced"` // open positions without a market price
+}
+
+// Add accumulates other into t
+func (t *ValuationTotals) Add(other ValuationTotals) {
+	t.MarketValue += other.MarketValue
+	t.CostBasis += other.CostBasis
+	t.UnrealizedPnL += other.UnrealizedPnL
+	t.RealizedPnL += other.RealizedPnL
+	t.Dividends += other.Dividends
+	t.TotalPnL += other.TotalPnL
+	t.Fees += other.Fees
+	t.OpenPositions += other.OpenPositions
+	t.Unpriced += other.Unpriced
+}
+
+// PortfolioValuation is a portfolio's open positions marked to market with its totals
+type PortfolioValuation struct {
+	PortfolioID int64 `json:"portfolio_id"`
+	ValuationTotals
+	Positions []PositionValuation `json:"positions"`
+	ValuedAt  time.Time           `json:"valued_at"`
+}
+
+// PortfolioSummary is one portfolio's totals within a rollup
+type PortfolioSummary struct {
+	Portfolio Portfolio       `json:"portfolio"`
+	Totals    ValuationTotals `json:"totals"`
+}
+
+// PortfolioRollup aggregates valuations and greeks across a user's portfolios
+type PortfolioRollup struct {
+	Portfolios []PortfolioSummary `json:"portfolios"`
+	Totals     ValuationTotals    `json:"totals"`
+	Greeks     *PortfolioGreeks   `json:"greeks"` // combined across all open positions, per-position detail omitted
+	ValuedAt   time.Time          `json:"valued_at"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// IVObservation is a single daily ATM implied volatility reading for an underlying
+type IVObservation struct {
+	Ticker          string    `json:"ticker"`
+	ObservedOn      time.Time `json:"observed_on"`
+	ATMIV           float64   `json:"atm_iv"`
+	UnderlyingPrice *float64  `json:"underlying_price,omitempty"`
+}
+
+// IVRankWindow reports where the current IV sits within a lookback window
+type IVRankWindow struct {
+	Window       string   `json:"window"`
+	Observations int      `json:"observations"`
+	Low          *float64 `json:"low,omitempty"`
+	High         *float64 `json:"high,omitempty"`
+	IVRank       *float64 `json:"iv_rank,omitempty"`       // 0-100, position between low and high
+	IVPercentile *float64 `json:"iv_percentile,omitempty"` // 0-100, share of days with lower IV
+}
@@ -0,0 +1,103 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// MaxWatchlistItems caps the tickers in one watchlist
+const MaxWatchlistItems = 200
+
+// stockPattern matches stock and ETF tickers such as AAPL, BRK.B or RDS-A
+var stockPattern = regexp.MustCompile(`^[A-Z][A-Z0-9.\-]{0,9}$`)
+
+// IsStockTicker reports whether an uppercased ticker looks like a stock or ETF symbol
+func IsStockTicker(ticker string) bool {
+	return stockPattern.MatchString(ticker)
+}
+
+// TickerAssetType classifies a normalized ticker: O:-prefixed OCC symbols are option
+// contracts, anything else a stock
+func TickerAssetType(ticker string) string {
+	if strings.HasPrefix(ticker, "O:") {
+		return AssetTypeOption
+	}
+	return AssetTypeStock
+}
+
+// Watchlist is a user's named, ordered list of stock tickers and option contracts
+type Watchlist struct {
+	ID          int64           `json:"id"`
+	UserID      *string         `json:"user_id,omitempty"`
+	Name        string          `json:"name"`
+	Description *string         `json:"description,omitempty"`
+	Items       []WatchlistItem `json:"items"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	DeletedAt   *time.Time      `json:"deleted_at,omitempty"` // set while it is deleted and restorable
+}
+
+// WatchlistItem is one ticker of a watchlist: a stock, or an option contract as an
+// O:-prefixed OCC symbol
+type WatchlistItem struct {
+	Ticker    string    `json:"ticker"`
+	AssetType string    `json:"asset_type"` // "stock" or "option"
+	AddedAt   time.Time `json:"added_at"`
+}
+
+// Tickers returns the watchlist's tickers of the asset type in order, or every ticker
+// when assetType is empty
+func (w *Watchlist) Tickers(assetType string) []string {
+	tickers := make([]string, 0, len(w.Items))
+	for _, item := range w.Items {
+		if assetType == "" || item.AssetType == assetType {
+			tickers = append(tickers, item.Ticker)
+		}
+	}
+	return tickers
+}
+
+// WatchlistQuote is the live snapshot of one watchlist ticker. Fields are nil when the
+// market data has no value for them.
+type WatchlistQuote struct {
+	Ticker        string   `json:"ticker"`
+	Name          string   `json:"name,omitempty"`
+	Price         *float64 `json:"price"` // session close, or the previous close before the open
+	PreviousClose *float64 `json:"previous_close"`
+	Change        *float64 `json:"change"`
+	ChangePercent *float64 `json:"change_percent"`
+	Open          *float64 `json:"open"`
+	High          *float64 `json:"high"`
+	Low           *float64 `json:"low"`
+	ATMIV         *float64 `json:"atm_iv,omitempty"` // 30-day constant-maturity ATM IV, when requested
+}
+
+// WatchlistContractQuote is the live snapshot of one watchlist option contract. Fields are
+// nil when the market data has no value for them.
+type WatchlistContractQuote struct {
+	Ticker            string   `json:"ticker"`
+	Underlying        string   `json:"underlying"`
+	ContractType      string   `json:"contract_type"`
+	StrikePrice       float64  `json:"strike_price"`
+	ExpirationDate    string   `json:"expiration_date"`
+	Bid               *float64 `json:"bid"`
+	Ask               *float64 `json:"ask"`
+	Mark              *float64 `json:"mark"` // mid, else last trade, else the day close
+	Last              *float64 `json:"last"`
+	ImpliedVolatility *float64 `json:"implied_volatility"`
+	OpenInterest      *int64   `json:"open_interest"`
+	Greeks            *Greeks  `json:"greeks,omitempty"`
+	Day               *Session `json:"day,omitempty"` // change, OHLC and volume of the session
+	UnderlyingPrice   *float64 `json:"underlying_price"`
+}
+
+// WatchlistQuotes are the live quotes of every stock and contract of a watchlist, each in
+// list order
+type WatchlistQuotes struct {
+	WatchlistID int64                    `json:"watchlist_id"`
+	Quotes      []WatchlistQuote         `json:"quotes"`
+	Contracts   []WatchlistContractQuote `json:"contracts"`
+	Unpriced    []string                 `json:"unpriced"` // tickers the market data did not return
+	FetchedAt   time.Time                `json:"fetched_at"`
+}
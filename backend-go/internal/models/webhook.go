@@ -0,0 +1,73 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Webhook events
+const (
+	EventPositionOpened  = "position.opened"  // data: the new position
+	EventPositionClosed  = "position.closed"  // data: the closed position
+	EventSnapshotCreated = "snapshot.created" // data: the end-of-day snapshot
+	EventPing            = "ping"             // sent on request to test an endpoint
+)
+
+// WebhookEvents lists the events a webhook can subscribe to
+var WebhookEvents = []string{EventPositionOpened, EventPositionClosed, EventSnapshotCreated}
+
+// Webhook delivery statuses
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed" // retries exhausted
+)
+
+// Webhook is a URL notified of a portfolio's events. The signing secret is only returned
+// when the webhook is created.
+type Webhook struct {
+	ID             int64      `json:"id"`
+	PortfolioID    int64      `json:"portfolio_id"`
+	URL            string     `json:"url"`
+	Description    *string    `json:"description,omitempty"`
+	Events         []string   `json:"events"`
+	Secret         string     `json:"secret,omitempty"`
+	Active         bool       `json:"active"`
+	FailureCount   int        `json:"failure_count"`
+	LastDeliveryAt *time.Time `json:"last_delivery_at,omitempty"`
+	LastStatus     *int       `json:"last_status,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// WebhookDelivery is one event queued for a webhook and its delivery attempts
+type WebhookDelivery struct {
+	ID             int64           `json:"id"`
+	WebhookID      int64           `json:"webhook_id"`
+	Event          string          `json:"event"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	ResponseStatus *int            `json:"response_status,omitempty"`
+	LastError      *string         `json:"last_error,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+}
+
+// WebhookEvent is the JSON body posted to a webhook URL
+type WebhookEvent struct {
+	ID          int64           `json:"id"` // delivery ID, the same across retries
+	Event       string          `json:"event"`
+	PortfolioID int64           `json:"portfolio_id"`
+	CreatedAt   time.Time       `json:"created_at"`
+	Data        json.RawMessage `json:"data"`
+}
+
+// PendingDelivery is a due delivery with the webhook it is sent to
+type PendingDelivery struct {
+	Delivery    WebhookDelivery
+	PortfolioID int64
+	URL         string
+	Secret      string
+}
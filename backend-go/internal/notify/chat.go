@@ -0,0 +1,117 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/webhook"
+)
+
+// Chat webhook settings
+const (
+	chatTimeout        = 10 * time.Second
+	chatErrorExcerpt   = 200
+	discordTitleMax    = 256  // Discord embed title limit
+	discordBodyMax     = 4096 // Discord embed description limit
+	discordAlertColour = 0xF5A623
+)
+
+// SlackNotifier posts alert messages to Slack incoming webhooks
+type SlackNotifier struct {
+	client *http.Client
+}
+
+// NewSlackNotifier creates a new Slack notifier
+func NewSlackNotifier() *SlackNotifier {
+	return &SlackNotifier{client: webhook.NewClient(chatTimeout)}
+}
+
+// Send posts the rendered message with its subject in bold
+func (n *SlackNotifier) Send(ctx context.Context, p *models.PendingAlertDelivery) (*int, error) {
+	msg, err := Render(&p.Delivery)
+	if err != nil {
+		return nil, err
+	}
+	text := "*" + msg.Subject + "*\n" + msg.Body
+	return postChat(ctx, n.client, p.Channel.URL, map[string]any{
+		"text": text,
+		"blocks": []map[string]any{{
+			"type": "section",
+			"text": map[string]string{"type": "mrkdwn", "text": text},
+		}},
+	})
+}
+
+// DiscordNotifier posts alert messages to Discord channel webhooks
+type DiscordNotifier struct {
+	client *http.Client
+}
+
+// NewDiscordNotifier creates a new Discord notifier
+func NewDiscordNotifier() *DiscordNotifier {
+	return &DiscordNotifier{client: webhook.NewClient(chatTimeout)}
+}
+
+// Send posts the rendered message as an embed titled with its subject
+func (n *DiscordNotifier) Send(ctx context.Context, p *models.PendingAlertDelivery) (*int, error) {
+	msg, err := Render(&p.Delivery)
+	if err != nil {
+		return nil, err
+	}
+	return postChat(ctx, n.client, p.Channel.URL, map[string]any{
+		"username": "Periscope",
+		"embeds": []map[string]any{{
+			"title":       truncate(msg.Subject, discordTitleMax),
+			"description": truncate(msg.Body, discordBodyMax),
+			"color":       discordAlertColour,
+			"timestamp":   p.Delivery.CreatedAt.UTC().Format(time.RFC3339),
+		}},
+	})
+}
+
+// postChat posts a JSON message to a chat webhook URL. Any 2xx response counts as delivered;
+// rate limited and failed posts are retried with the delivery backoff.
+func postChat(ctx context.Context, client *http.Client, url *string, message any) (*int, error) {
+	if url == nil {
+		return nil, fmt.Errorf("alert channel has no URL")
+	}
+	body, err := json.Marshal(message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode chat message: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, *url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("invalid chat webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Periscope-Alerts/1.0")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	status := resp.StatusCode
+	if status < 200 || status >= 300 {
+		excerpt, _ := io.ReadAll(io.LimitReader(resp.Body, chatErrorExcerpt))
+		return &status, fmt.Errorf("chat webhook responded %d: %s", status, bytes.TrimSpace(excerpt))
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	return &status, nil
+}
+
+// truncate shortens text to at most max runes, marking the cut with an ellipsis
+func truncate(text string, max int) string {
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	return string(runes[:max-1]) + "…"
+}
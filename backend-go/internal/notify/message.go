@@ -0,0 +1,273 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/analytics"
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+)
+
+// Message is a notification rendered for people rather than programs, for email and chat
+// channels
+type Message struct {
+	Subject string
+	Body    string
+}
+
+// Message templates, one subject and body pair per kind of alert
+const messageTemplates = `
+{{define "price.subject"}}{{.Name}} {{.Label}} {{rule .Alert.Operator}} {{value .Alert.Metric .Alert.Threshold}}{{end}}
+{{define "price.body"}}{{.Name}} {{.Label}} was {{value .Alert.Metric .Trigger.Value}} at {{market .Trigger.TriggeredAt}}, triggering your alert on {{.Label}} {{rule .Alert.Operator}} {{value .Alert.Metric .Alert.Threshold}}.
+{{template "footer" .}}{{end}}
+
+{{define "volatility.subject"}}{{.Name}} {{.Label}} {{rule .Alert.Operator}} {{value .Alert.Metric .Alert.Threshold}}{{end}}
+{{define "volatility.body"}}{{.Name}} {{.Label}} was {{value .Alert.Metric .Trigger.Value}} at {{market .Trigger.TriggeredAt}}, triggering your alert on {{.Label}} {{rule .Alert.Operator}} {{value .Alert.Metric .Alert.Threshold}}.
+Option premiums move with implied volatility, so this may be a time to review positions on {{.Underlying}}.
+{{template "footer" .}}{{end}}
+
+{{define "greeks.subject"}}{{.Name}} {{.Label}} {{rule .Alert.Operator}} {{value .Alert.Metric .Alert.Threshold}}{{end}}
+{{define "greeks.body"}}The {{.Name}} contract's {{.Label}} was {{value .Alert.Metric .Trigger.Value}} at {{market .Trigger.TriggeredAt}}, triggering your alert on {{.Label}} {{rule .Alert.Operator}} {{value .Alert.Metric .Alert.Threshold}}.
+{{template "footer" .}}{{end}}
+
+{{define "liquidity.subject"}}{{.Name}} {{.Label}} {{rule .Alert.Operator}} {{value .Alert.Metric .Alert.Threshold}}{{end}}
+{{define "liquidity.body"}}The {{.Name}} contract's {{.Label}} was {{value .Alert.Metric .Trigger.Value}} at {{market .Trigger.TriggeredAt}}, triggering your alert on {{.Label}} {{rule .Alert.Operator}} {{value .Alert.Metric .Alert.Threshold}}.
+Check the quote before trading: fills on thin contracts can land far from the mid.
+{{template "footer" .}}{{end}}
+
+{{define "earnings.subject"}}{{.Name}} reports earnings {{days .Trigger.Value}}{{end}}
+{{define "earnings.body"}}{{.Name}} reports earnings {{days .Trigger.Value}}. Your earnings alerts fire {{count .Alert.Threshold "day"}} ahead of releases on the tickers you hold or watch.
+{{template "footer" .}}{{end}}
+
+{{define "composite.subject"}}{{.Name}}: alert conditions met{{end}}
+{{define "composite.body"}}{{if .Alert.Condition.Any}}One of the conditions{{else}}The conditions{{end}} of your alert on {{.Name}} {{if .Alert.Condition.Any}}was{{else}}were{{end}} met at {{market .Trigger.TriggeredAt}}:
+{{range conditions .Alert .Trigger}}- {{.}}
+{{end}}{{template "footer" .}}{{end}}
+
+{{define "ping.subject"}}Test notification{{end}}
+{{define "ping.body"}}This is a test notification from Periscope. Your alert channel is set up to receive alerts.
+{{end}}
+
+{{define "footer"}}{{with .Alert.Note}}
+Note: {{.}}
+{{end}}
+Alert #{{.Alert.ID}}{{if eq .Alert.Mode "recurring"}}, re-arms after {{cooldown .Alert.CooldownSeconds}}{{else}}, now triggered until you re-arm it{{end}}.
+{{end}}
+`
+
+var messages = template.Must(template.New("messages").Funcs(template.FuncMap{
+	"rule":       ruleText,
+	"value":      metricValue,
+	"market":     marketTime,
+	"days":       daysText,
+	"count":      func(v float64, unit string) string { return plural(int(math.Round(v)), unit) },
+	"cooldown":   cooldownText,
+	"conditions": conditionLines,
+}).Parse(messageTemplates))
+
+// metricGroups maps each alert metric to its message template
+var metricGroups = map[string]string{
+	models.MarketMetricPrice:            "price",
+	models.MarketMetricChangePercent:    "price",
+	models.MarketMetricATMIV:            "volatility",
+	models.MarketMetricIVRank:           "volatility",
+	models.MarketMetricIVPercentile:     "volatility",
+	models.MarketMetricIV:               "volatility",
+	models.MarketMetricDelta:            "greeks",
+	models.MarketMetricAbsDelta:         "greeks",
+	models.MarketMetricGamma:            "greeks",
+	models.MarketMetricTheta:            "greeks",
+	models.MarketMetricVega:             "greeks",
+	models.MarketMetricStrikeDistance:   "greeks",
+	models.MarketMetricDaysToExpiration: "greeks",
+	models.MarketMetricSpreadPercent:    "liquidity",
+	models.MarketMetricQuoteSize:        "liquidity",
+	models.MarketMetricLiquidity:        "liquidity",
+	models.MarketMetricEarningsDays:     "earnings",
+}
+
+// metricLabels names each alert metric in messages
+var metricLabels = map[string]string{
+	models.MarketMetricPrice:            "price",
+	models.MarketMetricChangePercent:    "change on the day",
+	models.MarketMetricATMIV:            "30-day ATM IV",
+	models.MarketMetricIVRank:           "IV rank",
+	models.MarketMetricIVPercentile:     "IV percentile",
+	models.MarketMetricIV:               "implied volatility",
+	models.MarketMetricDelta:            "delta",
+	models.MarketMetricAbsDelta:         "absolute delta",
+	models.MarketMetricGamma:            "gamma",
+	models.MarketMetricTheta:            "theta",
+	models.MarketMetricVega:             "vega",
+	models.MarketMetricStrikeDistance:   "distance from strike",
+	models.MarketMetricDaysToExpiration: "days to expiration",
+	models.MarketMetricSpreadPercent:    "bid-ask spread",
+	models.MarketMetricQuoteSize:        "quote size",
+	models.MarketMetricLiquidity:        "liquidity score",
+	models.MarketMetricEarningsDays:     "days to earnings",
+}
+
+// alertMessage is the data alert templates are rendered with
+type alertMessage struct {
+	models.AlertNotification
+	Name       string // the stock ticker, or a readable option contract
+	Underlying string
+	Label      string
+}
+
+// Render renders a delivery's event as a message
+func Render(d *models.AlertDelivery) (*Message, error) {
+	switch d.Event {
+	case models.EventPing:
+		return render("ping", nil)
+	case models.EventAlertTriggered:
+		var n models.AlertNotification
+		if err := json.Unmarshal(d.Payload, &n); err != nil {
+			return nil, fmt.Errorf("failed to decode alert notification: %w", err)
+		}
+		group, ok := metricGroups[n.Alert.Metric]
+		if !ok {
+			group = "price"
+		}
+		if n.Alert.Condition != nil {
+			group = "composite"
+		}
+		data := alertMessage{
+			AlertNotification: n,
+			Name:              n.Alert.Ticker,
+			Underlying:        n.Alert.Ticker,
+			Label:             n.Alert.Metric,
+		}
+		if label, ok := metricLabels[n.Alert.Metric]; ok {
+			data.Label = label
+		}
+		if symbol, err := models.ParseOptionTicker(n.Alert.Ticker); err == nil && n.Alert.AssetType == models.AssetTypeOption {
+			data.Underlying = symbol.Underlying
+			data.Name = fmt.Sprintf("%s %s %s %s", symbol.Underlying, symbol.ExpirationDate,
+				strconv.FormatFloat(symbol.StrikePrice, 'f', -1, 64), symbol.ContractType)
+		}
+		return render(group, data)
+	}
+	return nil, fmt.Errorf("no message template for %s events", d.Event)
+}
+
+// render executes the subject and body templates of a group
+func render(group string, data any) (*Message, error) {
+	var subject, body bytes.Buffer
+	if err := messages.ExecuteTemplate(&subject, group+".subject", data); err != nil {
+		return nil, fmt.Errorf("failed to render message subject: %w", err)
+	}
+	if err := messages.ExecuteTemplate(&body, group+".body", data); err != nil {
+		return nil, fmt.Errorf("failed to render message body: %w", err)
+	}
+	return &Message{
+		Subject: strings.TrimSpace(subject.String()),
+		Body:    strings.TrimSpace(body.String()) + "\n",
+	}, nil
+}
+
+// ruleText describes an alert operator
+func ruleText(operator string) string {
+	switch operator {
+	case ">":
+		return "above"
+	case ">=":
+		return "at or above"
+	case "<":
+		return "below"
+	case "<=":
+		return "at or below"
+	case models.OperatorCrossesAbove:
+		return "crossing above"
+	case models.OperatorCrossesBelow:
+		return "crossing below"
+	}
+	return operator
+}
+
+// metricValue formats a value in the metric's units
+func metricValue(metric string, v float64) string {
+	switch metric {
+	case models.MarketMetricPrice:
+		return "$" + strconv.FormatFloat(v, 'f', 2, 64)
+	case models.MarketMetricChangePercent:
+		return fmt.Sprintf("%+.2f%%", v)
+	case models.MarketMetricATMIV, models.MarketMetricIV:
+		return fmt.Sprintf("%.1f%%", v*100)
+	case models.MarketMetricSpreadPercent, models.MarketMetricStrikeDistance:
+		return fmt.Sprintf("%.1f%%", v)
+	case models.MarketMetricIVRank, models.MarketMetricIVPercentile, models.MarketMetricQuoteSize, models.MarketMetricLiquidity:
+		return strconv.FormatFloat(math.Round(v), 'f', 0, 64)
+	case models.MarketMetricDelta, models.MarketMetricAbsDelta, models.MarketMetricGamma, models.MarketMetricTheta, models.MarketMetricVega:
+		return strconv.FormatFloat(v, 'f', 3, 64)
+	case models.MarketMetricEarningsDays:
+		return daysText(v)
+	case models.MarketMetricDaysToExpiration:
+		return plural(int(math.Round(v)), "day")
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// conditionLines describes each comparison of a composite alert with the value it was
+// evaluated against
+func conditionLines(a models.Alert, t models.AlertTrigger) []string {
+	if a.Condition == nil {
+		return nil
+	}
+	var lines []string
+	for _, c := range a.Condition.Comparisons() {
+		label, ok := metricLabels[c.Metric]
+		if !ok {
+			label = c.Metric
+		}
+		line := fmt.Sprintf("%s %s %s", label, ruleText(c.Operator), metricValue(c.Metric, *c.Threshold))
+		if v, ok := t.Snapshot[c.Metric]; ok {
+			line += fmt.Sprintf(" (was %s)", metricValue(c.Metric, v))
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// daysText describes a number of days until an event
+func daysText(v float64) string {
+	switch days := int(math.Round(v)); days {
+	case 0:
+		return "today"
+	case 1:
+		return "in 1 day"
+	default:
+		return fmt.Sprintf("in %d days", days)
+	}
+}
+
+// cooldownText describes a cooldown in the largest whole unit that fits it
+func cooldownText(seconds int) string {
+	d := time.Duration(seconds) * time.Second
+	switch {
+	case d >= 24*time.Hour && d%(24*time.Hour) == 0:
+		return plural(int(d/(24*time.Hour)), "day")
+	case d >= time.Hour && d%time.Hour == 0:
+		return plural(int(d/time.Hour), "hour")
+	default:
+		return plural(int(d/time.Minute), "minute")
+	}
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
+
+// marketTime formats a time in exchange time
+func marketTime(t time.Time) string {
+	return analytics.MarketTime(t).Format("Jan 2 3:04 PM") + " ET"
+}
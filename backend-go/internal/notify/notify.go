@@ -0,0 +1,22 @@
+// Package notify sends alert notifications over the channels users configure for them
+package notify
+
+import (
+	"context"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+)
+
+// Notifier sends alert deliveries over one kind of channel
+type Notifier interface {
+	// Send delivers one notification, returning the response status when the channel
+	// reports one
+	Send(ctx context.Context, p *models.PendingAlertDelivery) (*int, error)
+}
+
+// Limiter is implemented by notifiers that cap how many notifications each user receives
+// in a window, such as email. Deliveries over the cap wait until the window has room.
+type Limiter interface {
+	Limit() (count int, window time.Duration)
+}
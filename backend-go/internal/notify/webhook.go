@@ -0,0 +1,44 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/webhook"
+)
+
+// webhookTimeout bounds one alert webhook request
+const webhookTimeout = 10 * time.Second
+
+// WebhookNotifier posts alert events to a URL, signed with the channel's secret the same
+// way as portfolio webhooks
+type WebhookNotifier struct {
+	client *http.Client
+}
+
+// NewWebhookNotifier creates a new webhook notifier
+func NewWebhookNotifier() *WebhookNotifier {
+	return &WebhookNotifier{client: webhook.NewClient(webhookTimeout)}
+}
+
+// Send posts the signed event envelope
+func (n *WebhookNotifier) Send(ctx context.Context, p *models.PendingAlertDelivery) (*int, error) {
+	d := &p.Delivery
+	if p.Channel.URL == nil {
+		return nil, fmt.Errorf("alert channel %d has no URL", p.Channel.ID)
+	}
+	body, err := json.Marshal(models.AlertEvent{
+		ID:        d.ID,
+		Event:     d.Event,
+		CreatedAt: d.CreatedAt,
+		Data:      d.Payload,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode alert event: %w", err)
+	}
+	return webhook.Post(ctx, n.client, *p.Channel.URL, p.Channel.Secret, d.Event, d.ID, body)
+}
@@ -0,0 +1,162 @@
+package pricing
+
+import (
+	"math"
+)
+
+// OptionType distinguishes calls from puts
+type OptionType string
+
+const (
+	Call OptionType = "call"
+	Put  OptionType = "put"
+)
+
+// Inputs holds the Black-Scholes-Merton model inputs
+type Inputs struct {
+	Type     OptionType
+	Spot     float64 // underlying price
+	Strike   float64
+	Years    float64 // time to expiration in years
+	Rate     float64 // continuously compounded risk-free rate
+	DivYield float64 // continuous dividend yield
+	Vol      float64 // annualized volatility
+}
+
+// Result holds a model price and first-order greeks using market conventions:
+// theta per calendar day, vega and rho per 1 percentage point
+type Result struct {
+	Price float64 `json:"price"`
+	Delta float64 `json:"delta"`
+	Gamma float64 `json:"gamma"`
+	Theta float64 `json:"theta"`
+	Vega  float64 `json:"vega"`
+	Rho   float64 `json:"rho"`
+}
+
+// valid reports whether the inputs can be priced with the closed-form model
+func (in Inputs) valid() bool {
+	return in.Spot > 0 && in.Strike > 0 && in.Years > 0 && in.Vol > 0
+}
+
+// d1d2 returns the standard d1 and d2 terms
+func (in Inputs) d1d2() (float64, float64) {
+	sqrtT := math.Sqrt(in.Years)
+	d1 := (math.Log(in.Spot/in.Strike) + (in.Rate-in.DivYield+0.5*in.Vol*in.Vol)*in.Years) / (in.Vol * sqrtT)
+	return d1, d1 - in.Vol*sqrtT
+}
+
+// Price returns the Black-Scholes-Merton value of a European option.
+// At or past expiration, or with zero volatility, intrinsic value is returned.
+func Price(in Inputs) float64 {
+	if !in.valid() {
+		return intrinsic(in)
+	}
+
+	d1, d2 := in.d1d2()
+	dfq := math.Exp(-in.DivYield * in.Years)
+	dfr := math.Exp(-in.Rate * in.Years)
+
+	if in.Type == Put {
+		return in.Strike*dfr*normCDF(-d2) - in.Spot*dfq*normCDF(-d1)
+	}
+	return in.Spot*dfq*normCDF(d1) - in.Strike*dfr*normCDF(d2)
+}
+
+// Compute returns the model price together with first-order greeks
+func Compute(in Inputs) Result {
+	if !in.valid() {
+		res := Result{Price: intrinsic(in)}
+		if res.Price > 0 {
+			res.Delta = 1
+			if in.Type == Put {
+				res.Delta = -1
+			}
+		}
+		return res
+	}
+
+	d1, d2 := in.d1d2()
+	sqrtT := math.Sqrt(in.Years)
+	dfq := math.Exp(-in.DivYield * in.Years)
+	dfr := math.Exp(-in.Rate * in.Years)
+	pdf := normPDF(d1)
+
+	res := Result{
+		Price: Price(in),
+		Gamma: dfq * pdf / (in.Spot * in.Vol * sqrtT),
+		Vega:  in.Spot * dfq * pdf * sqrtT / 100.0,
+	}
+
+	decay := -in.Spot * dfq * pdf * in.Vol / (2 * sqrtT)
+	if in.Type == Put {
+		res.Delta = dfq * (normCDF(d1) - 1)
+		res.Theta = (decay + in.Rate*in.Strike*dfr*normCDF(-d2) - in.DivYield*in.Spot*dfq*normCDF(-d1)) / 365.0
+		res.Rho = -in.Strike * in.Years * dfr * normCDF(-d2) / 100.0
+	} else {
+		res.Delta = dfq * normCDF(d1)
+		res.Theta = (decay - in.Rate*in.Strike*dfr*normCDF(d2) + in.DivYield*in.Spot*dfq*normCDF(d1)) / 365.0
+		res.Rho = in.Strike * in.Years * dfr * normCDF(d2) / 100.0
+	}
+
+	return res
+}
+
+// intrinsic returns the exercise value of the option
+func intrinsic(in Inputs) float64 {
+	if in.Type == Put {
+		return math.Max(in.Strike-in.Spot, 0)
+	}
+	return math.Max(in.Spot-in.Strike, 0)
+}
+
+// normCDF is the standard normal cumulative distribution function
+func normCDF(x float64) float64 {
+	return 0.5 * math.Erfc(-x/math.Sqrt2)
+}
+
+// normPDF is the standard normal probability density function
+func normPDF(x float64) float64 {
+	return math.Exp(-0.5*x*x) / math.Sqrt(2*math.Pi)
+}
+
+// SecondOrder holds cross and second-order sensitivities using market conventions:
+// vanna is the delta change per 1 vol point, charm the delta change per calendar day,
+// and vomma the vega (per 1 vol point) change per 1 vol point
+type SecondOrder struct {
+	Vanna float64 `json:"vanna"`
+	Charm float64 `json:"charm"`
+	Vomma float64 `json:"vomma"`
+}
+
+// ComputeSecondOrder returns vanna, charm and vomma. All are zero when the inputs
+// cannot be priced (expired contracts or zero volatility).
+func ComputeSecondOrder(in Inputs) SecondOrder {
+	if !in.valid() {
+		return SecondOrder{}
+	}
+
+	d1, d2 := in.d1d2()
+	sqrtT := math.Sqrt(in.Years)
+	dfq := math.Exp(-in.DivYield * in.Years)
+	pdf := normPDF(d1)
+
+	vanna := -dfq * pdf * d2 / in.Vol
+	vega := in.Spot * dfq * pdf * sqrtT
+	vomma := vega * d1 * d2 / in.Vol
+
+	// Charm is reported as the change in delta over one day of passing time
+	drift := dfq * pdf * (2*(in.Rate-in.DivYield)*in.Years - d2*in.Vol*sqrtT) / (2 * in.Years * in.Vol * sqrtT)
+	var charm float64
+	if in.Type == Put {
+		charm = -in.DivYield*dfq*normCDF(-d1) - drift
+	} else {
+		charm = in.DivYield*dfq*normCDF(d1) - drift
+	}
+
+	return SecondOrder{
+		Vanna: vanna / 100.0,
+		Charm: charm / 365.0,
+		Vomma: vomma / 10000.0,
+	}
+}
@@ -0,0 +1,244 @@
+package rebalance
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+)
+
+// ErrNoBase is returned when there is no positive allocation base to measure targets against
+var ErrNoBase = errors.New("no positive allocation base")
+
+// Leg is an open position with its share-equivalent delta
+type Leg struct {
+	PositionID int64
+	Ticker     string
+	Underlying string
+	Option     bool
+	Side       string
+	Quantity   float64 // open contracts or shares
+	Delta      float64 // signed position delta in shares of the underlying
+}
+
+// Exposures returns the dollar delta held in each priced underlying
+func Exposures(legs []Leg, prices map[string]float64) map[string]float64 {
+	exposures := make(map[string]float64)
+	for _, leg := range legs {
+		if price, ok := prices[leg.Underlying]; ok {
+			exposures[leg.Underlying] += leg.Delta * price
+		}
+	}
+	return exposures
+}
+
+// Plan measures each target's drift from the base and, for targets that drifted more than
+// threshold percent points, suggests trades that close the gap. A sector's adjustment is
+// split across its underlyings by their current exposure, or evenly when it holds none.
+// Legs on underlyings without a price must be left out by the caller.
+func Plan(targets []models.AllocationTarget, legs []Leg, prices map[string]float64, base, threshold float64) (*models.RebalancePlan, error) {
+	if base <= 0 {
+		return nil, ErrNoBase
+	}
+
+	exposures := Exposures(legs, prices)
+	plan := &models.RebalancePlan{
+		Base:               base,
+		Threshold:          threshold,
+		Targets:            []models.TargetDrift{},
+		Untargeted:         []models.UnderlyingAllocation{},
+		UnallocatedPercent: 100,
+	}
+
+	targeted := make(map[string]bool)
+	for _, target := range targets {
+		plan.UnallocatedPercent -= target.Percent
+
+		drift := models.TargetDrift{
+			Target:      target,
+			Underlyings: []models.UnderlyingAllocation{},
+			Trades:      []models.RebalanceTrade{},
+		}
+		for _, ticker := range target.Tickers {
+			targeted[ticker] = true
+			u := underlying(ticker, exposures, prices, base)
+			drift.Exposure += u.Exposure
+			drift.Underlyings = append(drift.Underlyings, u)
+		}
+		drift.ActualPercent = drift.Exposure / base * 100
+		drift.DriftPercent = drift.ActualPercent - target.Percent
+		drift.Adjustment = target.Percent/100*base - drift.Exposure
+		drift.Rebalance = math.Abs(drift.DriftPercent) > threshold
+
+		if drift.Rebalance {
+			for i, amount := range split(drift.Underlyings, drift.Adjustment) {
+				u := drift.Underlyings[i]
+				if u.Price == nil || amount == 0 {
+					continue
+				}
+				drift.Trades = append(drift.Trades, Trades(u.Ticker, legs, amount / *u.Price, *u.Price)...)
+			}
+		}
+		plan.Targets = append(plan.Targets, drift)
+	}
+	plan.UnallocatedPercent = math.Max(plan.UnallocatedPercent, 0)
+
+	for ticker := range exposures {
+		if !targeted[ticker] {
+			plan.Untargeted = append(plan.Untargeted, underlying(ticker, exposures, prices, base))
+		}
+	}
+	sort.Slice(plan.Untargeted, func(i, j int) bool {
+		a, b := plan.Untargeted[i], plan.Untargeted[j]
+		if math.Abs(a.Exposure) != math.Abs(b.Exposure) {
+			return math.Abs(a.Exposure) > math.Abs(b.Exposure)
+		}
+		return a.Ticker < b.Ticker
+	})
+
+	return plan, nil
+}
+
+// Trades returns the ways to change an underlying's delta by deltaShares shares: a share
+// trade first, then opening or closing contracts of each option leg held on it, closest to
+// the wanted delta first. Shares are never shorted; selling stops at the shares held.
+func Trades(ticker string, legs []Leg, deltaShares, price float64) []models.RebalanceTrade {
+	var shares []models.RebalanceTrade
+	var options []models.RebalanceTrade
+
+	var stock *Leg
+	for i := range legs {
+		leg := &legs[i]
+		if leg.Underlying != ticker || leg.Quantity <= 0 {
+			continue
+		}
+		if !leg.Option {
+			if leg.Side == models.SideLong && (stock == nil || leg.Quantity > stock.Quantity) {
+				stock = leg
+			}
+			continue
+		}
+		if leg.Delta == 0 {
+			continue
+		}
+		// Delta added by one more contract of the leg's side
+		perContract := leg.Delta / leg.Quantity
+		if trade, ok := legTrade(leg, deltaShares/perContract, perContract, price); ok {
+			options = append(options, trade)
+		}
+	}
+
+	n := math.Round(math.Abs(deltaShares))
+	switch {
+	case n == 0:
+	case deltaShares > 0:
+		trade := models.RebalanceTrade{
+			Instrument: models.RebalanceShares,
+			Ticker:     ticker,
+			Action:     models.TransactionOpen,
+			Side:       models.SideLong,
+			Quantity:   n,
+		}
+		if stock != nil {
+			trade.PositionID = &stock.PositionID
+			trade.Action = models.TransactionAdd
+		}
+		shares = append(shares, withDelta(trade, n, price))
+	case stock != nil:
+		n = math.Min(n, stock.Quantity)
+		shares = append(shares, withDelta(models.RebalanceTrade{
+			Instrument: models.RebalanceShares,
+			Ticker:     ticker,
+			PositionID: &stock.PositionID,
+			Action:     models.TransactionClose,
+			Side:       models.SideLong,
+			Quantity:   n,
+		}, -n, price))
+	}
+
+	sort.SliceStable(options, func(i, j int) bool {
+		return math.Abs(options[i].DeltaShares-deltaShares) < math.Abs(options[j].DeltaShares-deltaShares)
+	})
+	return append(shares, options...)
+}
+
+// legTrade opens (contracts > 0) or closes (contracts < 0) whole contracts of an option leg,
+// closing at most the contracts held
+func legTrade(leg *Leg, contracts, perContract, price float64) (models.RebalanceTrade, bool) {
+	n := math.Round(math.Abs(contracts))
+	action := models.TransactionAdd
+	if contracts < 0 {
+		n = math.Min(n, leg.Quantity)
+		action = models.TransactionClose
+	}
+	if n == 0 {
+		return models.RebalanceTrade{}, false
+	}
+
+	change := n * perContract
+	if action == models.TransactionClose {
+		change = -change
+	}
+	return withDelta(models.RebalanceTrade{
+		Instrument: models.RebalanceOptions,
+		Ticker:     leg.Ticker,
+		PositionID: &leg.PositionID,
+		Action:     action,
+		Side:       leg.Side,
+		Quantity:   n,
+	}, change, price), true
+}
+
+func withDelta(trade models.RebalanceTrade, deltaShares, price float64) models.RebalanceTrade {
+	trade.DeltaShares = deltaShares
+	trade.Amount = deltaShares * price
+	return trade
+}
+
+// split divides an adjustment across a target's underlyings: by current exposure when all
+// of it is long, otherwise evenly across the priced underlyings
+func split(underlyings []models.UnderlyingAllocation, adjustment float64) []float64 {
+	amounts := make([]float64, len(underlyings))
+
+	var total float64
+	priced := 0
+	proportional := true
+	for _, u := range underlyings {
+		if u.Price == nil {
+			continue
+		}
+		priced++
+		total += u.Exposure
+		if u.Exposure < 0 {
+			proportional = false
+		}
+	}
+	if priced == 0 {
+		return amounts
+	}
+	proportional = proportional && total > 0
+
+	for i, u := range underlyings {
+		switch {
+		case u.Price == nil:
+		case proportional:
+			amounts[i] = adjustment * u.Exposure / total
+		default:
+			amounts[i] = adjustment / float64(priced)
+		}
+	}
+	return amounts
+}
+
+func underlying(ticker string, exposures, prices map[string]float64, base float64) models.UnderlyingAllocation {
+	u := models.UnderlyingAllocation{
+		Ticker:   ticker,
+		Exposure: exposures[ticker],
+		Percent:  exposures[ticker] / base * 100,
+	}
+	if price, ok := prices[ticker]; ok {
+		u.Price = &price
+	}
+	return u
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// Row filters for a user's data, with the user's ID as $1
+const (
+	ownedByUser      = `user_id = $1::uuid`
+	inUserPortfolios = `portfolio_id IN (SELECT id FROM portfolios WHERE user_id = $1::uuid)`
+	inUserPositions  = `position_id IN (SELECT p.id FROM positions p JOIN portfolios f ON f.id = p.portfolio_id WHERE f.user_id = $1::uuid)`
+)
+
+// exportSection is a table exported with a user's data: the rows matching where, in order,
+// without the columns in omit (credentials, which are not the user's data to take away)
+type exportSection struct {
+	name    string
+	table   string
+	where   string
+	orderBy string
+	omit    []string
+}
+
+// accountExportSections are the tables a user's data lives in. Rows removed by Purge are
+// exported, and so is the audit log of the user's actions.
+var accountExportSections = []exportSection{
+	{name: "account", table: "users", where: `id = $1::uuid`, orderBy: "id", omit: []string{"massive_key_ciphertext"}},
+	{name: "settings", table: "user_settings", where: ownedByUser, orderBy: "id"},
+	{name: "portfolios", table: "portfolios", where: ownedByUser, orderBy: "id"},
+	{name: "positions", table: "positions", where: inUserPortfolios, orderBy: "id"},
+	{name: "transactions", table: "transactions", where: inUserPortfolios, orderBy: "id"},
+	{name: "position_lots", table: "position_lots", where: inUserPositions, orderBy: "id"},
+	{name: "lot_closures", table: "lot_closures", where: `transaction_id IN (SELECT id FROM transactions WHERE ` + inUserPortfolios + `)`, orderBy: "id"},
+	{name: "dividends", table: "dividends", where: inUserPortfolios, orderBy: "id"},
+	{name: "portfolio_snapshots", table: "portfolio_snapshots", where: inUserPortfolios, orderBy: "portfolio_id, snapshot_date"},
+	{name: "strategies", table: "strategies", where: inUserPortfolios, orderBy: "id"},
+	{name: "allocation_targets", table: "allocation_targets", where: inUserPortfolios, orderBy: "id"},
+	{name: "paper_orders", table: "paper_orders", where: inUserPortfolios, orderBy: "id"},
+	{name: "journal_entries", table: "journal_entries", where: inUserPortfolios, orderBy: "id", omit: []string{"search"}},
+	{name: "journal_attachments", table: "journal_attachments", where: `entry_id IN (SELECT id FROM journal_entries WHERE ` + inUserPortfolios + `)`, orderBy: "id"},
+	{name: "position_alerts", table: "position_alerts", where: inUserPortfolios, orderBy: "id"},
+	{name: "share_links", table: "share_links", where: inUserPortfolios, orderBy: "id"},
+	{name: "webhooks", table: "webhooks", where: inUserPortfolios, orderBy: "id", omit: []string{"secret"}},
+	{name: "webhook_deliveries", table: "webhook_deliveries", where: `webhook_id IN (SELECT id FROM webhooks WHERE ` + inUserPortfolios + `)`, orderBy: "id"},
+	{name: "watchlists", table: "watchlists", where: ownedByUser, orderBy: "id"},
+	{name: "watchlist_items", table: "watchlist_items", where: `watchlist_id IN (SELECT id FROM watchlists WHERE ` + ownedByUser + `)`, orderBy: "id"},
+	{name: "alerts", table: "alerts", where: ownedByUser, orderBy: "id"},
+	{name: "alert_triggers", table: "alert_triggers", where: `alert_id IN (SELECT id FROM alerts WHERE ` + ownedByUser + `)`, orderBy: "id"},
+	{name: "earnings_alert_settings", table: "earnings_alert_settings", where: ownedByUser, orderBy: "id"},
+	{name: "alert_channels", table: "alert_channels", where: ownedByUser, orderBy: "id", omit: []string{"secret"}},
+	{name: "alert_deliveries", table: "alert_deliveries", where: `channel_id IN (SELECT id FROM alert_channels WHERE ` + ownedByUser + `)`, orderBy: "id"},
+	{name: "api_keys", table: "api_keys", where: ownedByUser, orderBy: "id", omit: []string{"key_hash"}},
+	{name: "usage_daily", table: "usage_daily", where: ownedByUser, orderBy: "day"},
+	{name: "audit_log", table: "audit_log", where: `actor_id = $1::uuid`, orderBy: "id"},
+}
+
+// AccountRepository exports and purges everything stored for a user
+type AccountRepository struct {
+	db    *database.DB
+	users *database.Cache[models.User] // invalidated with the UserRepository's
+}
+
+// NewAccountRepository creates a new account repository
+func NewAccountRepository(db *database.DB) *AccountRepository {
+	return &AccountRepository{db: db, users: database.NewCache[models.User](db, "users", userCacheTTL)}
+}
+
+// Export returns every row stored for the user, one section per table, read in a single
+// snapshot so the sections agree with each other
+func (r *AccountRepository) Export(ctx context.Context, userID string) ([]models.AccountExportSection, error) {
+	tx, err := r.db.Pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sections := make([]models.AccountExportSection, 0, len(accountExportSections))
+	for _, s := range accountExportSections {
+		omit := s.omit
+		if omit == nil {
+			omit = []string{}
+		}
+		var rows []byte
+		err := tx.QueryRow(ctx, `
+			SELECT COALESCE(jsonb_agg(to_jsonb(t) - $2::text[] ORDER BY `+s.orderBy+`), '[]'::jsonb)
+			FROM `+s.table+` t
+			WHERE `+s.where,
+			userID, omit).Scan(&rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to export %s: %w", s.name, err)
+		}
+		sections = append(sections, models.AccountExportSection{Name: s.name, Rows: json.RawMessage(rows)})
+	}
+	return sections, nil
+}
+
+// Purge deletes everything stored for the user, except the audit log, which is append-only,
+// and leaves their users row as a disabled tombstone without an email
+func (r *AccountRepository) Purge(ctx context.Context, userID string, now time.Time) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Deleting portfolios cascades to their positions, trades, journal and everything else
+	// recorded under them; watchlists, alerts and channels likewise take their items,
+	// triggers and deliveries
+	for _, table := range []string{"portfolios", "watchlists", "alerts", "alert_channels", "earnings_alert_settings",
+		"user_settings", "api_keys", "usage_daily"} {
+		if _, err := tx.Exec(ctx, `DELETE FROM `+table+` WHERE `+ownedByUser, userID); err != nil {
+			return fmt.Errorf("failed to purge %s: %w", table, err)
+		}
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE users
+		SET email = NULL, google_sub = NULL,
+			massive_key_ciphertext = NULL, massive_key_hint = NULL, massive_key_set_at = NULL,
+			disabled_at = COALESCE(disabled_at, $2), disabled_reason = 'account deleted',
+			deleted_at = $2, updated_at = NOW()
+		WHERE id = $1::uuid`,
+		userID, now)
+	if err != nil {
+		return fmt.Errorf("failed to mark user deleted: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit account purge: %w", err)
+	}
+	r.users.Invalidate(ctx, userID)
+	return nil
+}
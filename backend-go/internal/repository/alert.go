@@ -0,0 +1,585 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// AlertRepository persists market alert rules and their trigger records
+type AlertRepository struct {
+	db *database.DB
+}
+
+// NewAlertRepository creates a new alert repository
+func NewAlertRepository(db *database.DB) *AlertRepository {
+	return &AlertRepository{db: db}
+}
+
+const alertColumns = `id, user_id::text, ticker, metric, operator, threshold, note, mode, cooldown_seconds, status,
+	source, last_value, last_evaluated_at, triggered_value, triggered_at, cooldown_until, snoozed_until,
+	trigger_count, created_at, updated_at, condition, deleted_at`
+
+func scanAlert(row pgx.Row) (*models.Alert, error) {
+	var a models.Alert
+	err := row.Scan(&a.ID, &a.UserID, &a.Ticker, &a.Metric, &a.Operator, &a.Threshold, &a.Note, &a.Mode, &a.CooldownSeconds,
+		&a.Status, &a.Source, &a.LastValue, &a.LastEvaluatedAt, &a.TriggeredValue, &a.TriggeredAt, &a.CooldownUntil,
+		&a.SnoozedUntil, &a.TriggerCount, &a.CreatedAt, &a.UpdatedAt, &a.Condition, &a.DeletedAt)
+	if err != nil {
+		return nil, err
+	}
+	a.AssetType = models.TickerAssetType(a.Ticker)
+	return &a, nil
+}
+
+// Create inserts an armed alert and fills in its generated fields
+func (r *AlertRepository) Create(ctx context.Context, a *models.Alert) error {
+	err := r.db.Pool.QueryRow(ctx, `
+		INSERT INTO alerts (user_id, ticker, metric, operator, threshold, condition, note, mode, cooldown_seconds)
+		VALUES ($1::uuid, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, status, source, trigger_count, created_at, updated_at`,
+		a.UserID, a.Ticker, a.Metric, a.Operator, a.Threshold, a.Condition, a.Note, a.Mode, a.CooldownSeconds,
+	).Scan(&a.ID, &a.Status, &a.Source, &a.TriggerCount, &a.CreatedAt, &a.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create alert: %w", err)
+	}
+	return nil
+}
+
+// List returns a user's alerts, newest first, optionally with one status ("" for all).
+// A nil user lists alerts without an owner.
+func (r *AlertRepository) List(ctx context.Context, userID *string, status string) ([]models.Alert, error) {
+	return r.query(ctx, `
+		SELECT `+alertColumns+`
+		FROM alerts
+		WHERE user_id IS NOT DISTINCT FROM $1::uuid AND deleted_at IS NULL AND ($2 = '' OR status = $2)
+		ORDER BY created_at DESC, id DESC`,
+		userID, status)
+}
+
+// ListDeleted returns a user's deleted alerts, most recently deleted first
+func (r *AlertRepository) ListDeleted(ctx context.Context, userID *string) ([]models.Alert, error) {
+	return r.query(ctx, `
+		SELECT `+alertColumns+`
+		FROM alerts
+		WHERE user_id IS NOT DISTINCT FROM $1::uuid AND deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, id DESC`,
+		userID)
+}
+
+// ListDue re-arms alerts whose cooldown or snooze has passed, then returns every armed
+// alert for the engine to evaluate
+func (r *AlertRepository) ListDue(ctx context.Context, now time.Time) ([]models.Alert, error) {
+	if _, err := r.db.Pool.Exec(ctx, `
+		UPDATE alerts SET status = 'armed', cooldown_until = NULL, snoozed_until = NULL, updated_at = NOW()
+		WHERE deleted_at IS NULL
+			AND ((status = 'cooldown' AND cooldown_until <= $1) OR (status = 'snoozed' AND snoozed_until <= $1))`,
+		now); err != nil {
+		return nil, fmt.Errorf("failed to re-arm alerts: %w", err)
+	}
+	return r.query(ctx, `
+		SELECT `+alertColumns+` FROM alerts WHERE status = 'armed' AND deleted_at IS NULL ORDER BY ticker, id`)
+}
+
+// query runs an alert SELECT and scans every row
+func (r *AlertRepository) query(ctx context.Context, sql string, args ...any) ([]models.Alert, error) {
+	rows, err := r.db.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alerts: %w", err)
+	}
+	defer rows.Close()
+
+	alerts := []models.Alert{}
+	for rows.Next() {
+		a, err := scanAlert(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan alert: %w", err)
+		}
+		alerts = append(alerts, *a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read alerts: %w", err)
+	}
+	return alerts, nil
+}
+
+// Get returns one of a user's alerts
+func (r *AlertRepository) Get(ctx context.Context, userID *string, id int64) (*models.Alert, error) {
+	a, err := scanAlert(r.db.Pool.QueryRow(ctx, `
+		SELECT `+alertColumns+` FROM alerts
+		WHERE id = $1 AND user_id IS NOT DISTINCT FROM $2::uuid AND deleted_at IS NULL`,
+		id, userID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alert: %w", err)
+	}
+	return a, nil
+}
+
+// Update saves an alert's rule or composite condition, note, mode, cooldown and status. Re-arming clears the
+// last trigger and any cooldown or snooze; changing the metric forgets the last value so a crossing
+// is not measured against another metric.
+func (r *AlertRepository) Update(ctx context.Context, a *models.Alert) error {
+	err := r.db.Pool.QueryRow(ctx, `
+		UPDATE alerts
+		SET last_value = CASE WHEN metric = $2 THEN last_value END,
+		    metric = $2, operator = $3, threshold = $4, note = $5, mode = $6, cooldown_seconds = $7, status = $8,
+		    condition = $9,
+		    triggered_value = CASE WHEN $8 = 'armed' THEN NULL ELSE triggered_value END,
+		    triggered_at = CASE WHEN $8 = 'armed' THEN NULL ELSE triggered_at END,
+		    cooldown_until = CASE WHEN $8 = 'cooldown' THEN cooldown_until END,
+		    snoozed_until = CASE WHEN $8 = 'snoozed' THEN snoozed_until END,
+		    updated_at = NOW()
+		WHERE id = $1 AND user_id IS NOT DISTINCT FROM $10::uuid AND deleted_at IS NULL
+		RETURNING last_value, triggered_value, triggered_at, cooldown_until, snoozed_until, updated_at`,
+		a.ID, a.Metric, a.Operator, a.Threshold, a.Note, a.Mode, a.CooldownSeconds, a.Status, a.Condition, a.UserID,
+	).Scan(&a.LastValue, &a.TriggeredValue, &a.TriggeredAt, &a.CooldownUntil, &a.SnoozedUntil, &a.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update alert: %w", err)
+	}
+	return nil
+}
+
+// Delete moves one of a user's alerts to their deleted alerts, where it stops being
+// evaluated and its triggers are hidden until it is restored or purged. Managed earnings
+// alerts are removed outright, since syncing recreates them.
+func (r *AlertRepository) Delete(ctx context.Context, userID *string, id int64) error {
+	tag, err := r.db.Pool.Exec(ctx, `
+		UPDATE alerts SET deleted_at = NOW()
+		WHERE id = $1 AND user_id IS NOT DISTINCT FROM $2::uuid AND source = 'user' AND deleted_at IS NULL`,
+		id, userID)
+	if err == nil && tag.RowsAffected() == 0 {
+		tag, err = r.db.Pool.Exec(ctx, `
+			DELETE FROM alerts WHERE id = $1 AND user_id IS NOT DISTINCT FROM $2::uuid AND source <> 'user'`,
+			id, userID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete alert: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Restore brings back one of a user's deleted alerts in the state it was deleted in; an
+// alert whose cooldown or snooze passed meanwhile re-arms on the next evaluation
+func (r *AlertRepository) Restore(ctx context.Context, userID *string, id int64) (*models.Alert, error) {
+	a, err := scanAlert(r.db.Pool.QueryRow(ctx, `
+		UPDATE alerts SET deleted_at = NULL, updated_at = NOW()
+		WHERE id = $1 AND user_id IS NOT DISTINCT FROM $2::uuid AND deleted_at IS NOT NULL
+		RETURNING `+alertColumns,
+		id, userID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore alert: %w", err)
+	}
+	return a, nil
+}
+
+// Purge permanently removes one of a user's deleted alerts and its triggers. Alerts that
+// aren't deleted are not found.
+func (r *AlertRepository) Purge(ctx context.Context, userID *string, id int64) error {
+	tag, err := r.db.Pool.Exec(ctx, `
+		DELETE FROM alerts
+		WHERE id = $1 AND user_id IS NOT DISTINCT FROM $2::uuid AND deleted_at IS NOT NULL`,
+		id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to purge alert: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// RecordEvaluation stores the latest value of an armed alert's metric (nil when
+// unavailable). Alerts changed since they were loaded are left alone.
+func (r *AlertRepository) RecordEvaluation(ctx context.Context, id int64, value *float64, at time.Time) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		UPDATE alerts SET last_value = $2, last_evaluated_at = $3
+		WHERE id = $1 AND status = 'armed' AND deleted_at IS NULL`,
+		id, value, at)
+	if err != nil {
+		return fmt.Errorf("failed to record alert evaluation: %w", err)
+	}
+	return nil
+}
+
+// Trigger records that an armed alert fired with value, keeping the snapshot of market data
+// it was evaluated against, and queues its notification for the owner's alert channels: a
+// recurring alert goes into cooldown and a one-shot alert stays triggered. Returns the
+// trigger record, or nil when the alert was changed since it was loaded and so did not fire.
+func (r *AlertRepository) Trigger(ctx context.Context, a *models.Alert, value float64, snapshot map[string]float64, at time.Time) (*models.AlertTrigger, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	updated, err := scanAlert(tx.QueryRow(ctx, `
+		UPDATE alerts
+		SET status = CASE WHEN mode = 'recurring' THEN 'cooldown' ELSE 'triggered' END,
+		    cooldown_until = CASE WHEN mode = 'recurring' THEN $3 + make_interval(secs => cooldown_seconds) END,
+		    last_value = $2, last_evaluated_at = $3,
+		    triggered_value = $2, triggered_at = $3,
+		    trigger_count = trigger_count + 1,
+		    updated_at = NOW()
+		WHERE id = $1 AND status = 'armed' AND deleted_at IS NULL
+		RETURNING `+alertColumns,
+		a.ID, value, at))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to trigger alert: %w", err)
+	}
+
+	if snapshot == nil {
+		snapshot = map[string]float64{}
+	}
+	t := &models.AlertTrigger{
+		AlertID:       a.ID,
+		Value:         value,
+		PreviousValue: a.LastValue,
+		Threshold:     updated.Threshold,
+		Snapshot:      snapshot,
+		TriggeredAt:   at,
+	}
+	err = tx.QueryRow(ctx, `
+		INSERT INTO alert_triggers (alert_id, value, previous_value, threshold, snapshot, triggered_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id`,
+		t.AlertID, t.Value, t.PreviousValue, t.Threshold, t.Snapshot, t.TriggeredAt).Scan(&t.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record alert trigger: %w", err)
+	}
+	if err := enqueueAlertDeliveries(ctx, tx, updated, t); err != nil {
+		return nil, err
+	}
+	if err := notifyAlertTrigger(ctx, tx, updated, t); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit alert trigger: %w", err)
+	}
+	*a = *updated
+	return t, nil
+}
+
+const alertTriggerColumns = `t.id, t.alert_id, t.value, t.previous_value, t.threshold, t.snapshot,
+	t.triggered_at, t.acknowledged_at`
+
+func scanAlertTrigger(row pgx.Row) (*models.AlertTrigger, error) {
+	var t models.AlertTrigger
+	err := row.Scan(&t.ID, &t.AlertID, &t.Value, &t.PreviousValue, &t.Threshold, &t.Snapshot,
+		&t.TriggeredAt, &t.AcknowledgedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// ListTriggers returns a user's alert triggers, newest first, with their alerts as they are
+// now
+func (r *AlertRepository) ListTriggers(ctx context.Context, userID *string, filter models.AlertTriggerFilter) ([]models.AlertNotification, error) {
+	return r.notifications(ctx, `
+		SELECT `+alertTriggerColumns+`
+		FROM alert_triggers t
+		JOIN alerts a ON a.id = t.alert_id
+		WHERE a.user_id IS NOT DISTINCT FROM $1::uuid AND a.deleted_at IS NULL
+			AND ($2 = 0 OR t.alert_id = $2)
+			AND (NOT $3 OR t.acknowledged_at IS NULL)
+			AND ($4 = 0 OR t.id < $4)
+		ORDER BY t.id DESC
+		LIMIT $5`,
+		userID, filter.AlertID, filter.Unacknowledged, filter.Before, filter.Limit)
+}
+
+// ListNotificationsSince returns a user's alert triggers recorded after a trigger ID, oldest
+// first, with their alerts as they are now
+func (r *AlertRepository) ListNotificationsSince(ctx context.Context, userID *string, afterID int64, limit int) ([]models.AlertNotification, error) {
+	return r.notifications(ctx, `
+		SELECT `+alertTriggerColumns+`
+		FROM alert_triggers t
+		JOIN alerts a ON a.id = t.alert_id
+		WHERE a.user_id IS NOT DISTINCT FROM $1::uuid AND a.deleted_at IS NULL AND t.id > $2
+		ORDER BY t.id
+		LIMIT $3`,
+		userID, afterID, limit)
+}
+
+// notifications runs an alert trigger SELECT and pairs each trigger with its alert
+func (r *AlertRepository) notifications(ctx context.Context, sql string, args ...any) ([]models.AlertNotification, error) {
+	rows, err := r.db.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alert triggers: %w", err)
+	}
+	defer rows.Close()
+
+	triggers := []models.AlertTrigger{}
+	for rows.Next() {
+		t, err := scanAlertTrigger(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan alert trigger: %w", err)
+		}
+		triggers = append(triggers, *t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read alert triggers: %w", err)
+	}
+
+	ids := make([]int64, len(triggers))
+	for i, t := range triggers {
+		ids[i] = t.AlertID
+	}
+	alerts, err := r.query(ctx, `SELECT `+alertColumns+` FROM alerts WHERE id = ANY($1)`, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int64]models.Alert, len(alerts))
+	for _, a := range alerts {
+		byID[a.ID] = a
+	}
+
+	notifications := make([]models.AlertNotification, 0, len(triggers))
+	for _, t := range triggers {
+		notifications = append(notifications, models.AlertNotification{Alert: byID[t.AlertID], Trigger: t})
+	}
+	return notifications, nil
+}
+
+// Acknowledge marks one of a user's alert triggers as seen. Acknowledging it again keeps
+// the first time.
+func (r *AlertRepository) Acknowledge(ctx context.Context, userID *string, triggerID int64) (*models.AlertTrigger, error) {
+	t, err := scanAlertTrigger(r.db.Pool.QueryRow(ctx, `
+		UPDATE alert_triggers t
+		SET acknowledged_at = COALESCE(t.acknowledged_at, NOW())
+		FROM alerts a
+		WHERE t.id = $2 AND a.id = t.alert_id AND a.user_id IS NOT DISTINCT FROM $1::uuid AND a.deleted_at IS NULL
+		RETURNING `+alertTriggerColumns,
+		userID, triggerID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to acknowledge alert trigger: %w", err)
+	}
+	return t, nil
+}
+
+// AcknowledgeAll marks every unacknowledged trigger of a user's alerts as seen, or only
+// those of one alert when alertID is not 0, and returns how many were
+func (r *AlertRepository) AcknowledgeAll(ctx context.Context, userID *string, alertID int64) (int64, error) {
+	tag, err := r.db.Pool.Exec(ctx, `
+		UPDATE alert_triggers t
+		SET acknowledged_at = NOW()
+		FROM alerts a
+		WHERE a.id = t.alert_id AND a.user_id IS NOT DISTINCT FROM $1::uuid AND a.deleted_at IS NULL
+			AND ($2 = 0 OR t.alert_id = $2) AND t.acknowledged_at IS NULL`,
+		userID, alertID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to acknowledge alert triggers: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// Snooze holds an alert until a time, whatever its state, after which it re-arms
+func (r *AlertRepository) Snooze(ctx context.Context, a *models.Alert, until time.Time) error {
+	updated, err := scanAlert(r.db.Pool.QueryRow(ctx, `
+		UPDATE alerts
+		SET status = 'snoozed', snoozed_until = $2, cooldown_until = NULL, updated_at = NOW()
+		WHERE id = $1 AND user_id IS NOT DISTINCT FROM $3::uuid AND deleted_at IS NULL
+		RETURNING `+alertColumns,
+		a.ID, until, a.UserID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to snooze alert: %w", err)
+	}
+	*a = *updated
+	return nil
+}
+
+// ListenTriggers calls fn with every alert trigger recorded by any instance, as it commits,
+// until the context ends or the listening connection fails
+func (r *AlertRepository) ListenTriggers(ctx context.Context, fn func(models.AlertNotification)) error {
+	conn, err := r.db.Pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire listener connection: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+alertTriggerChannel); err != nil {
+		return fmt.Errorf("failed to listen for alert triggers: %w", err)
+	}
+	defer func() {
+		// The connection returns to the pool, so stop listening on it
+		_, _ = conn.Exec(context.Background(), "UNLISTEN "+alertTriggerChannel)
+	}()
+
+	for {
+		n, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to wait for alert triggers: %w", err)
+		}
+		var notification models.AlertNotification
+		if err := json.Unmarshal([]byte(n.Payload), &notification); err != nil {
+			continue
+		}
+		fn(notification)
+	}
+}
+
+// GetEarningsSettings returns a user's earnings alert settings with the tickers they cover,
+// or disabled defaults when the user has none
+func (r *AlertRepository) GetEarningsSettings(ctx context.Context, userID *string) (*models.EarningsAlertSettings, error) {
+	s := &models.EarningsAlertSettings{
+		UserID:     userID,
+		DaysBefore: models.DefaultEarningsDaysBefore,
+		Portfolios: true,
+		Watchlists: true,
+	}
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT enabled, days_before, portfolios, watchlists, synced_at, updated_at
+		FROM earnings_alert_settings
+		WHERE user_id IS NOT DISTINCT FROM $1::uuid`,
+		userID).Scan(&s.Enabled, &s.DaysBefore, &s.Portfolios, &s.Watchlists, &s.SyncedAt, &s.UpdatedAt)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to get earnings alert settings: %w", err)
+	}
+
+	if err := r.db.Pool.QueryRow(ctx, `
+		SELECT COALESCE(array_agg(ticker ORDER BY ticker), '{}')
+		FROM alerts
+		WHERE source = 'earnings' AND user_id IS NOT DISTINCT FROM $1::uuid`,
+		userID).Scan(&s.Tickers); err != nil {
+		return nil, fmt.Errorf("failed to list earnings alert tickers: %w", err)
+	}
+	return s, nil
+}
+
+// ListEarningsSettings returns every enabled user's earnings alert settings
+func (r *AlertRepository) ListEarningsSettings(ctx context.Context) ([]models.EarningsAlertSettings, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT user_id::text, enabled, days_before, portfolios, watchlists, synced_at, updated_at
+		FROM earnings_alert_settings
+		WHERE enabled
+		ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list earnings alert settings: %w", err)
+	}
+	defer rows.Close()
+
+	settings := []models.EarningsAlertSettings{}
+	for rows.Next() {
+		var s models.EarningsAlertSettings
+		if err := rows.Scan(&s.UserID, &s.Enabled, &s.DaysBefore, &s.Portfolios, &s.Watchlists, &s.SyncedAt, &s.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan earnings alert settings: %w", err)
+		}
+		settings = append(settings, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read earnings alert settings: %w", err)
+	}
+	return settings, nil
+}
+
+// SaveEarningsSettings creates or replaces a user's earnings alert settings
+func (r *AlertRepository) SaveEarningsSettings(ctx context.Context, s *models.EarningsAlertSettings) error {
+	err := r.db.Pool.QueryRow(ctx, `
+		INSERT INTO earnings_alert_settings (user_id, enabled, days_before, portfolios, watchlists)
+		VALUES ($1::uuid, $2, $3, $4, $5)
+		ON CONFLICT (COALESCE(user_id, '00000000-0000-0000-0000-000000000000'::uuid))
+		DO UPDATE SET enabled = EXCLUDED.enabled, days_before = EXCLUDED.days_before,
+		              portfolios = EXCLUDED.portfolios, watchlists = EXCLUDED.watchlists,
+		              updated_at = NOW()
+		RETURNING synced_at, updated_at`,
+		s.UserID, s.Enabled, s.DaysBefore, s.Portfolios, s.Watchlists,
+	).Scan(&s.SyncedAt, &s.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save earnings alert settings: %w", err)
+	}
+	return nil
+}
+
+// SyncEarningsAlerts makes the user's managed earnings alerts cover exactly the tickers:
+// alerts on other tickers are removed, missing ones are created armed, and existing ones
+// take the new threshold and cooldown while keeping their state
+func (r *AlertRepository) SyncEarningsAlerts(ctx context.Context, userID *string, tickers []string, threshold float64, cooldownSeconds int, at time.Time) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `
+		DELETE FROM alerts
+		WHERE source = 'earnings' AND user_id IS NOT DISTINCT FROM $1::uuid AND NOT (ticker = ANY($2))`,
+		userID, tickers); err != nil {
+		return fmt.Errorf("failed to remove earnings alerts: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO alerts (user_id, ticker, metric, operator, threshold, mode, cooldown_seconds, source)
+		SELECT $1::uuid, t, 'days_to_earnings', '<=', $3, 'recurring', $4, 'earnings'
+		FROM unnest($2::text[]) t
+		ON CONFLICT (COALESCE(user_id, '00000000-0000-0000-0000-000000000000'::uuid), ticker) WHERE source = 'earnings'
+		DO UPDATE SET threshold = EXCLUDED.threshold, cooldown_seconds = EXCLUDED.cooldown_seconds, updated_at = NOW()
+		WHERE alerts.threshold <> EXCLUDED.threshold OR alerts.cooldown_seconds <> EXCLUDED.cooldown_seconds`,
+		userID, tickers, threshold, cooldownSeconds); err != nil {
+		return fmt.Errorf("failed to save earnings alerts: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE earnings_alert_settings SET synced_at = $2
+		WHERE user_id IS NOT DISTINCT FROM $1::uuid`,
+		userID, at); err != nil {
+		return fmt.Errorf("failed to record earnings alert sync: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit earnings alerts: %w", err)
+	}
+	return nil
+}
+
+// alertTriggerChannel is the Postgres notification channel alert triggers are published on
+const alertTriggerChannel = "alert_triggers"
+
+// maxNotifyPayload is the largest payload Postgres accepts for a notification, in bytes
+const maxNotifyPayload = 7999
+
+// notifyAlertTrigger publishes a trigger to listeners inside the caller's transaction, so it
+// is only seen once committed. An alert note that would overflow the payload is left out.
+func notifyAlertTrigger(ctx context.Context, q querier, a *models.Alert, t *models.AlertTrigger) error {
+	n := models.AlertNotification{Alert: *a, Trigger: *t}
+	payload, err := json.Marshal(n)
+	if err == nil && len(payload) > maxNotifyPayload {
+		n.Alert.Note = nil
+		payload, err = json.Marshal(n)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode alert payload: %w", err)
+	}
+	if _, err := q.Exec(ctx, `SELECT pg_notify($1, $2)`, alertTriggerChannel, string(payload)); err != nil {
+		return fmt.Errorf("failed to publish alert trigger: %w", err)
+	}
+	return nil
+}
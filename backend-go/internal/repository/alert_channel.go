@@ -0,0 +1,361 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/secrets"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// maxAlertChannelFailures is how many deliveries in a row may exhaust their retries before
+// the channel is disabled
+const maxAlertChannelFailures = 10
+
+// AlertChannelRepository persists alert delivery channels and their delivery outbox.
+// Webhook signing secrets are encrypted with box when it is set.
+type AlertChannelRepository struct {
+	db  *database.DB
+	box *secrets.Box
+}
+
+// NewAlertChannelRepository creates a new alert channel repository
+func NewAlertChannelRepository(db *database.DB, box *secrets.Box) *AlertChannelRepository {
+	return &AlertChannelRepository{db: db, box: box}
+}
+
+// alertChannelSecretOwner binds a channel's encrypted secret to its user
+func alertChannelSecretOwner(userID *string) string {
+	if userID == nil {
+		return "alert_channel:"
+	}
+	return "alert_channel:" + *userID
+}
+
+const alertChannelColumns = `c.id, c.user_id::text, c.kind, c.name, c.url, c.address, c.watchlist_id, c.active,
+	c.failure_count,
+	c.last_delivery_at, c.last_status, c.created_at, c.updated_at`
+
+const alertDeliveryColumns = `d.id, d.channel_id, d.trigger_id, d.event, d.payload, d.status, d.attempts,
+	d.next_attempt_at, d.response_status, d.last_error, d.created_at, d.delivered_at`
+
+func alertChannelDest(ch *models.AlertChannel) []any {
+	return []any{&ch.ID, &ch.UserID, &ch.Kind, &ch.Name, &ch.URL, &ch.Address, &ch.WatchlistID, &ch.Active,
+		&ch.FailureCount,
+		&ch.LastDeliveryAt, &ch.LastStatus, &ch.CreatedAt, &ch.UpdatedAt}
+}
+
+func alertDeliveryDest(d *models.AlertDelivery) []any {
+	return []any{&d.ID, &d.ChannelID, &d.TriggerID, &d.Event, &d.Payload, &d.Status, &d.Attempts,
+		&d.NextAttemptAt, &d.ResponseStatus, &d.LastError, &d.CreatedAt, &d.DeliveredAt}
+}
+
+// Create inserts an alert channel with its secret
+func (r *AlertChannelRepository) Create(ctx context.Context, ch *models.AlertChannel) error {
+	secret, err := sealSecret(r.box, ch.Secret, alertChannelSecretOwner(ch.UserID))
+	if err != nil {
+		return err
+	}
+	err = r.db.Pool.QueryRow(ctx, `
+		INSERT INTO alert_channels (user_id, kind, name, url, address, watchlist_id, secret, active)
+		VALUES ($1::uuid, $2, $3, $4, $5, $6, NULLIF($7, ''), $8)
+		RETURNING id, failure_count, created_at, updated_at`,
+		ch.UserID, ch.Kind, ch.Name, ch.URL, ch.Address, ch.WatchlistID, secret, ch.Active,
+	).Scan(&ch.ID, &ch.FailureCount, &ch.CreatedAt, &ch.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create alert channel: %w", err)
+	}
+	return nil
+}
+
+// List returns a user's alert channels, oldest first, without their secrets. A nil user
+// lists channels without an owner.
+func (r *AlertChannelRepository) List(ctx context.Context, userID *string) ([]models.AlertChannel, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT `+alertChannelColumns+`
+		FROM alert_channels c
+		WHERE c.user_id IS NOT DISTINCT FROM $1::uuid
+		ORDER BY c.id`,
+		userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alert channels: %w", err)
+	}
+	defer rows.Close()
+
+	channels := []models.AlertChannel{}
+	for rows.Next() {
+		var ch models.AlertChannel
+		if err := rows.Scan(alertChannelDest(&ch)...); err != nil {
+			return nil, fmt.Errorf("failed to scan alert channel: %w", err)
+		}
+		channels = append(channels, ch)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read alert channels: %w", err)
+	}
+	return channels, nil
+}
+
+// Get returns one of a user's alert channels without its secret
+func (r *AlertChannelRepository) Get(ctx context.Context, userID *string, id int64) (*models.AlertChannel, error) {
+	var ch models.AlertChannel
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT `+alertChannelColumns+` FROM alert_channels c WHERE c.id = $1 AND c.user_id IS NOT DISTINCT FROM $2::uuid`,
+		id, userID).Scan(alertChannelDest(&ch)...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alert channel: %w", err)
+	}
+	return &ch, nil
+}
+
+// Update saves a channel's name, URL, address, watchlist and active flag. Reactivating a channel clears its
+// failure count.
+func (r *AlertChannelRepository) Update(ctx context.Context, ch *models.AlertChannel) error {
+	err := r.db.Pool.QueryRow(ctx, `
+		UPDATE alert_channels
+		SET name = $2, url = $3, address = $4, watchlist_id = $5, active = $6,
+			failure_count = CASE WHEN $6 AND NOT active THEN 0 ELSE failure_count END,
+			updated_at = NOW()
+		WHERE id = $1 AND user_id IS NOT DISTINCT FROM $7::uuid
+		RETURNING failure_count, updated_at`,
+		ch.ID, ch.Name, ch.URL, ch.Address, ch.WatchlistID, ch.Active, ch.UserID,
+	).Scan(&ch.FailureCount, &ch.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update alert channel: %w", err)
+	}
+	return nil
+}
+
+// Delete removes one of a user's alert channels and its queued deliveries
+func (r *AlertChannelRepository) Delete(ctx context.Context, userID *string, id int64) error {
+	tag, err := r.db.Pool.Exec(ctx, `
+		DELETE FROM alert_channels WHERE id = $1 AND user_id IS NOT DISTINCT FROM $2::uuid`,
+		id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete alert channel: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Enqueue queues an event for one channel outside of any trigger, e.g. a ping
+func (r *AlertChannelRepository) Enqueue(ctx context.Context, ch *models.AlertChannel, event string, data any) (*models.AlertDelivery, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode alert payload: %w", err)
+	}
+
+	var d models.AlertDelivery
+	err = r.db.Pool.QueryRow(ctx, `
+		INSERT INTO alert_deliveries AS d (channel_id, event, payload)
+		VALUES ($1, $2, $3)
+		RETURNING `+alertDeliveryColumns,
+		ch.ID, event, json.RawMessage(payload)).Scan(alertDeliveryDest(&d)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to queue alert delivery: %w", err)
+	}
+	return &d, nil
+}
+
+// ListDeliveries returns a channel's most recent deliveries, newest first
+func (r *AlertChannelRepository) ListDeliveries(ctx context.Context, channelID int64, limit int) ([]models.AlertDelivery, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT `+alertDeliveryColumns+`
+		FROM alert_deliveries d
+		WHERE d.channel_id = $1
+		ORDER BY d.created_at DESC, d.id DESC
+		LIMIT $2`,
+		channelID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alert deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []models.AlertDelivery{}
+	for rows.Next() {
+		var d models.AlertDelivery
+		if err := rows.Scan(alertDeliveryDest(&d)...); err != nil {
+			return nil, fmt.Errorf("failed to scan alert delivery: %w", err)
+		}
+		deliveries = append(deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read alert deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// ClaimDue leases up to limit pending deliveries of active channels that are due, pushing
+// their next attempt out by lease so concurrent workers skip them while they are sent
+func (r *AlertChannelRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]models.PendingAlertDelivery, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		WITH due AS (
+			SELECT d.id
+			FROM alert_deliveries d
+			JOIN alert_channels c ON c.id = d.channel_id
+			WHERE d.status = 'pending' AND d.next_attempt_at <= NOW() AND c.active
+			ORDER BY d.next_attempt_at, d.id
+			LIMIT $1
+			FOR UPDATE OF d SKIP LOCKED
+		)
+		UPDATE alert_deliveries d
+		SET next_attempt_at = NOW() + make_interval(secs => $2)
+		FROM due, alert_channels c
+		WHERE d.id = due.id AND c.id = d.channel_id
+		RETURNING `+alertDeliveryColumns+`, `+alertChannelColumns+`, COALESCE(c.secret, '')`,
+		limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim alert deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	pending := []models.PendingAlertDelivery{}
+	for rows.Next() {
+		var p models.PendingAlertDelivery
+		dest := append(alertDeliveryDest(&p.Delivery), alertChannelDest(&p.Channel)...)
+		if err := rows.Scan(append(dest, &p.Channel.Secret)...); err != nil {
+			return nil, fmt.Errorf("failed to scan alert delivery: %w", err)
+		}
+		// A secret that doesn't open is left to be retried after the lease, when the
+		// missing encryption key may have been configured
+		if p.Channel.Secret, err = openSecret(r.box, p.Channel.Secret, alertChannelSecretOwner(p.Channel.UserID)); err != nil {
+			log.Printf("[Alerts] ⚠ Failed to decrypt secret of alert channel %d: %v", p.Channel.ID, err)
+			continue
+		}
+		pending = append(pending, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read alert deliveries: %w", err)
+	}
+	return pending, nil
+}
+
+// RecentDeliveries counts the notifications delivered to a user's channels of one kind
+// since a time, with the earliest of them
+func (r *AlertChannelRepository) RecentDeliveries(ctx context.Context, userID *string, kind string, since time.Time) (int, *time.Time, error) {
+	var count int
+	var earliest *time.Time
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT count(*), min(d.delivered_at)
+		FROM alert_deliveries d
+		JOIN alert_channels c ON c.id = d.channel_id
+		WHERE c.user_id IS NOT DISTINCT FROM $1::uuid AND c.kind = $2
+			AND d.status = 'delivered' AND d.delivered_at > $3`,
+		userID, kind, since).Scan(&count, &earliest)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to count alert deliveries: %w", err)
+	}
+	return count, earliest, nil
+}
+
+// Postpone moves a pending delivery's next attempt without counting an attempt
+func (r *AlertChannelRepository) Postpone(ctx context.Context, d *models.AlertDelivery, until time.Time) error {
+	if _, err := r.db.Pool.Exec(ctx, `
+		UPDATE alert_deliveries SET next_attempt_at = $2 WHERE id = $1 AND status = 'pending'`,
+		d.ID, until); err != nil {
+		return fmt.Errorf("failed to postpone alert delivery: %w", err)
+	}
+	d.NextAttemptAt = until
+	return nil
+}
+
+// RecordAttempt stores the outcome of sending a delivery. A delivered notification resets
+// the channel's failure count; a failed one is retried at retryAt, or marked failed when
+// retryAt is nil, which disables the channel after too many failures in a row.
+func (r *AlertChannelRepository) RecordAttempt(ctx context.Context, d *models.AlertDelivery, responseStatus *int, attemptErr error, retryAt *time.Time) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var lastError *string
+	status := models.DeliveryDelivered
+	if attemptErr != nil {
+		msg := attemptErr.Error()
+		lastError = &msg
+		status = models.DeliveryPending
+		if retryAt == nil {
+			status = models.DeliveryFailed
+		}
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE alert_deliveries
+		SET status = $2, attempts = attempts + 1, response_status = $3, last_error = $4,
+			next_attempt_at = COALESCE($5, next_attempt_at),
+			delivered_at = CASE WHEN $2 = 'delivered' THEN NOW() END
+		WHERE id = $1`,
+		d.ID, status, responseStatus, lastError, retryAt)
+	if err != nil {
+		return fmt.Errorf("failed to record alert delivery: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE alert_channels
+		SET last_delivery_at = NOW(), last_status = $2,
+			failure_count = CASE $3 WHEN 'delivered' THEN 0 WHEN 'failed' THEN failure_count + 1 ELSE failure_count END,
+			active = active AND NOT ($3 = 'failed' AND failure_count + 1 >= $4)
+		WHERE id = $1`,
+		d.ChannelID, responseStatus, status, maxAlertChannelFailures)
+	if err != nil {
+		return fmt.Errorf("failed to update alert channel health: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit alert delivery: %w", err)
+	}
+	d.Status = status
+	return nil
+}
+
+// enqueueAlertDeliveries queues a trigger's notification for every active channel of the
+// alert's owner, inside the caller's transaction so only recorded triggers are sent. A
+// channel scoped to a watchlist only gets alerts on a ticker in the watchlist, or on an
+// option contract whose underlying is.
+func enqueueAlertDeliveries(ctx context.Context, q querier, a *models.Alert, t *models.AlertTrigger) error {
+	payload, err := json.Marshal(models.AlertNotification{Alert: *a, Trigger: *t})
+	if err != nil {
+		return fmt.Errorf("failed to encode alert payload: %w", err)
+	}
+	underlying := a.Ticker
+	if symbol, err := models.ParseOptionTicker(a.Ticker); err == nil {
+		underlying = symbol.Underlying
+	}
+	_, err = q.Exec(ctx, `
+		INSERT INTO alert_deliveries (channel_id, trigger_id, event, payload)
+		SELECT c.id, $2, $3, $4
+		FROM alert_channels c
+		WHERE c.user_id IS NOT DISTINCT FROM $1::uuid AND c.active
+			AND (c.watchlist_id IS NULL OR EXISTS (
+				SELECT 1 FROM watchlist_items wi
+				WHERE wi.watchlist_id = c.watchlist_id AND wi.ticker IN ($5, $6)))`,
+		a.UserID, t.ID, models.EventAlertTriggered, json.RawMessage(payload), a.Ticker, underlying)
+	if err != nil {
+		return fmt.Errorf("failed to queue alert deliveries: %w", err)
+	}
+	return nil
+}
+
+// RotateSecrets re-encrypts the channels' webhook signing secrets with the primary
+// encryption key, including those stored in the clear, and returns how many it re-encrypted
+func (r *AlertChannelRepository) RotateSecrets(ctx context.Context) (int, error) {
+	return rotateSecrets(ctx, r.db.Pool, r.box, "alert channel secret",
+		`SELECT id::text, 'alert_channel:' || COALESCE(user_id::text, ''), secret FROM alert_channels WHERE secret IS NOT NULL`,
+		`UPDATE alert_channels SET secret = $2, updated_at = NOW() WHERE id = $1::bigint AND secret = $3`,
+		true)
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/database"
+)
+
+// AllocationRepository persists the target allocations of portfolios
+type AllocationRepository struct {
+	db *database.DB
+}
+
+// NewAllocationRepository creates a new allocation repository
+func NewAllocationRepository(db *database.DB) *AllocationRepository {
+	return &AllocationRepository{db: db}
+}
+
+// List returns a portfolio's allocation targets, largest first
+func (r *AllocationRepository) List(ctx context.Context, portfolioID int64) ([]models.AllocationTarget, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT kind, name, tickers, target_percent
+		FROM allocation_targets
+		WHERE portfolio_id = $1
+		ORDER BY target_percent DESC, name`,
+		portfolioID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list allocation targets: %w", err)
+	}
+	defer rows.Close()
+
+	targets := []models.AllocationTarget{}
+	for rows.Next() {
+		var t models.AllocationTarget
+		if err := rows.Scan(&t.Kind, &t.Name, &t.Tickers, &t.Percent); err != nil {
+			return nil, fmt.Errorf("failed to scan allocation target: %w", err)
+		}
+		targets = append(targets, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read allocation targets: %w", err)
+	}
+	return targets, nil
+}
+
+// Replace swaps a portfolio's allocation targets for the given set in one transaction;
+// an empty set clears them
+func (r *AllocationRepository) Replace(ctx context.Context, portfolioID int64, targets []models.AllocationTarget) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM allocation_targets WHERE portfolio_id = $1`, portfolioID); err != nil {
+		return fmt.Errorf("failed to clear allocation targets: %w", err)
+	}
+	for _, t := range targets {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO allocation_targets (portfolio_id, kind, name, tickers, target_percent)
+			VALUES ($1, $2, $3, $4, $5)`,
+			portfolioID, t.Kind, t.Name, t.Tickers, t.Percent)
+		if isUniqueViolation(err) {
+			return ErrDuplicate
+		}
+		if err != nil {
+			return fmt.Errorf("failed to save allocation target: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit allocation targets: %w", err)
+	}
+	return nil
+}
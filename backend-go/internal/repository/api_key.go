@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// apiKeyUseResolution is how stale a key's last use may get before a request records it
+// again, so busy scripts do not write on every call
+const apiKeyUseResolution = time.Minute
+
+// APIKeyRepository persists users' API keys by their hashes
+type APIKeyRepository struct {
+	db *database.DB
+}
+
+// NewAPIKeyRepository creates a new API key repository
+func NewAPIKeyRepository(db *database.DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+const apiKeyColumns = `id, user_id::text, name, prefix, scopes, expires_at, last_used_at, revoked_at, created_at`
+
+func scanAPIKey(row pgx.Row) (*models.APIKey, error) {
+	var k models.APIKey
+	err := row.Scan(&k.ID, &k.UserID, &k.Name, &k.Prefix, &k.Scopes, &k.ExpiresAt, &k.LastUsedAt, &k.RevokedAt, &k.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
+
+// Create stores a new key by its hash, unless its owner already holds the most unrevoked
+// keys allowed
+func (r *APIKeyRepository) Create(ctx context.Context, k *models.APIKey, hash string) error {
+	err := r.db.Pool.QueryRow(ctx, `
+		INSERT INTO api_keys (user_id, name, prefix, key_hash, scopes, expires_at)
+		SELECT $1::uuid, $2, $3, $4, $5, $6
+		WHERE (SELECT COUNT(*) FROM api_keys WHERE user_id = $1::uuid AND revoked_at IS NULL) < $7
+		RETURNING id, created_at`,
+		k.UserID, k.Name, k.Prefix, hash, k.Scopes, k.ExpiresAt, models.MaxAPIKeys,
+	).Scan(&k.ID, &k.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrTooManyAPIKeys
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create API key: %w", err)
+	}
+	return nil
+}
+
+// List returns a user's API keys, newest first, including revoked and expired ones
+func (r *APIKeyRepository) List(ctx context.Context, userID string) ([]models.APIKey, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT `+apiKeyColumns+`
+		FROM api_keys
+		WHERE user_id = $1::uuid
+		ORDER BY created_at DESC, id DESC`,
+		userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := []models.APIKey{}
+	for rows.Next() {
+		k, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan API key: %w", err)
+		}
+		keys = append(keys, *k)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read API keys: %w", err)
+	}
+	return keys, nil
+}
+
+// Revoke disables one of a user's keys immediately; revoking twice keeps the first
+// revocation time
+func (r *APIKeyRepository) Revoke(ctx context.Context, userID string, id int64) (*models.APIKey, error) {
+	k, err := scanAPIKey(r.db.Pool.QueryRow(ctx, `
+		UPDATE api_keys SET revoked_at = COALESCE(revoked_at, NOW())
+		WHERE user_id = $1::uuid AND id = $2
+		RETURNING `+apiKeyColumns,
+		userID, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to revoke API key: %w", err)
+	}
+	return k, nil
+}
+
+// Use returns the key with the hash, revoked or not, and records that it was used
+func (r *APIKeyRepository) Use(ctx context.Context, hash string, now time.Time) (*models.APIKey, error) {
+	k, err := scanAPIKey(r.db.Pool.QueryRow(ctx, `
+		SELECT `+apiKeyColumns+` FROM api_keys WHERE key_hash = $1`,
+		hash))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+
+	if k.Active(now) && (k.LastUsedAt == nil || now.Sub(*k.LastUsedAt) >= apiKeyUseResolution) {
+		if _, err := r.db.Pool.Exec(ctx, `UPDATE api_keys SET last_used_at = $2 WHERE id = $1`, k.ID, now); err != nil {
+			return nil, fmt.Errorf("failed to record API key use: %w", err)
+		}
+		k.LastUsedAt = &now
+	}
+	return k, nil
+}
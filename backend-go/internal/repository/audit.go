@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/database"
+)
+
+// AuditRepository appends to and reads the audit log. Entries cannot be changed once
+// recorded.
+type AuditRepository struct {
+	db *database.DB
+}
+
+// NewAuditRepository creates a new audit repository
+func NewAuditRepository(db *database.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// Connected reports whether the database has been reached, so entries can be recorded
+func (r *AuditRepository) Connected() bool {
+	return r.db.Connected()
+}
+
+const auditColumns = `id, actor_id::text, api_key_id, ip::text, action, resource_type, resource_id, before, after, created_at`
+
+// Record appends an entry to the audit log
+func (r *AuditRepository) Record(ctx context.Context, e *models.AuditEntry) error {
+	err := r.db.Pool.QueryRow(ctx, `
+		INSERT INTO audit_log (actor_id, api_key_id, ip, action, resource_type, resource_id, before, after)
+		VALUES ($1::uuid, $2, $3::text::inet, $4, $5, $6, $7, $8)
+		RETURNING id, created_at`,
+		e.ActorID, e.APIKeyID, e.IP, e.Action, e.ResourceType, e.ResourceID, jsonOrNull(e.Before), jsonOrNull(e.After),
+	).Scan(&e.ID, &e.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+	return nil
+}
+
+// List returns audit log entries matching the filter, newest first
+func (r *AuditRepository) List(ctx context.Context, f models.AuditFilter) ([]models.AuditEntry, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT `+auditColumns+`
+		FROM audit_log
+		WHERE ($1 = '' OR actor_id = NULLIF($1, '')::uuid)
+			AND ($2 = '' OR action = $2)
+			AND ($3 = '' OR resource_type = $3)
+			AND ($4 = '' OR resource_id = $4)
+			AND ($5 = 0 OR id < $5)
+		ORDER BY id DESC
+		LIMIT $6`,
+		f.ActorID, f.Action, f.ResourceType, f.ResourceID, f.Before, f.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []models.AuditEntry{}
+	for rows.Next() {
+		var e models.AuditEntry
+		var before, after []byte
+		if err := rows.Scan(&e.ID, &e.ActorID, &e.APIKeyID, &e.IP, &e.Action, &e.ResourceType, &e.ResourceID,
+			&before, &after, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		e.Before, e.After = before, after
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit entries: %w", err)
+	}
+	return entries, nil
+}
+
+// jsonOrNull passes a JSON document to a JSONB column, storing SQL NULL when it is empty
+func jsonOrNull(doc []byte) any {
+	if len(doc) == 0 {
+		return nil
+	}
+	return string(doc)
+}
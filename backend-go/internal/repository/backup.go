@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/aaronbengochea/periscope/backend-go/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// BackupTables are the tables Periscope owns, in an order they can be restored in, each
+// after those it references. Supabase's auth schema and the Massive quote cache are left to
+// Supabase's own backups and to Massive.
+var BackupTables = []string{
+	"users", "user_settings", "api_keys", "usage_daily", "audit_log",
+	"portfolios", "positions", "transactions", "position_lots", "lot_closures",
+	"portfolio_snapshots", "position_alerts", "dividends", "strategies", "share_links",
+	"allocation_targets", "journal_entries", "journal_attachments", "paper_orders",
+	"webhooks", "webhook_deliveries",
+	"watchlists", "watchlist_items",
+	"alerts", "alert_triggers", "earnings_alert_settings", "alert_channels", "alert_deliveries",
+	"iv_history", "options_snapshots", "options_snapshot_contracts",
+	"options_snapshot_contracts_daily",
+}
+
+// BackupRepository dumps Periscope's tables for backups
+type BackupRepository struct {
+	db *database.DB
+}
+
+// NewBackupRepository creates a new backup repository
+func NewBackupRepository(db *database.DB) *BackupRepository {
+	return &BackupRepository{db: db}
+}
+
+// Dump writes each of tables as CSV with a header line to the writer open returns for it,
+// closing it afterwards, all read in a single snapshot so the tables agree with each other.
+// It returns the latest applied migration, which the dump's columns match, and the number
+// of rows written per table.
+func (r *BackupRepository) Dump(ctx context.Context, tables []string, open func(table string) (io.WriteCloser, error)) (string, map[string]int64, error) {
+	tx, err := r.db.Pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var version string
+	err = tx.QueryRow(ctx, `SELECT COALESCE(MAX(version), '') FROM schema_migrations`).Scan(&version)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	rows := make(map[string]int64, len(tables))
+	for _, table := range tables {
+		w, err := open(table)
+		if err != nil {
+			return "", nil, err
+		}
+		// Selecting rather than copying the table directly also reads hypertables' chunks
+		tag, err := tx.Conn().PgConn().CopyTo(ctx, w,
+			`COPY (SELECT * FROM `+pgx.Identifier{table}.Sanitize()+`) TO STDOUT WITH (FORMAT csv, HEADER)`)
+		if closeErr := w.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to dump %s: %w", table, err)
+		}
+		rows[table] = tag.RowsAffected()
+	}
+	return version, rows, nil
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/database"
+)
+
+// DividendRepository persists the dividends earned by share positions and keeps each
+// position's running dividend total in step
+type DividendRepository struct {
+	db *database.DB
+}
+
+// NewDividendRepository creates a new dividend repository
+func NewDividendRepository(db *database.DB) *DividendRepository {
+	return &DividendRepository{db: db}
+}
+
+// ReplaceForPosition stores a position's dividends with ex-dividend dates on or after the
+// given YYYY-MM-DD date, removing earlier-recorded ones in that window that are no longer
+// earned, and refreshes the position's total of paid dividends in the same transaction
+func (r *DividendRepository) ReplaceForPosition(ctx context.Context, positionID int64, since string, dividends []models.Dividend) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	exDates := make([]string, len(dividends))
+	for i, d := range dividends {
+		exDates[i] = d.ExDividendDate
+	}
+	if _, err := tx.Exec(ctx, `
+		DELETE FROM dividends
+		WHERE position_id = $1 AND ex_dividend_date >= $2::date AND NOT (ex_dividend_date = ANY($3::date[]))`,
+		positionID, since, exDates); err != nil {
+		return fmt.Errorf("failed to remove stale dividends: %w", err)
+	}
+
+	for i := range dividends {
+		d := &dividends[i]
+		err := tx.QueryRow(ctx, `
+			INSERT INTO dividends (portfolio_id, position_id, ticker, ex_dividend_date, pay_date, cash_amount,
+				frequency, quantity, amount, status)
+			VALUES ($1, $2, $3, $4::date, $5::date, $6, $7, $8, $9, $10)
+			ON CONFLICT (position_id, ex_dividend_date)
+			DO UPDATE SET pay_date = EXCLUDED.pay_date,
+			              cash_amount = EXCLUDED.cash_amount,
+			              frequency = EXCLUDED.frequency,
+			              quantity = EXCLUDED.quantity,
+			              amount = EXCLUDED.amount,
+			              status = EXCLUDED.status,
+			              updated_at = NOW()
+			RETURNING id, created_at, updated_at`,
+			d.PortfolioID, positionID, d.Ticker, d.ExDividendDate, d.PayDate, d.CashAmount,
+			d.Frequency, d.Quantity, d.Amount, d.Status,
+		).Scan(&d.ID, &d.CreatedAt, &d.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to upsert dividend: %w", err)
+		}
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE positions
+		SET dividends = (SELECT COALESCE(SUM(amount), 0) FROM dividends WHERE position_id = $1 AND status = 'paid'),
+		    updated_at = NOW()
+		WHERE id = $1`,
+		positionID); err != nil {
+		return fmt.Errorf("failed to refresh position dividends: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// ListByPortfolio returns a portfolio's dividends, newest ex-dividend date first
+func (r *DividendRepository) ListByPortfolio(ctx context.Context, portfolioID int64) ([]models.Dividend, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT id, portfolio_id, position_id, ticker, ex_dividend_date::text, pay_date::text, cash_amount,
+		       frequency, quantity, amount, status, created_at, updated_at
+		FROM dividends
+		WHERE portfolio_id = $1
+		ORDER BY ex_dividend_date DESC, ticker, id`,
+		portfolioID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dividends: %w", err)
+	}
+	defer rows.Close()
+
+	dividends := []models.Dividend{}
+	for rows.Next() {
+		var d models.Dividend
+		if err := rows.Scan(&d.ID, &d.PortfolioID, &d.PositionID, &d.Ticker, &d.ExDividendDate, &d.PayDate, &d.CashAmount,
+			&d.Frequency, &d.Quantity, &d.Amount, &d.Status, &d.CreatedAt, &d.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan dividend: %w", err)
+		}
+		dividends = append(dividends, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read dividends: %w", err)
+	}
+	return dividends, nil
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/ledger"
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// Expire settles an expired option leg against the underlying's closing price on its
+// expiration date. The leg's remaining quantity is closed at zero as an expire, assign or
+// exercise transaction; assignments and exercises then deliver the shares at the strike,
+// reducing an opposite share position in the underlying first and opening or adding to one
+// on the delivered side with the rest.
+func (r *TransactionRepository) Expire(ctx context.Context, portfolioID, positionID int64, underlyingClose float64) (*models.Expiration, error) {
+	var result *models.Expiration
+	err := r.db.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		p, err := lockOpenPosition(ctx, tx, portfolioID, positionID)
+		if err != nil {
+			return err
+		}
+		if !p.IsOption() || p.ExpirationDate == nil || p.ContractType == nil || p.StrikePrice == nil {
+			return fmt.Errorf("position %d is not an option leg", positionID)
+		}
+
+		settlement := ledger.Settle(*p.ContractType, p.Side, *p.StrikePrice, underlyingClose)
+		quantity := p.Quantity
+		t, err := closePosition(ctx, tx, p, settlement.Action, Trade{
+			Quantity: quantity,
+			Price:    0,
+			TradedAt: *p.ExpirationDate,
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `UPDATE positions SET close_reason = $2 WHERE id = $1`, p.ID, settlement.CloseReason); err != nil {
+			return fmt.Errorf("failed to mark position %s: %w", settlement.CloseReason, err)
+		}
+		p.CloseReason = &settlement.CloseReason
+
+		result = &models.Expiration{
+			Position:        *p,
+			UnderlyingClose: underlyingClose,
+			Transaction:     *t,
+		}
+		if settlement.DeliverSide == "" {
+			return nil
+		}
+
+		result.Deliveries, err = deliverShares(ctx, tx, p, settlement.DeliverSide, Trade{
+			Quantity: quantity * float64(p.Multiplier),
+			Price:    *p.StrikePrice,
+			TradedAt: *p.ExpirationDate,
+			Related:  &t.ID,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// deliverShares books the share side of an assignment or exercise. Bought shares cover an
+// open short position first and sold shares reduce an open long position first; whatever
+// remains opens or adds to a share position on the delivered side.
+func deliverShares(ctx context.Context, q querier, option *models.Position, side string, trade Trade) ([]models.Transaction, error) {
+	opposite := models.SideLong
+	if side == models.SideLong {
+		opposite = models.SideShort
+	}
+
+	var deliveries []models.Transaction
+	remaining := trade.Quantity
+
+	held, err := lockOpenPositionByTicker(ctx, q, option.PortfolioID, option.UnderlyingTicker, opposite)
+	switch {
+	case err == nil:
+		fill := trade
+		fill.Quantity = math.Min(remaining, held.Quantity)
+		t, err := closePosition(ctx, q, held, models.TransactionClose, fill)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, *t)
+		remaining -= fill.Quantity
+	case !errors.Is(err, errNoOpenPosition):
+		return nil, err
+	}
+
+	if remaining <= 0 {
+		return deliveries, nil
+	}
+	fill := trade
+	fill.Quantity = remaining
+
+	held, err = lockOpenPositionByTicker(ctx, q, option.PortfolioID, option.UnderlyingTicker, side)
+	switch {
+	case err == nil:
+		t, err := addToPosition(ctx, q, held, fill)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, *t)
+	case errors.Is(err, errNoOpenPosition):
+		shares := &models.Position{
+			PortfolioID:      option.PortfolioID,
+			AssetType:        models.AssetTypeStock,
+			Ticker:           option.UnderlyingTicker,
+			UnderlyingTicker: option.UnderlyingTicker,
+			Side:             side,
+			Quantity:         fill.Quantity,
+			Multiplier:       1,
+			OpenPrice:        fill.Price,
+			OpenedAt:         fill.TradedAt,
+		}
+		t, err := openPosition(ctx, q, shares, models.TransactionOpen, 0, fill.Related)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, *t)
+	default:
+		return nil, err
+	}
+	return deliveries, nil
+}
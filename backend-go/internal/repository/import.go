@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/ledger"
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// errNoOpenPosition is reported on import rows that add to or close a position that is not open
+var errNoOpenPosition = errors.New("no matching open position")
+
+// Import applies parsed trades to a portfolio in file order inside a single database
+// transaction, so closes can match positions opened earlier in the same file. Each row runs
+// in its own savepoint and row-level problems are reported per row. The transaction is
+// committed only when every row succeeds and dryRun is false; the returned flag reports
+// whether it was.
+func (r *TransactionRepository) Import(ctx context.Context, portfolioID int64, trades []models.ImportedTrade, dryRun bool) ([]models.ImportRowResult, bool, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	results := make([]models.ImportRowResult, 0, len(trades))
+	failed := 0
+	for i := range trades {
+		trade := &trades[i]
+		result := models.ImportRowResult{
+			Row:    trade.Row,
+			Status: models.ImportRowOK,
+			Action: trade.Action,
+			Ticker: trade.Ticker,
+		}
+
+		savepoint, err := tx.Begin(ctx)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to create savepoint: %w", err)
+		}
+
+		if err := importTrade(ctx, savepoint, portfolioID, trade, &result); err != nil {
+			savepoint.Rollback(ctx)
+			message, ok := importRowError(err)
+			if !ok {
+				return nil, false, fmt.Errorf("row %d: %w", trade.Row, err)
+			}
+			result = models.ImportRowResult{
+				Row:    trade.Row,
+				Status: models.ImportRowError,
+				Action: trade.Action,
+				Ticker: trade.Ticker,
+				Error:  message,
+			}
+			failed++
+		} else if err := savepoint.Commit(ctx); err != nil {
+			return nil, false, fmt.Errorf("failed to release savepoint: %w", err)
+		}
+
+		results = append(results, result)
+	}
+
+	if dryRun || failed > 0 {
+		log.Printf("[Import] Rolled back import into portfolio %d (dry_run=%v, %d of %d rows failed)",
+			portfolioID, dryRun, failed, len(trades))
+		return results, false, nil
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return results, true, nil
+}
+
+// importTrade applies one trade, filling in the IDs it created
+func importTrade(ctx context.Context, q querier, portfolioID int64, trade *models.ImportedTrade, result *models.ImportRowResult) error {
+	// Broker opens scale into an open position in the same contract and side
+	action := trade.Action
+	var p *models.Position
+	if action == models.TransactionOpen && trade.AddToOpen {
+		existing, err := lockOpenPositionByTicker(ctx, q, portfolioID, trade.Ticker, trade.Side)
+		switch {
+		case err == nil:
+			p, action = existing, models.TransactionAdd
+			result.Action = action
+		case !errors.Is(err, errNoOpenPosition):
+			return err
+		}
+	}
+
+	if action == models.TransactionOpen {
+		p = &models.Position{
+			PortfolioID:      portfolioID,
+			AssetType:        trade.AssetType,
+			Ticker:           trade.Ticker,
+			UnderlyingTicker: trade.UnderlyingTicker,
+			ContractType:     trade.ContractType,
+			StrikePrice:      trade.StrikePrice,
+			ExpirationDate:   trade.ExpirationDate,
+			Side:             trade.Side,
+			Quantity:         trade.Quantity,
+			Multiplier:       trade.Multiplier,
+			OpenPrice:        trade.Price,
+			OpenedAt:         trade.TradedAt,
+		}
+		t, err := openPosition(ctx, q, p, models.TransactionOpen, trade.Fees, nil)
+		if err != nil {
+			return err
+		}
+		result.PositionID = &p.ID
+		result.TransactionID = &t.ID
+		return nil
+	}
+
+	if p == nil {
+		var err error
+		if p, err = lockOpenPositionByTicker(ctx, q, portfolioID, trade.Ticker, trade.Side); err != nil {
+			return err
+		}
+	}
+
+	fill := Trade{
+		Quantity: trade.Quantity,
+		Price:    trade.Price,
+		Fees:     trade.Fees,
+		TradedAt: trade.TradedAt,
+	}
+	var t *models.Transaction
+	var err error
+	if action == models.TransactionAdd {
+		t, err = addToPosition(ctx, q, p, fill)
+	} else {
+		t, err = closePosition(ctx, q, p, models.TransactionClose, fill)
+	}
+	if err != nil {
+		return err
+	}
+
+	result.PositionID = &p.ID
+	result.TransactionID = &t.ID
+	result.RealizedPnL = t.RealizedPnL
+	return nil
+}
+
+// lockOpenPositionByTicker loads the oldest open position in a portfolio for a ticker,
+// optionally restricted to one side, for update
+func lockOpenPositionByTicker(ctx context.Context, q querier, portfolioID int64, ticker, side string) (*models.Position, error) {
+	p, err := scanPosition(q.QueryRow(ctx, `
+		SELECT `+positionColumns+`
+		FROM positions
+		WHERE portfolio_id = $1 AND ticker = $2 AND status = 'open' AND ($3 = '' OR side = $3)
+		ORDER BY opened_at, id
+		LIMIT 1
+		FOR UPDATE`,
+		portfolioID, ticker, side))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errNoOpenPosition
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock position: %w", err)
+	}
+	return p, nil
+}
+
+// importRowError turns expected trade problems into a row message; ok is false for
+// unexpected failures that should abort the import
+func importRowError(err error) (string, bool) {
+	switch {
+	case errors.Is(err, errNoOpenPosition):
+		return "no open position for this ticker to add to or close", true
+	case errors.Is(err, ledger.ErrInsufficientQuantity):
+		return "quantity exceeds the open quantity", true
+	}
+	return "", false
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+)
+
+// The interfaces below are what handlers, services and jobs depend on, so they can be run
+// against an in-memory fake instead of a live database. The pgx-backed repositories in this
+// package implement them.
+
+// PortfolioRepo stores portfolios. A nil userID reaches the portfolios without an owner, for
+// deployments without auth.
+type PortfolioRepo interface {
+	Create(ctx context.Context, p *models.Portfolio) error
+	List(ctx context.Context, userID *string) ([]models.Portfolio, error)
+	ListAll(ctx context.Context) ([]models.Portfolio, error)
+	Get(ctx context.Context, userID *string, id int64) (*models.Portfolio, error)
+	GetAny(ctx context.Context, id int64) (*models.Portfolio, error)
+	Update(ctx context.Context, p *models.Portfolio) error
+	Delete(ctx context.Context, userID *string, id int64) error
+	ListDeleted(ctx context.Context, userID *string) ([]models.Portfolio, error)
+	Restore(ctx context.Context, userID *string, id int64) (*models.Portfolio, error)
+	Purge(ctx context.Context, userID *string, id int64) error
+}
+
+// WatchlistRepo stores watchlists and their tickers
+type WatchlistRepo interface {
+	Create(ctx context.Context, w *models.Watchlist, tickers []string) error
+	List(ctx context.Context, userID *string) ([]models.Watchlist, error)
+	Get(ctx context.Context, userID *string, id int64) (*models.Watchlist, error)
+	Update(ctx context.Context, w *models.Watchlist) error
+	Delete(ctx context.Context, userID *string, id int64) error
+	ListDeleted(ctx context.Context, userID *string) ([]models.Watchlist, error)
+	Restore(ctx context.Context, userID *string, id int64) (*models.Watchlist, error)
+	Purge(ctx context.Context, userID *string, id int64) error
+	AddItems(ctx context.Context, w *models.Watchlist, tickers []string) error
+	ReplaceItems(ctx context.Context, w *models.Watchlist, tickers []string) error
+	RemoveItem(ctx context.Context, w *models.Watchlist, ticker string) error
+}
+
+// AlertRepo stores a user's market alerts, their triggers and the earnings alert settings
+type AlertRepo interface {
+	Create(ctx context.Context, a *models.Alert) error
+	List(ctx context.Context, userID *string, status string) ([]models.Alert, error)
+	Get(ctx context.Context, userID *string, id int64) (*models.Alert, error)
+	Update(ctx context.Context, a *models.Alert) error
+	Delete(ctx context.Context, userID *string, id int64) error
+	ListDeleted(ctx context.Context, userID *string) ([]models.Alert, error)
+	Restore(ctx context.Context, userID *string, id int64) (*models.Alert, error)
+	Purge(ctx context.Context, userID *string, id int64) error
+	Snooze(ctx context.Context, a *models.Alert, until time.Time) error
+	ListTriggers(ctx context.Context, userID *string, filter models.AlertTriggerFilter) ([]models.AlertNotification, error)
+	Acknowledge(ctx context.Context, userID *string, triggerID int64) (*models.AlertTrigger, error)
+	AcknowledgeAll(ctx context.Context, userID *string, alertID int64) (int64, error)
+	GetEarningsSettings(ctx context.Context, userID *string) (*models.EarningsAlertSettings, error)
+	SaveEarningsSettings(ctx context.Context, s *models.EarningsAlertSettings) error
+}
+
+// SnapshotRepo stores daily portfolio snapshots
+type SnapshotRepo interface {
+	Upsert(ctx context.Context, s *models.PortfolioSnapshot) error
+	ListSince(ctx context.Context, portfolioID int64, since string) ([]models.PortfolioSnapshot, error)
+}
+
+var (
+	_ PortfolioRepo = (*PortfolioRepository)(nil)
+	_ WatchlistRepo = (*WatchlistRepository)(nil)
+	_ AlertRepo     = (*AlertRepository)(nil)
+	_ SnapshotRepo  = (*SnapshotRepository)(nil)
+)
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository/sqlcdb"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/database"
+)
+
+// IVHistoryRepository persists daily ATM IV observations
+type IVHistoryRepository struct {
+	db *database.DB
+}
+
+// NewIVHistoryRepository creates a new IV history repository
+func NewIVHistoryRepository(db *database.DB) *IVHistoryRepository {
+	return &IVHistoryRepository{db: db}
+}
+
+// Upsert stores the observation, replacing any earlier reading for the same day
+func (r *IVHistoryRepository) Upsert(ctx context.Context, obs *models.IVObservation) error {
+	err := sqlcdb.New(r.db.Pool).UpsertIVObservation(ctx, sqlcdb.UpsertIVObservationParams{
+		Ticker:          obs.Ticker,
+		ObservedOn:      obs.ObservedOn,
+		AtmIv:           obs.ATMIV,
+		UnderlyingPrice: obs.UnderlyingPrice,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upsert iv history: %w", err)
+	}
+	return nil
+}
+
+// ListSince returns observations for a ticker on or after the given date, oldest first
+func (r *IVHistoryRepository) ListSince(ctx context.Context, ticker string, since time.Time) ([]models.IVObservation, error) {
+	rows, err := sqlcdb.New(r.db.Reader()).ListIVHistorySince(ctx, sqlcdb.ListIVHistorySinceParams{
+		Ticker:     ticker,
+		ObservedOn: since,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query iv history: %w", err)
+	}
+
+	var history []models.IVObservation
+	for _, row := range rows {
+		history = append(history, models.IVObservation{
+			Ticker:          row.Ticker,
+			ObservedOn:      row.ObservedOn,
+			ATMIV:           row.AtmIv,
+			UnderlyingPrice: row.UnderlyingPrice,
+		})
+	}
+	return history, nil
+}
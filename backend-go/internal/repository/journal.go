@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// JournalRepository persists trade journal entries and their attachments
+type JournalRepository struct {
+	db *database.DB
+}
+
+// NewJournalRepository creates a new journal repository
+func NewJournalRepository(db *database.DB) *JournalRepository {
+	return &JournalRepository{db: db}
+}
+
+const journalColumns = `e.id, e.portfolio_id, e.position_id, e.transaction_id, e.kind, e.title, e.body, e.tags,
+	e.created_at, e.updated_at`
+
+func scanJournalEntry(row pgx.Row, extra ...any) (*models.JournalEntry, error) {
+	var e models.JournalEntry
+	dest := append([]any{&e.ID, &e.PortfolioID, &e.PositionID, &e.TransactionID, &e.Kind, &e.Title, &e.Body, &e.Tags,
+		&e.CreatedAt, &e.UpdatedAt}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	e.Attachments = []models.JournalAttachment{}
+	return &e, nil
+}
+
+// List returns a portfolio's journal entries matching the filter. With a search query the
+// best matches come first, each with its relevance and a highlighted snippet; otherwise
+// the newest entries come first.
+func (r *JournalRepository) List(ctx context.Context, portfolioID int64, f models.JournalFilter) ([]models.JournalEntry, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT `+journalColumns+`,
+			CASE WHEN $2 <> '' THEN ts_rank(e.search, q)::float8 END,
+			CASE WHEN $2 <> '' THEN ts_headline('english', e.body, q, 'MaxFragments=2, MinWords=5, MaxWords=20') END
+		FROM journal_entries e, websearch_to_tsquery('english', $2) q
+		WHERE e.portfolio_id = $1
+			AND ($2 = '' OR e.search @@ q)
+			AND ($3 = '' OR $3 = ANY(e.tags))
+			AND ($4 = '' OR e.kind = $4)
+			AND ($5 = 0 OR e.position_id = $5)
+		ORDER BY ts_rank(e.search, q) DESC, e.created_at DESC, e.id DESC
+		LIMIT $6`,
+		portfolioID, f.Query, f.Tag, f.Kind, f.PositionID, f.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list journal entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []models.JournalEntry{}
+	for rows.Next() {
+		var rank *float64
+		var snippet *string
+		e, err := scanJournalEntry(rows, &rank, &snippet)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan journal entry: %w", err)
+		}
+		e.Rank = rank
+		e.Snippet = snippet
+		entries = append(entries, *e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read journal entries: %w", err)
+	}
+
+	if err := loadAttachments(ctx, r.db.Pool, entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Get returns a single journal entry of a portfolio with its attachments
+func (r *JournalRepository) Get(ctx context.Context, portfolioID, id int64) (*models.JournalEntry, error) {
+	return getJournalEntry(ctx, r.db.Pool, portfolioID, id)
+}
+
+// Create inserts a journal entry with its attachments
+func (r *JournalRepository) Create(ctx context.Context, e *models.JournalEntry) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO journal_entries (portfolio_id, position_id, transaction_id, kind, title, body, tags)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at, updated_at`,
+		e.PortfolioID, e.PositionID, e.TransactionID, e.Kind, e.Title, e.Body, e.Tags,
+	).Scan(&e.ID, &e.CreatedAt, &e.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create journal entry: %w", err)
+	}
+	if err := insertAttachments(ctx, tx, e.ID, e.Attachments); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit journal entry: %w", err)
+	}
+	return nil
+}
+
+// Update saves a journal entry's kind, title, body and tags, replaces its attachments
+// unless attachments is nil, then reloads it
+func (r *JournalRepository) Update(ctx context.Context, e *models.JournalEntry, attachments []models.JournalAttachment) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE journal_entries SET kind = $3, title = $4, body = $5, tags = $6, updated_at = NOW()
+		WHERE portfolio_id = $1 AND id = $2`,
+		e.PortfolioID, e.ID, e.Kind, e.Title, e.Body, e.Tags)
+	if err != nil {
+		return fmt.Errorf("failed to update journal entry: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	if attachments != nil {
+		if _, err := tx.Exec(ctx, `DELETE FROM journal_attachments WHERE entry_id = $1`, e.ID); err != nil {
+			return fmt.Errorf("failed to clear journal attachments: %w", err)
+		}
+		if err := insertAttachments(ctx, tx, e.ID, attachments); err != nil {
+			return err
+		}
+	}
+
+	updated, err := getJournalEntry(ctx, tx, e.PortfolioID, e.ID)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit journal entry: %w", err)
+	}
+	*e = *updated
+	return nil
+}
+
+// Delete removes a journal entry and its attachments
+func (r *JournalRepository) Delete(ctx context.Context, portfolioID, id int64) error {
+	tag, err := r.db.Pool.Exec(ctx, `DELETE FROM journal_entries WHERE portfolio_id = $1 AND id = $2`, portfolioID, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete journal entry: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func getJournalEntry(ctx context.Context, q querier, portfolioID, id int64) (*models.JournalEntry, error) {
+	e, err := scanJournalEntry(q.QueryRow(ctx, `
+		SELECT `+journalColumns+` FROM journal_entries e WHERE e.portfolio_id = $1 AND e.id = $2`,
+		portfolioID, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get journal entry: %w", err)
+	}
+
+	entries := []models.JournalEntry{*e}
+	if err := loadAttachments(ctx, q, entries); err != nil {
+		return nil, err
+	}
+	return &entries[0], nil
+}
+
+func insertAttachments(ctx context.Context, q querier, entryID int64, attachments []models.JournalAttachment) error {
+	for _, a := range attachments {
+		if _, err := q.Exec(ctx, `
+			INSERT INTO journal_attachments (entry_id, name, url) VALUES ($1, $2, $3)`,
+			entryID, a.Name, a.URL); err != nil {
+			return fmt.Errorf("failed to save journal attachment: %w", err)
+		}
+	}
+	return nil
+}
+
+// loadAttachments fills in the attachments of the entries, in the order they were added
+func loadAttachments(ctx context.Context, q querier, entries []models.JournalEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	ids := make([]int64, len(entries))
+	index := make(map[int64]int, len(entries))
+	for i, e := range entries {
+		ids[i] = e.ID
+		index[e.ID] = i
+	}
+
+	rows, err := q.Query(ctx, `
+		SELECT entry_id, name, url
+		FROM journal_attachments
+		WHERE entry_id = ANY($1)
+		ORDER BY entry_id, id`, ids)
+	if err != nil {
+		return fmt.Errorf("failed to list journal attachments: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entryID int64
+		var a models.JournalAttachment
+		if err := rows.Scan(&entryID, &a.Name, &a.URL); err != nil {
+			return fmt.Errorf("failed to scan journal attachment: %w", err)
+		}
+		e := &entries[index[entryID]]
+		e.Attachments = append(e.Attachments, a)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read journal attachments: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,325 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository/sqlcdb"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// OptionsSnapshotRepository persists captures of options chains and their contracts
+type OptionsSnapshotRepository struct {
+	db     *database.DB
+	latest *database.Cache[models.OptionsSnapshot] // by ticker
+}
+
+// latestSnapshotCacheTTL is how long a ticker's latest capture is reused. A new capture
+// invalidates it in every instance.
+const latestSnapshotCacheTTL = 5 * time.Minute
+
+// NewOptionsSnapshotRepository creates a new options snapshot repository
+func NewOptionsSnapshotRepository(db *database.DB) *OptionsSnapshotRepository {
+	return &OptionsSnapshotRepository{
+		db:     db,
+		latest: database.NewCache[models.OptionsSnapshot](db, "latest_snapshots", latestSnapshotCacheTTL),
+	}
+}
+
+func optionsSnapshotFromRow(row sqlcdb.OptionsSnapshot) *models.OptionsSnapshot {
+	s := &models.OptionsSnapshot{
+		ID:         row.ID,
+		Ticker:     row.Ticker,
+		CapturedAt: row.CapturedAt,
+		Spot:       row.Spot,
+		Contracts:  int(row.Contracts),
+	}
+	if row.CreatedAt != nil {
+		s.CreatedAt = *row.CreatedAt
+	}
+	return s
+}
+
+// snapshotCopyBatch is how many contracts each COPY sends
+const snapshotCopyBatch = 2000
+
+// snapshotContractColumns are the options_snapshot_contracts columns a capture writes, in
+// the order copySnapshotContracts sends them
+var snapshotContractColumns = []string{"snapshot_id", "captured_at", "underlying", "ticker", "contract_type",
+	"strike_price", "expiration_date", "bid", "ask", "last_price", "volume", "open_interest",
+	"implied_volatility", "delta", "gamma", "theta", "vega", "rho"}
+
+// Create stores a capture together with its contracts in one transaction, filling in the
+// capture's generated fields and the contracts' snapshot ID. Contracts are bulk loaded with
+// COPY into a staging table, then moved over skipping any the chain listed twice, so the
+// capture's contract count is of those stored.
+func (r *OptionsSnapshotRepository) Create(ctx context.Context, s *models.OptionsSnapshot, contracts []models.OptionsSnapshotContract) error {
+	err := r.db.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		q := sqlcdb.New(tx)
+		row, err := q.CreateOptionsSnapshot(ctx, sqlcdb.CreateOptionsSnapshotParams{
+			Ticker:     s.Ticker,
+			CapturedAt: s.CapturedAt,
+			Spot:       s.Spot,
+			Contracts:  int32(len(contracts)),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create options snapshot: %w", err)
+		}
+		s.ID = row.ID
+		if row.CreatedAt != nil {
+			s.CreatedAt = *row.CreatedAt
+		}
+		for i := range contracts {
+			contracts[i].SnapshotID = s.ID
+		}
+
+		stored, err := copySnapshotContracts(ctx, tx, contracts)
+		if err != nil {
+			return err
+		}
+		s.Contracts = int(stored)
+		if stored != int64(len(contracts)) {
+			if err := q.SetOptionsSnapshotContracts(ctx, sqlcdb.SetOptionsSnapshotContractsParams{
+				ID:        s.ID,
+				Contracts: int32(stored),
+			}); err != nil {
+				return fmt.Errorf("failed to count options snapshot contracts: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	r.latest.Invalidate(ctx, s.Ticker)
+	return nil
+}
+
+// copySnapshotContracts bulk loads contracts in batches into a staging table dropped at
+// commit, then inserts them into options_snapshot_contracts, skipping rows whose ticker and
+// capture time are already there. It returns how many were inserted.
+func copySnapshotContracts(ctx context.Context, tx pgx.Tx, contracts []models.OptionsSnapshotContract) (int64, error) {
+	_, err := tx.Exec(ctx, `
+		CREATE TEMP TABLE options_snapshot_contracts_staging
+			(LIKE options_snapshot_contracts INCLUDING DEFAULTS) ON COMMIT DROP`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to stage options snapshot contracts: %w", err)
+	}
+
+	staging := pgx.Identifier{"options_snapshot_contracts_staging"}
+	for start := 0; start < len(contracts); start += snapshotCopyBatch {
+		batch := contracts[start:min(start+snapshotCopyBatch, len(contracts))]
+		_, err := tx.CopyFrom(ctx, staging, snapshotContractColumns, pgx.CopyFromSlice(len(batch), func(i int) ([]any, error) {
+			c := &batch[i]
+			// COPY sends binary values, so the date is sent as a time rather than text
+			expiration, err := time.Parse(time.DateOnly, c.ExpirationDate)
+			if err != nil {
+				return nil, fmt.Errorf("contract %s has invalid expiration %q", c.Ticker, c.ExpirationDate)
+			}
+			return []any{c.SnapshotID, c.CapturedAt, c.Underlying, c.Ticker, c.ContractType,
+				c.StrikePrice, expiration, c.Bid, c.Ask, c.LastPrice, c.Volume, c.OpenInterest,
+				c.ImpliedVol, c.Delta, c.Gamma, c.Theta, c.Vega, c.Rho}, nil
+		}))
+		if err != nil {
+			return 0, fmt.Errorf("failed to copy options snapshot contracts: %w", err)
+		}
+	}
+
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO options_snapshot_contracts
+		SELECT * FROM options_snapshot_contracts_staging
+		ON CONFLICT (ticker, captured_at) DO NOTHING`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to store options snapshot contracts: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// Latest returns the last capture of a ticker's chain, reusing a recent read
+func (r *OptionsSnapshotRepository) Latest(ctx context.Context, ticker string) (*models.OptionsSnapshot, error) {
+	if s, ok := r.latest.Get(ticker); ok {
+		return &s, nil
+	}
+	s, err := r.LatestBefore(ctx, ticker, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	r.latest.Set(ticker, *s)
+	return s, nil
+}
+
+// LatestBefore returns the last capture of a ticker's chain taken before the given time
+func (r *OptionsSnapshotRepository) LatestBefore(ctx context.Context, ticker string, before time.Time) (*models.OptionsSnapshot, error) {
+	row, err := sqlcdb.New(r.db.Reader()).LatestOptionsSnapshotBefore(ctx, sqlcdb.LatestOptionsSnapshotBeforeParams{
+		Ticker: ticker,
+		Before: before,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get options snapshot: %w", err)
+	}
+	return optionsSnapshotFromRow(row), nil
+}
+
+// ListContracts returns a capture's contracts by expiration, strike and type, only those of
+// one expiration when expiration (YYYY-MM-DD) is set
+func (r *OptionsSnapshotRepository) ListContracts(ctx context.Context, s *models.OptionsSnapshot, expiration *string) ([]models.OptionsSnapshotContract, error) {
+	params := sqlcdb.ListOptionsSnapshotContractsParams{
+		Underlying: s.Ticker,
+		CapturedAt: s.CapturedAt,
+		SnapshotID: s.ID,
+	}
+	if expiration != nil {
+		date, err := time.Parse(time.DateOnly, *expiration)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expiration %q: %w", *expiration, err)
+		}
+		params.Expiration = &date
+	}
+	rows, err := sqlcdb.New(r.db.Reader()).ListOptionsSnapshotContracts(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list options snapshot contracts: %w", err)
+	}
+
+	contracts := make([]models.OptionsSnapshotContract, len(rows))
+	for i, row := range rows {
+		contracts[i] = models.OptionsSnapshotContract{
+			SnapshotID:     row.SnapshotID,
+			CapturedAt:     row.CapturedAt,
+			Underlying:     row.Underlying,
+			Ticker:         row.Ticker,
+			ContractType:   row.ContractType,
+			StrikePrice:    row.StrikePrice,
+			ExpirationDate: row.ExpirationDate.Format(time.DateOnly),
+			Bid:            row.Bid,
+			Ask:            row.Ask,
+			LastPrice:      row.LastPrice,
+			Volume:         row.Volume,
+			OpenInterest:   row.OpenInterest,
+			ImpliedVol:     row.ImpliedVolatility,
+			Delta:          row.Delta,
+			Gamma:          row.Gamma,
+			Theta:          row.Theta,
+			Vega:           row.Vega,
+			Rho:            row.Rho,
+		}
+	}
+	return contracts, nil
+}
+
+// RollUpBefore deletes up to limit captures taken before the given time that weren't the last
+// capture of their ticker's trading day, leaving one capture per ticker and day. It returns
+// how many were deleted; their contracts go with them.
+func (r *OptionsSnapshotRepository) RollUpBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	n, err := sqlcdb.New(r.db.Pool).RollUpOptionsSnapshotsBefore(ctx, sqlcdb.RollUpOptionsSnapshotsBeforeParams{
+		Before:  before,
+		MaxRows: int32(limit),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to roll up options snapshots: %w", err)
+	}
+	return n, nil
+}
+
+// DeleteBefore deletes up to limit captures taken before the given time, with their
+// contracts, and returns how many were deleted
+func (r *OptionsSnapshotRepository) DeleteBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	n, err := sqlcdb.New(r.db.Pool).DeleteOptionsSnapshotsBefore(ctx, sqlcdb.DeleteOptionsSnapshotsBeforeParams{
+		Before:  before,
+		MaxRows: int32(limit),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete options snapshots: %w", err)
+	}
+	return n, nil
+}
+
+// Hypertables reports whether the contracts and their daily rollups are TimescaleDB
+// hypertables, which retention prunes by dropping chunks rather than deleting rows
+func (r *OptionsSnapshotRepository) Hypertables(ctx context.Context) (bool, error) {
+	version, err := r.db.TimescaleVersion(ctx)
+	if err != nil || version == "" {
+		return false, err
+	}
+	var n int
+	err = r.db.Pool.QueryRow(ctx, `
+		SELECT count(*) FROM timescaledb_information.hypertables
+		WHERE hypertable_name IN ('options_snapshot_contracts', 'options_snapshot_contracts_daily')`).Scan(&n)
+	if err != nil {
+		return false, fmt.Errorf("failed to list hypertables: %w", err)
+	}
+	return n == 2, nil
+}
+
+// RollUpChunksBefore copies the contracts of each ticker's last capture of a trading day out
+// of the intraday chunks that end by before into the daily rollups, then drops those chunks
+// whole. It returns the end of the last chunk dropped, zero when none has ended, and how many
+// contracts were copied. The captures before it that weren't rolled up are left for
+// RollUpBefore; the hypertable has no foreign key for their deletes to cascade through.
+// Requires Hypertables.
+func (r *OptionsSnapshotRepository) RollUpChunksBefore(ctx context.Context, before time.Time) (time.Time, int64, error) {
+	var cutoff time.Time
+	var copied int64
+	err := r.db.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		var err error
+		if cutoff, err = chunksEndedBy(ctx, tx, "options_snapshot_contracts", before); err != nil || cutoff.IsZero() {
+			return err
+		}
+		if copied, err = sqlcdb.New(tx).CopyOptionsSnapshotDailyContracts(ctx, cutoff); err != nil {
+			return fmt.Errorf("failed to copy daily options snapshot contracts: %w", err)
+		}
+		return dropChunks(ctx, tx, "options_snapshot_contracts", cutoff)
+	})
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	return cutoff, copied, nil
+}
+
+// DropDailyChunksBefore drops the daily rollup chunks that end by before and returns the end
+// of the last one, zero when none has ended. The captures before it are left for
+// DeleteBefore. Requires Hypertables.
+func (r *OptionsSnapshotRepository) DropDailyChunksBefore(ctx context.Context, before time.Time) (time.Time, error) {
+	var cutoff time.Time
+	err := r.db.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		var err error
+		if cutoff, err = chunksEndedBy(ctx, tx, "options_snapshot_contracts_daily", before); err != nil || cutoff.IsZero() {
+			return err
+		}
+		return dropChunks(ctx, tx, "options_snapshot_contracts_daily", cutoff)
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+	return cutoff, nil
+}
+
+// chunksEndedBy returns the end of the hypertable's last chunk to end by before, or zero when
+// none has. Written by hand, like dropChunks: sqlc cannot check Timescale's catalog.
+func chunksEndedBy(ctx context.Context, tx pgx.Tx, hypertable string, before time.Time) (time.Time, error) {
+	var end *time.Time
+	err := tx.QueryRow(ctx, `
+		SELECT max(range_end) FROM timescaledb_information.chunks
+		WHERE hypertable_name = $1 AND range_end <= $2`, hypertable, before).Scan(&end)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to list %s chunks: %w", hypertable, err)
+	}
+	if end == nil {
+		return time.Time{}, nil
+	}
+	return *end, nil
+}
+
+// dropChunks drops the hypertable's chunks that end by before
+func dropChunks(ctx context.Context, tx pgx.Tx, hypertable string, before time.Time) error {
+	if _, err := tx.Exec(ctx, `SELECT drop_chunks($1::regclass, older_than => $2::timestamptz)`, hypertable, before); err != nil {
+		return fmt.Errorf("failed to drop %s chunks: %w", hypertable, err)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// PaperOrderRepository records simulated orders and writes their fills to the ledger
+type PaperOrderRepository struct {
+	db *database.DB
+}
+
+// NewPaperOrderRepository creates a new paper order repository
+func NewPaperOrderRepository(db *database.DB) *PaperOrderRepository {
+	return &PaperOrderRepository{db: db}
+}
+
+const paperOrderColumns = `id, portfolio_id, asset_type, ticker, instruction, quantity, limit_price, status,
+	bid, ask, reference_price, slippage_bps, fill_price, fees, reject_reason, position_id, transaction_id, created_at`
+
+// Fill records a filled order and its trade in one transaction. Opening orders add to the
+// oldest open position in the same ticker and side, or else open the given position with
+// the order's quantity and price; closing orders reduce that position or return
+// ErrNothingToClose. The order's position and transaction IDs are filled in.
+func (r *PaperOrderRepository) Fill(ctx context.Context, o *models.PaperOrder, position *models.Position, tradedAt string) error {
+	trade := Trade{
+		Quantity: o.Quantity,
+		Price:    *o.FillPrice,
+		Fees:     o.Fees,
+		TradedAt: tradedAt,
+	}
+	err := r.db.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		existing, err := lockOpenPositionByTicker(ctx, tx, o.PortfolioID, o.Ticker, o.PositionSide())
+		if err != nil && !errors.Is(err, errNoOpenPosition) {
+			return err
+		}
+
+		var t *models.Transaction
+		switch {
+		case o.Opens() && existing == nil:
+			position.Quantity = o.Quantity
+			position.OpenPrice = *o.FillPrice
+			position.OpenedAt = tradedAt
+			if t, err = openPosition(ctx, tx, position, models.TransactionOpen, o.Fees, nil); err != nil {
+				return err
+			}
+		case o.Opens():
+			position = existing
+			if t, err = addToPosition(ctx, tx, position, trade); err != nil {
+				return err
+			}
+		case existing == nil:
+			return ErrNothingToClose
+		default:
+			position = existing
+			if t, err = closePosition(ctx, tx, position, models.TransactionClose, trade); err != nil {
+				return err
+			}
+		}
+
+		o.PositionID = &position.ID
+		o.TransactionID = &t.ID
+		return insertPaperOrder(ctx, tx, o)
+	})
+	if err != nil {
+		return err
+	}
+	o.Position = position
+	return nil
+}
+
+// Reject records an order that was not filled
+func (r *PaperOrderRepository) Reject(ctx context.Context, o *models.PaperOrder) error {
+	return insertPaperOrder(ctx, r.db.Pool, o)
+}
+
+// List returns a portfolio's most recent paper orders, newest first
+func (r *PaperOrderRepository) List(ctx context.Context, portfolioID int64, limit int) ([]models.PaperOrder, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT `+paperOrderColumns+`
+		FROM paper_orders
+		WHERE portfolio_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2`,
+		portfolioID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list paper orders: %w", err)
+	}
+	defer rows.Close()
+
+	orders := []models.PaperOrder{}
+	for rows.Next() {
+		var o models.PaperOrder
+		err := rows.Scan(&o.ID, &o.PortfolioID, &o.AssetType, &o.Ticker, &o.Instruction, &o.Quantity, &o.LimitPrice,
+			&o.Status, &o.Bid, &o.Ask, &o.ReferencePrice, &o.SlippageBps, &o.FillPrice, &o.Fees, &o.RejectReason,
+			&o.PositionID, &o.TransactionID, &o.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan paper order: %w", err)
+		}
+		orders = append(orders, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read paper orders: %w", err)
+	}
+	return orders, nil
+}
+
+func insertPaperOrder(ctx context.Context, q querier, o *models.PaperOrder) error {
+	err := q.QueryRow(ctx, `
+		INSERT INTO paper_orders (portfolio_id, asset_type, ticker, instruction, quantity, limit_price, status,
+			bid, ask, reference_price, slippage_bps, fill_price, fees, reject_reason, position_id, transaction_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+		RETURNING id, created_at`,
+		o.PortfolioID, o.AssetType, o.Ticker, o.Instruction, o.Quantity, o.LimitPrice, o.Status,
+		o.Bid, o.Ask, o.ReferencePrice, o.SlippageBps, o.FillPrice, o.Fees, o.RejectReason, o.PositionID, o.TransactionID,
+	).Scan(&o.ID, &o.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record paper order: %w", err)
+	}
+	return nil
+}
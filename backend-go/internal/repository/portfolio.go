@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// PortfolioRepository persists portfolios
+type PortfolioRepository struct {
+	db *database.DB
+}
+
+// NewPortfolioRepository creates a new portfolio repository
+func NewPortfolioRepository(db *database.DB) *PortfolioRepository {
+	return &PortfolioRepository{db: db}
+}
+
+const portfolioColumns = `id, user_id::text, name, description, account_type, include_in_rollup, created_at, updated_at,
+	deleted_at`
+
+func scanPortfolio(row pgx.Row) (*models.Portfolio, error) {
+	var p models.Portfolio
+	err := row.Scan(&p.ID, &p.UserID, &p.Name, &p.Description, &p.Settings.AccountType, &p.Settings.IncludeInRollup,
+		&p.CreatedAt, &p.UpdatedAt, &p.DeletedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Create inserts a portfolio and fills in its generated fields.
+// Returns ErrDuplicate when the owner already has a portfolio with the same name.
+func (r *PortfolioRepository) Create(ctx context.Context, p *models.Portfolio) error {
+	err := r.db.Pool.QueryRow(ctx, `
+		INSERT INTO portfolios (user_id, name, description, account_type, include_in_rollup)
+		VALUES ($1::uuid, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at`,
+		p.UserID, p.Name, p.Description, p.Settings.AccountType, p.Settings.IncludeInRollup,
+	).Scan(&p.ID, &p.CreatedAt, &p.UpdatedAt)
+	if isUniqueViolation(err) {
+		return ErrDuplicate
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create portfolio: %w", err)
+	}
+	return nil
+}
+
+// List returns a user's portfolios, newest first. A nil user lists portfolios without an owner.
+func (r *PortfolioRepository) List(ctx context.Context, userID *string) ([]models.Portfolio, error) {
+	return r.query(ctx, `
+		SELECT `+portfolioColumns+`
+		FROM portfolios
+		WHERE user_id IS NOT DISTINCT FROM $1::uuid AND deleted_at IS NULL
+		ORDER BY created_at DESC`,
+		userID)
+}
+
+// ListAll returns every portfolio across all users, oldest first, for background jobs
+func (r *PortfolioRepository) ListAll(ctx context.Context) ([]models.Portfolio, error) {
+	return r.query(ctx, `SELECT `+portfolioColumns+` FROM portfolios WHERE deleted_at IS NULL ORDER BY id`)
+}
+
+// ListDeleted returns a user's deleted portfolios, most recently deleted first
+func (r *PortfolioRepository) ListDeleted(ctx context.Context, userID *string) ([]models.Portfolio, error) {
+	return r.query(ctx, `
+		SELECT `+portfolioColumns+`
+		FROM portfolios
+		WHERE user_id IS NOT DISTINCT FROM $1::uuid AND deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, id DESC`,
+		userID)
+}
+
+// query runs a portfolio SELECT and scans every row
+func (r *PortfolioRepository) query(ctx context.Context, sql string, args ...any) ([]models.Portfolio, error) {
+	rows, err := r.db.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list portfolios: %w", err)
+	}
+	defer rows.Close()
+
+	portfolios := []models.Portfolio{}
+	for rows.Next() {
+		p, err := scanPortfolio(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan portfolio: %w", err)
+		}
+		portfolios = append(portfolios, *p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read portfolios: %w", err)
+	}
+
+	return portfolios, nil
+}
+
+// Get returns one of a user's portfolios; another user's portfolio is not found. A nil user
+// gets portfolios without an owner.
+func (r *PortfolioRepository) Get(ctx context.Context, userID *string, id int64) (*models.Portfolio, error) {
+	p, err := scanPortfolio(r.db.Pool.QueryRow(ctx, `
+		SELECT `+portfolioColumns+` FROM portfolios
+		WHERE id = $1 AND user_id IS NOT DISTINCT FROM $2::uuid AND deleted_at IS NULL`,
+		id, userID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get portfolio: %w", err)
+	}
+	return p, nil
+}
+
+// GetAny returns a portfolio whoever owns it, for access authorized by other means: share
+// link tokens and background jobs
+func (r *PortfolioRepository) GetAny(ctx context.Context, id int64) (*models.Portfolio, error) {
+	p, err := scanPortfolio(r.db.Pool.QueryRow(ctx, `
+		SELECT `+portfolioColumns+` FROM portfolios WHERE id = $1 AND deleted_at IS NULL`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get portfolio: %w", err)
+	}
+	return p, nil
+}
+
+// Update saves the portfolio's name, description and settings if it still belongs to its
+// owner
+func (r *PortfolioRepository) Update(ctx context.Context, p *models.Portfolio) error {
+	err := r.db.Pool.QueryRow(ctx, `
+		UPDATE portfolios
+		SET name = $2, description = $3, account_type = $4, include_in_rollup = $5, updated_at = NOW()
+		WHERE id = $1 AND user_id IS NOT DISTINCT FROM $6::uuid AND deleted_at IS NULL
+		RETURNING updated_at`,
+		p.ID, p.Name, p.Description, p.Settings.AccountType, p.Settings.IncludeInRollup, p.UserID).Scan(&p.UpdatedAt)
+	if isUniqueViolation(err) {
+		return ErrDuplicate
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update portfolio: %w", err)
+	}
+	return nil
+}
+
+// Delete moves one of a user's portfolios to their deleted portfolios, hiding it and
+// everything recorded under it until it is restored or purged
+func (r *PortfolioRepository) Delete(ctx context.Context, userID *string, id int64) error {
+	tag, err := r.db.Pool.Exec(ctx, `
+		UPDATE portfolios SET deleted_at = NOW()
+		WHERE id = $1 AND user_id IS NOT DISTINCT FROM $2::uuid AND deleted_at IS NULL`,
+		id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete portfolio: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Restore brings back one of a user's deleted portfolios. Returns ErrDuplicate when the user
+// has since created a portfolio with the same name.
+func (r *PortfolioRepository) Restore(ctx context.Context, userID *string, id int64) (*models.Portfolio, error) {
+	p, err := scanPortfolio(r.db.Pool.QueryRow(ctx, `
+		UPDATE portfolios SET deleted_at = NULL, updated_at = NOW()
+		WHERE id = $1 AND user_id IS NOT DISTINCT FROM $2::uuid AND deleted_at IS NOT NULL
+		RETURNING `+portfolioColumns,
+		id, userID))
+	if isUniqueViolation(err) {
+		return nil, ErrDuplicate
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore portfolio: %w", err)
+	}
+	return p, nil
+}
+
+// Purge permanently removes one of a user's deleted portfolios with its positions, trades
+// and everything else recorded under it. Portfolios that aren't deleted are not found.
+func (r *PortfolioRepository) Purge(ctx context.Context, userID *string, id int64) error {
+	tag, err := r.db.Pool.Exec(ctx, `
+		DELETE FROM portfolios
+		WHERE id = $1 AND user_id IS NOT DISTINCT FROM $2::uuid AND deleted_at IS NOT NULL`,
+		id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to purge portfolio: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,233 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/database/rest"
+)
+
+// RESTPortfolioRepository persists portfolios through the Supabase REST API, for deployments
+// that cannot open a Postgres connection
+type RESTPortfolioRepository struct {
+	client *rest.Client
+}
+
+// NewRESTPortfolioRepository creates a portfolio repository backed by the REST API
+func NewRESTPortfolioRepository(client *rest.Client) *RESTPortfolioRepository {
+	return &RESTPortfolioRepository{client: client}
+}
+
+const portfolioSelect = "id,user_id,name,description,account_type,include_in_rollup,created_at,updated_at,deleted_at"
+
+// portfolioRow is a portfolios row as the REST API reads and writes it
+type portfolioRow struct {
+	ID              int64      `json:"id,omitempty"`
+	UserID          *string    `json:"user_id"`
+	Name            string     `json:"name"`
+	Description     *string    `json:"description"`
+	AccountType     string     `json:"account_type"`
+	IncludeInRollup bool       `json:"include_in_rollup"`
+	CreatedAt       *time.Time `json:"created_at,omitempty"`
+	UpdatedAt       *time.Time `json:"updated_at,omitempty"`
+	DeletedAt       *time.Time `json:"deleted_at,omitempty"`
+}
+
+func newPortfolioRow(p *models.Portfolio) portfolioRow {
+	return portfolioRow{
+		UserID:          p.UserID,
+		Name:            p.Name,
+		Description:     p.Description,
+		AccountType:     p.Settings.AccountType,
+		IncludeInRollup: p.Settings.IncludeInRollup,
+	}
+}
+
+func (row portfolioRow) portfolio() models.Portfolio {
+	p := models.Portfolio{
+		ID:          row.ID,
+		UserID:      row.UserID,
+		Name:        row.Name,
+		Description: row.Description,
+		Settings:    models.PortfolioSettings{AccountType: row.AccountType, IncludeInRollup: row.IncludeInRollup},
+		DeletedAt:   row.DeletedAt,
+	}
+	if row.CreatedAt != nil {
+		p.CreatedAt = *row.CreatedAt
+	}
+	if row.UpdatedAt != nil {
+		p.UpdatedAt = *row.UpdatedAt
+	}
+	return p
+}
+
+// ownerFilter limits a REST query to a user's rows, or to those without an owner for a nil
+// user, as user_id IS NOT DISTINCT FROM does in SQL
+func ownerFilter(query url.Values, userID *string) url.Values {
+	if userID == nil {
+		query.Set("user_id", rest.IsNull())
+	} else {
+		query.Set("user_id", rest.Eq(*userID))
+	}
+	return query
+}
+
+// Create inserts a portfolio and fills in its generated fields.
+// Returns ErrDuplicate when the owner already has a portfolio with the same name.
+func (r *RESTPortfolioRepository) Create(ctx context.Context, p *models.Portfolio) error {
+	var rows []portfolioRow
+	err := r.client.Insert(ctx, "portfolios", newPortfolioRow(p), &rows)
+	if isUniqueViolation(err) {
+		return ErrDuplicate
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create portfolio: %w", err)
+	}
+	if len(rows) != 1 {
+		return fmt.Errorf("failed to create portfolio: %d rows returned", len(rows))
+	}
+	*p = rows[0].portfolio()
+	return nil
+}
+
+// List returns a user's portfolios, newest first. A nil user lists portfolios without an owner.
+func (r *RESTPortfolioRepository) List(ctx context.Context, userID *string) ([]models.Portfolio, error) {
+	query := ownerFilter(url.Values{
+		"select":     {portfolioSelect},
+		"deleted_at": {rest.IsNull()},
+		"order":      {"created_at.desc"},
+	}, userID)
+	return r.query(ctx, query)
+}
+
+// ListAll returns every portfolio across all users, oldest first, for background jobs
+func (r *RESTPortfolioRepository) ListAll(ctx context.Context) ([]models.Portfolio, error) {
+	return r.query(ctx, url.Values{"select": {portfolioSelect}, "deleted_at": {rest.IsNull()}, "order": {"id"}})
+}
+
+// ListDeleted returns a user's deleted portfolios, most recently deleted first
+func (r *RESTPortfolioRepository) ListDeleted(ctx context.Context, userID *string) ([]models.Portfolio, error) {
+	query := ownerFilter(url.Values{
+		"select":     {portfolioSelect},
+		"deleted_at": {rest.NotNull()},
+		"order":      {"deleted_at.desc,id.desc"},
+	}, userID)
+	return r.query(ctx, query)
+}
+
+func (r *RESTPortfolioRepository) query(ctx context.Context, query url.Values) ([]models.Portfolio, error) {
+	var rows []portfolioRow
+	if err := r.client.Select(ctx, "portfolios", query, &rows); err != nil {
+		return nil, fmt.Errorf("failed to list portfolios: %w", err)
+	}
+	portfolios := make([]models.Portfolio, len(rows))
+	for i, row := range rows {
+		portfolios[i] = row.portfolio()
+	}
+	return portfolios, nil
+}
+
+// Get returns one of a user's portfolios; another user's portfolio is not found. A nil user
+// gets portfolios without an owner.
+func (r *RESTPortfolioRepository) Get(ctx context.Context, userID *string, id int64) (*models.Portfolio, error) {
+	return r.get(ctx, ownerFilter(url.Values{"id": {rest.Eq(id)}, "deleted_at": {rest.IsNull()}}, userID))
+}
+
+// GetAny returns a portfolio whoever owns it, for access authorized by other means
+func (r *RESTPortfolioRepository) GetAny(ctx context.Context, id int64) (*models.Portfolio, error) {
+	return r.get(ctx, url.Values{"id": {rest.Eq(id)}, "deleted_at": {rest.IsNull()}})
+}
+
+func (r *RESTPortfolioRepository) get(ctx context.Context, query url.Values) (*models.Portfolio, error) {
+	query.Set("select", portfolioSelect)
+	var rows []portfolioRow
+	if err := r.client.Select(ctx, "portfolios", query, &rows); err != nil {
+		return nil, fmt.Errorf("failed to get portfolio: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, ErrNotFound
+	}
+	p := rows[0].portfolio()
+	return &p, nil
+}
+
+// Update saves the portfolio's name, description and settings if it still belongs to its
+// owner
+func (r *RESTPortfolioRepository) Update(ctx context.Context, p *models.Portfolio) error {
+	patch := map[string]any{
+		"name":              p.Name,
+		"description":       p.Description,
+		"account_type":      p.Settings.AccountType,
+		"include_in_rollup": p.Settings.IncludeInRollup,
+		"updated_at":        time.Now().UTC(),
+	}
+	query := ownerFilter(url.Values{
+		"id":         {rest.Eq(p.ID)},
+		"deleted_at": {rest.IsNull()},
+		"select":     {"updated_at"},
+	}, p.UserID)
+
+	var rows []portfolioRow
+	err := r.client.Update(ctx, "portfolios", query, patch, &rows)
+	if isUniqueViolation(err) {
+		return ErrDuplicate
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update portfolio: %w", err)
+	}
+	if len(rows) == 0 {
+		return ErrNotFound
+	}
+	p.UpdatedAt = *rows[0].UpdatedAt
+	return nil
+}
+
+// Delete moves one of a user's portfolios to their deleted portfolios, hiding it until it is
+// restored or purged
+func (r *RESTPortfolioRepository) Delete(ctx context.Context, userID *string, id int64) error {
+	var rows []struct{}
+	query := ownerFilter(url.Values{"id": {rest.Eq(id)}, "deleted_at": {rest.IsNull()}, "select": {"id"}}, userID)
+	if err := r.client.Update(ctx, "portfolios", query, map[string]any{"deleted_at": time.Now().UTC()}, &rows); err != nil {
+		return fmt.Errorf("failed to delete portfolio: %w", err)
+	}
+	if len(rows) == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Restore brings back one of a user's deleted portfolios. Returns ErrDuplicate when the user
+// has since created a portfolio with the same name.
+func (r *RESTPortfolioRepository) Restore(ctx context.Context, userID *string, id int64) (*models.Portfolio, error) {
+	var rows []portfolioRow
+	query := ownerFilter(url.Values{"id": {rest.Eq(id)}, "deleted_at": {rest.NotNull()}, "select": {portfolioSelect}}, userID)
+	err := r.client.Update(ctx, "portfolios", query, map[string]any{"deleted_at": nil, "updated_at": time.Now().UTC()}, &rows)
+	if isUniqueViolation(err) {
+		return nil, ErrDuplicate
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore portfolio: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, ErrNotFound
+	}
+	p := rows[0].portfolio()
+	return &p, nil
+}
+
+// Purge permanently removes one of a user's deleted portfolios with everything recorded
+// under it. Portfolios that aren't deleted are not found.
+func (r *RESTPortfolioRepository) Purge(ctx context.Context, userID *string, id int64) error {
+	var rows []struct{}
+	query := ownerFilter(url.Values{"id": {rest.Eq(id)}, "deleted_at": {rest.NotNull()}, "select": {"id"}}, userID)
+	if err := r.client.Delete(ctx, "portfolios", query, &rows); err != nil {
+		return fmt.Errorf("failed to purge portfolio: %w", err)
+	}
+	if len(rows) == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// PositionRepository reads option legs and share lots. Writes go through the
+// TransactionRepository so every change is recorded in the trade ledger.
+type PositionRepository struct {
+	db *database.DB
+}
+
+// NewPositionRepository creates a new position repository
+func NewPositionRepository(db *database.DB) *PositionRepository {
+	return &PositionRepository{db: db}
+}
+
+const positionColumns = `id, portfolio_id, asset_type, ticker, underlying_ticker, contract_type,
+	strike_price, expiration_date::text, side, quantity, multiplier, open_price, opened_at::text,
+	status, close_price, closed_at::text, close_reason, fees, realized_pnl, dividends, strategy_id, created_at, updated_at`
+
+func scanPosition(row pgx.Row) (*models.Position, error) {
+	var p models.Position
+	err := row.Scan(&p.ID, &p.PortfolioID, &p.AssetType, &p.Ticker, &p.UnderlyingTicker, &p.ContractType,
+		&p.StrikePrice, &p.ExpirationDate, &p.Side, &p.Quantity, &p.Multiplier, &p.OpenPrice, &p.OpenedAt,
+		&p.Status, &p.ClosePrice, &p.ClosedAt, &p.CloseReason, &p.Fees, &p.RealizedPnL, &p.Dividends, &p.StrategyID, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func collectPositions(rows pgx.Rows) ([]models.Position, error) {
+	defer rows.Close()
+
+	positions := []models.Position{}
+	for rows.Next() {
+		p, err := scanPosition(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan position: %w", err)
+		}
+		positions = append(positions, *p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read positions: %w", err)
+	}
+	return positions, nil
+}
+
+// ListByPortfolio returns a portfolio's positions filtered by status ("" for all)
+func (r *PositionRepository) ListByPortfolio(ctx context.Context, portfolioID int64, status string) ([]models.Position, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT `+positionColumns+`
+		FROM positions
+		WHERE portfolio_id = $1 AND ($2 = '' OR status = $2)
+		ORDER BY opened_at DESC, id DESC`,
+		portfolioID, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list positions: %w", err)
+	}
+	return collectPositions(rows)
+}
+
+// Get returns a single position within a portfolio
+func (r *PositionRepository) Get(ctx context.Context, portfolioID, id int64) (*models.Position, error) {
+	p, err := scanPosition(r.db.Pool.QueryRow(ctx, `
+		SELECT `+positionColumns+` FROM positions WHERE portfolio_id = $1 AND id = $2`,
+		portfolioID, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get position: %w", err)
+	}
+	return p, nil
+}
+
+// ListByPortfolios returns the positions of several portfolios filtered by status ("" for all)
+func (r *PositionRepository) ListByPortfolios(ctx context.Context, portfolioIDs []int64, status string) ([]models.Position, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT `+positionColumns+`
+		FROM positions
+		WHERE portfolio_id = ANY($1) AND ($2 = '' OR status = $2)
+		ORDER BY portfolio_id, opened_at DESC, id DESC`,
+		portfolioIDs, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list positions: %w", err)
+	}
+	return collectPositions(rows)
+}
+
+// ListExpired returns open option legs across all portfolios that expire on or before
+// the given YYYY-MM-DD date, oldest expiration first
+func (r *PositionRepository) ListExpired(ctx context.Context, through string) ([]models.Position, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT `+positionColumns+`
+		FROM positions
+		WHERE status = 'open' AND asset_type = 'option' AND expiration_date <= $1::date
+		ORDER BY expiration_date, portfolio_id, id`,
+		through)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expired positions: %w", err)
+	}
+	return collectPositions(rows)
+}
+
+// ListShares returns share positions across all portfolios that are open or were closed
+// on or after the given YYYY-MM-DD date, by ticker
+func (r *PositionRepository) ListShares(ctx context.Context, closedSince string) ([]models.Position, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT `+positionColumns+`
+		FROM positions
+		WHERE asset_type = 'stock' AND (status = 'open' OR closed_at >= $1::date)
+		ORDER BY ticker, portfolio_id, id`,
+		closedSince)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list share positions: %w", err)
+	}
+	return collectPositions(rows)
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// PositionAlertRepository persists alert rules attached to positions
+type PositionAlertRepository struct {
+	db *database.DB
+}
+
+// NewPositionAlertRepository creates a new position alert repository
+func NewPositionAlertRepository(db *database.DB) *PositionAlertRepository {
+	return &PositionAlertRepository{db: db}
+}
+
+const positionAlertColumns = `id, portfolio_id, position_id, metric, operator, threshold, note, status,
+	last_value, last_evaluated_at, triggered_value, triggered_at, created_at, updated_at`
+
+func scanPositionAlert(row pgx.Row) (*models.PositionAlert, error) {
+	var a models.PositionAlert
+	err := row.Scan(&a.ID, &a.PortfolioID, &a.PositionID, &a.Metric, &a.Operator, &a.Threshold, &a.Note, &a.Status,
+		&a.LastValue, &a.LastEvaluatedAt, &a.TriggeredValue, &a.TriggeredAt, &a.CreatedAt, &a.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// Create inserts an armed alert and fills in its generated fields
+func (r *PositionAlertRepository) Create(ctx context.Context, a *models.PositionAlert) error {
+	err := r.db.Pool.QueryRow(ctx, `
+		INSERT INTO position_alerts (portfolio_id, position_id, metric, operator, threshold, note)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, status, created_at, updated_at`,
+		a.PortfolioID, a.PositionID, a.Metric, a.Operator, a.Threshold, a.Note,
+	).Scan(&a.ID, &a.Status, &a.CreatedAt, &a.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create position alert: %w", err)
+	}
+	return nil
+}
+
+// List returns a portfolio's alerts, newest first, optionally for one position (0 for all)
+func (r *PositionAlertRepository) List(ctx context.Context, portfolioID, positionID int64) ([]models.PositionAlert, error) {
+	return r.query(ctx, `
+		SELECT `+positionAlertColumns+`
+		FROM position_alerts
+		WHERE portfolio_id = $1 AND ($2 = 0 OR position_id = $2)
+		ORDER BY created_at DESC, id DESC`,
+		portfolioID, positionID)
+}
+
+// ListArmed returns the armed alerts on open positions across all portfolios that aren't
+// deleted, for the evaluator
+func (r *PositionAlertRepository) ListArmed(ctx context.Context) ([]models.PositionAlert, error) {
+	return r.query(ctx, `
+		SELECT `+positionAlertColumns+`
+		FROM position_alerts
+		WHERE status = 'armed' AND position_id IN (SELECT id FROM positions WHERE status = 'open')
+			AND portfolio_id IN (SELECT id FROM portfolios WHERE deleted_at IS NULL)
+		ORDER BY portfolio_id, position_id, id`)
+}
+
+// query runs a position alert SELECT and scans every row
+func (r *PositionAlertRepository) query(ctx context.Context, sql string, args ...any) ([]models.PositionAlert, error) {
+	rows, err := r.db.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list position alerts: %w", err)
+	}
+	defer rows.Close()
+
+	alerts := []models.PositionAlert{}
+	for rows.Next() {
+		a, err := scanPositionAlert(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan position alert: %w", err)
+		}
+		alerts = append(alerts, *a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read position alerts: %w", err)
+	}
+	return alerts, nil
+}
+
+// Get returns a single alert within a portfolio
+func (r *PositionAlertRepository) Get(ctx context.Context, portfolioID, id int64) (*models.PositionAlert, error) {
+	a, err := scanPositionAlert(r.db.Pool.QueryRow(ctx, `
+		SELECT `+positionAlertColumns+` FROM position_alerts WHERE portfolio_id = $1 AND id = $2`,
+		portfolioID, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get position alert: %w", err)
+	}
+	return a, nil
+}
+
+// Update saves an alert's rule, note and status. Re-arming clears the last trigger.
+func (r *PositionAlertRepository) Update(ctx context.Context, a *models.PositionAlert) error {
+	err := r.db.Pool.QueryRow(ctx, `
+		UPDATE position_alerts
+		SET metric = $3, operator = $4, threshold = $5, note = $6, status = $7,
+		    triggered_value = CASE WHEN $7 = 'armed' THEN NULL ELSE triggered_value END,
+		    triggered_at = CASE WHEN $7 = 'armed' THEN NULL ELSE triggered_at END,
+		    updated_at = NOW()
+		WHERE portfolio_id = $1 AND id = $2
+		RETURNING triggered_value, triggered_at, updated_at`,
+		a.PortfolioID, a.ID, a.Metric, a.Operator, a.Threshold, a.Note, a.Status,
+	).Scan(&a.TriggeredValue, &a.TriggeredAt, &a.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update position alert: %w", err)
+	}
+	return nil
+}
+
+// Delete removes an alert
+func (r *PositionAlertRepository) Delete(ctx context.Context, portfolioID, id int64) error {
+	tag, err := r.db.Pool.Exec(ctx, `DELETE FROM position_alerts WHERE portfolio_id = $1 AND id = $2`, portfolioID, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete position alert: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// RecordEvaluation stores the latest value of an armed alert's metric (nil when
+// unavailable), marking the alert triggered when it matched. Alerts changed since they
+// were loaded are left alone.
+func (r *PositionAlertRepository) RecordEvaluation(ctx context.Context, id int64, value *float64, triggered bool, at time.Time) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		UPDATE position_alerts
+		SET last_value = $2, last_evaluated_at = $4,
+		    status = CASE WHEN $3 THEN 'triggered' ELSE status END,
+		    triggered_value = CASE WHEN $3 THEN $2 ELSE triggered_value END,
+		    triggered_at = CASE WHEN $3 THEN $4 ELSE triggered_at END,
+		    updated_at = CASE WHEN $3 THEN NOW() ELSE updated_at END
+		WHERE id = $1 AND status = 'armed'`,
+		id, value, triggered, at)
+	if err != nil {
+		return fmt.Errorf("failed to record position alert evaluation: %w", err)
+	}
+	return nil
+}
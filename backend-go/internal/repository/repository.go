@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aaronbengochea/periscope/backend-go/pkg/database/rest"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ErrNotFound is returned when a requested record does not exist
+var ErrNotFound = errors.New("record not found")
+
+// ErrDuplicate is returned when a write violates a uniqueness constraint
+var ErrDuplicate = errors.New("record already exists")
+
+// ErrPositionClosed is returned when trading against a position that is no longer open
+var ErrPositionClosed = errors.New("position is closed")
+
+// ErrLedgerHistory is returned when editing an opening trade that later trades depend on
+var ErrLedgerHistory = errors.New("position has trades after its opening transaction")
+
+// ErrUnknownPosition is returned when grouping positions that are not in the portfolio
+var ErrUnknownPosition = errors.New("position not found in portfolio")
+
+// ErrNothingToClose is returned when a closing order has no open position on its side
+var ErrNothingToClose = errors.New("no open position to close")
+
+// ErrTooManyAPIKeys is returned when issuing an API key to a user who holds the most allowed
+var ErrTooManyAPIKeys = errors.New("too many API keys")
+
+// querier is satisfied by both the connection pool and an open transaction
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// uniqueViolation is the Postgres SQLSTATE for unique constraint violations
+const uniqueViolation = "23505"
+
+// isUniqueViolation reports whether err is a unique constraint violation, from Postgres or
+// relayed by the REST API
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == uniqueViolation
+	}
+	var restErr *rest.Error
+	return errors.As(err, &restErr) && restErr.Code == uniqueViolation
+}
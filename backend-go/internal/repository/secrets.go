@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/secrets"
+)
+
+// errNoSecretsKey is returned when opening a sealed secret without an encryption key
+var errNoSecretsKey = errors.New("secret is encrypted but no encryption key is configured")
+
+// sealSecret encrypts a signing secret for storage. Without a box it is stored in the clear,
+// as it was before secrets were encrypted.
+func sealSecret(box *secrets.Box, secret, owner string) (string, error) {
+	if box == nil || secret == "" {
+		return secret, nil
+	}
+	sealed, err := box.Seal(secret, owner)
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt secret: %w", err)
+	}
+	return sealed, nil
+}
+
+// openSecret decrypts a stored signing secret; one stored in the clear is returned as it is
+func openSecret(box *secrets.Box, stored, owner string) (string, error) {
+	if !secrets.Sealed(stored) {
+		return stored, nil
+	}
+	if box == nil {
+		return "", errNoSecretsKey
+	}
+	return box.Open(stored, owner)
+}
+
+// rotateSecrets re-seals with the box's primary key every secret listed that it didn't seal,
+// and seals those stored in the clear when clearText is set. list selects the row ID, the
+// owner the secret is bound to and the stored value, all as text; update sets the value of
+// row $1 to $2 if it is still $3, so a secret changed meanwhile is left alone. Secrets that
+// don't open are logged and skipped. It returns how many secrets were re-sealed.
+func rotateSecrets(ctx context.Context, q querier, box *secrets.Box, name, list, update string, clearText bool) (int, error) {
+	rows, err := q.Query(ctx, list)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list %s: %w", name, err)
+	}
+	type stored struct{ id, owner, value string }
+	var due []stored
+	for rows.Next() {
+		var s stored
+		if err := rows.Scan(&s.id, &s.owner, &s.value); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan %s: %w", name, err)
+		}
+		if box.NeedsRotation(s.value) {
+			due = append(due, s)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+
+	rotated := 0
+	for _, s := range due {
+		var sealed string
+		if clearText && !secrets.Sealed(s.value) {
+			sealed, err = box.Seal(s.value, s.owner)
+		} else {
+			sealed, err = box.Rotate(s.value, s.owner)
+		}
+		if err != nil {
+			log.Printf("[Secrets] ⚠ Failed to re-encrypt %s of %s: %v", name, s.id, err)
+			continue
+		}
+		tag, err := q.Exec(ctx, update, s.id, sealed, s.value)
+		if err != nil {
+			return rotated, fmt.Errorf("failed to save %s: %w", name, err)
+		}
+		rotated += int(tag.RowsAffected())
+	}
+	return rotated, nil
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// SettingsRepository persists users' preferences
+type SettingsRepository struct {
+	db *database.DB
+}
+
+// NewSettingsRepository creates a new settings repository
+func NewSettingsRepository(db *database.DB) *SettingsRepository {
+	return &SettingsRepository{db: db}
+}
+
+// Get returns a user's settings, or the defaults when they have not saved any
+func (r *SettingsRepository) Get(ctx context.Context, userID *string) (*models.UserSettings, error) {
+	s := models.DefaultUserSettings(userID)
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT expiration_window_days, greeks_display, second_order_greeks, risk_free_rate, theme,
+			alert_mode, alert_cooldown_seconds, updated_at
+		FROM user_settings
+		WHERE user_id IS NOT DISTINCT FROM $1::uuid`,
+		userID).Scan(&s.ExpirationWindowDays, &s.GreeksDisplay, &s.SecondOrderGreeks, &s.RiskFreeRate, &s.Theme,
+		&s.AlertMode, &s.AlertCooldownSeconds, &s.UpdatedAt)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to get user settings: %w", err)
+	}
+	return s, nil
+}
+
+// Save replaces a user's settings
+func (r *SettingsRepository) Save(ctx context.Context, s *models.UserSettings) error {
+	err := r.db.Pool.QueryRow(ctx, `
+		INSERT INTO user_settings (user_id, expiration_window_days, greeks_display, second_order_greeks,
+			risk_free_rate, theme, alert_mode, alert_cooldown_seconds)
+		VALUES ($1::uuid, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (COALESCE(user_id, '00000000-0000-0000-0000-000000000000'::uuid))
+		DO UPDATE SET expiration_window_days = EXCLUDED.expiration_window_days,
+		              greeks_display = EXCLUDED.greeks_display,
+		              second_order_greeks = EXCLUDED.second_order_greeks,
+		              risk_free_rate = EXCLUDED.risk_free_rate, theme = EXCLUDED.theme,
+		              alert_mode = EXCLUDED.alert_mode,
+		              alert_cooldown_seconds = EXCLUDED.alert_cooldown_seconds,
+		              updated_at = NOW()
+		RETURNING updated_at`,
+		s.UserID, s.ExpirationWindowDays, s.GreeksDisplay, s.SecondOrderGreeks,
+		s.RiskFreeRate, s.Theme, s.AlertMode, s.AlertCooldownSeconds,
+	).Scan(&s.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save user settings: %w", err)
+	}
+	return nil
+}
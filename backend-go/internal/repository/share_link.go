@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// ShareLinkRepository persists the settings and access audit of portfolio share links
+type ShareLinkRepository struct {
+	db *database.DB
+}
+
+// NewShareLinkRepository creates a new share link repository
+func NewShareLinkRepository(db *database.DB) *ShareLinkRepository {
+	return &ShareLinkRepository{db: db}
+}
+
+const shareLinkColumns = `id, portfolio_id, label, hide_cost_basis, expires_at, revoked_at,
+	access_count, last_accessed_at, created_at`
+
+func scanShareLink(row pgx.Row) (*models.ShareLink, error) {
+	var l models.ShareLink
+	err := row.Scan(&l.ID, &l.PortfolioID, &l.Label, &l.HideCostBasis, &l.ExpiresAt, &l.RevokedAt,
+		&l.AccessCount, &l.LastAccessedAt, &l.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+// Create inserts a share link and fills in its generated fields
+func (r *ShareLinkRepository) Create(ctx context.Context, l *models.ShareLink) error {
+	err := r.db.Pool.QueryRow(ctx, `
+		INSERT INTO share_links (portfolio_id, label, hide_cost_basis, expires_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at`,
+		l.PortfolioID, l.Label, l.HideCostBasis, l.ExpiresAt,
+	).Scan(&l.ID, &l.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create share link: %w", err)
+	}
+	return nil
+}
+
+// List returns a portfolio's share links, newest first, including revoked and expired ones
+func (r *ShareLinkRepository) List(ctx context.Context, portfolioID int64) ([]models.ShareLink, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT `+shareLinkColumns+`
+		FROM share_links
+		WHERE portfolio_id = $1
+		ORDER BY created_at DESC, id DESC`,
+		portfolioID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list share links: %w", err)
+	}
+	defer rows.Close()
+
+	links := []models.ShareLink{}
+	for rows.Next() {
+		l, err := scanShareLink(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan share link: %w", err)
+		}
+		links = append(links, *l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read share links: %w", err)
+	}
+	return links, nil
+}
+
+// Get returns a share link of a portfolio
+func (r *ShareLinkRepository) Get(ctx context.Context, portfolioID, id int64) (*models.ShareLink, error) {
+	l, err := scanShareLink(r.db.Pool.QueryRow(ctx, `
+		SELECT `+shareLinkColumns+` FROM share_links WHERE portfolio_id = $1 AND id = $2`,
+		portfolioID, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get share link: %w", err)
+	}
+	return l, nil
+}
+
+// Revoke disables a share link immediately; revoking twice keeps the first revocation time
+func (r *ShareLinkRepository) Revoke(ctx context.Context, portfolioID, id int64) error {
+	tag, err := r.db.Pool.Exec(ctx, `
+		UPDATE share_links SET revoked_at = COALESCE(revoked_at, NOW())
+		WHERE portfolio_id = $1 AND id = $2`,
+		portfolioID, id)
+	if err != nil {
+		return fmt.Errorf("failed to revoke share link: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// RecordAccess counts a view of a shared portfolio
+func (r *ShareLinkRepository) RecordAccess(ctx context.Context, id int64) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		UPDATE share_links SET access_count = access_count + 1, last_accessed_at = NOW()
+		WHERE id = $1`,
+		id)
+	if err != nil {
+		return fmt.Errorf("failed to record share link access: %w", err)
+	}
+	return nil
+}
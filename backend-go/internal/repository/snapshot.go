@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/database"
+)
+
+// SnapshotRepository persists end-of-day portfolio snapshots
+type SnapshotRepository struct {
+	db *database.DB
+}
+
+// NewSnapshotRepository creates a new snapshot repository
+func NewSnapshotRepository(db *database.DB) *SnapshotRepository {
+	return &SnapshotRepository{db: db}
+}
+
+// Upsert stores a snapshot, replacing any earlier one for the same portfolio and day. A new
+// day's snapshot is announced to the portfolio's webhooks.
+func (r *SnapshotRepository) Upsert(ctx context.Context, s *models.PortfolioSnapshot) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var inserted bool
+	err = tx.QueryRow(ctx, `
+		INSERT INTO portfolio_snapshots (
+			portfolio_id, snapshot_date, market_value, cost_basis, unrealized_pnl, realized_pnl,
+			total_pnl, fees, open_positions, unpriced, delta, dollar_delta, gamma, theta, vega,
+			beta_weighted_delta, beta_weighted_gamma, dividends)
+		VALUES ($1, $2::date, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+		ON CONFLICT (portfolio_id, snapshot_date)
+		DO UPDATE SET market_value = EXCLUDED.market_value,
+		              cost_basis = EXCLUDED.cost_basis,
+		              unrealized_pnl = EXCLUDED.unrealized_pnl,
+		              realized_pnl = EXCLUDED.realized_pnl,
+		              total_pnl = EXCLUDED.total_pnl,
+		              fees = EXCLUDED.fees,
+		              open_positions = EXCLUDED.open_positions,
+		              unpriced = EXCLUDED.unpriced,
+		              delta = EXCLUDED.delta,
+		              dollar_delta = EXCLUDED.dollar_delta,
+		              gamma = EXCLUDED.gamma,
+		              theta = EXCLUDED.theta,
+		              vega = EXCLUDED.vega,
+		              beta_weighted_delta = EXCLUDED.beta_weighted_delta,
+		              beta_weighted_gamma = EXCLUDED.beta_weighted_gamma,
+		              dividends = EXCLUDED.dividends,
+		              updated_at = NOW()
+		RETURNING created_at, xmax = 0`,
+		s.PortfolioID, s.Date, s.MarketValue, s.CostBasis, s.UnrealizedPnL, s.RealizedPnL,
+		s.TotalPnL, s.Fees, s.OpenPositions, s.Unpriced, s.Delta, s.DollarDelta, s.Gamma, s.Theta, s.Vega,
+		s.BetaWeightedDelta, s.BetaWeightedGamma, s.Dividends,
+	).Scan(&s.CreatedAt, &inserted)
+	if err != nil {
+		return fmt.Errorf("failed to upsert portfolio snapshot: %w", err)
+	}
+	if inserted {
+		if err := enqueueWebhookEvent(ctx, tx, s.PortfolioID, models.EventSnapshotCreated, s); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit portfolio snapshot: %w", err)
+	}
+	return nil
+}
+
+// ListSince returns a portfolio's snapshots on or after the given YYYY-MM-DD date, oldest first
+func (r *SnapshotRepository) ListSince(ctx context.Context, portfolioID int64, since string) ([]models.PortfolioSnapshot, error) {
+	rows, err := r.db.Reader().Query(ctx, `
+		SELECT portfolio_id, snapshot_date::text, market_value, cost_basis, unrealized_pnl, realized_pnl,
+		       total_pnl, fees, open_positions, unpriced, delta, dollar_delta, gamma, theta, vega,
+		       beta_weighted_delta, beta_weighted_gamma, dividends, created_at
+		FROM portfolio_snapshots
+		WHERE portfolio_id = $1 AND snapshot_date >= $2::date
+		ORDER BY snapshot_date ASC`,
+		portfolioID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query portfolio snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	snapshots := []models.PortfolioSnapshot{}
+	for rows.Next() {
+		var s models.PortfolioSnapshot
+		if err := rows.Scan(&s.PortfolioID, &s.Date, &s.MarketValue, &s.CostBasis, &s.UnrealizedPnL, &s.RealizedPnL,
+			&s.TotalPnL, &s.Fees, &s.OpenPositions, &s.Unpriced, &s.Delta, &s.DollarDelta, &s.Gamma, &s.Theta, &s.Vega,
+			&s.BetaWeightedDelta, &s.BetaWeightedGamma, &s.Dividends, &s.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan portfolio snapshot: %w", err)
+		}
+		snapshots = append(snapshots, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read portfolio snapshots: %w", err)
+	}
+
+	return snapshots, nil
+}
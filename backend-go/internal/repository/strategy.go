@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// StrategyRepository persists named strategies and the positions grouped into them
+type StrategyRepository struct {
+	db *database.DB
+}
+
+// NewStrategyRepository creates a new strategy repository
+func NewStrategyRepository(db *database.DB) *StrategyRepository {
+	return &StrategyRepository{db: db}
+}
+
+const strategyColumns = `s.id, s.portfolio_id, s.name, s.tags, s.notes,
+	COALESCE((SELECT array_agg(p.id ORDER BY p.id) FROM positions p WHERE p.strategy_id = s.id), '{}'),
+	s.created_at, s.updated_at`
+
+func scanStrategy(row pgx.Row) (*models.Strategy, error) {
+	var s models.Strategy
+	err := row.Scan(&s.ID, &s.PortfolioID, &s.Name, &s.Tags, &s.Notes, &s.PositionIDs, &s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Create inserts a strategy and groups the given positions into it, moving them out of
+// any strategy they were in. Returns ErrUnknownPosition when a position is not in the portfolio.
+func (r *StrategyRepository) Create(ctx context.Context, s *models.Strategy, positionIDs []int64) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO strategies (portfolio_id, name, tags, notes)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, updated_at`,
+		s.PortfolioID, s.Name, s.Tags, s.Notes,
+	).Scan(&s.ID, &s.CreatedAt, &s.UpdatedAt)
+	if isUniqueViolation(err) {
+		return ErrDuplicate
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create strategy: %w", err)
+	}
+
+	if err := assignPositions(ctx, tx, s.PortfolioID, s.ID, positionIDs); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit strategy: %w", err)
+	}
+	s.PositionIDs = sortedIDs(positionIDs)
+	return nil
+}
+
+// List returns a portfolio's strategies by name, optionally only those with a tag ("" for all)
+func (r *StrategyRepository) List(ctx context.Context, portfolioID int64, tag string) ([]models.Strategy, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT `+strategyColumns+`
+		FROM strategies s
+		WHERE s.portfolio_id = $1 AND ($2 = '' OR $2 = ANY(s.tags))
+		ORDER BY s.name, s.id`,
+		portfolioID, tag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list strategies: %w", err)
+	}
+	defer rows.Close()
+
+	strategies := []models.Strategy{}
+	for rows.Next() {
+		s, err := scanStrategy(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan strategy: %w", err)
+		}
+		strategies = append(strategies, *s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read strategies: %w", err)
+	}
+	return strategies, nil
+}
+
+// Get returns a single strategy within a portfolio
+func (r *StrategyRepository) Get(ctx context.Context, portfolioID, id int64) (*models.Strategy, error) {
+	s, err := scanStrategy(r.db.Pool.QueryRow(ctx, `
+		SELECT `+strategyColumns+` FROM strategies s WHERE s.portfolio_id = $1 AND s.id = $2`,
+		portfolioID, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get strategy: %w", err)
+	}
+	return s, nil
+}
+
+// Update saves a strategy's name, tags and notes, groups the added positions into it and
+// ungroups the removed ones, then reloads its positions
+func (r *StrategyRepository) Update(ctx context.Context, s *models.Strategy, add, remove []int64) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE strategies SET name = $3, tags = $4, notes = $5, updated_at = NOW()
+		WHERE portfolio_id = $1 AND id = $2`,
+		s.PortfolioID, s.ID, s.Name, s.Tags, s.Notes)
+	if isUniqueViolation(err) {
+		return ErrDuplicate
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update strategy: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	if err := assignPositions(ctx, tx, s.PortfolioID, s.ID, add); err != nil {
+		return err
+	}
+	if len(remove) > 0 {
+		if _, err := tx.Exec(ctx, `
+			UPDATE positions SET strategy_id = NULL, updated_at = NOW()
+			WHERE strategy_id = $1 AND id = ANY($2)`,
+			s.ID, remove); err != nil {
+			return fmt.Errorf("failed to ungroup positions: %w", err)
+		}
+	}
+
+	updated, err := scanStrategy(tx.QueryRow(ctx, `
+		SELECT `+strategyColumns+` FROM strategies s WHERE s.id = $1`, s.ID))
+	if err != nil {
+		return fmt.Errorf("failed to reload strategy: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit strategy: %w", err)
+	}
+	*s = *updated
+	return nil
+}
+
+// Delete removes a strategy; its positions are ungrouped, not deleted
+func (r *StrategyRepository) Delete(ctx context.Context, portfolioID, id int64) error {
+	tag, err := r.db.Pool.Exec(ctx, `DELETE FROM strategies WHERE portfolio_id = $1 AND id = $2`, portfolioID, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete strategy: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// assignPositions groups positions of the portfolio into a strategy, returning
+// ErrUnknownPosition when any of them is not in the portfolio
+func assignPositions(ctx context.Context, q querier, portfolioID, strategyID int64, positionIDs []int64) error {
+	if len(positionIDs) == 0 {
+		return nil
+	}
+	ids := sortedIDs(positionIDs)
+	tag, err := q.Exec(ctx, `
+		UPDATE positions SET strategy_id = $3, updated_at = NOW()
+		WHERE portfolio_id = $1 AND id = ANY($2)`,
+		portfolioID, ids, strategyID)
+	if err != nil {
+		return fmt.Errorf("failed to group positions: %w", err)
+	}
+	if tag.RowsAffected() != int64(len(ids)) {
+		return ErrUnknownPosition
+	}
+	return nil
+}
+
+// sortedIDs returns the IDs sorted and without duplicates
+func sortedIDs(ids []int64) []int64 {
+	sorted := append([]int64{}, ids...)
+	slices.Sort(sorted)
+	return slices.Compact(sorted)
+}
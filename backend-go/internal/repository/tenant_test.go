@@ -0,0 +1,260 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/database"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/database/rest"
+)
+
+const (
+	alice = "00000000-0000-0000-0000-00000000000a"
+	bob   = "00000000-0000-0000-0000-00000000000b"
+)
+
+// fakePostgREST serves tables from memory, applying the eq, is.null and not.is.null filters
+// the REST repositories send, so their owner filters are exercised as PostgREST would
+type fakePostgREST struct {
+	mu     sync.Mutex
+	tables map[string][]map[string]any
+}
+
+func (f *fakePostgREST) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	table := strings.TrimPrefix(r.URL.Path, "/rest/v1/")
+	var matched []map[string]any
+	for _, row := range f.tables[table] {
+		if matches(row, r.URL.Query()) {
+			matched = append(matched, row)
+		}
+	}
+
+	if r.Method == http.MethodPatch {
+		var patch map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for _, row := range matched {
+			for k, v := range patch {
+				row[k] = v
+			}
+		}
+	}
+	if matched == nil {
+		matched = []map[string]any{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(matched)
+}
+
+// matches applies a request's column filters to a row, ignoring the other parameters
+func matches(row map[string]any, query map[string][]string) bool {
+	for column, filters := range query {
+		if column == "select" || column == "order" || strings.Contains(column, ".") {
+			continue
+		}
+		value := row[column]
+		for _, filter := range filters {
+			switch {
+			case filter == "is.null":
+				if value != nil {
+					return false
+				}
+			case filter == "not.is.null":
+				if value == nil {
+					return false
+				}
+			case strings.HasPrefix(filter, "eq."):
+				if value == nil || strings.TrimPrefix(filter, "eq.") != stringValue(value) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+func stringValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return ""
+}
+
+func newFakePostgREST(t *testing.T) (*fakePostgREST, *rest.Client) {
+	f := &fakePostgREST{tables: map[string][]map[string]any{
+		"portfolios": {
+			{"id": float64(1), "user_id": alice, "name": "Alice's", "account_type": "taxable", "deleted_at": nil},
+		},
+		"watchlists": {
+			{"id": float64(7), "user_id": alice, "name": "Alice's", "watchlist_items": []any{}, "deleted_at": nil},
+		},
+	}}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	return f, rest.NewClient(srv.URL, "service-key")
+}
+
+func owner(id string) *string { return &id }
+
+func TestRESTPortfolioOwnerMismatchFindsNothing(t *testing.T) {
+	ctx := context.Background()
+	f, client := newFakePostgREST(t)
+	repo := NewRESTPortfolioRepository(client)
+
+	for _, user := range []*string{owner(bob), nil} {
+		if _, err := repo.Get(ctx, user, 1); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Get as %v: got %v, want ErrNotFound", user, err)
+		}
+		if err := repo.Update(ctx, &models.Portfolio{ID: 1, UserID: user, Name: "taken"}); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Update as %v: got %v, want ErrNotFound", user, err)
+		}
+		if err := repo.Delete(ctx, user, 1); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Delete as %v: got %v, want ErrNotFound", user, err)
+		}
+		if list, err := repo.List(ctx, user); err != nil || len(list) != 0 {
+			t.Errorf("List as %v: got %d portfolios, %v; want none", user, len(list), err)
+		}
+	}
+	if row := f.tables["portfolios"][0]; row["name"] != "Alice's" || row["deleted_at"] != nil {
+		t.Errorf("another user changed the portfolio: %v", row)
+	}
+
+	if p, err := repo.Get(ctx, owner(alice), 1); err != nil || p.Name != "Alice's" {
+		t.Errorf("Get as the owner: got %v, %v", p, err)
+	}
+}
+
+func TestRESTWatchlistOwnerMismatchFindsNothing(t *testing.T) {
+	ctx := context.Background()
+	f, client := newFakePostgREST(t)
+	repo := NewRESTWatchlistRepository(client)
+
+	for _, user := range []*string{owner(bob), nil} {
+		if _, err := repo.Get(ctx, user, 7); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Get as %v: got %v, want ErrNotFound", user, err)
+		}
+		if err := repo.Delete(ctx, user, 7); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Delete as %v: got %v, want ErrNotFound", user, err)
+		}
+	}
+	if row := f.tables["watchlists"][0]; row["deleted_at"] != nil {
+		t.Errorf("another user deleted the watchlist: %v", row)
+	}
+
+	if w, err := repo.Get(ctx, owner(alice), 7); err != nil || w.Name != "Alice's" {
+		t.Errorf("Get as the owner: got %v, %v", w, err)
+	}
+}
+
+// TestPostgresOwnerMismatchFindsNothing runs the same checks against the Postgres
+// repositories, and the alert and trigger ones that have no REST version, when
+// TEST_DATABASE_URL names a migrated database to write to. Nothing sets it automatically, so
+// it runs only when started by hand (see the README).
+func TestPostgresOwnerMismatchFindsNothing(t *testing.T) {
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	ctx := context.Background()
+	db, err := database.NewSupabaseDB(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(db.Close)
+
+	portfolios := NewPortfolioRepository(db)
+	p := &models.Portfolio{UserID: owner(alice), Name: "tenant test " + t.Name(), Settings: models.DefaultPortfolioSettings()}
+	if err := portfolios.Create(ctx, p); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = portfolios.Delete(ctx, p.UserID, p.ID)
+		_ = portfolios.Purge(ctx, p.UserID, p.ID)
+	})
+
+	watchlists := NewWatchlistRepository(db)
+	w := &models.Watchlist{UserID: owner(alice), Name: "tenant test " + t.Name()}
+	if err := watchlists.Create(ctx, w, []string{"AAPL"}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = watchlists.Delete(ctx, w.UserID, w.ID)
+		_ = watchlists.Purge(ctx, w.UserID, w.ID)
+	})
+
+	alerts := NewAlertRepository(db)
+	a := &models.Alert{UserID: owner(alice), Ticker: "AAPL", Metric: "price", Operator: ">", Threshold: 200, Mode: models.AlertOneShot}
+	if err := alerts.Create(ctx, a); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = alerts.Delete(ctx, a.UserID, a.ID)
+		_ = alerts.Purge(ctx, a.UserID, a.ID)
+	})
+	trigger, err := alerts.Trigger(ctx, a, 201, nil, time.Now())
+	if err != nil || trigger == nil {
+		t.Fatalf("failed to trigger the alert: %v, %v", trigger, err)
+	}
+
+	for _, user := range []*string{owner(bob), nil} {
+		if _, err := portfolios.Get(ctx, user, p.ID); !errors.Is(err, ErrNotFound) {
+			t.Errorf("portfolio Get as %v: got %v, want ErrNotFound", user, err)
+		}
+		if err := portfolios.Update(ctx, &models.Portfolio{ID: p.ID, UserID: user, Name: "taken"}); !errors.Is(err, ErrNotFound) {
+			t.Errorf("portfolio Update as %v: got %v, want ErrNotFound", user, err)
+		}
+		if err := portfolios.Delete(ctx, user, p.ID); !errors.Is(err, ErrNotFound) {
+			t.Errorf("portfolio Delete as %v: got %v, want ErrNotFound", user, err)
+		}
+		if _, err := watchlists.Get(ctx, user, w.ID); !errors.Is(err, ErrNotFound) {
+			t.Errorf("watchlist Get as %v: got %v, want ErrNotFound", user, err)
+		}
+		if err := watchlists.Delete(ctx, user, w.ID); !errors.Is(err, ErrNotFound) {
+			t.Errorf("watchlist Delete as %v: got %v, want ErrNotFound", user, err)
+		}
+		if _, err := alerts.Get(ctx, user, a.ID); !errors.Is(err, ErrNotFound) {
+			t.Errorf("alert Get as %v: got %v, want ErrNotFound", user, err)
+		}
+		taken := *a
+		taken.UserID, taken.Status = user, models.AlertDisabled
+		if err := alerts.Update(ctx, &taken); !errors.Is(err, ErrNotFound) {
+			t.Errorf("alert Update as %v: got %v, want ErrNotFound", user, err)
+		}
+		if err := alerts.Delete(ctx, user, a.ID); !errors.Is(err, ErrNotFound) {
+			t.Errorf("alert Delete as %v: got %v, want ErrNotFound", user, err)
+		}
+		if _, err := alerts.Acknowledge(ctx, user, trigger.ID); !errors.Is(err, ErrNotFound) {
+			t.Errorf("alert trigger Acknowledge as %v: got %v, want ErrNotFound", user, err)
+		}
+		if n, err := alerts.AcknowledgeAll(ctx, user, a.ID); err != nil || n != 0 {
+			t.Errorf("alert trigger AcknowledgeAll as %v: got %d, %v; want none", user, n, err)
+		}
+	}
+	if got, err := portfolios.Get(ctx, owner(alice), p.ID); err != nil || got.Name != p.Name {
+		t.Errorf("portfolio Get as the owner: got %v, %v", got, err)
+	}
+	if got, err := alerts.Get(ctx, owner(alice), a.ID); err != nil || got.Status != models.AlertTriggered {
+		t.Errorf("alert Get as the owner: got %v, %v; want it still triggered", got, err)
+	}
+	if got, err := alerts.Acknowledge(ctx, owner(alice), trigger.ID); err != nil || got.AcknowledgedAt == nil {
+		t.Errorf("alert trigger Acknowledge as the owner: got %v, %v", got, err)
+	}
+}
@@ -0,0 +1,617 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/ledger"
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// Trade describes a fill against an existing position
+type Trade struct {
+	Quantity float64
+	Price    float64
+	Fees     float64
+	TradedAt string // YYYY-MM-DD
+	Related  *int64 // transaction that caused this one, e.g. the assignment behind a share delivery
+}
+
+// TransactionRepository records the trade ledger. Every write updates the ledger,
+// the FIFO lots and the position's running totals in a single database transaction.
+type TransactionRepository struct {
+	db *database.DB
+}
+
+// NewTransactionRepository creates a new transaction repository
+func NewTransactionRepository(db *database.DB) *TransactionRepository {
+	return &TransactionRepository{db: db}
+}
+
+const transactionColumns = `id, portfolio_id, position_id, action, quantity, price, fees, amount,
+	realized_pnl, related_transaction_id, traded_at::text, created_at`
+
+const lotColumns = `id, position_id, transaction_id, quantity, remaining_quantity, price, fees,
+	opened_at::text, created_at`
+
+func scanTransaction(row pgx.Row) (*models.Transaction, error) {
+	var t models.Transaction
+	err := row.Scan(&t.ID, &t.PortfolioID, &t.PositionID, &t.Action, &t.Quantity, &t.Price, &t.Fees, &t.Amount,
+		&t.RealizedPnL, &t.RelatedTransactionID, &t.TradedAt, &t.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func scanLot(row pgx.Row) (*models.Lot, error) {
+	var l models.Lot
+	err := row.Scan(&l.ID, &l.PositionID, &l.TransactionID, &l.Quantity, &l.RemainingQuantity, &l.Price, &l.Fees,
+		&l.OpenedAt, &l.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+// Open inserts a new position together with its opening transaction and first lot
+func (r *TransactionRepository) Open(ctx context.Context, p *models.Position, fees float64) (*models.Transaction, error) {
+	var t *models.Transaction
+	err := r.db.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		var err error
+		t, err = openPosition(ctx, tx, p, models.TransactionOpen, fees, nil)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Add records an additional buy (long) or sell (short) into an open position as a new lot
+func (r *TransactionRepository) Add(ctx context.Context, portfolioID, positionID int64, trade Trade) (*models.Position, *models.Transaction, error) {
+	var p *models.Position
+	var t *models.Transaction
+	err := r.db.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		var err error
+		if p, err = lockOpenPosition(ctx, tx, portfolioID, positionID); err != nil {
+			return err
+		}
+		t, err = addToPosition(ctx, tx, p, trade)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return p, t, nil
+}
+
+// Close reduces an open position by matching the trade against its lots oldest first.
+// The position is marked closed once no quantity remains.
+func (r *TransactionRepository) Close(ctx context.Context, portfolioID, positionID int64, trade Trade) (*models.Position, *models.Transaction, error) {
+	var p *models.Position
+	var t *models.Transaction
+	err := r.db.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		var err error
+		if p, err = lockOpenPosition(ctx, tx, portfolioID, positionID); err != nil {
+			return err
+		}
+		t, err = closePosition(ctx, tx, p, models.TransactionClose, trade)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return p, t, nil
+}
+
+// Roll closes quantity of an open position and opens the replacement position in one step.
+// The roll_in transaction references the roll_out transaction, and the replacement joins
+// the rolled position's strategy.
+func (r *TransactionRepository) Roll(ctx context.Context, portfolioID, positionID int64, trade Trade, next *models.Position, openFees float64) (*models.Transaction, *models.Transaction, error) {
+	var out, in *models.Transaction
+	err := r.db.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		p, err := lockOpenPosition(ctx, tx, portfolioID, positionID)
+		if err != nil {
+			return err
+		}
+		if out, err = closePosition(ctx, tx, p, models.TransactionRollOut, trade); err != nil {
+			return err
+		}
+		next.StrategyID = p.StrategyID
+		in, err = openPosition(ctx, tx, next, models.TransactionRollIn, openFees, &out.ID)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return out, in, nil
+}
+
+// CorrectOpen rewrites the opening trade of a position from its side, quantity, open price,
+// opened date and fees. Only allowed while the opening transaction is the position's only trade.
+func (r *TransactionRepository) CorrectOpen(ctx context.Context, p *models.Position) error {
+	return r.db.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		current, err := lockOpenPosition(ctx, tx, p.PortfolioID, p.ID)
+		if err != nil {
+			return err
+		}
+
+		var count int
+		if err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM transactions WHERE position_id = $1`, current.ID).Scan(&count); err != nil {
+			return fmt.Errorf("failed to count transactions: %w", err)
+		}
+		if count > 1 {
+			return ErrLedgerHistory
+		}
+
+		amount := ledger.Amount(p.Side, false, p.Quantity, p.OpenPrice, p.Fees, p.Multiplier)
+		var transactionID int64
+		err = tx.QueryRow(ctx, `
+			UPDATE transactions
+			SET quantity = $2, price = $3, fees = $4, amount = $5, traded_at = $6::date
+			WHERE position_id = $1 AND action IN ('open', 'roll_in')
+			RETURNING id`,
+			p.ID, p.Quantity, p.OpenPrice, p.Fees, amount, p.OpenedAt).Scan(&transactionID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to update opening transaction: %w", err)
+		}
+
+		_, err = tx.Exec(ctx, `
+			UPDATE position_lots
+			SET quantity = $2, remaining_quantity = $2, price = $3, fees = $4, opened_at = $5::date
+			WHERE transaction_id = $1`,
+			transactionID, p.Quantity, p.OpenPrice, p.Fees, p.OpenedAt)
+		if err != nil {
+			return fmt.Errorf("failed to update lot: %w", err)
+		}
+
+		updated, err := scanPosition(tx.QueryRow(ctx, `
+			UPDATE positions
+			SET side = $3, quantity = $4, open_price = $5, opened_at = $6::date, fees = $7, updated_at = NOW()
+			WHERE portfolio_id = $1 AND id = $2
+			RETURNING `+positionColumns,
+			p.PortfolioID, p.ID, p.Side, p.Quantity, p.OpenPrice, p.OpenedAt, p.Fees))
+		if err != nil {
+			return fmt.Errorf("failed to update position: %w", err)
+		}
+		*p = *updated
+		return nil
+	})
+}
+
+// List returns a portfolio's transactions, newest first, optionally for one position (0 for all)
+func (r *TransactionRepository) List(ctx context.Context, portfolioID, positionID int64) ([]models.Transaction, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT `+transactionColumns+`
+		FROM transactions
+		WHERE portfolio_id = $1 AND ($2 = 0 OR position_id = $2)
+		ORDER BY traded_at DESC, id DESC`,
+		portfolioID, positionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transactions: %w", err)
+	}
+	defer rows.Close()
+
+	transactions := []models.Transaction{}
+	for rows.Next() {
+		t, err := scanTransaction(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan transaction: %w", err)
+		}
+		transactions = append(transactions, *t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read transactions: %w", err)
+	}
+	return transactions, nil
+}
+
+// Get returns a single transaction with the lot closures it recorded
+func (r *TransactionRepository) Get(ctx context.Context, portfolioID, id int64) (*models.Transaction, error) {
+	t, err := scanTransaction(r.db.Pool.QueryRow(ctx, `
+		SELECT `+transactionColumns+` FROM transactions WHERE portfolio_id = $1 AND id = $2`,
+		portfolioID, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT id, transaction_id, lot_id, quantity, open_value, close_value, realized_pnl
+		FROM lot_closures
+		WHERE transaction_id = $1
+		ORDER BY id`, t.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list lot closures: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var lc models.LotClosure
+		if err := rows.Scan(&lc.ID, &lc.TransactionID, &lc.LotID, &lc.Quantity, &lc.OpenValue, &lc.CloseValue, &lc.RealizedPnL); err != nil {
+			return nil, fmt.Errorf("failed to scan lot closure: %w", err)
+		}
+		t.Closures = append(t.Closures, lc)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read lot closures: %w", err)
+	}
+	return t, nil
+}
+
+// ListLots returns every lot of a position, oldest first
+func (r *TransactionRepository) ListLots(ctx context.Context, portfolioID, positionID int64) ([]models.Lot, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT l.id, l.position_id, l.transaction_id, l.quantity, l.remaining_quantity, l.price, l.fees,
+			l.opened_at::text, l.created_at
+		FROM position_lots l
+		JOIN positions p ON p.id = l.position_id
+		WHERE p.portfolio_id = $1 AND l.position_id = $2
+		ORDER BY l.opened_at, l.id`,
+		portfolioID, positionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list lots: %w", err)
+	}
+	return collectLots(rows)
+}
+
+func collectLots(rows pgx.Rows) ([]models.Lot, error) {
+	defer rows.Close()
+
+	lots := []models.Lot{}
+	for rows.Next() {
+		l, err := scanLot(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan lot: %w", err)
+		}
+		lots = append(lots, *l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read lots: %w", err)
+	}
+	return lots, nil
+}
+
+// lockOpenPosition loads a position for update, failing if it is not open
+func lockOpenPosition(ctx context.Context, q querier, portfolioID, positionID int64) (*models.Position, error) {
+	p, err := scanPosition(q.QueryRow(ctx, `
+		SELECT `+positionColumns+` FROM positions WHERE portfolio_id = $1 AND id = $2 FOR UPDATE`,
+		portfolioID, positionID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock position: %w", err)
+	}
+	if p.Status != models.PositionOpen {
+		return nil, ErrPositionClosed
+	}
+	return p, nil
+}
+
+// listOpenLots returns a position's lots with quantity remaining, oldest first
+func listOpenLots(ctx context.Context, q querier, positionID int64) ([]models.Lot, error) {
+	rows, err := q.Query(ctx, `
+		SELECT `+lotColumns+`
+		FROM position_lots
+		WHERE position_id = $1 AND remaining_quantity > 0
+		ORDER BY opened_at, id`,
+		positionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list lots: %w", err)
+	}
+	return collectLots(rows)
+}
+
+// openPosition inserts a position with its opening transaction and lot
+func openPosition(ctx context.Context, q querier, p *models.Position, action string, fees float64, related *int64) (*models.Transaction, error) {
+	err := q.QueryRow(ctx, `
+		INSERT INTO positions (portfolio_id, asset_type, ticker, underlying_ticker, contract_type,
+			strike_price, expiration_date, side, quantity, multiplier, open_price, opened_at, fees, strategy_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7::date, $8, $9, $10, $11, $12::date, $13, $14)
+		RETURNING id, status, fees, realized_pnl, created_at, updated_at`,
+		p.PortfolioID, p.AssetType, p.Ticker, p.UnderlyingTicker, p.ContractType,
+		p.StrikePrice, p.ExpirationDate, p.Side, p.Quantity, p.Multiplier, p.OpenPrice, p.OpenedAt, fees, p.StrategyID,
+	).Scan(&p.ID, &p.Status, &p.Fees, &p.RealizedPnL, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create position: %w", err)
+	}
+
+	t := &models.Transaction{
+		PortfolioID:          p.PortfolioID,
+		PositionID:           p.ID,
+		Action:               action,
+		Quantity:             p.Quantity,
+		Price:                p.OpenPrice,
+		Fees:                 fees,
+		Amount:               ledger.Amount(p.Side, false, p.Quantity, p.OpenPrice, fees, p.Multiplier),
+		RelatedTransactionID: related,
+		TradedAt:             p.OpenedAt,
+	}
+	if err := insertTransaction(ctx, q, t); err != nil {
+		return nil, err
+	}
+	if err := insertLot(ctx, q, t); err != nil {
+		return nil, err
+	}
+	if err := enqueueWebhookEvent(ctx, q, p.PortfolioID, models.EventPositionOpened, p); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// addToPosition records an add transaction, opens its lot and refreshes the position totals
+func addToPosition(ctx context.Context, q querier, p *models.Position, trade Trade) (*models.Transaction, error) {
+	t := &models.Transaction{
+		PortfolioID:          p.PortfolioID,
+		PositionID:           p.ID,
+		Action:               models.TransactionAdd,
+		Quantity:             trade.Quantity,
+		Price:                trade.Price,
+		Fees:                 trade.Fees,
+		Amount:               ledger.Amount(p.Side, false, trade.Quantity, trade.Price, trade.Fees, p.Multiplier),
+		TradedAt:             trade.TradedAt,
+		RelatedTransactionID: trade.Related,
+	}
+	if err := insertTransaction(ctx, q, t); err != nil {
+		return nil, err
+	}
+	if err := insertLot(ctx, q, t); err != nil {
+		return nil, err
+	}
+
+	lots, err := listOpenLots(ctx, q, p.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := refreshPosition(ctx, q, p, lots, trade.TradedAt); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// closePosition matches a closing trade against the position's lots, records the
+// transaction and lot closures, and refreshes the position totals
+func closePosition(ctx context.Context, q querier, p *models.Position, action string, trade Trade) (*models.Transaction, error) {
+	lots, err := listOpenLots(ctx, q, p.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	fills, err := ledger.CloseFIFO(lots, p.Side, p.Multiplier, trade.Quantity, trade.Price, trade.Fees)
+	if err != nil {
+		return nil, err
+	}
+	realized := ledger.RealizedPnL(fills)
+
+	t := &models.Transaction{
+		PortfolioID:          p.PortfolioID,
+		PositionID:           p.ID,
+		Action:               action,
+		Quantity:             trade.Quantity,
+		Price:                trade.Price,
+		Fees:                 trade.Fees,
+		Amount:               ledger.Amount(p.Side, true, trade.Quantity, trade.Price, trade.Fees, p.Multiplier),
+		RealizedPnL:          &realized,
+		TradedAt:             trade.TradedAt,
+		RelatedTransactionID: trade.Related,
+	}
+	if err := insertTransaction(ctx, q, t); err != nil {
+		return nil, err
+	}
+
+	for _, f := range fills {
+		if _, err := q.Exec(ctx, `UPDATE position_lots SET remaining_quantity = $2 WHERE id = $1`,
+			f.Lot.ID, f.Lot.RemainingQuantity); err != nil {
+			return nil, fmt.Errorf("failed to update lot: %w", err)
+		}
+
+		lc := models.LotClosure{
+			TransactionID: t.ID,
+			LotID:         f.Lot.ID,
+			Quantity:      f.Quantity,
+			OpenValue:     f.OpenValue,
+			CloseValue:    f.CloseValue,
+			RealizedPnL:   f.RealizedPnL,
+		}
+		err := q.QueryRow(ctx, `
+			INSERT INTO lot_closures (transaction_id, lot_id, quantity, open_value, close_value, realized_pnl)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id`,
+			lc.TransactionID, lc.LotID, lc.Quantity, lc.OpenValue, lc.CloseValue, lc.RealizedPnL).Scan(&lc.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to record lot closure: %w", err)
+		}
+		t.Closures = append(t.Closures, lc)
+	}
+
+	if err := refreshPosition(ctx, q, p, lots, trade.TradedAt); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func insertTransaction(ctx context.Context, q querier, t *models.Transaction) error {
+	err := q.QueryRow(ctx, `
+		INSERT INTO transactions (portfolio_id, position_id, action, quantity, price, fees, amount,
+			realized_pnl, related_transaction_id, traded_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10::date)
+		RETURNING id, created_at`,
+		t.PortfolioID, t.PositionID, t.Action, t.Quantity, t.Price, t.Fees, t.Amount,
+		t.RealizedPnL, t.RelatedTransactionID, t.TradedAt).Scan(&t.ID, &t.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record transaction: %w", err)
+	}
+	return nil
+}
+
+// insertLot opens a lot for the full quantity of an opening transaction
+func insertLot(ctx context.Context, q querier, t *models.Transaction) error {
+	_, err := q.Exec(ctx, `
+		INSERT INTO position_lots (position_id, transaction_id, quantity, remaining_quantity, price, fees, opened_at)
+		VALUES ($1, $2, $3, $3, $4, $5, $6::date)`,
+		t.PositionID, t.ID, t.Quantity, t.Price, t.Fees, t.TradedAt)
+	if err != nil {
+		return fmt.Errorf("failed to open lot: %w", err)
+	}
+	return nil
+}
+
+// refreshPosition rolls the open lots and ledger totals up into the position row.
+// Once no quantity remains the position is closed as of tradedAt, keeping its last open price.
+func refreshPosition(ctx context.Context, q querier, p *models.Position, lots []models.Lot, tradedAt string) error {
+	quantity := ledger.OpenQuantity(lots)
+
+	status := models.PositionOpen
+	var openPrice *float64
+	var closedAt *string
+	if quantity > 0 {
+		avg := ledger.AveragePrice(lots)
+		openPrice = &avg
+	} else {
+		status = models.PositionClosed
+		closedAt = &tradedAt
+	}
+
+	updated, err := scanPosition(q.QueryRow(ctx, `
+		UPDATE positions p
+		SET quantity = $3,
+			open_price = COALESCE($4, p.open_price),
+			status = $5,
+			closed_at = $6::date,
+			close_price = t.avg_close_price,
+			fees = t.total_fees,
+			realized_pnl = t.total_realized_pnl,
+			updated_at = NOW()
+		FROM (
+			SELECT
+				SUM(quantity * price) FILTER (WHERE action IN ('close', 'roll_out', 'expire', 'assign', 'exercise'))
+					/ NULLIF(SUM(quantity) FILTER (WHERE action IN ('close', 'roll_out', 'expire', 'assign', 'exercise')), 0) AS avg_close_price,
+				COALESCE(SUM(fees), 0) AS total_fees,
+				COALESCE(SUM(realized_pnl), 0) AS total_realized_pnl
+			FROM transactions
+			WHERE position_id = $2
+		) t
+		WHERE p.portfolio_id = $1 AND p.id = $2
+		RETURNING `+positionColumns,
+		p.PortfolioID, p.ID, quantity, openPrice, status, closedAt))
+	if err != nil {
+		return fmt.Errorf("failed to update position: %w", err)
+	}
+	if p.Status == models.PositionOpen && updated.Status == models.PositionClosed {
+		if err := enqueueWebhookEvent(ctx, q, p.PortfolioID, models.EventPositionClosed, updated); err != nil {
+			return err
+		}
+	}
+	*p = *updated
+	return nil
+}
+
+// ListRealized returns the lots closed by a portfolio's transactions traded between two
+// YYYY-MM-DD dates inclusive, ordered by close date
+func (r *TransactionRepository) ListRealized(ctx context.Context, portfolioID int64, from, to string) ([]models.RealizedLot, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT t.id, lc.lot_id, p.id, p.ticker, p.underlying_ticker, p.asset_type, p.side, t.action,
+			lc.quantity, l.opened_at::text, t.traded_at::text, lc.open_value, lc.close_value, lc.realized_pnl
+		FROM lot_closures lc
+		JOIN transactions t ON t.id = lc.transaction_id
+		JOIN position_lots l ON l.id = lc.lot_id
+		JOIN positions p ON p.id = t.position_id
+		WHERE t.portfolio_id = $1 AND t.traded_at BETWEEN $2::date AND $3::date
+		ORDER BY t.traded_at, t.id, l.opened_at, l.id`,
+		portfolioID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list realized lots: %w", err)
+	}
+	defer rows.Close()
+
+	realized := []models.RealizedLot{}
+	for rows.Next() {
+		var rl models.RealizedLot
+		var openValue, closeValue float64
+		if err := rows.Scan(&rl.TransactionID, &rl.LotID, &rl.PositionID, &rl.Ticker, &rl.UnderlyingTicker,
+			&rl.AssetType, &rl.Side, &rl.Action, &rl.Quantity, &rl.OpenedAt, &rl.ClosedAt,
+			&openValue, &closeValue, &rl.RealizedPnL); err != nil {
+			return nil, fmt.Errorf("failed to scan realized lot: %w", err)
+		}
+		if rl.Side == models.SideShort {
+			rl.Proceeds, rl.CostBasis = openValue, closeValue
+		} else {
+			rl.Proceeds, rl.CostBasis = closeValue, openValue
+		}
+		realized = append(realized, rl)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read realized lots: %w", err)
+	}
+	return realized, nil
+}
+
+// ListAcquisitions returns the lots a portfolio opened between two YYYY-MM-DD dates
+// inclusive, oldest first
+func (r *TransactionRepository) ListAcquisitions(ctx context.Context, portfolioID int64, from, to string) ([]ledger.Acquisition, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT l.id, p.ticker, p.side, l.quantity, l.opened_at::text
+		FROM position_lots l
+		JOIN positions p ON p.id = l.position_id
+		WHERE p.portfolio_id = $1 AND l.opened_at BETWEEN $2::date AND $3::date
+		ORDER BY l.opened_at, l.id`,
+		portfolioID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list acquisitions: %w", err)
+	}
+	defer rows.Close()
+
+	acquisitions := []ledger.Acquisition{}
+	for rows.Next() {
+		var a ledger.Acquisition
+		if err := rows.Scan(&a.LotID, &a.Ticker, &a.Side, &a.Quantity, &a.OpenedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan acquisition: %w", err)
+		}
+		acquisitions = append(acquisitions, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read acquisitions: %w", err)
+	}
+	return acquisitions, nil
+}
+
+// QuantityChanges returns every dated change in a position's quantity from the ledger:
+// lots opened and lot quantities consumed by closing transactions, oldest first
+func (r *TransactionRepository) QuantityChanges(ctx context.Context, positionID int64) ([]ledger.QuantityChange, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT opened_at::text AS date, quantity FROM position_lots WHERE position_id = $1
+		UNION ALL
+		SELECT t.traded_at::text, -lc.quantity
+		FROM lot_closures lc
+		JOIN transactions t ON t.id = lc.transaction_id
+		WHERE t.position_id = $1
+		ORDER BY date`,
+		positionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quantity changes: %w", err)
+	}
+	defer rows.Close()
+
+	changes := []ledger.QuantityChange{}
+	for rows.Next() {
+		var c ledger.QuantityChange
+		if err := rows.Scan(&c.Date, &c.Quantity); err != nil {
+			return nil, fmt.Errorf("failed to scan quantity change: %w", err)
+		}
+		changes = append(changes, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read quantity changes: %w", err)
+	}
+	return changes, nil
+}
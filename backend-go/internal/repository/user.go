@@ -0,0 +1,411 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/secrets"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// userCacheTTL is how long a user read for a request is reused. Changes made through the
+// repository invalidate it in every instance; usage recorded with each request only in the
+// instance serving it, so other instances' quota counts may lag by up to this long.
+const userCacheTTL = 30 * time.Second
+
+// userSeenResolution is how stale a user's last-seen time may get before a request records
+// it again, so active users do not write on every call
+const userSeenResolution = time.Minute
+
+// UserRepository records the users calling the API and the actions admins take on them
+type UserRepository struct {
+	db    *database.DB
+	users *database.Cache[models.User] // by ID
+}
+
+// NewUserRepository creates a new user repository
+func NewUserRepository(db *database.DB) *UserRepository {
+	return &UserRepository{db: db, users: database.NewCache[models.User](db, "users", userCacheTTL)}
+}
+
+// Connected reports whether the database has been reached, so users can be recorded
+func (r *UserRepository) Connected() bool {
+	return r.db.Connected()
+}
+
+const userColumns = `id::text, provider, email, disabled_at, disabled_reason, quota_reset_at, last_seen_at, created_at,
+	tier, quota_period_start, quota_pages, quota_contracts, deletion_requested_at, deletion_scheduled_at, deleted_at,
+	massive_key_ciphertext, massive_key_hint, massive_key_set_at`
+
+func scanUser(row pgx.Row) (*models.User, error) {
+	var u models.User
+	err := row.Scan(&u.ID, &u.Provider, &u.Email, &u.DisabledAt, &u.DisabledReason, &u.QuotaResetAt, &u.LastSeenAt, &u.CreatedAt,
+		&u.Tier, &u.QuotaPeriodStart, &u.QuotaPages, &u.QuotaContracts, &u.DeletionRequestedAt, &u.DeletionScheduledAt, &u.DeletedAt,
+		&u.MassiveKeyCiphertext, &u.MassiveKeyHint, &u.MassiveKeySetAt)
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// Touch returns the user, recording them on their first request and noting that they were
+// seen. email is kept when the request does not carry one, as with API keys.
+func (r *UserRepository) Touch(ctx context.Context, id string, email *string, now time.Time) (*models.User, error) {
+	u, err := r.Get(ctx, id)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	if u != nil && now.Sub(u.LastSeenAt) < userSeenResolution && (email == nil || (u.Email != nil && *u.Email == *email)) {
+		return u, nil
+	}
+
+	u, err = scanUser(r.db.Pool.QueryRow(ctx, `
+		INSERT INTO users (id, email, last_seen_at)
+		VALUES ($1::uuid, $2, $3)
+		ON CONFLICT (id) DO UPDATE
+		SET email = CASE WHEN users.deleted_at IS NULL THEN COALESCE(EXCLUDED.email, users.email) END,
+			last_seen_at = EXCLUDED.last_seen_at,
+			updated_at = NOW()
+		RETURNING `+userColumns,
+		id, email, now))
+	if err != nil {
+		return nil, fmt.Errorf("failed to record user: %w", err)
+	}
+	r.users.Set(id, *u)
+	return u, nil
+}
+
+// TouchGoogle returns the user a Google account signs in as, provisioning a new user on the
+// account's first sign-in, and notes that they were seen
+func (r *UserRepository) TouchGoogle(ctx context.Context, sub string, email *string, now time.Time) (*models.User, error) {
+	u, err := scanUser(r.db.Pool.QueryRow(ctx, `SELECT `+userColumns+` FROM users WHERE google_sub = $1`, sub))
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if err == nil && now.Sub(u.LastSeenAt) < userSeenResolution && (email == nil || (u.Email != nil && *u.Email == *email)) {
+		return u, nil
+	}
+
+	u, err = scanUser(r.db.Pool.QueryRow(ctx, `
+		INSERT INTO users (id, provider, google_sub, email, last_seen_at)
+		VALUES (gen_random_uuid(), $1, $2, $3, $4)
+		ON CONFLICT (google_sub) DO UPDATE
+		SET email = CASE WHEN users.deleted_at IS NULL THEN COALESCE(EXCLUDED.email, users.email) END,
+			last_seen_at = EXCLUDED.last_seen_at,
+			updated_at = NOW()
+		RETURNING `+userColumns,
+		models.UserProviderGoogle, sub, email, now))
+	if err != nil {
+		return nil, fmt.Errorf("failed to record user: %w", err)
+	}
+	r.users.Set(u.ID, *u)
+	return u, nil
+}
+
+// Get returns a user by ID, reusing a recent read
+func (r *UserRepository) Get(ctx context.Context, id string) (*models.User, error) {
+	if u, ok := r.users.Get(id); ok {
+		return &u, nil
+	}
+	u, err := scanUser(r.db.Pool.QueryRow(ctx, `SELECT `+userColumns+` FROM users WHERE id = $1::uuid`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	r.users.Set(id, *u)
+	return u, nil
+}
+
+// List returns users matching the filter, most recently seen first
+func (r *UserRepository) List(ctx context.Context, f models.UserFilter) ([]models.User, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT `+userColumns+`
+		FROM users
+		WHERE ($1 = '' OR lower(email) LIKE '%' || lower($1) || '%')
+			AND ($2::boolean IS NULL OR (disabled_at IS NOT NULL) = $2)
+		ORDER BY last_seen_at DESC, id
+		LIMIT $3 OFFSET $4`,
+		f.Email, f.Disabled, f.Limit, f.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	defer rows.Close()
+
+	users := []models.User{}
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, *u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read users: %w", err)
+	}
+	return users, nil
+}
+
+// Usage counts what a user has stored and how they have used the API in the last 30 days
+func (r *UserRepository) Usage(ctx context.Context, id string) (*models.UserUsage, error) {
+	u, err := r.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := models.UserUsage{
+		UserID:       u.ID,
+		LastSeenAt:   u.LastSeenAt,
+		QuotaResetAt: u.QuotaResetAt,
+		Quota:        u.Quota(time.Now()),
+	}
+	err = r.db.Pool.QueryRow(ctx, `
+		SELECT
+			(SELECT COUNT(*) FROM portfolios WHERE user_id = $1::uuid),
+			(SELECT COUNT(*) FROM positions p JOIN portfolios f ON f.id = p.portfolio_id
+				WHERE f.user_id = $1::uuid AND p.status = 'open'),
+			(SELECT COUNT(*) FROM watchlists WHERE user_id = $1::uuid),
+			(SELECT COUNT(*) FROM alerts WHERE user_id = $1::uuid),
+			(SELECT COUNT(*) FROM alert_triggers t JOIN alerts a ON a.id = t.alert_id
+				WHERE a.user_id = $1::uuid AND t.triggered_at > NOW() - INTERVAL '30 days'),
+			(SELECT COUNT(*) FROM alert_channels WHERE user_id = $1::uuid),
+			(SELECT COUNT(*) FROM api_keys
+				WHERE user_id = $1::uuid AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > NOW())),
+			(SELECT MAX(last_used_at) FROM api_keys WHERE user_id = $1::uuid)`,
+		id).Scan(&usage.Portfolios, &usage.OpenPositions, &usage.Watchlists, &usage.Alerts, &usage.AlertTriggers30d,
+		&usage.AlertChannels, &usage.ActiveAPIKeys, &usage.APIKeyLastUsedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user usage: %w", err)
+	}
+	return &usage, nil
+}
+
+// SetDisabled disables a user with a reason, or re-enables them when disabled is false.
+// Disabling twice keeps the first time and updates the reason.
+func (r *UserRepository) SetDisabled(ctx context.Context, id string, disabled bool, reason *string) (*models.User, error) {
+	u, err := scanUser(r.db.Pool.QueryRow(ctx, `
+		UPDATE users
+		SET disabled_at = CASE WHEN $2 THEN COALESCE(disabled_at, NOW()) END,
+			disabled_reason = CASE WHEN $2 THEN $3 END,
+			updated_at = NOW()
+		WHERE id = $1::uuid
+		RETURNING `+userColumns,
+		id, disabled, reason))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+	r.users.Invalidate(ctx, id)
+	return u, nil
+}
+
+// ResetQuota starts the user's quota afresh: usage before now no longer counts against it
+func (r *UserRepository) ResetQuota(ctx context.Context, id string, now time.Time) (*models.User, error) {
+	start, _ := models.QuotaPeriod(now)
+	u, err := scanUser(r.db.Pool.QueryRow(ctx, `
+		UPDATE users
+		SET quota_reset_at = $2, quota_period_start = $3::date, quota_pages = 0, quota_contracts = 0,
+			updated_at = NOW()
+		WHERE id = $1::uuid
+		RETURNING `+userColumns,
+		id, now, start.Format(time.DateOnly)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to reset user quota: %w", err)
+	}
+	r.users.Invalidate(ctx, id)
+	return u, nil
+}
+
+// SetTier moves a user to a usage tier, which takes effect on the current quota period
+func (r *UserRepository) SetTier(ctx context.Context, id, tier string) (*models.User, error) {
+	u, err := scanUser(r.db.Pool.QueryRow(ctx, `
+		UPDATE users SET tier = $2, updated_at = NOW()
+		WHERE id = $1::uuid
+		RETURNING `+userColumns,
+		id, tier))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to set user tier: %w", err)
+	}
+	r.users.Invalidate(ctx, id)
+	return u, nil
+}
+
+// RecordUsage adds a request's upstream usage to the user's day and to their current quota
+// period, starting the period's counts afresh when a new month has begun
+func (r *UserRepository) RecordUsage(ctx context.Context, id string, pages, contracts int64, now time.Time) error {
+	start, _ := models.QuotaPeriod(now)
+	_, err := r.db.Pool.Exec(ctx, `
+		WITH daily AS (
+			INSERT INTO usage_daily (user_id, day, pages, contracts, requests)
+			VALUES ($1::uuid, $2::date, $3, $4, 1)
+			ON CONFLICT (user_id, day) DO UPDATE
+			SET pages = usage_daily.pages + EXCLUDED.pages,
+				contracts = usage_daily.contracts + EXCLUDED.contracts,
+				requests = usage_daily.requests + 1
+		)
+		UPDATE users
+		SET quota_pages = CASE WHEN quota_period_start = $5::date THEN quota_pages ELSE 0 END + $3,
+			quota_contracts = CASE WHEN quota_period_start = $5::date THEN quota_contracts ELSE 0 END + $4,
+			quota_period_start = $5::date
+		WHERE id = $1::uuid`,
+		id, now.UTC().Format(time.DateOnly), pages, contracts, start.Format(time.DateOnly))
+	if err != nil {
+		return fmt.Errorf("failed to record usage: %w", err)
+	}
+	r.users.Forget(id)
+	return nil
+}
+
+// DailyUsage returns a user's upstream usage on each day since the given day, oldest first.
+// Days without usage are left out.
+func (r *UserRepository) DailyUsage(ctx context.Context, id string, since time.Time) ([]models.UsageDay, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT to_char(day, 'YYYY-MM-DD'), pages, contracts, requests
+		FROM usage_daily
+		WHERE user_id = $1::uuid AND day >= $2::date
+		ORDER BY day`,
+		id, since.UTC().Format(time.DateOnly))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily usage: %w", err)
+	}
+	defer rows.Close()
+
+	days := []models.UsageDay{}
+	for rows.Next() {
+		var d models.UsageDay
+		if err := rows.Scan(&d.Day, &d.Pages, &d.Contracts, &d.Requests); err != nil {
+			return nil, fmt.Errorf("failed to scan daily usage: %w", err)
+		}
+		days = append(days, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read daily usage: %w", err)
+	}
+	return days, nil
+}
+
+// ScheduleDeletion schedules the user's data to be purged at the given time. Asking again
+// keeps the original schedule.
+func (r *UserRepository) ScheduleDeletion(ctx context.Context, id string, now, at time.Time) (*models.User, error) {
+	u, err := scanUser(r.db.Pool.QueryRow(ctx, `
+		UPDATE users
+		SET deletion_requested_at = COALESCE(deletion_requested_at, $2),
+			deletion_scheduled_at = COALESCE(deletion_scheduled_at, $3),
+			updated_at = NOW()
+		WHERE id = $1::uuid AND deleted_at IS NULL
+		RETURNING `+userColumns,
+		id, now, at))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to schedule account deletion: %w", err)
+	}
+	r.users.Invalidate(ctx, id)
+	return u, nil
+}
+
+// CancelDeletion cancels the user's scheduled deletion, if it has not happened yet
+func (r *UserRepository) CancelDeletion(ctx context.Context, id string) (*models.User, error) {
+	u, err := scanUser(r.db.Pool.QueryRow(ctx, `
+		UPDATE users
+		SET deletion_requested_at = NULL, deletion_scheduled_at = NULL, updated_at = NOW()
+		WHERE id = $1::uuid AND deleted_at IS NULL
+		RETURNING `+userColumns,
+		id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel account deletion: %w", err)
+	}
+	r.users.Invalidate(ctx, id)
+	return u, nil
+}
+
+// ListDueDeletions returns the users whose scheduled deletion is due at now, earliest first
+func (r *UserRepository) ListDueDeletions(ctx context.Context, now time.Time) ([]models.User, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT `+userColumns+`
+		FROM users
+		WHERE deletion_scheduled_at <= $1 AND deleted_at IS NULL
+		ORDER BY deletion_scheduled_at`,
+		now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due account deletions: %w", err)
+	}
+	defer rows.Close()
+
+	users := []models.User{}
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, *u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read users: %w", err)
+	}
+	return users, nil
+}
+
+// SetMassiveKey stores the user's own Massive API key, already sealed, with the hint shown
+// in its place. Setting a key replaces the previous one.
+func (r *UserRepository) SetMassiveKey(ctx context.Context, id, ciphertext, hint string, now time.Time) (*models.User, error) {
+	u, err := scanUser(r.db.Pool.QueryRow(ctx, `
+		UPDATE users
+		SET massive_key_ciphertext = $2, massive_key_hint = $3, massive_key_set_at = $4, updated_at = NOW()
+		WHERE id = $1::uuid AND deleted_at IS NULL
+		RETURNING `+userColumns,
+		id, ciphertext, hint, now))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to set massive key: %w", err)
+	}
+	r.users.Invalidate(ctx, id)
+	return u, nil
+}
+
+// ClearMassiveKey removes the user's own Massive API key, so their requests go back to the
+// server's key
+func (r *UserRepository) ClearMassiveKey(ctx context.Context, id string) (*models.User, error) {
+	u, err := scanUser(r.db.Pool.QueryRow(ctx, `
+		UPDATE users
+		SET massive_key_ciphertext = NULL, massive_key_hint = NULL, massive_key_set_at = NULL, updated_at = NOW()
+		WHERE id = $1::uuid
+		RETURNING `+userColumns,
+		id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to clear massive key: %w", err)
+	}
+	r.users.Invalidate(ctx, id)
+	return u, nil
+}
+
+// RotateMassiveKeys re-encrypts the Massive API keys users stored with the box's primary
+// encryption key and returns how many it re-encrypted
+func (r *UserRepository) RotateMassiveKeys(ctx context.Context, box *secrets.Box) (int, error) {
+	return rotateSecrets(ctx, r.db.Pool, box, "Massive API key",
+		`SELECT id::text, id::text, massive_key_ciphertext FROM users WHERE massive_key_ciphertext IS NOT NULL`,
+		`UPDATE users SET massive_key_ciphertext = $2, updated_at = NOW() WHERE id = $1::uuid AND massive_key_ciphertext = $3`,
+		false)
+}
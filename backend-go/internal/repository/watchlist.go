@@ -0,0 +1,363 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// WatchlistRepository persists watchlists and their tickers
+type WatchlistRepository struct {
+	db *database.DB
+}
+
+// NewWatchlistRepository creates a new watchlist repository
+func NewWatchlistRepository(db *database.DB) *WatchlistRepository {
+	return &WatchlistRepository{db: db}
+}
+
+const watchlistColumns = `id, user_id::text, name, description, created_at, updated_at, deleted_at`
+
+func scanWatchlist(row pgx.Row) (*models.Watchlist, error) {
+	var w models.Watchlist
+	if err := row.Scan(&w.ID, &w.UserID, &w.Name, &w.Description, &w.CreatedAt, &w.UpdatedAt, &w.DeletedAt); err != nil {
+		return nil, err
+	}
+	w.Items = []models.WatchlistItem{}
+	return &w, nil
+}
+
+// Create inserts a watchlist with its tickers in order.
+// Returns ErrDuplicate when the owner already has a watchlist with the same name.
+func (r *WatchlistRepository) Create(ctx context.Context, w *models.Watchlist, tickers []string) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO watchlists (user_id, name, description)
+		VALUES ($1::uuid, $2, $3)
+		RETURNING id, created_at, updated_at`,
+		w.UserID, w.Name, w.Description,
+	).Scan(&w.ID, &w.CreatedAt, &w.UpdatedAt)
+	if isUniqueViolation(err) {
+		return ErrDuplicate
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create watchlist: %w", err)
+	}
+	if err := appendWatchlistItems(ctx, tx, w.ID, tickers); err != nil {
+		return err
+	}
+
+	return r.reload(ctx, tx, w)
+}
+
+// List returns a user's watchlists with their tickers, newest first. A nil user lists
+// watchlists without an owner.
+func (r *WatchlistRepository) List(ctx context.Context, userID *string) ([]models.Watchlist, error) {
+	return r.query(ctx, `
+		SELECT `+watchlistColumns+`
+		FROM watchlists
+		WHERE user_id IS NOT DISTINCT FROM $1::uuid AND deleted_at IS NULL
+		ORDER BY created_at DESC`,
+		userID)
+}
+
+// ListDeleted returns a user's deleted watchlists with their tickers, most recently deleted
+// first
+func (r *WatchlistRepository) ListDeleted(ctx context.Context, userID *string) ([]models.Watchlist, error) {
+	return r.query(ctx, `
+		SELECT `+watchlistColumns+`
+		FROM watchlists
+		WHERE user_id IS NOT DISTINCT FROM $1::uuid AND deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, id DESC`,
+		userID)
+}
+
+// query runs a watchlist SELECT and loads the tickers of every row
+func (r *WatchlistRepository) query(ctx context.Context, sql string, args ...any) ([]models.Watchlist, error) {
+	rows, err := r.db.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list watchlists: %w", err)
+	}
+	defer rows.Close()
+
+	watchlists := []models.Watchlist{}
+	for rows.Next() {
+		w, err := scanWatchlist(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan watchlist: %w", err)
+		}
+		watchlists = append(watchlists, *w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read watchlists: %w", err)
+	}
+
+	if err := loadWatchlistItems(ctx, r.db.Pool, watchlists); err != nil {
+		return nil, err
+	}
+	return watchlists, nil
+}
+
+// Get returns a single watchlist with its tickers
+func (r *WatchlistRepository) Get(ctx context.Context, userID *string, id int64) (*models.Watchlist, error) {
+	return getWatchlist(ctx, r.db.Pool, userID, id)
+}
+
+// Update saves a watchlist's name and description
+func (r *WatchlistRepository) Update(ctx context.Context, w *models.Watchlist) error {
+	err := r.db.Pool.QueryRow(ctx, `
+		UPDATE watchlists SET name = $2, description = $3, updated_at = NOW()
+		WHERE id = $1 AND user_id IS NOT DISTINCT FROM $4::uuid AND deleted_at IS NULL
+		RETURNING updated_at`,
+		w.ID, w.Name, w.Description, w.UserID).Scan(&w.UpdatedAt)
+	if isUniqueViolation(err) {
+		return ErrDuplicate
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update watchlist: %w", err)
+	}
+	return nil
+}
+
+// Delete moves one of a user's watchlists to their deleted watchlists, hiding it until it is
+// restored or purged
+func (r *WatchlistRepository) Delete(ctx context.Context, userID *string, id int64) error {
+	tag, err := r.db.Pool.Exec(ctx, `
+		UPDATE watchlists SET deleted_at = NOW()
+		WHERE id = $1 AND user_id IS NOT DISTINCT FROM $2::uuid AND deleted_at IS NULL`,
+		id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete watchlist: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Restore brings back one of a user's deleted watchlists with its tickers. Returns
+// ErrDuplicate when the user has since created a watchlist with the same name.
+func (r *WatchlistRepository) Restore(ctx context.Context, userID *string, id int64) (*models.Watchlist, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE watchlists SET deleted_at = NULL, updated_at = NOW()
+		WHERE id = $1 AND user_id IS NOT DISTINCT FROM $2::uuid AND deleted_at IS NOT NULL`,
+		id, userID)
+	if isUniqueViolation(err) {
+		return nil, ErrDuplicate
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore watchlist: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, ErrNotFound
+	}
+
+	w := &models.Watchlist{ID: id, UserID: userID}
+	if err := r.reload(ctx, tx, w); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Purge permanently removes one of a user's deleted watchlists and its tickers. Watchlists
+// that aren't deleted are not found.
+func (r *WatchlistRepository) Purge(ctx context.Context, userID *string, id int64) error {
+	tag, err := r.db.Pool.Exec(ctx, `
+		DELETE FROM watchlists
+		WHERE id = $1 AND user_id IS NOT DISTINCT FROM $2::uuid AND deleted_at IS NOT NULL`,
+		id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to purge watchlist: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// AddItems appends tickers to the end of a watchlist, skipping ones already on it, then
+// reloads it
+func (r *WatchlistRepository) AddItems(ctx context.Context, w *models.Watchlist, tickers []string) error {
+	return r.changeItems(ctx, w, func(tx pgx.Tx) error {
+		return appendWatchlistItems(ctx, tx, w.ID, tickers)
+	})
+}
+
+// ReplaceItems sets a watchlist's tickers to exactly the given list in that order, which
+// also reorders it; tickers kept on the list keep the time they were added
+func (r *WatchlistRepository) ReplaceItems(ctx context.Context, w *models.Watchlist, tickers []string) error {
+	return r.changeItems(ctx, w, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `
+			DELETE FROM watchlist_items WHERE watchlist_id = $1 AND NOT (ticker = ANY($2))`,
+			w.ID, tickers); err != nil {
+			return fmt.Errorf("failed to remove watchlist tickers: %w", err)
+		}
+		_, err := tx.Exec(ctx, `
+			INSERT INTO watchlist_items (watchlist_id, ticker, sort_order)
+			SELECT $1, t.ticker, t.ord - 1
+			FROM unnest($2::text[]) WITH ORDINALITY AS t(ticker, ord)
+			ON CONFLICT (watchlist_id, ticker) DO UPDATE SET sort_order = EXCLUDED.sort_order`,
+			w.ID, tickers)
+		if err != nil {
+			return fmt.Errorf("failed to save watchlist tickers: %w", err)
+		}
+		return nil
+	})
+}
+
+// RemoveItem removes a ticker from a watchlist, closing the gap it leaves in the order.
+// Returns ErrNotFound when the ticker is not on the list.
+func (r *WatchlistRepository) RemoveItem(ctx context.Context, w *models.Watchlist, ticker string) error {
+	return r.changeItems(ctx, w, func(tx pgx.Tx) error {
+		var order int
+		err := tx.QueryRow(ctx, `
+			DELETE FROM watchlist_items WHERE watchlist_id = $1 AND ticker = $2
+			RETURNING sort_order`,
+			w.ID, ticker).Scan(&order)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to remove watchlist ticker: %w", err)
+		}
+		if _, err := tx.Exec(ctx, `
+			UPDATE watchlist_items SET sort_order = sort_order - 1
+			WHERE watchlist_id = $1 AND sort_order > $2`,
+			w.ID, order); err != nil {
+			return fmt.Errorf("failed to reorder watchlist: %w", err)
+		}
+		return nil
+	})
+}
+
+// changeItems runs an edit of a watchlist's tickers in a transaction holding the
+// watchlist's row lock, bumps its updated_at and reloads it
+func (r *WatchlistRepository) changeItems(ctx context.Context, w *models.Watchlist, edit func(tx pgx.Tx) error) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE watchlists SET updated_at = NOW()
+		WHERE id = $1 AND user_id IS NOT DISTINCT FROM $2::uuid AND deleted_at IS NULL`,
+		w.ID, w.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to lock watchlist: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	if err := edit(tx); err != nil {
+		return err
+	}
+
+	return r.reload(ctx, tx, w)
+}
+
+// reload reads the watchlist back inside the transaction, then commits it
+func (r *WatchlistRepository) reload(ctx context.Context, tx pgx.Tx, w *models.Watchlist) error {
+	updated, err := getWatchlist(ctx, tx, w.UserID, w.ID)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit watchlist: %w", err)
+	}
+	*w = *updated
+	return nil
+}
+
+func getWatchlist(ctx context.Context, q querier, userID *string, id int64) (*models.Watchlist, error) {
+	w, err := scanWatchlist(q.QueryRow(ctx, `
+		SELECT `+watchlistColumns+` FROM watchlists
+		WHERE id = $1 AND user_id IS NOT DISTINCT FROM $2::uuid AND deleted_at IS NULL`,
+		id, userID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get watchlist: %w", err)
+	}
+
+	watchlists := []models.Watchlist{*w}
+	if err := loadWatchlistItems(ctx, q, watchlists); err != nil {
+		return nil, err
+	}
+	return &watchlists[0], nil
+}
+
+// appendWatchlistItems adds tickers after the last one on the list, skipping duplicates
+func appendWatchlistItems(ctx context.Context, q querier, watchlistID int64, tickers []string) error {
+	_, err := q.Exec(ctx, `
+		INSERT INTO watchlist_items (watchlist_id, ticker, sort_order)
+		SELECT $1, t.ticker,
+			(SELECT COALESCE(MAX(sort_order) + 1, 0) FROM watchlist_items WHERE watchlist_id = $1)
+				+ ROW_NUMBER() OVER (ORDER BY t.ord) - 1
+		FROM unnest($2::text[]) WITH ORDINALITY AS t(ticker, ord)
+		WHERE NOT EXISTS (
+			SELECT 1 FROM watchlist_items i WHERE i.watchlist_id = $1 AND i.ticker = t.ticker
+		)`,
+		watchlistID, tickers)
+	if err != nil {
+		return fmt.Errorf("failed to add watchlist tickers: %w", err)
+	}
+	return nil
+}
+
+// loadWatchlistItems fills in the tickers of the watchlists in list order
+func loadWatchlistItems(ctx context.Context, q querier, watchlists []models.Watchlist) error {
+	if len(watchlists) == 0 {
+		return nil
+	}
+	ids := make([]int64, len(watchlists))
+	index := make(map[int64]int, len(watchlists))
+	for i, w := range watchlists {
+		ids[i] = w.ID
+		index[w.ID] = i
+	}
+
+	rows, err := q.Query(ctx, `
+		SELECT watchlist_id, ticker, added_at
+		FROM watchlist_items
+		WHERE watchlist_id = ANY($1)
+		ORDER BY watchlist_id, sort_order`, ids)
+	if err != nil {
+		return fmt.Errorf("failed to list watchlist tickers: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var watchlistID int64
+		var item models.WatchlistItem
+		if err := rows.Scan(&watchlistID, &item.Ticker, &item.AddedAt); err != nil {
+			return fmt.Errorf("failed to scan watchlist ticker: %w", err)
+		}
+		item.AssetType = models.TickerAssetType(item.Ticker)
+		w := &watchlists[index[watchlistID]]
+		w.Items = append(w.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read watchlist tickers: %w", err)
+	}
+	return nil
+}
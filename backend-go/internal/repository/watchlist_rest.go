@@ -0,0 +1,352 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/database/rest"
+)
+
+// RESTWatchlistRepository persists watchlists through the Supabase REST API, for deployments
+// that cannot open a Postgres connection. The API has no transactions, so each change is a
+// few requests: a watchlist whose tickers fail to save is deleted again, and removing a
+// ticker leaves a gap in the order, which sorts the same.
+type RESTWatchlistRepository struct {
+	client *rest.Client
+}
+
+// NewRESTWatchlistRepository creates a watchlist repository backed by the REST API
+func NewRESTWatchlistRepository(client *rest.Client) *RESTWatchlistRepository {
+	return &RESTWatchlistRepository{client: client}
+}
+
+// watchlistSelect embeds each watchlist's tickers through the watchlist_items foreign key
+const watchlistSelect = "id,user_id,name,description,created_at,updated_at,deleted_at,watchlist_items(ticker,added_at,sort_order)"
+
+// watchlistRow is a watchlists row with its items as the REST API reads it
+type watchlistRow struct {
+	ID          int64      `json:"id"`
+	UserID      *string    `json:"user_id"`
+	Name        string     `json:"name"`
+	Description *string    `json:"description"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	DeletedAt   *time.Time `json:"deleted_at"`
+	Items       []struct {
+		Ticker    string    `json:"ticker"`
+		AddedAt   time.Time `json:"added_at"`
+		SortOrder int       `json:"sort_order"`
+	} `json:"watchlist_items"`
+}
+
+// nextSortOrder is the sort_order after the watchlist's last ticker
+func (row *watchlistRow) nextSortOrder() int {
+	next := 0
+	for _, item := range row.Items {
+		next = max(next, item.SortOrder+1)
+	}
+	return next
+}
+
+func (row watchlistRow) watchlist() models.Watchlist {
+	w := models.Watchlist{
+		ID:          row.ID,
+		UserID:      row.UserID,
+		Name:        row.Name,
+		Description: row.Description,
+		Items:       make([]models.WatchlistItem, len(row.Items)),
+		CreatedAt:   row.CreatedAt,
+		UpdatedAt:   row.UpdatedAt,
+		DeletedAt:   row.DeletedAt,
+	}
+	for i, item := range row.Items {
+		w.Items[i] = models.WatchlistItem{
+			Ticker:    item.Ticker,
+			AssetType: models.TickerAssetType(item.Ticker),
+			AddedAt:   item.AddedAt,
+		}
+	}
+	return w
+}
+
+// watchlistItemRow is a watchlist_items row as the REST API writes it
+type watchlistItemRow struct {
+	WatchlistID int64  `json:"watchlist_id"`
+	Ticker      string `json:"ticker"`
+	SortOrder   int    `json:"sort_order"`
+}
+
+// Create inserts a watchlist with its tickers in order.
+// Returns ErrDuplicate when the owner already has a watchlist with the same name.
+func (r *RESTWatchlistRepository) Create(ctx context.Context, w *models.Watchlist, tickers []string) error {
+	var rows []watchlistRow
+	err := r.client.Insert(ctx, "watchlists", map[string]any{
+		"user_id":     w.UserID,
+		"name":        w.Name,
+		"description": w.Description,
+	}, &rows)
+	if isUniqueViolation(err) {
+		return ErrDuplicate
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create watchlist: %w", err)
+	}
+	if len(rows) != 1 {
+		return fmt.Errorf("failed to create watchlist: %d rows returned", len(rows))
+	}
+	w.ID = rows[0].ID
+
+	if err := r.appendItems(ctx, w.ID, nil, tickers); err != nil {
+		query := url.Values{"id": {rest.Eq(w.ID)}}
+		if err := r.client.Delete(ctx, "watchlists", query, nil); err != nil {
+			log.Printf("[Watchlist] ⚠ Failed to delete watchlist %d whose tickers were not saved: %v", w.ID, err)
+		}
+		return err
+	}
+	return r.reload(ctx, w)
+}
+
+// List returns a user's watchlists with their tickers, newest first. A nil user lists
+// watchlists without an owner.
+func (r *RESTWatchlistRepository) List(ctx context.Context, userID *string) ([]models.Watchlist, error) {
+	return r.list(ctx, ownerFilter(url.Values{
+		"select":                {watchlistSelect},
+		"deleted_at":            {rest.IsNull()},
+		"order":                 {"created_at.desc"},
+		"watchlist_items.order": {"sort_order"},
+	}, userID))
+}
+
+// ListDeleted returns a user's deleted watchlists with their tickers, most recently deleted
+// first
+func (r *RESTWatchlistRepository) ListDeleted(ctx context.Context, userID *string) ([]models.Watchlist, error) {
+	return r.list(ctx, ownerFilter(url.Values{
+		"select":                {watchlistSelect},
+		"deleted_at":            {rest.NotNull()},
+		"order":                 {"deleted_at.desc,id.desc"},
+		"watchlist_items.order": {"sort_order"},
+	}, userID))
+}
+
+func (r *RESTWatchlistRepository) list(ctx context.Context, query url.Values) ([]models.Watchlist, error) {
+	var rows []watchlistRow
+	if err := r.client.Select(ctx, "watchlists", query, &rows); err != nil {
+		return nil, fmt.Errorf("failed to list watchlists: %w", err)
+	}
+	watchlists := make([]models.Watchlist, len(rows))
+	for i, row := range rows {
+		watchlists[i] = row.watchlist()
+	}
+	return watchlists, nil
+}
+
+// Get returns a single watchlist with its tickers
+func (r *RESTWatchlistRepository) Get(ctx context.Context, userID *string, id int64) (*models.Watchlist, error) {
+	row, err := r.get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	w := row.watchlist()
+	return &w, nil
+}
+
+func (r *RESTWatchlistRepository) get(ctx context.Context, userID *string, id int64) (*watchlistRow, error) {
+	query := ownerFilter(url.Values{
+		"id":                    {rest.Eq(id)},
+		"deleted_at":            {rest.IsNull()},
+		"select":                {watchlistSelect},
+		"watchlist_items.order": {"sort_order"},
+	}, userID)
+	var rows []watchlistRow
+	if err := r.client.Select(ctx, "watchlists", query, &rows); err != nil {
+		return nil, fmt.Errorf("failed to get watchlist: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, ErrNotFound
+	}
+	return &rows[0], nil
+}
+
+// Update saves a watchlist's name and description
+func (r *RESTWatchlistRepository) Update(ctx context.Context, w *models.Watchlist) error {
+	var rows []watchlistRow
+	query := ownerFilter(url.Values{
+		"id":         {rest.Eq(w.ID)},
+		"deleted_at": {rest.IsNull()},
+		"select":     {"id,updated_at"},
+	}, w.UserID)
+	err := r.client.Update(ctx, "watchlists", query, map[string]any{
+		"name":        w.Name,
+		"description": w.Description,
+		"updated_at":  time.Now().UTC(),
+	}, &rows)
+	if isUniqueViolation(err) {
+		return ErrDuplicate
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update watchlist: %w", err)
+	}
+	if len(rows) == 0 {
+		return ErrNotFound
+	}
+	w.UpdatedAt = rows[0].UpdatedAt
+	return nil
+}
+
+// Delete moves one of a user's watchlists to their deleted watchlists, hiding it until it is
+// restored or purged
+func (r *RESTWatchlistRepository) Delete(ctx context.Context, userID *string, id int64) error {
+	var rows []struct{}
+	query := ownerFilter(url.Values{"id": {rest.Eq(id)}, "deleted_at": {rest.IsNull()}, "select": {"id"}}, userID)
+	if err := r.client.Update(ctx, "watchlists", query, map[string]any{"deleted_at": time.Now().UTC()}, &rows); err != nil {
+		return fmt.Errorf("failed to delete watchlist: %w", err)
+	}
+	if len(rows) == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Restore brings back one of a user's deleted watchlists with its tickers. Returns
+// ErrDuplicate when the user has since created a watchlist with the same name.
+func (r *RESTWatchlistRepository) Restore(ctx context.Context, userID *string, id int64) (*models.Watchlist, error) {
+	var rows []struct{}
+	query := ownerFilter(url.Values{"id": {rest.Eq(id)}, "deleted_at": {rest.NotNull()}, "select": {"id"}}, userID)
+	err := r.client.Update(ctx, "watchlists", query, map[string]any{"deleted_at": nil, "updated_at": time.Now().UTC()}, &rows)
+	if isUniqueViolation(err) {
+		return nil, ErrDuplicate
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore watchlist: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, ErrNotFound
+	}
+	return r.Get(ctx, userID, id)
+}
+
+// Purge permanently removes one of a user's deleted watchlists and its tickers. Watchlists
+// that aren't deleted are not found.
+func (r *RESTWatchlistRepository) Purge(ctx context.Context, userID *string, id int64) error {
+	var rows []struct{}
+	query := ownerFilter(url.Values{"id": {rest.Eq(id)}, "deleted_at": {rest.NotNull()}, "select": {"id"}}, userID)
+	if err := r.client.Delete(ctx, "watchlists", query, &rows); err != nil {
+		return fmt.Errorf("failed to purge watchlist: %w", err)
+	}
+	if len(rows) == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// AddItems appends tickers to the end of a watchlist, skipping ones already on it, then
+// reloads it
+func (r *RESTWatchlistRepository) AddItems(ctx context.Context, w *models.Watchlist, tickers []string) error {
+	current, err := r.touch(ctx, w)
+	if err != nil {
+		return err
+	}
+	if err := r.appendItems(ctx, w.ID, current, tickers); err != nil {
+		return err
+	}
+	return r.reload(ctx, w)
+}
+
+// ReplaceItems sets a watchlist's tickers to exactly the given list in that order, which
+// also reorders it; tickers kept on the list keep the time they were added
+func (r *RESTWatchlistRepository) ReplaceItems(ctx context.Context, w *models.Watchlist, tickers []string) error {
+	if _, err := r.touch(ctx, w); err != nil {
+		return err
+	}
+	query := url.Values{"watchlist_id": {rest.Eq(w.ID)}}
+	if len(tickers) > 0 {
+		query.Set("ticker", rest.NotIn(tickers))
+	}
+	if err := r.client.Delete(ctx, "watchlist_items", query, nil); err != nil {
+		return fmt.Errorf("failed to remove watchlist tickers: %w", err)
+	}
+	if len(tickers) > 0 {
+		items := make([]watchlistItemRow, len(tickers))
+		for i, ticker := range tickers {
+			items[i] = watchlistItemRow{WatchlistID: w.ID, Ticker: ticker, SortOrder: i}
+		}
+		if err := r.client.Upsert(ctx, "watchlist_items", "watchlist_id,ticker", items, nil); err != nil {
+			return fmt.Errorf("failed to save watchlist tickers: %w", err)
+		}
+	}
+	return r.reload(ctx, w)
+}
+
+// RemoveItem removes a ticker from a watchlist.
+// Returns ErrNotFound when the ticker is not on the list.
+func (r *RESTWatchlistRepository) RemoveItem(ctx context.Context, w *models.Watchlist, ticker string) error {
+	if _, err := r.touch(ctx, w); err != nil {
+		return err
+	}
+	var rows []struct{}
+	query := url.Values{"watchlist_id": {rest.Eq(w.ID)}, "ticker": {rest.Eq(ticker)}, "select": {"ticker"}}
+	if err := r.client.Delete(ctx, "watchlist_items", query, &rows); err != nil {
+		return fmt.Errorf("failed to remove watchlist ticker: %w", err)
+	}
+	if len(rows) == 0 {
+		return ErrNotFound
+	}
+	return r.reload(ctx, w)
+}
+
+// touch bumps the updated_at of a watchlist that still belongs to its owner and returns the
+// watchlist as it was, with its tickers
+func (r *RESTWatchlistRepository) touch(ctx context.Context, w *models.Watchlist) (*watchlistRow, error) {
+	current, err := r.get(ctx, w.UserID, w.ID)
+	if err != nil {
+		return nil, err
+	}
+	query := url.Values{"id": {rest.Eq(w.ID)}}
+	if err := r.client.Update(ctx, "watchlists", query, map[string]any{"updated_at": time.Now().UTC()}, nil); err != nil {
+		return nil, fmt.Errorf("failed to update watchlist: %w", err)
+	}
+	return current, nil
+}
+
+// appendItems adds tickers after the last of current's, skipping those already on it; the
+// list is empty when current is nil
+func (r *RESTWatchlistRepository) appendItems(ctx context.Context, watchlistID int64, current *watchlistRow, tickers []string) error {
+	next := 0
+	seen := make(map[string]bool)
+	if current != nil {
+		next = current.nextSortOrder()
+		for _, item := range current.Items {
+			seen[item.Ticker] = true
+		}
+	}
+	var items []watchlistItemRow
+	for _, ticker := range tickers {
+		if seen[ticker] {
+			continue
+		}
+		seen[ticker] = true
+		items = append(items, watchlistItemRow{WatchlistID: watchlistID, Ticker: ticker, SortOrder: next})
+		next++
+	}
+	if len(items) == 0 {
+		return nil
+	}
+	if err := r.client.InsertMissing(ctx, "watchlist_items", "watchlist_id,ticker", items, nil); err != nil {
+		return fmt.Errorf("failed to add watchlist tickers: %w", err)
+	}
+	return nil
+}
+
+// reload reads the watchlist back after a change
+func (r *RESTWatchlistRepository) reload(ctx context.Context, w *models.Watchlist) error {
+	updated, err := r.Get(ctx, w.UserID, w.ID)
+	if err != nil {
+		return err
+	}
+	*w = *updated
+	return nil
+}
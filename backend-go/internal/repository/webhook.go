@@ -0,0 +1,314 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/secrets"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// maxWebhookFailures is how many deliveries in a row may exhaust their retries before the
+// webhook is disabled
+const maxWebhookFailures = 10
+
+// WebhookRepository persists webhooks and their delivery outbox. Signing secrets are
+// encrypted with box when it is set.
+type WebhookRepository struct {
+	db  *database.DB
+	box *secrets.Box
+}
+
+// NewWebhookRepository creates a new webhook repository
+func NewWebhookRepository(db *database.DB, box *secrets.Box) *WebhookRepository {
+	return &WebhookRepository{db: db, box: box}
+}
+
+// webhookSecretOwner binds a webhook's encrypted secret to its portfolio
+func webhookSecretOwner(portfolioID int64) string {
+	return fmt.Sprintf("webhook:%d", portfolioID)
+}
+
+const webhookColumns = `id, portfolio_id, url, description, events, active, failure_count,
+	last_delivery_at, last_status, created_at, updated_at`
+
+const deliveryColumns = `d.id, d.webhook_id, d.event, d.payload, d.status, d.attempts, d.next_attempt_at,
+	d.response_status, d.last_error, d.created_at, d.delivered_at`
+
+func scanWebhook(row pgx.Row) (*models.Webhook, error) {
+	var w models.Webhook
+	err := row.Scan(&w.ID, &w.PortfolioID, &w.URL, &w.Description, &w.Events, &w.Active, &w.FailureCount,
+		&w.LastDeliveryAt, &w.LastStatus, &w.CreatedAt, &w.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+func deliveryDest(d *models.WebhookDelivery) []any {
+	return []any{&d.ID, &d.WebhookID, &d.Event, &d.Payload, &d.Status, &d.Attempts, &d.NextAttemptAt,
+		&d.ResponseStatus, &d.LastError, &d.CreatedAt, &d.DeliveredAt}
+}
+
+// Create inserts a webhook with its signing secret
+func (r *WebhookRepository) Create(ctx context.Context, w *models.Webhook) error {
+	secret, err := sealSecret(r.box, w.Secret, webhookSecretOwner(w.PortfolioID))
+	if err != nil {
+		return err
+	}
+	err = r.db.Pool.QueryRow(ctx, `
+		INSERT INTO webhooks (portfolio_id, url, description, events, secret, active)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, failure_count, created_at, updated_at`,
+		w.PortfolioID, w.URL, w.Description, w.Events, secret, w.Active,
+	).Scan(&w.ID, &w.FailureCount, &w.CreatedAt, &w.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+	return nil
+}
+
+// List returns a portfolio's webhooks, oldest first, without their secrets
+func (r *WebhookRepository) List(ctx context.Context, portfolioID int64) ([]models.Webhook, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT `+webhookColumns+` FROM webhooks WHERE portfolio_id = $1 ORDER BY id`,
+		portfolioID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	webhooks := []models.Webhook{}
+	for rows.Next() {
+		w, err := scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook: %w", err)
+		}
+		webhooks = append(webhooks, *w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read webhooks: %w", err)
+	}
+	return webhooks, nil
+}
+
+// Get returns a single webhook of a portfolio without its secret
+func (r *WebhookRepository) Get(ctx context.Context, portfolioID, id int64) (*models.Webhook, error) {
+	w, err := scanWebhook(r.db.Pool.QueryRow(ctx, `
+		SELECT `+webhookColumns+` FROM webhooks WHERE portfolio_id = $1 AND id = $2`,
+		portfolioID, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+	return w, nil
+}
+
+// Update saves a webhook's URL, description, events and active flag. Reactivating a
+// webhook clears its failure count.
+func (r *WebhookRepository) Update(ctx context.Context, w *models.Webhook) error {
+	err := r.db.Pool.QueryRow(ctx, `
+		UPDATE webhooks
+		SET url = $3, description = $4, events = $5, active = $6,
+			failure_count = CASE WHEN $6 AND NOT active THEN 0 ELSE failure_count END,
+			updated_at = NOW()
+		WHERE portfolio_id = $1 AND id = $2
+		RETURNING failure_count, updated_at`,
+		w.PortfolioID, w.ID, w.URL, w.Description, w.Events, w.Active,
+	).Scan(&w.FailureCount, &w.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update webhook: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a webhook and its queued deliveries
+func (r *WebhookRepository) Delete(ctx context.Context, portfolioID, id int64) error {
+	tag, err := r.db.Pool.Exec(ctx, `DELETE FROM webhooks WHERE portfolio_id = $1 AND id = $2`, portfolioID, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Enqueue queues an event for one webhook regardless of its subscriptions, e.g. a ping
+func (r *WebhookRepository) Enqueue(ctx context.Context, w *models.Webhook, event string, data any) (*models.WebhookDelivery, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	var d models.WebhookDelivery
+	err = r.db.Pool.QueryRow(ctx, `
+		INSERT INTO webhook_deliveries AS d (webhook_id, event, payload)
+		VALUES ($1, $2, $3)
+		RETURNING `+deliveryColumns,
+		w.ID, event, json.RawMessage(payload)).Scan(deliveryDest(&d)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to queue webhook delivery: %w", err)
+	}
+	return &d, nil
+}
+
+// ListDeliveries returns a webhook's most recent deliveries, newest first
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, webhookID int64, limit int) ([]models.WebhookDelivery, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT `+deliveryColumns+`
+		FROM webhook_deliveries d
+		WHERE d.webhook_id = $1
+		ORDER BY d.created_at DESC, d.id DESC
+		LIMIT $2`,
+		webhookID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []models.WebhookDelivery{}
+	for rows.Next() {
+		var d models.WebhookDelivery
+		if err := rows.Scan(deliveryDest(&d)...); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// ClaimDue leases up to limit pending deliveries of active webhooks that are due, pushing
+// their next attempt out by lease so concurrent workers skip them while they are sent
+func (r *WebhookRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]models.PendingDelivery, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		WITH due AS (
+			SELECT d.id
+			FROM webhook_deliveries d
+			JOIN webhooks w ON w.id = d.webhook_id
+			WHERE d.status = 'pending' AND d.next_attempt_at <= NOW() AND w.active
+			ORDER BY d.next_attempt_at, d.id
+			LIMIT $1
+			FOR UPDATE OF d SKIP LOCKED
+		)
+		UPDATE webhook_deliveries d
+		SET next_attempt_at = NOW() + make_interval(secs => $2)
+		FROM due, webhooks w
+		WHERE d.id = due.id AND w.id = d.webhook_id
+		RETURNING `+deliveryColumns+`, w.portfolio_id, w.url, w.secret`,
+		limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	pending := []models.PendingDelivery{}
+	for rows.Next() {
+		var p models.PendingDelivery
+		dest := append(deliveryDest(&p.Delivery), &p.PortfolioID, &p.URL, &p.Secret)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		// A secret that doesn't open is left to be retried after the lease, when the
+		// missing encryption key may have been configured
+		if p.Secret, err = openSecret(r.box, p.Secret, webhookSecretOwner(p.PortfolioID)); err != nil {
+			log.Printf("[Webhook] ⚠ Failed to decrypt secret of webhook %d: %v", p.Delivery.WebhookID, err)
+			continue
+		}
+		pending = append(pending, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read webhook deliveries: %w", err)
+	}
+	return pending, nil
+}
+
+// RecordAttempt stores the outcome of sending a delivery. A delivered event resets the
+// webhook's failure count; a failed one is retried at retryAt, or marked failed when
+// retryAt is nil, which disables the webhook after too many failures in a row.
+func (r *WebhookRepository) RecordAttempt(ctx context.Context, d *models.WebhookDelivery, responseStatus *int, attemptErr error, retryAt *time.Time) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var lastError *string
+	status := models.DeliveryDelivered
+	if attemptErr != nil {
+		msg := attemptErr.Error()
+		lastError = &msg
+		status = models.DeliveryPending
+		if retryAt == nil {
+			status = models.DeliveryFailed
+		}
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = attempts + 1, response_status = $3, last_error = $4,
+			next_attempt_at = COALESCE($5, next_attempt_at),
+			delivered_at = CASE WHEN $2 = 'delivered' THEN NOW() END
+		WHERE id = $1`,
+		d.ID, status, responseStatus, lastError, retryAt)
+	if err != nil {
+		return fmt.Errorf("failed to record webhook delivery: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE webhooks
+		SET last_delivery_at = NOW(), last_status = $2,
+			failure_count = CASE $3 WHEN 'delivered' THEN 0 WHEN 'failed' THEN failure_count + 1 ELSE failure_count END,
+			active = active AND NOT ($3 = 'failed' AND failure_count + 1 >= $4)
+		WHERE id = $1`,
+		d.WebhookID, responseStatus, status, maxWebhookFailures)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook health: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit webhook delivery: %w", err)
+	}
+	d.Status = status
+	return nil
+}
+
+// enqueueWebhookEvent queues an event for every active webhook of the portfolio subscribed
+// to it, inside the caller's transaction so events are only sent for committed changes
+func enqueueWebhookEvent(ctx context.Context, q querier, portfolioID int64, event string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+	_, err = q.Exec(ctx, `
+		INSERT INTO webhook_deliveries (webhook_id, event, payload)
+		SELECT id, $2, $3 FROM webhooks WHERE portfolio_id = $1 AND active AND $2 = ANY(events)`,
+		portfolioID, event, json.RawMessage(payload))
+	if err != nil {
+		return fmt.Errorf("failed to queue %s webhooks: %w", event, err)
+	}
+	return nil
+}
+
+// RotateSecrets re-encrypts the webhooks' signing secrets with the primary encryption key,
+// including those stored in the clear, and returns how many it re-encrypted
+func (r *WebhookRepository) RotateSecrets(ctx context.Context) (int, error) {
+	return rotateSecrets(ctx, r.db.Pool, r.box, "webhook secret",
+		`SELECT id::text, 'webhook:' || portfolio_id, secret FROM webhooks`,
+		`UPDATE webhooks SET secret = $2, updated_at = NOW() WHERE id = $1::bigint AND secret = $3`,
+		true)
+}
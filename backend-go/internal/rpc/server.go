@@ -0,0 +1,181 @@
+// Package rpc serves Periscope's market data to internal services over gRPC, from the same
+// services the REST API uses. The messages and service are defined in
+// proto/periscope/v1/market_data.proto and generated into periscopev1.
+package rpc
+
+import (
+	"context"
+	"crypto/subtle"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/rpc/periscopev1"
+	"github.com/aaronbengochea/periscope/backend-go/internal/services"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/massive"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// maxTickers is the most contracts or stock tickers one request may ask for, as over REST
+const maxTickers = 250
+
+// MarketDataServer implements the MarketData service
+type MarketDataServer struct {
+	periscopev1.UnimplementedMarketDataServer
+	massiveClient *massive.Client
+	chains        *services.ChainService
+}
+
+// NewMarketDataServer creates a new market data server
+func NewMarketDataServer(massiveClient *massive.Client, chains *services.ChainService) *MarketDataServer {
+	return &MarketDataServer{
+		massiveClient: massiveClient,
+		chains:        chains,
+	}
+}
+
+// NewServer creates a gRPC server with the MarketData service, accepting only calls that carry
+// "authorization: Bearer <token>" metadata
+func NewServer(massiveClient *massive.Client, token string) *grpc.Server {
+	server := grpc.NewServer(grpc.UnaryInterceptor(requireToken(token)))
+	periscopev1.RegisterMarketDataServer(server, NewMarketDataServer(massiveClient, services.NewChainService(massiveClient)))
+	return server
+}
+
+// requireToken rejects calls without the shared token
+func requireToken(token string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		presented := ""
+		if values := md.Get("authorization"); len(values) > 0 {
+			if scheme, value, ok := strings.Cut(values[0], " "); ok && strings.EqualFold(scheme, "Bearer") {
+				presented = strings.TrimSpace(value)
+			}
+		}
+		if subtle.ConstantTimeCompare([]byte(presented), []byte(token)) != 1 {
+			return nil, status.Error(codes.Unauthenticated, "invalid or missing token")
+		}
+		return handler(ctx, req)
+	}
+}
+
+// GetChain returns an underlying's options chain
+func (s *MarketDataServer) GetChain(ctx context.Context, req *periscopev1.GetChainRequest) (*periscopev1.Chain, error) {
+	ticker := strings.ToUpper(strings.TrimSpace(req.GetTicker()))
+	if ticker == "" {
+		return nil, status.Error(codes.InvalidArgument, "ticker is required")
+	}
+	params := &massive.OptionsChainParams{StrikePrice: req.StrikePrice}
+	if req.GetExpirationDate() != "" {
+		if _, err := time.Parse("2006-01-02", req.GetExpirationDate()); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "expiration_date must be YYYY-MM-DD")
+		}
+		params.ExpirationDate = &req.ExpirationDate
+	}
+	switch req.GetContractType() {
+	case "":
+	case "call", "put":
+		params.ContractType = &req.ContractType
+	default:
+		return nil, status.Error(codes.InvalidArgument, "contract_type must be call or put")
+	}
+
+	snapshot, err := s.chains.GetSnapshot(ctx, ticker, params)
+	if err != nil {
+		log.Printf("[RPC] ✗ Failed to fetch options chain for %s: %v", ticker, err)
+		return nil, status.Error(codes.Unavailable, "failed to fetch options chain")
+	}
+	log.Printf("[RPC] ✓ Sending %d contracts for %s", len(snapshot.Contracts), ticker)
+
+	contracts := ContractMessages(snapshot.Contracts)
+	for _, contract := range contracts {
+		if contract.UnderlyingTicker == "" {
+			contract.UnderlyingTicker = ticker
+		}
+	}
+	return &periscopev1.Chain{
+		Ticker:          snapshot.Ticker,
+		UnderlyingPrice: snapshot.Spot,
+		Contracts:       contracts,
+		FetchedAt:       timestamppb.New(snapshot.FetchedAt),
+	}, nil
+}
+
+// GetContracts returns contract snapshots by OCC ticker
+func (s *MarketDataServer) GetContracts(ctx context.Context, req *periscopev1.GetContractsRequest) (*periscopev1.GetContractsResponse, error) {
+	if len(req.GetTickers()) == 0 || len(req.GetTickers()) > maxTickers {
+		return nil, status.Error(codes.InvalidArgument, "between 1 and 250 tickers are required")
+	}
+	contracts, err := s.massiveClient.GetContractDetails(ctx, req.GetTickers())
+	if err != nil {
+		log.Printf("[RPC] ✗ Failed to fetch contract details: %v", err)
+		return nil, status.Error(codes.Unavailable, "failed to fetch contract details")
+	}
+	return &periscopev1.GetContractsResponse{Contracts: ContractMessages(contracts)}, nil
+}
+
+// GetPrices returns the latest stock prices
+func (s *MarketDataServer) GetPrices(ctx context.Context, req *periscopev1.GetPricesRequest) (*periscopev1.GetPricesResponse, error) {
+	if len(req.GetTickers()) == 0 || len(req.GetTickers()) > maxTickers {
+		return nil, status.Error(codes.InvalidArgument, "between 1 and 250 tickers are required")
+	}
+	prices, err := s.massiveClient.GetStockPrices(ctx, req.GetTickers())
+	if err != nil {
+		log.Printf("[RPC] ✗ Failed to fetch stock prices: %v", err)
+		return nil, status.Error(codes.Unavailable, "failed to fetch stock prices")
+	}
+	return &periscopev1.GetPricesResponse{Prices: prices}, nil
+}
+
+// ContractMessages converts contracts to their protobuf messages
+func ContractMessages(contracts []models.OptionContract) []*periscopev1.Contract {
+	out := make([]*periscopev1.Contract, len(contracts))
+	for i := range contracts {
+		out[i] = contractMessage(&contracts[i])
+	}
+	return out
+}
+
+func contractMessage(c *models.OptionContract) *periscopev1.Contract {
+	msg := &periscopev1.Contract{
+		ImpliedVolatility: c.ImpliedVol,
+		OpenInterest:      c.OpenInterest,
+	}
+	if d := c.Details; d != nil {
+		msg.Ticker = deref(d.Ticker)
+		msg.ContractType = deref(d.ContractType)
+		msg.StrikePrice = deref(d.StrikePrice)
+		msg.ExpirationDate = deref(d.ExpirationDate)
+	}
+	if u := c.UnderlyingAsset; u != nil {
+		msg.UnderlyingTicker = deref(u.Ticker)
+		msg.UnderlyingPrice = u.Price
+	}
+	if q := c.LastQuote; q != nil {
+		msg.Bid, msg.Ask = q.Bid, q.Ask
+	}
+	if t := c.LastTrade; t != nil {
+		msg.LastPrice = t.Price
+	}
+	if day := c.Day; day != nil {
+		msg.Volume = day.Volume
+	}
+	if g := c.Greeks; g != nil {
+		msg.Greeks = &periscopev1.Greeks{Delta: g.Delta, Gamma: g.Gamma, Theta: g.Theta, Vega: g.Vega, Rho: g.Rho}
+	}
+	return msg
+}
+
+// deref returns the value a pointer points to, or the zero value for nil
+func deref[T any](p *T) T {
+	if p == nil {
+		var zero T
+		return zero
+	}
+	return *p
+}
@@ -0,0 +1,174 @@
+// Package secrets encrypts credentials users hand to Periscope, such as their own vendor API
+// keys and webhook signing secrets, so they are not stored in the clear
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// KeySize is the length in bytes of the keys a Box encrypts with (AES-256)
+const KeySize = 32
+
+// ErrDecrypt is returned for ciphertexts that are malformed, tampered with, sealed for a
+// different owner or sealed with a key the box doesn't hold
+var ErrDecrypt = errors.New("failed to decrypt secret")
+
+// envelopePrefix starts every ciphertext sealed with envelope encryption, followed by the ID
+// of the key that wrapped its data key, the wrapped data key and the sealed secret:
+// "env1:KEY-ID:WRAPPED-KEY:SEALED". Ciphertexts without it were sealed directly with a key
+// before envelope encryption.
+const envelopePrefix = "env1:"
+
+// Box seals and opens secrets with envelope encryption: each secret is sealed with its own
+// random AES-256-GCM data key, which is stored alongside it wrapped by a key-encryption key.
+// Secrets are sealed with the primary key; the others only open what they sealed before a
+// rotation, until Rotate re-seals it. Each secret is bound to its owner, so a ciphertext
+// copied to another owner's row does not open.
+type Box struct {
+	primary KeyWrapper
+	keys    map[string]KeyWrapper
+}
+
+// NewBox creates a box sealing with primary and also opening secrets sealed with previous
+// keys
+func NewBox(primary KeyWrapper, previous ...KeyWrapper) (*Box, error) {
+	b := &Box{primary: primary, keys: map[string]KeyWrapper{}}
+	for _, key := range append([]KeyWrapper{primary}, previous...) {
+		if _, ok := b.keys[key.ID()]; ok {
+			return nil, fmt.Errorf("encryption key %q is given twice", key.ID())
+		}
+		b.keys[key.ID()] = key
+	}
+	return b, nil
+}
+
+// Seal encrypts a secret for its owner and returns it in the envelope format. Sealing the
+// same secret twice yields different ciphertexts.
+func (b *Box) Seal(secret, owner string) (string, error) {
+	dataKey := make([]byte, KeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", fmt.Errorf("failed to generate data key: %w", err)
+	}
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := seal(aead, []byte(secret), []byte(owner))
+	if err != nil {
+		return "", err
+	}
+	wrapped, err := b.primary.WrapKey(dataKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to wrap data key: %w", err)
+	}
+	return envelopePrefix + b.primary.ID() + ":" + base64.StdEncoding.EncodeToString(wrapped) + ":" +
+		base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Open decrypts a ciphertext sealed for owner
+func (b *Box) Open(ciphertext, owner string) (string, error) {
+	rest, ok := strings.CutPrefix(ciphertext, envelopePrefix)
+	if !ok {
+		return b.openDirect(ciphertext, owner)
+	}
+	parts := strings.Split(rest, ":")
+	if len(parts) != 3 {
+		return "", ErrDecrypt
+	}
+	key, ok := b.keys[parts[0]]
+	if !ok {
+		return "", fmt.Errorf("%w: sealed with unknown key %q", ErrDecrypt, parts[0])
+	}
+	wrapped, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", ErrDecrypt
+	}
+	sealed, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", ErrDecrypt
+	}
+	dataKey, err := key.UnwrapKey(wrapped)
+	if err != nil {
+		return "", ErrDecrypt
+	}
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return "", ErrDecrypt
+	}
+	secret, err := open(aead, sealed, []byte(owner))
+	if err != nil {
+		return "", ErrDecrypt
+	}
+	return string(secret), nil
+}
+
+// openDirect opens a ciphertext sealed before envelope encryption, directly with whichever
+// local key it was sealed with
+func (b *Box) openDirect(ciphertext, owner string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", ErrDecrypt
+	}
+	for _, key := range b.keys {
+		local, ok := key.(*LocalKey)
+		if !ok {
+			continue
+		}
+		if secret, err := open(local.aead, sealed, []byte(owner)); err == nil {
+			return string(secret), nil
+		}
+	}
+	return "", ErrDecrypt
+}
+
+// NeedsRotation reports whether a ciphertext was sealed other than with the primary key
+func (b *Box) NeedsRotation(ciphertext string) bool {
+	return !strings.HasPrefix(ciphertext, envelopePrefix+b.primary.ID()+":")
+}
+
+// Rotate re-seals a ciphertext with the primary key
+func (b *Box) Rotate(ciphertext, owner string) (string, error) {
+	secret, err := b.Open(ciphertext, owner)
+	if err != nil {
+		return "", err
+	}
+	return b.Seal(secret, owner)
+}
+
+// Sealed reports whether a stored value is a ciphertext in the envelope format, rather than
+// a secret stored in the clear before it was encrypted
+func Sealed(value string) bool {
+	return strings.HasPrefix(value, envelopePrefix)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts plaintext with a random nonce, which it puts first
+func seal(aead cipher.AEAD, plaintext, additional []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, additional), nil
+}
+
+// open decrypts what seal encrypted
+func open(aead cipher.AEAD, sealed, additional []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, ErrDecrypt
+	}
+	nonce, sealed := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, sealed, additional)
+}
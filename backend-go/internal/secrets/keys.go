@@ -0,0 +1,76 @@
+package secrets
+
+import (
+	"crypto/cipher"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// KeyWrapper encrypts the data keys of a Box with a key-encryption key. LocalKey holds the
+// key in the process; a KMS-backed wrapper keeps it in the KMS and makes a call per seal and
+// open instead.
+type KeyWrapper interface {
+	// ID names the key in the ciphertexts it wrapped; it must not contain ':'
+	ID() string
+	WrapKey(dataKey []byte) ([]byte, error)
+	UnwrapKey(wrapped []byte) ([]byte, error)
+}
+
+// LocalKey is a key-encryption key held in memory, wrapping data keys with AES-256-GCM
+type LocalKey struct {
+	id   string
+	aead cipher.AEAD
+}
+
+// NewLocalKey creates a key-encryption key from a base64-encoded 32-byte key
+func NewLocalKey(id, encodedKey string) (*LocalKey, error) {
+	if id == "" || strings.Contains(id, ":") {
+		return nil, fmt.Errorf("encryption key ID %q must be non-empty and without ':'", id)
+	}
+	key, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return nil, fmt.Errorf("encryption key %q is not base64: %w", id, err)
+	}
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("encryption key %q must be %d bytes, got %d", id, KeySize, len(key))
+	}
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	return &LocalKey{id: id, aead: aead}, nil
+}
+
+// ID implements KeyWrapper
+func (k *LocalKey) ID() string {
+	return k.id
+}
+
+// WrapKey implements KeyWrapper, binding the wrapped key to this key's ID
+func (k *LocalKey) WrapKey(dataKey []byte) ([]byte, error) {
+	return seal(k.aead, dataKey, []byte(k.id))
+}
+
+// UnwrapKey implements KeyWrapper
+func (k *LocalKey) UnwrapKey(wrapped []byte) ([]byte, error) {
+	return open(k.aead, wrapped, []byte(k.id))
+}
+
+// ParseKeys reads keys given as ID:base64-key pairs, as listed in SECRETS_PREVIOUS_KEYS
+func ParseKeys(list []string) ([]KeyWrapper, error) {
+	keys := make([]KeyWrapper, 0, len(list))
+	for i, entry := range list {
+		id, encoded, ok := strings.Cut(entry, ":")
+		if !ok {
+			// The entry is not echoed, as it may be a bare key
+			return nil, fmt.Errorf("encryption key %d must be given as ID:base64-key", i+1)
+		}
+		key, err := NewLocalKey(id, encoded)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
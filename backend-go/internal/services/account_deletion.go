@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/auth"
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+)
+
+// AccountDeletionService carries out account deletions once their grace period is over
+type AccountDeletionService struct {
+	users    *repository.UserRepository
+	accounts *repository.AccountRepository
+	admin    *auth.AdminClient
+}
+
+// NewAccountDeletionService creates a new account deletion service. admin deletes the
+// Supabase accounts of deleted users; when nil, only Periscope's data is purged.
+func NewAccountDeletionService(users *repository.UserRepository, accounts *repository.AccountRepository, admin *auth.AdminClient) *AccountDeletionService {
+	return &AccountDeletionService{users: users, accounts: accounts, admin: admin}
+}
+
+// PurgeDue deletes every account whose deletion is due and returns how many were deleted.
+// The Supabase account goes first, so a user whose sign-in cannot be removed yet keeps their
+// data until the next run rather than being left able to sign in to an empty account.
+func (s *AccountDeletionService) PurgeDue(ctx context.Context, now time.Time) (int, error) {
+	due, err := s.users.ListDueDeletions(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, u := range due {
+		if s.admin != nil && u.Provider == models.UserProviderSupabase {
+			if err := s.admin.DeleteUser(ctx, u.ID); err != nil {
+				log.Printf("[AccountDeletion] ⚠ Failed to delete Supabase account of user %s: %v", u.ID, err)
+				continue
+			}
+		}
+		if err := s.accounts.Purge(ctx, u.ID, now); err != nil {
+			log.Printf("[AccountDeletion] ⚠ Failed to purge user %s: %v", u.ID, err)
+			continue
+		}
+		log.Printf("[AccountDeletion] ✓ Deleted user %s", u.ID)
+		purged++
+	}
+	return purged, nil
+}
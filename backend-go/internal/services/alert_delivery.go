@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/notify"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+)
+
+// AlertDeliveryService sends queued alert notifications through the notifier of each
+// channel's kind, retrying failed deliveries with the same backoff as webhooks. Notifiers
+// with a per-user limit hold deliveries back until the user's window has room.
+type AlertDeliveryService struct {
+	channels  *repository.AlertChannelRepository
+	notifiers map[string]notify.Notifier // by channel kind
+}
+
+// NewAlertDeliveryService creates a new alert delivery service
+func NewAlertDeliveryService(channels *repository.AlertChannelRepository, notifiers map[string]notify.Notifier) *AlertDeliveryService {
+	return &AlertDeliveryService{
+		channels:  channels,
+		notifiers: notifiers,
+	}
+}
+
+// DeliverDue sends every delivery that is due, one batch at a time, and returns how many
+// were delivered
+func (s *AlertDeliveryService) DeliverDue(ctx context.Context) (int, error) {
+	delivered := 0
+	for {
+		pending, err := s.channels.ClaimDue(ctx, webhookBatchSize, webhookLease)
+		if err != nil {
+			return delivered, err
+		}
+		for i := range pending {
+			ok, err := s.deliver(ctx, &pending[i])
+			if err != nil {
+				return delivered, err
+			}
+			if ok {
+				delivered++
+			}
+		}
+		if len(pending) < webhookBatchSize {
+			return delivered, nil
+		}
+	}
+}
+
+// deliver sends one delivery and records the outcome, reporting whether it was accepted.
+// Deliveries to a channel kind without a notifier fail without retries.
+func (s *AlertDeliveryService) deliver(ctx context.Context, p *models.PendingAlertDelivery) (bool, error) {
+	d := &p.Delivery
+	var status *int
+	var sendErr error
+	retryable := true
+	if notifier, ok := s.notifiers[p.Channel.Kind]; ok {
+		if limiter, ok := notifier.(notify.Limiter); ok {
+			if held, err := s.holdOverLimit(ctx, p, limiter); held || err != nil {
+				return false, err
+			}
+		}
+		status, sendErr = notifier.Send(ctx, p)
+	} else {
+		sendErr = fmt.Errorf("no notifier for %s channels", p.Channel.Kind)
+		retryable = false
+	}
+
+	var retryAt *time.Time
+	if sendErr != nil && retryable && d.Attempts+1 < webhookMaxAttempts {
+		next := time.Now().Add(Backoff(d.Attempts + 1))
+		retryAt = &next
+	}
+	if err := s.channels.RecordAttempt(ctx, d, status, sendErr, retryAt); err != nil {
+		return false, err
+	}
+
+	switch {
+	case sendErr == nil:
+		log.Printf("[AlertDeliveryService] ✓ Delivered %s %d to %s channel %d", d.Event, d.ID, p.Channel.Kind, d.ChannelID)
+	case retryAt != nil:
+		log.Printf("[AlertDeliveryService] ⚠ Delivery %d to %s channel %d failed (attempt %d), retrying at %s: %v",
+			d.ID, p.Channel.Kind, d.ChannelID, d.Attempts+1, retryAt.Format(time.RFC3339), sendErr)
+	default:
+		log.Printf("[AlertDeliveryService] ✗ Delivery %d to %s channel %d failed after %d attempts: %v",
+			d.ID, p.Channel.Kind, d.ChannelID, d.Attempts+1, sendErr)
+	}
+	return sendErr == nil, nil
+}
+
+// holdOverLimit postpones a delivery whose user has already received the notifier's limit
+// of notifications over its kind of channel within the window, until the earliest of them
+// leaves the window
+func (s *AlertDeliveryService) holdOverLimit(ctx context.Context, p *models.PendingAlertDelivery, limiter notify.Limiter) (bool, error) {
+	limit, window := limiter.Limit()
+	if limit <= 0 {
+		return false, nil
+	}
+	count, earliest, err := s.channels.RecentDeliveries(ctx, p.Channel.UserID, p.Channel.Kind, time.Now().Add(-window))
+	if err != nil {
+		return false, err
+	}
+	if count < limit || earliest == nil {
+		return false, nil
+	}
+
+	until := earliest.Add(window)
+	if err := s.channels.Postpone(ctx, &p.Delivery, until); err != nil {
+		return false, err
+	}
+	log.Printf("[AlertDeliveryService] ⚠ Holding delivery %d to %s channel %d until %s: %d sent in the last %s",
+		p.Delivery.ID, p.Channel.Kind, p.Delivery.ChannelID, until.Format(time.RFC3339), count, window)
+	return true, nil
+}
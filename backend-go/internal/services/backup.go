@@ -0,0 +1,126 @@
+package services
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/storage"
+)
+
+// backupManifestFile is written last in each backup, so a backup without it is incomplete
+const backupManifestFile = "manifest.json"
+
+// BackupManifest describes a backup: when it was taken, the schema its columns match and
+// the file each table was written to
+type BackupManifest struct {
+	CreatedAt     time.Time     `json:"created_at"`
+	SchemaVersion string        `json:"schema_version"`
+	Tables        []BackupTable `json:"tables"`
+}
+
+// BackupTable is a table's file in a backup, gzip-compressed CSV with a header line
+type BackupTable struct {
+	Name  string `json:"name"`
+	File  string `json:"file"`
+	Rows  int64  `json:"rows"`
+	Bytes int64  `json:"bytes"`
+}
+
+// BackupService dumps Periscope's tables to object storage, independently of the backups
+// Supabase takes of the whole database
+type BackupService struct {
+	backups *repository.BackupRepository
+	store   storage.Store
+	prefix  string
+}
+
+// NewBackupService creates a new backup service writing backups under prefix in store
+func NewBackupService(backups *repository.BackupRepository, store storage.Store, prefix string) *BackupService {
+	return &BackupService{backups: backups, store: store, prefix: strings.Trim(prefix, "/")}
+}
+
+// Run takes a backup of every table in repository.BackupTables, written to a folder named
+// after now, and returns its manifest. The tables are dumped to temporary files first, so
+// the database snapshot isn't held open while they upload.
+func (s *BackupService) Run(ctx context.Context, now time.Time) (*BackupManifest, error) {
+	dir, err := os.MkdirTemp("", "periscope-backup-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create backup directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	version, rows, err := s.backups.Dump(ctx, repository.BackupTables, func(table string) (io.WriteCloser, error) {
+		f, err := os.Create(filepath.Join(dir, table+".csv.gz"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create backup file for %s: %w", table, err)
+		}
+		return &gzipFile{Writer: gzip.NewWriter(f), file: f}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	folder := path.Join(s.prefix, now.UTC().Format("2006-01-02T150405Z"))
+	manifest := &BackupManifest{CreatedAt: now.UTC(), SchemaVersion: version}
+	for _, table := range repository.BackupTables {
+		name := table + ".csv.gz"
+		size, err := s.upload(ctx, path.Join(folder, name), filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		manifest.Tables = append(manifest.Tables, BackupTable{Name: table, File: name, Rows: rows[table], Bytes: size})
+	}
+
+	encoded, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode backup manifest: %w", err)
+	}
+	err = s.store.Put(ctx, path.Join(folder, backupManifestFile), strings.NewReader(string(encoded)),
+		int64(len(encoded)), "application/json")
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[Backup] ✓ Backed up %d tables to %s", len(manifest.Tables), folder)
+	return manifest, nil
+}
+
+// upload copies a local backup file to the store and returns its size
+func (s *BackupService) upload(ctx context.Context, objectPath, file string) (int64, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open backup file: %w", err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return 0, fmt.Errorf("failed to open backup file: %w", err)
+	}
+	if err := s.store.Put(ctx, objectPath, f, info.Size(), "application/gzip"); err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+// gzipFile compresses into a file, closing both when closed
+type gzipFile struct {
+	*gzip.Writer
+	file *os.File
+}
+
+func (g *gzipFile) Close() error {
+	err := g.Writer.Close()
+	if closeErr := g.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
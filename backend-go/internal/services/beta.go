@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/analytics"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/massive"
+)
+
+// BenchmarkTicker is the index proxy positions are beta-weighted against
+const BenchmarkTicker = "SPY"
+
+// betaLookbackDays is the calendar window of daily bars used to estimate beta (~1 year)
+const betaLookbackDays = 365
+
+// cachedBeta is a beta and volatility estimate and the market date it was computed on
+type cachedBeta struct {
+	beta *float64
+	vol  *float64 // annualized close-to-close volatility
+	date string
+}
+
+// BetaService estimates underlying betas against the benchmark, and their historical
+// volatility, from daily bars. Estimates are cached for the rest of the market day.
+type BetaService struct {
+	massiveClient *massive.Client
+
+	mu    sync.Mutex
+	cache map[string]cachedBeta
+}
+
+// NewBetaService creates a new beta service
+func NewBetaService(massiveClient *massive.Client) *BetaService {
+	return &BetaService{
+		massiveClient: massiveClient,
+		cache:         make(map[string]cachedBeta),
+	}
+}
+
+// Betas returns the beta of each ticker against the benchmark. Tickers whose history
+// cannot be fetched or is too short are omitted; the benchmark itself is always 1.
+func (s *BetaService) Betas(ctx context.Context, tickers []string) map[string]float64 {
+	betas := make(map[string]float64, len(tickers))
+
+	var others []string
+	for _, ticker := range tickers {
+		if ticker == BenchmarkTicker {
+			betas[ticker] = 1
+		} else {
+			others = append(others, ticker)
+		}
+	}
+
+	for ticker, est := range s.estimates(ctx, others) {
+		if est.beta != nil {
+			betas[ticker] = *est.beta
+		}
+	}
+	return betas
+}
+
+// Volatilities returns the annualized historical volatility of each ticker over the
+// beta lookback window. Tickers whose history cannot be fetched are omitted.
+func (s *BetaService) Volatilities(ctx context.Context, tickers []string) map[string]float64 {
+	vols := make(map[string]float64, len(tickers))
+	for ticker, est := range s.estimates(ctx, tickers) {
+		if est.vol != nil {
+			vols[ticker] = *est.vol
+		}
+	}
+	return vols
+}
+
+// estimates returns today's cached estimates for the tickers, computing missing ones
+// from one year of daily bars aligned with the benchmark
+func (s *BetaService) estimates(ctx context.Context, tickers []string) map[string]cachedBeta {
+	now := time.Now()
+	today := analytics.MarketDate(now).Format("2006-01-02")
+	result := make(map[string]cachedBeta, len(tickers))
+
+	var missing []string
+	s.mu.Lock()
+	for _, ticker := range tickers {
+		if cached, ok := s.cache[ticker]; ok && cached.date == today {
+			result[ticker] = cached
+			continue
+		}
+		missing = append(missing, ticker)
+	}
+	s.mu.Unlock()
+
+	if len(missing) == 0 {
+		return result
+	}
+
+	from := now.AddDate(0, 0, -betaLookbackDays)
+	benchmarkBars, err := s.massiveClient.GetDailyBars(ctx, BenchmarkTicker, from, now)
+	if err != nil {
+		log.Printf("[BetaService] ⚠ Failed to fetch %s bars: %v", BenchmarkTicker, err)
+		return result
+	}
+	benchmark := make(map[string]float64, len(benchmarkBars))
+	for _, b := range benchmarkBars {
+		benchmark[barDate(b)] = b.Close
+	}
+
+	for _, ticker := range missing {
+		bars := benchmarkBars
+		if ticker != BenchmarkTicker {
+			if bars, err = s.massiveClient.GetDailyBars(ctx, ticker, from, now); err != nil {
+				// Not cached so the next request retries
+				log.Printf("[BetaService] ⚠ Failed to fetch %s bars: %v", ticker, err)
+				continue
+			}
+		}
+
+		// Align on the dates both series traded
+		var closes, asset, bench []float64
+		for _, b := range bars {
+			closes = append(closes, b.Close)
+			if benchClose, ok := benchmark[barDate(b)]; ok {
+				asset = append(asset, b.Close)
+				bench = append(bench, benchClose)
+			}
+		}
+
+		est := cachedBeta{
+			beta: analytics.Beta(asset, bench),
+			vol:  analytics.HistoricalVolatility(closes),
+			date: today,
+		}
+		if est.beta == nil {
+			log.Printf("[BetaService] ⚠ Not enough history to estimate beta for %s", ticker)
+		}
+
+		s.mu.Lock()
+		s.cache[ticker] = est
+		s.mu.Unlock()
+		result[ticker] = est
+	}
+
+	return result
+}
+
+// barDate returns the market date of a daily bar as YYYY-MM-DD
+func barDate(b massive.Bar) string {
+	return analytics.MarketDate(b.Time()).Format("2006-01-02")
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/massive"
+)
+
+// defaultChainLimit is the Massive API maximum page size; pagination fetches the rest
+const defaultChainLimit = 250
+
+// ChainSnapshot is a full options chain paired with the underlying price it was priced against
+type ChainSnapshot struct {
+	Ticker    string
+	Spot      float64
+	Contracts []models.OptionContract
+	FetchedAt time.Time
+}
+
+// ChainService fetches option chains for analytics that need the underlying price
+type ChainService struct {
+	massiveClient *massive.Client
+}
+
+// NewChainService creates a new chain service
+func NewChainService(massiveClient *massive.Client) *ChainService {
+	return &ChainService{
+		massiveClient: massiveClient,
+	}
+}
+
+// GetSnapshot fetches the chain and resolves the underlying price, preferring the stock
+// snapshot and falling back to the underlying price reported on the contracts
+func (s *ChainService) GetSnapshot(ctx context.Context, ticker string, params *massive.OptionsChainParams) (*ChainSnapshot, error) {
+	if params == nil {
+		params = &massive.OptionsChainParams{}
+	}
+	if params.Limit == nil {
+		limit := defaultChainLimit
+		params.Limit = &limit
+	}
+
+	response, err := s.massiveClient.GetOptionsChain(ctx, ticker, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch options chain: %w", err)
+	}
+
+	snapshot := &ChainSnapshot{
+		Ticker:    ticker,
+		Contracts: response.Results,
+		FetchedAt: time.Now(),
+	}
+
+	stockPrice, err := s.massiveClient.GetStockPrice(ctx, ticker)
+	if err != nil {
+		log.Printf("[ChainService] ⚠ Stock price fetch failed, falling back to contract data: %v", err)
+	}
+	if stockPrice != nil {
+		snapshot.Spot = *stockPrice
+	} else {
+		for i := range response.Results {
+			ua := response.Results[i].UnderlyingAsset
+			if ua != nil && ua.Price != nil && *ua.Price > 0 {
+				snapshot.Spot = *ua.Price
+				break
+			}
+		}
+	}
+
+	if snapshot.Spot <= 0 {
+		return nil, fmt.Errorf("no underlying price available for %s", ticker)
+	}
+
+	return snapshot, nil
+}
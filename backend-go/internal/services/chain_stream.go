@@ -0,0 +1,247 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/massive"
+)
+
+// chainStreamBuffer is how many updates a subscriber may fall behind by
+const chainStreamBuffer = 8
+
+// ChainUpdate is what a chain subscriber receives after a fetch: the whole chain the first
+// time, then only the contracts whose quotes or greeks changed and the tickers of those that
+// left the chain. Err is set instead when the fetch failed.
+type ChainUpdate struct {
+	Snapshot        bool                    `json:"-"`
+	Ticker          string                  `json:"ticker"`
+	UnderlyingPrice float64                 `json:"underlying_price"`
+	Contracts       []models.OptionContract `json:"contracts"`
+	Removed         []string                `json:"removed,omitempty"`
+	FetchedAt       time.Time               `json:"fetched_at"`
+	Err             error                   `json:"-"`
+}
+
+// ChainStream re-fetches the chains clients are streaming on an interval and pushes each
+// subscriber what changed. Clients streaming the same chain share one fetch, and a chain is
+// fetched only while someone streams it.
+type ChainStream struct {
+	chains   *ChainService
+	interval time.Duration
+
+	mu    sync.Mutex
+	feeds map[chainKey]*chainFeed
+}
+
+// chainKey identifies a streamed chain: an underlying narrowed to an expiration and type
+type chainKey struct {
+	ticker, expiration, contractType string
+}
+
+// chainFeed is one streamed chain, its subscribers and the contracts last sent them
+type chainFeed struct {
+	key         chainKey
+	subscribers map[*chainSubscriber]struct{}
+	contracts   map[string]contractState // by contract ticker
+	spot        float64
+	fetchedAt   time.Time
+	fetched     bool // contracts holds a fetch
+	stop        context.CancelFunc
+}
+
+type chainSubscriber struct {
+	updates chan ChainUpdate
+	primed  bool // has received the snapshot
+}
+
+// contractState is a contract as last sent and the encoding of the fields compared for changes
+type contractState struct {
+	contract    models.OptionContract
+	fingerprint []byte
+}
+
+// NewChainStream creates a new chain stream fetching every interval
+func NewChainStream(chains *ChainService, interval time.Duration) *ChainStream {
+	return &ChainStream{
+		chains:   chains,
+		interval: interval,
+		feeds:    make(map[chainKey]*chainFeed),
+	}
+}
+
+// Subscribe returns a channel receiving updates of the ticker's chain, narrowed to an
+// expiration and contract type when given, and a function that ends the subscription. The
+// channel is closed when the subscriber falls too far behind.
+func (s *ChainStream) Subscribe(ticker, expiration, contractType string) (<-chan ChainUpdate, func()) {
+	key := chainKey{ticker: ticker, expiration: expiration, contractType: contractType}
+	sub := &chainSubscriber{updates: make(chan ChainUpdate, chainStreamBuffer)}
+
+	s.mu.Lock()
+	feed, ok := s.feeds[key]
+	if !ok {
+		ctx, cancel := context.WithCancel(context.Background())
+		feed = &chainFeed{
+			key:         key,
+			subscribers: make(map[*chainSubscriber]struct{}),
+			contracts:   make(map[string]contractState),
+			stop:        cancel,
+		}
+		s.feeds[key] = feed
+		go s.poll(ctx, feed)
+	}
+	feed.subscribers[sub] = struct{}{}
+	if feed.fetched {
+		s.send(feed, sub, feed.snapshot())
+	}
+	s.mu.Unlock()
+
+	var once sync.Once
+	return sub.updates, func() {
+		once.Do(func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if _, ok := feed.subscribers[sub]; ok {
+				delete(feed.subscribers, sub)
+				close(sub.updates)
+			}
+			if len(feed.subscribers) == 0 && s.feeds[key] == feed {
+				feed.stop()
+				delete(s.feeds, key)
+			}
+		})
+	}
+}
+
+// poll fetches a chain every interval until its last subscriber leaves
+func (s *ChainStream) poll(ctx context.Context, feed *chainFeed) {
+	log.Printf("[ChainStream] ✓ Streaming %s chain every %s", feed.key.ticker, s.interval)
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		s.refresh(ctx, feed)
+		select {
+		case <-ctx.Done():
+			log.Printf("[ChainStream] ✓ Stopped streaming %s chain", feed.key.ticker)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// refresh fetches the chain and sends each subscriber the snapshot or the changes
+func (s *ChainStream) refresh(ctx context.Context, feed *chainFeed) {
+	params := &massive.OptionsChainParams{}
+	if feed.key.expiration != "" {
+		params.ExpirationDate = &feed.key.expiration
+	}
+	if feed.key.contractType != "" {
+		params.ContractType = &feed.key.contractType
+	}
+	snapshot, err := s.chains.GetSnapshot(ctx, feed.key.ticker, params)
+	if ctx.Err() != nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		log.Printf("[ChainStream] ⚠ Failed to fetch %s chain: %v", feed.key.ticker, err)
+		for sub := range feed.subscribers {
+			s.send(feed, sub, ChainUpdate{Ticker: feed.key.ticker, Err: err})
+		}
+		return
+	}
+
+	changes := ChainUpdate{
+		Ticker:          feed.key.ticker,
+		UnderlyingPrice: snapshot.Spot,
+		Contracts:       []models.OptionContract{},
+		FetchedAt:       snapshot.FetchedAt,
+	}
+	seen := make(map[string]struct{}, len(snapshot.Contracts))
+	for _, contract := range snapshot.Contracts {
+		if contract.Details == nil || contract.Details.Ticker == nil {
+			continue
+		}
+		ticker := *contract.Details.Ticker
+		seen[ticker] = struct{}{}
+		fingerprint := contractFingerprint(contract)
+		if state, ok := feed.contracts[ticker]; ok && bytes.Equal(state.fingerprint, fingerprint) {
+			continue
+		}
+		feed.contracts[ticker] = contractState{contract: contract, fingerprint: fingerprint}
+		changes.Contracts = append(changes.Contracts, contract)
+	}
+	for ticker := range feed.contracts {
+		if _, ok := seen[ticker]; !ok {
+			delete(feed.contracts, ticker)
+			changes.Removed = append(changes.Removed, ticker)
+		}
+	}
+	sortContracts(changes.Contracts)
+	sort.Strings(changes.Removed)
+	feed.spot, feed.fetchedAt, feed.fetched = snapshot.Spot, snapshot.FetchedAt, true
+
+	quiet := len(changes.Contracts) == 0 && len(changes.Removed) == 0
+	for sub := range feed.subscribers {
+		switch {
+		case !sub.primed:
+			s.send(feed, sub, feed.snapshot())
+		case !quiet:
+			s.send(feed, sub, changes)
+		}
+	}
+}
+
+// snapshot returns the whole chain as last fetched; the caller holds s.mu
+func (f *chainFeed) snapshot() ChainUpdate {
+	update := ChainUpdate{
+		Snapshot:        true,
+		Ticker:          f.key.ticker,
+		UnderlyingPrice: f.spot,
+		Contracts:       make([]models.OptionContract, 0, len(f.contracts)),
+		FetchedAt:       f.fetchedAt,
+	}
+	for _, state := range f.contracts {
+		update.Contracts = append(update.Contracts, state.contract)
+	}
+	sortContracts(update.Contracts)
+	return update
+}
+
+// send hands a subscriber an update, dropping it when its buffer is full; the caller holds s.mu
+func (s *ChainStream) send(feed *chainFeed, sub *chainSubscriber, u ChainUpdate) {
+	select {
+	case sub.updates <- u:
+		if u.Snapshot {
+			sub.primed = true
+		}
+	default:
+		log.Printf("[ChainStream] ⚠ Dropping %s chain subscriber %d updates behind", feed.key.ticker, chainStreamBuffer)
+		delete(feed.subscribers, sub)
+		close(sub.updates)
+	}
+}
+
+// contractFingerprint encodes the fields of a contract whose change is worth sending: all but
+// the underlying, whose price moves every contract at once and is sent on the update instead
+func contractFingerprint(c models.OptionContract) []byte {
+	c.UnderlyingAsset = nil
+	raw, _ := json.Marshal(c)
+	return raw
+}
+
+// sortContracts orders contracts by ticker, which for OCC symbols is by expiration, type and
+// strike
+func sortContracts(contracts []models.OptionContract) {
+	sort.Slice(contracts, func(i, j int) bool {
+		return *contracts[i].Details.Ticker < *contracts[j].Details.Ticker
+	})
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+)
+
+// ErrNotDemoTicker is returned for tickers outside the demo set
+var ErrNotDemoTicker = errors.New("ticker is not available in the demo")
+
+// Where a demo snapshot's data came from
+const (
+	DemoSourceSample  = "sample"  // generated by Periscope, not market data
+	DemoSourceDelayed = "delayed" // a chain fetched from Massive by the last refresh
+)
+
+// DemoSnapshot is a demo ticker's options chain as the demo serves it
+type DemoSnapshot struct {
+	Ticker    string
+	Source    string
+	AsOf      time.Time
+	Spot      float64
+	Contracts []models.OptionContract
+}
+
+// DemoService serves a small, fixed set of tickers to anonymous visitors without calling
+// Massive on their requests. Each ticker is served from the chain the last refresh fetched,
+// which is delayed by up to the refresh interval, or from a generated sample chain until a
+// refresh has succeeded (or when refreshes are off).
+type DemoService struct {
+	chains       *ChainService
+	tickers      []string
+	riskFreeRate float64
+
+	mu     sync.RWMutex
+	cached map[string]cachedDemoChain
+}
+
+// cachedDemoChain keeps a refreshed chain encoded, so every request decodes its own copy to
+// filter and annotate
+type cachedDemoChain struct {
+	spot      float64
+	contracts []byte
+	fetchedAt time.Time
+}
+
+// NewDemoService creates a demo service for the given tickers. chains fetches the delayed
+// chains on Refresh.
+func NewDemoService(chains *ChainService, tickers []string, riskFreeRate float64) *DemoService {
+	normalized := make([]string, 0, len(tickers))
+	for _, t := range tickers {
+		if t = strings.ToUpper(strings.TrimSpace(t)); t != "" {
+			normalized = append(normalized, t)
+		}
+	}
+	return &DemoService{
+		chains:       chains,
+		tickers:      normalized,
+		riskFreeRate: riskFreeRate,
+		cached:       make(map[string]cachedDemoChain),
+	}
+}
+
+// Tickers returns the tickers the demo serves, in configured order
+func (s *DemoService) Tickers() []string {
+	return s.tickers
+}
+
+// Snapshot returns the demo chain for a ticker, or ErrNotDemoTicker
+func (s *DemoService) Snapshot(ticker string, now time.Time) (*DemoSnapshot, error) {
+	ticker = strings.ToUpper(ticker)
+	if !s.serves(ticker) {
+		return nil, ErrNotDemoTicker
+	}
+
+	s.mu.RLock()
+	cached, ok := s.cached[ticker]
+	s.mu.RUnlock()
+	if ok {
+		var contracts []models.OptionContract
+		if err := json.Unmarshal(cached.contracts, &contracts); err != nil {
+			return nil, fmt.Errorf("failed to decode demo chain: %w", err)
+		}
+		return &DemoSnapshot{
+			Ticker:    ticker,
+			Source:    DemoSourceDelayed,
+			AsOf:      cached.fetchedAt,
+			Spot:      cached.spot,
+			Contracts: contracts,
+		}, nil
+	}
+
+	underlying := sampleUnderlying(ticker)
+	return &DemoSnapshot{
+		Ticker:    ticker,
+		Source:    DemoSourceSample,
+		AsOf:      now,
+		Spot:      underlying.spot,
+		Contracts: SampleChain(ticker, underlying.spot, underlying.vol, s.riskFreeRate, now),
+	}, nil
+}
+
+// Refresh fetches every demo ticker's chain from Massive and caches it. A ticker that fails
+// keeps its previous chain.
+func (s *DemoService) Refresh(ctx context.Context) error {
+	refreshed := 0
+	for _, ticker := range s.tickers {
+		snapshot, err := s.chains.GetSnapshot(ctx, ticker, nil)
+		if err != nil {
+			log.Printf("[DemoService] ⚠ Failed to refresh %s: %v", ticker, err)
+			continue
+		}
+		contracts, err := json.Marshal(snapshot.Contracts)
+		if err != nil {
+			return fmt.Errorf("failed to encode demo chain: %w", err)
+		}
+
+		s.mu.Lock()
+		s.cached[ticker] = cachedDemoChain{spot: snapshot.Spot, contracts: contracts, fetchedAt: snapshot.FetchedAt}
+		s.mu.Unlock()
+		refreshed++
+	}
+
+	log.Printf("[DemoService] ✓ Refreshed %d of %d demo chains", refreshed, len(s.tickers))
+	if refreshed == 0 && len(s.tickers) > 0 {
+		return errors.New("no demo chains refreshed")
+	}
+	return nil
+}
+
+func (s *DemoService) serves(ticker string) bool {
+	for _, t := range s.tickers {
+		if t == ticker {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"hash/fnv"
+	"math"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/analytics"
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/pricing"
+)
+
+// Shape of a generated sample chain
+const (
+	sampleWeeklies     = 4    // weekly expirations before the monthlies
+	sampleMonthlies    = 3    // monthly (third Friday) expirations after the weeklies
+	sampleStrikeRange  = 0.2  // strikes span spot ±20%
+	sampleSkew         = 0.6  // how much implied vol rises as strikes fall
+	sampleSmile        = 1.5  // curvature of the smile in log-moneyness
+	sampleMinVol       = 0.05 // floor on generated implied vol
+	sampleOpenInterest = 5000 // open interest at the money
+)
+
+// sampleUnderlyingParams are the price and volatility a sample chain is generated around
+type sampleUnderlyingParams struct {
+	spot, vol float64
+}
+
+// sampleUnderlyings are the well-known tickers' sample parameters. Other tickers get a stable
+// made-up price.
+var sampleUnderlyings = map[string]sampleUnderlyingParams{
+	"SPY":  {575, 0.15},
+	"QQQ":  {495, 0.19},
+	"IWM":  {220, 0.22},
+	"AAPL": {230, 0.24},
+	"MSFT": {420, 0.22},
+	"NVDA": {135, 0.48},
+	"AMZN": {190, 0.32},
+	"TSLA": {250, 0.55},
+}
+
+func sampleUnderlying(ticker string) sampleUnderlyingParams {
+	if u, ok := sampleUnderlyings[ticker]; ok {
+		return u
+	}
+	h := fnv.New32a()
+	h.Write([]byte(ticker))
+	return sampleUnderlyingParams{spot: float64(20 + h.Sum32()%480), vol: 0.3}
+}
+
+// SampleChain generates a plausible options chain for a ticker trading at spot: weekly and
+// monthly expirations after now, strikes around spot, priced with Black-Scholes on a skewed
+// volatility smile, with quotes, volume and open interest that thin out away from the money.
+// It is the same for the same inputs, and is not market data.
+func SampleChain(ticker string, spot, vol, rate float64, now time.Time) []models.OptionContract {
+	step := sampleStrikeStep(spot)
+	low := math.Ceil(spot*(1-sampleStrikeRange)/step) * step
+	high := math.Floor(spot*(1+sampleStrikeRange)/step) * step
+
+	contracts := []models.OptionContract{}
+	for _, expiration := range sampleExpirations(now) {
+		dte, err := analytics.DaysToExpiration(expiration, now)
+		if err != nil || dte < 0 {
+			continue
+		}
+		years := math.Max(analytics.YearFraction(dte), 0.5/365)
+		for strike := low; strike <= high+step/2; strike += step {
+			for _, optionType := range []pricing.OptionType{pricing.Call, pricing.Put} {
+				contracts = append(contracts, sampleContract(ticker, expiration, optionType, strike, spot, vol, rate, years))
+			}
+		}
+	}
+	return contracts
+}
+
+func sampleContract(ticker, expiration string, optionType pricing.OptionType, strike, spot, vol, rate, years float64) models.OptionContract {
+	moneyness := math.Log(strike / spot)
+	iv := math.Max(vol*(1-sampleSkew*moneyness+sampleSmile*moneyness*moneyness), sampleMinVol)
+	res := pricing.Compute(pricing.Inputs{Type: optionType, Spot: spot, Strike: strike, Years: years, Rate: rate, Vol: iv})
+
+	mid := math.Max(res.Price, 0.01)
+	spread := math.Max(0.01, roundCents(0.02+0.01*math.Sqrt(mid)*(1+5*math.Abs(moneyness))))
+	bid := math.Max(roundCents(mid-spread/2), 0)
+	ask := roundCents(bid + spread)
+	last := roundCents(mid)
+
+	weight := math.Exp(-8 * moneyness * moneyness)
+	openInterest := int64(sampleOpenInterest * weight)
+	volume := openInterest / 5
+	bidSize, askSize := int64(10+40*weight), int64(10+40*weight)
+
+	symbol := models.OptionSymbol{Underlying: ticker, ExpirationDate: expiration, ContractType: string(optionType), StrikePrice: strike}
+	contractTicker := symbol.Ticker()
+	contractType := string(optionType)
+	exerciseStyle := "american"
+	sharesPerContract := 100
+	underlyingTicker := ticker
+
+	return models.OptionContract{
+		Details: &models.ContractDetails{
+			Ticker:            &contractTicker,
+			ContractType:      &contractType,
+			StrikePrice:       &strike,
+			ExpirationDate:    &expiration,
+			ExerciseStyle:     &exerciseStyle,
+			SharesPerContract: &sharesPerContract,
+		},
+		Greeks: &models.Greeks{
+			Delta: &res.Delta,
+			Gamma: &res.Gamma,
+			Theta: &res.Theta,
+			Vega:  &res.Vega,
+			Rho:   &res.Rho,
+		},
+		ImpliedVol:      &iv,
+		OpenInterest:    &openInterest,
+		LastQuote:       &models.LastQuote{Bid: &bid, Ask: &ask, BidSize: &bidSize, AskSize: &askSize},
+		LastTrade:       &models.LastTrade{Price: &last},
+		Day:             &models.DayBar{Close: &last, Volume: &volume},
+		UnderlyingAsset: &models.UnderlyingAsset{Ticker: &underlyingTicker, Price: &spot},
+	}
+}
+
+// sampleExpirations returns the next weekly Fridays after today followed by the third
+// Fridays of the months after them, as YYYY-MM-DD
+func sampleExpirations(now time.Time) []string {
+	today := analytics.MarketDate(now)
+	friday := today.AddDate(0, 0, (int(time.Friday)-int(today.Weekday())+7)%7)
+	if !friday.After(today) {
+		friday = friday.AddDate(0, 0, 7)
+	}
+
+	var expirations []string
+	for i := 0; i < sampleWeeklies; i++ {
+		expirations = append(expirations, friday.Format("2006-01-02"))
+		friday = friday.AddDate(0, 0, 7)
+	}
+	last := friday.AddDate(0, 0, -7)
+	month := time.Date(last.Year(), last.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for len(expirations) < sampleWeeklies+sampleMonthlies {
+		third := thirdFriday(month)
+		if third.After(last) {
+			expirations = append(expirations, third.Format("2006-01-02"))
+		}
+		month = month.AddDate(0, 1, 0)
+	}
+	return expirations
+}
+
+// thirdFriday returns the third Friday of the month starting at first
+func thirdFriday(first time.Time) time.Time {
+	offset := (int(time.Friday) - int(first.Weekday()) + 7) % 7
+	return first.AddDate(0, 0, offset+14)
+}
+
+// sampleStrikeStep returns a listed-strike spacing suited to the price
+func sampleStrikeStep(spot float64) float64 {
+	switch {
+	case spot < 50:
+		return 1
+	case spot < 200:
+		return 2.5
+	case spot < 500:
+		return 5
+	default:
+		return 10
+	}
+}
+
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
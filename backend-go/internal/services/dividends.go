@@ -0,0 +1,240 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/analytics"
+	"github.com/aaronbengochea/periscope/backend-go/internal/ledger"
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/massive"
+)
+
+// dividendLookbackDays is how far back dividends are rebuilt from the ledger and searched
+// for the latest regular rate
+const dividendLookbackDays = 365
+
+// DividendService tracks the dividends earned by share positions and projects forward income
+type DividendService struct {
+	massiveClient *massive.Client
+	positions     *repository.PositionRepository
+	transactions  *repository.TransactionRepository
+	dividends     *repository.DividendRepository
+}
+
+// NewDividendService creates a new dividend service
+func NewDividendService(massiveClient *massive.Client, positions *repository.PositionRepository, transactions *repository.TransactionRepository, dividends *repository.DividendRepository) *DividendService {
+	return &DividendService{
+		massiveClient: massiveClient,
+		positions:     positions,
+		transactions:  transactions,
+		dividends:     dividends,
+	}
+}
+
+// Sync rebuilds the dividends of every share position held during the lookback window as
+// of the given YYYY-MM-DD date. Tickers whose dividends cannot be fetched are skipped and
+// retried on the next run.
+func (s *DividendService) Sync(ctx context.Context, today string) error {
+	date, err := analytics.ParseDate(today)
+	if err != nil {
+		return fmt.Errorf("invalid date %q: %w", today, err)
+	}
+	since := date.AddDate(0, 0, -dividendLookbackDays)
+
+	positions, err := s.positions.ListShares(ctx, since.Format("2006-01-02"))
+	if err != nil {
+		return err
+	}
+
+	byTicker := make(map[string][]models.Position)
+	var tickers []string
+	for _, p := range positions {
+		if _, ok := byTicker[p.Ticker]; !ok {
+			tickers = append(tickers, p.Ticker)
+		}
+		byTicker[p.Ticker] = append(byTicker[p.Ticker], p)
+	}
+
+	updated, failed := 0, 0
+	for _, ticker := range tickers {
+		declared, err := s.massiveClient.GetDividends(ctx, ticker, since)
+		if err != nil {
+			log.Printf("[DividendService] ⚠ Failed to fetch dividends for %s: %v", ticker, err)
+			failed++
+			continue
+		}
+
+		for i := range byTicker[ticker] {
+			p := &byTicker[ticker][i]
+			changes, err := s.transactions.QuantityChanges(ctx, p.ID)
+			if err != nil {
+				return err
+			}
+			earned := EarnedDividends(p, changes, declared, today)
+			if err := s.dividends.ReplaceForPosition(ctx, p.ID, since.Format("2006-01-02"), earned); err != nil {
+				return err
+			}
+			updated++
+		}
+	}
+
+	log.Printf("[DividendService] ✓ Synced dividends for %d share positions across %d tickers (%d failed)",
+		updated, len(tickers), failed)
+	return nil
+}
+
+// EarnedDividends returns the dividends a share position earns (or owes, when short) from
+// a ticker's declared dividends as of the given YYYY-MM-DD date. Past ex-dividend dates use
+// the quantity the ledger held going into the ex-date; upcoming ones use the current open
+// quantity. Dividends are paid once their pay date (or, without one, ex-date) has passed.
+func EarnedDividends(p *models.Position, changes []ledger.QuantityChange, declared []massive.Dividend, today string) []models.Dividend {
+	sign := 1.0
+	if p.Side == models.SideShort {
+		sign = -1
+	}
+
+	earned := []models.Dividend{}
+	for _, d := range declared {
+		if d.CashAmount <= 0 || d.ExDividendDate == "" {
+			continue
+		}
+
+		var held float64
+		if d.ExDividendDate <= today {
+			held = ledger.HeldAt(changes, d.ExDividendDate)
+		} else if p.Status == models.PositionOpen {
+			held = p.Quantity
+		}
+		if held == 0 {
+			continue
+		}
+
+		status := models.DividendAnnounced
+		paidOn := d.PayDate
+		if paidOn == "" {
+			paidOn = d.ExDividendDate
+		}
+		if paidOn <= today {
+			status = models.DividendPaid
+		}
+
+		dividend := models.Dividend{
+			PortfolioID:    p.PortfolioID,
+			PositionID:     p.ID,
+			Ticker:         p.Ticker,
+			ExDividendDate: d.ExDividendDate,
+			CashAmount:     d.CashAmount,
+			Frequency:      d.Frequency,
+			Quantity:       sign * held,
+			Amount:         sign * held * d.CashAmount,
+			Status:         status,
+		}
+		if d.PayDate != "" {
+			payDate := d.PayDate
+			dividend.PayDate = &payDate
+		}
+		earned = append(earned, dividend)
+	}
+	return earned
+}
+
+// PortfolioDividends returns a portfolio's recorded dividends with totals and projects the
+// annual income of its open share positions from each ticker's latest regular dividend
+func (s *DividendService) PortfolioDividends(ctx context.Context, portfolioID int64) (*models.PortfolioDividends, error) {
+	dividends, err := s.dividends.ListByPortfolio(ctx, portfolioID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.PortfolioDividends{
+		PortfolioID: portfolioID,
+		Projections: []models.DividendProjection{},
+		Dividends:   dividends,
+	}
+	for _, d := range dividends {
+		if d.Status == models.DividendPaid {
+			result.Received += d.Amount
+		} else {
+			result.Announced += d.Amount
+		}
+	}
+
+	open, err := s.positions.ListByPortfolio(ctx, portfolioID, models.PositionOpen)
+	if err != nil {
+		return nil, err
+	}
+	var shares []models.Position
+	var tickers []string
+	seen := make(map[string]bool)
+	for _, p := range open {
+		if p.IsOption() {
+			continue
+		}
+		shares = append(shares, p)
+		if !seen[p.Ticker] {
+			seen[p.Ticker] = true
+			tickers = append(tickers, p.Ticker)
+		}
+	}
+	if len(shares) == 0 {
+		return result, nil
+	}
+
+	since := time.Now().AddDate(0, 0, -dividendLookbackDays)
+	rates := make(map[string]massive.Dividend, len(tickers))
+	for _, ticker := range tickers {
+		declared, err := s.massiveClient.GetDividends(ctx, ticker, since)
+		if err != nil {
+			log.Printf("[DividendService] ⚠ Failed to fetch dividends for %s: %v", ticker, err)
+			continue
+		}
+		if rate, ok := latestRegularDividend(declared); ok {
+			rates[ticker] = rate
+		}
+	}
+
+	prices, err := s.massiveClient.GetStockPrices(ctx, tickers)
+	if err != nil {
+		log.Printf("[DividendService] ⚠ Failed to fetch prices for dividend yields: %v", err)
+	}
+
+	for _, p := range shares {
+		rate, ok := rates[p.Ticker]
+		if !ok {
+			continue
+		}
+		proj := models.DividendProjection{
+			PositionID:   p.ID,
+			Ticker:       p.Ticker,
+			Quantity:     p.SignedQuantity(),
+			CashAmount:   rate.CashAmount,
+			Frequency:    rate.Frequency,
+			AnnualIncome: p.SignedQuantity() * rate.CashAmount * float64(rate.Frequency),
+		}
+		if price, ok := prices[p.Ticker]; ok && price > 0 {
+			yield := rate.CashAmount * float64(rate.Frequency) / price
+			proj.Price = &price
+			proj.Yield = &yield
+		}
+		result.ProjectedAnnualIncome += proj.AnnualIncome
+		result.Projections = append(result.Projections, proj)
+	}
+
+	return result, nil
+}
+
+// latestRegularDividend returns the most recent recurring dividend among dividends ordered
+// by ex-dividend date
+func latestRegularDividend(declared []massive.Dividend) (massive.Dividend, bool) {
+	for i := len(declared) - 1; i >= 0; i-- {
+		d := declared[i]
+		if d.Frequency > 0 && d.CashAmount > 0 && d.DividendType != massive.DividendSpecial {
+			return d, true
+		}
+	}
+	return massive.Dividend{}, false
+}
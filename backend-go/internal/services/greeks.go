@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/analytics"
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/pricing"
+)
+
+// PortfolioGreeks refreshes live snapshots for a portfolio's open positions and totals
+// their net and beta-weighted greeks
+func (s *ValuationService) PortfolioGreeks(ctx context.Context, portfolioID int64) (*models.PortfolioGreeks, error) {
+	positions, err := s.positions.ListByPortfolio(ctx, portfolioID, models.PositionOpen)
+	if err != nil {
+		return nil, err
+	}
+
+	quotes, err := s.FetchQuotes(ctx, positions, BenchmarkTicker)
+	if err != nil {
+		return nil, err
+	}
+
+	betas := s.betas.Betas(ctx, underlyingTickers(positions))
+
+	greeks := AggregateGreeks(positions, quotes, betas, s.riskFreeRate, time.Now())
+	greeks.PortfolioID = portfolioID
+
+	log.Printf("[ValuationService] ✓ Aggregated greeks for portfolio %d: %d positions, %d incomplete",
+		portfolioID, len(greeks.Positions), greeks.Incomplete)
+	return greeks, nil
+}
+
+// AggregateGreeks scales each open position's per-unit greeks by its signed quantity and
+// multiplier and sums them. Positions with missing greeks contribute what is available and
+// are counted as incomplete; positions without a beta or prices are left out of the
+// beta-weighted totals.
+func AggregateGreeks(positions []models.Position, quotes *Quotes, betas map[string]float64, rate float64, now time.Time) *models.PortfolioGreeks {
+	result := &models.PortfolioGreeks{
+		Benchmark: BenchmarkTicker,
+		Positions: []models.PositionGreeks{},
+		AsOf:      now,
+	}
+	if price, ok := quotes.Stocks[BenchmarkTicker]; ok {
+		result.BenchmarkPrice = &price
+	}
+
+	for i := range positions {
+		p := &positions[i]
+		if p.Status != models.PositionOpen {
+			continue
+		}
+
+		units := p.SignedQuantity() * float64(p.Multiplier)
+		unit, source := unitGreeks(p, quotes, rate, now)
+
+		pg := models.PositionGreeks{
+			PositionID:       p.ID,
+			Ticker:           p.Ticker,
+			UnderlyingTicker: p.UnderlyingTicker,
+			Quantity:         p.SignedQuantity(),
+			UnderlyingPrice:  quotes.UnderlyingPrice(p),
+			Source:           source,
+			Delta:            scale(unit.delta, units),
+			Gamma:            scale(unit.gamma, units),
+			Theta:            scale(unit.theta, units),
+			Vega:             scale(unit.vega, units),
+		}
+		if beta, ok := betas[p.UnderlyingTicker]; ok {
+			pg.Beta = &beta
+		}
+		if source == models.GreeksPartial || source == models.GreeksMissing {
+			result.Incomplete++
+		}
+
+		if pg.Delta != nil {
+			result.Delta += *pg.Delta
+			if pg.UnderlyingPrice != nil {
+				result.DollarDelta += *pg.Delta * *pg.UnderlyingPrice
+			}
+		}
+		if pg.Gamma != nil {
+			result.Gamma += *pg.Gamma
+		}
+		if pg.Theta != nil {
+			result.Theta += *pg.Theta
+		}
+		if pg.Vega != nil {
+			result.Vega += *pg.Vega
+		}
+
+		// Beta-weighting converts underlying share equivalents into benchmark share
+		// equivalents: a 1% benchmark move is assumed to move the underlying beta%
+		if pg.Beta != nil && pg.UnderlyingPrice != nil && result.BenchmarkPrice != nil && pg.Delta != nil {
+			ratio := *pg.Beta * *pg.UnderlyingPrice / *result.BenchmarkPrice
+			bwDelta := *pg.Delta * ratio
+			pg.BetaWeightedDelta = &bwDelta
+			result.BetaWeightedDelta += bwDelta
+			if pg.Gamma != nil {
+				bwGamma := *pg.Gamma * ratio * ratio
+				pg.BetaWeightedGamma = &bwGamma
+				result.BetaWeightedGamma += bwGamma
+			}
+		} else {
+			result.Unweighted++
+		}
+
+		result.Positions = append(result.Positions, pg)
+	}
+
+	return result
+}
+
+// underlyingTickers returns the distinct underlyings of the positions
+func underlyingTickers(positions []models.Position) []string {
+	var tickers []string
+	seen := make(map[string]bool)
+	for _, p := range positions {
+		if !seen[p.UnderlyingTicker] {
+			seen[p.UnderlyingTicker] = true
+			tickers = append(tickers, p.UnderlyingTicker)
+		}
+	}
+	return tickers
+}
+
+// greekSet holds per-unit greeks, any of which may be unavailable
+type greekSet struct {
+	delta, gamma, theta, vega *float64
+}
+
+func (g greekSet) complete() bool {
+	return g.delta != nil && g.gamma != nil && g.theta != nil && g.vega != nil
+}
+
+func (g greekSet) empty() bool {
+	return g.delta == nil && g.gamma == nil && g.theta == nil && g.vega == nil
+}
+
+// unitGreeks returns per-share or per-contract greeks for a position, preferring the
+// snapshot and filling gaps from the pricing model when the contract has an implied vol
+func unitGreeks(p *models.Position, quotes *Quotes, rate float64, now time.Time) (greekSet, string) {
+	if !p.IsOption() {
+		one, zero := 1.0, 0.0
+		return greekSet{delta: &one, gamma: &zero, theta: &zero, vega: &zero}, models.GreeksFromStock
+	}
+
+	c, ok := quotes.Contracts[p.Ticker]
+	if !ok {
+		return greekSet{}, models.GreeksMissing
+	}
+
+	var g greekSet
+	if c.Greeks != nil {
+		g = greekSet{delta: c.Greeks.Delta, gamma: c.Greeks.Gamma, theta: c.Greeks.Theta, vega: c.Greeks.Vega}
+	}
+	if g.complete() {
+		return g, models.GreeksFromSnapshot
+	}
+
+	fromModel := false
+	if in, ok := analytics.PricingInputs(c, rate, now); ok {
+		res := pricing.Compute(in)
+		fill := func(dst **float64, v float64) {
+			if *dst == nil {
+				*dst = &v
+				fromModel = true
+			}
+		}
+		fill(&g.delta, res.Delta)
+		fill(&g.gamma, res.Gamma)
+		fill(&g.theta, res.Theta)
+		fill(&g.vega, res.Vega)
+	}
+
+	switch {
+	case g.complete() && fromModel:
+		return g, models.GreeksFromModel
+	case g.empty():
+		return g, models.GreeksMissing
+	default:
+		return g, models.GreeksPartial
+	}
+}
+
+// scale multiplies an optional value, preserving nil
+func scale(v *float64, by float64) *float64 {
+	if v == nil {
+		return nil
+	}
+	scaled := *v * by
+	return &scaled
+}
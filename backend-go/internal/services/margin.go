@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/analytics"
+	"github.com/aaronbengochea/periscope/backend-go/internal/margin"
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/pricing"
+)
+
+// Margin estimates a portfolio's Reg-T and portfolio margin requirements from live
+// snapshots, and again with proposed trades added, so their collateral impact is known
+// before they are opened. Buying power is reported when the account equity is given.
+func (s *ValuationService) Margin(ctx context.Context, portfolioID int64, trades []models.Position, equity *float64) (*models.MarginEstimate, error) {
+	positions, err := s.positions.ListByPortfolio(ctx, portfolioID, models.PositionOpen)
+	if err != nil {
+		return nil, err
+	}
+
+	all := append(append([]models.Position{}, positions...), trades...)
+	quotes, err := s.FetchQuotes(ctx, all)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	estimate := &models.MarginEstimate{
+		PortfolioID:   portfolioID,
+		AccountEquity: equity,
+		Current:       SummarizeMargin(positions, quotes, s.riskFreeRate, now, equity),
+		AsOf:          now,
+	}
+	if len(trades) > 0 {
+		with := SummarizeMargin(all, quotes, s.riskFreeRate, now, equity)
+		regT := with.RegT - estimate.Current.RegT
+		pm := with.PortfolioMargin - estimate.Current.PortfolioMargin
+		estimate.WithTrades = &with
+		estimate.RegTChange = &regT
+		estimate.PortfolioMarginChange = &pm
+	}
+
+	log.Printf("[ValuationService] ✓ Estimated margin for portfolio %d: Reg-T %.2f, portfolio margin %.2f, %d proposed trades",
+		portfolioID, estimate.Current.RegT, estimate.Current.PortfolioMargin, len(trades))
+	return estimate, nil
+}
+
+// SummarizeMargin groups open positions by underlying and totals their margin
+// requirements. Underlyings without a price are listed as unpriced and left out.
+func SummarizeMargin(positions []models.Position, quotes *Quotes, rate float64, now time.Time, equity *float64) models.MarginSummary {
+	byUnderlying := make(map[string][]*models.Position)
+	for i := range positions {
+		p := &positions[i]
+		if p.Status == models.PositionOpen {
+			byUnderlying[p.UnderlyingTicker] = append(byUnderlying[p.UnderlyingTicker], p)
+		}
+	}
+	tickers := make([]string, 0, len(byUnderlying))
+	for ticker := range byUnderlying {
+		tickers = append(tickers, ticker)
+	}
+	sort.Strings(tickers)
+
+	summary := models.MarginSummary{
+		Underlyings: []models.UnderlyingMargin{},
+		Unpriced:    []string{},
+	}
+	for _, ticker := range tickers {
+		group := byUnderlying[ticker]
+		var spot *float64
+		for _, p := range group {
+			if spot = quotes.UnderlyingPrice(p); spot != nil {
+				break
+			}
+		}
+		if spot == nil {
+			summary.Unpriced = append(summary.Unpriced, ticker)
+			continue
+		}
+
+		legs := make([]margin.Leg, 0, len(group))
+		for _, p := range group {
+			legs = append(legs, marginLeg(p, quotes, *spot, rate, now))
+		}
+		m := margin.Underlying(ticker, legs, *spot)
+		summary.RegT += m.RegT
+		summary.PortfolioMargin += m.PortfolioMargin
+		summary.Underlyings = append(summary.Underlyings, m)
+	}
+
+	if equity != nil {
+		regT := *equity - summary.RegT
+		pm := *equity - summary.PortfolioMargin
+		summary.RegTBuyingPower = &regT
+		summary.PortfolioMarginBuyingPower = &pm
+	}
+	return summary
+}
+
+// marginLeg converts a position to a margin leg. Option legs without a mark fall back to
+// the model price, or intrinsic value when the contract lacks pricing inputs.
+func marginLeg(p *models.Position, quotes *Quotes, spot, rate float64, now time.Time) margin.Leg {
+	leg := margin.Leg{
+		PositionID: p.ID,
+		Ticker:     p.Ticker,
+		Option:     p.IsOption(),
+		Quantity:   p.SignedQuantity(),
+		Multiplier: float64(p.Multiplier),
+		Mark:       spot,
+	}
+	if !leg.Option {
+		if mark := quotes.MarkPrice(p); mark != nil {
+			leg.Mark = *mark
+		}
+		return leg
+	}
+
+	leg.Call = p.ContractType != nil && *p.ContractType == "call"
+	if p.StrikePrice != nil {
+		leg.Strike = *p.StrikePrice
+	}
+	if p.ExpirationDate != nil {
+		leg.Expiration = *p.ExpirationDate
+	}
+
+	model := pricing.Inputs{Type: pricing.Put, Spot: spot, Strike: leg.Strike}
+	if leg.Call {
+		model.Type = pricing.Call
+	}
+	if c, ok := quotes.Contracts[p.Ticker]; ok {
+		if in, ok := analytics.PricingInputs(c, rate, now); ok {
+			in.Spot = spot
+			model = in
+			leg.Model = &in
+		}
+	}
+
+	leg.Mark = pricing.Price(model)
+	if mark := quotes.MarkPrice(p); mark != nil {
+		leg.Mark = *mark
+	}
+	return leg
+}
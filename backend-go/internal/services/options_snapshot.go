@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+)
+
+// OptionsSnapshotService captures options chains into Periscope's own history, the dataset
+// behind historical chains, IV rank and open interest changes
+type OptionsSnapshotService struct {
+	chains    *ChainService
+	snapshots *repository.OptionsSnapshotRepository
+}
+
+// NewOptionsSnapshotService creates a new options snapshot service
+func NewOptionsSnapshotService(chains *ChainService, snapshots *repository.OptionsSnapshotRepository) *OptionsSnapshotService {
+	return &OptionsSnapshotService{chains: chains, snapshots: snapshots}
+}
+
+// Capture fetches a ticker's whole chain and stores every contract in it. Contracts without
+// a ticker, type, strike or expiration are left out.
+func (s *OptionsSnapshotService) Capture(ctx context.Context, ticker string) (*models.OptionsSnapshot, error) {
+	chain, err := s.chains.GetSnapshot(ctx, ticker, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	capturedAt := chain.FetchedAt.UTC()
+	contracts := make([]models.OptionsSnapshotContract, 0, len(chain.Contracts))
+	for i := range chain.Contracts {
+		if row, ok := models.NewOptionsSnapshotContract(ticker, capturedAt, &chain.Contracts[i]); ok {
+			contracts = append(contracts, row)
+		}
+	}
+	if len(contracts) == 0 {
+		return nil, fmt.Errorf("no contracts to store for %s", ticker)
+	}
+
+	snapshot := &models.OptionsSnapshot{
+		Ticker:     ticker,
+		CapturedAt: capturedAt,
+		Spot:       chain.Spot,
+	}
+	if err := s.snapshots.Create(ctx, snapshot, contracts); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// CaptureAll captures each ticker's chain in turn. A ticker that fails is logged and
+// skipped; an error is returned only when none could be captured.
+func (s *OptionsSnapshotService) CaptureAll(ctx context.Context, tickers []string) error {
+	captured, contracts := 0, 0
+	for _, ticker := range tickers {
+		snapshot, err := s.Capture(ctx, ticker)
+		if err != nil {
+			log.Printf("[OptionsSnapshotService] ⚠ Failed to capture %s: %v", ticker, err)
+			continue
+		}
+		captured++
+		contracts += snapshot.Contracts
+	}
+
+	log.Printf("[OptionsSnapshotService] ✓ Captured %d of %d chains (%d contracts)", captured, len(tickers), contracts)
+	if captured == 0 && len(tickers) > 0 {
+		return errors.New("no options chains captured")
+	}
+	return nil
+}
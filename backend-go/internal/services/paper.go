@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/analytics"
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+)
+
+// ErrNoQuote is returned when an order's contract or stock has no usable market price
+var ErrNoQuote = errors.New("no quote available")
+
+// PaperTradingService fills simulated orders of paper portfolios against live quotes and
+// records them in the same ledger as real trades
+type PaperTradingService struct {
+	valuation   *ValuationService
+	orders      *repository.PaperOrderRepository
+	slippageBps float64 // default adverse slippage from the reference price, in basis points
+}
+
+// NewPaperTradingService creates a new paper trading service
+func NewPaperTradingService(valuation *ValuationService, orders *repository.PaperOrderRepository, slippageBps float64) *PaperTradingService {
+	return &PaperTradingService{
+		valuation:   valuation,
+		orders:      orders,
+		slippageBps: slippageBps,
+	}
+}
+
+// Submit fills an order at the quote's mid (or last price without a two-sided quote) moved
+// against the trader by the slippage, defaulting to the configured slippage when nil. A
+// limit order the fill would violate is recorded as rejected instead. position describes
+// the contract or stock and is used when the order opens a new position.
+func (s *PaperTradingService) Submit(ctx context.Context, o *models.PaperOrder, position *models.Position, slippageBps *float64) error {
+	quotes, err := s.valuation.FetchQuotes(ctx, []models.Position{*position})
+	if err != nil {
+		return err
+	}
+
+	if position.IsOption() {
+		contract, ok := quotes.Contracts[position.Ticker]
+		if !ok {
+			return fmt.Errorf("%w for %s", ErrNoQuote, position.Ticker)
+		}
+		if q := contract.LastQuote; q != nil {
+			o.Bid, o.Ask = q.Bid, q.Ask
+		}
+		if d := contract.Details; d != nil && d.SharesPerContract != nil && *d.SharesPerContract > 0 {
+			position.Multiplier = *d.SharesPerContract
+		}
+	}
+	reference := quotes.MarkPrice(position)
+	if reference == nil {
+		return fmt.Errorf("%w for %s", ErrNoQuote, position.Ticker)
+	}
+	o.ReferencePrice = *reference
+
+	o.SlippageBps = s.slippageBps
+	if slippageBps != nil {
+		o.SlippageBps = *slippageBps
+	}
+	fill := FillPrice(o.ReferencePrice, o.SlippageBps, o.Buys())
+	o.FillPrice = &fill
+
+	if reason := limitViolation(o); reason != "" {
+		o.Status = models.OrderRejected
+		o.RejectReason = &reason
+		if err := s.orders.Reject(ctx, o); err != nil {
+			return err
+		}
+		log.Printf("[PaperTradingService] ⚠ Rejected %s %.4f %s: %s", o.Instruction, o.Quantity, o.Ticker, reason)
+		return nil
+	}
+
+	o.Status = models.OrderFilled
+	tradedAt := analytics.MarketDate(time.Now()).Format("2006-01-02")
+	if err := s.orders.Fill(ctx, o, position, tradedAt); err != nil {
+		return err
+	}
+
+	log.Printf("[PaperTradingService] ✓ Filled %s %.4f %s at %.2f (reference %.4f, %.1f bps) into position %d",
+		o.Instruction, o.Quantity, o.Ticker, fill, o.ReferencePrice, o.SlippageBps, *o.PositionID)
+	return nil
+}
+
+// FillPrice moves the reference price against the trader by the slippage, up for buys and
+// down for sells, rounded to the cent
+func FillPrice(reference, slippageBps float64, buy bool) float64 {
+	slip := reference * slippageBps / 10000
+	if !buy {
+		slip = -slip
+	}
+	return math.Max(math.Round((reference+slip)*100)/100, 0)
+}
+
+// limitViolation explains why a limit order cannot fill at its fill price, or returns ""
+func limitViolation(o *models.PaperOrder) string {
+	if o.LimitPrice == nil {
+		return ""
+	}
+	if o.Buys() && *o.FillPrice > *o.LimitPrice {
+		return fmt.Sprintf("fill price %.2f is above the limit %.2f", *o.FillPrice, *o.LimitPrice)
+	}
+	if !o.Buys() && *o.FillPrice < *o.LimitPrice {
+		return fmt.Sprintf("fill price %.2f is below the limit %.2f", *o.FillPrice, *o.LimitPrice)
+	}
+	return ""
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/analytics"
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/massive"
+)
+
+// PerformanceService computes portfolio returns from the snapshot history
+type PerformanceService struct {
+	massiveClient *massive.Client
+	snapshots     repository.SnapshotRepo
+}
+
+// NewPerformanceService creates a new performance service
+func NewPerformanceService(massiveClient *massive.Client, snapshots repository.SnapshotRepo) *PerformanceService {
+	return &PerformanceService{
+		massiveClient: massiveClient,
+		snapshots:     snapshots,
+	}
+}
+
+// Performance returns a portfolio's return series from the snapshots on or after the given
+// YYYY-MM-DD date, compared with the benchmark's daily closes over the same days. A failed
+// benchmark fetch leaves the benchmark returns empty rather than failing the request.
+func (s *PerformanceService) Performance(ctx context.Context, portfolioID int64, since string) (*models.PortfolioPerformance, error) {
+	snapshots, err := s.snapshots.ListSince(ctx, portfolioID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	benchmark := make(map[string]float64)
+	if len(snapshots) > 0 {
+		first, err := analytics.ParseDate(snapshots[0].Date)
+		if err != nil {
+			return nil, fmt.Errorf("invalid snapshot date %q: %w", snapshots[0].Date, err)
+		}
+		// Start a week early so the first snapshot has a prior close to fall back on
+		bars, err := s.massiveClient.GetDailyBars(ctx, BenchmarkTicker, first.AddDate(0, 0, -7), time.Now())
+		if err != nil {
+			log.Printf("[PerformanceService] ⚠ Failed to fetch %s bars: %v", BenchmarkTicker, err)
+		}
+		for _, b := range bars {
+			benchmark[barDate(b)] = b.Close
+		}
+	}
+
+	perf, err := BuildPerformance(snapshots, benchmark)
+	if err != nil {
+		return nil, err
+	}
+	perf.PortfolioID = portfolioID
+	return perf, nil
+}
+
+// BuildPerformance computes time-weighted, money-weighted and benchmark returns from
+// snapshots ordered oldest first. benchmark maps YYYY-MM-DD dates to closes; days without a
+// close use the latest earlier one.
+func BuildPerformance(snapshots []models.PortfolioSnapshot, benchmark map[string]float64) (*models.PortfolioPerformance, error) {
+	perf := &models.PortfolioPerformance{
+		Benchmark: BenchmarkTicker,
+		Points:    []models.PerformancePoint{},
+	}
+	if len(snapshots) == 0 {
+		return perf, nil
+	}
+
+	n := len(snapshots)
+	days := make([]float64, n)
+	equity := make([]float64, n)
+	pnl := make([]float64, n)
+	first, err := analytics.ParseDate(snapshots[0].Date)
+	if err != nil {
+		return nil, fmt.Errorf("invalid snapshot date %q: %w", snapshots[0].Date, err)
+	}
+	for i, snap := range snapshots {
+		date, err := analytics.ParseDate(snap.Date)
+		if err != nil {
+			return nil, fmt.Errorf("invalid snapshot date %q: %w", snap.Date, err)
+		}
+		days[i] = math.Round(date.Sub(first).Hours() / 24)
+		equity[i] = snap.MarketValue
+		pnl[i] = snap.TotalPnL
+	}
+
+	flows := analytics.NetFlows(equity, pnl)
+	twr, undefined := analytics.TimeWeightedReturns(equity, flows)
+	mwr := analytics.ModifiedDietzReturns(days, equity, flows)
+	benchmarkReturns := benchmarkSeries(snapshots, benchmark)
+
+	for i, snap := range snapshots {
+		point := models.PerformancePoint{
+			Date:            snap.Date,
+			Equity:          equity[i],
+			NetFlow:         flows[i],
+			TWR:             twr[i],
+			MWR:             mwr[i],
+			BenchmarkReturn: benchmarkReturns[i],
+		}
+		if i > 0 {
+			point.PnL = pnl[i] - pnl[i-1]
+		}
+		perf.Points = append(perf.Points, point)
+	}
+
+	perf.From = snapshots[0].Date
+	perf.To = snapshots[n-1].Date
+	perf.TWR = twr[n-1]
+	perf.MWR = mwr[n-1]
+	perf.BenchmarkReturn = benchmarkReturns[n-1]
+	perf.UndefinedDays = undefined
+
+	if span := days[n-1]; span >= 365 {
+		annualize := func(r float64) *float64 {
+			a := math.Pow(1+r, 365/span) - 1
+			return &a
+		}
+		perf.AnnualizedTWR = annualize(perf.TWR)
+		perf.AnnualizedMWR = analytics.AnnualizedIRR(days, equity, flows)
+		if perf.BenchmarkReturn != nil {
+			perf.AnnualizedBenchmark = annualize(*perf.BenchmarkReturn)
+		}
+	}
+
+	return perf, nil
+}
+
+// benchmarkSeries returns the benchmark's cumulative return as of each snapshot date,
+// carrying the latest close forward over days it did not trade. Returns are nil until the
+// benchmark has a close.
+func benchmarkSeries(snapshots []models.PortfolioSnapshot, closes map[string]float64) []*float64 {
+	returns := make([]*float64, len(snapshots))
+	if len(closes) == 0 {
+		return returns
+	}
+
+	// Seed with the latest close before the first snapshot
+	var last, base float64
+	var seed string
+	for date, c := range closes {
+		if date <= snapshots[0].Date && date > seed {
+			seed, last = date, c
+		}
+	}
+	base = last
+
+	for i, snap := range snapshots {
+		if c, ok := closes[snap.Date]; ok {
+			last = c
+		}
+		if last <= 0 {
+			continue
+		}
+		if base <= 0 {
+			base = last
+		}
+		r := last/base - 1
+		returns[i] = &r
+	}
+	return returns
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/analytics"
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+)
+
+// PositionAlertMetrics refreshes live snapshots for positions and returns the alert
+// metrics available for each, keyed by position ID
+func (s *ValuationService) PositionAlertMetrics(ctx context.Context, positions []models.Position) (map[int64]map[string]float64, error) {
+	quotes, err := s.FetchQuotes(ctx, positions)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	metrics := make(map[int64]map[string]float64, len(positions))
+	for i := range positions {
+		metrics[positions[i].ID] = PositionMetrics(&positions[i], quotes, s.riskFreeRate, now)
+	}
+	return metrics, nil
+}
+
+// PositionMetrics returns the alert metrics of an open position that are available from
+// the quotes. Greeks are per share and signed by side; metrics without data are omitted.
+func PositionMetrics(p *models.Position, quotes *Quotes, rate float64, now time.Time) map[string]float64 {
+	metrics := make(map[string]float64)
+	set := func(metric string, v *float64) {
+		if v != nil {
+			metrics[metric] = *v
+		}
+	}
+
+	sign := 1.0
+	if p.Side == models.SideShort {
+		sign = -1
+	}
+	greeks, _ := unitGreeks(p, quotes, rate, now)
+	set(models.AlertMetricDelta, scale(greeks.delta, sign))
+	set(models.AlertMetricGamma, scale(greeks.gamma, sign))
+	set(models.AlertMetricTheta, scale(greeks.theta, sign))
+	set(models.AlertMetricVega, scale(greeks.vega, sign))
+	set(models.AlertMetricUnderlyingPrice, quotes.UnderlyingPrice(p))
+
+	if mark := quotes.MarkPrice(p); mark != nil {
+		costBasis := p.CostBasis()
+		pnl := p.SignedQuantity()**mark*float64(p.Multiplier) - costBasis
+		metrics[models.AlertMetricMarkPrice] = *mark
+		metrics[models.AlertMetricPnL] = pnl
+		if costBasis != 0 {
+			metrics[models.AlertMetricPnLPercent] = pnl / math.Abs(costBasis) * 100
+		}
+	}
+
+	if p.IsOption() {
+		if c, ok := quotes.Contracts[p.Ticker]; ok {
+			set(models.AlertMetricImpliedVolatility, c.ImpliedVol)
+		}
+		if p.ExpirationDate != nil {
+			if dte, err := analytics.DaysToExpiration(*p.ExpirationDate, now); err == nil {
+				metrics[models.AlertMetricDaysToExpiration] = float64(dte)
+			}
+		}
+	}
+
+	return metrics
+}
@@ -0,0 +1,262 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/massive"
+)
+
+// quoteStreamBuffer is how many updates a subscriber may fall behind by
+const quoteStreamBuffer = 256
+
+// QuoteUpdate is a changed quote of one stock or option contract; exactly one of Stock and
+// Contract is set
+type QuoteUpdate struct {
+	Symbol   string
+	Stock    *models.WatchlistQuote
+	Contract *models.WatchlistContractQuote
+}
+
+// QuoteStream polls the quotes of every symbol some client is subscribed to and pushes each
+// subscriber the ones that changed since the last poll. It polls only while at least one
+// client is subscribed, in one batched snapshot request for the stocks and another for the
+// contracts however many clients share them.
+type QuoteStream struct {
+	massiveClient *massive.Client
+	interval      time.Duration
+
+	mu          sync.Mutex
+	subscribers map[*QuoteSubscription]struct{}
+	last        map[string]quoteState // latest quote of each subscribed symbol
+	stop        context.CancelFunc    // stops the poller, nil when it is not running
+}
+
+// quoteState is a symbol's latest quote and its encoding, compared to find changes
+type quoteState struct {
+	update QuoteUpdate
+	raw    []byte
+}
+
+// QuoteSubscription is one client's set of symbols and the channel its updates arrive on
+type QuoteSubscription struct {
+	stream  *QuoteStream
+	symbols map[string]struct{}
+	updates chan QuoteUpdate
+	closed  bool
+}
+
+// NewQuoteStream creates a new quote stream polling every interval
+func NewQuoteStream(massiveClient *massive.Client, interval time.Duration) *QuoteStream {
+	return &QuoteStream{
+		massiveClient: massiveClient,
+		interval:      interval,
+		subscribers:   make(map[*QuoteSubscription]struct{}),
+		last:          make(map[string]quoteState),
+	}
+}
+
+// Subscribe starts a subscription to no symbols
+func (s *QuoteStream) Subscribe() *QuoteSubscription {
+	sub := &QuoteSubscription{
+		stream:  s,
+		symbols: make(map[string]struct{}),
+		updates: make(chan QuoteUpdate, quoteStreamBuffer),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subscribers[sub] = struct{}{}
+	if s.stop == nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		s.stop = cancel
+		go s.poll(ctx)
+	}
+	return sub
+}
+
+// Updates returns the channel of the subscription's quote updates. It is closed when the
+// subscription ends, or when the subscriber falls too far behind.
+func (sub *QuoteSubscription) Updates() <-chan QuoteUpdate {
+	return sub.updates
+}
+
+// Symbols returns the subscribed symbols in order
+func (sub *QuoteSubscription) Symbols() []string {
+	sub.stream.mu.Lock()
+	defer sub.stream.mu.Unlock()
+	symbols := make([]string, 0, len(sub.symbols))
+	for symbol := range sub.symbols {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+// Add subscribes to more symbols, sending the latest known quote of each right away; the
+// others arrive with the next poll
+func (sub *QuoteSubscription) Add(symbols []string) {
+	s := sub.stream
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, symbol := range symbols {
+		if _, ok := sub.symbols[symbol]; ok {
+			continue
+		}
+		sub.symbols[symbol] = struct{}{}
+		if state, ok := s.last[symbol]; ok {
+			s.send(sub, state.update)
+		}
+	}
+}
+
+// Remove unsubscribes from symbols
+func (sub *QuoteSubscription) Remove(symbols []string) {
+	sub.stream.mu.Lock()
+	defer sub.stream.mu.Unlock()
+	for _, symbol := range symbols {
+		delete(sub.symbols, symbol)
+	}
+}
+
+// Close ends the subscription, stopping the poller after the last one
+func (sub *QuoteSubscription) Close() {
+	s := sub.stream
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.drop(sub)
+	if len(s.subscribers) == 0 && s.stop != nil {
+		s.stop()
+		s.stop = nil
+		s.last = make(map[string]quoteState)
+	}
+}
+
+// drop removes a subscriber and closes its channel; the caller holds s.mu
+func (s *QuoteStream) drop(sub *QuoteSubscription) {
+	if sub.closed {
+		return
+	}
+	sub.closed = true
+	delete(s.subscribers, sub)
+	close(sub.updates)
+}
+
+// send hands a subscriber an update, dropping it when its buffer is full; the caller holds s.mu
+func (s *QuoteStream) send(sub *QuoteSubscription, u QuoteUpdate) {
+	if sub.closed {
+		return
+	}
+	select {
+	case sub.updates <- u:
+	default:
+		log.Printf("[QuoteStream] ⚠ Dropping quote stream subscriber %d updates behind", quoteStreamBuffer)
+		s.drop(sub)
+	}
+}
+
+// poll fetches the subscribed quotes every interval until stopped
+func (s *QuoteStream) poll(ctx context.Context) {
+	log.Printf("[QuoteStream] ✓ Polling quotes every %s", s.interval)
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		s.refresh(ctx)
+		select {
+		case <-ctx.Done():
+			log.Println("[QuoteStream] ✓ Stopped polling quotes")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// refresh fetches the quotes of every subscribed symbol and publishes those that changed
+func (s *QuoteStream) refresh(ctx context.Context) {
+	s.mu.Lock()
+	wanted := make(map[string]struct{})
+	for sub := range s.subscribers {
+		for symbol := range sub.symbols {
+			wanted[symbol] = struct{}{}
+		}
+	}
+	// Forget symbols nobody follows any more, so resubscribing sends a fresh quote
+	for symbol := range s.last {
+		if _, ok := wanted[symbol]; !ok {
+			delete(s.last, symbol)
+		}
+	}
+	s.mu.Unlock()
+
+	var stocks, contracts []string
+	for symbol := range wanted {
+		if strings.HasPrefix(symbol, "O:") {
+			contracts = append(contracts, symbol)
+		} else {
+			stocks = append(stocks, symbol)
+		}
+	}
+	if len(wanted) == 0 {
+		return
+	}
+
+	var updates []QuoteUpdate
+	if len(stocks) > 0 {
+		snapshots, err := s.massiveClient.GetStockSnapshots(ctx, stocks)
+		if err != nil {
+			log.Printf("[QuoteStream] ⚠ Failed to fetch stock snapshots: %v", err)
+		}
+		for _, ticker := range stocks {
+			if stock, ok := snapshots[ticker]; ok {
+				q := stockQuote(ticker, &stock)
+				updates = append(updates, QuoteUpdate{Symbol: ticker, Stock: &q})
+			}
+		}
+	}
+	if len(contracts) > 0 {
+		details, err := s.massiveClient.GetContractDetails(ctx, contracts)
+		if err != nil {
+			log.Printf("[QuoteStream] ⚠ Failed to fetch option snapshots: %v", err)
+		}
+		for i := range details {
+			d := details[i].Details
+			if d == nil || d.Ticker == nil {
+				continue
+			}
+			symbol, err := models.ParseOptionTicker(*d.Ticker)
+			if err != nil {
+				continue
+			}
+			q := contractQuote(*d.Ticker, symbol, &details[i])
+			updates = append(updates, QuoteUpdate{Symbol: *d.Ticker, Contract: &q})
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ctx.Err() != nil {
+		return // the last subscriber left while fetching
+	}
+	for _, u := range updates {
+		raw, err := json.Marshal(u)
+		if err != nil {
+			continue
+		}
+		if state, ok := s.last[u.Symbol]; ok && bytes.Equal(state.raw, raw) {
+			continue
+		}
+		s.last[u.Symbol] = quoteState{update: u, raw: raw}
+		for sub := range s.subscribers {
+			if _, ok := sub.symbols[u.Symbol]; ok {
+				s.send(sub, u)
+			}
+		}
+	}
+}
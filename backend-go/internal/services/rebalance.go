@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/rebalance"
+)
+
+// Rebalance measures a portfolio's drift from its target allocations in dollar delta,
+// so option legs count by their delta-equivalent shares, and suggests share and option
+// trades for targets beyond the threshold. The base defaults to the portfolio's net dollar
+// delta; pass the account value to allocate cash as well.
+func (s *ValuationService) Rebalance(ctx context.Context, portfolioID int64, targets []models.AllocationTarget, base *float64, threshold float64) (*models.RebalancePlan, error) {
+	positions, err := s.positions.ListByPortfolio(ctx, portfolioID, models.PositionOpen)
+	if err != nil {
+		return nil, err
+	}
+
+	var targetTickers []string
+	for _, t := range targets {
+		targetTickers = append(targetTickers, t.Tickers...)
+	}
+	quotes, err := s.FetchQuotes(ctx, positions, targetTickers...)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	greeks := AggregateGreeks(positions, quotes, nil, s.riskFreeRate, now)
+	deltas := make(map[int64]*float64, len(greeks.Positions))
+	for _, pg := range greeks.Positions {
+		deltas[pg.PositionID] = pg.Delta
+	}
+
+	legs, incomplete := rebalanceLegs(positions, deltas, quotes.Stocks)
+	allocationBase := 0.0
+	if base != nil {
+		allocationBase = *base
+	} else {
+		for _, exposure := range rebalance.Exposures(legs, quotes.Stocks) {
+			allocationBase += exposure
+		}
+	}
+
+	plan, err := rebalance.Plan(targets, legs, quotes.Stocks, allocationBase, threshold)
+	if err != nil {
+		return nil, err
+	}
+	plan.PortfolioID = portfolioID
+	plan.Incomplete = incomplete
+	plan.ValuedAt = now
+
+	drifted := 0
+	for _, t := range plan.Targets {
+		if t.Rebalance {
+			drifted++
+		}
+	}
+	log.Printf("[ValuationService] ✓ Rebalance plan for portfolio %d: %d targets, %d beyond %.1f%% drift",
+		portfolioID, len(plan.Targets), drifted, threshold)
+	return plan, nil
+}
+
+// rebalanceLegs pairs open positions with their deltas, counting those without a delta or
+// an underlying price as incomplete
+func rebalanceLegs(positions []models.Position, deltas map[int64]*float64, prices map[string]float64) ([]rebalance.Leg, int) {
+	legs := make([]rebalance.Leg, 0, len(positions))
+	incomplete := 0
+	for _, p := range positions {
+		delta := deltas[p.ID]
+		if _, ok := prices[p.UnderlyingTicker]; !ok || delta == nil {
+			incomplete++
+			continue
+		}
+		legs = append(legs, rebalance.Leg{
+			PositionID: p.ID,
+			Ticker:     p.Ticker,
+			Underlying: p.UnderlyingTicker,
+			Option:     p.AssetType == models.AssetTypeOption,
+			Side:       p.Side,
+			Quantity:   p.Quantity,
+			Delta:      *delta,
+		})
+	}
+	return legs, incomplete
+}
@@ -0,0 +1,249 @@
+package services
+
+import (
+	"context"
+	"log"
+	"math"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/analytics"
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/pricing"
+)
+
+// tradingDaysPerYear scales annualized volatility to the VaR horizon
+const tradingDaysPerYear = 252.0
+
+// varLevels are the confidence levels VaR is reported at, with their one-tailed z-scores
+var varLevels = []struct {
+	confidence float64
+	z          float64
+}{
+	{0.95, 1.6449},
+	{0.99, 2.3263},
+}
+
+// stressScenarios are the shocks applied to the open positions
+var stressScenarios = []models.StressScenario{
+	{Name: "spot -10%", SpotShock: -0.10},
+	{Name: "spot -5%", SpotShock: -0.05},
+	{Name: "spot +5%", SpotShock: 0.05},
+	{Name: "spot +10%", SpotShock: 0.10},
+	{Name: "iv -20%", IVShock: -0.20},
+	{Name: "iv +20%", IVShock: 0.20},
+}
+
+// PortfolioRisk refreshes live snapshots for a portfolio's open positions and reports its
+// beta-weighted exposure, parametric VaR over horizonDays trading days and stress test P/L
+func (s *ValuationService) PortfolioRisk(ctx context.Context, portfolioID int64, horizonDays int) (*models.PortfolioRisk, error) {
+	positions, err := s.positions.ListByPortfolio(ctx, portfolioID, models.PositionOpen)
+	if err != nil {
+		return nil, err
+	}
+
+	quotes, err := s.FetchQuotes(ctx, positions, BenchmarkTicker)
+	if err != nil {
+		return nil, err
+	}
+
+	tickers := underlyingTickers(positions)
+	betas := s.betas.Betas(ctx, tickers)
+	vols := s.betas.Volatilities(ctx, append(tickers, BenchmarkTicker))
+
+	risk := AssessRisk(positions, quotes, betas, vols, s.riskFreeRate, horizonDays, time.Now())
+	risk.PortfolioID = portfolioID
+
+	log.Printf("[ValuationService] ✓ Assessed risk for portfolio %d: %d underlyings, %d excluded from VaR",
+		portfolioID, len(risk.Underlyings), risk.Excluded)
+	return risk, nil
+}
+
+// AssessRisk computes risk metrics for open positions. Each underlying's volatility is the
+// average implied volatility of its option legs, or its historical volatility when it has
+// none. VaR is delta-normal under a single-index model — underlyings co-move through their
+// beta to the benchmark and are otherwise independent — less the expected gamma and theta
+// P/L over the horizon. Stress scenarios fully reprice option legs with Black-Scholes.
+func AssessRisk(positions []models.Position, quotes *Quotes, betas, historicalVols map[string]float64, rate float64, horizonDays int, now time.Time) *models.PortfolioRisk {
+	greeks := AggregateGreeks(positions, quotes, betas, rate, now)
+	risk := &models.PortfolioRisk{
+		Benchmark:         BenchmarkTicker,
+		BenchmarkPrice:    greeks.BenchmarkPrice,
+		BetaWeightedDelta: greeks.BetaWeightedDelta,
+		BetaWeightedGamma: greeks.BetaWeightedGamma,
+		VaR:               []models.ValueAtRisk{},
+		Scenarios:         []models.StressScenario{},
+		Underlyings:       []models.UnderlyingExposure{},
+		AsOf:              now,
+	}
+	if greeks.BenchmarkPrice != nil {
+		risk.BetaWeightedDollarDelta = greeks.BetaWeightedDelta * *greeks.BenchmarkPrice
+	}
+	if vol, ok := historicalVols[BenchmarkTicker]; ok {
+		risk.BenchmarkVolatility = &vol
+	}
+
+	risk.Underlyings = exposures(positions, quotes, greeks, historicalVols)
+	risk.VaR, risk.Excluded = parametricVaR(risk.Underlyings, risk.BenchmarkVolatility, horizonDays)
+
+	for _, scenario := range stressScenarios {
+		risk.Scenarios = append(risk.Scenarios, stress(scenario, positions, quotes, rate, now))
+	}
+	return risk
+}
+
+// exposures groups position greeks by underlying and attaches each underlying's volatility
+func exposures(positions []models.Position, quotes *Quotes, greeks *models.PortfolioGreeks, historicalVols map[string]float64) []models.UnderlyingExposure {
+	byID := make(map[int64]*models.Position, len(positions))
+	for i := range positions {
+		byID[positions[i].ID] = &positions[i]
+	}
+
+	index := make(map[string]int)
+	var result []models.UnderlyingExposure
+	gamma := make(map[string]float64)
+	ivSum := make(map[string]float64)
+	ivWeight := make(map[string]float64)
+
+	for _, pg := range greeks.Positions {
+		i, ok := index[pg.UnderlyingTicker]
+		if !ok {
+			i = len(result)
+			index[pg.UnderlyingTicker] = i
+			result = append(result, models.UnderlyingExposure{Ticker: pg.UnderlyingTicker})
+		}
+		e := &result[i]
+		e.Positions++
+		if e.Price == nil {
+			e.Price = pg.UnderlyingPrice
+		}
+		if e.Beta == nil {
+			e.Beta = pg.Beta
+		}
+		if pg.Delta != nil {
+			e.Delta += *pg.Delta
+		}
+		if pg.Gamma != nil {
+			gamma[e.Ticker] += *pg.Gamma
+		}
+		if pg.Theta != nil {
+			e.Theta += *pg.Theta
+		}
+		if pg.Vega != nil {
+			e.Vega += *pg.Vega
+		}
+
+		// Weight each leg's implied volatility by its size in shares
+		if p := byID[pg.PositionID]; p != nil && p.IsOption() {
+			if c, ok := quotes.Contracts[p.Ticker]; ok && c.ImpliedVol != nil && *c.ImpliedVol > 0 {
+				weight := p.Quantity * float64(p.Multiplier)
+				ivSum[e.Ticker] += *c.ImpliedVol * weight
+				ivWeight[e.Ticker] += weight
+			}
+		}
+	}
+
+	for i := range result {
+		e := &result[i]
+		if e.Price != nil {
+			e.DollarDelta = e.Delta * *e.Price
+			e.DollarGamma = gamma[e.Ticker] * *e.Price * *e.Price
+		}
+		if ivWeight[e.Ticker] > 0 {
+			vol := ivSum[e.Ticker] / ivWeight[e.Ticker]
+			e.Volatility = &vol
+			e.VolSource = models.VolFromImplied
+		} else if vol, ok := historicalVols[e.Ticker]; ok {
+			e.Volatility = &vol
+			e.VolSource = models.VolFromHistorical
+		}
+	}
+	if result == nil {
+		result = []models.UnderlyingExposure{}
+	}
+	return result
+}
+
+// parametricVaR returns VaR at each reported confidence level and the number of
+// underlyings left out for lack of a price or volatility. Underlyings without a beta, or
+// any underlying when the benchmark volatility is unknown, are treated as independent.
+func parametricVaR(underlyings []models.UnderlyingExposure, benchmarkVol *float64, horizonDays int) ([]models.ValueAtRisk, int) {
+	horizon := float64(horizonDays) / tradingDaysPerYear
+
+	var market, idiosyncratic, drift float64
+	excluded := 0
+	for _, e := range underlyings {
+		if e.Price == nil || e.Volatility == nil {
+			excluded++
+			continue
+		}
+
+		variance := *e.Volatility * *e.Volatility * horizon
+		drift += 0.5*e.DollarGamma*variance + e.Theta*float64(horizonDays)
+
+		if e.Beta != nil && benchmarkVol != nil {
+			market += e.DollarDelta * *e.Beta
+			systematic := *e.Beta * *e.Beta * *benchmarkVol * *benchmarkVol * horizon
+			idiosyncratic += e.DollarDelta * e.DollarDelta * math.Max(variance-systematic, 0)
+		} else {
+			idiosyncratic += e.DollarDelta * e.DollarDelta * variance
+		}
+	}
+
+	var marketVariance float64
+	if benchmarkVol != nil {
+		marketVariance = market * market * *benchmarkVol * *benchmarkVol * horizon
+	}
+	stdDev := math.Sqrt(marketVariance + idiosyncratic)
+
+	levels := make([]models.ValueAtRisk, 0, len(varLevels))
+	for _, level := range varLevels {
+		levels = append(levels, models.ValueAtRisk{
+			Confidence:  level.confidence,
+			HorizonDays: horizonDays,
+			VaR:         math.Max(level.z*stdDev-drift, 0),
+			StdDev:      stdDev,
+			Drift:       drift,
+		})
+	}
+	return levels, excluded
+}
+
+// stress returns the P/L of the open positions under a scenario. Option legs are repriced
+// with the model from their implied volatility; legs without pricing inputs fall back to
+// a delta-gamma estimate for spot shocks and are otherwise counted as unpriced.
+func stress(scenario models.StressScenario, positions []models.Position, quotes *Quotes, rate float64, now time.Time) models.StressScenario {
+	for i := range positions {
+		p := &positions[i]
+		if p.Status != models.PositionOpen {
+			continue
+		}
+		units := p.SignedQuantity() * float64(p.Multiplier)
+		spot := quotes.UnderlyingPrice(p)
+
+		if !p.IsOption() {
+			if spot == nil {
+				scenario.Unpriced++
+				continue
+			}
+			scenario.PnL += units * *spot * scenario.SpotShock
+			continue
+		}
+
+		if c, ok := quotes.Contracts[p.Ticker]; ok {
+			if in, ok := analytics.PricingInputs(c, rate, now); ok {
+				shocked := shiftInputs(in, scenario.SpotShock, scenario.IVShock, 0)
+				scenario.PnL += units * (pricing.Price(shocked) - pricing.Price(in))
+				continue
+			}
+		}
+
+		unit, _ := unitGreeks(p, quotes, rate, now)
+		if scenario.IVShock != 0 || spot == nil || unit.delta == nil || unit.gamma == nil {
+			scenario.Unpriced++
+			continue
+		}
+		move := *spot * scenario.SpotShock
+		scenario.PnL += units * (*unit.delta*move + 0.5**unit.gamma*move*move)
+	}
+	return scenario
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/analytics"
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/massive"
+)
+
+// rollStrikeRange limits roll candidates to strikes within this fraction of the underlying
+const rollStrikeRange = 0.25
+
+// RollParams narrows the roll scan
+type RollParams struct {
+	MaxDTE     int  // latest candidate expiration, in days from today
+	Limit      int  // candidates returned
+	CreditOnly bool // drop rolls that cost a net debit
+}
+
+// RollService suggests rolls for short option legs from the live chain
+type RollService struct {
+	massiveClient *massive.Client
+	chains        *ChainService
+	riskFreeRate  float64
+}
+
+// NewRollService creates a new roll suggestion service
+func NewRollService(massiveClient *massive.Client, chains *ChainService, riskFreeRate float64) *RollService {
+	return &RollService{
+		massiveClient: massiveClient,
+		chains:        chains,
+		riskFreeRate:  riskFreeRate,
+	}
+}
+
+// Suggest scans the chain of a short option leg's underlying for contracts of the same
+// type expiring no earlier and ranks them as roll candidates
+func (s *RollService) Suggest(ctx context.Context, p *models.Position, params RollParams) (*models.RollSuggestions, error) {
+	contracts, err := s.massiveClient.GetContractDetails(ctx, []string{p.Ticker})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch option snapshot: %w", err)
+	}
+	var current *models.OptionContract
+	if len(contracts) > 0 {
+		current = &contracts[0]
+	}
+
+	chain, err := s.chains.GetSnapshot(ctx, p.UnderlyingTicker, &massive.OptionsChainParams{ContractType: p.ContractType})
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := RankRolls(p, current, chain, params, s.riskFreeRate, time.Now())
+	log.Printf("[RollService] ✓ Ranked %d roll candidates for position %d (%s), threatened: %v",
+		len(suggestions.Candidates), p.ID, p.Ticker, suggestions.Threatened)
+	return suggestions, nil
+}
+
+// RankRolls scores every contract in the chain that the leg could roll into: same type,
+// a different contract expiring on or after the leg and within params.MaxDTE, a strike
+// within rollStrikeRange of the underlying and a price. Candidates are ranked by score,
+// then by net credit.
+func RankRolls(p *models.Position, current *models.OptionContract, chain *ChainSnapshot, params RollParams, rate float64, now time.Time) *models.RollSuggestions {
+	suggestions := &models.RollSuggestions{
+		PositionID:       p.ID,
+		Ticker:           p.Ticker,
+		UnderlyingTicker: p.UnderlyingTicker,
+		Quantity:         p.Quantity,
+		UnderlyingPrice:  chain.Spot,
+		Candidates:       []models.RollCandidate{},
+		AsOf:             now,
+	}
+	if p.ContractType != nil {
+		suggestions.ContractType = *p.ContractType
+	}
+	if p.StrikePrice != nil {
+		suggestions.StrikePrice = *p.StrikePrice
+	}
+	if p.ExpirationDate != nil {
+		suggestions.ExpirationDate = *p.ExpirationDate
+		suggestions.DTE, _ = analytics.DaysToExpiration(*p.ExpirationDate, now)
+	}
+	if current != nil {
+		suggestions.ClosePrice = analytics.ContractPrice(current)
+		suggestions.Delta = analytics.ContractDelta(current, rate, now)
+	}
+	suggestions.Threats = analytics.RollThreats(suggestions.ContractType, suggestions.StrikePrice, chain.Spot, suggestions.Delta, suggestions.DTE)
+	suggestions.Threatened = len(suggestions.Threats) > 0
+
+	var closePrice float64
+	if suggestions.ClosePrice != nil {
+		closePrice = *suggestions.ClosePrice
+	}
+	maxAddedDays := params.MaxDTE - suggestions.DTE
+	units := p.Quantity * float64(p.Multiplier)
+
+	for i := range chain.Contracts {
+		c := &chain.Contracts[i]
+		d := c.Details
+		if d == nil || d.Ticker == nil || d.StrikePrice == nil || d.ExpirationDate == nil || d.ContractType == nil {
+			continue
+		}
+		if *d.Ticker == p.Ticker || *d.ContractType != suggestions.ContractType || *d.ExpirationDate < suggestions.ExpirationDate {
+			continue
+		}
+		if math.Abs(*d.StrikePrice-chain.Spot) > rollStrikeRange*chain.Spot {
+			continue
+		}
+		dte, err := analytics.DaysToExpiration(*d.ExpirationDate, now)
+		if err != nil || dte <= 0 || dte > params.MaxDTE {
+			continue
+		}
+		price := analytics.ContractPrice(c)
+		if price == nil || *price <= 0 {
+			continue
+		}
+
+		candidate := models.RollCandidate{
+			Ticker:         *d.Ticker,
+			ExpirationDate: *d.ExpirationDate,
+			StrikePrice:    *d.StrikePrice,
+			DTE:            dte,
+			Price:          *price,
+			Delta:          analytics.ContractDelta(c, rate, now),
+			NetCredit:      *price - closePrice,
+			AddedDays:      dte - suggestions.DTE,
+			LiquidityScore: analytics.LiquidityScore(c),
+		}
+		if params.CreditOnly && candidate.NetCredit < 0 {
+			continue
+		}
+		candidate.NetCreditTotal = candidate.NetCredit * units
+		if suggestions.Delta != nil && candidate.Delta != nil {
+			reduction := math.Abs(*suggestions.Delta) - math.Abs(*candidate.Delta)
+			candidate.DeltaReduction = &reduction
+		}
+		candidate.Score = analytics.RollScore(&candidate, chain.Spot, maxAddedDays)
+		suggestions.Candidates = append(suggestions.Candidates, candidate)
+	}
+
+	sort.SliceStable(suggestions.Candidates, func(i, j int) bool {
+		a, b := suggestions.Candidates[i], suggestions.Candidates[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return a.NetCredit > b.NetCredit
+	})
+	if params.Limit > 0 && len(suggestions.Candidates) > params.Limit {
+		suggestions.Candidates = suggestions.Candidates[:params.Limit]
+	}
+	return suggestions
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+)
+
+// Rollup values several portfolios against one batch of live quotes and combines their
+// totals and greeks into an all-accounts view
+func (s *ValuationService) Rollup(ctx context.Context, portfolios []models.Portfolio) (*models.PortfolioRollup, error) {
+	now := time.Now()
+	rollup := &models.PortfolioRollup{
+		Portfolios: []models.PortfolioSummary{},
+		ValuedAt:   now,
+	}
+
+	byPortfolio, open, quotes, err := s.quotePortfolios(ctx, portfolios)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range portfolios {
+		valuation := Valuate(byPortfolio[p.ID], quotes)
+		rollup.Portfolios = append(rollup.Portfolios, models.PortfolioSummary{
+			Portfolio: p,
+			Totals:    valuation.ValuationTotals,
+		})
+		rollup.Totals.Add(valuation.ValuationTotals)
+	}
+
+	betas := s.betas.Betas(ctx, underlyingTickers(open))
+	rollup.Greeks = AggregateGreeks(open, quotes, betas, s.riskFreeRate, now)
+	rollup.Greeks.Positions = nil
+
+	log.Printf("[ValuationService] ✓ Rolled up %d portfolios with %d open positions", len(portfolios), len(open))
+	return rollup, nil
+}
+
+// quotePortfolios loads the positions of several portfolios, grouped by portfolio, and one
+// batch of quotes covering all of their open positions and the benchmark
+func (s *ValuationService) quotePortfolios(ctx context.Context, portfolios []models.Portfolio) (map[int64][]models.Position, []models.Position, *Quotes, error) {
+	ids := make([]int64, len(portfolios))
+	for i, p := range portfolios {
+		ids[i] = p.ID
+	}
+
+	positions, err := s.positions.ListByPortfolios(ctx, ids, "")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	byPortfolio := make(map[int64][]models.Position, len(portfolios))
+	var open []models.Position
+	for _, p := range positions {
+		byPortfolio[p.PortfolioID] = append(byPortfolio[p.PortfolioID], p)
+		if p.Status == models.PositionOpen {
+			open = append(open, p)
+		}
+	}
+
+	quotes, err := s.FetchQuotes(ctx, open, BenchmarkTicker)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return byPortfolio, open, quotes, nil
+}
@@ -0,0 +1,268 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/analytics"
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/pricing"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+)
+
+// seedChainTicker is the underlying whose chain the seed records into the snapshot history
+const seedChainTicker = "SPY"
+
+// seedUser is a sample user and what they own. A user without an ID stands for the data
+// without an owner, which is what requests see when auth is disabled.
+type seedUser struct {
+	id         string
+	email      string
+	portfolios []seedPortfolio
+	watchlists []seedWatchlist
+}
+
+type seedPortfolio struct {
+	name        string
+	description string
+	accountType string
+	positions   []seedPosition
+}
+
+// seedPosition is a sample trade. Options are given by their underlying, an index into the
+// sample chain's expirations and a strike as a fraction of spot, and open at the sample price
+// times priceFactor; shares open at price. A position with closedDaysAgo set is closed then
+// at closePrice.
+type seedPosition struct {
+	ticker        string
+	contractType  pricing.OptionType // empty for shares
+	expiration    int
+	moneyness     float64
+	priceFactor   float64
+	side          string
+	quantity      float64
+	price         float64
+	openedDaysAgo int
+	closedDaysAgo int
+	closePrice    float64
+}
+
+type seedWatchlist struct {
+	name    string
+	tickers []string
+}
+
+// seedBrokerage and seedRetirement are the first sample user's portfolios, also seeded
+// without an owner
+var (
+	seedBrokerage = seedPortfolio{
+		name:        "Sample Brokerage",
+		description: "Shares, a covered call and a long call",
+		accountType: models.AccountTaxable,
+		positions: []seedPosition{
+			{ticker: "SPY", side: models.SideLong, quantity: 200, price: 541.20, openedDaysAgo: 120},
+			{ticker: "SPY", contractType: pricing.Call, expiration: 5, moneyness: 1.04, priceFactor: 1.25,
+				side: models.SideShort, quantity: 2, openedDaysAgo: 12},
+			{ticker: "AAPL", side: models.SideLong, quantity: 50, price: 212.35, openedDaysAgo: 90},
+			{ticker: "NVDA", contractType: pricing.Call, expiration: 6, moneyness: 1.05, priceFactor: 0.85,
+				side: models.SideLong, quantity: 1, openedDaysAgo: 20},
+			{ticker: "TSLA", side: models.SideLong, quantity: 20, price: 238.10, openedDaysAgo: 60,
+				closedDaysAgo: 25, closePrice: 262.45},
+		},
+	}
+	seedRetirement = seedPortfolio{
+		name:        "Sample Roth IRA",
+		description: "Index funds and a cash-secured put",
+		accountType: models.AccountRothIRA,
+		positions: []seedPosition{
+			{ticker: "QQQ", side: models.SideLong, quantity: 30, price: 468.90, openedDaysAgo: 200},
+			{ticker: "IWM", contractType: pricing.Put, expiration: 4, moneyness: 0.95, priceFactor: 1.1,
+				side: models.SideShort, quantity: 1, openedDaysAgo: 8},
+		},
+	}
+	seedWatchlists = []seedWatchlist{
+		{name: "Mega Caps", tickers: []string{"AAPL", "MSFT", "NVDA", "AMZN"}},
+		{name: "Index ETFs", tickers: []string{"SPY", "QQQ", "IWM"}},
+	}
+)
+
+// seedUsers are the sample users and their data
+var seedUsers = []seedUser{
+	{portfolios: []seedPortfolio{seedBrokerage, seedRetirement}, watchlists: seedWatchlists},
+	{id: "00000000-0000-4000-8000-00000000a11c", email: "alice@example.com",
+		portfolios: []seedPortfolio{seedBrokerage, seedRetirement}, watchlists: seedWatchlists},
+	{id: "00000000-0000-4000-8000-000000000b0b", email: "bob@example.com",
+		portfolios: []seedPortfolio{{
+			name:        "Sample Paper Account",
+			description: "A protective put on SPY shares",
+			accountType: models.AccountPaper,
+			positions: []seedPosition{
+				{ticker: "SPY", side: models.SideLong, quantity: 100, price: 566.80, openedDaysAgo: 30},
+				{ticker: "SPY", contractType: pricing.Put, expiration: 6, moneyness: 0.95, priceFactor: 1.05,
+					side: models.SideLong, quantity: 1, openedDaysAgo: 30},
+			},
+		}},
+		watchlists: []seedWatchlist{{name: "High Volatility", tickers: []string{"TSLA", "NVDA"}}}},
+}
+
+// SeedService loads sample users, portfolios, watchlists and a chain snapshot into the
+// database, so local development and demos have data without a Massive key. Everything
+// seeded is generated sample data, not market data.
+type SeedService struct {
+	users        *repository.UserRepository
+	portfolios   *repository.PortfolioRepository
+	transactions *repository.TransactionRepository
+	watchlists   *repository.WatchlistRepository
+	snapshots    *repository.OptionsSnapshotRepository
+	riskFreeRate float64
+}
+
+// NewSeedService creates a new seed service
+func NewSeedService(users *repository.UserRepository, portfolios *repository.PortfolioRepository,
+	transactions *repository.TransactionRepository, watchlists *repository.WatchlistRepository,
+	snapshots *repository.OptionsSnapshotRepository, riskFreeRate float64) *SeedService {
+	return &SeedService{
+		users:        users,
+		portfolios:   portfolios,
+		transactions: transactions,
+		watchlists:   watchlists,
+		snapshots:    snapshots,
+		riskFreeRate: riskFreeRate,
+	}
+}
+
+// Run seeds the sample data as of now. It can be run again: portfolios and watchlists that
+// already exist by name are left as they are, and the chain is recorded only if the history
+// has no capture of it yet.
+func (s *SeedService) Run(ctx context.Context, now time.Time) error {
+	for _, u := range seedUsers {
+		var owner *string
+		if u.id != "" {
+			id, email := u.id, u.email
+			if _, err := s.users.Touch(ctx, id, &email, now); err != nil {
+				return fmt.Errorf("failed to seed user %s: %w", email, err)
+			}
+			owner = &id
+		}
+		for _, p := range u.portfolios {
+			if err := s.seedPortfolio(ctx, owner, p, now); err != nil {
+				return err
+			}
+		}
+		for _, w := range u.watchlists {
+			watchlist := &models.Watchlist{UserID: owner, Name: w.name}
+			err := s.watchlists.Create(ctx, watchlist, w.tickers)
+			if errors.Is(err, repository.ErrDuplicate) {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("failed to seed watchlist %q: %w", w.name, err)
+			}
+		}
+	}
+	return s.seedChain(ctx, now)
+}
+
+// seedPortfolio creates a portfolio and records its trades, unless the owner already has a
+// portfolio of that name
+func (s *SeedService) seedPortfolio(ctx context.Context, owner *string, p seedPortfolio, now time.Time) error {
+	description := p.description
+	portfolio := &models.Portfolio{UserID: owner, Name: p.name, Description: &description,
+		Settings: models.DefaultPortfolioSettings()}
+	portfolio.Settings.AccountType = p.accountType
+	err := s.portfolios.Create(ctx, portfolio)
+	if errors.Is(err, repository.ErrDuplicate) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to seed portfolio %q: %w", p.name, err)
+	}
+
+	for _, sp := range p.positions {
+		position := seedPositionAt(portfolio.ID, sp, now, s.riskFreeRate)
+		if _, err := s.transactions.Open(ctx, position, 0); err != nil {
+			return fmt.Errorf("failed to seed %s position: %w", position.Ticker, err)
+		}
+		if sp.closedDaysAgo == 0 {
+			continue
+		}
+		trade := repository.Trade{Quantity: sp.quantity, Price: sp.closePrice, TradedAt: seedDate(now, sp.closedDaysAgo)}
+		if _, _, err := s.transactions.Close(ctx, portfolio.ID, position.ID, trade); err != nil {
+			return fmt.Errorf("failed to seed %s position: %w", position.Ticker, err)
+		}
+	}
+	log.Printf("[Seed] ✓ Seeded portfolio %q with %d positions", p.name, len(p.positions))
+	return nil
+}
+
+// seedChain records the sample SPY chain as a capture of the snapshot history
+func (s *SeedService) seedChain(ctx context.Context, now time.Time) error {
+	_, err := s.snapshots.LatestBefore(ctx, seedChainTicker, now)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+
+	underlying := sampleUnderlying(seedChainTicker)
+	capturedAt := now.UTC().Truncate(time.Second)
+	chain := SampleChain(seedChainTicker, underlying.spot, underlying.vol, s.riskFreeRate, now)
+	contracts := make([]models.OptionsSnapshotContract, 0, len(chain))
+	for i := range chain {
+		if row, ok := models.NewOptionsSnapshotContract(seedChainTicker, capturedAt, &chain[i]); ok {
+			contracts = append(contracts, row)
+		}
+	}
+	snapshot := &models.OptionsSnapshot{Ticker: seedChainTicker, CapturedAt: capturedAt, Spot: underlying.spot}
+	if err := s.snapshots.Create(ctx, snapshot, contracts); err != nil {
+		return fmt.Errorf("failed to seed %s chain: %w", seedChainTicker, err)
+	}
+	log.Printf("[Seed] ✓ Recorded sample %s chain (%d contracts)", seedChainTicker, len(contracts))
+	return nil
+}
+
+// seedPositionAt builds a sample position, pricing options off the sample chain as of now
+func seedPositionAt(portfolioID int64, sp seedPosition, now time.Time, rate float64) *models.Position {
+	position := &models.Position{
+		PortfolioID:      portfolioID,
+		AssetType:        models.AssetTypeStock,
+		Ticker:           sp.ticker,
+		UnderlyingTicker: sp.ticker,
+		Side:             sp.side,
+		Quantity:         sp.quantity,
+		Multiplier:       1,
+		OpenPrice:        sp.price,
+		OpenedAt:         seedDate(now, sp.openedDaysAgo),
+	}
+	if sp.contractType == "" {
+		return position
+	}
+
+	underlying := sampleUnderlying(sp.ticker)
+	expiration := sampleExpirations(now)[sp.expiration]
+	step := sampleStrikeStep(underlying.spot)
+	strike := math.Round(underlying.spot*sp.moneyness/step) * step
+	dte, _ := analytics.DaysToExpiration(expiration, now)
+	years := math.Max(analytics.YearFraction(dte), 0.5/365)
+	contract := sampleContract(sp.ticker, expiration, sp.contractType, strike, underlying.spot, underlying.vol, rate, years)
+
+	contractType := string(sp.contractType)
+	position.AssetType = models.AssetTypeOption
+	position.Ticker = *contract.Details.Ticker
+	position.ContractType = &contractType
+	position.StrikePrice = &strike
+	position.ExpirationDate = &expiration
+	position.Multiplier = *contract.Details.SharesPerContract
+	position.OpenPrice = roundCents(*contract.LastTrade.Price * sp.priceFactor)
+	return position
+}
+
+// seedDate returns the market date daysAgo days before now, as YYYY-MM-DD
+func seedDate(now time.Time, daysAgo int) string {
+	return analytics.MarketDate(now).AddDate(0, 0, -daysAgo).Format("2006-01-02")
+}
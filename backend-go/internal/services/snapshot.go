@@ -0,0 +1,44 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+)
+
+// Snapshots values each portfolio against one batch of live quotes and returns its totals
+// and greeks for the given trading day (YYYY-MM-DD)
+func (s *ValuationService) Snapshots(ctx context.Context, portfolios []models.Portfolio, date string) ([]models.PortfolioSnapshot, error) {
+	byPortfolio, open, quotes, err := s.quotePortfolios(ctx, portfolios)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	betas := s.betas.Betas(ctx, underlyingTickers(open))
+
+	snapshots := make([]models.PortfolioSnapshot, 0, len(portfolios))
+	for _, p := range portfolios {
+		positions := byPortfolio[p.ID]
+		valuation := Valuate(positions, quotes)
+		greeks := AggregateGreeks(positions, quotes, betas, s.riskFreeRate, now)
+
+		snapshots = append(snapshots, models.PortfolioSnapshot{
+			PortfolioID:       p.ID,
+			Date:              date,
+			ValuationTotals:   valuation.ValuationTotals,
+			Delta:             greeks.Delta,
+			DollarDelta:       greeks.DollarDelta,
+			Gamma:             greeks.Gamma,
+			Theta:             greeks.Theta,
+			Vega:              greeks.Vega,
+			BetaWeightedDelta: greeks.BetaWeightedDelta,
+			BetaWeightedGamma: greeks.BetaWeightedGamma,
+		})
+	}
+
+	log.Printf("[ValuationService] ✓ Snapshotted %d portfolios with %d open positions for %s", len(portfolios), len(open), date)
+	return snapshots, nil
+}
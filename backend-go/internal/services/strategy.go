@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+)
+
+// StrategySummaries values the legs of each strategy against one batch of live quotes and
+// reports strategy-level totals and net greeks. Closed legs still count toward realized P/L.
+func (s *ValuationService) StrategySummaries(ctx context.Context, portfolioID int64, strategies []models.Strategy) ([]models.StrategySummary, error) {
+	byStrategy, open, quotes, err := s.quoteStrategies(ctx, portfolioID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	betas := s.betas.Betas(ctx, underlyingTickers(open))
+	summaries := make([]models.StrategySummary, 0, len(strategies))
+	for _, strategy := range strategies {
+		legs := byStrategy[strategy.ID]
+		greeks := AggregateGreeks(openPositions(legs), quotes, betas, s.riskFreeRate, now)
+		greeks.PortfolioID = portfolioID
+		greeks.Positions = nil
+		summaries = append(summaries, models.StrategySummary{
+			Strategy: strategy,
+			Totals:   Valuate(legs, quotes).ValuationTotals,
+			Greeks:   greeks,
+		})
+	}
+
+	log.Printf("[ValuationService] ✓ Valued %d strategies in portfolio %d", len(strategies), portfolioID)
+	return summaries, nil
+}
+
+// ValueStrategy marks a strategy's legs to market and aggregates their greeks
+func (s *ValuationService) ValueStrategy(ctx context.Context, strategy *models.Strategy) (*models.StrategyValuation, error) {
+	byStrategy, _, quotes, err := s.quoteStrategies(ctx, strategy.PortfolioID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	legs := byStrategy[strategy.ID]
+	open := openPositions(legs)
+	valuation := Valuate(legs, quotes)
+	greeks := AggregateGreeks(open, quotes, s.betas.Betas(ctx, underlyingTickers(open)), s.riskFreeRate, now)
+	greeks.PortfolioID = strategy.PortfolioID
+
+	log.Printf("[ValuationService] ✓ Valued strategy %d (%s): %d open legs, %d unpriced",
+		strategy.ID, strategy.Name, valuation.OpenPositions, valuation.Unpriced)
+	return &models.StrategyValuation{
+		Strategy:        *strategy,
+		ValuationTotals: valuation.ValuationTotals,
+		Positions:       valuation.Positions,
+		Greeks:          greeks,
+		ValuedAt:        now,
+	}, nil
+}
+
+// quoteStrategies loads a portfolio's grouped positions by strategy and one batch of quotes
+// covering their open legs and the benchmark
+func (s *ValuationService) quoteStrategies(ctx context.Context, portfolioID int64) (map[int64][]models.Position, []models.Position, *Quotes, error) {
+	positions, err := s.positions.ListByPortfolio(ctx, portfolioID, "")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	byStrategy := make(map[int64][]models.Position)
+	var open []models.Position
+	for _, p := range positions {
+		if p.StrategyID == nil {
+			continue
+		}
+		byStrategy[*p.StrategyID] = append(byStrategy[*p.StrategyID], p)
+		if p.Status == models.PositionOpen {
+			open = append(open, p)
+		}
+	}
+
+	quotes, err := s.FetchQuotes(ctx, open, BenchmarkTicker)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return byStrategy, open, quotes, nil
+}
+
+// openPositions returns the open positions among positions
+func openPositions(positions []models.Position) []models.Position {
+	var open []models.Position
+	for _, p := range positions {
+		if p.Status == models.PositionOpen {
+			open = append(open, p)
+		}
+	}
+	return open
+}
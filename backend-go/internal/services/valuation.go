@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/analytics"
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/massive"
+)
+
+// Quotes holds live market data for a set of positions
+type Quotes struct {
+	Contracts map[string]*models.OptionContract // option snapshots by OCC ticker
+	Stocks    map[string]float64                // share prices by ticker, including option underlyings
+}
+
+// UnderlyingPrice returns the underlying price of a position, preferring the
+// option snapshot's underlying and falling back to the stock snapshot
+func (q *Quotes) UnderlyingPrice(p *models.Position) *float64 {
+	if p.IsOption() {
+		if c, ok := q.Contracts[p.Ticker]; ok && c.UnderlyingAsset != nil && c.UnderlyingAsset.Price != nil && *c.UnderlyingAsset.Price > 0 {
+			return c.UnderlyingAsset.Price
+		}
+	}
+	if price, ok := q.Stocks[p.UnderlyingTicker]; ok {
+		return &price
+	}
+	return nil
+}
+
+// MarkPrice returns the current per-unit price of a position, or nil when unpriced
+func (q *Quotes) MarkPrice(p *models.Position) *float64 {
+	if p.IsOption() {
+		if c, ok := q.Contracts[p.Ticker]; ok {
+			return analytics.ContractPrice(c)
+		}
+		return nil
+	}
+	if price, ok := q.Stocks[p.Ticker]; ok {
+		return &price
+	}
+	return nil
+}
+
+// ValuationService marks portfolio positions to market and aggregates their greeks
+// using live snapshots
+type ValuationService struct {
+	massiveClient *massive.Client
+	positions     *repository.PositionRepository
+	betas         *BetaService
+	riskFreeRate  float64
+}
+
+// NewValuationService creates a new valuation service
+func NewValuationService(massiveClient *massive.Client, positions *repository.PositionRepository, betas *BetaService, riskFreeRate float64) *ValuationService {
+	return &ValuationService{
+		massiveClient: massiveClient,
+		positions:     positions,
+		betas:         betas,
+		riskFreeRate:  riskFreeRate,
+	}
+}
+
+// FetchQuotes loads option snapshots for the option legs and share prices for stock
+// positions, option underlyings and any extra tickers in as few batched requests as possible
+func (s *ValuationService) FetchQuotes(ctx context.Context, positions []models.Position, extraTickers ...string) (*Quotes, error) {
+	var contractTickers, stockTickers []string
+	seen := make(map[string]bool)
+	for _, ticker := range extraTickers {
+		if !seen[ticker] {
+			seen[ticker] = true
+			stockTickers = append(stockTickers, ticker)
+		}
+	}
+	for i := range positions {
+		p := &positions[i]
+		if p.IsOption() && !seen[p.Ticker] {
+			seen[p.Ticker] = true
+			contractTickers = append(contractTickers, p.Ticker)
+		}
+		if !seen[p.UnderlyingTicker] {
+			seen[p.UnderlyingTicker] = true
+			stockTickers = append(stockTickers, p.UnderlyingTicker)
+		}
+	}
+
+	quotes := &Quotes{Contracts: make(map[string]*models.OptionContract, len(contractTickers))}
+
+	contracts, err := s.massiveClient.GetContractDetails(ctx, contractTickers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch option snapshots: %w", err)
+	}
+	for i := range contracts {
+		if details := contracts[i].Details; details != nil && details.Ticker != nil {
+			quotes.Contracts[*details.Ticker] = &contracts[i]
+		}
+	}
+
+	quotes.Stocks, err = s.massiveClient.GetStockPrices(ctx, stockTickers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch stock prices: %w", err)
+	}
+
+	return quotes, nil
+}
+
+// ValuePortfolio marks a portfolio's open positions to market and totals its unrealized
+// P/L together with the realized P/L and fees recorded in the trade ledger
+func (s *ValuationService) ValuePortfolio(ctx context.Context, portfolioID int64) (*models.PortfolioValuation, error) {
+	positions, err := s.positions.ListByPortfolio(ctx, portfolioID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var open []models.Position
+	for _, p := range positions {
+		if p.Status == models.PositionOpen {
+			open = append(open, p)
+		}
+	}
+
+	quotes, err := s.FetchQuotes(ctx, open)
+	if err != nil {
+		return nil, err
+	}
+
+	valuation := Valuate(positions, quotes)
+	valuation.PortfolioID = portfolioID
+	valuation.ValuedAt = time.Now()
+
+	log.Printf("[ValuationService] ✓ Valued portfolio %d: %d open positions, %d unpriced",
+		portfolioID, valuation.OpenPositions, valuation.Unpriced)
+	return valuation, nil
+}
+
+// Valuate marks open positions against the quotes and sums realized P/L, dividends and
+// fees across every position. Unpriced positions are listed but excluded from the totals.
+func Valuate(positions []models.Position, quotes *Quotes) *models.PortfolioValuation {
+	valuation := &models.PortfolioValuation{Positions: []models.PositionValuation{}}
+
+	for i := range positions {
+		p := &positions[i]
+		valuation.RealizedPnL += p.RealizedPnL
+		valuation.Dividends += p.Dividends
+		valuation.Fees += p.Fees
+		if p.Status != models.PositionOpen {
+			continue
+		}
+		valuation.OpenPositions++
+
+		pv := models.PositionValuation{
+			Position:        *p,
+			MarkPrice:       quotes.MarkPrice(p),
+			UnderlyingPrice: quotes.UnderlyingPrice(p),
+			CostBasis:       p.CostBasis(),
+		}
+		if pv.MarkPrice == nil {
+			valuation.Unpriced++
+			valuation.Positions = append(valuation.Positions, pv)
+			continue
+		}
+
+		marketValue := p.SignedQuantity() * *pv.MarkPrice * float64(p.Multiplier)
+		unrealized := marketValue - pv.CostBasis
+		pv.MarketValue = &marketValue
+		pv.UnrealizedPnL = &unrealized
+		if pv.CostBasis != 0 {
+			pct := unrealized / math.Abs(pv.CostBasis) * 100
+			pv.UnrealizedPnLPercent = &pct
+		}
+
+		valuation.MarketValue += marketValue
+		valuation.CostBasis += pv.CostBasis
+		valuation.UnrealizedPnL += unrealized
+		valuation.Positions = append(valuation.Positions, pv)
+	}
+
+	valuation.TotalPnL = valuation.RealizedPnL + valuation.UnrealizedPnL + valuation.Dividends
+	return valuation
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/analytics"
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/pkg/massive"
+)
+
+// Watchlist IV settings
+const (
+	watchlistIVMaturityDays = 30
+	watchlistIVWorkers      = 4 // concurrent chain fetches; the client's rate limiter still applies
+)
+
+// WatchlistService quotes the tickers of a watchlist
+type WatchlistService struct {
+	massiveClient *massive.Client
+}
+
+// NewWatchlistService creates a new watchlist service
+func NewWatchlistService(massiveClient *massive.Client) *WatchlistService {
+	return &WatchlistService{
+		massiveClient: massiveClient,
+	}
+}
+
+// Quotes fetches price and day change for every stock of the watchlist in one batched
+// snapshot request, and quotes, greeks and day stats for every option contract in another.
+// With withIV it also fetches each priced stock's option chain for its 30-day ATM IV;
+// stocks whose chain cannot be fetched are quoted without it.
+func (s *WatchlistService) Quotes(ctx context.Context, w *models.Watchlist, withIV bool) (*models.WatchlistQuotes, error) {
+	result := &models.WatchlistQuotes{
+		WatchlistID: w.ID,
+		Quotes:      []models.WatchlistQuote{},
+		Contracts:   []models.WatchlistContractQuote{},
+		Unpriced:    []string{},
+		FetchedAt:   time.Now(),
+	}
+
+	stocks := w.Tickers(models.AssetTypeStock)
+	snapshots, err := s.massiveClient.GetStockSnapshots(ctx, stocks)
+	if err != nil {
+		return nil, err
+	}
+	for _, ticker := range stocks {
+		stock, ok := snapshots[ticker]
+		if !ok {
+			result.Unpriced = append(result.Unpriced, ticker)
+			continue
+		}
+		result.Quotes = append(result.Quotes, stockQuote(ticker, &stock))
+	}
+
+	contracts := w.Tickers(models.AssetTypeOption)
+	details, err := s.massiveClient.GetContractDetails(ctx, contracts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch option snapshots: %w", err)
+	}
+	byTicker := make(map[string]*models.OptionContract, len(details))
+	for i := range details {
+		if d := details[i].Details; d != nil && d.Ticker != nil {
+			byTicker[*d.Ticker] = &details[i]
+		}
+	}
+	for _, ticker := range contracts {
+		contract, ok := byTicker[ticker]
+		symbol, err := models.ParseOptionTicker(ticker)
+		if !ok || err != nil {
+			result.Unpriced = append(result.Unpriced, ticker)
+			continue
+		}
+		result.Contracts = append(result.Contracts, contractQuote(ticker, symbol, contract))
+	}
+
+	if withIV {
+		s.fillATMIV(ctx, result.Quotes, result.FetchedAt)
+	}
+	return result, nil
+}
+
+// stockQuote flattens a stock snapshot, which has session data, into a watchlist quote
+func stockQuote(ticker string, stock *massive.StockResult) models.WatchlistQuote {
+	return models.WatchlistQuote{
+		Ticker:        ticker,
+		Name:          stock.Name,
+		Price:         stock.Price(),
+		PreviousClose: stock.Session.PreviousClose,
+		Change:        stock.Session.Change,
+		ChangePercent: stock.Session.ChangePercent,
+		Open:          stock.Session.Open,
+		High:          stock.Session.High,
+		Low:           stock.Session.Low,
+	}
+}
+
+// contractQuote flattens a contract snapshot into a watchlist quote
+func contractQuote(ticker string, symbol *models.OptionSymbol, c *models.OptionContract) models.WatchlistContractQuote {
+	q := models.WatchlistContractQuote{
+		Ticker:            ticker,
+		Underlying:        symbol.Underlying,
+		ContractType:      symbol.ContractType,
+		StrikePrice:       symbol.StrikePrice,
+		ExpirationDate:    symbol.ExpirationDate,
+		Mark:              analytics.ContractPrice(c),
+		ImpliedVolatility: c.ImpliedVol,
+		OpenInterest:      c.OpenInterest,
+		Greeks:            c.Greeks,
+		Day:               c.Session,
+	}
+	if c.LastQuote != nil {
+		q.Bid, q.Ask = c.LastQuote.Bid, c.LastQuote.Ask
+	}
+	if c.LastTrade != nil {
+		q.Last = c.LastTrade.Price
+	}
+	if c.UnderlyingAsset != nil {
+		q.UnderlyingPrice = c.UnderlyingAsset.Price
+	}
+	return q
+}
+
+// fillATMIV sets the ATM IV of each priced quote, fetching chains a few at a time
+func (s *WatchlistService) fillATMIV(ctx context.Context, quotes []models.WatchlistQuote, now time.Time) {
+	jobs := make(chan *models.WatchlistQuote)
+	var wg sync.WaitGroup
+	for range watchlistIVWorkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for q := range jobs {
+				chain, err := s.massiveClient.GetOptionsChain(ctx, q.Ticker, &massive.OptionsChainParams{})
+				if err != nil {
+					log.Printf("[WatchlistService] ⚠ No chain for %s, quoting without IV: %v", q.Ticker, err)
+					continue
+				}
+				q.ATMIV = analytics.ConstantMaturityIV(chain.Results, *q.Price, watchlistIVMaturityDays, now)
+			}
+		}()
+	}
+
+	for i := range quotes {
+		if quotes[i].Price == nil {
+			continue
+		}
+		select {
+		case jobs <- &quotes[i]:
+		case <-ctx.Done():
+		}
+	}
+	close(jobs)
+	wg.Wait()
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+	"github.com/aaronbengochea/periscope/backend-go/internal/repository"
+	"github.com/aaronbengochea/periscope/backend-go/internal/webhook"
+)
+
+// Webhook delivery settings
+const (
+	webhookBatchSize   = 50
+	webhookTimeout     = 10 * time.Second
+	webhookLease       = 2 * time.Minute // claimed deliveries are retried after this if a worker dies
+	webhookMaxAttempts = 8
+	webhookBaseBackoff = 30 * time.Second
+	webhookMaxBackoff  = time.Hour
+)
+
+// WebhookService posts queued portfolio events to their webhooks, retrying failed
+// deliveries with exponential backoff
+type WebhookService struct {
+	webhooks *repository.WebhookRepository
+	client   *http.Client
+}
+
+// NewWebhookService creates a new webhook delivery service
+func NewWebhookService(webhooks *repository.WebhookRepository) *WebhookService {
+	return &WebhookService{
+		webhooks: webhooks,
+		client:   webhook.NewClient(webhookTimeout),
+	}
+}
+
+// DeliverDue sends every delivery that is due, one batch at a time, and returns how many
+// were delivered
+func (s *WebhookService) DeliverDue(ctx context.Context) (int, error) {
+	delivered := 0
+	for {
+		pending, err := s.webhooks.ClaimDue(ctx, webhookBatchSize, webhookLease)
+		if err != nil {
+			return delivered, err
+		}
+		for i := range pending {
+			ok, err := s.deliver(ctx, &pending[i])
+			if err != nil {
+				return delivered, err
+			}
+			if ok {
+				delivered++
+			}
+		}
+		if len(pending) < webhookBatchSize {
+			return delivered, nil
+		}
+	}
+}
+
+// deliver posts one delivery and records the outcome, reporting whether it was accepted
+func (s *WebhookService) deliver(ctx context.Context, p *models.PendingDelivery) (bool, error) {
+	d := &p.Delivery
+	status, sendErr := s.send(ctx, p)
+
+	var retryAt *time.Time
+	if sendErr != nil && d.Attempts+1 < webhookMaxAttempts {
+		next := time.Now().Add(Backoff(d.Attempts + 1))
+		retryAt = &next
+	}
+	if err := s.webhooks.RecordAttempt(ctx, d, status, sendErr, retryAt); err != nil {
+		return false, err
+	}
+
+	switch {
+	case sendErr == nil:
+		log.Printf("[WebhookService] ✓ Delivered %s %d to webhook %d", d.Event, d.ID, d.WebhookID)
+	case retryAt != nil:
+		log.Printf("[WebhookService] ⚠ Delivery %d to webhook %d failed (attempt %d), retrying at %s: %v",
+			d.ID, d.WebhookID, d.Attempts+1, retryAt.Format(time.RFC3339), sendErr)
+	default:
+		log.Printf("[WebhookService] ✗ Delivery %d to webhook %d failed after %d attempts: %v",
+			d.ID, d.WebhookID, d.Attempts+1, sendErr)
+	}
+	return sendErr == nil, nil
+}
+
+// send posts the signed event envelope, returning the response status when one was
+// received
+func (s *WebhookService) send(ctx context.Context, p *models.PendingDelivery) (*int, error) {
+	d := &p.Delivery
+	body, err := json.Marshal(models.WebhookEvent{
+		ID:          d.ID,
+		Event:       d.Event,
+		PortfolioID: p.PortfolioID,
+		CreatedAt:   d.CreatedAt,
+		Data:        d.Payload,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode webhook event: %w", err)
+	}
+	return webhook.Post(ctx, s.client, p.URL, p.Secret, d.Event, d.ID, body)
+}
+
+// Backoff returns the wait before retrying a delivery that failed attempt times: 30s
+// doubling on each attempt, capped at an hour
+func Backoff(attempt int) time.Duration {
+	wait := webhookBaseBackoff
+	for i := 1; i < attempt && wait < webhookMaxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, webhookMaxBackoff)
+}
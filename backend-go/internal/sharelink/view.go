@@ -0,0 +1,69 @@
+package sharelink
+
+import (
+	"github.com/aaronbengochea/periscope/backend-go/internal/models"
+)
+
+// View builds the read-only view a link exposes from a portfolio valuation. Internal IDs
+// are dropped, and open prices, cost basis, P/L, dividends and fees are redacted when the
+// link hides cost basis.
+func View(p *models.Portfolio, v *models.PortfolioValuation, link *models.ShareLink) *models.SharedPortfolio {
+	show := !link.HideCostBasis
+	reveal := func(x float64) *float64 {
+		if !show {
+			return nil
+		}
+		return &x
+	}
+	revealPtr := func(x *float64) *float64 {
+		if !show {
+			return nil
+		}
+		return x
+	}
+
+	view := &models.SharedPortfolio{
+		Name:          p.Name,
+		Description:   p.Description,
+		HideCostBasis: link.HideCostBasis,
+		Totals: models.SharedTotals{
+			MarketValue:   v.MarketValue,
+			CostBasis:     reveal(v.CostBasis),
+			UnrealizedPnL: reveal(v.UnrealizedPnL),
+			RealizedPnL:   reveal(v.RealizedPnL),
+			Dividends:     reveal(v.Dividends),
+			TotalPnL:      reveal(v.TotalPnL),
+			Fees:          reveal(v.Fees),
+			OpenPositions: v.OpenPositions,
+			Unpriced:      v.Unpriced,
+		},
+		Positions: make([]models.SharedPosition, 0, len(v.Positions)),
+		ValuedAt:  v.ValuedAt,
+		ExpiresAt: link.ExpiresAt,
+	}
+
+	for i := range v.Positions {
+		pv := &v.Positions[i]
+		pos := &pv.Position
+		view.Positions = append(view.Positions, models.SharedPosition{
+			AssetType:            pos.AssetType,
+			Ticker:               pos.Ticker,
+			UnderlyingTicker:     pos.UnderlyingTicker,
+			ContractType:         pos.ContractType,
+			StrikePrice:          pos.StrikePrice,
+			ExpirationDate:       pos.ExpirationDate,
+			Side:                 pos.Side,
+			Quantity:             pos.Quantity,
+			Multiplier:           pos.Multiplier,
+			OpenedAt:             pos.OpenedAt,
+			MarkPrice:            pv.MarkPrice,
+			UnderlyingPrice:      pv.UnderlyingPrice,
+			MarketValue:          pv.MarketValue,
+			OpenPrice:            reveal(pos.OpenPrice),
+			CostBasis:            reveal(pv.CostBasis),
+			UnrealizedPnL:        revealPtr(pv.UnrealizedPnL),
+			UnrealizedPnLPercent: revealPtr(pv.UnrealizedPnLPercent),
+		})
+	}
+	return view
+}
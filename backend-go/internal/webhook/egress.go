@@ -0,0 +1,90 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// dialTimeout bounds connecting to a webhook host
+const dialTimeout = 5 * time.Second
+
+// ErrPrivateAddress is returned for webhook hosts that are, or resolve to, addresses
+// Periscope does not send to: loopback, private, link-local and unspecified ones
+var ErrPrivateAddress = errors.New("webhook host is not a public address")
+
+// sharedAddressSpace is the carrier-grade NAT range, which some clouds put their metadata
+// service in
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// Public reports whether ip is an address webhook requests may be sent to
+func Public(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	return ip.IsValid() &&
+		!ip.IsLoopback() &&
+		!ip.IsPrivate() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast() &&
+		!ip.IsUnspecified() &&
+		!sharedAddressSpace.Contains(ip)
+}
+
+// NewClient returns an HTTP client for user-supplied URLs. Its dialer checks every address
+// after DNS resolution, so neither a redirect nor a host that resolves differently at send
+// time than when it was registered can reach an internal service. Proxies from the
+// environment are not used, since they would connect on the client's behalf.
+func NewClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: dialTimeout, Control: dialControl}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+// dialControl rejects connections to addresses that are not public
+func dialControl(_, address string, _ syscall.RawConn) error {
+	addr, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("unexpected dial address %q: %w", address, err)
+	}
+	if !Public(addr.Addr()) {
+		return fmt.Errorf("%w: %s", ErrPrivateAddress, addr.Addr())
+	}
+	return nil
+}
+
+// CheckURL rejects a URL whose host is, or resolves to, an address that is not public, so
+// such webhooks are refused when they are registered rather than failing every delivery.
+// The client from NewClient checks again on every request.
+func CheckURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", err)
+	}
+	host := u.Hostname()
+	if ip, err := netip.ParseAddr(host); err == nil {
+		if !Public(ip) {
+			return fmt.Errorf("%w: %s", ErrPrivateAddress, ip)
+		}
+		return nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", host, err)
+	}
+	for _, addr := range addrs {
+		if !Public(addr) {
+			return fmt.Errorf("%w: %s resolves to %s", ErrPrivateAddress, host, addr)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPublic(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"8.8.8.8", true},
+		{"2606:4700:4700::1111", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"::ffff:127.0.0.1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.10", false},
+		{"fc00::1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"100.100.100.200", false},
+		{"224.0.0.1", false},
+	}
+	for _, tt := range tests {
+		if got := Public(netip.MustParseAddr(tt.ip)); got != tt.want {
+			t.Errorf("Public(%s) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+}
+
+func TestCheckURL(t *testing.T) {
+	for _, rawURL := range []string{
+		"http://127.0.0.1:8080/hook",
+		"http://localhost/hook",
+		"http://[::1]/hook",
+		"http://169.254.169.254/latest/meta-data/",
+		"https://10.0.0.5/hook",
+	} {
+		if err := CheckURL(context.Background(), rawURL); !errors.Is(err, ErrPrivateAddress) {
+			t.Errorf("CheckURL(%s) = %v, want ErrPrivateAddress", rawURL, err)
+		}
+	}
+	if err := CheckURL(context.Background(), "https://93.184.215.14/hook"); err != nil {
+		t.Errorf("CheckURL of a public address: %v", err)
+	}
+}
+
+func TestClientRefusesPrivateAddresses(t *testing.T) {
+	reached := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached = true
+	}))
+	defer srv.Close()
+
+	_, err := Post(context.Background(), NewClient(time.Second), srv.URL, "whsec_test", "ping", 1, []byte(`{}`))
+	if !errors.Is(err, ErrPrivateAddress) {
+		t.Errorf("got %v, want ErrPrivateAddress", err)
+	}
+	if reached {
+		t.Error("request reached a loopback server")
+	}
+}
+
+func TestPostKeepsNoBodyFromPrivatePeers(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "internal credentials", http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	status, err := Post(context.Background(), srv.Client(), srv.URL, "whsec_test", "ping", 1, []byte(`{}`))
+	if status == nil || *status != http.StatusForbidden {
+		t.Fatalf("got status %v, want 403", status)
+	}
+	if err == nil || strings.Contains(err.Error(), "credentials") {
+		t.Errorf("got %v, want the status without the body", err)
+	}
+}
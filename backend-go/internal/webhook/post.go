@@ -0,0 +1,55 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"net/netip"
+	"time"
+)
+
+// errorExcerpt is how much of a failed response body is kept in the error
+const errorExcerpt = 200
+
+// Post sends a signed JSON body to url as one delivery of an event, returning the response
+// status when one was received. Any 2xx response counts as delivered. The error keeps an
+// excerpt of a failed response's body only when it came from a public address, since the
+// owner of the webhook can read it back.
+func Post(ctx context.Context, client *http.Client, url, secret, event string, deliveryID int64, body []byte) (*int, error) {
+	var peer netip.Addr
+	ctx = httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			addr, _ := netip.ParseAddrPort(info.Conn.RemoteAddr().String())
+			peer = addr.Addr()
+		},
+	})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Periscope-Webhooks/1.0")
+	req.Header.Set(EventHeader, event)
+	req.Header.Set(DeliveryHeader, fmt.Sprint(deliveryID))
+	req.Header.Set(SignatureHeader, Sign(secret, time.Now(), body))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	status := resp.StatusCode
+	if status < 200 || status >= 300 {
+		if !Public(peer) {
+			return &status, fmt.Errorf("endpoint responded %d", status)
+		}
+		excerpt, _ := io.ReadAll(io.LimitReader(resp.Body, errorExcerpt))
+		return &status, fmt.Errorf("endpoint responded %d: %s", status, bytes.TrimSpace(excerpt))
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	return &status, nil
+}
@@ -0,0 +1,41 @@
+// Package webhook signs and sends outbound webhook requests so receivers can check they
+// came from Periscope and were not replayed
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Headers set on every webhook request
+const (
+	SignatureHeader = "X-Periscope-Signature"
+	EventHeader     = "X-Periscope-Event"
+	DeliveryHeader  = "X-Periscope-Delivery"
+)
+
+// NewSecret returns a random signing secret for a new webhook
+func NewSecret() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// Sign returns the signature header value for a body sent at t: "t=<unix seconds>,v1=<hex
+// HMAC-SHA256 of "<unix seconds>.<body>" keyed with the secret>". Receivers recompute the
+// HMAC and reject stale timestamps.
+func Sign(secret string, t time.Time, body []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,15 @@
+-- Daily constant-maturity ATM implied volatility per underlying
+-- Feeds IV rank / IV percentile analytics
+CREATE TABLE IF NOT EXISTS iv_history (
+  ticker TEXT NOT NULL,
+  observed_on DATE NOT NULL,
+  atm_iv NUMERIC(10, 6) NOT NULL,
+  underlying_price NUMERIC(12, 4),
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW(),
+  PRIMARY KEY (ticker, observed_on)
+);
+
+CREATE INDEX IF NOT EXISTS idx_iv_history_ticker_date ON iv_history(ticker, observed_on DESC);
+
+COMMENT ON TABLE iv_history IS 'Daily 30-day constant-maturity ATM implied volatility per underlying';
@@ -0,0 +1,12 @@
+-- Portfolios group positions and trades
+CREATE TABLE IF NOT EXISTS portfolios (
+  id BIGSERIAL PRIMARY KEY,
+  name TEXT NOT NULL CHECK (char_length(name) BETWEEN 1 AND 100),
+  description TEXT,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_portfolios_created_at ON portfolios(created_at DESC);
+
+COMMENT ON TABLE portfolios IS 'User portfolios containing option and share positions';
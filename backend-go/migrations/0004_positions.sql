@@ -0,0 +1,28 @@
+-- Positions: option legs and share lots held in a portfolio
+CREATE TABLE IF NOT EXISTS positions (
+  id BIGSERIAL PRIMARY KEY,
+  portfolio_id BIGINT NOT NULL REFERENCES portfolios(id) ON DELETE CASCADE,
+  asset_type TEXT NOT NULL CHECK (asset_type IN ('option', 'stock')),
+  ticker TEXT NOT NULL,
+  underlying_ticker TEXT NOT NULL,
+  contract_type TEXT CHECK (contract_type IN ('call', 'put')),
+  strike_price NUMERIC(12, 4),
+  expiration_date DATE,
+  side TEXT NOT NULL CHECK (side IN ('long', 'short')),
+  quantity NUMERIC(18, 4) NOT NULL CHECK (quantity > 0),
+  multiplier INTEGER NOT NULL DEFAULT 1 CHECK (multiplier > 0),
+  open_price NUMERIC(12, 4) NOT NULL CHECK (open_price >= 0),
+  opened_at DATE NOT NULL DEFAULT CURRENT_DATE,
+  status TEXT NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'closed')),
+  close_price NUMERIC(12, 4),
+  closed_at DATE,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW(),
+  CHECK (asset_type = 'stock' OR (contract_type IS NOT NULL AND strike_price IS NOT NULL AND expiration_date IS NOT NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_positions_portfolio ON positions(portfolio_id, status);
+CREATE INDEX IF NOT EXISTS idx_positions_ticker ON positions(ticker);
+CREATE INDEX IF NOT EXISTS idx_positions_expiration ON positions(expiration_date) WHERE status = 'open';
+
+COMMENT ON TABLE positions IS 'Option legs and share lots held in a portfolio';
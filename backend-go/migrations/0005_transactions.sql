@@ -0,0 +1,98 @@
+-- Trade ledger: every fill against a position, the FIFO lots it opens and the lots each close consumes
+
+-- Positions keep running totals maintained by the ledger. quantity now tracks the open
+-- quantity and reaches zero once a position is fully closed.
+ALTER TABLE positions ADD COLUMN IF NOT EXISTS fees NUMERIC(14, 4) NOT NULL DEFAULT 0;
+ALTER TABLE positions ADD COLUMN IF NOT EXISTS realized_pnl NUMERIC(18, 4) NOT NULL DEFAULT 0;
+ALTER TABLE positions DROP CONSTRAINT IF EXISTS positions_quantity_check;
+ALTER TABLE positions ADD CONSTRAINT positions_quantity_check
+  CHECK (quantity > 0 OR (quantity = 0 AND status = 'closed'));
+
+CREATE TABLE IF NOT EXISTS transactions (
+  id BIGSERIAL PRIMARY KEY,
+  portfolio_id BIGINT NOT NULL REFERENCES portfolios(id) ON DELETE CASCADE,
+  position_id BIGINT NOT NULL REFERENCES positions(id) ON DELETE CASCADE,
+  action TEXT NOT NULL CHECK (action IN ('open', 'add', 'close', 'roll_out', 'roll_in')),
+  quantity NUMERIC(18, 4) NOT NULL CHECK (quantity > 0),
+  price NUMERIC(12, 4) NOT NULL CHECK (price >= 0),
+  fees NUMERIC(14, 4) NOT NULL DEFAULT 0 CHECK (fees >= 0),
+  amount NUMERIC(18, 4) NOT NULL, -- signed cash flow net of fees (negative = paid)
+  realized_pnl NUMERIC(18, 4),    -- set on closing actions
+  related_transaction_id BIGINT REFERENCES transactions(id) ON DELETE SET NULL,
+  traded_at DATE NOT NULL DEFAULT CURRENT_DATE,
+  created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_portfolio ON transactions(portfolio_id, traded_at DESC);
+CREATE INDEX IF NOT EXISTS idx_transactions_position ON transactions(position_id);
+
+CREATE TABLE IF NOT EXISTS position_lots (
+  id BIGSERIAL PRIMARY KEY,
+  position_id BIGINT NOT NULL REFERENCES positions(id) ON DELETE CASCADE,
+  transaction_id BIGINT NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+  quantity NUMERIC(18, 4) NOT NULL CHECK (quantity > 0),
+  remaining_quantity NUMERIC(18, 4) NOT NULL CHECK (remaining_quantity >= 0 AND remaining_quantity <= quantity),
+  price NUMERIC(12, 4) NOT NULL CHECK (price >= 0),
+  fees NUMERIC(14, 4) NOT NULL DEFAULT 0 CHECK (fees >= 0),
+  opened_at DATE NOT NULL,
+  created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_position_lots_open ON position_lots(position_id, opened_at, id) WHERE remaining_quantity > 0;
+
+CREATE TABLE IF NOT EXISTS lot_closures (
+  id BIGSERIAL PRIMARY KEY,
+  transaction_id BIGINT NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+  lot_id BIGINT NOT NULL REFERENCES position_lots(id) ON DELETE CASCADE,
+  quantity NUMERIC(18, 4) NOT NULL CHECK (quantity > 0),
+  open_value NUMERIC(18, 4) NOT NULL,  -- opening cash of the closed quantity, fees included
+  close_value NUMERIC(18, 4) NOT NULL, -- closing cash of the closed quantity, fees included
+  realized_pnl NUMERIC(18, 4) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_lot_closures_transaction ON lot_closures(transaction_id);
+CREATE INDEX IF NOT EXISTS idx_lot_closures_lot ON lot_closures(lot_id);
+
+-- Backfill: give every existing position an opening transaction and a single lot
+WITH opened AS (
+  INSERT INTO transactions (portfolio_id, position_id, action, quantity, price, amount, traded_at)
+  SELECT p.portfolio_id, p.id, 'open', p.quantity, p.open_price,
+    CASE WHEN p.side = 'long' THEN -1 ELSE 1 END * p.quantity * p.open_price * p.multiplier,
+    p.opened_at
+  FROM positions p
+  WHERE NOT EXISTS (SELECT 1 FROM transactions t WHERE t.position_id = p.id)
+  RETURNING id, position_id, quantity, price, traded_at
+)
+INSERT INTO position_lots (position_id, transaction_id, quantity, remaining_quantity, price, opened_at)
+SELECT o.position_id, o.id, o.quantity,
+  CASE WHEN p.status = 'open' THEN o.quantity ELSE 0 END,
+  o.price, o.traded_at
+FROM opened o
+JOIN positions p ON p.id = o.position_id;
+
+-- Backfill: record the close of already-closed positions against their lot
+WITH closed AS (
+  INSERT INTO transactions (portfolio_id, position_id, action, quantity, price, amount, realized_pnl, traded_at)
+  SELECT p.portfolio_id, p.id, 'close', p.quantity, COALESCE(p.close_price, 0),
+    CASE WHEN p.side = 'long' THEN 1 ELSE -1 END * p.quantity * COALESCE(p.close_price, 0) * p.multiplier,
+    CASE WHEN p.side = 'long' THEN 1 ELSE -1 END * p.quantity * (COALESCE(p.close_price, 0) - p.open_price) * p.multiplier,
+    COALESCE(p.closed_at, p.opened_at)
+  FROM positions p
+  WHERE p.status = 'closed' AND p.quantity > 0
+    AND NOT EXISTS (SELECT 1 FROM transactions t WHERE t.position_id = p.id AND t.action = 'close')
+  RETURNING id, position_id, quantity, price, realized_pnl
+)
+INSERT INTO lot_closures (transaction_id, lot_id, quantity, open_value, close_value, realized_pnl)
+SELECT c.id, l.id, c.quantity, l.quantity * l.price * p.multiplier, c.quantity * c.price * p.multiplier, c.realized_pnl
+FROM closed c
+JOIN positions p ON p.id = c.position_id
+JOIN position_lots l ON l.position_id = c.position_id;
+
+UPDATE positions p
+SET realized_pnl = t.realized_pnl, quantity = 0
+FROM transactions t
+WHERE t.position_id = p.id AND t.action = 'close' AND p.status = 'closed' AND p.quantity > 0;
+
+COMMENT ON TABLE transactions IS 'Trade ledger: every open, add, close and roll fill with fees';
+COMMENT ON TABLE position_lots IS 'FIFO cost basis lots opened by ledger transactions';
+COMMENT ON TABLE lot_closures IS 'Lots consumed by each closing transaction and the P/L realized on them';
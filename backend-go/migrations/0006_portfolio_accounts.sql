@@ -0,0 +1,23 @@
+-- Portfolio accounts: owner, per-portfolio settings and unique names per user
+
+ALTER TABLE portfolios ADD COLUMN IF NOT EXISTS user_id UUID;
+ALTER TABLE portfolios ADD COLUMN IF NOT EXISTS account_type TEXT NOT NULL DEFAULT 'taxable'
+  CHECK (account_type IN ('taxable', 'ira', 'roth_ira', 'margin', 'paper', 'other'));
+ALTER TABLE portfolios ADD COLUMN IF NOT EXISTS include_in_rollup BOOLEAN NOT NULL DEFAULT TRUE;
+
+-- Disambiguate existing duplicate names before enforcing uniqueness
+UPDATE portfolios p
+SET name = left(p.name, 80) || ' (' || p.id || ')'
+WHERE EXISTS (
+  SELECT 1 FROM portfolios q
+  WHERE q.id < p.id
+    AND q.user_id IS NOT DISTINCT FROM p.user_id
+    AND lower(q.name) = lower(p.name)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_portfolios_user_name
+  ON portfolios (COALESCE(user_id, '00000000-0000-0000-0000-000000000000'::uuid), lower(name));
+CREATE INDEX IF NOT EXISTS idx_portfolios_user ON portfolios(user_id, created_at DESC);
+
+COMMENT ON COLUMN portfolios.user_id IS 'Owning Supabase auth user; NULL for portfolios created without authentication';
+COMMENT ON COLUMN portfolios.include_in_rollup IS 'Whether the portfolio counts toward the all-accounts rollup';
@@ -0,0 +1,31 @@
+-- End-of-day portfolio valuations for performance history
+-- Written by the daily snapshot job after the market close; one row per portfolio per trading day
+CREATE TABLE IF NOT EXISTS portfolio_snapshots (
+  portfolio_id BIGINT NOT NULL REFERENCES portfolios(id) ON DELETE CASCADE,
+  snapshot_date DATE NOT NULL,
+
+  -- Valuation (market_value is the net liquidation value of priced open positions)
+  market_value NUMERIC(16, 4) NOT NULL,
+  cost_basis NUMERIC(16, 4) NOT NULL,
+  unrealized_pnl NUMERIC(16, 4) NOT NULL,
+  realized_pnl NUMERIC(16, 4) NOT NULL,
+  total_pnl NUMERIC(16, 4) NOT NULL,
+  fees NUMERIC(14, 4) NOT NULL,
+  open_positions INTEGER NOT NULL,
+  unpriced INTEGER NOT NULL,
+
+  -- Greeks (delta and gamma in share equivalents; beta-weighted in SPY share equivalents)
+  delta NUMERIC(16, 4) NOT NULL,
+  dollar_delta NUMERIC(16, 4) NOT NULL,
+  gamma NUMERIC(16, 6) NOT NULL,
+  theta NUMERIC(16, 4) NOT NULL,
+  vega NUMERIC(16, 4) NOT NULL,
+  beta_weighted_delta NUMERIC(16, 4) NOT NULL,
+  beta_weighted_gamma NUMERIC(16, 6) NOT NULL,
+
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW(),
+  PRIMARY KEY (portfolio_id, snapshot_date)
+);
+
+COMMENT ON TABLE portfolio_snapshots IS 'Daily end-of-day portfolio equity, P/L and greeks';
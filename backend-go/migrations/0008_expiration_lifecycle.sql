@@ -0,0 +1,11 @@
+-- Expiration lifecycle: option legs closed by expiration, assignment or exercise
+-- Share deliveries from assignments and exercises are ordinary open/add/close transactions
+-- that reference the option's assign/exercise transaction
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_action_check;
+ALTER TABLE transactions ADD CONSTRAINT transactions_action_check
+  CHECK (action IN ('open', 'add', 'close', 'roll_out', 'roll_in', 'expire', 'assign', 'exercise'));
+
+ALTER TABLE positions ADD COLUMN IF NOT EXISTS close_reason TEXT
+  CHECK (close_reason IN ('expired', 'assigned', 'exercised'));
+
+COMMENT ON COLUMN positions.close_reason IS 'Set when an option leg was closed by its expiration rather than a trade';
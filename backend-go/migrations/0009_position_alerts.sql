@@ -0,0 +1,29 @@
+-- Alert rules attached to positions, e.g. "delta > 0.40" on a short put or "pnl_percent < -50"
+-- Evaluated during market hours against live contract snapshots; a rule that fires stays
+-- triggered until it is re-armed
+CREATE TABLE IF NOT EXISTS position_alerts (
+  id BIGSERIAL PRIMARY KEY,
+  portfolio_id BIGINT NOT NULL REFERENCES portfolios(id) ON DELETE CASCADE,
+  position_id BIGINT NOT NULL REFERENCES positions(id) ON DELETE CASCADE,
+  metric TEXT NOT NULL CHECK (metric IN (
+    'delta', 'gamma', 'theta', 'vega', 'implied_volatility', 'mark_price',
+    'underlying_price', 'pnl', 'pnl_percent', 'days_to_expiration')),
+  operator TEXT NOT NULL CHECK (operator IN ('>', '>=', '<', '<=')),
+  threshold NUMERIC(18, 6) NOT NULL,
+  note TEXT,
+  status TEXT NOT NULL DEFAULT 'armed' CHECK (status IN ('armed', 'triggered', 'disabled')),
+
+  -- Latest evaluation and the value that fired the rule
+  last_value NUMERIC(18, 6),
+  last_evaluated_at TIMESTAMPTZ,
+  triggered_value NUMERIC(18, 6),
+  triggered_at TIMESTAMPTZ,
+
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_position_alerts_portfolio ON position_alerts(portfolio_id, position_id);
+CREATE INDEX IF NOT EXISTS idx_position_alerts_armed ON position_alerts(position_id) WHERE status = 'armed';
+
+COMMENT ON TABLE position_alerts IS 'Alert rules on position greeks, prices and P/L';
@@ -0,0 +1,27 @@
+-- Cash dividends earned (or owed, when short) by share positions
+-- Written by the daily dividend job from the shares each position held at the ex-dividend
+-- date; announced dividends become paid on their pay date
+CREATE TABLE IF NOT EXISTS dividends (
+  id BIGSERIAL PRIMARY KEY,
+  portfolio_id BIGINT NOT NULL REFERENCES portfolios(id) ON DELETE CASCADE,
+  position_id BIGINT NOT NULL REFERENCES positions(id) ON DELETE CASCADE,
+  ticker TEXT NOT NULL,
+  ex_dividend_date DATE NOT NULL,
+  pay_date DATE,
+  cash_amount NUMERIC(12, 6) NOT NULL, -- per share
+  frequency INTEGER NOT NULL DEFAULT 0, -- payments per year, 0 for one-time
+  quantity NUMERIC(18, 4) NOT NULL,     -- signed shares held at the ex-dividend date (negative when short)
+  amount NUMERIC(16, 4) NOT NULL,       -- signed cash: received when long, paid when short
+  status TEXT NOT NULL CHECK (status IN ('announced', 'paid')),
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW(),
+  UNIQUE (position_id, ex_dividend_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_dividends_portfolio ON dividends(portfolio_id, ex_dividend_date DESC);
+
+-- Running total of paid dividends, included in position and portfolio P/L
+ALTER TABLE positions ADD COLUMN IF NOT EXISTS dividends NUMERIC(16, 4) NOT NULL DEFAULT 0;
+ALTER TABLE portfolio_snapshots ADD COLUMN IF NOT EXISTS dividends NUMERIC(16, 4) NOT NULL DEFAULT 0;
+
+COMMENT ON TABLE dividends IS 'Cash dividends earned or owed by share positions';
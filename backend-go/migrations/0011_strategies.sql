@@ -0,0 +1,21 @@
+-- Named strategies grouping option legs and share lots, e.g. "AAPL iron condor Jan"
+-- A position belongs to at most one strategy; deleting a strategy ungroups its legs
+CREATE TABLE IF NOT EXISTS strategies (
+  id BIGSERIAL PRIMARY KEY,
+  portfolio_id BIGINT NOT NULL REFERENCES portfolios(id) ON DELETE CASCADE,
+  name TEXT NOT NULL CHECK (length(name) BETWEEN 1 AND 200),
+  tags TEXT[] NOT NULL DEFAULT '{}',
+  notes TEXT,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW(),
+  UNIQUE (portfolio_id, name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_strategies_tags ON strategies USING GIN (tags);
+
+ALTER TABLE positions ADD COLUMN IF NOT EXISTS strategy_id BIGINT REFERENCES strategies(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_positions_strategy ON positions(strategy_id) WHERE strategy_id IS NOT NULL;
+
+COMMENT ON TABLE strategies IS 'Named, tagged groups of positions valued as one strategy';
+COMMENT ON COLUMN positions.strategy_id IS 'Strategy the position is grouped into; rolled legs keep it';
@@ -0,0 +1,22 @@
+-- Read-only share links for portfolios
+-- The link token is signed by the API and not stored; a row only records the link's
+-- settings so it can be listed, revoked and audited
+CREATE TABLE IF NOT EXISTS share_links (
+  id BIGSERIAL PRIMARY KEY,
+  portfolio_id BIGINT NOT NULL REFERENCES portfolios(id) ON DELETE CASCADE,
+  label TEXT,
+  hide_cost_basis BOOLEAN NOT NULL DEFAULT FALSE,
+  expires_at TIMESTAMPTZ NOT NULL,
+  revoked_at TIMESTAMPTZ,
+
+  -- Access audit
+  access_count INTEGER NOT NULL DEFAULT 0,
+  last_accessed_at TIMESTAMPTZ,
+
+  created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_share_links_portfolio ON share_links(portfolio_id);
+
+COMMENT ON TABLE share_links IS 'Expiring read-only portfolio share links';
+COMMENT ON COLUMN share_links.hide_cost_basis IS 'Redact open prices, cost basis and P/L from the shared view';
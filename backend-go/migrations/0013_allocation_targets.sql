@@ -0,0 +1,18 @@
+-- Target allocations of a portfolio: a ticker target holds one underlying, a sector target
+-- a named group of underlyings. An underlying belongs to at most one target, and the target
+-- percentages of a portfolio add up to at most 100 (checked by the API on replace).
+CREATE TABLE IF NOT EXISTS allocation_targets (
+  id BIGSERIAL PRIMARY KEY,
+  portfolio_id BIGINT NOT NULL REFERENCES portfolios(id) ON DELETE CASCADE,
+  kind TEXT NOT NULL CHECK (kind IN ('ticker', 'sector')),
+  name TEXT NOT NULL CHECK (length(name) BETWEEN 1 AND 100),
+  tickers TEXT[] NOT NULL CHECK (cardinality(tickers) > 0),
+  target_percent NUMERIC(7, 4) NOT NULL CHECK (target_percent > 0 AND target_percent <= 100),
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  UNIQUE (portfolio_id, name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_allocation_targets_portfolio ON allocation_targets(portfolio_id);
+
+COMMENT ON TABLE allocation_targets IS 'Target allocations by ticker or sector used for drift and rebalance suggestions';
+COMMENT ON COLUMN allocation_targets.target_percent IS 'Share of the allocation base (net dollar delta by default)';
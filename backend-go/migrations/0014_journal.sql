@@ -0,0 +1,39 @@
+-- Trade journal: entry theses, exit reasons and free-form notes, optionally attached to a
+-- position or a ledger transaction, with links to attachments such as chart screenshots
+CREATE TABLE IF NOT EXISTS journal_entries (
+  id BIGSERIAL PRIMARY KEY,
+  portfolio_id BIGINT NOT NULL REFERENCES portfolios(id) ON DELETE CASCADE,
+  position_id BIGINT REFERENCES positions(id) ON DELETE CASCADE,
+  transaction_id BIGINT REFERENCES transactions(id) ON DELETE CASCADE,
+  kind TEXT NOT NULL DEFAULT 'note' CHECK (kind IN ('thesis', 'exit', 'note')),
+  title TEXT CHECK (length(title) <= 200),
+  body TEXT NOT NULL CHECK (length(body) BETWEEN 1 AND 20000),
+  tags TEXT[] NOT NULL DEFAULT '{}',
+
+  -- Full-text search document: the title ranks above the body
+  search TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('english', body), 'B')
+  ) STORED,
+
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_journal_entries_portfolio ON journal_entries(portfolio_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_journal_entries_position ON journal_entries(position_id) WHERE position_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_journal_entries_search ON journal_entries USING GIN (search);
+CREATE INDEX IF NOT EXISTS idx_journal_entries_tags ON journal_entries USING GIN (tags);
+
+CREATE TABLE IF NOT EXISTS journal_attachments (
+  id BIGSERIAL PRIMARY KEY,
+  entry_id BIGINT NOT NULL REFERENCES journal_entries(id) ON DELETE CASCADE,
+  name TEXT NOT NULL CHECK (length(name) BETWEEN 1 AND 200),
+  url TEXT NOT NULL CHECK (length(url) <= 2000),
+  created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_journal_attachments_entry ON journal_attachments(entry_id);
+
+COMMENT ON TABLE journal_entries IS 'Trade journal notes with full-text search';
+COMMENT ON TABLE journal_attachments IS 'Links to files attached to journal entries';
@@ -0,0 +1,31 @@
+-- Simulated orders of paper portfolios. Filled orders are written to the positions and
+-- transactions ledger exactly like real trades and keep the quote they were filled against;
+-- rejected orders (limit not reached) are kept for the order history.
+CREATE TABLE IF NOT EXISTS paper_orders (
+  id BIGSERIAL PRIMARY KEY,
+  portfolio_id BIGINT NOT NULL REFERENCES portfolios(id) ON DELETE CASCADE,
+  asset_type TEXT NOT NULL CHECK (asset_type IN ('option', 'stock')),
+  ticker TEXT NOT NULL,
+  instruction TEXT NOT NULL CHECK (instruction IN ('buy_to_open', 'sell_to_open', 'buy_to_close', 'sell_to_close')),
+  quantity NUMERIC(18, 4) NOT NULL CHECK (quantity > 0),
+  limit_price NUMERIC(12, 4) CHECK (limit_price >= 0),
+  status TEXT NOT NULL CHECK (status IN ('filled', 'rejected')),
+
+  -- Quote at submission: bid/ask when available, the reference price slippage is applied to
+  bid NUMERIC(12, 4),
+  ask NUMERIC(12, 4),
+  reference_price NUMERIC(12, 4) NOT NULL,
+  slippage_bps NUMERIC(8, 2) NOT NULL DEFAULT 0,
+  fill_price NUMERIC(12, 4),
+  fees NUMERIC(12, 4) NOT NULL DEFAULT 0,
+  reject_reason TEXT,
+
+  position_id BIGINT REFERENCES positions(id) ON DELETE SET NULL,
+  transaction_id BIGINT REFERENCES transactions(id) ON DELETE SET NULL,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  CHECK (status <> 'filled' OR fill_price IS NOT NULL)
+);
+
+CREATE INDEX IF NOT EXISTS idx_paper_orders_portfolio ON paper_orders(portfolio_id, created_at DESC);
+
+COMMENT ON TABLE paper_orders IS 'Simulated orders of paper portfolios filled against live quotes';
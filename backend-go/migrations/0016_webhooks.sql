@@ -0,0 +1,43 @@
+-- Outbound webhooks: URLs registered on a portfolio receive signed event notifications.
+-- Events are queued in webhook_deliveries in the same transaction as the change that caused
+-- them and delivered by a background job with retries.
+CREATE TABLE IF NOT EXISTS webhooks (
+  id BIGSERIAL PRIMARY KEY,
+  portfolio_id BIGINT NOT NULL REFERENCES portfolios(id) ON DELETE CASCADE,
+  url TEXT NOT NULL CHECK (length(url) <= 2000),
+  description TEXT CHECK (length(description) <= 200),
+  events TEXT[] NOT NULL CHECK (cardinality(events) > 0),
+  secret TEXT NOT NULL,
+  active BOOLEAN NOT NULL DEFAULT TRUE,
+
+  -- Health: consecutive deliveries that exhausted their retries; the webhook is disabled
+  -- once too many fail in a row
+  failure_count INTEGER NOT NULL DEFAULT 0,
+  last_delivery_at TIMESTAMPTZ,
+  last_status INTEGER,
+
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhooks_portfolio ON webhooks(portfolio_id) WHERE active;
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+  id BIGSERIAL PRIMARY KEY,
+  webhook_id BIGINT NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+  event TEXT NOT NULL,
+  payload JSONB NOT NULL,
+  status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'failed')),
+  attempts INTEGER NOT NULL DEFAULT 0,
+  next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  response_status INTEGER,
+  last_error TEXT,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  delivered_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook ON webhook_deliveries(webhook_id, created_at DESC);
+
+COMMENT ON TABLE webhooks IS 'User-registered URLs notified of portfolio events';
+COMMENT ON TABLE webhook_deliveries IS 'Outbox of webhook events with delivery attempts';
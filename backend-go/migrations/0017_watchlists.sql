@@ -0,0 +1,28 @@
+-- Watchlists: named, ordered lists of tickers owned by a user, used for batch quotes and
+-- scanners. Names are unique per user like portfolio names.
+CREATE TABLE IF NOT EXISTS watchlists (
+  id BIGSERIAL PRIMARY KEY,
+  user_id UUID,
+  name TEXT NOT NULL CHECK (char_length(name) BETWEEN 1 AND 100),
+  description TEXT,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_watchlists_user_name
+  ON watchlists (COALESCE(user_id, '00000000-0000-0000-0000-000000000000'::uuid), lower(name));
+CREATE INDEX IF NOT EXISTS idx_watchlists_user ON watchlists(user_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS watchlist_items (
+  id BIGSERIAL PRIMARY KEY,
+  watchlist_id BIGINT NOT NULL REFERENCES watchlists(id) ON DELETE CASCADE,
+  ticker TEXT NOT NULL CHECK (char_length(ticker) BETWEEN 1 AND 30),
+  sort_order INTEGER NOT NULL,
+  added_at TIMESTAMPTZ DEFAULT NOW(),
+  UNIQUE (watchlist_id, ticker)
+);
+
+CREATE INDEX IF NOT EXISTS idx_watchlist_items_order ON watchlist_items(watchlist_id, sort_order);
+
+COMMENT ON TABLE watchlists IS 'Named ticker lists owned by a user';
+COMMENT ON COLUMN watchlist_items.sort_order IS 'Position of the ticker in the list, from 0';
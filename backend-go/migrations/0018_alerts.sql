@@ -0,0 +1,42 @@
+-- Market alerts: a user's threshold rules on a metric of a stock or option contract, e.g.
+-- "AAPL price > 200". Evaluated by the background alert engine during market hours. A rule
+-- that fires is recorded in alert_triggers and either stays triggered until re-armed or,
+-- with a cooldown, re-arms itself once the cooldown passes.
+CREATE TABLE IF NOT EXISTS alerts (
+  id BIGSERIAL PRIMARY KEY,
+  user_id UUID,
+  ticker TEXT NOT NULL CHECK (char_length(ticker) BETWEEN 1 AND 30),
+  metric TEXT NOT NULL,
+  operator TEXT NOT NULL CHECK (operator IN ('>', '>=', '<', '<=')),
+  threshold NUMERIC(18, 6) NOT NULL,
+  note TEXT,
+  cooldown_seconds INTEGER NOT NULL DEFAULT 0 CHECK (cooldown_seconds >= 0),
+  status TEXT NOT NULL DEFAULT 'armed' CHECK (status IN ('armed', 'triggered', 'cooldown', 'disabled')),
+
+  -- Latest evaluation and the last time the rule fired
+  last_value NUMERIC(18, 6),
+  last_evaluated_at TIMESTAMPTZ,
+  triggered_value NUMERIC(18, 6),
+  triggered_at TIMESTAMPTZ,
+  cooldown_until TIMESTAMPTZ,
+  trigger_count INTEGER NOT NULL DEFAULT 0,
+
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_alerts_user ON alerts(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_alerts_due ON alerts(status) WHERE status IN ('armed', 'cooldown');
+
+CREATE TABLE IF NOT EXISTS alert_triggers (
+  id BIGSERIAL PRIMARY KEY,
+  alert_id BIGINT NOT NULL REFERENCES alerts(id) ON DELETE CASCADE,
+  value NUMERIC(18, 6) NOT NULL,
+  threshold NUMERIC(18, 6) NOT NULL,
+  triggered_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_alert_triggers_alert ON alert_triggers(alert_id, triggered_at DESC);
+
+COMMENT ON TABLE alerts IS 'User alert rules on stock and option contract market data';
+COMMENT ON TABLE alert_triggers IS 'Every time an alert fired, with the value that fired it';
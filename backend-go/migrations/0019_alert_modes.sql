@@ -0,0 +1,15 @@
+-- Alert modes and crossing operators. A one-shot alert stays triggered after it fires; a
+-- recurring alert goes into cooldown and re-arms itself. "crosses_above" and
+-- "crosses_below" fire only when the value moves across the threshold between two
+-- evaluations, rather than on every evaluation it is beyond it.
+ALTER TABLE alerts ADD COLUMN IF NOT EXISTS mode TEXT NOT NULL DEFAULT 'one_shot'
+  CHECK (mode IN ('one_shot', 'recurring'));
+
+-- Alerts created with a cooldown before modes existed were recurring
+UPDATE alerts SET mode = 'recurring' WHERE cooldown_seconds > 0;
+
+ALTER TABLE alerts DROP CONSTRAINT IF EXISTS alerts_operator_check;
+ALTER TABLE alerts ADD CONSTRAINT alerts_operator_check
+  CHECK (operator IN ('>', '>=', '<', '<=', 'crosses_above', 'crosses_below'));
+
+COMMENT ON COLUMN alerts.cooldown_seconds IS 'Time a recurring alert waits after firing before it re-arms';
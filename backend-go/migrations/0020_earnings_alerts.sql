@@ -0,0 +1,27 @@
+-- Earnings proximity alerts. A user's settings choose how many days before earnings to be
+-- alerted and whether to cover the tickers held in their portfolios, their watchlists or
+-- both; the alert engine keeps one managed days_to_earnings alert per covered ticker.
+CREATE TABLE IF NOT EXISTS earnings_alert_settings (
+  id BIGSERIAL PRIMARY KEY,
+  user_id UUID,
+  enabled BOOLEAN NOT NULL DEFAULT TRUE,
+  days_before INTEGER NOT NULL DEFAULT 3 CHECK (days_before BETWEEN 0 AND 30),
+  portfolios BOOLEAN NOT NULL DEFAULT TRUE,
+  watchlists BOOLEAN NOT NULL DEFAULT TRUE,
+  synced_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_earnings_alert_settings_user
+  ON earnings_alert_settings (COALESCE(user_id, '00000000-0000-0000-0000-000000000000'::uuid));
+
+-- Alerts created by the user, or managed by their earnings alert settings
+ALTER TABLE alerts ADD COLUMN IF NOT EXISTS source TEXT NOT NULL DEFAULT 'user'
+  CHECK (source IN ('user', 'earnings'));
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_alerts_earnings_ticker
+  ON alerts (COALESCE(user_id, '00000000-0000-0000-0000-000000000000'::uuid), ticker)
+  WHERE source = 'earnings';
+
+COMMENT ON TABLE earnings_alert_settings IS 'Per-user settings for alerts ahead of earnings on held and watched tickers';
@@ -0,0 +1,45 @@
+-- Alert delivery channels: where a user's alert triggers are sent. A trigger queues one
+-- delivery per active channel of the alert's owner in the same transaction that records
+-- it; a background job sends them with retries and tracks each delivery's status.
+CREATE TABLE IF NOT EXISTS alert_channels (
+  id BIGSERIAL PRIMARY KEY,
+  user_id UUID,
+  kind TEXT NOT NULL CHECK (kind IN ('webhook')),
+  name TEXT CHECK (length(name) <= 100),
+  url TEXT CHECK (length(url) <= 2000),
+  secret TEXT,
+  active BOOLEAN NOT NULL DEFAULT TRUE,
+
+  -- Health: consecutive deliveries that exhausted their retries; the channel is disabled
+  -- once too many fail in a row
+  failure_count INTEGER NOT NULL DEFAULT 0,
+  last_delivery_at TIMESTAMPTZ,
+  last_status INTEGER,
+
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_alert_channels_user ON alert_channels(user_id) WHERE active;
+
+CREATE TABLE IF NOT EXISTS alert_deliveries (
+  id BIGSERIAL PRIMARY KEY,
+  channel_id BIGINT NOT NULL REFERENCES alert_channels(id) ON DELETE CASCADE,
+  trigger_id BIGINT REFERENCES alert_triggers(id) ON DELETE CASCADE,
+  event TEXT NOT NULL,
+  payload JSONB NOT NULL,
+  status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'failed')),
+  attempts INTEGER NOT NULL DEFAULT 0,
+  next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  response_status INTEGER,
+  last_error TEXT,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  delivered_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_alert_deliveries_due ON alert_deliveries(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_alert_deliveries_channel ON alert_deliveries(channel_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_alert_deliveries_trigger ON alert_deliveries(trigger_id);
+
+COMMENT ON TABLE alert_channels IS 'Where a user''s alert triggers are delivered';
+COMMENT ON TABLE alert_deliveries IS 'Outbox of alert notifications with delivery attempts';
@@ -0,0 +1,9 @@
+-- Email alert channels: a channel of kind 'email' sends each notification to an address
+ALTER TABLE alert_channels ADD COLUMN IF NOT EXISTS address TEXT CHECK (length(address) <= 320);
+
+ALTER TABLE alert_channels DROP CONSTRAINT IF EXISTS alert_channels_kind_check;
+ALTER TABLE alert_channels ADD CONSTRAINT alert_channels_kind_check CHECK (kind IN ('webhook', 'email'));
+
+-- Supports the per-user email rate limit, which counts recent deliveries
+CREATE INDEX IF NOT EXISTS idx_alert_deliveries_delivered ON alert_deliveries(channel_id, delivered_at)
+  WHERE status = 'delivered';
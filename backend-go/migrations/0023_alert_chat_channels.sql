@@ -0,0 +1,9 @@
+-- Slack and Discord alert channels, and channels scoped to one watchlist
+ALTER TABLE alert_channels DROP CONSTRAINT IF EXISTS alert_channels_kind_check;
+ALTER TABLE alert_channels ADD CONSTRAINT alert_channels_kind_check
+  CHECK (kind IN ('webhook', 'email', 'slack', 'discord'));
+
+ALTER TABLE alert_channels ADD COLUMN IF NOT EXISTS watchlist_id BIGINT REFERENCES watchlists(id) ON DELETE CASCADE;
+CREATE INDEX IF NOT EXISTS idx_alert_channels_watchlist ON alert_channels(watchlist_id) WHERE watchlist_id IS NOT NULL;
+
+COMMENT ON COLUMN alert_channels.watchlist_id IS 'When set, only triggers of alerts on the watchlist''s tickers or their option contracts are delivered';
@@ -0,0 +1,21 @@
+-- Alert history: each trigger keeps the market data it was evaluated against and whether the
+-- user has acknowledged it; alerts can be snoozed until a time, after which they re-arm
+ALTER TABLE alert_triggers ADD COLUMN IF NOT EXISTS previous_value NUMERIC(18, 6);
+ALTER TABLE alert_triggers ADD COLUMN IF NOT EXISTS snapshot JSONB NOT NULL DEFAULT '{}'::jsonb;
+ALTER TABLE alert_triggers ADD COLUMN IF NOT EXISTS acknowledged_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_alert_triggers_unacknowledged ON alert_triggers(alert_id)
+  WHERE acknowledged_at IS NULL;
+
+ALTER TABLE alerts ADD COLUMN IF NOT EXISTS snoozed_until TIMESTAMPTZ;
+
+ALTER TABLE alerts DROP CONSTRAINT IF EXISTS alerts_status_check;
+ALTER TABLE alerts ADD CONSTRAINT alerts_status_check
+  CHECK (status IN ('armed', 'triggered', 'cooldown', 'snoozed', 'disabled'));
+
+DROP INDEX IF EXISTS idx_alerts_due;
+CREATE INDEX IF NOT EXISTS idx_alerts_due ON alerts(status) WHERE status IN ('armed', 'cooldown', 'snoozed');
+
+COMMENT ON COLUMN alert_triggers.previous_value IS 'Value at the evaluation before the trigger, which crossing operators compare with';
+COMMENT ON COLUMN alert_triggers.snapshot IS 'Every metric fetched for the ticker in the run that fired the alert';
+COMMENT ON COLUMN alerts.snoozed_until IS 'When a snoozed alert re-arms';
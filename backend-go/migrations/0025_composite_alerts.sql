@@ -0,0 +1,7 @@
+-- Composite alert rules: an AND/OR tree of comparisons, e.g.
+-- {"all": [{"metric": "iv_rank", "operator": ">", "threshold": 50}, ...]}. The metric,
+-- operator and threshold columns hold the rule's first comparison, whose value is recorded
+-- on each evaluation and trigger.
+ALTER TABLE alerts ADD COLUMN IF NOT EXISTS condition JSONB;
+
+COMMENT ON COLUMN alerts.condition IS 'Composite rule of all/any groups of comparisons; NULL for single-comparison rules';
@@ -0,0 +1,20 @@
+-- API keys for programmatic access: scripts and bots authenticate as their owner with a
+-- bearer key instead of a Supabase session. Only a SHA-256 hash of each key is stored; the
+-- key itself is shown once, when it is issued.
+CREATE TABLE IF NOT EXISTS api_keys (
+  id BIGSERIAL PRIMARY KEY,
+  user_id UUID NOT NULL,
+  name TEXT NOT NULL CHECK (length(name) BETWEEN 1 AND 100),
+  prefix TEXT NOT NULL,
+  key_hash TEXT NOT NULL UNIQUE,
+  expires_at TIMESTAMPTZ,
+  last_used_at TIMESTAMPTZ,
+  revoked_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_user ON api_keys(user_id, created_at DESC);
+
+COMMENT ON TABLE api_keys IS 'Bearer keys scripts and bots use to call the API as their owner';
+COMMENT ON COLUMN api_keys.prefix IS 'Leading characters of the key, shown so owners can tell keys apart';
+COMMENT ON COLUMN api_keys.key_hash IS 'Hex SHA-256 of the key; the key itself is never stored';
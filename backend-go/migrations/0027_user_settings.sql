@@ -0,0 +1,21 @@
+-- Per-user preferences: display defaults the frontend applies to the options chain and
+-- greeks, the theme, and the mode and cooldown new alerts get when none is given
+CREATE TABLE IF NOT EXISTS user_settings (
+  id BIGSERIAL PRIMARY KEY,
+  user_id UUID,
+  expiration_window_days INTEGER NOT NULL DEFAULT 45 CHECK (expiration_window_days BETWEEN 1 AND 730),
+  greeks_display TEXT NOT NULL DEFAULT 'per_share' CHECK (greeks_display IN ('per_share', 'per_contract')),
+  second_order_greeks BOOLEAN NOT NULL DEFAULT FALSE,
+  risk_free_rate DOUBLE PRECISION CHECK (risk_free_rate BETWEEN 0 AND 0.25),
+  theme TEXT NOT NULL DEFAULT 'system' CHECK (theme IN ('system', 'light', 'dark')),
+  alert_mode TEXT NOT NULL DEFAULT 'one_shot' CHECK (alert_mode IN ('one_shot', 'recurring')),
+  alert_cooldown_seconds INTEGER NOT NULL DEFAULT 3600 CHECK (alert_cooldown_seconds BETWEEN 0 AND 604800),
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_settings_user
+  ON user_settings (COALESCE(user_id, '00000000-0000-0000-0000-000000000000'::uuid));
+
+COMMENT ON TABLE user_settings IS 'Per-user display preferences and alert defaults';
+COMMENT ON COLUMN user_settings.risk_free_rate IS 'Preferred annual risk-free rate; NULL uses the server''s rate';
@@ -0,0 +1,12 @@
+-- Composite indexes for user-scoped lookups. Every query on a user's portfolios, watchlists,
+-- alerts and alert channels now filters by user_id, so these lead with it.
+
+-- Alert list filtered by status, newest first
+CREATE INDEX IF NOT EXISTS idx_alerts_user_status ON alerts(user_id, status, created_at DESC);
+
+-- Channel list, including inactive channels (idx_alert_channels_user covers active ones only)
+CREATE INDEX IF NOT EXISTS idx_alert_channels_user_id ON alert_channels(user_id, id);
+
+-- Single-row lookups by owner and ID
+CREATE INDEX IF NOT EXISTS idx_portfolios_user_id ON portfolios(user_id, id);
+CREATE INDEX IF NOT EXISTS idx_watchlists_user_id ON watchlists(user_id, id);
@@ -0,0 +1,20 @@
+-- Users of the API, recorded on their first authenticated request. Supabase Auth owns the
+-- accounts themselves; this table holds what Periscope adds: when a user was last seen, and
+-- whether an admin has disabled them or reset their quota.
+CREATE TABLE IF NOT EXISTS users (
+  id UUID PRIMARY KEY,
+  email TEXT,
+  disabled_at TIMESTAMPTZ,
+  disabled_reason TEXT CHECK (length(disabled_reason) <= 500),
+  quota_reset_at TIMESTAMPTZ,
+  last_seen_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_users_last_seen ON users(last_seen_at DESC);
+CREATE INDEX IF NOT EXISTS idx_users_email ON users(lower(email));
+
+COMMENT ON TABLE users IS 'API users, recorded on first authenticated request';
+COMMENT ON COLUMN users.disabled_at IS 'When an admin disabled the user; disabled users are rejected with 403';
+COMMENT ON COLUMN users.quota_reset_at IS 'When an admin last reset the user''s quota; usage is counted from then';
@@ -0,0 +1,36 @@
+-- Append-only record of changes users make through the API: who made them (and with which
+-- API key), from where, and the resource before and after. Rows are never updated or
+-- deleted; the trigger below rejects any attempt.
+CREATE TABLE IF NOT EXISTS audit_log (
+  id BIGSERIAL PRIMARY KEY,
+  actor_id UUID,
+  api_key_id BIGINT,
+  ip INET,
+  action TEXT NOT NULL,
+  resource_type TEXT NOT NULL,
+  resource_id TEXT,
+  before JSONB,
+  after JSONB,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_created ON audit_log(created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor ON audit_log(actor_id, id DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_log_resource ON audit_log(resource_type, resource_id, id DESC);
+
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_log_append_only ON audit_log;
+CREATE TRIGGER audit_log_append_only
+  BEFORE UPDATE OR DELETE ON audit_log
+  FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
+
+COMMENT ON TABLE audit_log IS 'Append-only record of changes users make through the API';
+COMMENT ON COLUMN audit_log.action IS 'What was done, as resource.verb, e.g. portfolio.update';
+COMMENT ON COLUMN audit_log.api_key_id IS 'The API key the change was made with, if not a session';
+COMMENT ON COLUMN audit_log.before IS 'The resource before the change; null for creations';
+COMMENT ON COLUMN audit_log.after IS 'The resource after the change; null for deletions';
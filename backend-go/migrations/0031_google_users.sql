@@ -0,0 +1,9 @@
+-- Users who sign in with Google ID tokens rather than Supabase. Google's subject IDs are not
+-- UUIDs, so each Google account is given a Periscope user ID the first time it signs in and
+-- found by its subject from then on.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS provider TEXT NOT NULL DEFAULT 'supabase'
+  CHECK (provider IN ('supabase', 'google'));
+ALTER TABLE users ADD COLUMN IF NOT EXISTS google_sub TEXT UNIQUE;
+
+COMMENT ON COLUMN users.provider IS 'Who authenticates the user: supabase, or google for Google ID tokens';
+COMMENT ON COLUMN users.google_sub IS 'The Google account ID of users provisioned from Google ID tokens';
@@ -0,0 +1,23 @@
+-- Per-user metering of upstream Massive API usage: pages fetched (every response, each page
+-- of a paginated chain counting separately) and contracts fetched through contract details.
+-- usage_daily keeps the history; the quota_* columns on users total the current monthly
+-- quota period, which starts afresh each calendar month (UTC) or when an admin resets it.
+CREATE TABLE IF NOT EXISTS usage_daily (
+  user_id UUID NOT NULL,
+  day DATE NOT NULL,
+  pages BIGINT NOT NULL DEFAULT 0,
+  contracts BIGINT NOT NULL DEFAULT 0,
+  requests BIGINT NOT NULL DEFAULT 0,
+  PRIMARY KEY (user_id, day)
+);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS tier TEXT NOT NULL DEFAULT 'free'
+  CHECK (tier IN ('free', 'pro', 'unlimited'));
+ALTER TABLE users ADD COLUMN IF NOT EXISTS quota_period_start DATE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS quota_pages BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS quota_contracts BIGINT NOT NULL DEFAULT 0;
+
+COMMENT ON TABLE usage_daily IS 'Upstream API usage per user and UTC day';
+COMMENT ON COLUMN usage_daily.requests IS 'API requests that made upstream calls';
+COMMENT ON COLUMN users.tier IS 'Usage tier, which sets the monthly quota: free, pro or unlimited';
+COMMENT ON COLUMN users.quota_period_start IS 'First day of the month quota_pages and quota_contracts count';
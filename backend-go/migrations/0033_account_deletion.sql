@@ -0,0 +1,13 @@
+-- Account deletion with a grace period. A user who asks to delete their account is scheduled
+-- for deletion; until then they can cancel, and after it the deletion job purges their data
+-- and leaves the users row as a tombstone (no email, disabled) so stale tokens are turned
+-- away. The audit log is append-only and keeps its entries.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deletion_requested_at TIMESTAMPTZ;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deletion_scheduled_at TIMESTAMPTZ;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_users_deletion_due ON users(deletion_scheduled_at)
+  WHERE deletion_scheduled_at IS NOT NULL AND deleted_at IS NULL;
+
+COMMENT ON COLUMN users.deletion_scheduled_at IS 'When the user''s data will be purged; NULL unless they asked to delete their account';
+COMMENT ON COLUMN users.deleted_at IS 'When the user''s data was purged; the row remains as a tombstone';
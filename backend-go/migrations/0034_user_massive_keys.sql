@@ -0,0 +1,9 @@
+-- Users may bring their own Massive API key, so the market data they request counts against
+-- their own plan instead of the server's. The key is encrypted by the API (AES-256-GCM, bound
+-- to the user's id) before it is stored; only its last four characters are kept in the clear.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS massive_key_ciphertext TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS massive_key_hint TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS massive_key_set_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN users.massive_key_ciphertext IS 'The user''s own Massive API key, encrypted with SECRETS_ENCRYPTION_KEY; NULL to use the server''s key';
+COMMENT ON COLUMN users.massive_key_hint IS 'Last four characters of the user''s Massive API key, for display';
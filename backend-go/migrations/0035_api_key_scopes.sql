@@ -0,0 +1,12 @@
+-- API key scopes: each key grants only the scopes it was issued with (resource:read or
+-- resource:write, write implying read), so an owner can hand a dashboard a read-only key.
+-- Keys issued before scopes existed keep full access.
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS scopes TEXT[] NOT NULL DEFAULT ARRAY[
+  'market:read',
+  'portfolio:read', 'portfolio:write',
+  'watchlist:read', 'watchlist:write',
+  'alert:read', 'alert:write',
+  'account:read', 'account:write'
+];
+
+COMMENT ON COLUMN api_keys.scopes IS 'What the key may do, as resource:read or resource:write; sessions are not scoped';
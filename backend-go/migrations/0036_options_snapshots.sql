@@ -0,0 +1,44 @@
+-- Options chain snapshots: Periscope's own history of the chains it fetches. Each capture of
+-- an underlying's chain is a row in options_snapshots, and each of its contracts a row in
+-- options_snapshot_contracts with the quote, greeks, IV, volume and open interest at that
+-- moment. captured_at is repeated on the contracts so time-range queries need no join.
+CREATE TABLE IF NOT EXISTS options_snapshots (
+  id BIGSERIAL PRIMARY KEY,
+  ticker TEXT NOT NULL,
+  captured_at TIMESTAMPTZ NOT NULL,
+  spot NUMERIC(12, 4) NOT NULL,
+  contracts INTEGER NOT NULL,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  UNIQUE (ticker, captured_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_options_snapshots_ticker ON options_snapshots(ticker, captured_at DESC);
+
+CREATE TABLE IF NOT EXISTS options_snapshot_contracts (
+  snapshot_id BIGINT NOT NULL REFERENCES options_snapshots(id) ON DELETE CASCADE,
+  captured_at TIMESTAMPTZ NOT NULL,
+  underlying TEXT NOT NULL,
+  ticker TEXT NOT NULL,
+  contract_type TEXT NOT NULL CHECK (contract_type IN ('call', 'put')),
+  strike_price NUMERIC(12, 4) NOT NULL,
+  expiration_date DATE NOT NULL,
+  bid NUMERIC(12, 4),
+  ask NUMERIC(12, 4),
+  last_price NUMERIC(12, 4),
+  volume BIGINT,
+  open_interest BIGINT,
+  implied_volatility NUMERIC(10, 6),
+  delta NUMERIC(10, 6),
+  gamma NUMERIC(10, 6),
+  theta NUMERIC(10, 6),
+  vega NUMERIC(10, 6),
+  rho NUMERIC(10, 6),
+  PRIMARY KEY (ticker, captured_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_options_snapshot_contracts_snapshot ON options_snapshot_contracts(snapshot_id);
+CREATE INDEX IF NOT EXISTS idx_options_snapshot_contracts_underlying
+  ON options_snapshot_contracts(underlying, captured_at DESC, expiration_date);
+
+COMMENT ON TABLE options_snapshots IS 'Captures of an underlying''s options chain, one row per capture';
+COMMENT ON TABLE options_snapshot_contracts IS 'Each contract''s quote, greeks and activity in a chain capture';
@@ -0,0 +1,68 @@
+-- TimescaleDB hypertables for the time-series tables: chain snapshot contracts and quote
+-- history. Everything here is skipped when the timescaledb extension can't be created (it
+-- must be installed and in shared_preload_libraries), leaving ordinary tables on plain
+-- Postgres. The migration is recorded either way, so a database that enables Timescale
+-- later should run this file by hand (psql -f); every step is safe to repeat.
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_available_extensions WHERE name = 'timescaledb') THEN
+    RAISE NOTICE 'timescaledb is not installed, skipping hypertables';
+    RETURN;
+  END IF;
+  BEGIN
+    CREATE EXTENSION IF NOT EXISTS timescaledb;
+  EXCEPTION WHEN OTHERS THEN
+    RAISE NOTICE 'timescaledb could not be enabled (%), skipping hypertables', SQLERRM;
+    RETURN;
+  END;
+
+  -- Chain snapshot contracts: weekly chunks, compressed per underlying once a week old.
+  -- Existing rows are moved into chunks.
+  PERFORM create_hypertable('options_snapshot_contracts', 'captured_at',
+    chunk_time_interval => INTERVAL '7 days', migrate_data => true, if_not_exists => true);
+  IF NOT EXISTS (SELECT 1 FROM timescaledb_information.hypertables
+                 WHERE hypertable_name = 'options_snapshot_contracts' AND compression_enabled) THEN
+    ALTER TABLE options_snapshot_contracts SET (
+      timescaledb.compress,
+      timescaledb.compress_segmentby = 'underlying',
+      timescaledb.compress_orderby = 'captured_at DESC, expiration_date, strike_price, ticker'
+    );
+  END IF;
+  PERFORM add_compression_policy('options_snapshot_contracts', INTERVAL '7 days', if_not_exists => true);
+
+  -- Quote history is a declaratively partitioned table, which Timescale cannot convert. It
+  -- is rebuilt as a hypertable while it is still empty; a populated table is left as it is.
+  IF EXISTS (SELECT 1 FROM pg_class WHERE relname = 'options_quotes' AND relkind = 'p')
+     AND NOT EXISTS (SELECT 1 FROM options_quotes) THEN
+    DROP TABLE options_quotes;
+    CREATE TABLE options_quotes (
+      id BIGSERIAL NOT NULL,
+      ticker TEXT NOT NULL,
+      timestamp TIMESTAMPTZ NOT NULL,
+      bid NUMERIC(10, 4),
+      ask NUMERIC(10, 4),
+      last_price NUMERIC(10, 4),
+      volume BIGINT,
+      open_interest BIGINT,
+      implied_volatility NUMERIC(10, 6),
+      delta NUMERIC(10, 6),
+      gamma NUMERIC(10, 6),
+      theta NUMERIC(10, 6),
+      vega NUMERIC(10, 6),
+      rho NUMERIC(10, 6),
+      created_at TIMESTAMPTZ DEFAULT NOW(),
+      PRIMARY KEY (id, timestamp)
+    );
+    CREATE INDEX idx_options_quotes_ticker_timestamp ON options_quotes(ticker, timestamp DESC);
+    COMMENT ON TABLE options_quotes IS 'Hypertable of historical options quotes and Greeks';
+
+    PERFORM create_hypertable('options_quotes', 'timestamp', chunk_time_interval => INTERVAL '1 day');
+    ALTER TABLE options_quotes SET (
+      timescaledb.compress,
+      timescaledb.compress_segmentby = 'ticker',
+      timescaledb.compress_orderby = 'timestamp DESC'
+    );
+    PERFORM add_compression_policy('options_quotes', INTERVAL '3 days', if_not_exists => true);
+  END IF;
+END
+$$;
@@ -0,0 +1,26 @@
+-- Soft deletes for portfolios, watchlists and alerts: deleting one sets deleted_at and hides
+-- it, with everything recorded under it, until it is restored or purged. Names only need to
+-- be unique among what isn't deleted, so a deleted portfolio's name can be reused; restoring
+-- it then fails until one of the two is renamed.
+ALTER TABLE portfolios ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE watchlists ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE alerts ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+DROP INDEX IF EXISTS idx_portfolios_user_name;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_portfolios_user_name
+  ON portfolios (COALESCE(user_id, '00000000-0000-0000-0000-000000000000'::uuid), lower(name))
+  WHERE deleted_at IS NULL;
+
+DROP INDEX IF EXISTS idx_watchlists_user_name;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_watchlists_user_name
+  ON watchlists (COALESCE(user_id, '00000000-0000-0000-0000-000000000000'::uuid), lower(name))
+  WHERE deleted_at IS NULL;
+
+-- Each user's deleted items, most recently deleted first
+CREATE INDEX IF NOT EXISTS idx_portfolios_deleted ON portfolios(user_id, deleted_at DESC) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_watchlists_deleted ON watchlists(user_id, deleted_at DESC) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_alerts_deleted ON alerts(user_id, deleted_at DESC) WHERE deleted_at IS NOT NULL;
+
+COMMENT ON COLUMN portfolios.deleted_at IS 'When the portfolio was deleted; NULL while it is live. Purging removes the row';
+COMMENT ON COLUMN watchlists.deleted_at IS 'When the watchlist was deleted; NULL while it is live. Purging removes the row';
+COMMENT ON COLUMN alerts.deleted_at IS 'When the alert was deleted; NULL while it is live. Purging removes the row';
@@ -0,0 +1,66 @@
+-- Daily rollups of chain snapshot contracts: each ticker's last capture of a trading day,
+-- kept after the intraday captures around it have expired. With TimescaleDB the retention
+-- job copies those captures here and then drops the intraday chunks whole, rather than
+-- deleting rows out of compressed chunks; on plain Postgres it deletes rows instead and this
+-- table stays empty.
+CREATE TABLE IF NOT EXISTS options_snapshot_contracts_daily (
+  snapshot_id BIGINT NOT NULL,
+  captured_at TIMESTAMPTZ NOT NULL,
+  underlying TEXT NOT NULL,
+  ticker TEXT NOT NULL,
+  contract_type TEXT NOT NULL CHECK (contract_type IN ('call', 'put')),
+  strike_price NUMERIC(12, 4) NOT NULL,
+  expiration_date DATE NOT NULL,
+  bid NUMERIC(12, 4),
+  ask NUMERIC(12, 4),
+  last_price NUMERIC(12, 4),
+  volume BIGINT,
+  open_interest BIGINT,
+  implied_volatility NUMERIC(10, 6),
+  delta NUMERIC(10, 6),
+  gamma NUMERIC(10, 6),
+  theta NUMERIC(10, 6),
+  vega NUMERIC(10, 6),
+  rho NUMERIC(10, 6),
+  PRIMARY KEY (ticker, captured_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_options_snapshot_contracts_daily_underlying
+  ON options_snapshot_contracts_daily(underlying, captured_at DESC, expiration_date);
+
+COMMENT ON TABLE options_snapshot_contracts_daily IS 'Contracts of each ticker''s last chain capture of a trading day, once its intraday captures have expired';
+
+-- With Timescale, the rollups become a hypertable in monthly chunks, compressed once two
+-- months old so rows copied in at the end of the intraday retention land in chunks still
+-- uncompressed. The contracts' foreign key is dropped: captures are removed with their
+-- chunks, and a cascading delete would have to decompress every chunk to find their rows.
+-- Like 0037, this is skipped without Timescale and safe to run again by hand after enabling
+-- it.
+DO $$
+BEGIN
+  -- Checked apart: timescaledb_information only exists once the extension does
+  IF NOT EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'timescaledb') THEN
+    RAISE NOTICE 'timescaledb is not enabled, skipping';
+    RETURN;
+  END IF;
+  IF NOT EXISTS (SELECT 1 FROM timescaledb_information.hypertables
+                 WHERE hypertable_name = 'options_snapshot_contracts') THEN
+    RAISE NOTICE 'options_snapshot_contracts is not a hypertable, skipping';
+    RETURN;
+  END IF;
+
+  ALTER TABLE options_snapshot_contracts DROP CONSTRAINT IF EXISTS options_snapshot_contracts_snapshot_id_fkey;
+
+  PERFORM create_hypertable('options_snapshot_contracts_daily', 'captured_at',
+    chunk_time_interval => INTERVAL '30 days', migrate_data => true, if_not_exists => true);
+  IF NOT EXISTS (SELECT 1 FROM timescaledb_information.hypertables
+                 WHERE hypertable_name = 'options_snapshot_contracts_daily' AND compression_enabled) THEN
+    ALTER TABLE options_snapshot_contracts_daily SET (
+      timescaledb.compress,
+      timescaledb.compress_segmentby = 'underlying',
+      timescaledb.compress_orderby = 'captured_at DESC, expiration_date, strike_price, ticker'
+    );
+  END IF;
+  PERFORM add_compression_policy('options_snapshot_contracts_daily', INTERVAL '60 days', if_not_exists => true);
+END
+$$;
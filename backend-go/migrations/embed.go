@@ -0,0 +1,11 @@
+// Package migrations holds the versioned SQL migrations of the Periscope schema, embedded in
+// the API binary so it can bring a database up to date itself
+package migrations
+
+import "embed"
+
+// FS contains the migration files, named VERSION_description.sql with a four-digit sequence
+// number as the version
+//
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,20 @@
+package migrations
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aaronbengochea/periscope/backend-go/pkg/database"
+)
+
+func TestMigrationsAreNumberedInSequence(t *testing.T) {
+	all, err := database.LoadMigrations(FS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, m := range all {
+		if want := fmt.Sprintf("%04d", i+1); m.Version != want {
+			t.Errorf("migration %s has version %s, want %s", m.Name, m.Version, want)
+		}
+	}
+}
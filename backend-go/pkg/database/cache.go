@@ -0,0 +1,169 @@
+package database
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// invalidationChannel is the NOTIFY channel cache invalidations are published on, with a
+// "cache:key" payload
+const invalidationChannel = "periscope_cache"
+
+// cacheMaxEntries bounds a cache; past it expired entries are dropped, and if that is not
+// enough the cache starts over
+const cacheMaxEntries = 10000
+
+// Cache keeps query results in memory for up to a TTL. A change invalidates its key in this
+// instance right away and, through Postgres NOTIFY, in every other instance listening with
+// ListenForInvalidations, so a multi-instance deployment doesn't serve stale rows. Without
+// a listener, entries still expire after the TTL.
+type Cache[V any] struct {
+	db   *DB
+	name string
+	ttl  time.Duration
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry[V]
+}
+
+type cacheEntry[V any] struct {
+	value   V
+	expires time.Time
+}
+
+// NewCache creates a cache named for what it holds; caches with the same name are
+// invalidated together. db may be nil, for a cache that only expires.
+func NewCache[V any](db *DB, name string, ttl time.Duration) *Cache[V] {
+	c := &Cache[V]{db: db, name: name, ttl: ttl, entries: make(map[string]cacheEntry[V])}
+	if db != nil {
+		db.caches.register(name, c.drop)
+	}
+	return c
+}
+
+// Get returns the cached value for key, if there is one that hasn't expired
+func (c *Cache[V]) Get(key string) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok || time.Now().After(entry.expires) {
+		var zero V
+		return zero, false
+	}
+	return entry.value, true
+}
+
+// Set caches value for key
+func (c *Cache[V]) Set(key string, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	if len(c.entries) >= cacheMaxEntries {
+		for k, entry := range c.entries {
+			if now.After(entry.expires) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= cacheMaxEntries {
+			c.entries = make(map[string]cacheEntry[V])
+		}
+	}
+	c.entries[key] = cacheEntry[V]{value: value, expires: now.Add(c.ttl)}
+}
+
+// Invalidate drops key from this cache and publishes the invalidation to the other
+// instances. A failed publish is logged; their entries then expire after the TTL.
+func (c *Cache[V]) Invalidate(ctx context.Context, key string) {
+	if c.db == nil {
+		c.drop(key)
+		return
+	}
+	c.db.caches.dispatch(c.name, key)
+	if !c.db.Connected() {
+		return
+	}
+	if _, err := c.db.Pool.Exec(ctx, `SELECT pg_notify($1, $2)`, invalidationChannel, c.name+":"+key); err != nil {
+		log.Printf("[Database] ⚠ Failed to publish invalidation of %s %s: %v", c.name, key, err)
+	}
+}
+
+// Forget drops key from this instance's cache only, for changes frequent enough that
+// publishing each would cost more than the other instances' entries expiring
+func (c *Cache[V]) Forget(key string) {
+	c.drop(key)
+}
+
+func (c *Cache[V]) drop(key string) {
+	c.mu.Lock()
+	delete(c.entries, key)
+	c.mu.Unlock()
+}
+
+// cacheRegistry routes invalidations to every cache of a name
+type cacheRegistry struct {
+	mu     sync.RWMutex
+	byName map[string][]func(key string)
+}
+
+func (r *cacheRegistry) register(name string, drop func(key string)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.byName == nil {
+		r.byName = make(map[string][]func(key string))
+	}
+	r.byName[name] = append(r.byName[name], drop)
+}
+
+func (r *cacheRegistry) dispatch(name, key string) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, drop := range r.byName[name] {
+		drop(key)
+	}
+}
+
+// ListenForInvalidations applies the cache invalidations other instances publish until ctx
+// is cancelled. It holds one pool connection for LISTEN, so it needs a direct or session
+// pooler connection; it reconnects with backoff when the connection drops.
+func (db *DB) ListenForInvalidations(ctx context.Context) {
+	backoff := time.Second
+	for ctx.Err() == nil {
+		if err := db.listen(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("[Database] ⚠ Cache invalidation listener stopped, retrying in %s: %v", backoff, err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(backoff):
+			}
+			backoff = min(backoff*2, time.Minute)
+			continue
+		}
+		backoff = time.Second
+	}
+}
+
+func (db *DB) listen(ctx context.Context) error {
+	pooled, err := db.Pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	// The session is LISTENing, so it is taken out of the pool rather than handed back
+	conn := pooled.Hijack()
+	defer conn.Close(context.WithoutCancel(ctx))
+
+	if _, err := conn.Exec(ctx, `LISTEN `+invalidationChannel); err != nil {
+		return err
+	}
+	log.Println("[Database] ✓ Listening for cache invalidations")
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		if name, key, ok := strings.Cut(n.Payload, ":"); ok {
+			db.caches.dispatch(name, key)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// migrationLockID is the advisory lock each migration's transaction holds, so instances
+// starting together do not apply the same migration twice. It is a transaction-level lock,
+// released on commit, since the transaction pooler only keeps a transaction on one server
+// session.
+const migrationLockID = 7_301_955_118
+
+// Migration is a versioned SQL file. Files are named VERSION_description.sql, VERSION being
+// a zero-padded sequence number, and applied in version order.
+type Migration struct {
+	Version  string `json:"version"`
+	Name     string `json:"name"`
+	SQL      string `json:"-"`
+	Checksum string `json:"checksum"` // hex SHA-256 of the file
+}
+
+// MigrationStatus is a migration and whether it has been applied
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+	Modified  bool       `json:"modified"` // the file changed after it was applied
+}
+
+// LoadMigrations reads the migrations in the root of fsys, ordered by version
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	paths, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list migrations: %w", err)
+	}
+
+	migrations := make([]Migration, 0, len(paths))
+	seen := map[string]string{}
+	for _, p := range paths {
+		version, _, ok := strings.Cut(strings.TrimSuffix(path.Base(p), ".sql"), "_")
+		if !ok || version == "" || strings.Trim(version, "0123456789") != "" {
+			return nil, fmt.Errorf("migration %s is not named VERSION_description.sql", p)
+		}
+		if other, ok := seen[version]; ok {
+			return nil, fmt.Errorf("migrations %s and %s share version %s", other, p, version)
+		}
+		seen[version] = p
+
+		body, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", p, err)
+		}
+		sum := sha256.Sum256(body)
+		migrations = append(migrations, Migration{
+			Version:  version,
+			Name:     path.Base(p),
+			SQL:      string(body),
+			Checksum: hex.EncodeToString(sum[:]),
+		})
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// MigrationStatus reports which of the migrations have been applied
+func (db *DB) MigrationStatus(ctx context.Context, migrations []Migration) ([]MigrationStatus, error) {
+	if err := db.ensureMigrationTable(ctx); err != nil {
+		return nil, err
+	}
+	applied, err := db.appliedMigrations(ctx, db.Pool)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		status := MigrationStatus{Migration: m}
+		if a, ok := applied[m.Version]; ok {
+			status.AppliedAt = &a.at
+			status.Modified = a.checksum != "" && a.checksum != m.Checksum
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Migrate applies the migrations that have not been applied yet, in version order, each in
+// its own transaction, and returns those it applied. It stops at the first that fails,
+// leaving the ones before it applied.
+func (db *DB) Migrate(ctx context.Context, migrations []Migration) ([]Migration, error) {
+	return db.migrate(ctx, migrations, "", true)
+}
+
+// BaselineMigrations records the migrations up to and including version as applied without
+// running them, for databases whose schema was set up by hand before migrations were
+// tracked
+func (db *DB) BaselineMigrations(ctx context.Context, migrations []Migration, version string) ([]Migration, error) {
+	return db.migrate(ctx, migrations, version, false)
+}
+
+// migrate records each pending migration as applied, up to version when it is set, running
+// them when run is set
+func (db *DB) migrate(ctx context.Context, migrations []Migration, version string, run bool) ([]Migration, error) {
+	if version != "" && !hasVersion(migrations, version) {
+		return nil, fmt.Errorf("no migration has version %s", version)
+	}
+	if err := db.ensureMigrationTable(ctx); err != nil {
+		return nil, err
+	}
+	applied, err := db.appliedMigrations(ctx, db.Pool)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, m := range migrations {
+		if version != "" && m.Version > version {
+			break
+		}
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+		ok, err := db.applyMigration(ctx, m, run)
+		if err != nil {
+			return done, err
+		}
+		if ok {
+			done = append(done, m)
+		}
+	}
+	return done, nil
+}
+
+// applyMigration runs m, when run is set, and records it in one transaction holding the
+// migration lock. It reports false when another instance applied m while this one waited
+// for the lock.
+func (db *DB) applyMigration(ctx context.Context, m Migration, run bool) (bool, error) {
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin migration %s: %w", m.Name, err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, migrationLockID); err != nil {
+		return false, fmt.Errorf("failed to lock migrations: %w", err)
+	}
+	var recorded bool
+	err = tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)`, m.Version).Scan(&recorded)
+	if err != nil {
+		return false, fmt.Errorf("failed to check migration %s: %w", m.Name, err)
+	}
+	if recorded {
+		return false, nil
+	}
+
+	if run {
+		if _, err := tx.Exec(ctx, m.SQL); err != nil {
+			return false, fmt.Errorf("migration %s failed: %w", m.Name, err)
+		}
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO schema_migrations (version, name, checksum, applied_at)
+		VALUES ($1, $2, $3, NOW())`,
+		m.Version, m.Name, m.Checksum)
+	if err != nil {
+		return false, fmt.Errorf("failed to record migration %s: %w", m.Name, err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit migration %s: %w", m.Name, err)
+	}
+	return true, nil
+}
+
+// hasVersion reports whether one of the migrations has version
+func hasVersion(migrations []Migration, version string) bool {
+	for _, m := range migrations {
+		if m.Version == version {
+			return true
+		}
+	}
+	return false
+}
+
+// ensureMigrationTable creates the table applied migrations are recorded in, under the
+// migration lock so instances starting together do not both create it
+func (db *DB) ensureMigrationTable(ctx context.Context) error {
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin creating schema_migrations: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, migrationLockID); err != nil {
+		return fmt.Errorf("failed to lock migrations: %w", err)
+	}
+	_, err = tx.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			checksum TEXT NOT NULL DEFAULT '',
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	return nil
+}
+
+// rowsQuerier is satisfied by both the pool and a transaction
+type rowsQuerier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+// appliedMigration is a migration recorded in schema_migrations
+type appliedMigration struct {
+	checksum string
+	at       time.Time
+}
+
+// appliedMigrations returns the applied migrations by version
+func (db *DB) appliedMigrations(ctx context.Context, q rowsQuerier) (map[string]appliedMigration, error) {
+	rows, err := q.Query(ctx, `SELECT version, checksum, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := map[string]appliedMigration{}
+	for rows.Next() {
+		var version string
+		var a appliedMigration
+		if err := rows.Scan(&version, &a.checksum, &a.at); err != nil {
+			return nil, fmt.Errorf("failed to scan applied migration: %w", err)
+		}
+		applied[version] = a
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+	return applied, nil
+}
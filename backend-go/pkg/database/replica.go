@@ -0,0 +1,37 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// AttachReplica connects a read-only replica for heavy reads, which then go through Reader.
+// Without a replica, or when it can't be reached here, Reader is the primary pool.
+func (db *DB) AttachReplica(ctx context.Context, connectionString string) error {
+	replica, err := OpenSupabaseDB(connectionString, db.tracer)
+	if err != nil {
+		return fmt.Errorf("replica: %w", err)
+	}
+	if err := replica.Pool.Ping(ctx); err != nil {
+		replica.Pool.Close()
+		return fmt.Errorf("failed to ping replica: %w", err)
+	}
+	db.replica = replica.Pool
+	return nil
+}
+
+// Reader returns the pool for reads that tolerate replication lag: snapshot, history and
+// analytics queries. It is the replica when one is attached, and the primary otherwise.
+func (db *DB) Reader() *pgxpool.Pool {
+	if db.replica != nil {
+		return db.replica
+	}
+	return db.Pool
+}
+
+// HasReplica reports whether reads are routed to a replica
+func (db *DB) HasReplica() bool {
+	return db.replica != nil
+}
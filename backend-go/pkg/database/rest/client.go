@@ -0,0 +1,174 @@
+// Package rest reaches a Supabase project's tables through its PostgREST API, for deployments
+// that cannot open a Postgres connection. Requests are authorized with the service key, which
+// bypasses row level security, so callers filter by owner themselves.
+package rest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// requestTimeout bounds each REST request
+const requestTimeout = 10 * time.Second
+
+// Client reads and writes the tables of a Supabase project through PostgREST
+type Client struct {
+	baseURL    string
+	serviceKey string
+	client     *http.Client
+}
+
+// NewClient creates a client for the project at supabaseURL, authorized with its service key
+func NewClient(supabaseURL, serviceKey string) *Client {
+	return &Client{
+		baseURL:    strings.TrimRight(supabaseURL, "/") + "/rest/v1",
+		serviceKey: serviceKey,
+		client:     &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// Error is an error response from PostgREST. Code is the Postgres SQLSTATE for errors the
+// database raised, such as 23505 for unique violations, and a PGRST code otherwise.
+type Error struct {
+	Status  int    `json:"-"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Details string `json:"details"`
+	Hint    string `json:"hint"`
+}
+
+func (e *Error) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("PostgREST responded %d: %s", e.Status, e.Message)
+	}
+	return fmt.Sprintf("PostgREST responded %d (%s): %s", e.Status, e.Code, e.Message)
+}
+
+// Eq filters a column to a value, as in query.Set("id", rest.Eq(id))
+func Eq(value any) string {
+	return "eq." + fmt.Sprint(value)
+}
+
+// IsNull filters a column to NULL
+func IsNull() string {
+	return "is.null"
+}
+
+// NotNull filters a column to values that aren't NULL
+func NotNull() string {
+	return "not.is.null"
+}
+
+// NotIn filters a column to none of the values
+func NotIn(values []string) string {
+	return "not.in.(" + quoteList(values) + ")"
+}
+
+// quoteList double-quotes each value, so commas and parentheses in them are not read as
+// list syntax
+func quoteList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		v = strings.ReplaceAll(v, `\`, `\\`)
+		quoted[i] = `"` + strings.ReplaceAll(v, `"`, `\"`) + `"`
+	}
+	return strings.Join(quoted, ",")
+}
+
+// Select reads the rows of table matching query into out, a pointer to a slice
+func (c *Client) Select(ctx context.Context, table string, query url.Values, out any) error {
+	return c.do(ctx, http.MethodGet, table, query, nil, "", out)
+}
+
+// Insert adds rows, a value or a slice, to table and reads the inserted rows into out
+func (c *Client) Insert(ctx context.Context, table string, rows, out any) error {
+	return c.do(ctx, http.MethodPost, table, nil, rows, "return=representation", out)
+}
+
+// InsertMissing adds those of rows that don't conflict on the onConflict columns, a
+// comma-separated list, and reads the inserted rows into out
+func (c *Client) InsertMissing(ctx context.Context, table, onConflict string, rows, out any) error {
+	query := url.Values{"on_conflict": {onConflict}}
+	return c.do(ctx, http.MethodPost, table, query, rows, "return=representation,resolution=ignore-duplicates", out)
+}
+
+// Upsert adds rows, updating the columns they set on those that conflict on the onConflict
+// columns, and reads the written rows into out
+func (c *Client) Upsert(ctx context.Context, table, onConflict string, rows, out any) error {
+	query := url.Values{"on_conflict": {onConflict}}
+	return c.do(ctx, http.MethodPost, table, query, rows, "return=representation,resolution=merge-duplicates", out)
+}
+
+// Update sets the columns in patch on the rows of table matching query and reads the updated
+// rows into out
+func (c *Client) Update(ctx context.Context, table string, query url.Values, patch, out any) error {
+	return c.do(ctx, http.MethodPatch, table, query, patch, "return=representation", out)
+}
+
+// Delete removes the rows of table matching query and reads them into out
+func (c *Client) Delete(ctx context.Context, table string, query url.Values, out any) error {
+	return c.do(ctx, http.MethodDelete, table, query, nil, "return=representation", out)
+}
+
+// do sends a request for table and decodes the JSON response into out when it is set
+func (c *Client) do(ctx context.Context, method, table string, query url.Values, body any, prefer string, out any) error {
+	endpoint := c.baseURL + "/" + url.PathEscape(table)
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	var payload io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode REST request: %w", err)
+		}
+		payload = bytes.NewReader(encoded)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, payload)
+	if err != nil {
+		return fmt.Errorf("invalid REST request: %w", err)
+	}
+	req.Header.Set("apikey", c.serviceKey)
+	req.Header.Set("Authorization", "Bearer "+c.serviceKey)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if out == nil {
+		// Nothing to decode, so don't have the rows sent back
+		prefer = strings.Replace(prefer, "return=representation", "return=minimal", 1)
+	}
+	if prefer != "" {
+		req.Header.Set("Prefer", prefer)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("REST request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		restErr := &Error{Status: resp.StatusCode}
+		excerpt, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		if json.Unmarshal(excerpt, restErr) != nil || restErr.Message == "" {
+			restErr.Message = string(bytes.TrimSpace(excerpt[:min(len(excerpt), 200)]))
+		}
+		return restErr
+	}
+	if out == nil {
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		return nil
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 16<<20)).Decode(out); err != nil {
+		return fmt.Errorf("invalid REST response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package database
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Stats describes the database connection for the detailed health check
+type Stats struct {
+	Connected   bool       `json:"connected"`
+	Primary     PoolStats  `json:"primary"`
+	Replica     *PoolStats `json:"replica,omitempty"`
+	LastError   string     `json:"last_error,omitempty"`
+	LastErrorAt *time.Time `json:"last_error_at,omitempty"`
+}
+
+// PoolStats describes a connection pool. Acquires that found no idle connection had to wait
+// for one to be opened or released; a growing share of them means the pool is too small.
+type PoolStats struct {
+	MaxConns          int32   `json:"max_connections"`
+	TotalConns        int32   `json:"total_connections"`
+	AcquiredConns     int32   `json:"acquired_connections"`
+	IdleConns         int32   `json:"idle_connections"`
+	ConstructingConns int32   `json:"constructing_connections"`
+	Acquires          int64   `json:"acquires"`
+	EmptyAcquires     int64   `json:"empty_acquires"`
+	CanceledAcquires  int64   `json:"canceled_acquires"`
+	AcquireWaitMs     float64 `json:"acquire_wait_ms"`     // total since the pool was opened
+	AvgAcquireWaitMs  float64 `json:"avg_acquire_wait_ms"` // per acquire
+	NewConns          int64   `json:"new_connections"`
+}
+
+// connError is a failed ping and when it happened
+type connError struct {
+	err error
+	at  time.Time
+}
+
+// Stats returns the connection pools' statistics and the last failed ping, which is kept
+// after the database answers again
+func (db *DB) Stats() Stats {
+	stats := Stats{Connected: db.Connected(), Primary: poolStats(db.Pool)}
+	if db.replica != nil {
+		replica := poolStats(db.replica)
+		stats.Replica = &replica
+	}
+	if last := db.lastErr.Load(); last != nil {
+		stats.LastError = last.err.Error()
+		stats.LastErrorAt = &last.at
+	}
+	return stats
+}
+
+func poolStats(pool *pgxpool.Pool) PoolStats {
+	s := pool.Stat()
+	stats := PoolStats{
+		MaxConns:          s.MaxConns(),
+		TotalConns:        s.TotalConns(),
+		AcquiredConns:     s.AcquiredConns(),
+		IdleConns:         s.IdleConns(),
+		ConstructingConns: s.ConstructingConns(),
+		Acquires:          s.AcquireCount(),
+		EmptyAcquires:     s.EmptyAcquireCount(),
+		CanceledAcquires:  s.CanceledAcquireCount(),
+		AcquireWaitMs:     float64(s.AcquireDuration()) / float64(time.Millisecond),
+		NewConns:          s.NewConnsCount(),
+	}
+	if stats.Acquires > 0 {
+		stats.AvgAcquireWaitMs = stats.AcquireWaitMs / float64(stats.Acquires)
+	}
+	return stats
+}
@@ -3,6 +3,9 @@ package database
 import (
 	"context"
 	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -10,10 +13,34 @@ import (
 // DB wraps the database connection pool
 type DB struct {
 	Pool *pgxpool.Pool
+
+	replica *pgxpool.Pool // see AttachReplica
+	tracer  *QueryTracer
+	caches  cacheRegistry // see Cache
+
+	connected atomic.Bool
+	lastErr   atomic.Pointer[connError]
 }
 
 // NewSupabaseDB creates a new database connection to Supabase
 func NewSupabaseDB(connectionString string) (*DB, error) {
+	db, err := OpenSupabaseDB(connectionString, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Test the connection
+	if err := db.ping(context.Background()); err != nil {
+		db.Pool.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+	return db, nil
+}
+
+// OpenSupabaseDB creates the connection pool without connecting. Connections are made as
+// they are needed, so the pool recovers by itself once the database is reachable; Connect
+// and Reconnect report when that happens. Queries are traced with tracer when it is set.
+func OpenSupabaseDB(connectionString string, tracer *QueryTracer) (*DB, error) {
 	if connectionString == "" {
 		return nil, fmt.Errorf("database connection string is empty")
 	}
@@ -29,19 +56,81 @@ func NewSupabaseDB(connectionString string) (*DB, error) {
 	config.MaxConnLifetime = 0        // Connections live forever
 	config.MaxConnIdleTime = 0        // Idle connections are not closed
 	config.HealthCheckPeriod = 0      // Disable health check (Supabase handles this)
+	if tracer != nil {
+		config.ConnConfig.Tracer = tracer
+	}
 
 	pool, err := pgxpool.NewWithConfig(context.Background(), config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create connection pool: %w", err)
 	}
+	return &DB{Pool: pool, tracer: tracer}, nil
+}
+
+// Connect pings the database up to attempts times, doubling the wait between attempts from
+// backoff, and returns the last error if none succeeds
+func (db *DB) Connect(ctx context.Context, attempts int, backoff time.Duration) error {
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if err = db.ping(ctx); err == nil {
+			return nil
+		}
+		if attempt == attempts {
+			break
+		}
+		log.Printf("[Database] ⚠ Connection attempt %d of %d failed, retrying in %s: %v", attempt, attempts, backoff, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+	return err
+}
 
-	// Test the connection
-	if err := pool.Ping(context.Background()); err != nil {
-		pool.Close()
-		return nil, fmt.Errorf("failed to ping database: %w", err)
+// Reconnect keeps pinging the database in the background, backing off from backoff up to
+// maxBackoff, until it answers or ctx is done; onConnect then runs once
+func (db *DB) Reconnect(ctx context.Context, backoff, maxBackoff time.Duration, onConnect func(ctx context.Context)) {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			if err := db.ping(ctx); err != nil {
+				backoff = min(backoff*2, maxBackoff)
+				log.Printf("[Database] ⚠ Still unreachable, retrying in %s: %v", backoff, err)
+				continue
+			}
+			log.Println("[Database] ✓ Reconnected")
+			onConnect(ctx)
+			return
+		}
+	}()
+}
+
+// Connected reports whether the database has answered since the pool was opened. Requests
+// needing the database are refused until it has.
+func (db *DB) Connected() bool {
+	return db != nil && db.connected.Load()
+}
+
+// LastError returns the error of the last failed connection attempt, nil once connected
+func (db *DB) LastError() error {
+	if last := db.lastErr.Load(); last != nil && !db.Connected() {
+		return last.err
 	}
+	return nil
+}
 
-	return &DB{Pool: pool}, nil
+func (db *DB) ping(ctx context.Context) error {
+	if err := db.Health(ctx); err != nil {
+		return err
+	}
+	db.connected.Store(true)
+	return nil
 }
 
 // Close closes the database connection pool
@@ -49,9 +138,16 @@ func (db *DB) Close() {
 	if db.Pool != nil {
 		db.Pool.Close()
 	}
+	if db.replica != nil {
+		db.replica.Close()
+	}
 }
 
-// Health checks if the database connection is alive
+// Health checks if the database connection is alive, recording a failure for Stats
 func (db *DB) Health(ctx context.Context) error {
-	return db.Pool.Ping(ctx)
+	if err := db.Pool.Ping(ctx); err != nil {
+		db.lastErr.Store(&connError{err: err, at: time.Now()})
+		return err
+	}
+	return nil
 }
@@ -0,0 +1,23 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// TimescaleVersion returns the installed TimescaleDB extension's version, or "" when the
+// database doesn't have it (time-series tables are then ordinary tables)
+func (db *DB) TimescaleVersion(ctx context.Context) (string, error) {
+	var version string
+	err := db.Pool.QueryRow(ctx, `SELECT extversion FROM pg_extension WHERE extname = 'timescaledb'`).Scan(&version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to detect timescaledb: %w", err)
+	}
+	return version, nil
+}
@@ -0,0 +1,121 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Query metrics, labeled by statement kind and the first table it names (see queryLabel)
+var (
+	queryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "periscope_db_query_duration_seconds",
+		Help:    "Duration of database queries.",
+		Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+	}, []string{"query"})
+	queryRows = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "periscope_db_query_rows_total",
+		Help: "Rows returned or affected by database queries.",
+	}, []string{"query"})
+	queryErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "periscope_db_query_errors_total",
+		Help: "Database queries that failed.",
+	}, []string{"query"})
+)
+
+// QueryTracer records every query's duration, rows and errors in the query metrics and logs
+// queries slower than a threshold. Logged statements show their bind parameters' types, never
+// their values, which may be user data or credentials.
+type QueryTracer struct {
+	slow time.Duration
+}
+
+// NewQueryTracer creates a query tracer logging queries that take at least slow; 0 logs none
+func NewQueryTracer(slow time.Duration) *QueryTracer {
+	return &QueryTracer{slow: slow}
+}
+
+type queryTraceKey struct{}
+
+type queryTrace struct {
+	sql   string
+	args  []any
+	start time.Time
+}
+
+// TraceQueryStart implements pgx.QueryTracer
+func (t *QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, queryTraceKey{}, &queryTrace{sql: data.SQL, args: data.Args, start: time.Now()})
+}
+
+// TraceQueryEnd implements pgx.QueryTracer
+func (t *QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	trace, ok := ctx.Value(queryTraceKey{}).(*queryTrace)
+	if !ok {
+		return
+	}
+	elapsed := time.Since(trace.start)
+	label := queryLabel(trace.sql)
+	rows := data.CommandTag.RowsAffected()
+
+	queryDuration.WithLabelValues(label).Observe(elapsed.Seconds())
+	queryRows.WithLabelValues(label).Add(float64(rows))
+	if data.Err != nil {
+		queryErrors.WithLabelValues(label).Inc()
+	}
+
+	if t.slow > 0 && elapsed >= t.slow {
+		log.Printf("[Database] ⚠ Slow query (%s, %d rows): %s %s",
+			elapsed.Round(time.Millisecond), rows, compactSQL(trace.sql), redactArgs(trace.args))
+	}
+}
+
+var (
+	sqlSpace = regexp.MustCompile(`\s+`)
+	sqlTable = regexp.MustCompile(`(?i)\b(?:from|into|update|join)\s+([a-z_][a-z0-9_.]*)`)
+)
+
+// queryLabel names a statement by its kind and the first table it reads or writes, as in
+// "select portfolios", keeping the metrics' label values few
+func queryLabel(sql string) string {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return "other"
+	}
+	kind := strings.ToLower(fields[0])
+	switch kind {
+	case "select", "insert", "update", "delete", "with":
+	case "begin", "commit", "rollback", "savepoint", "release":
+		return kind
+	default:
+		return "other"
+	}
+	if m := sqlTable.FindStringSubmatch(sql); m != nil {
+		return kind + " " + strings.ToLower(m[1])
+	}
+	return kind
+}
+
+// compactSQL puts a statement on one line
+func compactSQL(sql string) string {
+	return strings.TrimSpace(sqlSpace.ReplaceAllString(sql, " "))
+}
+
+// redactArgs describes bind parameters by type only, as in [$1=string $2=int64]
+func redactArgs(args []any) string {
+	if len(args) == 0 {
+		return ""
+	}
+	parts := make([]string, len(args))
+	for i, arg := range args {
+		parts[i] = fmt.Sprintf("$%d=%T", i+1, arg)
+	}
+	return "[" + strings.Join(parts, " ") + "]"
+}
@@ -0,0 +1,44 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// txKey is the context key of the transaction WithTx runs its function in
+type txKey struct{}
+
+// WithTx runs fn in a transaction, committing it if fn returns nil and rolling it back if fn
+// fails or panics. The context fn is given carries the transaction: a WithTx called with it,
+// as when one repository write is made inside another, runs in a savepoint of the outer
+// transaction rather than a transaction of its own, so the outer commit or rollback covers
+// both and a failed inner write only undoes its own changes.
+func (db *DB) WithTx(ctx context.Context, fn func(ctx context.Context, tx pgx.Tx) error) error {
+	var tx pgx.Tx
+	var err error
+	if outer, ok := TxFromContext(ctx); ok {
+		tx, err = outer.Begin(ctx)
+	} else {
+		tx, err = db.Pool.Begin(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx), tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// TxFromContext returns the transaction a WithTx function is running in, if any
+func TxFromContext(ctx context.Context) (pgx.Tx, bool) {
+	tx, ok := ctx.Value(txKey{}).(pgx.Tx)
+	return tx, ok
+}
@@ -5,9 +5,13 @@ import (
 	"net/http"
 )
 
-// AppError represents an application error with HTTP status code
+// AppError represents an application error with HTTP status code. Code is a stable,
+// machine-readable name for the kind of error, and Details carries any structured context
+// the client can act on, such as the accepted values of a parameter.
 type AppError struct {
+	Code       string `json:"code"`
 	Message    string `json:"message"`
+	Details    any    `json:"details,omitempty"`
 	StatusCode int    `json:"-"`
 	Err        error  `json:"-"`
 }
@@ -25,10 +29,17 @@ func (e *AppError) Unwrap() error {
 	return e.Err
 }
 
+// WithDetails attaches structured context to the error and returns it
+func (e *AppError) WithDetails(details any) *AppError {
+	e.Details = details
+	return e
+}
+
 // Common error constructors
 
 func NewBadRequestError(message string, err error) *AppError {
 	return &AppError{
+		Code:       "bad_request",
 		Message:    message,
 		StatusCode: http.StatusBadRequest,
 		Err:        err,
@@ -37,6 +48,7 @@ func NewBadRequestError(message string, err error) *AppError {
 
 func NewNotFoundError(message string) *AppError {
 	return &AppError{
+		Code:       "not_found",
 		Message:    message,
 		StatusCode: http.StatusNotFound,
 	}
@@ -44,6 +56,7 @@ func NewNotFoundError(message string) *AppError {
 
 func NewInternalError(message string, err error) *AppError {
 	return &AppError{
+		Code:       "internal_error",
 		Message:    message,
 		StatusCode: http.StatusInternalServerError,
 		Err:        err,
@@ -52,6 +65,7 @@ func NewInternalError(message string, err error) *AppError {
 
 func NewUnauthorizedError(message string) *AppError {
 	return &AppError{
+		Code:       "unauthorized",
 		Message:    message,
 		StatusCode: http.StatusUnauthorized,
 	}
@@ -59,7 +73,58 @@ func NewUnauthorizedError(message string) *AppError {
 
 func NewRateLimitError(message string) *AppError {
 	return &AppError{
+		Code:       "rate_limited",
 		Message:    message,
 		StatusCode: http.StatusTooManyRequests,
 	}
 }
+
+func NewServiceUnavailableError(message string) *AppError {
+	return &AppError{
+		Code:       "service_unavailable",
+		Message:    message,
+		StatusCode: http.StatusServiceUnavailable,
+	}
+}
+
+func NewConflictError(message string) *AppError {
+	return &AppError{
+		Code:       "conflict",
+		Message:    message,
+		StatusCode: http.StatusConflict,
+	}
+}
+
+func NewForbiddenError(message string) *AppError {
+	return &AppError{
+		Code:       "forbidden",
+		Message:    message,
+		StatusCode: http.StatusForbidden,
+	}
+}
+
+func NewNotAcceptableError(message string) *AppError {
+	return &AppError{
+		Code:       "not_acceptable",
+		Message:    message,
+		StatusCode: http.StatusNotAcceptable,
+	}
+}
+
+func NewTimeoutError(message string, err error) *AppError {
+	return &AppError{
+		Code:       "timeout",
+		Message:    message,
+		StatusCode: http.StatusGatewayTimeout,
+		Err:        err,
+	}
+}
+
+func NewPayloadTooLargeError(message string, err error) *AppError {
+	return &AppError{
+		Code:       "payload_too_large",
+		Message:    message,
+		StatusCode: http.StatusRequestEntityTooLarge,
+		Err:        err,
+	}
+}
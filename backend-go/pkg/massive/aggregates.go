@@ -0,0 +1,60 @@
+package massive
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+)
+
+// AggregatesResponse represents the aggregate bars response
+type AggregatesResponse struct {
+	Ticker       string `json:"ticker"`
+	Status       string `json:"status"`
+	RequestID    string `json:"request_id"`
+	ResultsCount int    `json:"resultsCount"`
+	Results      []Bar  `json:"results"`
+}
+
+// Bar is a single OHLCV aggregate bar
+type Bar struct {
+	Open      float64 `json:"o"`
+	High      float64 `json:"h"`
+	Low       float64 `json:"l"`
+	Close     float64 `json:"c"`
+	Volume    float64 `json:"v"`
+	VWAP      float64 `json:"vw,omitempty"`
+	Timestamp int64   `json:"t"` // Unix milliseconds of the bar start
+}
+
+// Time returns the bar start as a time.Time
+func (b Bar) Time() time.Time {
+	return time.UnixMilli(b.Timestamp)
+}
+
+// GetDailyBars fetches split-adjusted daily bars for a ticker between two dates (inclusive),
+// ordered oldest first
+func (c *Client) GetDailyBars(ctx context.Context, ticker string, from, to time.Time) ([]Bar, error) {
+	log.Printf("[Massive API] Fetching daily bars for %s (%s to %s)", ticker, from.Format("2006-01-02"), to.Format("2006-01-02"))
+
+	u, err := url.Parse(fmt.Sprintf("%s/v2/aggs/ticker/%s/range/1/day/%s/%s",
+		c.rootURL(), url.PathEscape(ticker), from.Format("2006-01-02"), to.Format("2006-01-02")))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse URL: %w", err)
+	}
+
+	q := u.Query()
+	q.Set("adjusted", "true")
+	q.Set("sort", "asc")
+	q.Set("limit", "50000")
+	u.RawQuery = q.Encode()
+
+	var result AggregatesResponse
+	if err := c.getJSON(ctx, u, &result); err != nil {
+		return nil, err
+	}
+
+	log.Printf("[Massive API] ✓ Fetched %d daily bars for %s", len(result.Results), ticker)
+	return result.Results, nil
+}
@@ -5,22 +5,30 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"iter"
 	"log"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aaronbengochea/periscope/backend-go/internal/models"
 	"golang.org/x/time/rate"
 )
 
+// maxChainPages stops chain pagination from looping forever (20 pages = 5000 contracts)
+const maxChainPages = 20
+
 // Client wraps the Massive API client
 type Client struct {
 	httpClient *http.Client
 	baseURL    string
 	apiKey     string
 	limiter    *rate.Limiter
+
+	// Limiters for the keys of users who bring their own, by key
+	keyLimiters sync.Map
 }
 
 // OptionsChainParams contains optional query parameters for options chain requests
@@ -35,7 +43,8 @@ type OptionsChainParams struct {
 func NewClient(baseURL, apiKey string) *Client {
 	return &Client{
 		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
+			Timeout:   10 * time.Second,
+			Transport: requestIDTransport{base: http.DefaultTransport},
 		},
 		baseURL: baseURL,
 		apiKey:  apiKey,
@@ -46,62 +55,73 @@ func NewClient(baseURL, apiKey string) *Client {
 // GetOptionsChain fetches the options chain for a given underlying ticker
 // Automatically follows pagination to get all available contracts
 func (c *Client) GetOptionsChain(ctx context.Context, underlyingTicker string, params *OptionsChainParams) (*models.OptionsChainResponse, error) {
-	allResults := []models.OptionContract{}
 	var firstResponse *models.OptionsChainResponse
-	nextURL := ""
-	pageCount := 0
-	maxPages := 20 // Safety limit to prevent infinite loops (20 pages = 5000 contracts)
-
-	for {
-		pageCount++
-		if pageCount > maxPages {
-			log.Printf("[Massive API] ⚠ Reached max page limit (%d), stopping pagination", maxPages)
-			break
-		}
-
-		var response *models.OptionsChainResponse
-		var err error
-
-		if nextURL != "" {
-			// Fetch next page using next_url
-			response, err = c.fetchPage(ctx, nextURL)
-		} else {
-			// Fetch first page with params
-			response, err = c.fetchFirstPage(ctx, underlyingTicker, params)
-		}
-
+	allResults := []models.OptionContract{}
+	for response, err := range c.OptionsChainPages(ctx, underlyingTicker, params) {
 		if err != nil {
 			return nil, err
 		}
-
 		// Store first response for metadata
 		if firstResponse == nil {
 			firstResponse = response
 		}
-
-		// Accumulate results
 		allResults = append(allResults, response.Results...)
-		log.Printf("[Massive API] Page %d: fetched %d contracts (total: %d)", pageCount, len(response.Results), len(allResults))
-
-		// Check if there are more pages
-		if response.NextURL == nil || *response.NextURL == "" {
-			break
-		}
-
-		nextURL = *response.NextURL
 	}
 
 	// Return combined response
 	firstResponse.Results = allResults
-	log.Printf("[Massive API] ✓ Total contracts fetched: %d across %d pages", len(allResults), pageCount)
-
 	return firstResponse, nil
 }
 
+// OptionsChainPages iterates over the pages of an options chain as they are fetched, so a
+// caller can use the first contracts before the last page arrives. A failed fetch is yielded
+// as the error and ends the iteration.
+func (c *Client) OptionsChainPages(ctx context.Context, underlyingTicker string, params *OptionsChainParams) iter.Seq2[*models.OptionsChainResponse, error] {
+	return func(yield func(*models.OptionsChainResponse, error) bool) {
+		nextURL := ""
+		total := 0
+		for pageCount := 1; ; pageCount++ {
+			if pageCount > maxChainPages {
+				log.Printf("[Massive API] ⚠ Reached max page limit (%d), stopping pagination", maxChainPages)
+				return
+			}
+
+			var response *models.OptionsChainResponse
+			var err error
+			if nextURL != "" {
+				// Fetch next page using next_url
+				response, err = c.fetchPage(ctx, nextURL)
+			} else {
+				// Fetch first page with params
+				response, err = c.fetchFirstPage(ctx, underlyingTicker, params)
+			}
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			total += len(response.Results)
+			log.Printf("[Massive API] Page %d: fetched %d contracts (total: %d)", pageCount, len(response.Results), total)
+			last := response.NextURL == nil || *response.NextURL == ""
+			if !last {
+				nextURL = *response.NextURL
+			}
+			if !yield(response, nil) {
+				return
+			}
+			// Check if there are more pages
+			if last {
+				log.Printf("[Massive API] ✓ Total contracts fetched: %d across %d pages", total, pageCount)
+				return
+			}
+		}
+	}
+}
+
 // fetchFirstPage fetches the first page of options chain
 func (c *Client) fetchFirstPage(ctx context.Context, underlyingTicker string, params *OptionsChainParams) (*models.OptionsChainResponse, error) {
 	// Apply rate limiting
-	if err := c.limiter.Wait(ctx); err != nil {
+	if err := c.limiterFor(ctx).Wait(ctx); err != nil {
 		return nil, fmt.Errorf("rate limit wait failed: %w", err)
 	}
 
@@ -113,7 +133,7 @@ func (c *Client) fetchFirstPage(ctx context.Context, underlyingTicker string, pa
 
 	// Add query parameters
 	q := u.Query()
-	q.Set("apiKey", c.apiKey)
+	q.Set("apiKey", c.apiKeyFor(ctx))
 
 	if params != nil {
 		if params.Limit != nil {
@@ -138,7 +158,7 @@ func (c *Client) fetchFirstPage(ctx context.Context, underlyingTicker string, pa
 // fetchPage fetches a page using the next_url from pagination
 func (c *Client) fetchPage(ctx context.Context, nextURL string) (*models.OptionsChainResponse, error) {
 	// Apply rate limiting
-	if err := c.limiter.Wait(ctx); err != nil {
+	if err := c.limiterFor(ctx).Wait(ctx); err != nil {
 		return nil, fmt.Errorf("rate limit wait failed: %w", err)
 	}
 
@@ -149,7 +169,7 @@ func (c *Client) fetchPage(ctx context.Context, nextURL string) (*models.Options
 	}
 
 	q := u.Query()
-	q.Set("apiKey", c.apiKey)
+	q.Set("apiKey", c.apiKeyFor(ctx))
 	u.RawQuery = q.Encode()
 
 	return c.executeRequest(ctx, u.String())
@@ -169,6 +189,7 @@ func (c *Client) executeRequest(ctx context.Context, urlStr string) (*models.Opt
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
 	defer resp.Body.Close()
+	countPage(ctx)
 
 	// Check status code
 	if resp.StatusCode != http.StatusOK {
@@ -185,6 +206,47 @@ func (c *Client) executeRequest(ctx context.Context, urlStr string) (*models.Opt
 	return &result, nil
 }
 
+// rootURL returns the API host without the version segment so that
+// endpoints living outside /v3 (benzinga, v2 aggregates) can be reached
+func (c *Client) rootURL() string {
+	return strings.TrimSuffix(strings.TrimRight(c.baseURL, "/"), "/v3")
+}
+
+// getJSON executes a rate-limited GET request and decodes the JSON body into out
+func (c *Client) getJSON(ctx context.Context, u *url.URL, out interface{}) error {
+	// Apply rate limiting
+	if err := c.limiterFor(ctx).Wait(ctx); err != nil {
+		return fmt.Errorf("rate limit wait failed: %w", err)
+	}
+
+	q := u.Query()
+	q.Set("apiKey", c.apiKeyFor(ctx))
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+	countPage(ctx)
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
+
 // Helper functions for logging
 func getStringValue(details *models.ContractDetails, field string) string {
 	if details == nil || details.Ticker == nil {
@@ -269,7 +331,7 @@ func (c *Client) GetContractDetails(ctx context.Context, contractTickers []strin
 		batch := contractTickers[i:end]
 
 		// Apply rate limiting
-		if err := c.limiter.Wait(ctx); err != nil {
+		if err := c.limiterFor(ctx).Wait(ctx); err != nil {
 			return nil, fmt.Errorf("rate limit wait failed: %w", err)
 		}
 
@@ -281,16 +343,14 @@ func (c *Client) GetContractDetails(ctx context.Context, contractTickers []strin
 
 		// Add query parameters
 		q := u.Query()
-		q.Set("apiKey", c.apiKey)
+		q.Set("apiKey", c.apiKeyFor(ctx))
 		q.Set("ticker.any_of", strings.Join(batch, ","))
 
 		u.RawQuery = q.Encode()
 
 		// Log the URL (mask API key)
 		logURL := u.String()
-		if len(c.apiKey) > 3 {
-			logURL = strings.Replace(logURL, c.apiKey, c.apiKey[:3]+"***", 1)
-		}
+		logURL = maskAPIKey(logURL, c.apiKeyFor(ctx))
 		log.Printf("[Massive API] Unified snapshot URL: %s", logURL)
 		log.Printf("[Massive API] Unified snapshot request for batch %d-%d", i, end)
 
@@ -306,6 +366,7 @@ func (c *Client) GetContractDetails(ctx context.Context, contractTickers []strin
 			return nil, fmt.Errorf("failed to execute request: %w", err)
 		}
 		defer resp.Body.Close()
+		countPage(ctx)
 
 		// Check status code
 		if resp.StatusCode != http.StatusOK {
@@ -320,6 +381,7 @@ func (c *Client) GetContractDetails(ctx context.Context, contractTickers []strin
 		}
 
 		allContracts = append(allContracts, result.Results...)
+		countContracts(ctx, len(result.Results))
 		log.Printf("[Massive API] Batch %d-%d: fetched %d contracts", i, end, len(result.Results))
 	}
 
@@ -378,7 +440,7 @@ func (c *Client) GetStockPrice(ctx context.Context, ticker string) (*float64, er
 	log.Printf("[Massive API] Fetching stock price for %s", ticker)
 
 	// Apply rate limiting
-	if err := c.limiter.Wait(ctx); err != nil {
+	if err := c.limiterFor(ctx).Wait(ctx); err != nil {
 		return nil, fmt.Errorf("rate limit wait failed: %w", err)
 	}
 
@@ -391,11 +453,11 @@ func (c *Client) GetStockPrice(ctx context.Context, ticker string) (*float64, er
 	// Add query parameters
 	// Note: Use just ticker parameter without type to get stock data
 	q := u.Query()
-	q.Set("apiKey", c.apiKey)
+	q.Set("apiKey", c.apiKeyFor(ctx))
 	q.Set("ticker", ticker)
 	u.RawQuery = q.Encode()
 
-	log.Printf("[Massive API] Stock snapshot URL: %s", u.String()[:len(u.String())-len(c.apiKey)]+"***")
+	log.Printf("[Massive API] Stock snapshot URL: %s", maskAPIKey(u.String(), c.apiKeyFor(ctx)))
 
 	// Create request
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
@@ -409,6 +471,7 @@ func (c *Client) GetStockPrice(ctx context.Context, ticker string) (*float64, er
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
 	defer resp.Body.Close()
+	countPage(ctx)
 
 	// Check status code
 	if resp.StatusCode != http.StatusOK {
@@ -0,0 +1,63 @@
+package massive
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+)
+
+// Dividend types
+const (
+	DividendRegular = "CD" // consistent, recurring cash dividend
+	DividendSpecial = "SC" // one-off special cash dividend
+)
+
+// DividendsResponse represents the dividends reference response
+type DividendsResponse struct {
+	Status    string     `json:"status"`
+	RequestID string     `json:"request_id"`
+	Results   []Dividend `json:"results"`
+}
+
+// Dividend represents a declared cash dividend
+type Dividend struct {
+	Ticker          string  `json:"ticker"`
+	CashAmount      float64 `json:"cash_amount"` // per share
+	Currency        string  `json:"currency,omitempty"`
+	DividendType    string  `json:"dividend_type,omitempty"`
+	Frequency       int     `json:"frequency"` // payments per year, 0 for one-time
+	DeclarationDate string  `json:"declaration_date,omitempty"`
+	ExDividendDate  string  `json:"ex_dividend_date"` // YYYY-MM-DD
+	RecordDate      string  `json:"record_date,omitempty"`
+	PayDate         string  `json:"pay_date,omitempty"`
+}
+
+// GetDividends fetches the cash dividends of a ticker with an ex-dividend date on or after
+// the given date, ordered by ex-dividend date ascending. Announced dividends with future
+// ex-dividend dates are included.
+func (c *Client) GetDividends(ctx context.Context, ticker string, from time.Time) ([]Dividend, error) {
+	log.Printf("[Massive API] Fetching dividends for %s since %s", ticker, from.Format("2006-01-02"))
+
+	u, err := url.Parse(fmt.Sprintf("%s/v3/reference/dividends", c.rootURL()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse URL: %w", err)
+	}
+
+	q := u.Query()
+	q.Set("ticker", ticker)
+	q.Set("ex_dividend_date.gte", from.Format("2006-01-02"))
+	q.Set("order", "asc")
+	q.Set("sort", "ex_dividend_date")
+	q.Set("limit", "1000")
+	u.RawQuery = q.Encode()
+
+	var result DividendsResponse
+	if err := c.getJSON(ctx, u, &result); err != nil {
+		return nil, err
+	}
+
+	log.Printf("[Massive API] ✓ Found %d dividends for %s", len(result.Results), ticker)
+	return result.Results, nil
+}
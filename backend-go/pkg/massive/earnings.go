@@ -0,0 +1,69 @@
+package massive
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+)
+
+// EarningsResponse represents the earnings calendar response
+type EarningsResponse struct {
+	Status    string          `json:"status"`
+	RequestID string          `json:"request_id"`
+	Results   []EarningsEvent `json:"results"`
+}
+
+// EarningsEvent represents a single scheduled or reported earnings release
+type EarningsEvent struct {
+	Ticker           string   `json:"ticker"`
+	CompanyName      string   `json:"company_name,omitempty"`
+	Date             string   `json:"date"`                  // YYYY-MM-DD
+	Time             string   `json:"time,omitempty"`        // HH:MM:SS (Eastern)
+	DateStatus       string   `json:"date_status,omitempty"` // "confirmed" or "projected"
+	FiscalPeriod     string   `json:"fiscal_period,omitempty"`
+	FiscalYear       int      `json:"fiscal_year,omitempty"`
+	EstimatedEPS     *float64 `json:"estimated_eps,omitempty"`
+	ActualEPS        *float64 `json:"actual_eps,omitempty"`
+	EstimatedRevenue *float64 `json:"estimated_revenue,omitempty"`
+}
+
+// BeforeOpen reports whether the release happens before the regular session opens.
+// Releases with no time are treated as after the close, the more common case.
+func (e *EarningsEvent) BeforeOpen() bool {
+	if e.Time == "" {
+		return false
+	}
+	t, err := time.Parse("15:04:05", e.Time)
+	if err != nil {
+		return false
+	}
+	return t.Hour() < 9 || (t.Hour() == 9 && t.Minute() < 30)
+}
+
+// GetUpcomingEarnings fetches earnings releases for a ticker on or after the given date,
+// ordered by date ascending
+func (c *Client) GetUpcomingEarnings(ctx context.Context, ticker string, from time.Time) ([]EarningsEvent, error) {
+	log.Printf("[Massive API] Fetching upcoming earnings for %s", ticker)
+
+	u, err := url.Parse(fmt.Sprintf("%s/benzinga/v1/earnings", c.rootURL()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse URL: %w", err)
+	}
+
+	q := u.Query()
+	q.Set("ticker", ticker)
+	q.Set("date.gte", from.Format("2006-01-02"))
+	q.Set("sort", "date.asc")
+	q.Set("limit", "10")
+	u.RawQuery = q.Encode()
+
+	var result EarningsResponse
+	if err := c.getJSON(ctx, u, &result); err != nil {
+		return nil, err
+	}
+
+	log.Printf("[Massive API] ✓ Found %d upcoming earnings events for %s", len(result.Results), ticker)
+	return result.Results, nil
+}
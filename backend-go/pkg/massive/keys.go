@@ -0,0 +1,93 @@
+package massive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/time/rate"
+)
+
+// ErrInvalidAPIKey is returned by VerifyAPIKey for keys Massive rejects
+var ErrInvalidAPIKey = errors.New("massive API key was rejected")
+
+type apiKeyKey struct{}
+
+// WithAPIKey returns a context whose API calls are made with key instead of the client's own,
+// so they count against the key holder's Massive plan. An empty key leaves the client's key.
+func WithAPIKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, apiKeyKey{}, key)
+}
+
+// HasAPIKey reports whether the context's API calls are made with a key of their own
+func HasAPIKey(ctx context.Context) bool {
+	key, _ := ctx.Value(apiKeyKey{}).(string)
+	return key != ""
+}
+
+// apiKeyFor returns the key to make the context's API calls with
+func (c *Client) apiKeyFor(ctx context.Context) string {
+	if key, _ := ctx.Value(apiKeyKey{}).(string); key != "" {
+		return key
+	}
+	return c.apiKey
+}
+
+// limiterFor returns the rate limiter for the context's key. Each key has its own, as each is
+// rate limited by Massive on its own.
+func (c *Client) limiterFor(ctx context.Context) *rate.Limiter {
+	key, _ := ctx.Value(apiKeyKey{}).(string)
+	if key == "" || key == c.apiKey {
+		return c.limiter
+	}
+	limiter, _ := c.keyLimiters.LoadOrStore(key, rate.NewLimiter(rate.Limit(10), 1))
+	return limiter.(*rate.Limiter)
+}
+
+// VerifyAPIKey checks that Massive accepts key with a single small reference request. It
+// returns ErrInvalidAPIKey if the key is refused, and another error if Massive could not be
+// asked.
+func (c *Client) VerifyAPIKey(ctx context.Context, key string) error {
+	ctx = WithAPIKey(ctx, key)
+	if err := c.limiterFor(ctx).Wait(ctx); err != nil {
+		return fmt.Errorf("rate limit wait failed: %w", err)
+	}
+
+	u, err := url.Parse(fmt.Sprintf("%s/v3/reference/tickers", c.rootURL()))
+	if err != nil {
+		return fmt.Errorf("failed to parse URL: %w", err)
+	}
+	q := u.Query()
+	q.Set("apiKey", key)
+	q.Set("limit", "1")
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return ErrInvalidAPIKey
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// maskAPIKey hides all but the first characters of key in a URL for logging
+func maskAPIKey(urlStr, key string) string {
+	if len(key) <= 3 {
+		return urlStr
+	}
+	return strings.Replace(urlStr, key, key[:3]+"***", 1)
+}
@@ -0,0 +1,86 @@
+package massive
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+)
+
+// maxSnapshotTickers is the most tickers the unified snapshot accepts per request
+const maxSnapshotTickers = 250
+
+// GetStockPrices fetches the latest price for several tickers using the unified snapshot,
+// batching up to 250 tickers per request. The session close is used, falling back to the
+// previous close; tickers without price data are omitted from the result.
+func (c *Client) GetStockPrices(ctx context.Context, tickers []string) (map[string]float64, error) {
+	snapshots, err := c.GetStockSnapshots(ctx, tickers)
+	if err != nil {
+		return nil, err
+	}
+
+	prices := make(map[string]float64, len(snapshots))
+	for ticker, stock := range snapshots {
+		if price := stock.Price(); price != nil {
+			prices[ticker] = *price
+		}
+	}
+
+	log.Printf("[Massive API] ✓ Priced %d of %d tickers", len(prices), len(tickers))
+	return prices, nil
+}
+
+// GetStockSnapshots fetches the session data of several tickers using the unified
+// snapshot, batching up to 250 tickers per request. Tickers the snapshot does not return
+// are omitted from the result.
+func (c *Client) GetStockSnapshots(ctx context.Context, tickers []string) (map[string]StockResult, error) {
+	snapshots := make(map[string]StockResult, len(tickers))
+	if len(tickers) == 0 {
+		return snapshots, nil
+	}
+
+	log.Printf("[Massive API] Fetching stock snapshots for %d tickers", len(tickers))
+
+	for i := 0; i < len(tickers); i += maxSnapshotTickers {
+		end := min(i+maxSnapshotTickers, len(tickers))
+
+		u, err := url.Parse(fmt.Sprintf("%s/snapshot", c.baseURL))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse URL: %w", err)
+		}
+
+		q := u.Query()
+		q.Set("ticker.any_of", strings.Join(tickers[i:end], ","))
+		q.Set("limit", fmt.Sprintf("%d", maxSnapshotTickers))
+		u.RawQuery = q.Encode()
+
+		var result StockSnapshot
+		if err := c.getJSON(ctx, u, &result); err != nil {
+			return nil, err
+		}
+
+		for _, stock := range result.Results {
+			if stock.Session != nil {
+				snapshots[stock.Ticker] = stock
+			}
+		}
+	}
+
+	return snapshots, nil
+}
+
+// Price returns the session close, falling back to the previous close, or nil when
+// neither is known
+func (r *StockResult) Price() *float64 {
+	if r.Session == nil {
+		return nil
+	}
+	if r.Session.Close != nil && *r.Session.Close > 0 {
+		return r.Session.Close
+	}
+	if r.Session.PreviousClose != nil && *r.Session.PreviousClose > 0 {
+		return r.Session.PreviousClose
+	}
+	return nil
+}
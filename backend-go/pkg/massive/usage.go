@@ -0,0 +1,46 @@
+package massive
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// Usage counts the upstream calls the client makes for one piece of work, so the credits it
+// consumes can be attributed to whoever asked for it. Attach one to a context with WithUsage;
+// it is safe for concurrent use.
+type Usage struct {
+	pages     atomic.Int64
+	contracts atomic.Int64
+}
+
+// Pages returns how many responses were fetched from the API, each page of a paginated
+// listing counting separately
+func (u *Usage) Pages() int64 {
+	return u.pages.Load()
+}
+
+// Contracts returns how many option contracts were fetched through contract details
+func (u *Usage) Contracts() int64 {
+	return u.contracts.Load()
+}
+
+type usageKey struct{}
+
+// WithUsage returns a context whose API calls are counted in u
+func WithUsage(ctx context.Context, u *Usage) context.Context {
+	return context.WithValue(ctx, usageKey{}, u)
+}
+
+// countPage records a fetched response against the context's usage, if it has one
+func countPage(ctx context.Context) {
+	if u, ok := ctx.Value(usageKey{}).(*Usage); ok {
+		u.pages.Add(1)
+	}
+}
+
+// countContracts records contracts fetched through contract details
+func countContracts(ctx context.Context, n int) {
+	if u, ok := ctx.Value(usageKey{}).(*Usage); ok {
+		u.contracts.Add(int64(n))
+	}
+}
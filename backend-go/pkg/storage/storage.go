@@ -0,0 +1,127 @@
+// Package storage writes files to object storage: a Supabase Storage bucket, reached with the
+// project's service key, or a local directory for development
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Store writes objects to a bucket or directory
+type Store interface {
+	// Put writes size bytes read from body to the object at path, a slash-separated name
+	// relative to the store, replacing any object already there
+	Put(ctx context.Context, path string, body io.Reader, size int64, contentType string) error
+}
+
+// uploadTimeout bounds each upload, which may be a large backup file
+const uploadTimeout = 10 * time.Minute
+
+// Supabase stores objects in a Supabase Storage bucket. The service key bypasses the
+// bucket's policies, so the bucket should be private.
+type Supabase struct {
+	baseURL    string
+	bucket     string
+	serviceKey string
+	client     *http.Client
+}
+
+// NewSupabase creates a store for a bucket of the project at supabaseURL
+func NewSupabase(supabaseURL, serviceKey, bucket string) *Supabase {
+	return &Supabase{
+		baseURL:    strings.TrimRight(supabaseURL, "/") + "/storage/v1",
+		bucket:     bucket,
+		serviceKey: serviceKey,
+		client:     &http.Client{Timeout: uploadTimeout},
+	}
+}
+
+// Put implements Store
+func (s *Supabase) Put(ctx context.Context, path string, body io.Reader, size int64, contentType string) error {
+	endpoint := s.baseURL + "/object/" + url.PathEscape(s.bucket) + "/" + escapePath(path)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, body)
+	if err != nil {
+		return fmt.Errorf("invalid storage request: %w", err)
+	}
+	req.ContentLength = size
+	req.Header.Set("apikey", s.serviceKey)
+	req.Header.Set("Authorization", "Bearer "+s.serviceKey)
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("x-upsert", "true")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		excerpt, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		var storageErr struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(excerpt, &storageErr) != nil || storageErr.Message == "" {
+			storageErr.Message = string(bytes.TrimSpace(excerpt[:min(len(excerpt), 200)]))
+		}
+		return fmt.Errorf("failed to upload %s: storage responded %d: %s", path, resp.StatusCode, storageErr.Message)
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	return nil
+}
+
+// escapePath escapes each segment of a slash-separated object path
+func escapePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return strings.Join(segments, "/")
+}
+
+// Dir stores objects as files under a local directory
+type Dir struct {
+	root string
+}
+
+// NewDir creates a store writing under root, which is created as needed
+func NewDir(root string) *Dir {
+	return &Dir{root: root}
+}
+
+// Put implements Store. The file is written under a temporary name and renamed into place,
+// so a failed write leaves no partial object.
+func (d *Dir) Put(ctx context.Context, path string, body io.Reader, size int64, contentType string) error {
+	target := filepath.Join(d.root, filepath.FromSlash(path))
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", path, err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	written, err := io.Copy(tmp, body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if written != size {
+		return fmt.Errorf("failed to write %s: wrote %d of %d bytes", path, written, size)
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,292 @@
+// Package xlsx writes simple Office Open XML workbooks: one header row per sheet followed by
+// rows of text, numbers and dates, with per-column number formats. It covers exports, not
+// formulas, merged cells or reading.
+package xlsx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+)
+
+// Format is the number format applied to a column's cells
+type Format int
+
+// Column formats. Percent values are fractions (0.1234 shows as 12.34%) and Date values are
+// YYYY-MM-DD strings or time.Time.
+const (
+	General Format = iota
+	Integer
+	Money
+	Price
+	Percent
+	Date
+)
+
+// styleIndex maps a format to its cellXfs entry in styles.xml; entry 1 is the header style
+var styleIndex = map[Format]int{General: 0, Integer: 2, Money: 3, Price: 4, Percent: 5, Date: 6}
+
+// Column is a sheet column's header, format and width in characters (0 for a default
+// based on the header)
+type Column struct {
+	Header string
+	Format Format
+	Width  float64
+}
+
+// Sheet is a worksheet. Row values may be string, float64, int, int64, bool, time.Time or
+// nil for an empty cell.
+type Sheet struct {
+	Name    string
+	Columns []Column
+	Rows    [][]any
+}
+
+// excelEpoch is day zero of Excel's 1900 date system, adjusted for its 1900 leap year bug
+var excelEpoch = time.Date(1899, time.December, 30, 0, 0, 0, 0, time.UTC)
+
+// Write encodes the sheets as an .xlsx workbook
+func Write(w io.Writer, sheets []Sheet) error {
+	if len(sheets) == 0 {
+		return fmt.Errorf("workbook needs at least one sheet")
+	}
+
+	zw := zip.NewWriter(w)
+	files := []struct {
+		name string
+		body string
+	}{
+		{"[Content_Types].xml", contentTypes(len(sheets))},
+		{"_rels/.rels", rootRels},
+		{"xl/workbook.xml", workbook(sheets)},
+		{"xl/_rels/workbook.xml.rels", workbookRels(len(sheets))},
+		{"xl/styles.xml", styles},
+	}
+	for _, f := range files {
+		if err := writeFile(zw, f.name, f.body); err != nil {
+			return err
+		}
+	}
+	for i := range sheets {
+		body, err := worksheet(&sheets[i])
+		if err != nil {
+			return fmt.Errorf("sheet %q: %w", sheets[i].Name, err)
+		}
+		if err := writeFile(zw, fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), body); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func writeFile(zw *zip.Writer, name, body string) error {
+	f, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to add %s: %w", name, err)
+	}
+	if _, err := io.WriteString(f, body); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// worksheet renders a sheet with a frozen, bold header row
+func worksheet(s *Sheet) (string, error) {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	b.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`)
+
+	if len(s.Columns) > 0 {
+		b.WriteString(`<cols>`)
+		for i, col := range s.Columns {
+			width := col.Width
+			if width == 0 {
+				width = math.Max(float64(len(col.Header))+2, 12)
+			}
+			fmt.Fprintf(&b, `<col min="%d" max="%d" width="%g" customWidth="1"/>`, i+1, i+1, width)
+		}
+		b.WriteString(`</cols>`)
+	}
+
+	b.WriteString(`<sheetData><row r="1">`)
+	for i, col := range s.Columns {
+		writeInlineString(&b, cellRef(i, 1), 1, col.Header)
+	}
+	b.WriteString(`</row>`)
+
+	for r, row := range s.Rows {
+		rowNum := r + 2
+		fmt.Fprintf(&b, `<row r="%d">`, rowNum)
+		for i, v := range row {
+			format := General
+			if i < len(s.Columns) {
+				format = s.Columns[i].Format
+			}
+			if err := writeCell(&b, cellRef(i, rowNum), format, v); err != nil {
+				return "", fmt.Errorf("row %d, column %d: %w", rowNum, i+1, err)
+			}
+		}
+		b.WriteString(`</row>`)
+	}
+	b.WriteString(`</sheetData></worksheet>`)
+	return b.String(), nil
+}
+
+// writeCell renders one value; nil leaves the cell empty
+func writeCell(b *strings.Builder, ref string, format Format, v any) error {
+	style := styleIndex[format]
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case string:
+		if format == Date && v != "" {
+			t, err := time.Parse("2006-01-02", v)
+			if err != nil {
+				return fmt.Errorf("invalid date %q", v)
+			}
+			writeNumber(b, ref, style, serial(t))
+			return nil
+		}
+		writeInlineString(b, ref, style, v)
+	case time.Time:
+		writeNumber(b, ref, style, serial(v))
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil
+		}
+		writeNumber(b, ref, style, v)
+	case int:
+		writeNumber(b, ref, style, float64(v))
+	case int64:
+		writeNumber(b, ref, style, float64(v))
+	case bool:
+		n := 0
+		if v {
+			n = 1
+		}
+		fmt.Fprintf(b, `<c r="%s" t="b"><v>%d</v></c>`, ref, n)
+	default:
+		return fmt.Errorf("unsupported value type %T", v)
+	}
+	return nil
+}
+
+func writeNumber(b *strings.Builder, ref string, style int, v float64) {
+	fmt.Fprintf(b, `<c r="%s" s="%d"><v>%s</v></c>`, ref, style, formatFloat(v))
+}
+
+func writeInlineString(b *strings.Builder, ref string, style int, v string) {
+	fmt.Fprintf(b, `<c r="%s" s="%d" t="inlineStr"><is><t xml:space="preserve">`, ref, style)
+	xml.EscapeText(b, []byte(v))
+	b.WriteString(`</t></is></c>`)
+}
+
+func formatFloat(v float64) string {
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.10f", v), "0"), ".")
+}
+
+// serial converts a date to an Excel serial day number
+func serial(t time.Time) float64 {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return day.Sub(excelEpoch).Hours() / 24
+}
+
+// cellRef returns the A1 reference of a zero-based column and one-based row
+func cellRef(col, row int) string {
+	name := ""
+	for col++; col > 0; col = (col - 1) / 26 {
+		name = string(rune('A'+(col-1)%26)) + name
+	}
+	return fmt.Sprintf("%s%d", name, row)
+}
+
+// sheetName trims a name to Excel's 31 characters and replaces the characters it forbids
+func sheetName(name string, i int) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+	if name == "" {
+		name = fmt.Sprintf("Sheet%d", i+1)
+	}
+	if len(name) > 31 {
+		name = name[:31]
+	}
+	return name
+}
+
+func contentTypes(sheets int) string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`)
+	b.WriteString(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	b.WriteString(`<Default Extension="xml" ContentType="application/xml"/>`)
+	b.WriteString(`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	b.WriteString(`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	for i := 1; i <= sheets; i++ {
+		fmt.Fprintf(&b, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i)
+	}
+	b.WriteString(`</Types>`)
+	return b.String()
+}
+
+const rootRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+func workbook(sheets []Sheet) string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	for i := range sheets {
+		b.WriteString(`<sheet name="`)
+		xml.EscapeText(&b, []byte(sheetName(sheets[i].Name, i)))
+		fmt.Fprintf(&b, `" sheetId="%d" r:id="rId%d"/>`, i+1, i+1)
+	}
+	b.WriteString(`</sheets></workbook>`)
+	return b.String()
+}
+
+func workbookRels(sheets int) string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for i := 1; i <= sheets; i++ {
+		fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i, i)
+	}
+	fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, sheets+1)
+	b.WriteString(`</Relationships>`)
+	return b.String()
+}
+
+// styles defines the cell formats indexed by styleIndex: default, bold header, integer,
+// money with red negatives, price to four places, percent and ISO date
+const styles = xml.Header + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<numFmts count="4">` +
+	`<numFmt numFmtId="164" formatCode="#,##0.00;[Red]-#,##0.00"/>` +
+	`<numFmt numFmtId="165" formatCode="#,##0.0000"/>` +
+	`<numFmt numFmtId="166" formatCode="0.00%"/>` +
+	`<numFmt numFmtId="167" formatCode="yyyy-mm-dd"/>` +
+	`</numFmts>` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="7">` +
+	`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+	`<xf numFmtId="1" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="165" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="166" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="167" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`</cellXfs>` +
+	`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>` +
+	`</styleSheet>`
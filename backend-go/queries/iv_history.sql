@@ -0,0 +1,15 @@
+-- name: UpsertIVObservation :exec
+-- Stores an observation, replacing any earlier reading for the same day
+INSERT INTO iv_history (ticker, observed_on, atm_iv, underlying_price)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (ticker, observed_on)
+DO UPDATE SET atm_iv = EXCLUDED.atm_iv,
+              underlying_price = EXCLUDED.underlying_price,
+              updated_at = NOW();
+
+-- name: ListIVHistorySince :many
+-- Observations for a ticker on or after a date, oldest first
+SELECT ticker, observed_on, atm_iv, underlying_price
+FROM iv_history
+WHERE ticker = $1 AND observed_on >= $2
+ORDER BY observed_on ASC;
@@ -0,0 +1,76 @@
+-- name: CreateOptionsSnapshot :one
+INSERT INTO options_snapshots (ticker, captured_at, spot, contracts)
+VALUES ($1, $2, $3, $4)
+RETURNING id, created_at;
+
+-- name: SetOptionsSnapshotContracts :exec
+UPDATE options_snapshots SET contracts = $2 WHERE id = $1;
+
+-- name: LatestOptionsSnapshotBefore :one
+-- The last capture of a ticker's chain taken before a time
+SELECT id, ticker, captured_at, spot, contracts, created_at
+FROM options_snapshots
+WHERE ticker = sqlc.arg(ticker) AND captured_at < sqlc.arg(before)
+ORDER BY captured_at DESC
+LIMIT 1;
+
+-- name: ListOptionsSnapshotContracts :many
+-- A capture's contracts by expiration, strike and type, of one expiration when it is given,
+-- from the intraday captures or the daily rollups, whichever holds it
+SELECT snapshot_id, captured_at, underlying, ticker, contract_type, strike_price, expiration_date,
+  bid, ask, last_price, volume, open_interest, implied_volatility, delta, gamma, theta, vega, rho
+FROM options_snapshot_contracts
+WHERE underlying = sqlc.arg(underlying) AND captured_at = sqlc.arg(captured_at) AND snapshot_id = sqlc.arg(snapshot_id)
+  AND (sqlc.narg(expiration)::date IS NULL OR expiration_date = sqlc.narg(expiration)::date)
+UNION ALL
+SELECT snapshot_id, captured_at, underlying, ticker, contract_type, strike_price, expiration_date,
+  bid, ask, last_price, volume, open_interest, implied_volatility, delta, gamma, theta, vega, rho
+FROM options_snapshot_contracts_daily
+WHERE underlying = sqlc.arg(underlying) AND captured_at = sqlc.arg(captured_at) AND snapshot_id = sqlc.arg(snapshot_id)
+  AND (sqlc.narg(expiration)::date IS NULL OR expiration_date = sqlc.narg(expiration)::date)
+ORDER BY expiration_date, strike_price, contract_type;
+
+-- name: CopyOptionsSnapshotDailyContracts :execrows
+-- Copies the contracts of each ticker's last capture of a trading day taken before a time
+-- into the daily rollups
+INSERT INTO options_snapshot_contracts_daily (snapshot_id, captured_at, underlying, ticker, contract_type,
+  strike_price, expiration_date, bid, ask, last_price, volume, open_interest, implied_volatility,
+  delta, gamma, theta, vega, rho)
+SELECT c.snapshot_id, c.captured_at, c.underlying, c.ticker, c.contract_type, c.strike_price,
+  c.expiration_date, c.bid, c.ask, c.last_price, c.volume, c.open_interest, c.implied_volatility,
+  c.delta, c.gamma, c.theta, c.vega, c.rho
+FROM options_snapshot_contracts c
+JOIN (
+  SELECT id, ticker, captured_at FROM (
+    SELECT id, ticker, captured_at, row_number() OVER (
+      PARTITION BY ticker, (captured_at AT TIME ZONE 'America/New_York')::date
+      ORDER BY captured_at DESC) AS n
+    FROM options_snapshots
+    WHERE options_snapshots.captured_at < sqlc.arg(before)
+  ) captures
+  WHERE n = 1
+) last ON c.snapshot_id = last.id AND c.underlying = last.ticker AND c.captured_at = last.captured_at
+WHERE c.captured_at < sqlc.arg(before)
+ON CONFLICT (ticker, captured_at) DO NOTHING;
+
+-- name: RollUpOptionsSnapshotsBefore :execrows
+-- Deletes captures before a time that weren't their ticker's last of the trading day
+DELETE FROM options_snapshots
+WHERE id IN (
+  SELECT id FROM (
+    SELECT id, row_number() OVER (
+      PARTITION BY ticker, (captured_at AT TIME ZONE 'America/New_York')::date
+      ORDER BY captured_at DESC) AS n
+    FROM options_snapshots
+    WHERE captured_at < sqlc.arg(before)
+  ) captures
+  WHERE n > 1
+  LIMIT sqlc.arg(max_rows)::int);
+
+-- name: DeleteOptionsSnapshotsBefore :execrows
+DELETE FROM options_snapshots
+WHERE id IN (
+  SELECT id FROM options_snapshots
+  WHERE captured_at < sqlc.arg(before)
+  ORDER BY captured_at
+  LIMIT sqlc.arg(max_rows)::int);
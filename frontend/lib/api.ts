@@ -5,6 +5,8 @@ const API_BASE_URL = process.env.NEXT_PUBLIC_API_URL || 'http://localhost:8080/a
 export const apiClient = axios.create({
   baseURL: API_BASE_URL,
   timeout: 10000,
+  // The API is on another origin (:8080 vs :3000); send its session cookies with every request
+  withCredentials: true,
 });
 
 // Types matching Go backend models